@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lang-portal/backend_go/internal/database"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+func newGroupsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "groups",
+		Short: "Manage word groups",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a word group",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.open(false)
+			if err != nil {
+				return err
+			}
+
+			groupRepo := repository.NewGroupRepository(db)
+			if _, err := groupRepo.GetByName(cmd.Context(), args[0], models.DefaultLanguage); err == nil {
+				return fmt.Errorf("group %q already exists", args[0])
+			} else if err != repository.ErrNotFound {
+				return err
+			}
+
+			group := &models.Group{Name: args[0]}
+			if err := group.Validate(); err != nil {
+				return err
+			}
+			if err := groupRepo.Create(cmd.Context(), group); err != nil {
+				return fmt.Errorf("failed to create group: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created group %q (id %d)\n", group.Name, group.ID)
+			return nil
+		},
+	})
+	return cmd
+}
+
+func newStatsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Maintain derived statistics",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "recompute",
+		Short: "Rebuild each word's review schedule, accuracy, progress and review totals from its review history",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.open(false)
+			if err != nil {
+				return err
+			}
+
+			reviewed, err := repository.NewStudyRepository(db).RecomputeReviewSchedule(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to recompute review schedule: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Recomputed review schedule for %d reviewed words\n", reviewed)
+			return nil
+		},
+	})
+	return cmd
+}
+
+func newMigrateCmd(a *app) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Create or update the database schema",
+		Long: `Create or update the database schema, then apply one-time data repairs.
+
+With --dry-run nothing is changed: the pending schema migrations and the rows
+the association repair would remove are reported instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun {
+				return runMigrateDryRun(cmd, a)
+			}
+
+			db, err := a.open(true)
+			if err != nil {
+				return err
+			}
+			if err := database.Migrate(db); err != nil {
+				return fmt.Errorf("failed to run migrations: %w", err)
+			}
+			version, _, err := database.SchemaVersion(db)
+			if err != nil {
+				return fmt.Errorf("failed to read schema version: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Migrations completed successfully (schema version %d)\n", version)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report pending migrations and repairs without applying them")
+	return cmd
+}
+
+// runMigrateDryRun reports what migrate would change on an existing database
+func runMigrateDryRun(cmd *cobra.Command, a *app) error {
+	db, err := a.open(false)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+
+	pending, err := database.PendingMigrations(db)
+	if err != nil {
+		return fmt.Errorf("failed to check migrations: %w", err)
+	}
+	fmt.Fprintf(out, "Pending schema migrations: %d\n", pending)
+
+	report, err := database.RepairAssociations(db, true)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Association repair would remove %d rows (%s)\n", report.Total(), report)
+	return nil
+}
+
+func newBackupCmd(a *app) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Write a consistent copy of the database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.open(false)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				output = fmt.Sprintf("%s.%s.bak", a.dbPath, time.Now().Format("20060102-150405"))
+			}
+			if err := database.Backup(db, output); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Backed up %s to %s\n", a.dbPath, output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "backup file (default <db>.<timestamp>.bak)")
+	return cmd
+}
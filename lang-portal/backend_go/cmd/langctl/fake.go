@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lang-portal/backend_go/internal/database"
+	"lang-portal/backend_go/internal/repository"
+)
+
+func newFakeCmd(a *app) *cobra.Command {
+	opts := database.DefaultFakeOptions()
+
+	cmd := &cobra.Command{
+		Use:   "fake",
+		Short: "Fill the database with synthetic data for load testing",
+		Long: `Fill the database with synthetic words, groups, study sessions and reviews,
+so that pagination, analytics and review scheduling can be benchmarked at
+realistic sizes. The defaults add about two years of study; the same --seed
+generates the same data.
+
+The database is created and migrated if needed. Afterwards the words are
+linked, their review schedule is recomputed and the daily summaries of the
+span are rebuilt, as if the reviews had been recorded through the API. Use a
+scratch database: the data is mixed into whatever the database holds.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.open(true)
+			if err != nil {
+				return err
+			}
+			if err := database.Migrate(db); err != nil {
+				return fmt.Errorf("failed to run migrations: %w", err)
+			}
+			out := cmd.OutOrStdout()
+			began := time.Now()
+
+			opts.Now = began
+			result, err := database.Fake(db, opts)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Added %d words, %d groups, %d group memberships, %d sessions and %d reviews\n",
+				result.Words, result.Groups, result.Memberships, result.Sessions, result.Reviews)
+
+			ctx := cmd.Context()
+			if _, err := repository.NewKanjiRepository(db).LinkUnlinkedWords(ctx); err != nil {
+				return fmt.Errorf("failed to link words to kanji: %w", err)
+			}
+			words := repository.NewWordRepository(db)
+			if _, err := words.LinkUnlinkedParts(ctx); err != nil {
+				return fmt.Errorf("failed to link words to parts of speech: %w", err)
+			}
+			if err := words.RankUnrankedWords(ctx); err != nil {
+				return fmt.Errorf("failed to rank words by frequency: %w", err)
+			}
+			reviewed, err := repository.NewStudyRepository(db).RecomputeReviewSchedule(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to recompute review schedule: %w", err)
+			}
+			days, err := repository.NewDailySummaryRepository(db).Compute(ctx, began.Add(-opts.Span), began.Add(24*time.Hour))
+			if err != nil {
+				return fmt.Errorf("failed to compute daily summaries: %w", err)
+			}
+
+			fmt.Fprintf(out, "Recomputed review schedule for %d reviewed words and %d daily summaries in %s\n",
+				reviewed, days, time.Since(began).Round(time.Millisecond))
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&opts.Words, "words", opts.Words, "number of words")
+	cmd.Flags().IntVar(&opts.Groups, "groups", opts.Groups, "number of groups")
+	cmd.Flags().IntVar(&opts.Sessions, "sessions", opts.Sessions, "number of study sessions")
+	cmd.Flags().IntVar(&opts.Reviews, "reviews", opts.Reviews, "number of reviews, shared out over the sessions")
+	cmd.Flags().IntVar(&opts.Learners, "learners", opts.Learners, "number of learners the sessions belong to")
+	cmd.Flags().DurationVar(&opts.Span, "span", opts.Span, "how far back the sessions reach")
+	cmd.Flags().Int64Var(&opts.Seed, "seed", opts.Seed, "random seed")
+	return cmd
+}
@@ -0,0 +1,97 @@
+// Command langctl manages the language portal database from the command line:
+// importing and exporting words, creating groups, recomputing review statistics,
+// running migrations, taking backups and generating load test data.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/user"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"lang-portal/backend_go/internal/database"
+)
+
+// app holds state shared by all subcommands
+type app struct {
+	dbPath  string
+	verbose bool
+	db      *gorm.DB
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	a := &app{}
+
+	// An invalid database URL only matters when it is not overridden by --db
+	defaultPath, pathErr := database.PathFromEnv()
+	root := &cobra.Command{
+		Use:          "langctl",
+		Short:        "Manage the language portal database",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if pathErr != nil && !cmd.Flags().Changed("db") {
+				return pathErr
+			}
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&a.dbPath, "db", defaultPath, "path to the SQLite database (env "+database.EnvPath+", "+database.EnvURL+" or "+database.EnvDataDir+")")
+	root.PersistentFlags().BoolVarP(&a.verbose, "verbose", "v", false, "log SQL statements")
+
+	root.AddCommand(
+		newWordsCmd(a),
+		newGroupsCmd(a),
+		newStatsCmd(a),
+		newMigrateCmd(a),
+		newBackupCmd(a),
+		newFakeCmd(a),
+	)
+	return root
+}
+
+// open connects to the database, creating the file if needed only when create is set
+func (a *app) open(create bool) (*gorm.DB, error) {
+	if a.db != nil {
+		return a.db, nil
+	}
+	if !create {
+		if _, err := os.Stat(a.dbPath); os.IsNotExist(err) {
+			return nil, fmt.Errorf("database does not exist at %s, run 'langctl migrate' first", a.dbPath)
+		}
+	}
+
+	level := gormlogger.Warn
+	if a.verbose {
+		level = gormlogger.Info
+	}
+	opts, err := database.OptionsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
+	db, err := database.Open(a.dbPath, opts, &gorm.Config{
+		Logger: database.NewQueryLogger(log.New(os.Stderr, "", log.LstdFlags), level, opts.SlowQueryThreshold),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	a.db = db
+	return db, nil
+}
+
+// actor identifies the operator in the audit log
+func (a *app) actor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return "langctl:" + u.Username
+	}
+	return "langctl"
+}
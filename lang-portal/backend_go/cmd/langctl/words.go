@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lang-portal/backend_go/internal/importer"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// wordRecord is the export format, matching the JSON seed files
+type wordRecord struct {
+	Japanese string   `json:"japanese"`
+	Romaji   string   `json:"romaji"`
+	English  string   `json:"english"`
+	Parts    []string `json:"parts"`
+}
+
+// exportPageSize is the number of words read per query during export
+const exportPageSize = 500
+
+func newWordsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "words",
+		Short: "Import and export words",
+	}
+	cmd.AddCommand(newWordsImportCmd(a), newWordsExportCmd(a), newWordsFrequencyCmd(a))
+	return cmd
+}
+
+func newWordsImportCmd(a *app) *cobra.Command {
+	var groupName, format, columns string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import words from a JSON or CSV file (use - for stdin)",
+		Long: "Import words from a JSON array in the seed file format, a Memrise course CSV or\n" +
+			"a generic list of two or three columns. The format and the meaning of each\n" +
+			"column are detected; use --format and --columns to override them, and\n" +
+			"--dry-run to check the detection without importing. Words whose japanese\n" +
+			"already exists are skipped, as are invalid rows, which are reported. With\n" +
+			"--group, all imported and skipped words are added to the group, which is\n" +
+			"created if missing.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.open(false)
+			if err != nil {
+				return err
+			}
+
+			data, err := readImportFile(args[0])
+			if err != nil {
+				return err
+			}
+			opts := importer.Options{Format: format}
+			if columns != "" {
+				opts.Columns = strings.Split(strings.ToLower(columns), ",")
+			}
+			parsed, err := importer.Parse(data, opts)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+
+			errOut := cmd.ErrOrStderr()
+			fmt.Fprintf(errOut, "Format %s", parsed.Format)
+			if len(parsed.Columns) > 0 {
+				fmt.Fprintf(errOut, ", columns %s", strings.Join(parsed.Columns, ","))
+			}
+			fmt.Fprintln(errOut)
+			var records []importer.Record
+			for _, row := range parsed.Rows {
+				if row.Error != "" {
+					fmt.Fprintf(errOut, "line %d: %s, skipped\n", row.Line, row.Error)
+					continue
+				}
+				records = append(records, row.Record)
+			}
+			invalid := len(parsed.Rows) - len(records)
+
+			if dryRun {
+				out := cmd.OutOrStdout()
+				for _, rec := range records {
+					fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", rec.Japanese, rec.Romaji, rec.English, strings.Join(rec.Parts, ","))
+				}
+				fmt.Fprintf(errOut, "Dry run: %d valid rows, %d invalid\n", len(records), invalid)
+				return nil
+			}
+
+			wordRepo := repository.NewWordRepository(db)
+			groupRepo := repository.NewGroupRepository(db)
+			auditRepo := repository.NewAuditRepository(db)
+
+			var group *models.Group
+			if groupName != "" {
+				group, err = groupRepo.GetByName(cmd.Context(), groupName, models.DefaultLanguage)
+				if err == repository.ErrNotFound {
+					group = &models.Group{Name: groupName}
+					err = groupRepo.Create(cmd.Context(), group)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to prepare group %q: %w", groupName, err)
+				}
+			}
+
+			var created, skipped int
+			for i, rec := range records {
+				word, err := wordRepo.GetByTerm(cmd.Context(), rec.Japanese)
+				switch {
+				case err == nil:
+					skipped++
+				case err == repository.ErrNotFound:
+					word = rec.Word()
+					if err := word.Validate(); err != nil {
+						return fmt.Errorf("record %d (%s): %w", i+1, rec.Japanese, err)
+					}
+					if err := wordRepo.Create(cmd.Context(), word); err != nil {
+						return fmt.Errorf("record %d (%s): %w", i+1, rec.Japanese, err)
+					}
+					created++
+				default:
+					return fmt.Errorf("record %d (%s): %w", i+1, rec.Japanese, err)
+				}
+
+				if group != nil {
+					if err := groupRepo.AddWord(cmd.Context(), group.ID, word.ID); err != nil {
+						return fmt.Errorf("failed to add %s to group %q: %w", rec.Japanese, groupName, err)
+					}
+				}
+			}
+
+			entry, err := models.NewAuditEntry(a.actor(), models.AuditActionImport, models.AuditEntityWord, nil, nil, map[string]interface{}{
+				"source":  args[0],
+				"format":  parsed.Format,
+				"group":   groupName,
+				"created": created,
+				"skipped": skipped,
+				"invalid": invalid,
+			})
+			if err == nil {
+				err = auditRepo.Create(cmd.Context(), entry)
+			}
+			if err != nil {
+				return fmt.Errorf("words were imported but the audit entry could not be recorded: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d words, skipped %d existing and %d invalid\n", created, skipped, invalid)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&groupName, "group", "", "add the words to this group")
+	cmd.Flags().StringVar(&format, "format", importer.FormatAuto, "file format: auto, json, csv or memrise")
+	cmd.Flags().StringVar(&columns, "columns", "", "comma-separated role of each column: japanese, romaji, reading, english, parts or skip")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the words that would be imported without importing them")
+	return cmd
+}
+
+func newWordsExportCmd(a *app) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all words as JSON in the seed file format",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.open(false)
+			if err != nil {
+				return err
+			}
+
+			wordRepo := repository.NewWordRepository(db)
+			var records []wordRecord
+			for page := 1; ; page++ {
+				result, err := wordRepo.List(cmd.Context(), repository.PaginationParams{Page: page, PageSize: exportPageSize}, repository.WordListOptions{})
+				if err != nil {
+					return fmt.Errorf("failed to list words: %w", err)
+				}
+				for _, w := range result.Items {
+					records = append(records, wordRecord{
+						Japanese: w.Term,
+						Romaji:   w.Reading,
+						English:  w.English,
+						Parts:    w.Parts,
+					})
+				}
+				if page >= result.TotalPages {
+					break
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" && output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(records); err != nil {
+				return fmt.Errorf("failed to write words: %w", err)
+			}
+
+			if output != "" && output != "-" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d words to %s\n", len(records), output)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write to this file instead of stdout")
+	return cmd
+}
+
+func newWordsFrequencyCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "frequency FILE",
+		Short: "Import a word frequency list and rank the words against it (use - for stdin)",
+		Long: "Import a word frequency list, such as one derived from BCCWJ, replacing the\n" +
+			"current one. Each line holds a rank, the word and optionally its reading,\n" +
+			"separated by tabs or commas; lines without a rank are ranked by position.\n" +
+			"Words are matched by their japanese, or by reading for kana-only words, and\n" +
+			"can then be ordered by frequency.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := a.open(false)
+			if err != nil {
+				return err
+			}
+
+			data, err := readImportFile(args[0])
+			if err != nil {
+				return err
+			}
+			entries, err := importer.ParseFrequencyList(data)
+			if err != nil {
+				return err
+			}
+
+			matched, err := repository.NewWordRepository(db).ReplaceWordFrequencies(cmd.Context(), entries)
+			if err != nil {
+				return fmt.Errorf("failed to import the frequency list: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d frequency entries; %d words ranked\n", len(entries), matched)
+			return nil
+		},
+	}
+}
+
+func readImportFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -1,92 +1,460 @@
+// Command server runs the language portal API. Its commands are serve, the
+// default, which migrates and seeds the database and starts the API; migrate,
+// which only applies pending migrations; and seed, which fills an empty
+// database with the starter vocabulary. All of them use the database at -db,
+// which defaults to the one configured by the environment, see
+// database.PathFromEnv.
 package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/gin-gonic/gin"
-	"gorm.io/driver/sqlite"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 
 	"lang-portal/backend_go/internal/api"
 	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/app"
+	"lang-portal/backend_go/internal/cache"
 	"lang-portal/backend_go/internal/database"
+	"lang-portal/backend_go/internal/dictionary"
+	"lang-portal/backend_go/internal/embeddings"
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/mail"
 	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/ocr"
 	"lang-portal/backend_go/internal/repository"
 	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/speech"
+	"lang-portal/backend_go/internal/telemetry"
+	"lang-portal/backend_go/internal/web"
 )
 
 const (
 	defaultPort = "8081"
-	dbPath      = "words.db"
+
+	// frontendEnv set to true serves the frontend embedded in the binary under
+	// /, as the -frontend flag of serve does
+	frontendEnv = "LANG_PORTAL_FRONTEND"
+
+	// mediaDirEnv is a directory of media, such as a volume mounted into a
+	// container: word recordings in its audio directory and KanjiVG stroke
+	// data in its kanjivg directory are used unless audioDirEnv or
+	// kanjiVGDirEnv point elsewhere
+	mediaDirEnv = "MEDIA_DIR"
+
+	// seedProfileEnv names the seed profile filling a new database, and the
+	// default of -profile; without it the demo profile is used
+	seedProfileEnv = "LANG_PORTAL_SEED_PROFILE"
+
+	// certificateKeyEnv names the key signing group certificates; without it a
+	// key is generated and kept in the database
+	certificateKeyEnv = "LANG_PORTAL_CERTIFICATE_KEY"
+
+	// launchKeyEnv names the key signing activity launch tokens; without it a key
+	// is generated and kept in the database
+	launchKeyEnv = "LANG_PORTAL_LAUNCH_KEY"
+
+	// adminKeyEnv names the bootstrap API key granting the admin scope, which
+	// creates the other keys; without it keys cannot be managed
+	adminKeyEnv = "LANG_PORTAL_ADMIN_KEY"
+
+	// kanjiVGDirEnv points at the kanji directory of a KanjiVG release for stroke
+	// order data; without it the files are fetched from kanjiVGURLEnv, which
+	// defaults to the KanjiVG repository
+	kanjiVGDirEnv = "LANG_PORTAL_KANJIVG_DIR"
+	kanjiVGURLEnv = "LANG_PORTAL_KANJIVG_URL"
+
+	// jmdictFileEnv points at an offline JMdict XML file for dictionary lookups;
+	// without it words are looked up with the Jisho API
+	jmdictFileEnv = "LANG_PORTAL_JMDICT_FILE"
+
+	// visionAPIKeyEnv is a Google Cloud Vision API key for reading text in
+	// images; without it images are read with the local Tesseract program at
+	// tesseractPathEnv (default "tesseract"), which needs the Japanese trained
+	// data
+	visionAPIKeyEnv  = "LANG_PORTAL_VISION_API_KEY"
+	tesseractPathEnv = "LANG_PORTAL_TESSERACT_PATH"
+
+	// audioDirEnv points at a directory of word recordings, one MP3 per word
+	// named after its Japanese text; without it words have no audio
+	audioDirEnv = "LANG_PORTAL_AUDIO_DIR"
+
+	// speechCommandEnv names a local speech-to-text program for pronunciation
+	// checks, with its arguments; the recording's path is appended. Without it
+	// recordings are sent to a Whisper API at whisperURLEnv (the OpenAI API by
+	// default) when whisperURLEnv or whisperAPIKeyEnv is set, and pronunciation
+	// checks are unavailable otherwise.
+	speechCommandEnv = "LANG_PORTAL_SPEECH_COMMAND"
+	whisperURLEnv    = "LANG_PORTAL_WHISPER_URL"
+	whisperAPIKeyEnv = "LANG_PORTAL_WHISPER_API_KEY"
+	whisperModelEnv  = "LANG_PORTAL_WHISPER_MODEL"
+
+	// embeddingsURLEnv is an OpenAI-compatible embeddings endpoint for related
+	// words, the OpenAI API by default; embeddings are used when it or
+	// embeddingsAPIKeyEnv is set
+	embeddingsURLEnv    = "LANG_PORTAL_EMBEDDINGS_URL"
+	embeddingsAPIKeyEnv = "LANG_PORTAL_EMBEDDINGS_API_KEY"
+	embeddingsModelEnv  = "LANG_PORTAL_EMBEDDINGS_MODEL"
+
+	// llmProvidersEnv lists the language model providers for the AI features
+	// (the tutor, sentence generation and word explanations) in the order they
+	// are tried: openai, ollama and bedrock, comma separated. It defaults to
+	// openai when llmURLEnv or llmAPIKeyEnv is set, and the AI features are not
+	// available without a provider.
+	llmProvidersEnv = "LANG_PORTAL_LLM_PROVIDERS"
+
+	// llmURLEnv is the OpenAI-compatible chat completions endpoint of the
+	// openai provider, the OpenAI API by default
+	llmURLEnv    = "LANG_PORTAL_LLM_URL"
+	llmAPIKeyEnv = "LANG_PORTAL_LLM_API_KEY"
+	llmModelEnv  = "LANG_PORTAL_LLM_MODEL"
+
+	// ollamaURLEnv is the chat endpoint of the ollama provider, a local Ollama
+	// server by default
+	ollamaURLEnv   = "LANG_PORTAL_OLLAMA_URL"
+	ollamaModelEnv = "LANG_PORTAL_OLLAMA_MODEL"
+
+	// bedrockURLEnv is the Bedrock runtime endpoint of the bedrock provider,
+	// which is required; requests carry bedrockAPIKeyEnv as a bearer token
+	bedrockURLEnv    = "LANG_PORTAL_BEDROCK_URL"
+	bedrockAPIKeyEnv = "LANG_PORTAL_BEDROCK_API_KEY"
+	bedrockModelEnv  = "LANG_PORTAL_BEDROCK_MODEL"
+
+	// llmTimeoutEnv bounds each attempt at a provider, as a Go duration, and
+	// llmRetriesEnv is the number of retries before failing over to the next
+	llmTimeoutEnv = "LANG_PORTAL_LLM_TIMEOUT"
+	llmRetriesEnv = "LANG_PORTAL_LLM_RETRIES"
+
+	// aiDailyRequestsEnv and aiDailyTokensEnv cap the requests and tokens each
+	// user may use of the AI features per UTC day; 0 lifts a cap
+	aiDailyRequestsEnv     = "LANG_PORTAL_AI_DAILY_REQUESTS"
+	aiDailyTokensEnv       = "LANG_PORTAL_AI_DAILY_TOKENS"
+	defaultAIDailyRequests = 200
+	defaultAIDailyTokens   = 200000
+
+	// xpReviewEnv, xpSessionEnv and xpGoalEnv set the experience awarded for a
+	// correct review, a completed session and a met group goal; xpLevelsEnv
+	// lists the total experience levels 2, 3 and so on need, comma separated
+	xpReviewEnv  = "LANG_PORTAL_XP_REVIEW"
+	xpSessionEnv = "LANG_PORTAL_XP_SESSION"
+	xpGoalEnv    = "LANG_PORTAL_XP_GOAL"
+	xpLevelsEnv  = "LANG_PORTAL_XP_LEVELS"
+
+	// Reminder emails are sent with the SendGrid-style API at sendGridURLEnv
+	// (default SendGrid's) when sendGridAPIKeyEnv is set, otherwise through the
+	// SMTP server at smtpAddrEnv (host:port), signing in with smtpUsernameEnv
+	// and smtpPasswordEnv when set; without either no emails are sent. They
+	// need mailFromEnv, the sender address, and publicURLEnv, the address the
+	// server is reached at, for unsubscribe links.
+	sendGridAPIKeyEnv = "LANG_PORTAL_SENDGRID_API_KEY"
+	sendGridURLEnv    = "LANG_PORTAL_SENDGRID_URL"
+	smtpAddrEnv       = "LANG_PORTAL_SMTP_ADDR"
+	smtpUsernameEnv   = "LANG_PORTAL_SMTP_USERNAME"
+	smtpPasswordEnv   = "LANG_PORTAL_SMTP_PASSWORD"
+	mailFromEnv       = "LANG_PORTAL_MAIL_FROM"
+	publicURLEnv      = "LANG_PORTAL_PUBLIC_URL"
+
+	// reviewRetentionEnv is the number of months, the current one included,
+	// whose reviews stay in word_review_items; older ones are moved to the
+	// review archive once a day. Unset or 0 keeps every review live.
+	reviewRetentionEnv = "LANG_PORTAL_REVIEW_RETENTION_MONTHS"
+
+	// sessionExpiryEnv is how long a study session may go without reviews, as
+	// a Go duration such as "12h", before the expiry job closes it. 0 keeps
+	// sessions open until they are completed.
+	sessionExpiryEnv     = "LANG_PORTAL_SESSION_EXPIRY"
+	defaultSessionExpiry = 24 * time.Hour
+
+	// redisURLEnv is a Redis URL such as redis://host:6379/0 holding the state
+	// replicas share: the cache of the statistics and the change events of live
+	// dashboards; without it each process keeps its own, in memory
+	redisURLEnv = "LANG_PORTAL_REDIS_URL"
+
+	// rateLimitRedisURLEnv is a Redis URL such as redis://host:6379/0 keeping
+	// the rate limits, so that they hold across replicas and restarts; without
+	// it each process limits on its own, in memory
+	rateLimitRedisURLEnv = "LANG_PORTAL_RATE_LIMIT_REDIS_URL"
+
+	// tlsCertFileEnv and tlsKeyFileEnv serve HTTPS with the certificate and
+	// key in these PEM files. autocertDomainsEnv instead serves HTTPS with
+	// certificates obtained from Let's Encrypt for these host names, comma
+	// separated, which must reach the server on port 443 or, for the HTTP
+	// challenge, port 80; they are kept in autocertCacheDirEnv, by default the
+	// autocert directory next to the database. autocertEmailEnv is the
+	// contact address given to Let's Encrypt. With either, PORT is the HTTPS
+	// port and httpRedirectPortEnv, such as 80, the port of a plain HTTP
+	// server redirecting to HTTPS.
+	tlsCertFileEnv      = "LANG_PORTAL_TLS_CERT_FILE"
+	tlsKeyFileEnv       = "LANG_PORTAL_TLS_KEY_FILE"
+	autocertDomainsEnv  = "LANG_PORTAL_AUTOCERT_DOMAINS"
+	autocertCacheDirEnv = "LANG_PORTAL_AUTOCERT_CACHE_DIR"
+	autocertEmailEnv    = "LANG_PORTAL_AUTOCERT_EMAIL"
+	httpRedirectPortEnv = "LANG_PORTAL_HTTP_REDIRECT_PORT"
+
+	// maxBodyBytesEnv caps the size of JSON request bodies, in bytes, 1 MB by
+	// default. The uploads have limits of their own: word lists
+	// (maxImportBytesEnv, 5 MB), recordings for pronunciation checks
+	// (maxRecordingBytesEnv, 10 MB), images for vocabulary capture
+	// (maxImageBytesEnv, 10 MB) and account archives (maxArchiveBytesEnv,
+	// 100 MB).
+	maxBodyBytesEnv      = "LANG_PORTAL_MAX_BODY_BYTES"
+	maxImportBytesEnv    = "LANG_PORTAL_MAX_IMPORT_BYTES"
+	maxRecordingBytesEnv = "LANG_PORTAL_MAX_RECORDING_BYTES"
+	maxImageBytesEnv     = "LANG_PORTAL_MAX_IMAGE_BYTES"
+	maxArchiveBytesEnv   = "LANG_PORTAL_MAX_ARCHIVE_BYTES"
+
+	// shutdownGraceEnv bounds how long a shutdown waits for requests and
+	// background work to finish, as a Go duration such as "30s"
+	shutdownGraceEnv     = "LANG_PORTAL_SHUTDOWN_GRACE"
+	defaultShutdownGrace = 10 * time.Second
+)
+
+// Commands of the server binary
+const (
+	commandServe   = "serve"
+	commandMigrate = "migrate"
+	commandSeed    = "seed"
 )
 
 func main() {
 	// Initialize logger
 	logger := log.New(os.Stdout, "", log.LstdFlags)
 
+	cmd, err := parseCommand(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	switch cmd.name {
+	case commandMigrate:
+		if err := migrateDatabase(logger, cmd.dbPath); err != nil {
+			logger.Fatalf("Failed to migrate database: %v", err)
+		}
+	case commandSeed:
+		if err := seedDatabase(logger, cmd.dbPath, cmd.profile); err != nil {
+			logger.Fatalf("Failed to seed database: %v", err)
+		}
+	default:
+		serve(logger, cmd)
+	}
+}
+
+// command is a command of the server binary with its flags
+type command struct {
+	name   string
+	dbPath string
+	// profile is the seed profile of serve and seed
+	profile string
+	// frontend serves the embedded frontend next to the API
+	frontend bool
+}
+
+// parseCommand reads the command and its flags from args. Without a command
+// the server is started, so that existing deployments keep working.
+func parseCommand(args []string) (command, error) {
+	cmd := command{name: commandServe}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd.name, args = args[0], args[1:]
+	}
+	switch cmd.name {
+	case commandServe, commandMigrate, commandSeed:
+	default:
+		return command{}, fmt.Errorf("unknown command %q: use %s, %s or %s", cmd.name, commandServe, commandMigrate, commandSeed)
+	}
+
+	defaultPath, err := database.PathFromEnv()
+	if err != nil {
+		return command{}, err
+	}
+	defaultProfile := os.Getenv(seedProfileEnv)
+	if defaultProfile == "" {
+		defaultProfile = database.DefaultSeedProfile
+	}
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.StringVar(&cmd.dbPath, "db", defaultPath, "path to the SQLite database (env "+database.EnvPath+", "+database.EnvURL+" or "+database.EnvDataDir+")")
+	if cmd.name != commandMigrate {
+		flags.StringVar(&cmd.profile, "profile", defaultProfile, "seed profile: minimal, demo or jlpt-n5 (env "+seedProfileEnv+")")
+	}
+	if cmd.name == commandServe {
+		defaultFrontend, _ := strconv.ParseBool(os.Getenv(frontendEnv))
+		flags.BoolVar(&cmd.frontend, "frontend", defaultFrontend, "serve the embedded frontend under / (env "+frontendEnv+")")
+	}
+	if err := flags.Parse(args); err != nil {
+		return command{}, err
+	}
+	if flags.NArg() > 0 {
+		return command{}, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	if cmd.profile != "" {
+		profiles, err := database.SeedProfiles()
+		if err != nil {
+			return command{}, err
+		}
+		if !slices.Contains(profiles, cmd.profile) {
+			return command{}, fmt.Errorf("unknown seed profile %q: use %s", cmd.profile, strings.Join(profiles, ", "))
+		}
+	}
+	if cmd.frontend && web.Assets() == nil {
+		return command{}, fmt.Errorf("this binary was built without the frontend: run mage frontend:build before building it")
+	}
+	return cmd, nil
+}
+
+// serve runs the API server and the background jobs until interrupted
+func serve(logger *log.Logger, cmd command) {
+	// Initialize tracing and metrics; they are exported only when an OTLP
+	// endpoint is configured
+	shutdownTracing, err := telemetry.Setup(context.Background())
+	if err != nil {
+		logger.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	if telemetry.Enabled() {
+		logger.Println("Exporting traces over OTLP")
+	}
+	if telemetry.MetricsEnabled() {
+		logger.Println("Exporting metrics over OTLP")
+	}
+
+	grace, err := shutdownGrace()
+	if err != nil {
+		logger.Fatalf("Invalid shutdown configuration: %v", err)
+	}
+
+	model, err := languageModel()
+	if err != nil {
+		logger.Fatalf("Invalid language model configuration: %v", err)
+	}
+	quota, err := aiQuota()
+	if err != nil {
+		logger.Fatalf("Invalid AI quota configuration: %v", err)
+	}
+	retentionMonths, err := reviewRetention()
+	if err != nil {
+		logger.Fatalf("Invalid review retention configuration: %v", err)
+	}
+	sessionIdle, err := sessionExpiry()
+	if err != nil {
+		logger.Fatalf("Invalid session expiry configuration: %v", err)
+	}
+	rules, err := xpRules()
+	if err != nil {
+		logger.Fatalf("Invalid XP configuration: %v", err)
+	}
+	shared, err := sharedStateClient()
+	if err != nil {
+		logger.Fatalf("Invalid Redis configuration: %v", err)
+	}
+	rateLimits, err := rateLimitStore()
+	if err != nil {
+		logger.Fatalf("Invalid rate limit configuration: %v", err)
+	}
+	limits, err := bodyLimits()
+	if err != nil {
+		logger.Fatalf("Invalid body limit configuration: %v", err)
+	}
+	tlsConfig, err := tlsSettings(cmd.dbPath)
+	if err != nil {
+		logger.Fatalf("Invalid TLS configuration: %v", err)
+	}
+	sender := mailSender()
+	if sender != nil && (os.Getenv(mailFromEnv) == "" || os.Getenv(publicURLEnv) == "") {
+		logger.Fatalf("Invalid mail configuration: %s and %s must be set to send reminder emails", mailFromEnv, publicURLEnv)
+	}
+
 	// Initialize database
-	db, err := initDatabase(logger)
+	db, err := initDatabase(logger, cmd.dbPath, cmd.profile)
 	if err != nil {
 		logger.Fatalf("Failed to initialize database: %v", err)
 	}
 
-	// Initialize repositories
-	wordRepo := repository.NewWordRepository(db)
-	groupRepo := repository.NewGroupRepository(db)
-	studyRepo := repository.NewStudyRepository(db)
-
-	// Initialize services
-	baseService := service.NewBaseService(wordRepo, groupRepo, studyRepo)
-	dashboardService := service.NewDashboardService(baseService)
-	wordService := service.NewWordService(baseService)
-	groupService := service.NewGroupService(baseService)
-	studyService := service.NewStudyService(baseService)
-
-	// Initialize router with middleware
-	router := gin.New() // Use gin.New() instead of gin.Default() to have more control over middleware
-
-	// Add security and stability middleware
-	router.Use(middleware.Recovery())                // Handle panics
-	router.Use(middleware.SecurityHeaders())         // Add security headers
-	router.Use(middleware.CORS())                    // Handle CORS
-	router.Use(middleware.RequestLogger())           // Log requests
-	router.Use(middleware.RateLimit(100, 200))       // Rate limit: 100 requests per second, burst of 200
-	router.Use(middleware.Timeout(30 * time.Second)) // Request timeout
-	router.Use(gin.Logger())                         // Gin's built-in logger
-
-	// Register API routes
-	api.RegisterRoutes(router, &api.Services{
-		Dashboard: dashboardService,
-		Word:      wordService,
-		Group:     groupService,
-		Study:     studyService,
-	})
-
-	// Create HTTP server with timeouts
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = defaultPort
 	}
-	srv := &http.Server{
-		Addr:         ":" + port,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	opts := []app.Option{
+		app.WithLogger(logger),
+		app.WithAddr(":" + port),
+		app.WithDBPath(cmd.dbPath),
+		app.WithServiceName(serviceName()),
+		app.WithAIQuota(quota),
+		app.WithXPRules(rules),
+		app.WithEmbeddings(embeddingProvider()),
+		app.WithSpeechRecognizer(speechRecognizer()),
+		app.WithDictionary(dictionaryProvider()),
+		app.WithTextReader(textReader()),
+		app.WithStrokeSource(strokeSource()),
+		app.WithAudioSource(audioSource()),
+		app.WithLaunchKey([]byte(os.Getenv(launchKeyEnv))),
+		app.WithBootstrapAdminKey(os.Getenv(adminKeyEnv)),
+		app.WithCertificateKey([]byte(os.Getenv(certificateKeyEnv))),
+		app.WithReviewRetention(retentionMonths),
+		app.WithSessionExpiry(sessionIdle),
+		app.WithBodyLimits(limits),
+	}
+	if model != nil {
+		opts = append(opts, app.WithLanguageModel(model))
+	}
+	if sender != nil {
+		opts = append(opts, app.WithMail(sender, os.Getenv(mailFromEnv), os.Getenv(publicURLEnv)))
+	}
+	if shared != nil {
+		hub, err := events.NewRedisHub(context.Background(), shared, events.DefaultBufferSize, logger)
+		if err != nil {
+			logger.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		logger.Println("Sharing the cache and events through Redis")
+		opts = append(opts, app.WithCache(cache.NewRedis(shared)), app.WithEvents(hub))
+	}
+	if cmd.frontend {
+		logger.Println("Serving the frontend")
+		opts = append(opts, app.WithFrontend(web.Assets()))
+	}
+	if tlsConfig != nil {
+		if len(tlsConfig.Domains) > 0 {
+			logger.Printf("Serving HTTPS with certificates from Let's Encrypt for %s", strings.Join(tlsConfig.Domains, ", "))
+		} else {
+			logger.Println("Serving HTTPS")
+		}
+		opts = append(opts, app.WithTLS(*tlsConfig))
 	}
+	if rateLimits != nil {
+		logger.Println("Keeping rate limits in Redis")
+		opts = append(opts, app.WithRateLimitStore(rateLimits))
+	}
+	portal, err := app.New(db, opts...)
+	if err != nil {
+		logger.Fatalf("Failed to assemble the application: %v", err)
+	}
+	portal.Start()
 
 	// Start server in a goroutine
 	go func() {
 		logger.Printf("Server starting on port %s", port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if portal.Redirect != nil {
+			logger.Printf("Redirecting HTTP on %s to HTTPS", portal.Redirect.Addr)
+		}
+		if err := portal.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Fatalf("Failed to start server: %v", err)
 		}
 	}()
@@ -95,47 +463,511 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	logger.Println("Shutting down server...")
+	logger.Printf("Shutting down server, waiting up to %s...", grace)
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Every step below shares the grace period
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
 	defer cancel()
 
-	// Attempt graceful shutdown
-	if err := srv.Shutdown(ctx); err != nil {
-		logger.Fatalf("Server forced to shutdown: %v", err)
+	// Stop the server, then the background jobs
+	portal.Shutdown(ctx)
+
+	// Close the connections, checkpointing the write-ahead log
+	closeDatabase(logger, db)
+	if shared != nil {
+		if err := shared.Close(); err != nil {
+			logger.Printf("Failed to close the Redis connections: %v", err)
+		}
+	}
+	if rateLimits != nil {
+		if err := rateLimits.Close(); err != nil {
+			logger.Printf("Failed to close the rate limit store: %v", err)
+		}
+	}
+
+	// Flush spans and metrics still buffered by the exporters
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Printf("Failed to flush telemetry: %v", err)
 	}
 
 	logger.Println("Server exiting")
 }
 
-func initDatabase(logger *log.Logger) (*gorm.DB, error) {
-	// Configure GORM logger
+// initDatabase opens the database, brings its schema up to date and seeds it
+// with profile when it has no words yet
+func initDatabase(logger *log.Logger, dbPath, profile string) (*gorm.DB, error) {
+	db, err := openDatabase(logger, dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run migrations
+	if err := database.Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	// Run seeds if database is empty
+	empty, err := hasNoWords(db)
+	if err != nil {
+		return nil, err
+	}
+	if empty {
+		if _, err := database.Seed(db, profile); err != nil {
+			return nil, fmt.Errorf("failed to run seeds: %w", err)
+		}
+		logger.Printf("Seeded the new database with the %s profile", profile)
+	}
+
+	if err := linkWords(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// openDatabase opens the database at dbPath without changing it
+func openDatabase(logger *log.Logger, dbPath string) (*gorm.DB, error) {
+	// Connection pragmas, pool limits and the slow query threshold, overridable
+	// via LANG_PORTAL_DB_* variables
+	opts, err := database.OptionsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
+
+	// Log queries with the request that ran them, flagging slow ones
 	gormConfig := &gorm.Config{
-		Logger: gormlogger.Default.LogMode(gormlogger.Info),
+		Logger: database.NewQueryLogger(logger, gormlogger.Info, opts.SlowQueryThreshold),
 	}
 
 	// Open database connection
-	db, err := gorm.Open(sqlite.Open(dbPath), gormConfig)
+	db, err := database.Open(dbPath, opts, gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Run migrations
-	if err := database.Migrate(db); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	// Record a span per query, parented to the calling request
+	if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics())); err != nil {
+		return nil, fmt.Errorf("failed to enable query tracing: %w", err)
 	}
+	return db, nil
+}
 
-	// Run seeds if database is empty
+// hasNoWords reports whether the database has no words yet
+func hasNoWords(db *gorm.DB) (bool, error) {
 	var count int64
 	if err := db.Model(&models.Word{}).Count(&count).Error; err != nil {
-		return nil, fmt.Errorf("failed to check word count: %w", err)
+		return false, fmt.Errorf("failed to check word count: %w", err)
 	}
-	if count == 0 {
-		if err := database.Seed(db); err != nil {
-			return nil, fmt.Errorf("failed to run seeds: %w", err)
+	return count == 0, nil
+}
+
+// linkWords derives the kanji links, part links and frequency ranks of words
+// written outside the word repository, such as seed data
+func linkWords(db *gorm.DB) error {
+	if _, err := repository.NewKanjiRepository(db).LinkUnlinkedWords(context.Background()); err != nil {
+		return fmt.Errorf("failed to link words to kanji: %w", err)
+	}
+	if _, err := repository.NewWordRepository(db).LinkUnlinkedParts(context.Background()); err != nil {
+		return fmt.Errorf("failed to link words to parts of speech: %w", err)
+	}
+	if err := repository.NewWordRepository(db).RankUnrankedWords(context.Background()); err != nil {
+		return fmt.Errorf("failed to rank words by frequency: %w", err)
+	}
+	return nil
+}
+
+// migrateDatabase applies the pending migrations to the database at dbPath,
+// creating it if needed
+func migrateDatabase(logger *log.Logger, dbPath string) error {
+	db, err := openDatabase(logger, dbPath)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(logger, db)
+
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+	version, _, err := database.SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	logger.Printf("Database %s is at schema version %d", dbPath, version)
+	return nil
+}
+
+// seedDatabase migrates the database at dbPath and adds the groups and words
+// of profile. Seeding is idempotent, so a database that has words only gains
+// the ones it is missing.
+func seedDatabase(logger *log.Logger, dbPath, profile string) error {
+	db, err := openDatabase(logger, dbPath)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(logger, db)
+
+	if err := database.Migrate(db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	result, err := database.Seed(db, profile)
+	if err != nil {
+		return fmt.Errorf("failed to run seeds: %w", err)
+	}
+	if err := linkWords(db); err != nil {
+		return err
+	}
+	logger.Printf("Seeded database %s with the %s profile: %d groups, %d words and %d group memberships added",
+		dbPath, profile, result.Groups, result.Words, result.Memberships)
+	return nil
+}
+
+// closeDatabase closes the connections, checkpointing the write-ahead log
+func closeDatabase(logger *log.Logger, db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			logger.Printf("Failed to close the database: %v", err)
 		}
 	}
+}
 
-	return db, nil
+// shutdownGrace returns how long a shutdown may take, from shutdownGraceEnv
+func shutdownGrace() (time.Duration, error) {
+	raw := os.Getenv(shutdownGraceEnv)
+	if raw == "" {
+		return defaultShutdownGrace, nil
+	}
+	grace, err := time.ParseDuration(raw)
+	if err != nil || grace <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration such as 30s, got %q", shutdownGraceEnv, raw)
+	}
+	return grace, nil
+}
+
+// tlsSettings returns the HTTPS configuration, or nil to serve plain HTTP.
+// Certificates from Let's Encrypt are kept next to the database at dbPath
+// unless autocertCacheDirEnv is set.
+func tlsSettings(dbPath string) (*app.TLS, error) {
+	t := app.TLS{
+		CertFile: os.Getenv(tlsCertFileEnv),
+		KeyFile:  os.Getenv(tlsKeyFileEnv),
+		CacheDir: os.Getenv(autocertCacheDirEnv),
+		Email:    os.Getenv(autocertEmailEnv),
+	}
+	for _, domain := range strings.Split(os.Getenv(autocertDomainsEnv), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			t.Domains = append(t.Domains, domain)
+		}
+	}
+	redirectPort := os.Getenv(httpRedirectPortEnv)
+	if t.CertFile == "" && t.KeyFile == "" && len(t.Domains) == 0 {
+		if redirectPort != "" {
+			return nil, fmt.Errorf("%s needs HTTPS, configured with %s and %s or %s", httpRedirectPortEnv, tlsCertFileEnv, tlsKeyFileEnv, autocertDomainsEnv)
+		}
+		return nil, nil
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, fmt.Errorf("%s and %s must be set together", tlsCertFileEnv, tlsKeyFileEnv)
+	}
+	if t.CertFile != "" && len(t.Domains) > 0 {
+		return nil, fmt.Errorf("set either %s and %s or %s, not both", tlsCertFileEnv, tlsKeyFileEnv, autocertDomainsEnv)
+	}
+	if len(t.Domains) > 0 && t.CacheDir == "" {
+		t.CacheDir = filepath.Join(filepath.Dir(dbPath), "autocert")
+	}
+	if redirectPort != "" {
+		if port, err := strconv.Atoi(redirectPort); err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("%s must be a port number such as 80, got %q", httpRedirectPortEnv, redirectPort)
+		}
+		t.RedirectAddr = ":" + redirectPort
+	}
+	return &t, nil
+}
+
+// sharedStateClient returns a client of the Redis at redisURLEnv, or nil to
+// keep the cache and events in memory
+func sharedStateClient() (*redis.Client, error) {
+	url := os.Getenv(redisURLEnv)
+	if url == "" {
+		return nil, nil
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a Redis URL such as redis://localhost:6379/0: %w", redisURLEnv, err)
+	}
+	return redis.NewClient(opts), nil
+}
+
+// rateLimitStore returns the Redis store of the rate limits at
+// rateLimitRedisURLEnv, or nil to keep them in memory
+func rateLimitStore() (*middleware.RedisRateLimitStore, error) {
+	url := os.Getenv(rateLimitRedisURLEnv)
+	if url == "" {
+		return nil, nil
+	}
+	store, err := middleware.NewRedisRateLimitStoreFromURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a Redis URL such as redis://localhost:6379/0: %w", rateLimitRedisURLEnv, err)
+	}
+	return store, nil
+}
+
+// sessionExpiry returns how long a study session may stay idle, from
+// sessionExpiryEnv, or 0 to never expire sessions
+func sessionExpiry() (time.Duration, error) {
+	raw := os.Getenv(sessionExpiryEnv)
+	if raw == "" {
+		return defaultSessionExpiry, nil
+	}
+	if raw == "0" {
+		return 0, nil
+	}
+	idle, err := time.ParseDuration(raw)
+	if err != nil || idle <= 0 {
+		return 0, fmt.Errorf("%s must be 0 or a positive duration such as 12h, got %q", sessionExpiryEnv, raw)
+	}
+	return idle, nil
+}
+
+// reviewRetention returns the months of reviews kept live, from
+// reviewRetentionEnv, or 0 to keep every review live
+func reviewRetention() (int, error) {
+	raw := os.Getenv(reviewRetentionEnv)
+	if raw == "" {
+		return 0, nil
+	}
+	months, err := strconv.Atoi(raw)
+	if err != nil || months < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative number of months, got %q", reviewRetentionEnv, raw)
+	}
+	return months, nil
+}
+
+// mediaDir returns the directory in env, otherwise name in the media
+// directory when that exists, otherwise ""
+func mediaDir(env, name string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	if media := os.Getenv(mediaDirEnv); media != "" {
+		dir := filepath.Join(media, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// strokeSource returns where stroke order data is read from
+func strokeSource() service.StrokeSource {
+	if dir := mediaDir(kanjiVGDirEnv, "kanjivg"); dir != "" {
+		return service.KanjiVGDir(dir)
+	}
+	url := os.Getenv(kanjiVGURLEnv)
+	if url == "" {
+		url = service.DefaultKanjiVGURL
+	}
+	return &service.KanjiVGHTTP{BaseURL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// dictionaryProvider returns where dictionary lookups are answered from
+func dictionaryProvider() dictionary.Provider {
+	if path := os.Getenv(jmdictFileEnv); path != "" {
+		return dictionary.NewJMdict(path)
+	}
+	return &dictionary.Jisho{Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// textReader returns what reads the text in images for vocabulary capture
+func textReader() ocr.Reader {
+	if key := os.Getenv(visionAPIKeyEnv); key != "" {
+		return &ocr.Vision{APIKey: key, Client: &http.Client{Timeout: 30 * time.Second}}
+	}
+	return &ocr.Tesseract{Path: os.Getenv(tesseractPathEnv)}
+}
+
+// audioSource returns where word audio is read from, or nil when none is configured
+func audioSource() service.AudioSource {
+	if dir := mediaDir(audioDirEnv, "audio"); dir != "" {
+		return service.AudioDir(dir)
+	}
+	return nil
+}
+
+// speechRecognizer returns what transcribes pronunciation checks, or nil when
+// none is configured
+func speechRecognizer() speech.Recognizer {
+	if command := strings.Fields(os.Getenv(speechCommandEnv)); len(command) > 0 {
+		return &speech.Command{Path: command[0], Args: command[1:]}
+	}
+	url, key := os.Getenv(whisperURLEnv), os.Getenv(whisperAPIKeyEnv)
+	if url == "" && key == "" {
+		return nil
+	}
+	return &speech.Whisper{
+		URL:    url,
+		APIKey: key,
+		Model:  os.Getenv(whisperModelEnv),
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// embeddingProvider returns what embeds words for related-word suggestions, or
+// nil when none is configured
+func embeddingProvider() embeddings.Provider {
+	url, key := os.Getenv(embeddingsURLEnv), os.Getenv(embeddingsAPIKeyEnv)
+	if url == "" && key == "" {
+		return nil
+	}
+	return &embeddings.OpenAI{
+		URL:       url,
+		APIKey:    key,
+		ModelName: os.Getenv(embeddingsModelEnv),
+		Client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// languageModel returns the language model for the AI features: a router over
+// the configured providers, or nil when there are none
+func languageModel() (*llm.Router, error) {
+	names := os.Getenv(llmProvidersEnv)
+	if names == "" && (os.Getenv(llmURLEnv) != "" || os.Getenv(llmAPIKeyEnv) != "") {
+		names = "openai"
+	}
+	// Attempts are bounded by the router rather than the HTTP client, which
+	// would cut streamed replies short
+	client := &http.Client{}
+	var providers []llm.Provider
+	for _, name := range strings.Split(names, ",") {
+		var provider llm.Client
+		switch name = strings.TrimSpace(name); name {
+		case "":
+			continue
+		case "openai":
+			provider = &llm.OpenAI{URL: os.Getenv(llmURLEnv), APIKey: os.Getenv(llmAPIKeyEnv), Model: os.Getenv(llmModelEnv), Client: client}
+		case "ollama":
+			provider = &llm.Ollama{URL: os.Getenv(ollamaURLEnv), Model: os.Getenv(ollamaModelEnv), Client: client}
+		case "bedrock":
+			if os.Getenv(bedrockURLEnv) == "" {
+				return nil, fmt.Errorf("%s is required for the bedrock provider", bedrockURLEnv)
+			}
+			provider = &llm.Bedrock{URL: os.Getenv(bedrockURLEnv), APIKey: os.Getenv(bedrockAPIKeyEnv), Model: os.Getenv(bedrockModelEnv), Client: client}
+		default:
+			return nil, fmt.Errorf("%s: unknown provider %q, expected openai, ollama or bedrock", llmProvidersEnv, name)
+		}
+		providers = append(providers, llm.Provider{Name: name, Client: provider})
+	}
+	if len(providers) == 0 {
+		return nil, nil
+	}
+
+	var config llm.RouterConfig
+	if raw := os.Getenv(llmTimeoutEnv); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("%s must be a positive duration such as 30s, got %q", llmTimeoutEnv, raw)
+		}
+		config.Timeout = timeout
+	}
+	if raw := os.Getenv(llmRetriesEnv); raw != "" {
+		retries, err := strconv.Atoi(raw)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("%s must be a number of retries, got %q", llmRetriesEnv, raw)
+		}
+		// The router reads zero as its default and a negative count as none
+		config.Retries = retries
+		if retries == 0 {
+			config.Retries = -1
+		}
+	}
+	return llm.NewRouter(config, providers...), nil
+}
+
+// aiQuota returns the daily quota of each client of the AI features
+func aiQuota() (service.AIQuota, error) {
+	quota := service.AIQuota{Requests: defaultAIDailyRequests, Tokens: defaultAIDailyTokens}
+	for env, limit := range map[string]*int64{aiDailyRequestsEnv: &quota.Requests, aiDailyTokensEnv: &quota.Tokens} {
+		raw := os.Getenv(env)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			return service.AIQuota{}, fmt.Errorf("%s must be a non-negative number, got %q", env, raw)
+		}
+		*limit = n
+	}
+	return quota, nil
+}
+
+// bodyLimits returns the caps on request bodies, the defaults unless
+// configured
+func bodyLimits() (api.BodyLimits, error) {
+	limits := api.DefaultBodyLimits()
+	for env, limit := range map[string]*int64{
+		maxBodyBytesEnv:      &limits.JSON,
+		maxImportBytesEnv:    &limits.Import,
+		maxRecordingBytesEnv: &limits.Recording,
+		maxImageBytesEnv:     &limits.Image,
+		maxArchiveBytesEnv:   &limits.Archive,
+	} {
+		raw := os.Getenv(env)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n <= 0 {
+			return api.BodyLimits{}, fmt.Errorf("%s must be a positive number of bytes, got %q", env, raw)
+		}
+		*limit = n
+	}
+	return limits, nil
+}
+
+// mailSender returns what sends reminder emails, or nil when none is configured
+func mailSender() mail.Sender {
+	if key := os.Getenv(sendGridAPIKeyEnv); key != "" {
+		return &mail.SendGrid{URL: os.Getenv(sendGridURLEnv), APIKey: key, Client: &http.Client{Timeout: 30 * time.Second}}
+	}
+	if addr := os.Getenv(smtpAddrEnv); addr != "" {
+		return &mail.SMTP{Addr: addr, Username: os.Getenv(smtpUsernameEnv), Password: os.Getenv(smtpPasswordEnv)}
+	}
+	return nil
+}
+
+// xpRules returns the experience awards and level curve, the defaults unless
+// configured
+func xpRules() (service.XPRules, error) {
+	rules := service.DefaultXPRules()
+	for env, amount := range map[string]*int64{xpReviewEnv: &rules.Review, xpSessionEnv: &rules.Session, xpGoalEnv: &rules.Goal} {
+		raw := os.Getenv(env)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			return service.XPRules{}, fmt.Errorf("%s must be a non-negative number, got %q", env, raw)
+		}
+		*amount = n
+	}
+	if raw := os.Getenv(xpLevelsEnv); raw != "" {
+		rules.Levels = nil
+		for _, field := range strings.Split(raw, ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+			if err != nil {
+				return service.XPRules{}, fmt.Errorf("%s must list numbers separated by commas, got %q", xpLevelsEnv, raw)
+			}
+			rules.Levels = append(rules.Levels, n)
+		}
+	}
+	if err := rules.Validate(); err != nil {
+		return service.XPRules{}, fmt.Errorf("%s: %w", xpLevelsEnv, err)
+	}
+	return rules, nil
+}
+
+// serviceName names the service in request spans
+func serviceName() string {
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		return name
+	}
+	return telemetry.DefaultServiceName
 }
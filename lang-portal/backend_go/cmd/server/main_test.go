@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/database"
+	"lang-portal/backend_go/internal/web"
+)
+
+// clearCommandEnv unsets the environment parseCommand reads, so that the
+// machine running the tests does not leak into them
+func clearCommandEnv(t *testing.T) {
+	for _, env := range []string{database.EnvPath, database.EnvURL, database.EnvDataDir, seedProfileEnv, frontendEnv} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want command
+	}{
+		{name: "no command serves", args: nil, want: command{name: commandServe, dbPath: database.DefaultPath, profile: database.DefaultSeedProfile}},
+		{name: "flags without a command serve", args: []string{"-db", "portal.db"}, want: command{name: commandServe, dbPath: "portal.db", profile: database.DefaultSeedProfile}},
+		{name: "serve", args: []string{"serve", "-profile", "minimal"}, want: command{name: commandServe, dbPath: database.DefaultPath, profile: database.SeedProfileMinimal}},
+		{name: "migrate", args: []string{"migrate", "-db", "/srv/portal.db"}, want: command{name: commandMigrate, dbPath: "/srv/portal.db"}},
+		{name: "seed", args: []string{"seed", "-db=portal.db", "-profile=jlpt-n5"}, want: command{name: commandSeed, dbPath: "portal.db", profile: database.SeedProfileJLPTN5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCommandEnv(t)
+
+			cmd, err := parseCommand(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cmd)
+		})
+	}
+}
+
+func TestParseCommand_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "unknown command", args: []string{"start"}, want: `unknown command "start"`},
+		{name: "unknown flag", args: []string{"serve", "-port", "80"}, want: "flag provided but not defined: -port"},
+		{name: "profile of migrate", args: []string{"migrate", "-profile", "demo"}, want: "flag provided but not defined: -profile"},
+		{name: "frontend of seed", args: []string{"seed", "-frontend"}, want: "flag provided but not defined: -frontend"},
+		{name: "unknown profile", args: []string{"seed", "-profile", "n1"}, want: `unknown seed profile "n1"`},
+		{name: "extra arguments", args: []string{"migrate", "now"}, want: "unexpected arguments: now"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCommandEnv(t)
+
+			_, err := parseCommand(tt.args)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	t.Run("help", func(t *testing.T) {
+		clearCommandEnv(t)
+
+		_, err := parseCommand([]string{"migrate", "-h"})
+		assert.Equal(t, flag.ErrHelp, err)
+	})
+}
+
+func TestParseCommand_Environment(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    []string
+		db      string
+		profile string
+	}{
+		{name: "database from the environment", env: map[string]string{database.EnvPath: "/srv/env.db"}, db: "/srv/env.db"},
+		{name: "database URL", env: map[string]string{database.EnvURL: "sqlite:///srv/url.db"}, db: "/srv/url.db"},
+		{name: "flag over the environment", env: map[string]string{database.EnvPath: "/srv/env.db"}, args: []string{"-db", "/srv/flag.db"}, db: "/srv/flag.db"},
+		{name: "data directory", env: map[string]string{database.EnvDataDir: "/data"}, db: filepath.Join("/data", database.DefaultPath)},
+		{name: "data directory does not apply to the flag", env: map[string]string{database.EnvDataDir: "/data"}, args: []string{"-db", "portal.db"}, db: "portal.db"},
+		{name: "profile from the environment", env: map[string]string{seedProfileEnv: "minimal"}, db: database.DefaultPath, profile: database.SeedProfileMinimal},
+		{name: "profile flag over the environment", env: map[string]string{seedProfileEnv: "minimal"}, args: []string{"-profile", "jlpt-n5"}, db: database.DefaultPath, profile: database.SeedProfileJLPTN5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCommandEnv(t)
+			for env, value := range tt.env {
+				t.Setenv(env, value)
+			}
+
+			cmd, err := parseCommand(append([]string{"seed"}, tt.args...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.db, cmd.dbPath)
+			want := tt.profile
+			if want == "" {
+				want = database.DefaultSeedProfile
+			}
+			assert.Equal(t, want, cmd.profile)
+		})
+	}
+
+	t.Run("invalid database URL", func(t *testing.T) {
+		clearCommandEnv(t)
+		t.Setenv(database.EnvURL, "postgres://db/portal")
+
+		_, err := parseCommand([]string{"migrate"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), database.EnvURL)
+	})
+
+	t.Run("unknown profile from the environment", func(t *testing.T) {
+		clearCommandEnv(t)
+		t.Setenv(seedProfileEnv, "n1")
+
+		_, err := parseCommand([]string{"serve"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown seed profile "n1"`)
+	})
+}
+
+func TestParseCommand_Frontend(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		env  string
+		args []string
+	}{
+		{name: "flag", args: []string{"serve", "-frontend"}},
+		{name: "environment", env: "true", args: []string{"serve"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCommandEnv(t)
+			t.Setenv(frontendEnv, tt.env)
+
+			cmd, err := parseCommand(tt.args)
+			if web.Assets() == nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "built without the frontend")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, cmd.frontend)
+		})
+	}
+
+	t.Run("flag turns off the environment", func(t *testing.T) {
+		clearCommandEnv(t)
+		t.Setenv(frontendEnv, "true")
+
+		cmd, err := parseCommand([]string{"serve", "-frontend=false"})
+		require.NoError(t, err)
+		assert.False(t, cmd.frontend)
+	})
+}
@@ -1,64 +0,0 @@
--- Create words table
-CREATE TABLE IF NOT EXISTS words (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    japanese TEXT NOT NULL,
-    romaji TEXT NOT NULL,
-    english TEXT NOT NULL,
-    parts JSON NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create groups table
-CREATE TABLE IF NOT EXISTS groups (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    name TEXT NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create word_groups join table
-CREATE TABLE IF NOT EXISTS word_groups (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    word_id INTEGER NOT NULL,
-    group_id INTEGER NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (word_id) REFERENCES words(id),
-    FOREIGN KEY (group_id) REFERENCES groups(id)
-);
-
--- Create study_activities table
-CREATE TABLE IF NOT EXISTS study_activities (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    name TEXT NOT NULL,
-    description TEXT NOT NULL,
-    thumbnail_url TEXT NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create study_sessions table
-CREATE TABLE IF NOT EXISTS study_sessions (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    group_id INTEGER NOT NULL,
-    study_activity_id INTEGER NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (group_id) REFERENCES groups(id),
-    FOREIGN KEY (study_activity_id) REFERENCES study_activities(id)
-);
-
--- Create word_review_items table
-CREATE TABLE IF NOT EXISTS word_review_items (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    word_id INTEGER NOT NULL,
-    study_session_id INTEGER NOT NULL,
-    correct BOOLEAN NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (word_id) REFERENCES words(id),
-    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id)
-);
-
--- Create indexes
-CREATE INDEX IF NOT EXISTS idx_word_groups_word_id ON word_groups(word_id);
-CREATE INDEX IF NOT EXISTS idx_word_groups_group_id ON word_groups(group_id);
-CREATE INDEX IF NOT EXISTS idx_study_sessions_group_id ON study_sessions(group_id);
-CREATE INDEX IF NOT EXISTS idx_study_sessions_activity_id ON study_sessions(study_activity_id);
-CREATE INDEX IF NOT EXISTS idx_word_reviews_word_id ON word_review_items(word_id);
-CREATE INDEX IF NOT EXISTS idx_word_reviews_session_id ON word_review_items(study_session_id); 
\ No newline at end of file
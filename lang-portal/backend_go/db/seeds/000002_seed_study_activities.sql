@@ -1,7 +0,0 @@
--- Insert initial study activities
-INSERT INTO study_activities (name, description, thumbnail_url) VALUES
-    ('Flashcards', 'Practice vocabulary with flashcards', '/images/activities/flashcards.jpg'),
-    ('Multiple Choice', 'Test your knowledge with multiple choice questions', '/images/activities/multiple-choice.jpg'),
-    ('Word Matching', 'Match words with their meanings', '/images/activities/word-matching.jpg'),
-    ('Sentence Completion', 'Complete sentences with the correct words', '/images/activities/sentence-completion.jpg'),
-    ('Listening Practice', 'Practice listening and understanding spoken words', '/images/activities/listening.jpg'); 
\ No newline at end of file
@@ -0,0 +1,45 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/app"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+func TestAdminAPI_KeysNeedAnAdminKey(t *testing.T) {
+	s := apitest.New(t, app.WithBootstrapAdminKey("bootstrap-secret"))
+	newKey := func(scopes ...string) service.NewAPIKey {
+		return service.NewAPIKey{Name: "import script", Scopes: scopes}
+	}
+
+	// Anonymous requests cannot grant themselves a key
+	resp := s.Post("/api/v1/admin/keys", newKey(models.ScopeAdmin)).Status(http.StatusUnauthorized)
+	assert.Equal(t, middleware.CodeUnauthorized, resp.ErrorCode())
+	s.Get("/api/v1/admin/keys").Status(http.StatusUnauthorized)
+	s.Delete("/api/v1/admin/keys/1").Status(http.StatusUnauthorized)
+
+	s.Header.Set(middleware.APIKeyHeader, "bootstrap-secret")
+	writer := apitest.JSON[service.CreatedAPIKey](s.Post("/api/v1/admin/keys", newKey(models.ScopeWrite)).Status(http.StatusCreated))
+	require.NotEmpty(t, writer.Key)
+
+	s.Header.Set(middleware.APIKeyHeader, writer.Key)
+	resp = s.Post("/api/v1/admin/keys", newKey(models.ScopeAdmin)).Status(http.StatusForbidden)
+	assert.Equal(t, middleware.CodeForbidden, resp.ErrorCode())
+
+	s.Header.Set(middleware.APIKeyHeader, "bootstrap-secret")
+	s.Delete(fmt.Sprintf("/api/v1/admin/keys/%d", writer.ID)).Status(http.StatusNoContent)
+}
+
+func TestAdminAPI_KeysWithoutBootstrapKey(t *testing.T) {
+	s := apitest.New(t)
+	s.Post("/api/v1/admin/keys", service.NewAPIKey{Name: "x", Scopes: []string{models.ScopeAdmin}}).Status(http.StatusUnauthorized)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"lang-portal/backend_go/internal/service"
+)
+
+// BodyLimits caps the size of request bodies, in bytes. Larger bodies are
+// rejected with 413 Request Entity Too Large.
+type BodyLimits struct {
+	// JSON applies to every route without a limit of its own
+	JSON int64
+	// Import applies to word lists sent to /words/import
+	Import int64
+	// Recording applies to recordings sent for pronunciation checks
+	Recording int64
+	// Image applies to images sent for vocabulary capture
+	Image int64
+	// Archive applies to account archives sent to /import/archive
+	Archive int64
+}
+
+// DefaultBodyLimits returns the limits used unless configured otherwise
+func DefaultBodyLimits() BodyLimits {
+	return BodyLimits{
+		JSON:      1 << 20,
+		Import:    5 << 20,
+		Recording: service.MaxRecordingSize,
+		Image:     service.MaxCaptureImageSize,
+		Archive:   100 << 20,
+	}
+}
+
+// uploadLimits maps the full paths of upload routes to their body limit
+type uploadLimits map[string]int64
+
+// declare records limit for the route at path in group
+func (u uploadLimits) declare(group *gin.RouterGroup, path string, limit int64) {
+	u[group.BasePath()+path] = limit
+}
@@ -0,0 +1,59 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/app"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+// createKey creates an API key with the bootstrap key and returns its secret
+func createKey(t *testing.T, s *apitest.Server, name string, scopes ...string) string {
+	t.Helper()
+	s.Header.Set(middleware.APIKeyHeader, "bootstrap-secret")
+	defer s.Header.Del(middleware.APIKeyHeader)
+	created := apitest.JSON[service.CreatedAPIKey](s.Post("/api/v1/admin/keys", service.NewAPIKey{Name: name, Scopes: scopes}).Status(http.StatusCreated))
+	require.NotEmpty(t, created.Key)
+	return created.Key
+}
+
+func TestCohortsAPI_NeedAnAPIKey(t *testing.T) {
+	s := apitest.New(t, app.WithBootstrapAdminKey("bootstrap-secret"))
+	teacher := createKey(t, s, "teacher", models.ScopeWrite)
+	other := createKey(t, s, "other teacher", models.ScopeWrite)
+
+	s.Header.Set(middleware.APIKeyHeader, teacher)
+	cohort := apitest.JSON[models.Cohort](s.Post("/api/v1/cohorts", map[string]string{"name": "Class 1A"}).Status(http.StatusCreated))
+	assert.Equal(t, "key:teacher", cohort.Owner)
+	path := fmt.Sprintf("/api/v1/cohorts/%d", cohort.ID)
+	s.Post(path+"/members", map[string]string{"learner": "key:student"}).Status(http.StatusCreated)
+
+	// Naming the teacher in the actor header is not enough
+	s.Header.Del(middleware.APIKeyHeader)
+	for _, actor := range []string{"teacher", "key:teacher", "key:student"} {
+		s.Header.Set(middleware.ActorHeader, actor)
+		resp := s.Get(path + "/stats").Status(http.StatusUnauthorized)
+		assert.Equal(t, middleware.CodeUnauthorized, resp.ErrorCode())
+		s.Get(path + "/leaderboard").Status(http.StatusUnauthorized)
+		s.Put(path+"/membership", map[string]interface{}{"display_name": "Mallory"}).Status(http.StatusUnauthorized)
+		s.Delete(path + "/members/key:student").Status(http.StatusUnauthorized)
+		s.Delete(path).Status(http.StatusUnauthorized)
+	}
+	s.Header.Del(middleware.ActorHeader)
+
+	// Another key's cohorts do not exist for it
+	s.Header.Set(middleware.APIKeyHeader, other)
+	s.Get(path).Status(http.StatusNotFound)
+	s.Delete(path).Status(http.StatusNotFound)
+
+	s.Header.Set(middleware.APIKeyHeader, teacher)
+	s.Get(path + "/stats").Status(http.StatusOK)
+}
@@ -0,0 +1,71 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+// TestResponses_Golden snapshots the JSON of representative responses of both
+// API versions, so that renamed fields and changed shapes show up in review.
+// Run with -update to accept intended changes.
+func TestResponses_Golden(t *testing.T) {
+	s := apitest.New(t)
+	water := createWord(t, s, "水", "mizu", "water")
+	fire := createWord(t, s, "火", "hi", "fire")
+	group := createGroup(t, s, "Elements", water, fire)
+	activity := createActivity(t, s, "")
+
+	session := s.Post("/api/v1/study/sessions", map[string]interface{}{
+		"group_id":          group.ID,
+		"study_activity_id": activity.ID,
+		"client":            map[string]string{"device_label": "laptop"},
+	}).Status(http.StatusCreated).Golden("study_session_created")
+	sessionID := apitest.JSON[struct {
+		ID uint `json:"id"`
+	}](session).ID
+	reviews := fmt.Sprintf("/api/v1/study/sessions/%d/reviews", sessionID)
+	s.Post(reviews, map[string]interface{}{"word_id": water.ID, "correct": true, "answer_time_ms": 1200}).
+		Status(http.StatusCreated).Golden("word_review_created")
+	s.Post(reviews, map[string]interface{}{"word_id": fire.ID, "correct": false}).Status(http.StatusCreated)
+	s.Post(fmt.Sprintf("/api/v1/study/sessions/%d/complete", sessionID), nil).
+		Status(http.StatusOK).Golden("study_session_summary")
+
+	for _, tc := range []struct {
+		name string
+		path string
+	}{
+		{"words", "/api/v1/words"},
+		{"word_detail", fmt.Sprintf("/api/v1/words/%d", water.ID)},
+		{"word_groups", fmt.Sprintf("/api/v1/words/%d/groups", water.ID)},
+		{"words_v2", "/api/v2/words"},
+		{"word_detail_v2", fmt.Sprintf("/api/v2/words/%d", water.ID)},
+		{"parts_of_speech", "/api/v1/words/parts"},
+		{"groups", "/api/v1/groups"},
+		{"group_detail", fmt.Sprintf("/api/v1/groups/%d", group.ID)},
+		{"group_words", fmt.Sprintf("/api/v1/groups/%d/words", group.ID)},
+		{"group_words_raw", fmt.Sprintf("/api/v1/groups/%d/raw", group.ID)},
+		{"group_stats", fmt.Sprintf("/api/v1/groups/%d/stats", group.ID)},
+		{"study_activities", "/api/v1/study/activities"},
+		{"study_activity", fmt.Sprintf("/api/v1/study/activities/%d", activity.ID)},
+		{"study_sessions", "/api/v1/study/sessions"},
+		{"study_session", fmt.Sprintf("/api/v1/study/sessions/%d", sessionID)},
+		{"study_session_reviews", reviews},
+		{"dashboard_last_session", "/api/v1/dashboard/last-session"},
+		{"dashboard_progress", "/api/v1/dashboard/progress"},
+		{"dashboard_quick_stats", "/api/v1/dashboard/quick-stats"},
+		{"dashboard_by_part", "/api/v1/dashboard/stats/by-part"},
+		{"error_not_found", "/api/v1/words/999"},
+		{"error_bad_request", "/api/v1/words/abc"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := s.Get(tc.path)
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("GET %s: status %d, body: %s", tc.path, resp.StatusCode, resp.Body)
+			}
+			resp.Golden(tc.name)
+		})
+	}
+}
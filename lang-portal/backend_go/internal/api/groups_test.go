@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/api"
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+// createGroup creates a group through the API and returns it as stored
+func createGroup(t *testing.T, s *apitest.Server, name string, words ...models.Word) models.Group {
+	t.Helper()
+	group := apitest.JSON[models.Group](s.Post("/api/v1/groups", map[string]string{"name": name}).Status(http.StatusCreated))
+	for _, word := range words {
+		s.Post(fmt.Sprintf("/api/v1/groups/%d/words/%d", group.ID, word.ID), nil).Status(http.StatusNoContent)
+	}
+	return group
+}
+
+func TestGroupsAPI_ManageWords(t *testing.T) {
+	s := apitest.New(t)
+	water := createWord(t, s, "水", "mizu", "water")
+	fire := createWord(t, s, "火", "hi", "fire")
+	group := createGroup(t, s, "Elements", water, fire)
+
+	detail := apitest.JSON[service.GroupDetail](s.Get(fmt.Sprintf("/api/v1/groups/%d", group.ID)).Status(http.StatusOK))
+	assert.Equal(t, "Elements", detail.Name)
+	assert.Equal(t, 2, detail.WordCount)
+
+	words := apitest.JSON[middleware.PaginatedResponse[service.Word]](s.Get(fmt.Sprintf("/api/v1/groups/%d/words?sort_by=english&order=asc", group.ID)).Status(http.StatusOK))
+	require.Len(t, words.Items, 2)
+	assert.Equal(t, "fire", words.Items[0].English)
+
+	groups := apitest.JSON[middleware.PaginatedResponse[service.Group]](s.Get(fmt.Sprintf("/api/v1/words/%d/groups", water.ID)).Status(http.StatusOK))
+	require.Len(t, groups.Items, 1)
+	assert.Equal(t, group.ID, groups.Items[0].ID)
+
+	s.Delete(fmt.Sprintf("/api/v1/groups/%d/words/%d", group.ID, fire.ID)).Status(http.StatusNoContent)
+	detail = apitest.JSON[service.GroupDetail](s.Get(fmt.Sprintf("/api/v1/groups/%d", group.ID)).Status(http.StatusOK))
+	assert.Equal(t, 1, detail.WordCount)
+}
+
+func TestGroupsAPI_ListAndDelete(t *testing.T) {
+	s := apitest.New(t)
+	createGroup(t, s, "Verbs")
+	group := createGroup(t, s, "Adjectives")
+
+	list := apitest.JSON[middleware.PaginatedResponse[service.Group]](s.Get("/api/v1/groups?sort_by=name&order=asc").Status(http.StatusOK))
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, "Adjectives", list.Items[0].Name)
+
+	s.Delete(fmt.Sprintf("/api/v1/groups/%d", group.ID)).Status(http.StatusNoContent)
+	s.Get(fmt.Sprintf("/api/v1/groups/%d", group.ID)).Status(http.StatusNotFound)
+}
+
+func TestGroupsAPI_RejectsInvalidRequests(t *testing.T) {
+	s := apitest.New(t)
+	group := createGroup(t, s, "Verbs")
+
+	resp := s.Post("/api/v1/groups", map[string]string{"name": "Verbs"}).Status(http.StatusBadRequest)
+	assert.Equal(t, "A group with this name already exists", resp.Error())
+	s.Get("/api/v1/groups/abc").Status(http.StatusBadRequest)
+	s.Post(fmt.Sprintf("/api/v1/groups/%d/words/999", group.ID), nil).Status(http.StatusNotFound)
+}
+
+func TestGroupsAPI_NamesArePerLanguage(t *testing.T) {
+	s := apitest.New(t)
+	japanese := createGroup(t, s, "Food")
+
+	korean := apitest.JSON[models.Group](s.Post("/api/v1/groups", map[string]string{"name": "Food", "language": "ko"}).Status(http.StatusCreated))
+	assert.NotEqual(t, japanese.ID, korean.ID)
+	assert.Equal(t, models.LanguageKorean, korean.Language)
+
+	resp := s.Post("/api/v1/groups", map[string]string{"name": "Food", "language": "ko"}).Status(http.StatusBadRequest)
+	assert.Equal(t, "A group with this name already exists", resp.Error())
+
+	// Renames are checked against the groups of the same language
+	other := apitest.JSON[models.Group](s.Post("/api/v1/groups", map[string]string{"name": "Drinks", "language": "ko"}).Status(http.StatusCreated))
+	s.Put(fmt.Sprintf("/api/v1/groups/%d", other.ID), map[string]string{"name": "Food"}).Status(http.StatusBadRequest)
+	s.Put(fmt.Sprintf("/api/v1/groups/%d", japanese.ID), map[string]string{"name": "Drinks"}).Status(http.StatusNoContent)
+}
+
+func TestGroupsAPI_Languages(t *testing.T) {
+	s := apitest.New(t)
+	water := createWord(t, s, "水", "mizu", "water")
+	mul := apitest.JSON[api.WordV2](s.Post("/api/v2/words", map[string]interface{}{
+		"language":    "ko",
+		"term":        "물",
+		"reading":     "mul",
+		"translation": "water",
+		"parts":       []string{"noun"},
+	}).Status(http.StatusCreated))
+	assert.Equal(t, models.LanguageKorean, mul.Language)
+
+	korean := apitest.JSON[models.Group](s.Post("/api/v1/groups", map[string]string{"name": "Korean basics", "language": "ko"}).Status(http.StatusCreated))
+	assert.Equal(t, models.LanguageKorean, korean.Language)
+	japanese := createGroup(t, s, "Japanese basics", water)
+	assert.Equal(t, models.LanguageJapanese, japanese.Language)
+
+	s.Post(fmt.Sprintf("/api/v1/groups/%d/words/%d", korean.ID, mul.ID), nil).Status(http.StatusNoContent)
+	s.Post(fmt.Sprintf("/api/v1/groups/%d/words/%d", korean.ID, water.ID), nil).Status(http.StatusBadRequest)
+
+	groups := apitest.JSON[middleware.PaginatedResponse[service.Group]](s.Get("/api/v1/groups?language=ko").Status(http.StatusOK))
+	require.Len(t, groups.Items, 1)
+	assert.Equal(t, "Korean basics", groups.Items[0].Name)
+	assert.Equal(t, 1, groups.Pagination.TotalItems)
+
+	words := apitest.JSON[middleware.PaginatedResponse[api.WordV2]](s.Get("/api/v2/words?language=ja").Status(http.StatusOK))
+	require.Len(t, words.Items, 1)
+	assert.Equal(t, "水", words.Items[0].Term)
+
+	stats := apitest.JSON[service.LanguageBreakdown](s.Get("/api/v1/dashboard/stats/by-language").Status(http.StatusOK))
+	assert.Equal(t, []service.LanguageStats{
+		{Language: "ja", Name: "Japanese", Words: 1, Groups: 1},
+		{Language: "ko", Name: "Korean", Words: 1, Groups: 1},
+	}, stats.Languages)
+
+	languages := apitest.JSON[struct {
+		Items []models.TargetLanguage `json:"items"`
+	}](s.Get("/api/v2/languages").Status(http.StatusOK))
+	assert.Equal(t, models.TargetLanguages, languages.Items)
+
+	s.Post("/api/v1/groups", map[string]string{"name": "Klingon", "language": "tlh"}).Status(http.StatusBadRequest)
+	s.Get("/api/v1/words?language=tlh").Status(http.StatusBadRequest)
+	s.Put(fmt.Sprintf("/api/v2/words/%d", mul.ID), map[string]interface{}{
+		"language": "ja", "term": "物", "reading": "mono", "translation": "thing", "parts": []string{"noun"},
+	}).Status(http.StatusBadRequest)
+}
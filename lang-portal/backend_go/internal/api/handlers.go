@@ -1,10 +1,20 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/importer"
+	"lang-portal/backend_go/internal/jpn"
 	"lang-portal/backend_go/internal/models"
 	"lang-portal/backend_go/internal/service"
 
@@ -15,9 +25,9 @@ import (
 
 func GetLastStudySession(s *service.DashboardService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		session, err := s.GetLastStudySession()
+		session, err := s.GetLastStudySession(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 		c.JSON(http.StatusOK, session)
@@ -26,332 +36,345 @@ func GetLastStudySession(s *service.DashboardService) gin.HandlerFunc {
 
 func GetStudyProgress(s *service.DashboardService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		progress, err := s.GetStudyProgress()
+		progress, err := s.GetStudyProgress(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 		c.JSON(http.StatusOK, progress)
 	}
 }
 
-func GetQuickStats(s *service.DashboardService) gin.HandlerFunc {
+// GetProgressHistory returns time-bucketed review activity for charts
+func GetProgressHistory(s *service.DashboardService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		stats, err := s.GetQuickStats()
+		periods := 0
+		if raw := c.Query("periods"); raw != "" {
+			var err error
+			if periods, err = strconv.Atoi(raw); err != nil || periods < 1 {
+				middleware.AbortInvalidInput(c, "Invalid periods value")
+				return
+			}
+		}
+
+		history, err := s.GetProgressHistory(c.Request.Context(), c.Query("interval"), periods)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
-		c.JSON(http.StatusOK, stats)
+		c.JSON(http.StatusOK, history)
 	}
 }
 
-// Word Handlers
-
-func CreateWord(s *service.WordService) gin.HandlerFunc {
+// GetDailySummaries returns the caller's precomputed study summaries per day
+func GetDailySummaries(s *service.DailySummaryService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var word models.Word
-		if err := c.ShouldBindJSON(&word); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		summaries, err := s.GetDailySummaries(c.Request.Context(), middleware.Actor(c), c.Query("from"), c.Query("to"))
+		if err != nil {
+			c.Error(err)
 			return
 		}
+		c.JSON(http.StatusOK, summaries)
+	}
+}
 
-		if err := s.CreateWord(&word); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// GetDeviceStats returns study statistics per device
+func GetDeviceStats(s *service.DashboardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := s.GetDeviceStats(c.Request.Context())
+		if err != nil {
+			c.Error(err)
 			return
 		}
-
-		c.JSON(http.StatusCreated, word)
+		c.JSON(http.StatusOK, stats)
 	}
 }
 
-func GetWord(s *service.WordService) gin.HandlerFunc {
+// GetPartStats returns review accuracy per part of speech
+func GetPartStats(s *service.DashboardService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		stats, err := s.GetPartStats(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+			c.Error(err)
 			return
 		}
+		c.JSON(http.StatusOK, stats)
+	}
+}
 
-		word, err := s.GetWord(uint(id))
+// GetLanguageStats returns vocabulary and study statistics per target language
+func GetLanguageStats(s *service.DashboardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := s.GetLanguageStats(c.Request.Context())
 		if err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
-
-		c.JSON(http.StatusOK, word)
+		c.JSON(http.StatusOK, stats)
 	}
 }
 
-func ListWords(s *service.WordService) gin.HandlerFunc {
+func GetQuickStats(s *service.DashboardService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ginParams := middleware.GetPaginationParams(c)
-		serviceParams := service.PaginationParams{
-			Page:     ginParams.Page,
-			PageSize: ginParams.PageSize,
-		}
-
-		servicePaginatedResult, err := s.ListWords(serviceParams)
+		stats, err := s.GetQuickStats(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
+		c.JSON(http.StatusOK, stats)
+	}
+}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
-		}
+// Word Handlers
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
-		c.JSON(http.StatusOK, response)
+// sortFromQuery returns the sort_by and order query parameters for the service
+func sortFromQuery(c *gin.Context) service.SortParams {
+	sort := middleware.GetQueryParams(c).Sort
+	return service.SortParams{By: sort.By, Order: sort.Order}
+}
+
+// wordListOptionsFromQuery reads the sort and the filters of word lists
+func wordListOptionsFromQuery(c *gin.Context) service.WordListOptions {
+	return service.WordListOptions{
+		Sort:     sortFromQuery(c),
+		Status:   middleware.GetQueryParams(c).Filters["status"],
+		Part:     middleware.GetQueryParams(c).Filters["part"],
+		Language: middleware.GetQueryParams(c).Filters["language"],
 	}
 }
 
-func UpdateWord(s *service.WordService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
-			return
-		}
+// meaningLanguage returns the language of word meanings asked for with the
+// lang parameter, "" when none was. It writes a 400 response and returns
+// false for a language meanings are not kept in.
+func meaningLanguage(c *gin.Context) (string, bool) {
+	lang := c.Query("lang")
+	if lang != "" && !slices.Contains(models.MeaningLanguages, lang) {
+		middleware.AbortInvalidInput(c, "lang must be one of "+strings.Join(models.MeaningLanguages, ", "))
+		return "", false
+	}
+	return lang, true
+}
 
+func CreateWord(s *service.WordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		var word models.Word
 		if err := c.ShouldBindJSON(&word); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			middleware.AbortInvalidBody(c, err)
 			return
 		}
 
-		if err := s.UpdateWord(uint(id), &word); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err := s.CreateWord(c.Request.Context(), &word); err != nil {
+			c.Error(err)
 			return
 		}
 
-		c.Status(http.StatusNoContent)
+		c.JSON(http.StatusCreated, word)
 	}
 }
 
-func DeleteWord(s *service.WordService) gin.HandlerFunc {
+// wordExportContentTypes are the content types of the word export formats
+var wordExportContentTypes = map[string]string{
+	service.WordExportCSV:    "text/csv; charset=utf-8",
+	service.WordExportNDJSON: "application/x-ndjson",
+}
+
+// ExportWords streams the whole vocabulary as a CSV or NDJSON download
+func ExportWords(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+		format := c.DefaultQuery("format", service.WordExportCSV)
+		if !service.ValidWordExportFormat(format) {
+			middleware.AbortInvalidInput(c, "format must be csv or ndjson")
 			return
 		}
 
-		if err := s.DeleteWord(uint(id)); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
-				return
+		filename := "words-" + time.Now().UTC().Format("20060102") + "." + format
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Header("Content-Type", wordExportContentTypes[format])
+		c.Status(http.StatusOK)
+		if err := s.ExportWords(c.Request.Context(), format, c.Writer); err != nil {
+			if !c.Writer.Written() {
+				// Nothing was sent yet, so the error replaces the download
+				c.Writer.Header().Del("Content-Disposition")
+				c.Writer.Header().Del("Content-Type")
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+			c.Error(err)
 		}
-
-		c.Status(http.StatusNoContent)
 	}
 }
 
-func GetWordsByGroup(s *service.WordService) gin.HandlerFunc {
+// ImportWords imports a word list sent as the request body. With dry_run it
+// returns a preview including a confirmation token, which must be passed as
+// confirm to run the import.
+func ImportWords(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			middleware.AbortInvalidInput(c, "Invalid dry_run value")
 			return
 		}
 
-		ginParams := middleware.GetPaginationParams(c)
-		serviceParams := service.PaginationParams{
-			Page:     ginParams.Page,
-			PageSize: ginParams.PageSize,
+		opts := importer.Options{Format: c.Query("format")}
+		if raw := c.Query("columns"); raw != "" {
+			for _, column := range strings.Split(raw, ",") {
+				opts.Columns = append(opts.Columns, strings.ToLower(strings.TrimSpace(column)))
+			}
+		}
+		if raw := c.Query("header"); raw != "" {
+			header, err := strconv.ParseBool(raw)
+			if err != nil {
+				middleware.AbortInvalidInput(c, "Invalid header value")
+				return
+			}
+			opts.Header = &header
 		}
 
-		servicePaginatedResult, err := s.GetWordsByGroup(uint(groupID), serviceParams)
+		// The size of the list is capped by BodyLimits.Import
+		data, err := io.ReadAll(c.Request.Body)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			middleware.AbortInvalidInput(c, "Failed to read the word list")
 			return
 		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
-		}
-
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
-		c.JSON(http.StatusOK, response)
-	}
-}
-
-// Group Handlers
-
-// GetGroupWordsRaw returns a simplified list of words in a group (id, japanese, romaji, english only)
-func GetGroupWordsRaw(s *service.GroupService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		if dryRun {
+			preview, err := s.PreviewWordImport(c.Request.Context(), data, opts, c.Query("group"))
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, preview)
 			return
 		}
 
-		// Get words from the group
-		words, err := s.GetWordsRaw(uint(groupID))
+		result, err := s.ImportWords(c.Request.Context(), data, opts, c.Query("group"), c.Query("confirm"), middleware.Actor(c))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch group words"})
+			c.Error(err)
 			return
 		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"items": words,
-		})
+		c.JSON(http.StatusOK, result)
 	}
 }
 
-func CreateGroup(s *service.GroupService) gin.HandlerFunc {
+func GetWord(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var group models.Group
-		if err := c.ShouldBindJSON(&group); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
-
-		if err := s.CreateGroup(&group); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeInvalidInput {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		lang, ok := meaningLanguage(c)
+		if !ok {
 			return
 		}
 
-		c.JSON(http.StatusCreated, group)
-	}
-}
-
-func GetGroup(s *service.GroupService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		word, err := s.GetWord(c.Request.Context(), uint(id))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			c.Error(err)
 			return
 		}
+		if lang != "" {
+			word.Translate(lang)
+		}
 
-		group, err := s.GetGroup(uint(id))
-		if err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		etag := middleware.WeakETag(word.UpdatedAt, word.ID, word.StudyStats.CorrectCount, word.StudyStats.WrongCount, len(word.Groups), lang)
+		if middleware.CheckNotModified(c, etag) {
 			return
 		}
 
-		c.JSON(http.StatusOK, group)
+		c.JSON(http.StatusOK, word)
 	}
 }
 
-func ListGroups(s *service.GroupService) gin.HandlerFunc {
+func ListWords(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ginParams := middleware.GetPaginationParams(c)
 		serviceParams := service.PaginationParams{
 			Page:     ginParams.Page,
 			PageSize: ginParams.PageSize,
 		}
+		lang, ok := meaningLanguage(c)
+		if !ok {
+			return
+		}
 
-		servicePaginatedResult, err := s.ListGroups(serviceParams)
+		servicePaginatedResult, err := s.ListWords(c.Request.Context(), serviceParams, wordListOptionsFromQuery(c))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
+		if lang != "" {
+			for i := range servicePaginatedResult.Items {
+				servicePaginatedResult.Items[i].Translate(lang)
+			}
+		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
+		etag := listETag(servicePaginatedResult.Items, func(item service.Word) time.Time { return item.UpdatedAt }, servicePaginatedResult.TotalItems, ginParams)
+		if middleware.CheckNotModified(c, etag) {
+			return
 		}
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
 		c.JSON(http.StatusOK, response)
 	}
 }
 
-func UpdateGroup(s *service.GroupService) gin.HandlerFunc {
+// ListPartsOfSpeech returns the parts of speech words can be tagged with
+func ListPartsOfSpeech(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		parts, err := s.ListPartsOfSpeech(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
-			return
-		}
-
-		var group models.Group
-		if err := c.ShouldBindJSON(&group); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-
-		if err := s.UpdateGroup(uint(id), &group); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
-				return
-			}
-			if err.(*service.ServiceError).Code == service.ErrCodeInvalidInput {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 
-		c.Status(http.StatusNoContent)
+		c.JSON(http.StatusOK, gin.H{"items": parts})
 	}
 }
 
-func DeleteGroup(s *service.GroupService) gin.HandlerFunc {
+const (
+	defaultDueWordsLimit = 20
+	maxDueWordsLimit     = 100
+)
+
+// GetDueWords returns words whose next review is due
+func GetDueWords(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
-			return
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultDueWordsLimit)))
+		if err != nil || limit < 1 {
+			limit = defaultDueWordsLimit
+		}
+		if limit > maxDueWordsLimit {
+			limit = maxDueWordsLimit
 		}
 
-		if err := s.DeleteGroup(uint(id)); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		words, err := s.GetDueWords(c.Request.Context(), limit)
+		if err != nil {
+			c.Error(err)
 			return
 		}
 
-		c.Status(http.StatusNoContent)
+		c.JSON(http.StatusOK, gin.H{"items": words})
 	}
 }
 
-func AddWordToGroup(s *service.GroupService) gin.HandlerFunc {
+func UpdateWord(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		wordID, err := strconv.ParseUint(c.Param("word_id"), 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+		var word models.Word
+		if err := c.ShouldBindJSON(&word); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+		if !bindIfMatch(c, &word.Version) {
 			return
 		}
 
-		if err := s.AddWordToGroup(uint(groupID), uint(wordID)); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Group or word not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err := s.UpdateWord(c.Request.Context(), uint(id), &word); err != nil {
+			c.Error(err)
 			return
 		}
 
@@ -359,65 +382,56 @@ func AddWordToGroup(s *service.GroupService) gin.HandlerFunc {
 	}
 }
 
-func RemoveWordFromGroup(s *service.GroupService) gin.HandlerFunc {
+// PatchWord updates only the fields present in the request body
+func PatchWord(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		wordID, err := strconv.ParseUint(c.Param("word_id"), 10, 32)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+		var patch service.WordPatch
+		if err := c.ShouldBindJSON(&patch); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+		if !bindIfMatch(c, &patch.Version) {
 			return
 		}
 
-		if err := s.RemoveWordFromGroup(uint(groupID), uint(wordID)); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Group or word not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		word, err := s.PatchWord(c.Request.Context(), uint(id), &patch)
+		if err != nil {
+			c.Error(err)
 			return
 		}
 
-		c.Status(http.StatusNoContent)
+		c.JSON(http.StatusOK, word)
 	}
 }
 
-func GetGroupStudyStats(s *service.GroupService) gin.HandlerFunc {
+func DeleteWord(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		totalSessions, totalReviews, correctReviews, err := s.GetGroupStudyStats(uint(id))
-		if err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err := s.DeleteWord(c.Request.Context(), uint(id), middleware.Actor(c)); err != nil {
+			c.Error(err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"total_sessions":  totalSessions,
-			"total_reviews":   totalReviews,
-			"correct_reviews": correctReviews,
-			"success_rate":    calculateSuccessRate(int64(totalReviews), int64(correctReviews)),
-		})
+		c.Status(http.StatusNoContent)
 	}
 }
 
-func GetGroupsByWord(s *service.GroupService) gin.HandlerFunc {
+func GetWordsByGroup(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		wordID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid word ID"})
+			middleware.AbortInvalidInput(c, "Invalid group ID")
 			return
 		}
 
@@ -427,321 +441,509 @@ func GetGroupsByWord(s *service.GroupService) gin.HandlerFunc {
 			PageSize: ginParams.PageSize,
 		}
 
-		servicePaginatedResult, err := s.GetGroupsByWord(uint(wordID), serviceParams)
+		servicePaginatedResult, err := s.GetWordsByGroup(c.Request.Context(), uint(groupID), serviceParams, wordListOptionsFromQuery(c))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
+		etag := listETag(servicePaginatedResult.Items, func(item service.Word) time.Time { return item.UpdatedAt }, servicePaginatedResult.TotalItems, ginParams)
+		if middleware.CheckNotModified(c, etag) {
+			return
 		}
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
 		c.JSON(http.StatusOK, response)
 	}
 }
 
-// Study Handlers
-
-func CreateStudyActivity(s *service.StudyService) gin.HandlerFunc {
+// ExplainWord asks the language model to explain how a word is used, at an
+// optional level
+func ExplainWord(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var activity models.StudyActivity
-		if err := c.ShouldBindJSON(&activity); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		if err := s.CreateStudyActivity(&activity); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		explanation, err := s.ExplainWord(c.Request.Context(), middleware.Client(c), uint(id), c.Query("level"))
+		if err != nil {
+			c.Error(err)
 			return
 		}
-
-		c.JSON(http.StatusCreated, activity)
+		c.JSON(http.StatusOK, explanation)
 	}
 }
 
-func GetStudyActivity(s *service.StudyService) gin.HandlerFunc {
+// Example Sentence Handlers
+
+func ListExampleSentences(s *service.SentenceService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity ID"})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		activity, err := s.GetStudyActivity(uint(id))
+		sentences, err := s.ListExampleSentences(c.Request.Context(), uint(id))
 		if err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Study activity not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
-
-		c.JSON(http.StatusOK, activity)
+		c.JSON(http.StatusOK, gin.H{"items": sentences})
 	}
 }
 
-func ListStudyActivities(s *service.StudyService) gin.HandlerFunc {
+func AddExampleSentence(s *service.SentenceService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ginParams := middleware.GetPaginationParams(c)
-		serviceParams := service.PaginationParams{
-			Page:     ginParams.Page,
-			PageSize: ginParams.PageSize,
-		}
-
-		servicePaginatedResult, err := s.ListStudyActivities(serviceParams)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
+		var req struct {
+			Japanese string `json:"japanese" binding:"required"`
+			English  string `json:"english"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
 		}
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
-		c.JSON(http.StatusOK, response)
+		sentence, err := s.AddExampleSentence(c.Request.Context(), uint(id), req.Japanese, req.English)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusCreated, sentence)
 	}
 }
 
-func CreateStudySession(s *service.StudyService) gin.HandlerFunc {
+func DeleteExampleSentence(s *service.SentenceService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var session models.StudySession
-		if err := c.ShouldBindJSON(&session); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
-
-		if err := s.CreateStudySession(&session); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Group or activity not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		sentenceID, err := strconv.ParseUint(c.Param("sentence_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid sentence ID")
 			return
 		}
 
-		c.JSON(http.StatusCreated, session)
+		if err := s.DeleteExampleSentence(c.Request.Context(), uint(id), uint(sentenceID), middleware.Actor(c)); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
 	}
 }
 
-func GetStudySession(s *service.StudyService) gin.HandlerFunc {
+// GetRelatedWords returns the words closest in meaning to a word
+func GetRelatedWords(s *service.WordService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultRelatedWords)))
+		if err != nil || limit < 1 {
+			limit = service.DefaultRelatedWords
+		}
 
-		session, err := s.GetStudySession(uint(id))
+		related, err := s.GetRelatedWords(c.Request.Context(), uint(id), limit)
 		if err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 
-		c.JSON(http.StatusOK, session)
+		c.JSON(http.StatusOK, gin.H{"items": related})
 	}
 }
 
-func ListStudySessions(s *service.StudyService) gin.HandlerFunc {
+// GetWordAudio returns the recording of a word as MP3
+func GetWordAudio(s *service.AudioService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ginParams := middleware.GetPaginationParams(c)
-
-		serviceParams := service.PaginationParams{
-			Page:     ginParams.Page,
-			PageSize: ginParams.PageSize,
-		}
-
-		servicePaginatedResult, err := s.ListStudySessions(serviceParams)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list study sessions: " + err.Error()})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
+		audio, err := s.GetWordAudio(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
 		}
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
-		c.JSON(http.StatusOK, response)
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Data(http.StatusOK, service.AudioContentType, audio)
 	}
 }
 
-func GetStudySessionsByGroup(s *service.StudyService) gin.HandlerFunc {
+// RenderWord returns a word and its example sentences rendered in a format,
+// ruby by default: HTML with the readings of kanji in <ruby> annotations
+func RenderWord(s *service.SentenceService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 32)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			middleware.AbortInvalidInput(c, "Invalid word ID")
 			return
 		}
 
-		ginParams := middleware.GetPaginationParams(c)
-		serviceParams := service.PaginationParams{
-			Page:     ginParams.Page,
-			PageSize: ginParams.PageSize,
+		rendered, err := s.RenderWord(c.Request.Context(), uint(id), c.DefaultQuery("format", service.RenderRuby))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, rendered)
+	}
+}
+
+// GenerateExampleSentence asks the language model for an example sentence for
+// a word, at an optional level. The sentence is not stored.
+func GenerateExampleSentence(s *service.SentenceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
+			return
 		}
 
-		servicePaginatedResult, err := s.GetStudySessionsByGroup(uint(groupID), serviceParams)
+		sentence, err := s.GenerateExampleSentence(c.Request.Context(), middleware.Client(c), uint(id), c.Query("level"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, sentence)
+	}
+}
+
+// Group Handlers
+
+// GetGroupWordsRaw returns a simplified list of words in a group (id, japanese, romaji, english only)
+func GetGroupWordsRaw(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
 			return
 		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
+		// Get words from the group
+		words, err := s.GetWordsRaw(c.Request.Context(), uint(groupID))
+		if err != nil {
+			c.Error(err)
+			return
 		}
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
-		c.JSON(http.StatusOK, response)
+		c.JSON(http.StatusOK, gin.H{
+			"items": words,
+		})
 	}
 }
 
-func GetStudySessionsByActivity(s *service.StudyService) gin.HandlerFunc {
+func CreateGroup(s *service.GroupService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		activityID, err := strconv.ParseUint(c.Param("activity_id"), 10, 32)
+		var group models.Group
+		if err := c.ShouldBindJSON(&group); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		if err := s.CreateGroup(c.Request.Context(), &group); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, group)
+	}
+}
+
+// SuggestGroups proposes groups derived from review behavior
+func SuggestGroups(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		suggestions, err := s.SuggestGroups(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+	}
+}
+
+// CreateGroupFromSuggestion materializes a suggestion returned by SuggestGroups as a group
+func CreateGroupFromSuggestion(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Key  string `json:"key" binding:"required"`
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		group, err := s.CreateGroupFromSuggestion(c.Request.Context(), req.Key, req.Name)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, group)
+	}
+}
+
+func GetGroup(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		group, err := s.GetGroup(c.Request.Context(), uint(id))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity ID"})
+			c.Error(err)
+			return
+		}
+
+		if middleware.CheckNotModified(c, middleware.WeakETag(group.UpdatedAt, group.ID, group.WordCount)) {
 			return
 		}
 
+		c.JSON(http.StatusOK, group)
+	}
+}
+
+func ListGroups(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		ginParams := middleware.GetPaginationParams(c)
 		serviceParams := service.PaginationParams{
 			Page:     ginParams.Page,
 			PageSize: ginParams.PageSize,
 		}
 
-		servicePaginatedResult, err := s.GetStudySessionsByActivity(uint(activityID), serviceParams)
+		servicePaginatedResult, err := s.ListGroups(c.Request.Context(), serviceParams, service.GroupListOptions{
+			Sort:     sortFromQuery(c),
+			Language: middleware.GetQueryParams(c).Filters["language"],
+		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
+		etag := listETag(servicePaginatedResult.Items, func(item service.Group) time.Time { return item.UpdatedAt }, servicePaginatedResult.TotalItems, ginParams)
+		if middleware.CheckNotModified(c, etag) {
+			return
 		}
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
 		c.JSON(http.StatusOK, response)
 	}
 }
 
-func AddWordReview(s *service.StudyService) gin.HandlerFunc {
+func UpdateGroup(s *service.GroupService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+			middleware.AbortInvalidInput(c, "Invalid group ID")
 			return
 		}
 
-		var review models.WordReview
-		if err := c.ShouldBindJSON(&review); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		var group models.Group
+		if err := c.ShouldBindJSON(&group); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+		if !bindIfMatch(c, &group.Version) {
 			return
 		}
 
-		if err := s.AddWordReview(uint(sessionID), &review); err != nil {
-			if err.(*service.ServiceError).Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Session or word not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err := s.UpdateGroup(c.Request.Context(), uint(id), &group); err != nil {
+			c.Error(err)
 			return
 		}
 
-		c.JSON(http.StatusCreated, review)
+		c.Status(http.StatusNoContent)
 	}
 }
 
-func GetWordReviewsBySession(s *service.StudyService) gin.HandlerFunc {
+// PatchGroup updates only the fields present in the request body
+func PatchGroup(s *service.GroupService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+			middleware.AbortInvalidInput(c, "Invalid group ID")
 			return
 		}
 
-		ginParams := middleware.GetPaginationParams(c)
-		serviceParams := service.PaginationParams{
-			Page:     ginParams.Page,
-			PageSize: ginParams.PageSize,
+		var patch service.GroupPatch
+		if err := c.ShouldBindJSON(&patch); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+		if !bindIfMatch(c, &patch.Version) {
+			return
+		}
+
+		group, err := s.PatchGroup(c.Request.Context(), uint(id), &patch)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, group)
+	}
+}
+
+func DeleteGroup(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
 		}
 
-		servicePaginatedResult, err := s.GetWordReviewsBySession(uint(sessionID), serviceParams)
+		if err := s.DeleteGroup(c.Request.Context(), uint(id), middleware.Actor(c)); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// PublishGroup publishes a group as a shared deck, under the slug given in the
+// optional body or a random one
+func PublishGroup(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			if serr, ok := err.(*service.ServiceError); ok && serr.Code == service.ErrCodeNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Study session not found"})
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+		var input struct {
+			Slug string `json:"slug"`
+		}
+		// The body is optional
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				middleware.AbortInvalidBody(c, err)
 				return
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+
+		group, err := s.PublishGroup(c.Request.Context(), uint(id), input.Slug, middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, group)
+	}
+}
+
+// UnpublishGroup makes a shared deck private again
+func UnpublishGroup(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
 			return
 		}
 
-		interfaceItems := make([]interface{}, len(servicePaginatedResult.Items))
-		for i, item := range servicePaginatedResult.Items {
-			interfaceItems[i] = item
+		if err := s.UnpublishGroup(c.Request.Context(), uint(id), middleware.Actor(c)); err != nil {
+			c.Error(err)
+			return
 		}
 
-		response := middleware.NewPaginatedResponse(interfaceItems, int(servicePaginatedResult.TotalItems), ginParams)
-		c.JSON(http.StatusOK, response)
+		c.Status(http.StatusNoContent)
 	}
 }
 
-func GetStudyStats(s *service.StudyService) gin.HandlerFunc {
+// GetSharedGroup returns a shared deck with its words
+func GetSharedGroup(s *service.GroupService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		totalSessions, totalReviews, correctReviews, err := s.GetStudyStats()
+		group, err := s.GetSharedGroup(c.Request.Context(), c.Param("slug"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"total_sessions":  totalSessions,
-			"total_reviews":   totalReviews,
-			"correct_reviews": correctReviews,
-			"success_rate":    calculateSuccessRate(totalReviews, correctReviews),
-		})
+		c.JSON(http.StatusOK, group)
 	}
 }
 
-func GetStudyStreak(s *service.StudyService) gin.HandlerFunc {
+// CopySharedGroup creates a group with the words of a shared deck, named as
+// given in the optional body or after the deck
+func CopySharedGroup(s *service.GroupService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		streak, err := s.GetStudyStreak()
+		var input struct {
+			Name string `json:"name"`
+		}
+		// The body is optional
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				middleware.AbortInvalidBody(c, err)
+				return
+			}
+		}
+
+		group, err := s.CopySharedGroup(c.Request.Context(), c.Param("slug"), input.Name)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.Error(err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"streak_days": streak})
+		c.JSON(http.StatusCreated, group)
 	}
 }
 
-func GetActiveGroups(s *service.StudyService) gin.HandlerFunc {
+func AddWordToGroup(s *service.GroupService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		count, err := s.GetActiveGroups()
+		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			middleware.AbortInvalidInput(c, "Invalid group ID")
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"active_groups": count})
+		wordID, err := strconv.ParseUint(c.Param("word_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
+			return
+		}
+
+		if err := s.AddWordToGroup(c.Request.Context(), uint(groupID), uint(wordID)); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
 	}
 }
 
-func ResetStudyHistory(s *service.StudyService) gin.HandlerFunc {
+func RemoveWordFromGroup(s *service.GroupService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if err := s.ResetStudyHistory(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		wordID, err := strconv.ParseUint(c.Param("word_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
+			return
+		}
+
+		if err := s.RemoveWordFromGroup(c.Request.Context(), uint(groupID), uint(wordID), middleware.Actor(c)); err != nil {
+			c.Error(err)
 			return
 		}
 
@@ -749,11 +951,2089 @@ func ResetStudyHistory(s *service.StudyService) gin.HandlerFunc {
 	}
 }
 
-// Helper functions
+func GetGroupStudyStats(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
 
-func calculateSuccessRate(total, correct int64) float64 {
-	if total == 0 {
-		return 0
+		totalSessions, totalReviews, correctReviews, err := s.GetGroupStudyStats(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		stats := gin.H{
+			"total_sessions":  totalSessions,
+			"total_reviews":   totalReviews,
+			"correct_reviews": correctReviews,
+			"success_rate":    calculateSuccessRate(int64(totalReviews), int64(correctReviews)),
+		}
+
+		// include=words adds the per-word breakdown
+		if c.Query("include") == "words" {
+			words, err := s.GetGroupWordStats(c.Request.Context(), uint(id))
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			stats["words"] = words
+		}
+
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
+func GetGroupsByWord(s *service.GroupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		wordID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
+			return
+		}
+
+		ginParams := middleware.GetPaginationParams(c)
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.GetGroupsByWord(c.Request.Context(), uint(wordID), serviceParams)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		etag := listETag(servicePaginatedResult.Items, func(item service.Group) time.Time { return item.UpdatedAt }, servicePaginatedResult.TotalItems, ginParams)
+		if middleware.CheckNotModified(c, etag) {
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// Kanji Handlers
+
+// ListKanji returns the kanji found in words with their study progress
+func ListKanji(s *service.KanjiService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := middleware.GetQueryParams(c)
+		jlpt, err := query.Filters.Int("jlpt", 0)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid jlpt value")
+			return
+		}
+
+		ginParams := query.PaginationParams
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.ListKanji(c.Request.Context(), serviceParams, service.KanjiListOptions{
+			JLPTLevel: jlpt,
+			Sort:      sortFromQuery(c),
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetKanji returns a kanji with its study progress
+func GetKanji(s *service.KanjiService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kanji, err := s.GetKanji(c.Request.Context(), c.Param("character"))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, kanji)
+	}
+}
+
+// UpdateKanji replaces the dictionary data of a kanji
+func UpdateKanji(s *service.KanjiService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var update service.KanjiUpdate
+		if err := c.ShouldBindJSON(&update); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		kanji, err := s.UpdateKanji(c.Request.Context(), c.Param("character"), &update)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, kanji)
+	}
+}
+
+// GetKanjiStrokes returns the KanjiVG stroke order SVG of a kanji
+func GetKanjiStrokes(s *service.KanjiService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		svg, err := s.GetKanjiStrokes(c.Request.Context(), c.Param("character"))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		// The file comes from a third party: never let it run scripts on this origin
+		c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Data(http.StatusOK, "image/svg+xml", svg)
+	}
+}
+
+// GetKanjiWords returns the words containing a kanji
+func GetKanjiWords(s *service.KanjiService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ginParams := middleware.GetPaginationParams(c)
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.GetKanjiWords(c.Request.Context(), c.Param("character"), serviceParams)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// Study Handlers
+
+func CreateStudyActivity(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var activity models.StudyActivity
+		if err := c.ShouldBindJSON(&activity); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		if err := s.CreateStudyActivity(c.Request.Context(), &activity); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, activity)
+	}
+}
+
+// ExportActivityCatalog returns the study activity catalog for import into another instance
+func ExportActivityCatalog(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		catalog, err := s.ExportActivityCatalog(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, catalog)
+	}
+}
+
+// ImportActivityCatalog creates or updates study activities from an exported catalog
+func ImportActivityCatalog(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var catalog service.ActivityCatalog
+		if err := c.ShouldBindJSON(&catalog); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		result, err := s.ImportActivityCatalog(c.Request.Context(), catalog, middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// RegisterStudyActivity registers an external study app, or updates its registration
+func RegisterStudyActivity(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reg service.ActivityRegistration
+		if err := c.ShouldBindJSON(&reg); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		activity, err := s.RegisterStudyActivity(c.Request.Context(), reg, middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		status := http.StatusOK
+		if activity.Created {
+			status = http.StatusCreated
+		}
+		c.JSON(status, activity)
+	}
+}
+
+// CheckAnswer grades a typed answer for a word without recording a review
+func CheckAnswer(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var check service.AnswerCheck
+		if err := c.ShouldBindJSON(&check); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+		if check.WordID == 0 {
+			middleware.AbortInvalidInput(c, "word_id is required")
+			return
+		}
+
+		result, err := s.CheckAnswer(c.Request.Context(), check)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// CheckPronunciation scores a recording of a word, sent as the request body,
+// and records it as a review in the given session or a new speaking session
+func CheckPronunciation(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		check := service.PronunciationCheck{
+			ContentType: c.ContentType(),
+			Client:      middleware.ClientInfo(c, ""),
+			Learner:     middleware.Actor(c),
+		}
+		for _, param := range []struct {
+			name string
+			id   *uint
+		}{{"word_id", &check.WordID}, {"session_id", &check.SessionID}, {"group_id", &check.GroupID}} {
+			raw := c.Query(param.name)
+			if raw == "" {
+				continue
+			}
+			value, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				middleware.AbortInvalidInput(c, "Invalid "+param.name)
+				return
+			}
+			*param.id = uint(value)
+		}
+		if check.WordID == 0 {
+			middleware.AbortInvalidInput(c, "word_id is required")
+			return
+		}
+
+		// The size of the recording is capped by BodyLimits.Recording
+		audio, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Failed to read the recording")
+			return
+		}
+
+		result, err := s.CheckPronunciation(c.Request.Context(), check, audio)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// GetClozeQuestions returns fill-in-the-blank questions made from the example
+// sentences of a group's words
+func GetClozeQuestions(s *service.SentenceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		groupID, err := strconv.ParseUint(c.Query("group_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultClozeQuestions)))
+		if err != nil || limit < 1 {
+			limit = service.DefaultClozeQuestions
+		}
+
+		set, err := s.GenerateCloze(c.Request.Context(), uint(groupID), limit)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, set)
+	}
+}
+
+// GetListeningQuiz returns multiple-choice listening questions for the words
+// of a group that have audio
+func GetListeningQuiz(s *service.AudioService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		groupID, err := strconv.ParseUint(c.Query("group_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultListeningQuestions)))
+		if err != nil || limit < 1 {
+			limit = service.DefaultListeningQuestions
+		}
+
+		quiz, err := s.GenerateListeningQuiz(c.Request.Context(), uint(groupID), limit)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, quiz)
+	}
+}
+
+// ListCounters returns the counter words that can be drilled, with the
+// answers given to each
+func ListCounters(s *service.CounterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		counters, err := s.ListCounters(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": counters})
+	}
+}
+
+// GetCounterDrill generates number and counter reading questions, for the
+// counters given as a comma-separated list or all of them
+func GetCounterDrill(s *service.CounterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var symbols []string
+		for _, symbol := range strings.Split(c.Query("counters"), ",") {
+			if symbol = strings.TrimSpace(symbol); symbol != "" {
+				symbols = append(symbols, symbol)
+			}
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(service.DefaultCounterQuestions)))
+		if err != nil || limit < 1 {
+			limit = service.DefaultCounterQuestions
+		}
+		maxNumber, err := strconv.Atoi(c.DefaultQuery("max_number", strconv.Itoa(service.DefaultCounterMaxNumber)))
+		if err != nil || maxNumber < 1 {
+			middleware.AbortInvalidInput(c, "Invalid max_number")
+			return
+		}
+
+		drill, err := s.GenerateCounterDrill(c.Request.Context(), symbols, limit, maxNumber)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, drill)
+	}
+}
+
+// ReviewCounter checks and records an answer to a counter question
+func ReviewCounter(s *service.CounterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Counter      string `json:"counter" binding:"required"`
+			Number       int    `json:"number" binding:"required"`
+			Answer       string `json:"answer" binding:"required"`
+			AnswerTimeMs *int   `json:"answer_time_ms"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		result, err := s.ReviewCounter(c.Request.Context(), req.Counter, req.Number, req.Answer, req.AnswerTimeMs)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, result)
+	}
+}
+
+// LaunchStudyActivity starts a session of an external activity for a group and
+// returns the URL opening it, with a signed launch token
+func LaunchStudyActivity(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid activity ID")
+			return
+		}
+		groupID, err := strconv.ParseUint(c.Query("group_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		launch, err := s.LaunchStudyActivity(c.Request.Context(), uint(id), uint(groupID), middleware.Actor(c), middleware.ClientInfo(c, ""))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, launch)
+	}
+}
+
+// SubmitActivityReviews adds a batch of reviews from an external activity,
+// authenticated with the launch token sent as a bearer token
+func SubmitActivityReviews(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			middleware.AbortWithError(c, http.StatusUnauthorized, middleware.CodeUnauthorized, "Launch token required")
+			return
+		}
+
+		var batch service.ActivityReviewBatch
+		if err := c.ShouldBindJSON(&batch); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		result, err := s.SubmitActivityReviews(c.Request.Context(), strings.TrimSpace(token), batch)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, result)
+	}
+}
+
+func GetStudyActivity(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid activity ID")
+			return
+		}
+
+		activity, err := s.GetStudyActivity(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, activity)
+	}
+}
+
+func ListStudyActivities(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ginParams := middleware.GetPaginationParams(c)
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.ListStudyActivities(c.Request.Context(), serviceParams)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func CreateStudySession(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var session models.StudySession
+		if err := c.ShouldBindJSON(&session); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+		session.Client = middleware.ClientInfo(c, session.Client.DeviceLabel)
+		session.Learner = middleware.Actor(c)
+
+		if err := s.CreateStudySession(c.Request.Context(), &session); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, session)
+	}
+}
+
+// GetMistakes returns the recent mistakes queue
+func GetMistakes(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultMistakesDays)))
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid days value")
+			return
+		}
+
+		words, err := s.GetMistakes(c.Request.Context(), days)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": words, "days": days})
+	}
+}
+
+// GetReviewForecast returns the number of reviews coming due per day
+func GetReviewForecast(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(service.DefaultForecastDays)))
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid days value")
+			return
+		}
+
+		forecast, err := s.GetReviewForecast(c.Request.Context(), days)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, forecast)
+	}
+}
+
+// CreateMistakesSession starts a study session on the recent mistakes queue
+func CreateMistakesSession(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			StudyActivityID uint   `json:"study_activity_id" binding:"required"`
+			Days            int    `json:"days"`
+			ReviewOrder     string `json:"review_order"`
+			DeviceLabel     string `json:"device_label"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+		if req.Days == 0 {
+			req.Days = service.DefaultMistakesDays
+		}
+
+		result, err := s.CreateMistakesSession(c.Request.Context(), middleware.Actor(c), req.StudyActivityID, req.Days, req.ReviewOrder, middleware.ClientInfo(c, req.DeviceLabel))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, result)
+	}
+}
+
+func GetStudySession(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid session ID")
+			return
+		}
+
+		session, err := s.GetStudySession(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+// CompleteStudySession marks a study session complete and returns its summary
+// with the experience earned
+func CompleteStudySession(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid session ID")
+			return
+		}
+
+		summary, err := s.CompleteSession(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+// GetStudySessionBundle returns an offline bundle for a study session
+func GetStudySessionBundle(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid session ID")
+			return
+		}
+
+		bundle, err := s.GetSessionBundle(c.Request.Context(), uint(id), c.Query("order"))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, bundle)
+	}
+}
+
+// GetActiveStudySession returns the caller's open study session to resume
+func GetActiveStudySession(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		active, err := s.GetActiveSession(c.Request.Context(), middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, active)
+	}
+}
+
+// GetStudySessionQueue returns the ordered word queue of a study session
+func GetStudySessionQueue(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid session ID")
+			return
+		}
+
+		queue, err := s.GetSessionQueue(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, queue)
+	}
+}
+
+func ListStudySessions(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ginParams := middleware.GetPaginationParams(c)
+
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.ListStudySessions(c.Request.Context(), serviceParams)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func GetStudySessionsByGroup(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		ginParams := middleware.GetPaginationParams(c)
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.GetStudySessionsByGroup(c.Request.Context(), uint(groupID), serviceParams)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func GetStudySessionsByActivity(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		activityID, err := strconv.ParseUint(c.Param("activity_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid activity ID")
+			return
+		}
+
+		ginParams := middleware.GetPaginationParams(c)
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.GetStudySessionsByActivity(c.Request.Context(), uint(activityID), serviceParams)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func AddWordReview(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid session ID")
+			return
+		}
+
+		var review models.WordReview
+		if err := c.ShouldBindJSON(&review); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		if err := s.AddWordReview(c.Request.Context(), uint(sessionID), &review); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, review)
+	}
+}
+
+func GetWordReviewsBySession(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid session ID")
+			return
+		}
+
+		ginParams := middleware.GetPaginationParams(c)
+		serviceParams := service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}
+
+		servicePaginatedResult, err := s.GetWordReviewsBySession(c.Request.Context(), uint(sessionID), serviceParams)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		response := middleware.NewPaginatedResponse(servicePaginatedResult.Items, int(servicePaginatedResult.TotalItems), ginParams)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func GetStudyStats(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		totalSessions, totalReviews, correctReviews, err := s.GetStudyStats(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"total_sessions":  totalSessions,
+			"total_reviews":   totalReviews,
+			"correct_reviews": correctReviews,
+			"success_rate":    calculateSuccessRate(totalReviews, correctReviews),
+		})
+	}
+}
+
+func GetStudyStreak(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		streak, err := s.GetStudyStreak(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"streak_days": streak})
+	}
+}
+
+// GetLearnerLevel returns the caller's experience and level
+func GetLearnerLevel(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		level, err := s.GetLearnerLevel(c.Request.Context(), middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, level)
+	}
+}
+
+func GetActiveGroups(s *service.StudyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		count, err := s.GetActiveGroups(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"active_groups": count})
+	}
+}
+
+// ResetStudyHistory deletes all study sessions and reviews. With dry_run=true it
+// returns the reset plan instead; the real run needs the plan's token as confirm.
+func ResetStudyHistory(s *service.StudyService) gin.HandlerFunc {
+	return resetHandler(s.PlanStudyHistoryReset, s.ResetStudyHistory)
+}
+
+// FullReset deletes all words, groups and study history, with the same dry run
+// and confirmation as ResetStudyHistory
+func FullReset(s *service.StudyService) gin.HandlerFunc {
+	return resetHandler(s.PlanFullReset, s.FullReset)
+}
+
+func resetHandler(plan func(context.Context) (*service.ResetPlan, error), reset func(ctx context.Context, actor, confirm string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid dry_run value")
+			return
+		}
+
+		if dryRun {
+			result, err := plan(c.Request.Context())
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, result)
+			return
+		}
+
+		if err := reset(c.Request.Context(), middleware.Actor(c), c.Query("confirm")); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Helper functions
+
+// bindIfMatch sets *version from the request's If-Match header, which takes
+// precedence over a version in the body. It writes a 400 response and returns
+// false for a malformed header.
+func bindIfMatch(c *gin.Context, version *uint) bool {
+	ifMatch, err := middleware.IfMatchVersion(c)
+	if err != nil {
+		middleware.AbortInvalidInput(c, err.Error())
+		return false
+	}
+	if ifMatch != 0 {
+		*version = ifMatch
+	}
+	return true
+}
+
+// listETag derives a weak ETag for a page of items from their most recent UpdatedAt
+// and the page contents, so counter changes (reviews, word counts) also invalidate it
+func listETag[T any](items []T, updatedAt func(T) time.Time, totalItems int64, params middleware.PaginationParams) string {
+	var latest time.Time
+	values := []interface{}{totalItems, params.Page, params.PageSize}
+	for _, item := range items {
+		if t := updatedAt(item); t.After(latest) {
+			latest = t
+		}
+		values = append(values, item)
+	}
+	return middleware.WeakETag(latest, values...)
+}
+
+func calculateSuccessRate(total, correct int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(correct) / float64(total) * 100
+}
+
+// Search Handlers
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// Search returns words, groups and activities matching the q query parameter.
+// The optional types parameter is a comma-separated list of result types.
+func Search(s *service.SearchService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSearchLimit)))
+		if err != nil || limit < 1 {
+			limit = defaultSearchLimit
+		}
+		if limit > maxSearchLimit {
+			limit = maxSearchLimit
+		}
+
+		var types []string
+		if raw := c.Query("types"); raw != "" {
+			for _, t := range strings.Split(raw, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					types = append(types, t)
+				}
+			}
+		}
+
+		results, err := s.Search(c.Request.Context(), c.Query("q"), types, limit)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": results})
+	}
+}
+
+// Dictionary Handlers
+
+const (
+	defaultDictionaryLimit = 10
+	maxDictionaryLimit     = 50
+)
+
+// LookupDictionary returns the dictionary entries matching the q query parameter
+func LookupDictionary(s *service.DictionaryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultDictionaryLimit)))
+		if err != nil || limit < 1 {
+			limit = defaultDictionaryLimit
+		}
+		if limit > maxDictionaryLimit {
+			limit = maxDictionaryLimit
+		}
+
+		entries, err := s.Lookup(c.Request.Context(), c.Query("q"), limit)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": entries})
+	}
+}
+
+// AddDictionaryWord creates a word from a dictionary entry returned by a lookup
+func AddDictionaryWord(s *service.DictionaryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Query   string `json:"query" binding:"required"`
+			EntryID string `json:"entry_id" binding:"required"`
+			Sense   int    `json:"sense"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		word, err := s.AddWord(c.Request.Context(), req.Query, req.EntryID, req.Sense)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, word)
+	}
+}
+
+// CaptureFromImage reads the Japanese words in an image, sent as the request
+// body, and returns them with their dictionary entries for confirmation
+func CaptureFromImage(s *service.DictionaryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The size of the image is capped by BodyLimits.Image
+		image, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Failed to read the image")
+			return
+		}
+
+		capture, err := s.CaptureFromImage(c.Request.Context(), image, c.ContentType())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, capture)
+	}
+}
+
+// AddCapturedWords adds confirmed words captured from an image to a group
+func AddCapturedWords(s *service.DictionaryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			GroupID uint                       `json:"group_id" binding:"required"`
+			Words   []service.CapturedWordPick `json:"words" binding:"required,dive"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		result, err := s.AddCapturedWords(c.Request.Context(), req.GroupID, req.Words)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Event Handlers
+
+// PollEvents long-polls for change events after the given cursor, as a fallback
+// for clients that cannot keep a realtime connection open. The optional wait
+// parameter, in seconds, shortens how long the request is held open.
+func PollEvents(s *service.EventService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var wait time.Duration
+		if raw := c.Query("wait"); raw != "" {
+			if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+				wait = time.Duration(seconds) * time.Second
+			}
+		}
+
+		batch, err := s.PollEvents(c.Request.Context(), c.Query("cursor"), wait)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, batch)
+	}
+}
+
+// Health Handlers
+
+// Liveness reports that the process is up; it checks no dependencies
+func Liveness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": service.HealthStatusOK})
+	}
+}
+
+// Readiness runs the dependency checks and answers 503 when a critical one fails
+func Readiness(s *service.HealthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := s.CheckReadiness(c.Request.Context())
+
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, report)
+	}
+}
+
+// Admin Handlers
+
+// ListRoutes returns the route table: every registered method and path with the
+// roles and rate limit classes that apply to it
+func ListRoutes(router *gin.Engine, policies *routePolicies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"routes": policies.describe(router)})
+	}
+}
+
+// API Key Handlers
+
+// ListAPIKeys returns every API key, revoked ones included, newest first
+func ListAPIKeys(s *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keys, err := s.ListAPIKeys(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"keys": keys})
+	}
+}
+
+// CreateAPIKey creates an API key. The key is only part of this response.
+func CreateAPIKey(s *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input service.NewAPIKey
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		key, err := s.CreateAPIKey(c.Request.Context(), input, middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusCreated, key)
+	}
+}
+
+// RevokeAPIKey revokes an API key
+func RevokeAPIKey(s *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid API key ID")
+			return
+		}
+
+		if err := s.RevokeAPIKey(c.Request.Context(), uint(id), middleware.Actor(c)); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Prompt Handlers
+
+// ListPrompts returns the prompts of the AI features in use
+func ListPrompts(s *service.PromptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prompts, err := s.ListPrompts(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"items": prompts})
+	}
+}
+
+// GetPrompt returns a prompt with its built-in body and versions
+func GetPrompt(s *service.PromptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prompt, err := s.GetPrompt(c.Request.Context(), c.Param("name"))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, prompt)
+	}
+}
+
+// UpdatePrompt stores a new version of a prompt
+func UpdatePrompt(s *service.PromptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Body string `json:"body" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		prompt, err := s.UpdatePrompt(c.Request.Context(), c.Param("name"), input.Body, middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, prompt)
+	}
+}
+
+// RestorePromptVersion makes an earlier version of a prompt current again;
+// version 0 restores the built-in prompt
+func RestorePromptVersion(s *service.PromptService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Version *int `json:"version" binding:"required,min=0"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		prompt, err := s.RestorePromptVersion(c.Request.Context(), c.Param("name"), *input.Version, middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, prompt)
+	}
+}
+
+// ListAIProviders returns the language model providers with their circuit
+// state and usage since the start
+func ListAIProviders(s *service.AIService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		providers, err := s.ListProviders(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"items": providers})
+	}
+}
+
+// GetAIUsage reports the usage of the AI features by user, feature and
+// provider, optionally for one user and an RFC 3339 since/until range
+func GetAIUsage(s *service.AIService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := middleware.GetQueryParams(c)
+		filter := service.AIUsageFilter{User: query.Filters["user"]}
+		for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+			t, err := query.Filters.Time(param)
+			if err != nil {
+				middleware.AbortInvalidInput(c, "Invalid "+param+" timestamp, expected RFC 3339")
+				return
+			}
+			*target = t
+		}
+
+		report, err := s.GetUsage(c.Request.Context(), filter)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// ListAuditEntries returns audit log entries, newest first. Entries can be
+// filtered by actor, action, entity_type, entity_id and an RFC 3339 since/until range.
+func ListAuditEntries(s *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := middleware.GetQueryParams(c)
+		filter := service.AuditFilter{
+			Actor:      query.Filters["actor"],
+			Action:     query.Filters["action"],
+			EntityType: query.Filters["entity_type"],
+		}
+
+		if raw := query.Filters["entity_id"]; raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				middleware.AbortInvalidInput(c, "Invalid entity ID")
+				return
+			}
+			entityID := uint(id)
+			filter.EntityID = &entityID
+		}
+
+		for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+			t, err := query.Filters.Time(param)
+			if err != nil {
+				middleware.AbortInvalidInput(c, "Invalid "+param+" timestamp, expected RFC 3339")
+				return
+			}
+			*target = t
+		}
+
+		ginParams := query.PaginationParams
+		result, err := s.ListAuditEntries(c.Request.Context(), filter, service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, middleware.NewPaginatedResponse(result.Items, int(result.TotalItems), ginParams))
+	}
+}
+
+// Share Handlers
+
+// CreateStatsShare creates an opt-in share token for the public stats widget
+func CreateStatsShare(s *service.ShareService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Stats []string `json:"stats" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		share, err := s.CreateShare(c.Request.Context(), input.Stats)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"token":      share.Token,
+			"stats":      share.Stats,
+			"url":        APIV1Prefix + "/public/stats/" + share.Token,
+			"created_at": share.CreatedAt,
+		})
+	}
+}
+
+// RevokeStatsShare deletes a share token
+func RevokeStatsShare(s *service.ShareService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.RevokeShare(c.Request.Context(), c.Param("token")); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetPublicStats returns the stats exposed by a share token. It is
+// unauthenticated and may be cached by browsers and proxies.
+func GetPublicStats(s *service.ShareService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := s.GetPublicStats(c.Request.Context(), c.Param("share_token"))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(service.PublicStatsCacheTTL.Seconds())))
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
+// Goal Handlers
+
+func GetGroupGoal(s *service.GoalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		status, err := s.GetGroupGoalStatus(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+func SetGroupGoal(s *service.GoalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		var req struct {
+			MinAccuracy float64 `json:"min_accuracy" binding:"required"`
+			Sessions    int     `json:"sessions" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		goal, err := s.SetGroupGoal(c.Request.Context(), uint(id), req.MinAccuracy, req.Sessions)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, goal)
+	}
+}
+
+func DeleteGroupGoal(s *service.GoalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		if err := s.DeleteGroupGoal(c.Request.Context(), uint(id), middleware.Actor(c)); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetGroupCertificate returns the group's latest certificate as JSON, or as a
+// PDF with format=pdf
+func GetGroupCertificate(s *service.GoalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "pdf" {
+			middleware.AbortInvalidInput(c, "format must be json or pdf")
+			return
+		}
+
+		certificate, err := s.GetGroupCertificate(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		if format == "pdf" {
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="certificate-%s.pdf"`, certificate.Token))
+			c.Data(http.StatusOK, "application/pdf", service.RenderCertificatePDF(certificate))
+			return
+		}
+		c.JSON(http.StatusOK, certificate)
+	}
+}
+
+// VerifyCertificate returns a shared certificate and whether its signature is valid
+func VerifyCertificate(s *service.GoalService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		certificate, valid, err := s.VerifyCertificate(c.Request.Context(), c.Param("token"))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"certificate": certificate, "valid": valid})
+	}
+}
+
+// Preferences Handlers
+
+func GetPreferences(s *service.PreferencesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prefs, err := s.GetPreferences(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, prefs)
+	}
+}
+
+func UpdatePreferences(s *service.PreferencesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var prefs service.Preferences
+		if err := c.ShouldBindJSON(&prefs); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		if err := s.UpdatePreferences(c.Request.Context(), &prefs); err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, prefs)
+	}
+}
+
+// Archive Handlers
+
+// ExportArchive returns the whole account as a JSON archive, or zipped with format=zip
+func ExportArchive(s *service.ArchiveService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "zip" {
+			middleware.AbortInvalidInput(c, "format must be json or zip")
+			return
+		}
+
+		archive, err := s.ExportArchive(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		filename := "lang-portal-" + archive.ExportedAt.Format("20060102") + "." + format
+		if format == "zip" {
+			var buf bytes.Buffer
+			if err := service.WriteArchiveZip(&buf, archive); err != nil {
+				c.Error(service.NewServiceError(service.ErrCodeInternal, "Failed to write archive", err))
+				return
+			}
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+			c.Data(http.StatusOK, "application/zip", buf.Bytes())
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.JSON(http.StatusOK, archive)
+	}
+}
+
+// RestoreArchive replaces the account's data with an archive sent as the
+// request body. With dry_run=true it returns the restore plan instead; the
+// real run needs the plan's token as confirm.
+func RestoreArchive(s *service.ArchiveService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid dry_run value")
+			return
+		}
+
+		// The size of the archive is capped by BodyLimits.Archive
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Failed to read the archive")
+			return
+		}
+
+		if dryRun {
+			plan, err := s.PlanArchiveRestore(c.Request.Context(), data)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, plan)
+			return
+		}
+
+		result, err := s.RestoreArchive(c.Request.Context(), data, c.Query("confirm"), middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Cohort Handlers
+
+// cohortID parses the cohort ID of the request, answering 400 when it is not one
+func cohortID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.AbortInvalidInput(c, "Invalid cohort ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// CreateCohort creates a cohort run by the caller
+func CreateCohort(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Name string `json:"name" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		cohort, err := s.CreateCohort(c.Request.Context(), middleware.Actor(c), input.Name)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusCreated, cohort)
+	}
+}
+
+// ListCohorts lists the cohorts the caller runs, newest first
+func ListCohorts(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cohorts, err := s.ListCohorts(c.Request.Context(), middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"items": cohorts})
+	}
+}
+
+// GetCohort returns one of the caller's cohorts with its members and groups
+func GetCohort(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+
+		cohort, err := s.GetCohort(c.Request.Context(), middleware.Actor(c), id)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, cohort)
+	}
+}
+
+// DeleteCohort deletes one of the caller's cohorts
+func DeleteCohort(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+
+		if err := s.DeleteCohort(c.Request.Context(), middleware.Actor(c), id); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// AddCohortMember adds a learner to one of the caller's cohorts
+func AddCohortMember(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+		var input struct {
+			Learner string `json:"learner" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		membership, err := s.AddMember(c.Request.Context(), middleware.Actor(c), id, input.Learner)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusCreated, membership)
+	}
+}
+
+// RemoveCohortMember removes a learner from one of the caller's cohorts
+func RemoveCohortMember(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+
+		if err := s.RemoveMember(c.Request.Context(), middleware.Actor(c), id, c.Param("learner")); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// AssignCohortGroup assigns a group to one of the caller's cohorts
+func AssignCohortGroup(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+		groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		if err := s.AssignGroup(c.Request.Context(), middleware.Actor(c), id, uint(groupID)); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// UnassignCohortGroup removes a group from one of the caller's cohorts
+func UnassignCohortGroup(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+		groupID, err := strconv.ParseUint(c.Param("group_id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid group ID")
+			return
+		}
+
+		if err := s.UnassignGroup(c.Request.Context(), middleware.Actor(c), id, uint(groupID)); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetCohortStats reports the study of the members of one of the caller's
+// cohorts, in total and per student
+func GetCohortStats(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+
+		stats, err := s.GetCohortStats(c.Request.Context(), middleware.Actor(c), id)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
+// GetCohortLeaderboard ranks a cohort's members for its teacher or a member by
+// the metric query parameter
+func GetCohortLeaderboard(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+
+		board, err := s.GetLeaderboard(c.Request.Context(), middleware.Actor(c), id, c.Query("metric"))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, board)
+	}
+}
+
+// UpdateCohortMembership sets how the caller appears on a cohort's leaderboard
+func UpdateCohortMembership(s *service.CohortService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := cohortID(c)
+		if !ok {
+			return
+		}
+		var input struct {
+			DisplayName       string `json:"display_name"`
+			LeaderboardOptOut bool   `json:"leaderboard_opt_out"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		membership, err := s.UpdateMembership(c.Request.Context(), middleware.Actor(c), id, input.DisplayName, input.LeaderboardOptOut)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, membership)
+	}
+}
+
+// Tutor Handlers
+
+// CreateTutorConversation starts a conversation with the tutor for the caller
+func CreateTutorConversation(s *service.TutorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Title string `json:"title"`
+		}
+		// The body is optional
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				middleware.AbortInvalidBody(c, err)
+				return
+			}
+		}
+
+		conversation, err := s.CreateConversation(c.Request.Context(), middleware.Actor(c), input.Title)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusCreated, conversation)
+	}
+}
+
+// ListTutorConversations lists the caller's conversations, most recently
+// active first
+func ListTutorConversations(s *service.TutorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ginParams := middleware.GetQueryParams(c).PaginationParams
+		result, err := s.ListConversations(c.Request.Context(), middleware.Actor(c), service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, middleware.NewPaginatedResponse(result.Items, int(result.TotalItems), ginParams))
+	}
+}
+
+// GetTutorConversation returns one of the caller's conversations with its messages
+func GetTutorConversation(s *service.TutorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid conversation ID")
+			return
+		}
+
+		conversation, err := s.GetConversation(c.Request.Context(), middleware.Actor(c), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, conversation)
+	}
+}
+
+// DeleteTutorConversation deletes one of the caller's conversations
+func DeleteTutorConversation(s *service.TutorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid conversation ID")
+			return
+		}
+
+		if err := s.DeleteConversation(c.Request.Context(), middleware.Actor(c), uint(id)); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// SendTutorMessage sends a message to the tutor. The reply is streamed as
+// server-sent events: "delta" events with the text as it is written, then a
+// "done" event with the stored reply, or an "error" event if the reply fails
+// part way. Errors before the reply starts are returned as usual. With
+// stream=false the reply is returned as JSON once complete.
+func SendTutorMessage(s *service.TutorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid conversation ID")
+			return
+		}
+		stream, err := strconv.ParseBool(c.DefaultQuery("stream", "true"))
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid stream value")
+			return
+		}
+		var input struct {
+			Content string `json:"content" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		ctx := c.Request.Context()
+		if !stream {
+			reply, err := s.SendMessage(ctx, middleware.Actor(c), middleware.Client(c), uint(id), input.Content, nil)
+			if err != nil {
+				c.Error(err)
+				return
+			}
+			c.JSON(http.StatusOK, reply)
+			return
+		}
+
+		started := false
+		onDelta := func(text string) error {
+			if !started {
+				started = true
+				// Replies take longer than the server's write timeout allows
+				// other responses; let them run until the request deadline
+				if deadline, ok := ctx.Deadline(); ok {
+					_ = http.NewResponseController(c.Writer).SetWriteDeadline(deadline)
+				}
+				c.Header("Cache-Control", "no-cache")
+				c.Header("X-Accel-Buffering", "no")
+			}
+			c.SSEvent("delta", gin.H{"text": text})
+			c.Writer.Flush()
+			return ctx.Err()
+		}
+
+		reply, err := s.SendMessage(ctx, middleware.Actor(c), middleware.Client(c), uint(id), input.Content, onDelta)
+		if err != nil {
+			c.Error(err)
+			if started {
+				// The body of the error response the request would have had
+				_, code, message, details := middleware.ErrorFor(err)
+				c.SSEvent("error", middleware.NewErrorResponse(c, code, message, details))
+				c.Writer.Flush()
+			}
+			return
+		}
+		c.SSEvent("done", reply)
+		c.Writer.Flush()
+	}
+}
+
+// Notification Handlers
+
+// notificationList is a page of the caller's inbox with their unread count
+type notificationList struct {
+	middleware.PaginatedResponse[models.Notification]
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// ListNotifications returns the caller's notifications, newest first, only the
+// unread ones with unread=true
+func ListNotifications(s *service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := middleware.GetQueryParams(c)
+		unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid unread flag")
+			return
+		}
+
+		ginParams := query.PaginationParams
+		page, err := s.ListNotifications(c.Request.Context(), middleware.Actor(c), unreadOnly, service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusOK, notificationList{
+			PaginatedResponse: middleware.NewPaginatedResponse(page.Items, int(page.TotalItems), ginParams),
+			UnreadCount:       page.UnreadCount,
+		})
+	}
+}
+
+// MarkNotificationRead marks one of the caller's notifications read
+func MarkNotificationRead(s *service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid notification ID")
+			return
+		}
+
+		notification, err := s.MarkRead(c.Request.Context(), middleware.Actor(c), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, notification)
+	}
+}
+
+// MarkAllNotificationsRead marks all of the caller's notifications read
+func MarkAllNotificationsRead(s *service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		marked, err := s.MarkAllRead(c.Request.Context(), middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"marked": marked})
+	}
+}
+
+// GetNotificationPreferences returns how the caller is reminded by email
+func GetNotificationPreferences(s *service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		preference, err := s.GetPreferences(c.Request.Context(), middleware.Actor(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, preference)
+	}
+}
+
+// UpdateNotificationPreferences sets the caller's reminder email address and
+// frequency
+func UpdateNotificationPreferences(s *service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Email          string `json:"email"`
+			EmailFrequency string `json:"email_frequency"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			middleware.AbortInvalidBody(c, err)
+			return
+		}
+
+		preference, err := s.UpdatePreferences(c.Request.Context(), middleware.Actor(c), input.Email, input.EmailFrequency)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, preference)
+	}
+}
+
+// UnsubscribeReminders turns off the reminder emails an unsubscribe link was
+// sent with. Mail clients post to it for one-click unsubscribing.
+func UnsubscribeReminders(s *service.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.Unsubscribe(c.Request.Context(), c.Param("token")); err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"unsubscribed": true})
+	}
+}
+
+// Tool Handlers
+
+// maxTransliterateLength is the longest text, in characters, Transliterate
+// accepts
+const maxTransliterateLength = 1000
+
+// Transliterate writes the kana and romaji of the text query parameter in the
+// script of the to query parameter: hiragana, katakana or romaji. Frontends
+// and import pipelines use it to normalize user input the way the server does.
+func Transliterate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		text, to := c.Query("text"), c.Query("to")
+		if strings.TrimSpace(text) == "" {
+			middleware.AbortInvalidInput(c, "text is required")
+			return
+		}
+		if utf8.RuneCountInString(text) > maxTransliterateLength {
+			middleware.AbortInvalidInput(c, fmt.Sprintf("text is limited to %d characters", maxTransliterateLength))
+			return
+		}
+		result, ok := jpn.Transliterate(text, to)
+		if !ok {
+			middleware.AbortInvalidInput(c, "to must be one of "+strings.Join(jpn.Scripts, ", "))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"text": text, "to": to, "result": result})
 	}
-	return float64(correct) / float64(total) * 100
 }
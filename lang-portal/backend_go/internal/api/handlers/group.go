@@ -1,386 +0,0 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
-
-	"lang-portal/backend_go/internal/api/middleware"
-	"lang-portal/backend_go/internal/models"
-)
-
-// GroupHandler handles word group-related requests
-type GroupHandler struct {
-	db *gorm.DB
-}
-
-// NewGroupHandler creates a new group handler
-func NewGroupHandler(db *gorm.DB) *GroupHandler {
-	return &GroupHandler{db: db}
-}
-
-// GetGroups returns a paginated list of word groups
-func (h *GroupHandler) GetGroups(c *gin.Context) {
-	params := middleware.GetPaginationParams(c)
-	offset := (params.Page - 1) * params.PageSize
-
-	var groups []models.Group
-	var total int64
-
-	// Get total count
-	if err := h.db.Model(&models.Group{}).Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count word groups"})
-		return
-	}
-
-	// Get paginated groups with word count
-	if err := h.db.Preload("Words").
-		Offset(offset).
-		Limit(params.PageSize).
-		Order("name ASC").
-		Find(&groups).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word groups"})
-		return
-	}
-
-	// Transform groups for response
-	items := make([]interface{}, len(groups))
-	for i, group := range groups {
-		items[i] = gin.H{
-			"id":         group.ID,
-			"name":       group.Name,
-			"word_count": len(group.Words),
-			"created_at": group.CreatedAt,
-		}
-	}
-
-	c.JSON(http.StatusOK, middleware.NewPaginatedResponse(items, int(total), params))
-}
-
-// GetGroup returns a single word group by ID
-func (h *GroupHandler) GetGroup(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
-		return
-	}
-
-	var group models.Group
-	if err := h.db.Preload("Words").First(&group, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Word group not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word group"})
-		return
-	}
-
-	c.JSON(http.StatusOK, group)
-}
-
-// CreateGroup creates a new word group
-func (h *GroupHandler) CreateGroup(c *gin.Context) {
-	var group models.Group
-	if err := c.ShouldBindJSON(&group); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Begin transaction
-	tx := h.db.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-
-	// Check if group with same name already exists
-	var existingGroup models.Group
-	if err := tx.Where("name = ?", group.Name).First(&existingGroup).Error; err == nil {
-		tx.Rollback()
-		c.JSON(http.StatusConflict, gin.H{"error": "A group with this name already exists"})
-		return
-	} else if err != gorm.ErrRecordNotFound {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for existing group"})
-		return
-	}
-
-	// Create the group
-	if err := tx.Create(&group).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create word group"})
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
-	}
-
-	c.JSON(http.StatusCreated, group)
-}
-
-// UpdateGroup updates an existing word group
-func (h *GroupHandler) UpdateGroup(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
-		return
-	}
-
-	// Begin transaction
-	tx := h.db.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-
-	var group models.Group
-	if err := tx.First(&group, id).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Word group not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word group"})
-		return
-	}
-
-	// Bind new data
-	if err := c.ShouldBindJSON(&group); err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Check if new name conflicts with existing group
-	var existingGroup models.Group
-	if err := tx.Where("name = ? AND id != ?", group.Name, id).First(&existingGroup).Error; err == nil {
-		tx.Rollback()
-		c.JSON(http.StatusConflict, gin.H{"error": "A group with this name already exists"})
-		return
-	} else if err != gorm.ErrRecordNotFound {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for existing group"})
-		return
-	}
-
-	// Update the group
-	if err := tx.Save(&group).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update word group"})
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
-	}
-
-	c.JSON(http.StatusOK, group)
-}
-
-// DeleteGroup deletes a word group
-func (h *GroupHandler) DeleteGroup(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
-		return
-	}
-
-	// Begin transaction
-	tx := h.db.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-
-	// Check if group exists
-	var group models.Group
-	if err := tx.First(&group, id).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Word group not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word group"})
-		return
-	}
-
-	// Delete the group (cascade will handle word associations)
-	if err := tx.Delete(&group).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete word group"})
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
-	}
-
-	c.Status(http.StatusNoContent)
-}
-
-// AddWordToGroup adds a word to a group
-func (h *GroupHandler) AddWordToGroup(c *gin.Context) {
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
-		return
-	}
-
-	var input struct {
-		WordID uint `json:"word_id" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Begin transaction
-	tx := h.db.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-
-	// Verify group exists
-	var group models.Group
-	if err := tx.First(&group, groupID).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Word group not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word group"})
-		return
-	}
-
-	// Verify word exists
-	var word models.Word
-	if err := tx.First(&word, input.WordID).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word"})
-		return
-	}
-
-	// Check if word is already in group
-	var count int64
-	err = tx.Model(&group).
-		Joins("JOIN word_groups ON word_groups.word_id = words.id").
-		Where("word_groups.group_id = ? AND words.id = ?", groupID, word.ID).
-		Count(&count).Error
-
-	if err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check word-group association"})
-		return
-	}
-
-	if count > 0 {
-		tx.Rollback()
-		c.JSON(http.StatusConflict, gin.H{"error": "Word is already in this group"})
-		return
-	}
-
-	// Add word to group
-	if err := tx.Model(&group).Association("Words").Append(&word); err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add word to group"})
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
-	}
-
-	c.Status(http.StatusNoContent)
-}
-
-// RemoveWordFromGroup removes a word from a group
-func (h *GroupHandler) RemoveWordFromGroup(c *gin.Context) {
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
-		return
-	}
-
-	var input struct {
-		WordID uint `json:"word_id" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Begin transaction
-	tx := h.db.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-
-	// Verify group exists
-	var group models.Group
-	if err := tx.First(&group, groupID).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Word group not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word group"})
-		return
-	}
-
-	// Verify word exists
-	var word models.Word
-	if err := tx.First(&word, input.WordID).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Word not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch word"})
-		return
-	}
-
-	// Check if word is in group
-	var existingWord models.Word
-	if err := tx.Model(&group).Association("Words").Find(&existingWord, input.WordID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check word-group association"})
-		return
-	}
-	if existingWord.ID == 0 {
-		tx.Rollback()
-		c.JSON(http.StatusNotFound, gin.H{"error": "Word is not in this group"})
-		return
-	}
-
-	// Remove word from group
-	if err := tx.Model(&group).Association("Words").Delete(&word); err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove word from group"})
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
-	}
-
-	c.Status(http.StatusNoContent)
-}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader names the request header identifying who performs a request.
+// Interactive clients are not authenticated yet, so the value is recorded as
+// given; it is ignored for requests made with an API key.
+const ActorHeader = "X-Actor"
+
+// maxActorLength bounds the actor name stored in the audit log
+const maxActorLength = 100
+
+// Prefixes of the actors the server derives itself, and of header values that
+// would pass for one
+const (
+	keyActorPrefix    = "key:"
+	ipActorPrefix     = "ip:"
+	headerActorPrefix = "header:"
+)
+
+// Actor returns the actor for the request: the name of its API key, the actor
+// header, or the client IP when neither is sent. A header value that looks like
+// a key or IP actor is prefixed with "header:", so that it cannot pass for one.
+func Actor(c *gin.Context) string {
+	if key := GetAPIKey(c); key != nil {
+		return truncateActor(keyActorPrefix + key.Name)
+	}
+	actor := strings.TrimSpace(c.GetHeader(ActorHeader))
+	if actor == "" {
+		return ipActorPrefix + c.ClientIP()
+	}
+	if strings.HasPrefix(actor, keyActorPrefix) || strings.HasPrefix(actor, ipActorPrefix) {
+		actor = headerActorPrefix + actor
+	}
+	return truncateActor(actor)
+}
+
+func truncateActor(actor string) string {
+	if len(actor) > maxActorLength {
+		actor = actor[:maxActorLength]
+	}
+	return actor
+}
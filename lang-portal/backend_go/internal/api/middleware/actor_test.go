@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	actor := func(header string, key *models.APIKey) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/words", nil)
+		c.Request.RemoteAddr = "192.0.2.1:1234"
+		if header != "" {
+			c.Request.Header.Set(ActorHeader, header)
+		}
+		if key != nil {
+			c.Set(apiKeyContextKey, key)
+		}
+		return Actor(c)
+	}
+
+	assert.Equal(t, "ip:192.0.2.1", actor("", nil))
+	assert.Equal(t, "ip:192.0.2.1", actor("   ", nil))
+	assert.Equal(t, "alice", actor(" alice ", nil))
+	assert.Equal(t, "key:import script", actor("alice", &models.APIKey{Name: "import script"}))
+	assert.Len(t, actor(strings.Repeat("a", 150), nil), maxActorLength)
+
+	// Header values cannot pass for the actors the server derives
+	assert.Equal(t, "header:key:admin", actor("key:admin", nil))
+	assert.Equal(t, "header:ip:10.0.0.1", actor("ip:10.0.0.1", nil))
+}
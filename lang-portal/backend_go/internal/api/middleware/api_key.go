@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries the API key of machine clients
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyContextKey stores the key that authenticated a request
+const apiKeyContextKey = "api_key"
+
+// APIKeyAuth authenticates requests sending an API key in the X-API-Key header
+// and checks that the key grants the scope of the request: read for GET and
+// HEAD, write for everything else. Requests without a key are passed on as
+// before. Unknown and revoked keys are answered with 401, and keys lacking the
+// scope with 403.
+func APIKeyAuth(keys *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(APIKeyHeader)
+		if secret == "" {
+			c.Next()
+			return
+		}
+
+		key, err := keys.Authenticate(c.Request.Context(), secret)
+		if err != nil {
+			status, code, message, _ := ErrorFor(err)
+			if status != http.StatusUnauthorized {
+				status, code, message = http.StatusInternalServerError, CodeInternal, "Internal server error"
+			}
+			AbortWithError(c, status, code, message)
+			return
+		}
+		c.Set(apiKeyContextKey, key)
+
+		scope := models.ScopeWrite
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = models.ScopeRead
+		}
+		if !requireScope(c, scope) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope restricts requests made with an API key to keys granting scope.
+// Requests without a key are not restricted, as there is no other
+// authentication yet.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if requireScope(c, scope) {
+			c.Next()
+		}
+	}
+}
+
+// RequireAPIKey answers requests made without an API key with 401, for routes
+// that interactive clients may not use, such as creating keys
+func RequireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetAPIKey(c) == nil {
+			AbortWithError(c, http.StatusUnauthorized, CodeUnauthorized, "An API key is required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetAPIKey returns the key that authenticated the request, or nil
+func GetAPIKey(c *gin.Context) *models.APIKey {
+	if key, ok := c.Get(apiKeyContextKey); ok {
+		return key.(*models.APIKey)
+	}
+	return nil
+}
+
+// requireScope aborts the request with 403 when it was made with a key that
+// does not grant scope
+func requireScope(c *gin.Context, scope string) bool {
+	if key := GetAPIKey(c); key != nil && !key.HasScope(scope) {
+		AbortWithError(c, http.StatusForbidden, CodeForbidden, "API key lacks the "+scope+" scope")
+		return false
+	}
+	return true
+}
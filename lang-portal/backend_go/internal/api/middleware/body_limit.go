@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit rejects request bodies larger than the limit of their route with
+// 413 Request Entity Too Large. routeLimits holds the limits of routes, such
+// as uploads, by their full path; other routes take defaultLimit. A body
+// within the limit is read before the handler runs, so that a body sent
+// without a length is rejected the same way instead of failing to bind.
+func BodyLimit(defaultLimit int64, routeLimits map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		limit := defaultLimit
+		if routeLimit, ok := routeLimits[c.FullPath()]; ok {
+			limit = routeLimit
+		}
+
+		if c.Request.ContentLength > limit {
+			writeBodyTooLarge(c, limit)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+		if err != nil {
+			AbortInvalidInput(c, "Failed to read the request body")
+			return
+		}
+		if int64(len(data)) > limit {
+			writeBodyTooLarge(c, limit)
+			return
+		}
+		c.Request.Body.Close()
+		c.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+		c.Next()
+	}
+}
+
+// writeBodyTooLarge responds that the request body exceeds limit. The
+// connection is closed since the rest of the body is not read.
+func writeBodyTooLarge(c *gin.Context, limit int64) {
+	c.Header("Connection", "close")
+	AbortWithErrorDetails(c, http.StatusRequestEntityTooLarge, CodePayloadTooLarge,
+		"Request bodies are limited to "+formatBytes(limit), gin.H{"max_bytes": limit})
+}
+
+// formatBytes formats n in MB or KB when it is a whole number of them
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
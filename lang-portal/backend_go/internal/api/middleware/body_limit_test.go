@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(16, map[string]int64{"/upload": 1 << 10}))
+	echo := func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.String(http.StatusOK, "%d", len(data))
+	}
+	router.POST("/json", echo)
+	router.POST("/upload", echo)
+
+	serve := func(path string, body io.Reader) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, body))
+		return w
+	}
+
+	w := serve("/json", strings.NewReader(`{"english":"x"}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "15", w.Body.String())
+
+	w = serve("/json", strings.NewReader(`{"english":"water"}`))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"PAYLOAD_TOO_LARGE","message":"Request bodies are limited to 16 bytes","details":{"max_bytes":16}}}`, w.Body.String())
+
+	// Without a length the body is counted as it is read
+	w = serve("/json", io.MultiReader(strings.NewReader(strings.Repeat("a", 17))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = serve("/upload", strings.NewReader(strings.Repeat("a", 1<<10)))
+	assert.Equal(t, http.StatusOK, w.Code, "routes have limits of their own")
+	w = serve("/upload", strings.NewReader(strings.Repeat("a", 1<<10+1)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "limited to 1 KB")
+}
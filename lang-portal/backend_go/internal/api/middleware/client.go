@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceLabelHeader names the request header carrying the learner's label for
+// the device a study session is started on, such as "phone"
+const DeviceLabelHeader = "X-Device-Label"
+
+// ClientInfo returns the client details to record with a study session started
+// by the request. The device label header wins over a label sent in the body.
+func ClientInfo(c *gin.Context, bodyLabel string) models.ClientInfo {
+	label := c.GetHeader(DeviceLabelHeader)
+	if label == "" {
+		label = bodyLabel
+	}
+	return models.NewClientInfo(label, c.Request.UserAgent())
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"lang-portal/backend_go/internal/i18n"
+	"lang-portal/backend_go/internal/service"
+)
+
+// Codes of error responses. They are part of the API: clients branch on the
+// code, while messages are for people and may change. The codes of service
+// errors are passed through.
+const (
+	// CodeInvalidInput is a malformed or invalid request (400)
+	CodeInvalidInput = service.ErrCodeInvalidInput
+	// CodeUnsupportedVersion is a request for an API version the path does
+	// not serve (400)
+	CodeUnsupportedVersion = "UNSUPPORTED_API_VERSION"
+	// CodeUnauthorized is a missing, unknown or revoked credential (401)
+	CodeUnauthorized = service.ErrCodeUnauthorized
+	// CodeForbidden is a credential lacking the needed scope (403)
+	CodeForbidden = "FORBIDDEN"
+	// CodeNotFound is an unknown resource or route (404)
+	CodeNotFound = service.ErrCodeNotFound
+	// CodeConflict is an update that lost a race or clashes with existing
+	// data (409)
+	CodeConflict = service.ErrCodeConflict
+	// CodePayloadTooLarge is a request body over its limit (413)
+	CodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+	// CodeRateLimited is a request over a rate limit or quota (429)
+	CodeRateLimited = service.ErrCodeRateLimited
+	// CodeInternal is a failure of the server (500)
+	CodeInternal = service.ErrCodeInternal
+	// CodeTimeout is a request that did not finish before its deadline (503)
+	CodeTimeout = "TIMEOUT"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes an error: its code, a message for people, details
+// that depend on the code, and the ID of the request for support
+type ErrorBody struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// FieldError is a detail of a CodeInvalidInput response for a body that
+// failed validation: the field and the rule it broke
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+}
+
+// NewErrorResponse returns the error response of the request c. The message
+// is translated into the language of the request's Accept-Language header
+// when the catalog has it.
+func NewErrorResponse(c *gin.Context, code, message string, details interface{}) ErrorResponse {
+	return ErrorResponse{Error: ErrorBody{
+		Code:      code,
+		Message:   i18n.Translate(i18n.Negotiate(c.GetHeader("Accept-Language")), message),
+		Details:   details,
+		RequestID: GetRequestID(c),
+	}}
+}
+
+// AbortWithError responds with status and an error of code, and stops the
+// handler chain
+func AbortWithError(c *gin.Context, status int, code, message string) {
+	AbortWithErrorDetails(c, status, code, message, nil)
+}
+
+// AbortWithErrorDetails is AbortWithError with details
+func AbortWithErrorDetails(c *gin.Context, status int, code, message string, details interface{}) {
+	c.AbortWithStatusJSON(status, NewErrorResponse(c, code, message, details))
+}
+
+// AbortInvalidInput responds with 400 and message
+func AbortInvalidInput(c *gin.Context, message string) {
+	AbortWithError(c, http.StatusBadRequest, CodeInvalidInput, message)
+}
+
+// AbortInvalidBody responds with 400 for a body that failed to bind. The
+// fields failing validation are listed in the details.
+func AbortInvalidBody(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		AbortInvalidInput(c, err.Error())
+		return
+	}
+	fields := make([]FieldError, 0, len(invalid))
+	for _, fieldErr := range invalid {
+		fields = append(fields, FieldError{Field: fieldName(fieldErr), Rule: fieldErr.Tag()})
+	}
+	AbortWithErrorDetails(c, http.StatusBadRequest, CodeInvalidInput, err.Error(), fields)
+}
+
+func init() {
+	// Name the fields of invalid bodies as clients send them
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// fieldName returns the path of the field below the bound struct, such as
+// words[0].english
+func fieldName(err validator.FieldError) string {
+	namespace := err.Namespace()
+	if _, field, ok := strings.Cut(namespace, "."); ok {
+		return field
+	}
+	return namespace
+}
+
+// StatusForError maps an error to the HTTP status code that should be returned for it.
+// ServiceError codes are mapped explicitly; any other error is treated as internal.
+func StatusForError(err error) int {
+	var srvErr *service.ServiceError
+	if errors.As(err, &srvErr) {
+		switch srvErr.Code {
+		case service.ErrCodeNotFound:
+			return http.StatusNotFound
+		case service.ErrCodeInvalidInput:
+			return http.StatusBadRequest
+		case service.ErrCodeUnauthorized:
+			return http.StatusUnauthorized
+		case service.ErrCodeConflict:
+			return http.StatusConflict
+		case service.ErrCodeRateLimited:
+			return http.StatusTooManyRequests
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorFor returns the status, code, message and details reported for err.
+// Only service errors carry a code, a message and details for the client;
+// other errors are reported as internal without exposing their message.
+// Service errors report their message without the error they wrap, which may
+// be a database error and would not be translated; internal ones report no
+// details.
+func ErrorFor(err error) (status int, code, message string, details interface{}) {
+	var srvErr *service.ServiceError
+	if !errors.As(err, &srvErr) || srvErr.Code == "" {
+		return http.StatusInternalServerError, CodeInternal, "Internal server error", nil
+	}
+	if srvErr.Code == service.ErrCodeInternal {
+		return http.StatusInternalServerError, CodeInternal, srvErr.Message, nil
+	}
+	return StatusForError(err), srvErr.Code, srvErr.Message, srvErr.Details
+}
+
+// ErrorHandler writes a JSON error response for the last error attached with c.Error,
+// unless the handler has already written a response. Errors after the request
+// deadline set by Timeout are reported as timeouts. Internal errors are logged
+// with their cause.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		if timedOut(c) {
+			writeTimeout(c)
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, code, message, details := ErrorFor(err)
+		if status == http.StatusInternalServerError {
+			// The client is not told the cause, so it is logged
+			fmt.Printf("[ERROR] %s %s req=%s: %v\n", c.Request.Method, c.Request.URL.Path, GetRequestID(c), err)
+		}
+		c.JSON(status, NewErrorResponse(c, code, message, details))
+	}
+}
+
+// NotFound answers requests for unknown routes
+func NotFound() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AbortWithError(c, http.StatusNotFound, CodeNotFound, "No route for "+c.Request.Method+" "+c.Request.URL.Path)
+	}
+}
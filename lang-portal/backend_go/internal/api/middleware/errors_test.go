@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/service"
+)
+
+func TestErrorFor(t *testing.T) {
+	dbErr := errors.New("sqlite: database is locked")
+	cases := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"plain error", dbErr, http.StatusInternalServerError, CodeInternal, "Internal server error"},
+		{"not found", service.NewServiceError(service.ErrCodeNotFound, "Word not found", nil), http.StatusNotFound, CodeNotFound, "Word not found"},
+		{"not found with cause", service.NewServiceError(service.ErrCodeNotFound, "Word not found", errors.New("record not found")), http.StatusNotFound, CodeNotFound, "Word not found"},
+		{"invalid input", service.NewServiceError(service.ErrCodeInvalidInput, "Invalid part of speech", nil), http.StatusBadRequest, CodeInvalidInput, "Invalid part of speech"},
+		{"conflict", service.NewServiceError(service.ErrCodeConflict, "Word already exists", nil), http.StatusConflict, CodeConflict, "Word already exists"},
+		{"internal", service.NewServiceError(service.ErrCodeInternal, "Failed to list words", dbErr), http.StatusInternalServerError, CodeInternal, "Failed to list words"},
+		{"wrapped", errors.Join(errors.New("outer"), service.NewServiceError(service.ErrCodeRateLimited, "Too many requests", nil)), http.StatusTooManyRequests, CodeRateLimited, "Too many requests"},
+		{"without code", service.NewServiceError("", "Something", dbErr), http.StatusInternalServerError, CodeInternal, "Internal server error"},
+	}
+	for _, tc := range cases {
+		status, code, message, details := ErrorFor(tc.err)
+		assert.Equal(t, tc.status, status, tc.name)
+		assert.Equal(t, tc.code, code, tc.name)
+		assert.Equal(t, tc.message, message, tc.name)
+		assert.Nil(t, details, tc.name)
+	}
+
+	reason := service.ErrorReason{Reason: "unexpected EOF"}
+	_, _, message, details := ErrorFor(service.NewServiceError(service.ErrCodeInvalidInput, "Archive is not valid JSON", errors.New("unexpected EOF")).WithDetails(reason))
+	assert.Equal(t, "Archive is not valid JSON", message)
+	assert.Equal(t, reason, details)
+	_, _, _, details = ErrorFor(service.NewServiceError(service.ErrCodeInternal, "Failed to restore", nil).WithDetails(reason))
+	assert.Nil(t, details, "internal errors have no details")
+}
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/plain", func(c *gin.Context) {
+		c.Error(errors.New("sqlite: no such table: words"))
+	})
+	router.GET("/service", func(c *gin.Context) {
+		c.Error(service.NewServiceError(service.ErrCodeNotFound, "Word not found", nil))
+	})
+	router.GET("/details", func(c *gin.Context) {
+		err := errors.New("unexpected EOF")
+		c.Error(service.NewServiceError(service.ErrCodeInvalidInput, "Archive is not valid JSON", err).WithDetails(service.ErrorReason{Reason: err.Error()}))
+	})
+	router.GET("/internal", func(c *gin.Context) {
+		c.Error(service.NewServiceError(service.ErrCodeInternal, "Failed to get word", errors.New("sqlite: disk I/O error")))
+	})
+	router.GET("/written", func(c *gin.Context) {
+		c.Error(errors.New("after the response"))
+		c.String(http.StatusOK, "ok")
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := serve("/plain")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Internal server error"}}`, w.Body.String())
+
+	w = serve("/service")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"NOT_FOUND","message":"Word not found"}}`, w.Body.String())
+
+	w = serve("/details")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"INVALID_INPUT","message":"Archive is not valid JSON","details":{"reason":"unexpected EOF"}}}`, w.Body.String())
+
+	w = serve("/internal")
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"Failed to get word"}}`, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "sqlite", "the wrapped error is not exposed")
+
+	w = serve("/written")
+	assert.Equal(t, http.StatusOK, w.Code, "responses already written are kept")
+	assert.Equal(t, "ok", w.Body.String())
+}
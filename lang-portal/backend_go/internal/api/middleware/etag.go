@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WeakETag builds a weak entity tag from a resource's last modification time and
+// any additional values that affect its representation (IDs, counters, page numbers)
+func WeakETag(updatedAt time.Time, values ...interface{}) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d", updatedAt.UnixNano())
+	for _, v := range values {
+		fmt.Fprintf(h, "|%v", v)
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// CheckNotModified sets the ETag header on the response and, if the request's
+// If-None-Match header matches it, writes a 304 Not Modified status.
+// It returns true when the handler should stop without writing a body.
+func CheckNotModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		// Weak comparison: W/"x" and "x" are considered equal
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatchVersion reads the record version an update was made against from the
+// If-Match header, sent as a quoted version number such as "3". It returns 0,
+// meaning no check, when the header is absent or "*".
+func IfMatchVersion(c *gin.Context) (uint, error) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+	version, err := strconv.ParseUint(strings.Trim(ifMatch, `"`), 10, 32)
+	if err != nil || version == 0 {
+		return 0, errors.New(`If-Match must be a record version such as "3"`)
+	}
+	return uint(version), nil
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPage     = 1
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// Query parameters read by QueryParams; every other parameter is a filter
+const (
+	pageParam     = "page"
+	pageSizeParam = "page_size"
+	sortByParam   = "sort_by"
+	orderParam    = "order"
+)
+
+// queryParamsKey is the context key QueryParams stores its result under
+const queryParamsKey = "query_params"
+
+// PaginationParams represents pagination parameters from the request
+type PaginationParams struct {
+	Page     int
+	PageSize int
+}
+
+// SortParams is the requested order of a list. By is validated by the service
+// against the fields the list supports; Order is lower-cased.
+type SortParams struct {
+	By    string
+	Order string
+}
+
+// Filters holds the single-valued query parameters other than paging and
+// sorting, by name. Handlers read the filters their list supports.
+type Filters map[string]string
+
+// Int returns the named filter as an integer, or def when it is absent
+func (f Filters) Int(name string, def int) (int, error) {
+	raw, ok := f[name]
+	if !ok || raw == "" {
+		return def, nil
+	}
+	return strconv.Atoi(raw)
+}
+
+// Time returns the named filter as an RFC 3339 timestamp, or the zero time when
+// it is absent
+func (f Filters) Time(name string) (time.Time, error) {
+	raw, ok := f[name]
+	if !ok || raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// QueryParams are the paging, sorting and filtering parameters of a request
+type QueryParams struct {
+	PaginationParams
+	Sort    SortParams
+	Filters Filters
+}
+
+// QueryParamsMiddleware parses the paging (page, page_size), sorting (sort_by,
+// order) and filter parameters of every request once, for handlers to read with
+// GetQueryParams or GetPaginationParams
+func QueryParamsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+
+		page, err := strconv.Atoi(query.Get(pageParam))
+		if err != nil || page < 1 {
+			page = defaultPage
+		}
+		pageSize, err := strconv.Atoi(query.Get(pageSizeParam))
+		if err != nil || pageSize < 1 {
+			pageSize = defaultPageSize
+		}
+		if pageSize > maxPageSize {
+			pageSize = maxPageSize
+		}
+
+		filters := Filters{}
+		for name, values := range query {
+			switch name {
+			case pageParam, pageSizeParam, sortByParam, orderParam:
+				continue
+			}
+			filters[name] = values[0]
+		}
+
+		c.Set(queryParamsKey, QueryParams{
+			PaginationParams: PaginationParams{Page: page, PageSize: pageSize},
+			Sort: SortParams{
+				By:    strings.TrimSpace(query.Get(sortByParam)),
+				Order: strings.ToLower(strings.TrimSpace(query.Get(orderParam))),
+			},
+			Filters: filters,
+		})
+
+		c.Next()
+	}
+}
+
+// GetQueryParams retrieves the parameters parsed by QueryParamsMiddleware, or
+// the defaults when it did not run
+func GetQueryParams(c *gin.Context) QueryParams {
+	if params, exists := c.Get(queryParamsKey); exists {
+		return params.(QueryParams)
+	}
+	return QueryParams{
+		PaginationParams: PaginationParams{Page: defaultPage, PageSize: defaultPageSize},
+		Filters:          Filters{},
+	}
+}
+
+// GetPaginationParams retrieves pagination parameters from the context
+func GetPaginationParams(c *gin.Context) PaginationParams {
+	return GetQueryParams(c).PaginationParams
+}
+
+// Pagination describes the page of a paginated response
+type Pagination struct {
+	CurrentPage  int `json:"current_page"`
+	TotalPages   int `json:"total_pages"`
+	TotalItems   int `json:"total_items"`
+	ItemsPerPage int `json:"items_per_page"`
+}
+
+// PaginatedResponse represents a paginated API response
+type PaginatedResponse[T any] struct {
+	Items      []T        `json:"items"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// NewPaginatedResponse creates a new paginated response. A nil items slice is
+// encoded as an empty list.
+func NewPaginatedResponse[T any](items []T, totalItems int, params PaginationParams) PaginatedResponse[T] {
+	totalPages := (totalItems + params.PageSize - 1) / params.PageSize
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	if items == nil {
+		items = []T{}
+	}
+
+	return PaginatedResponse[T]{
+		Items: items,
+		Pagination: Pagination{
+			CurrentPage:  params.Page,
+			TotalPages:   totalPages,
+			TotalItems:   totalItems,
+			ItemsPerPage: params.PageSize,
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitStore keeps the token buckets of the rate limits. The in-memory
+// store limits each process on its own; a shared store such as Redis applies
+// the limits across all replicas and keeps them over restarts.
+type RateLimitStore interface {
+	// Take takes a token from the bucket named key, which refills at rps
+	// tokens a second up to burst. When the bucket is empty it reports false
+	// and how long until the next token.
+	Take(ctx context.Context, key string, rps float64, burst int) (bool, time.Duration, error)
+}
+
+// memorySweepInterval is how often the in-memory store drops idle buckets
+const memorySweepInterval = time.Minute
+
+// MemoryRateLimitStore keeps the token buckets in the process. Buckets that
+// have refilled are dropped now and then, so that the buckets of clients that
+// went away do not pile up; a new bucket starts out full just the same.
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	swept    time.Time
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{limiters: make(map[string]*rate.Limiter)}
+}
+
+// Take implements RateLimitStore. The rate and burst of a bucket are fixed by
+// its first use.
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, rps float64, burst int) (bool, time.Duration, error) {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.swept) >= memorySweepInterval {
+		s.sweep(now)
+	}
+	limiter, ok := s.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(rps), burst)
+		s.limiters[key] = limiter
+	}
+	s.mu.Unlock()
+
+	reservation := limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		return false, delay, nil
+	}
+	return true, 0, nil
+}
+
+// sweep drops the buckets that are full at now. The caller holds s.mu.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, limiter := range s.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(s.limiters, key)
+		}
+	}
+	s.swept = now
+}
+
+// RateLimit middleware limits each client to rps requests a second with bursts
+// of up to burst, counted in store under name and the client, see Client. A
+// nil store keeps the buckets in memory. When the store fails the request is
+// let through: an unreachable store must not take the API down with it.
+func RateLimit(store RateLimitStore, name string, rps float64, burst int) gin.HandlerFunc {
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+	return func(c *gin.Context) {
+		key := name + ":" + Client(c)
+		allowed, wait, err := store.Take(c.Request.Context(), key, rps, burst)
+		if err != nil {
+			fmt.Printf("[RATE LIMIT] %s: %v\n", key, err)
+			c.Next()
+			return
+		}
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			AbortWithErrorDetails(c, http.StatusTooManyRequests, CodeRateLimited, "Rate limit exceeded", gin.H{
+				"retry_after": wait.Seconds(),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Client identifies the client a request is counted against by rate limits and
+// quotas: its API key once the request is authenticated, its IP address
+// otherwise. Unlike Actor it cannot be chosen by the client. Limits applied
+// before authentication are therefore per IP address.
+func Client(c *gin.Context) string {
+	if key := GetAPIKey(c); key != nil {
+		return "key:" + strconv.FormatUint(uint64(key.ID), 10)
+	}
+	return "ip:" + c.ClientIP()
+}
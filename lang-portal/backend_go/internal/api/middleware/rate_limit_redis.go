@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeToken refills the bucket in KEYS[1] for the time since it was last
+// used, by the clock of the Redis server so that replicas with skewed clocks
+// agree, and takes a token if one is left. It returns whether a token was
+// taken and otherwise the milliseconds until the next one. Idle buckets expire
+// once they would be full again.
+var takeToken = redis.NewScript(`
+local rps = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "at")
+local tokens = tonumber(bucket[1]) or burst
+local at = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - at) * rps)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rps * 1000)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "at", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rps * 1000) + 1000)
+return {allowed, wait}
+`)
+
+// RedisRateLimitKeyPrefix is put before the bucket names in Redis
+const RedisRateLimitKeyPrefix = "lang-portal:rate-limit:"
+
+// RedisRateLimitStore keeps the token buckets in Redis, shared by every
+// replica using the same server
+type RedisRateLimitStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisRateLimitStore creates a store on client
+func NewRedisRateLimitStore(client redis.UniversalClient) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client}
+}
+
+// NewRedisRateLimitStoreFromURL creates a store on the Redis server at url,
+// such as redis://:password@host:6379/0
+func NewRedisRateLimitStoreFromURL(url string) (*RedisRateLimitStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedisRateLimitStore(redis.NewClient(opts)), nil
+}
+
+// Take implements RateLimitStore
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, rps float64, burst int) (bool, time.Duration, error) {
+	result, err := takeToken.Run(ctx, s.client, []string{RedisRateLimitKeyPrefix + key}, rps, burst).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if result[0] == 1 {
+		return true, 0, nil
+	}
+	return false, time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// Ping checks that the Redis server is reachable
+func (s *RedisRateLimitStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close closes the connections to the Redis server
+func (s *RedisRateLimitStore) Close() error {
+	return s.client.Close()
+}
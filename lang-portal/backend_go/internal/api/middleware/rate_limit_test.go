@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+func rateLimitedRouter(store RateLimitStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimit(store, "test", 1, 2))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func statuses(router *gin.Engine, n int) []int {
+	return statusesFrom(router, "192.0.2.1:1234", n)
+}
+
+// statusesFrom sends n requests from the client at remoteAddr
+func statusesFrom(router *gin.Engine, remoteAddr string, n int) []int {
+	codes := make([]int, n)
+	for i := range codes {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		router.ServeHTTP(w, req)
+		codes[i] = w.Code
+	}
+	return codes
+}
+
+func TestRateLimit_Memory(t *testing.T) {
+	router := rateLimitedRouter(nil)
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, statuses(router, 3))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"error":{"code":"RATE_LIMITED","message":"Rate limit exceeded"`)
+}
+
+func TestRateLimit_PerClient(t *testing.T) {
+	limited := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+
+	t.Run("memory", func(t *testing.T) {
+		router := rateLimitedRouter(nil)
+		assert.Equal(t, limited, statusesFrom(router, "192.0.2.1:1234", 3))
+		assert.Equal(t, limited, statusesFrom(router, "192.0.2.2:1234", 3), "another client has its own bucket")
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		store := NewRedisRateLimitStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+		defer store.Close()
+		router := rateLimitedRouter(store)
+
+		assert.Equal(t, limited, statusesFrom(router, "192.0.2.1:1234", 3))
+		assert.Equal(t, limited, statusesFrom(router, "192.0.2.2:1234", 3), "another client has its own bucket")
+		assert.True(t, server.Exists(RedisRateLimitKeyPrefix+"test:ip:192.0.2.1"))
+		assert.True(t, server.Exists(RedisRateLimitKeyPrefix+"test:ip:192.0.2.2"))
+	})
+
+	t.Run("api keys", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if id, err := strconv.ParseUint(c.GetHeader(APIKeyHeader), 10, 32); err == nil {
+				c.Set(apiKeyContextKey, &models.APIKey{ID: uint(id)})
+			}
+		})
+		router.Use(RateLimit(nil, "test", 1, 2))
+		router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+		send := func(key string, n int) []int {
+			codes := make([]int, n)
+			for i := range codes {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set(APIKeyHeader, key)
+				router.ServeHTTP(w, req)
+				codes[i] = w.Code
+			}
+			return codes
+		}
+
+		// Keys are counted apart even when they share an address
+		assert.Equal(t, limited, send("1", 3))
+		assert.Equal(t, limited, send("2", 3))
+	})
+}
+
+func TestMemoryRateLimitStore_DropsIdleBuckets(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	ctx := context.Background()
+	allowed, _, err := store.Take(ctx, "busy", 1, 1)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	_, _, err = store.Take(ctx, "idle", 1000, 1)
+	require.NoError(t, err)
+
+	store.sweep(time.Now().Add(10 * time.Millisecond))
+	assert.Contains(t, store.limiters, "busy", "an empty bucket is kept")
+	assert.NotContains(t, store.limiters, "idle", "a refilled bucket is dropped")
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	newStore := func() *RedisRateLimitStore {
+		store := NewRedisRateLimitStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+		t.Cleanup(func() { store.Close() })
+		return store
+	}
+	ctx := context.Background()
+
+	t.Run("replicas share the bucket", func(t *testing.T) {
+		first, second := rateLimitedRouter(newStore()), rateLimitedRouter(newStore())
+
+		assert.Equal(t, []int{http.StatusOK}, statuses(first, 1))
+		assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, statuses(second, 2))
+		assert.Equal(t, []int{http.StatusTooManyRequests}, statuses(first, 1))
+		assert.True(t, server.Exists(RedisRateLimitKeyPrefix+"test:ip:192.0.2.1"))
+	})
+
+	t.Run("reports the wait for the next token", func(t *testing.T) {
+		store := newStore()
+		allowed, _, err := store.Take(ctx, "slow", 0.5, 1)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+
+		allowed, wait, err := store.Take(ctx, "slow", 0.5, 1)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+		assert.InDelta(t, 2*time.Second, wait, float64(100*time.Millisecond))
+		assert.NoError(t, store.Ping(ctx))
+	})
+
+	t.Run("lets requests through when Redis is down", func(t *testing.T) {
+		down := miniredis.RunT(t)
+		store := NewRedisRateLimitStore(redis.NewClient(&redis.Options{Addr: down.Addr(), MaxRetries: -1}))
+		defer store.Close()
+		down.Close()
+
+		assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, statuses(rateLimitedRouter(store), 3))
+		assert.Error(t, store.Ping(ctx))
+	})
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"lang-portal/backend_go/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID. A valid ID sent by the client, such
+// as one assigned by a proxy, is kept; otherwise one is generated.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs accepted from clients
+const maxRequestIDLength = 64
+
+// RequestID assigns every request an ID, returns it in the X-Request-ID header
+// and adds it with the matched route to the request context, where the query
+// logger reads it
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Set(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(telemetry.WithRequest(c.Request.Context(), telemetry.Request{
+			ID:    id,
+			Route: c.FullPath(),
+		}))
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID RequestID assigned to the request, or "" when
+// the middleware is not installed
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDHeader)
+}
+
+// validRequestID accepts IDs of printable ASCII without spaces, so that they
+// can be logged as a single field
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lang-portal/backend_go/internal/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	var seen telemetry.Request
+	router.GET("/api/words/:id", func(c *gin.Context) {
+		req, ok := telemetry.RequestFrom(c.Request.Context())
+		require.True(t, ok)
+		seen = req
+		assert.Equal(t, req.ID, GetRequestID(c))
+		c.Status(http.StatusNoContent)
+	})
+
+	serve := func(id string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/words/7", nil)
+		if id != "" {
+			r.Header.Set(RequestIDHeader, id)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("")
+	generated := w.Header().Get(RequestIDHeader)
+	assert.Len(t, generated, 16)
+	assert.Equal(t, telemetry.Request{ID: generated, Route: "/api/words/:id"}, seen)
+	assert.NotEqual(t, generated, serve("").Header().Get(RequestIDHeader))
+
+	w = serve("proxy-42")
+	assert.Equal(t, "proxy-42", w.Header().Get(RequestIDHeader), "IDs from clients are kept")
+	assert.Equal(t, "proxy-42", seen.ID)
+
+	w = serve("has space")
+	assert.NotEqual(t, "has space", w.Header().Get(RequestIDHeader), "IDs that cannot be logged as one field are replaced")
+}
@@ -2,12 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
 // SecurityHeaders adds security-related headers to all responses
@@ -36,9 +36,9 @@ func SecurityHeaders() gin.HandlerFunc {
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, Authorization")
-		c.Header("Access-Control-Expose-Headers", "Content-Length")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, Authorization, If-Match, If-None-Match, X-Actor, X-API-Key, X-Device-Label, X-Request-ID, API-Version")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, ETag, X-Request-ID, API-Version, Deprecation, Link")
 		c.Header("Access-Control-Max-Age", "86400") // 24 hours
 
 		if c.Request.Method == "OPTIONS" {
@@ -50,35 +50,6 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
-// RateLimiter implements rate limiting using a token bucket algorithm
-type RateLimiter struct {
-	limiter *rate.Limiter
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(rps float64, burst int) *RateLimiter {
-	return &RateLimiter{
-		limiter: rate.NewLimiter(rate.Limit(rps), burst),
-	}
-}
-
-// RateLimit middleware limits the number of requests per client
-func RateLimit(rps float64, burst int) gin.HandlerFunc {
-	limiter := NewRateLimiter(rps, burst)
-	return func(c *gin.Context) {
-		if !limiter.limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Rate limit exceeded",
-				"retry_after": time.Until(time.Now().Add(time.Second / time.Duration(rps))).Seconds(),
-			})
-			c.Abort()
-			return
-		}
-
-		c.Next()
-	}
-}
-
 // RequestLogger logs information about each request
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -101,14 +72,15 @@ func RequestLogger() gin.HandlerFunc {
 		method := c.Request.Method
 		path := c.Request.URL.Path
 
-		// Log request details
-		fmt.Printf("[%s] %s %s %d %s %s\n",
+		// Log request details, with the request ID that tags its queries
+		fmt.Printf("[%s] %s %s %d %s %s req=%s\n",
 			time.Now().Format("2006-01-02 15:04:05"),
 			clientIP,
 			method,
 			statusCode,
 			path,
 			duration,
+			GetRequestID(c),
 		)
 	}
 }
@@ -121,13 +93,8 @@ func Recovery() gin.HandlerFunc {
 				// Log the error
 				fmt.Printf("[PANIC] %v\n", err)
 
-				// Return 500 Internal Server Error
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": "Internal server error",
-				})
-
-				// Abort the request
-				c.Abort()
+				// Return 500 Internal Server Error and abort the request
+				AbortWithError(c, http.StatusInternalServerError, CodeInternal, "Internal server error")
 			}
 		}()
 
@@ -135,33 +102,31 @@ func Recovery() gin.HandlerFunc {
 	}
 }
 
-// Timeout middleware adds a timeout to the request context
+// Timeout middleware puts a deadline on the request context. The handler
+// chain runs on the request goroutine and is expected to stop once the context
+// is done, as the repositories do. If the deadline passes before a response was
+// written, a 503 timeout response is written after the chain returns; a response
+// already written by a handler is never replaced.
 func Timeout(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Create a context with timeout
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
 
-		// Create a channel to signal when the request is done
-		done := make(chan struct{})
-
-		// Process the request in a goroutine
-		go func() {
-			c.Next()
-			close(done)
-		}()
+		c.Next()
 
-		// Wait for either the request to complete or the timeout
-		select {
-		case <-done:
-			// Request completed successfully
-			return
-		case <-ctx.Done():
-			// Request timed out
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"error": "Request timeout",
-			})
-			c.Abort()
+		if timedOut(c) && !c.Writer.Written() {
+			writeTimeout(c)
 		}
 	}
 }
+
+// timedOut reports whether the request deadline set by Timeout has passed
+func timedOut(c *gin.Context) bool {
+	return errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+// writeTimeout responds with the status http.TimeoutHandler uses
+func writeTimeout(c *gin.Context) {
+	AbortWithError(c, http.StatusServiceUnavailable, CodeTimeout, "Request timeout")
+}
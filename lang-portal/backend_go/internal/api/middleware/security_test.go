@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithTimeout(timeout time.Duration, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(timeout), ErrorHandler())
+	router.GET("/", handlers...)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w
+}
+
+func TestTimeout(t *testing.T) {
+	t.Run("handler finishes in time", func(t *testing.T) {
+		w := serveWithTimeout(time.Second, func(c *gin.Context) {
+			_, ok := c.Request.Context().Deadline()
+			assert.True(t, ok)
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+
+	t.Run("handler stops at the deadline without responding", func(t *testing.T) {
+		w := serveWithTimeout(10*time.Millisecond, func(c *gin.Context) {
+			<-c.Request.Context().Done()
+		})
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"TIMEOUT","message":"Request timeout"}}`, w.Body.String())
+	})
+
+	t.Run("handler error after the deadline", func(t *testing.T) {
+		w := serveWithTimeout(10*time.Millisecond, func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			c.Error(errors.New("query interrupted"))
+		})
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"TIMEOUT","message":"Request timeout"}}`, w.Body.String())
+	})
+
+	t.Run("late response is not written twice", func(t *testing.T) {
+		w := serveWithTimeout(10*time.Millisecond, func(c *gin.Context) {
+			time.Sleep(30 * time.Millisecond)
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionHeader names the API version that served a response. Clients may
+// send it with a request to state the version they expect.
+const APIVersionHeader = "API-Version"
+
+// Headers marking responses of deprecated paths
+const (
+	DeprecationHeader = "Deprecation"
+	LinkHeader        = "Link"
+)
+
+// APIVersion tags the requests of a versioned route group with version and
+// returns it in the API-Version header. A request expecting another version is
+// rejected with 400 instead of being answered in a shape the client cannot read.
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(APIVersionHeader, version)
+		if requested := strings.TrimSpace(c.GetHeader(APIVersionHeader)); requested != "" && requested != version {
+			AbortWithError(c, http.StatusBadRequest, CodeUnsupportedVersion,
+				fmt.Sprintf("API version %s is not served at this path, which serves version %s", requested, version))
+			return
+		}
+		c.Set(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the API version of the route serving the request, or ""
+// outside versioned route groups
+func GetAPIVersion(c *gin.Context) string {
+	return c.GetString(APIVersionHeader)
+}
+
+// Deprecated marks the responses of paths under prefix as deprecated, linking
+// to the same path under successor, which replaces them
+func Deprecated(prefix, successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := successor + strings.TrimPrefix(c.Request.URL.Path, prefix)
+		c.Header(DeprecationHeader, "true")
+		c.Header(LinkHeader, fmt.Sprintf(`<%s>; rel="successor-version"`, path))
+		c.Next()
+	}
+}
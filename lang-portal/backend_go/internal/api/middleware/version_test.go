@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/api/v1", APIVersion("1"))
+	v1.GET("/words/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, GetAPIVersion(c))
+	})
+	legacy := router.Group("/api", Deprecated("/api", "/api/v1"), APIVersion("1"))
+	legacy.GET("/words/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, GetAPIVersion(c))
+	})
+
+	serve := func(path, version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if version != "" {
+			req.Header.Set(APIVersionHeader, version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/api/v1/words/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get(APIVersionHeader))
+	assert.Empty(t, w.Header().Get(DeprecationHeader))
+
+	w = serve("/api/words/1", "1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "true", w.Header().Get(DeprecationHeader))
+	assert.Equal(t, `</api/v1/words/1>; rel="successor-version"`, w.Header().Get(LinkHeader))
+
+	w = serve("/api/v1/words/1", "2")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "API version 2 is not served at this path")
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit buckets, reported in the route table by name
+const (
+	// RateLimitGlobal is the limit applied to every route, per IP address
+	RateLimitGlobal = "global"
+	// RateLimitPublic is the additional limit on the unauthenticated,
+	// embeddable endpoints
+	RateLimitPublic = "public"
+	// RateLimitShared is the additional limit on the shared decks
+	RateLimitShared = "shared"
+)
+
+// Roles reported in the route table: the scope an API key needs
+const (
+	RoleAdmin = models.ScopeAdmin
+)
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Handler    string   `json:"handler"`
+	Roles      []string `json:"roles"`
+	RateLimits []string `json:"rate_limits"`
+}
+
+// routePolicy is the access metadata of the routes under a path prefix. Gin
+// does not expose the middleware chain of a route, so the middleware enforcing
+// roles and rate limits is attached through routePolicies, which records the
+// policy of the group it is attached to.
+type routePolicy struct {
+	prefix     string
+	roles      []string
+	rateLimits []string
+}
+
+// routePolicies collects the policies of the middleware attached while
+// registering routes
+type routePolicies []routePolicy
+
+// rateLimit limits the routes of group with the buckets name of store, one
+// per client
+func (p *routePolicies) rateLimit(group *gin.RouterGroup, store middleware.RateLimitStore, name string, rps float64, burst int) {
+	group.Use(middleware.RateLimit(store, name, rps, burst))
+	p.record(group.BasePath(), routePolicy{rateLimits: []string{name}})
+}
+
+// requireScope restricts the routes of group to API keys granting scope,
+// reported as their role
+func (p *routePolicies) requireScope(group *gin.RouterGroup, scope string) {
+	group.Use(middleware.RequireScope(scope))
+	p.record(group.BasePath(), routePolicy{roles: []string{scope}})
+}
+
+// record keeps the policy for routes under prefix
+func (p *routePolicies) record(prefix string, policy routePolicy) {
+	policy.prefix = prefix
+	*p = append(*p, policy)
+}
+
+// describe builds the route table of router. Every matching policy
+// contributes, from the shortest prefix to the longest.
+func (p routePolicies) describe(router *gin.Engine) []RouteInfo {
+	policies := append(routePolicies(nil), p...)
+	sort.SliceStable(policies, func(i, j int) bool {
+		return len(policies[i].prefix) < len(policies[j].prefix)
+	})
+
+	routes := router.Routes()
+	table := make([]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		info := RouteInfo{
+			Method:     route.Method,
+			Path:       route.Path,
+			Handler:    route.Handler,
+			Roles:      []string{},
+			RateLimits: []string{},
+		}
+		for _, policy := range policies {
+			if !hasPathPrefix(route.Path, policy.prefix) {
+				continue
+			}
+			info.Roles = append(info.Roles, policy.roles...)
+			info.RateLimits = append(info.RateLimits, policy.rateLimits...)
+		}
+		table = append(table, info)
+	}
+
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Path != table[j].Path {
+			return table[i].Path < table[j].Path
+		}
+		return table[i].Method < table[j].Method
+	})
+	return table
+}
+
+// hasPathPrefix reports whether path is prefix or lies below it
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
@@ -0,0 +1,105 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/api"
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/app"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+// bucketRecorder is a rate limit store recording the names of the buckets
+// requests are counted in, without the client. It rejects requests in the
+// bucket named deny, so that a probe stops before reaching its handler.
+type bucketRecorder struct {
+	mu    sync.Mutex
+	taken []string
+	deny  string
+}
+
+func (r *bucketRecorder) Take(_ context.Context, key string, _ float64, _ int) (bool, time.Duration, error) {
+	name, _, _ := strings.Cut(key, ":")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.taken = append(r.taken, name)
+	return name != r.deny, time.Second, nil
+}
+
+// probe sends a request to path and returns the buckets it was counted in
+func (r *bucketRecorder) probe(s *apitest.Server, method, path, deny string) ([]string, *apitest.Response) {
+	r.mu.Lock()
+	r.taken, r.deny = nil, deny
+	r.mu.Unlock()
+	resp := s.Do(method, path, nil)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.taken, resp
+}
+
+var pathParam = regexp.MustCompile(`[:*][^/]+`)
+
+func TestRoutesAPI_TableMatchesRouter(t *testing.T) {
+	recorder := &bucketRecorder{}
+	s := apitest.New(t, app.WithRateLimitStore(recorder), app.WithBootstrapAdminKey("admin-secret"))
+
+	s.Header.Set(middleware.APIKeyHeader, "admin-secret")
+	table := apitest.JSON[struct {
+		Routes []api.RouteInfo `json:"routes"`
+	}](s.Get("/api/v1/routes").Status(http.StatusOK)).Routes
+
+	registered := map[string]bool{}
+	for _, route := range s.App.Router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	require.Len(t, table, len(registered))
+
+	// A key without the admin scope, for checking the roles
+	readKey := apitest.JSON[service.CreatedAPIKey](s.Post("/api/v1/admin/keys", service.NewAPIKey{
+		Name: "reader", Scopes: []string{models.ScopeRead},
+	}).Status(http.StatusCreated)).Key
+	s.Header.Set(middleware.APIKeyHeader, readKey)
+
+	for _, route := range table {
+		assert.True(t, registered[route.Method+" "+route.Path], "%s %s is registered", route.Method, route.Path)
+		require.NotEmpty(t, route.RateLimits, route.Path)
+		assert.Equal(t, api.RateLimitGlobal, route.RateLimits[0], "every route is limited globally")
+		if route.Method != http.MethodGet {
+			continue
+		}
+
+		// The request is rejected in the route's last bucket, before it reaches
+		// the scope checks and the handler
+		path := pathParam.ReplaceAllString(route.Path, "1")
+		last := route.RateLimits[len(route.RateLimits)-1]
+		taken, resp := recorder.probe(s, route.Method, path, last)
+		assert.Equal(t, route.RateLimits, taken, "buckets of %s", route.Path)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, route.Path)
+
+		if len(route.Roles) > 0 {
+			assert.Equal(t, []string{api.RoleAdmin}, route.Roles, route.Path)
+			_, resp := recorder.probe(s, route.Method, path, "")
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, "%s needs the admin scope", route.Path)
+		}
+	}
+
+	byPath := map[string]api.RouteInfo{}
+	for _, route := range table {
+		byPath[route.Method+" "+route.Path] = route
+	}
+	assert.Equal(t, []string{api.RateLimitGlobal, api.RateLimitShared}, byPath["GET /api/v1/shared/:slug"].RateLimits)
+	assert.Equal(t, []string{api.RateLimitGlobal, api.RateLimitPublic}, byPath["GET /api/public/stats/:share_token"].RateLimits)
+	assert.Equal(t, []string{api.RoleAdmin}, byPath["GET /api/v1/routes"].Roles)
+	assert.Empty(t, byPath["GET /api/v1/words"].Roles)
+}
@@ -7,87 +7,370 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Rate limits: the global limit on every route, and the limit for the public
+// endpoints and shared decks on top of it
+const (
+	globalRateLimit = 100
+	globalRateBurst = 200
+	publicRateLimit = 5
+	publicRateBurst = 20
+)
+
 // Services holds all service instances used by the API handlers
 type Services struct {
-	Dashboard *service.DashboardService
-	Word      *service.WordService
-	Group     *service.GroupService
-	Study     *service.StudyService
+	Dashboard    *service.DashboardService
+	Word         *service.WordService
+	Group        *service.GroupService
+	Study        *service.StudyService
+	Search       *service.SearchService
+	Audit        *service.AuditService
+	Share        *service.ShareService
+	Preferences  *service.PreferencesService
+	Health       *service.HealthService
+	Events       *service.EventService
+	Goal         *service.GoalService
+	Kanji        *service.KanjiService
+	Dictionary   *service.DictionaryService
+	Archive      *service.ArchiveService
+	Sentence     *service.SentenceService
+	Audio        *service.AudioService
+	APIKey       *service.APIKeyService
+	Tutor        *service.TutorService
+	Prompt       *service.PromptService
+	AI           *service.AIService
+	Counter      *service.CounterService
+	Cohort       *service.CohortService
+	Notification *service.NotificationService
+	DailySummary *service.DailySummaryService
 }
 
-// RegisterRoutes sets up all API routes and middleware
-func RegisterRoutes(router *gin.Engine, services *Services) {
-	// Create API group
-	api := router.Group("/api")
+// Prefixes of the API versions. LegacyAPIPrefix serves the v1 routes under the
+// paths they had before the API was versioned; it is deprecated.
+const (
+	LegacyAPIPrefix = "/api"
+	APIV1Prefix     = "/api/v1"
+	APIV2Prefix     = "/api/v2"
+)
+
+// RegisterRoutes sets up all API routes and middleware. Each version of the
+// API is a route group; a breaking change to a resource ships in a new version
+// while the previous one keeps its shape. The rate limits, the global one
+// included, count in rateLimits, or in memory when it is nil, and request
+// bodies are capped by bodyLimits.
+func RegisterRoutes(router *gin.Engine, services *Services, rateLimits middleware.RateLimitStore, bodyLimits BodyLimits) {
+	// Access metadata for the route table, recorded as the middleware
+	// enforcing it is attached below
+	var policies routePolicies
+	policies.rateLimit(&router.RouterGroup, rateLimits, RateLimitGlobal, globalRateLimit, globalRateBurst)
+
+	// Uploads declare their own body limit alongside their routes below
+	uploads := uploadLimits{}
+	router.Use(middleware.BodyLimit(bodyLimits.JSON, uploads))
+
+	v1 := router.Group(APIV1Prefix)
 	{
-		// Register middleware
-		api.Use(middleware.PaginationMiddleware())
+		v1.Use(middleware.APIVersion("1"))
+		registerV1Routes(v1, router, services, rateLimits, &policies, bodyLimits, uploads)
+	}
 
-		// Dashboard routes
-		dashboard := api.Group("/dashboard")
-		{
-			dashboard.GET("/last-session", GetLastStudySession(services.Dashboard))
-			dashboard.GET("/progress", GetStudyProgress(services.Dashboard))
-			dashboard.GET("/quick-stats", GetQuickStats(services.Dashboard))
-		}
+	// The v1 routes at their unversioned paths, for clients written before
+	// versioning; responses link to the /api/v1 path replacing them
+	legacy := router.Group(LegacyAPIPrefix)
+	{
+		legacy.Use(middleware.Deprecated(LegacyAPIPrefix, APIV1Prefix))
+		legacy.Use(middleware.APIVersion("1"))
+		registerV1Routes(legacy, router, services, rateLimits, &policies, bodyLimits, uploads)
+	}
+
+	// Probes for orchestrators, outside /api so they bypass API middleware
+	router.GET("/healthz", Liveness())
+	router.GET("/readyz", Readiness(services.Health))
+
+	// Language-neutral v2 resources, backed by the same services as v1
+	v2 := router.Group(APIV2Prefix)
+	{
+		v2.Use(middleware.APIVersion("2"))
+		v2.Use(middleware.APIKeyAuth(services.APIKey))
+		v2.Use(middleware.QueryParamsMiddleware())
+		v2.Use(middleware.ErrorHandler())
+
+		v2.GET("/openapi.yaml", GetOpenAPIV2())
+		v2.GET("/languages", ListLanguagesV2())
 
-		// Word routes
-		words := api.Group("/words")
+		words := v2.Group("/words")
 		{
-			words.GET("", ListWords(services.Word))
-			words.GET("/:id", GetWord(services.Word))
-			words.POST("", CreateWord(services.Word))
-			words.PUT("/:id", UpdateWord(services.Word))
+			words.GET("", ListWordsV2(services.Word))
+			words.GET("/:id", GetWordV2(services.Word))
+			words.POST("", CreateWordV2(services.Word))
+			words.PUT("/:id", UpdateWordV2(services.Word))
 			words.DELETE("/:id", DeleteWord(services.Word))
-			words.GET("/:id/groups", GetGroupsByWord(services.Group))
 		}
+	}
+}
 
-		// Group routes
-		groups := api.Group("/groups")
-		{
-			groups.GET("", ListGroups(services.Group))
-			groups.GET("/:id", GetGroup(services.Group))
-			groups.POST("", CreateGroup(services.Group))
-			groups.PUT("/:id", UpdateGroup(services.Group))
-			groups.DELETE("/:id", DeleteGroup(services.Group))
-			groups.POST("/:id/words/:word_id", AddWordToGroup(services.Group))
-			groups.DELETE("/:id/words/:word_id", RemoveWordFromGroup(services.Group))
-			groups.GET("/:id/stats", GetGroupStudyStats(services.Group))
-			groups.GET("/:id/words", GetWordsByGroup(services.Word))
-			groups.GET("/:id/raw", GetGroupWordsRaw(services.Group))
-		}
+// registerV1Routes registers the v1 routes on api, which is mounted under
+// APIV1Prefix and LegacyAPIPrefix. The limits of its uploads are recorded in
+// uploads.
+func registerV1Routes(api *gin.RouterGroup, router *gin.Engine, services *Services, rateLimits middleware.RateLimitStore, policies *routePolicies, bodyLimits BodyLimits, uploads uploadLimits) {
+	// Register middleware
+	api.Use(middleware.APIKeyAuth(services.APIKey))
+	api.Use(middleware.QueryParamsMiddleware())
+	api.Use(middleware.ErrorHandler())
 
-		// Study routes
-		study := api.Group("/study")
-		{
-			// Study activities
-			study.GET("/activities", ListStudyActivities(services.Study))
-			study.GET("/activities/:id", GetStudyActivity(services.Study))
-			study.POST("/activities", CreateStudyActivity(services.Study))
-
-			// Study sessions
-			study.GET("/sessions", ListStudySessions(services.Study))
-			study.POST("/sessions", CreateStudySession(services.Study))
-			study.GET("/sessions/:id", GetStudySession(services.Study))
-			study.GET("/sessions/group/:group_id", GetStudySessionsByGroup(services.Study))
-			study.GET("/sessions/activity/:activity_id", GetStudySessionsByActivity(services.Study))
-
-			// Word reviews
-			study.POST("/sessions/:id/reviews", AddWordReview(services.Study))
-			study.GET("/sessions/:id/reviews", GetWordReviewsBySession(services.Study))
-
-			// Study statistics
-			study.GET("/stats", GetStudyStats(services.Study))
-			study.GET("/streak", GetStudyStreak(services.Study))
-			study.GET("/active-groups", GetActiveGroups(services.Study))
-			study.POST("/reset", ResetStudyHistory(services.Study))
-		}
+	// Dashboard routes
+	dashboard := api.Group("/dashboard")
+	{
+		dashboard.GET("/last-session", GetLastStudySession(services.Dashboard))
+		dashboard.GET("/progress", GetStudyProgress(services.Dashboard))
+		dashboard.GET("/quick-stats", GetQuickStats(services.Dashboard))
+		dashboard.GET("/progress-history", GetProgressHistory(services.Dashboard))
+		dashboard.GET("/devices", GetDeviceStats(services.Dashboard))
+		dashboard.GET("/stats/by-part", GetPartStats(services.Dashboard))
+		dashboard.GET("/stats/by-language", GetLanguageStats(services.Dashboard))
+		dashboard.GET("/daily-summaries", GetDailySummaries(services.DailySummary))
+	}
+
+	// Word routes
+	words := api.Group("/words")
+	{
+		words.GET("", ListWords(services.Word))
+		words.GET("/due", GetDueWords(services.Word))
+		words.GET("/export", ExportWords(services.Word))
+		words.GET("/parts", ListPartsOfSpeech(services.Word))
+		words.POST("/import", ImportWords(services.Word))
+		uploads.declare(words, "/import", bodyLimits.Import)
+		words.GET("/:id", GetWord(services.Word))
+		words.POST("", CreateWord(services.Word))
+		words.PUT("/:id", UpdateWord(services.Word))
+		words.PATCH("/:id", PatchWord(services.Word))
+		words.DELETE("/:id", DeleteWord(services.Word))
+		words.GET("/:id/groups", GetGroupsByWord(services.Group))
+		words.GET("/:id/audio", GetWordAudio(services.Audio))
+		words.GET("/:id/related", GetRelatedWords(services.Word))
+		words.GET("/:id/explanation", ExplainWord(services.Word))
+		words.GET("/:id/sentences", ListExampleSentences(services.Sentence))
+		words.GET("/:id/render", RenderWord(services.Sentence))
+		words.POST("/:id/sentences", AddExampleSentence(services.Sentence))
+		words.POST("/:id/sentences/generate", GenerateExampleSentence(services.Sentence))
+		words.DELETE("/:id/sentences/:sentence_id", DeleteExampleSentence(services.Sentence))
+	}
+
+	// Kanji routes
+	kanji := api.Group("/kanji")
+	{
+		kanji.GET("", ListKanji(services.Kanji))
+		kanji.GET("/:character", GetKanji(services.Kanji))
+		kanji.PUT("/:character", UpdateKanji(services.Kanji))
+		kanji.GET("/:character/words", GetKanjiWords(services.Kanji))
+		kanji.GET("/:character/strokes", GetKanjiStrokes(services.Kanji))
+	}
+
+	// Group routes
+	groups := api.Group("/groups")
+	{
+		groups.GET("", ListGroups(services.Group))
+		groups.GET("/suggestions", SuggestGroups(services.Group))
+		groups.POST("/suggestions", CreateGroupFromSuggestion(services.Group))
+		groups.GET("/:id", GetGroup(services.Group))
+		groups.POST("", CreateGroup(services.Group))
+		groups.PUT("/:id", UpdateGroup(services.Group))
+		groups.PATCH("/:id", PatchGroup(services.Group))
+		groups.DELETE("/:id", DeleteGroup(services.Group))
+		groups.POST("/:id/words/:word_id", AddWordToGroup(services.Group))
+		groups.DELETE("/:id/words/:word_id", RemoveWordFromGroup(services.Group))
+		groups.GET("/:id/stats", GetGroupStudyStats(services.Group))
+		groups.GET("/:id/words", GetWordsByGroup(services.Word))
+		groups.GET("/:id/raw", GetGroupWordsRaw(services.Group))
+		groups.GET("/:id/goal", GetGroupGoal(services.Goal))
+		groups.PUT("/:id/goal", SetGroupGoal(services.Goal))
+		groups.DELETE("/:id/goal", DeleteGroupGoal(services.Goal))
+		groups.GET("/:id/certificate", GetGroupCertificate(services.Goal))
+		groups.PUT("/:id/share", PublishGroup(services.Group))
+		groups.DELETE("/:id/share", UnpublishGroup(services.Group))
+	}
+
+	// Study routes
+	study := api.Group("/study")
+	{
+		// Study activities
+		study.GET("/activities", ListStudyActivities(services.Study))
+		study.GET("/activities/:id", GetStudyActivity(services.Study))
+		study.POST("/activities", CreateStudyActivity(services.Study))
+		study.GET("/activities/export", ExportActivityCatalog(services.Study))
+		study.POST("/activities/import", ImportActivityCatalog(services.Study))
+		study.POST("/activities/register", RegisterStudyActivity(services.Study))
+		study.POST("/activities/:id/launch", LaunchStudyActivity(services.Study))
+
+		// Study sessions
+		study.GET("/sessions", ListStudySessions(services.Study))
+		study.POST("/sessions", CreateStudySession(services.Study))
+		study.GET("/sessions/active", GetActiveStudySession(services.Study))
+		study.GET("/sessions/:id", GetStudySession(services.Study))
+		study.GET("/sessions/:id/bundle", GetStudySessionBundle(services.Study))
+		study.GET("/sessions/:id/queue", GetStudySessionQueue(services.Study))
+		study.POST("/sessions/:id/complete", CompleteStudySession(services.Study))
+		study.GET("/sessions/group/:group_id", GetStudySessionsByGroup(services.Study))
+		study.GET("/sessions/activity/:activity_id", GetStudySessionsByActivity(services.Study))
+
+		// Recent mistakes queue
+		study.GET("/mistakes", GetMistakes(services.Study))
+		study.GET("/forecast", GetReviewForecast(services.Study))
+		study.POST("/mistakes/sessions", CreateMistakesSession(services.Study))
+
+		// Word reviews
+		study.POST("/sessions/:id/reviews", AddWordReview(services.Study))
+		study.GET("/sessions/:id/reviews", GetWordReviewsBySession(services.Study))
+
+		// Review batches from external activities, authenticated by launch token
+		study.POST("/activity-callback", SubmitActivityReviews(services.Study))
+
+		// Typed answer checking
+		study.POST("/check-answer", CheckAnswer(services.Study))
+		study.POST("/pronunciation-check", CheckPronunciation(services.Study))
+		uploads.declare(study, "/pronunciation-check", bodyLimits.Recording)
 
-		// Health check
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{
-				"status": "ok",
-			})
-		})
+		// Cloze questions from example sentences and listening quizzes from word audio
+		study.GET("/cloze", GetClozeQuestions(services.Sentence))
+		study.GET("/listening", GetListeningQuiz(services.Audio))
+
+		// Counter word drills, read from rules rather than the word list
+		study.GET("/counters", ListCounters(services.Counter))
+		study.GET("/counters/drill", GetCounterDrill(services.Counter))
+		study.POST("/counters/reviews", ReviewCounter(services.Counter))
+
+		// Study statistics
+		study.GET("/stats", GetStudyStats(services.Study))
+		study.GET("/streak", GetStudyStreak(services.Study))
+		study.GET("/xp", GetLearnerLevel(services.Study))
+		study.GET("/active-groups", GetActiveGroups(services.Study))
+		study.POST("/reset", ResetStudyHistory(services.Study))
+	}
+
+	// Tool routes
+	tools := api.Group("/tools")
+	{
+		tools.GET("/transliterate", Transliterate())
+	}
+
+	// Settings routes
+	settings := api.Group("/settings")
+	{
+		settings.GET("/preferences", GetPreferences(services.Preferences))
+		settings.PUT("/preferences", UpdatePreferences(services.Preferences))
+		settings.POST("/reset_history", ResetStudyHistory(services.Study))
+		settings.POST("/full_reset", FullReset(services.Study))
+	}
+
+	// Admin routes
+	admin := api.Group("/admin")
+	{
+		policies.requireScope(admin, RoleAdmin)
+		admin.GET("/audit", ListAuditEntries(services.Audit))
+
+		// API keys for machine clients, managed only with an admin key so that
+		// anonymous requests cannot grant themselves one
+		keys := admin.Group("/keys", middleware.RequireAPIKey())
+		keys.GET("", ListAPIKeys(services.APIKey))
+		keys.POST("", CreateAPIKey(services.APIKey))
+		keys.DELETE("/:id", RevokeAPIKey(services.APIKey))
+
+		// Prompts of the AI features
+		admin.GET("/prompts", ListPrompts(services.Prompt))
+		admin.GET("/prompts/:name", GetPrompt(services.Prompt))
+		admin.PUT("/prompts/:name", UpdatePrompt(services.Prompt))
+		admin.POST("/prompts/:name/restore", RestorePromptVersion(services.Prompt))
+		admin.GET("/ai/providers", ListAIProviders(services.AI))
+		admin.GET("/ai-usage", GetAIUsage(services.AI))
+	}
+
+	// Share tokens for the public stats widget
+	api.POST("/shares", CreateStatsShare(services.Share))
+	api.DELETE("/shares/:token", RevokeStatsShare(services.Share))
+
+	// Unauthenticated, embeddable endpoints with their own rate limit
+	public := api.Group("/public")
+	{
+		policies.rateLimit(public, rateLimits, RateLimitPublic, publicRateLimit, publicRateBurst)
+		public.GET("/stats/:share_token", GetPublicStats(services.Share))
+		public.GET("/certificates/:token", VerifyCertificate(services.Goal))
+		public.GET("/unsubscribe/:token", UnsubscribeReminders(services.Notification))
+		public.POST("/unsubscribe/:token", UnsubscribeReminders(services.Notification))
+	}
+
+	// Groups published as shared decks, readable without authentication
+	shared := api.Group("/shared")
+	{
+		policies.rateLimit(shared, rateLimits, RateLimitShared, publicRateLimit, publicRateBurst)
+		shared.GET("/:slug", GetSharedGroup(services.Group))
+		shared.POST("/:slug/copy", CopySharedGroup(services.Group))
 	}
+
+	// Long-poll fallback for live dashboards
+	api.GET("/events/poll", PollEvents(services.Events))
+
+	// Search across words, groups and activities
+	api.GET("/search", Search(services.Search))
+
+	// Dictionary lookup
+	dictionary := api.Group("/dictionary")
+	{
+		dictionary.GET("/lookup", LookupDictionary(services.Dictionary))
+		dictionary.POST("/words", AddDictionaryWord(services.Dictionary))
+	}
+
+	// Account archives for moving data between deployments
+	api.GET("/export", ExportArchive(services.Archive))
+	api.POST("/import/archive", RestoreArchive(services.Archive))
+	uploads.declare(api, "/import/archive", bodyLimits.Archive)
+	api.POST("/import/from-image", CaptureFromImage(services.Dictionary))
+	uploads.declare(api, "/import/from-image", bodyLimits.Image)
+	api.POST("/import/from-image/words", AddCapturedWords(services.Dictionary))
+
+	// Chats with the tutor
+	tutor := api.Group("/tutor")
+	{
+		tutor.GET("/conversations", ListTutorConversations(services.Tutor))
+		tutor.POST("/conversations", CreateTutorConversation(services.Tutor))
+		tutor.GET("/conversations/:id", GetTutorConversation(services.Tutor))
+		tutor.DELETE("/conversations/:id", DeleteTutorConversation(services.Tutor))
+		tutor.POST("/conversations/:id/messages", SendTutorMessage(services.Tutor))
+	}
+
+	// Cohorts, visible only to the teacher that created them and, for the
+	// leaderboard, their members. Both are told apart by their API key: the
+	// actor header can be set to anyone's name.
+	cohorts := api.Group("/cohorts", middleware.RequireAPIKey())
+	{
+		cohorts.GET("", ListCohorts(services.Cohort))
+		cohorts.POST("", CreateCohort(services.Cohort))
+		cohorts.GET("/:id", GetCohort(services.Cohort))
+		cohorts.DELETE("/:id", DeleteCohort(services.Cohort))
+		cohorts.GET("/:id/stats", GetCohortStats(services.Cohort))
+		cohorts.GET("/:id/leaderboard", GetCohortLeaderboard(services.Cohort))
+		cohorts.PUT("/:id/membership", UpdateCohortMembership(services.Cohort))
+		cohorts.POST("/:id/members", AddCohortMember(services.Cohort))
+		cohorts.DELETE("/:id/members/:learner", RemoveCohortMember(services.Cohort))
+		cohorts.PUT("/:id/groups/:group_id", AssignCohortGroup(services.Cohort))
+		cohorts.DELETE("/:id/groups/:group_id", UnassignCohortGroup(services.Cohort))
+	}
+
+	// The caller's in-app inbox, filled by the notifier
+	notifications := api.Group("/notifications")
+	{
+		notifications.GET("", ListNotifications(services.Notification))
+		notifications.POST("/read", MarkAllNotificationsRead(services.Notification))
+		notifications.GET("/preferences", GetNotificationPreferences(services.Notification))
+		notifications.PUT("/preferences", UpdateNotificationPreferences(services.Notification))
+		notifications.POST("/:id/read", MarkNotificationRead(services.Notification))
+	}
+
+	// Registered routes with their roles and rate limits, for debugging deployments
+	routes := api.Group("/routes")
+	{
+		policies.requireScope(routes, RoleAdmin)
+		routes.GET("", ListRoutes(router, policies))
+	}
+
+	// Liveness, kept for existing clients
+	api.GET("/health", Liveness())
 }
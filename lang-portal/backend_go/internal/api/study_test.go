@@ -0,0 +1,154 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/app"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+// createActivity creates a study activity through the API, rejecting repeated
+// reviews of a word in a session when dedup is "reject"
+func createActivity(t *testing.T, s *apitest.Server, dedup string) models.StudyActivity {
+	t.Helper()
+	resp := s.Post("/api/v1/study/activities", map[string]interface{}{
+		"name":          "Flashcards",
+		"description":   "Flip through the words of a group",
+		"thumbnail_url": "/thumbnails/flashcards.png",
+		"review_dedup":  dedup,
+	}).Status(http.StatusCreated)
+	return apitest.JSON[models.StudyActivity](resp)
+}
+
+func TestStudyAPI_SessionLifecycle(t *testing.T) {
+	s := apitest.New(t)
+	water := createWord(t, s, "水", "mizu", "water")
+	fire := createWord(t, s, "火", "hi", "fire")
+	group := createGroup(t, s, "Elements", water, fire)
+	activity := createActivity(t, s, "")
+
+	session := apitest.JSON[models.StudySession](s.Post("/api/v1/study/sessions", map[string]interface{}{
+		"group_id":          group.ID,
+		"study_activity_id": activity.ID,
+	}).Status(http.StatusCreated))
+	require.NotZero(t, session.ID)
+	reviewsPath := fmt.Sprintf("/api/v1/study/sessions/%d/reviews", session.ID)
+
+	s.Post(reviewsPath, map[string]interface{}{"word_id": water.ID, "correct": true}).Status(http.StatusCreated)
+	s.Post(reviewsPath, map[string]interface{}{"word_id": fire.ID, "correct": false}).Status(http.StatusCreated)
+
+	reviews := apitest.JSON[middleware.PaginatedResponse[service.WordReview]](s.Get(reviewsPath).Status(http.StatusOK))
+	require.Len(t, reviews.Items, 2)
+	assert.ElementsMatch(t, []uint{water.ID, fire.ID}, []uint{reviews.Items[0].WordID, reviews.Items[1].WordID})
+
+	info := apitest.JSON[service.StudySessionInfo](s.Get(fmt.Sprintf("/api/v1/study/sessions/%d", session.ID)).Status(http.StatusOK))
+	assert.Equal(t, "Flashcards", info.ActivityName)
+	assert.Equal(t, "Elements", info.GroupName)
+	assert.Equal(t, 2, info.ReviewItemsCount)
+	assert.InDelta(t, 50, info.SuccessRate, 0.01)
+
+	summary := apitest.JSON[service.SessionSummary](s.Post(fmt.Sprintf("/api/v1/study/sessions/%d/complete", session.ID), nil).Status(http.StatusOK))
+	assert.Equal(t, session.ID, summary.StudySessionID)
+	assert.NotNil(t, summary.CompletedAt)
+	assert.EqualValues(t, 2, summary.Reviews)
+	assert.EqualValues(t, 1, summary.CorrectReviews)
+
+	detail := apitest.JSON[service.WordDetail](s.Get(fmt.Sprintf("/api/v1/words/%d", water.ID)).Status(http.StatusOK))
+	assert.EqualValues(t, 1, detail.StudyStats.CorrectCount)
+}
+
+func TestStudyAPI_RejectsInvalidSessions(t *testing.T) {
+	s := apitest.New(t)
+	water := createWord(t, s, "水", "mizu", "water")
+	empty := createGroup(t, s, "Empty")
+	group := createGroup(t, s, "Elements", water)
+	activity := createActivity(t, s, "reject")
+
+	resp := s.Post("/api/v1/study/sessions", map[string]interface{}{
+		"group_id":          empty.ID,
+		"study_activity_id": activity.ID,
+	}).Status(http.StatusBadRequest)
+	assert.Equal(t, "Group has 0 words; a study session needs at least 1", resp.Error())
+
+	s.Post("/api/v1/study/sessions", map[string]interface{}{
+		"group_id":          999,
+		"study_activity_id": activity.ID,
+	}).Status(http.StatusNotFound)
+
+	session := apitest.JSON[models.StudySession](s.Post("/api/v1/study/sessions", map[string]interface{}{
+		"group_id":          group.ID,
+		"study_activity_id": activity.ID,
+	}).Status(http.StatusCreated))
+	reviewsPath := fmt.Sprintf("/api/v1/study/sessions/%d/reviews", session.ID)
+
+	s.Post(reviewsPath, map[string]interface{}{"word_id": water.ID, "correct": true}).Status(http.StatusCreated)
+	s.Post(reviewsPath, map[string]interface{}{"word_id": water.ID, "correct": true}).Status(http.StatusConflict)
+	s.Post("/api/v1/study/sessions/999/reviews", map[string]interface{}{"word_id": water.ID, "correct": true}).Status(http.StatusNotFound)
+}
+
+func TestStudyAPI_ExpiredSessionsAreClosed(t *testing.T) {
+	s := apitest.New(t)
+	water := createWord(t, s, "水", "mizu", "water")
+	group := createGroup(t, s, "Elements", water)
+	activity := createActivity(t, s, "")
+
+	session := apitest.JSON[models.StudySession](s.Post("/api/v1/study/sessions", map[string]interface{}{
+		"group_id":          group.ID,
+		"study_activity_id": activity.ID,
+	}).Status(http.StatusCreated))
+	require.NoError(t, s.DB.Model(&models.StudySession{}).Where("id = ?", session.ID).Update("expired_at", time.Now().UTC()).Error)
+
+	resp := s.Post(fmt.Sprintf("/api/v1/study/sessions/%d/reviews", session.ID), map[string]interface{}{"word_id": water.ID, "correct": true}).Status(http.StatusConflict)
+	assert.Equal(t, middleware.CodeConflict, resp.ErrorCode())
+	s.Post(fmt.Sprintf("/api/v1/study/sessions/%d/complete", session.ID), nil).Status(http.StatusConflict)
+
+	var stored models.StudySession
+	require.NoError(t, s.DB.Preload("Reviews").First(&stored, session.ID).Error)
+	assert.Empty(t, stored.Reviews)
+	assert.Nil(t, stored.CompletedAt, "an expired session is never completed as well")
+}
+
+func TestStudyAPI_SessionBundle(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "水.mp3"), []byte("ID3"), 0o644))
+	s := apitest.New(t, app.WithAudioSource(service.AudioDir(dir)))
+	water := createWord(t, s, "水", "mizu", "water")
+	fire := createWord(t, s, "火", "hi", "fire")
+	group := createGroup(t, s, "Elements", water, fire)
+	activity := createActivity(t, s, "")
+	s.Put("/api/v1/settings/preferences", map[string]interface{}{"review_order": service.ReviewOrderOldestFirst}).Status(http.StatusOK)
+
+	session := apitest.JSON[models.StudySession](s.Post("/api/v1/study/sessions", map[string]interface{}{
+		"group_id":          group.ID,
+		"study_activity_id": activity.ID,
+	}).Status(http.StatusCreated))
+	path := fmt.Sprintf("/api/v1/study/sessions/%d/bundle", session.ID)
+
+	bundle := apitest.JSON[service.SessionBundle](s.Get(path).Status(http.StatusOK))
+	require.Len(t, bundle.Words, 2)
+	assert.Equal(t, 2, bundle.TotalWords)
+	assert.False(t, bundle.Truncated)
+	assert.Equal(t, service.ReviewOrderOldestFirst, bundle.Settings.ReviewOrder)
+	assert.Equal(t, fmt.Sprintf("/api/v1/study/sessions/%d/reviews", session.ID), bundle.SyncURL)
+	audio := map[uint]string{}
+	for _, w := range bundle.Words {
+		audio[w.ID] = w.AudioURL
+	}
+	assert.Equal(t, map[uint]string{water.ID: fmt.Sprintf("/api/v1/words/%d/audio", water.ID), fire.ID: ""}, audio)
+	s.Get(audio[water.ID]).Status(http.StatusOK)
+
+	s.Get(path + "?order=sideways").Status(http.StatusBadRequest)
+	s.Get("/api/v1/study/sessions/999/bundle").Status(http.StatusNotFound)
+	s.Get("/api/v1/study/sessions/abc/bundle").Status(http.StatusBadRequest)
+}
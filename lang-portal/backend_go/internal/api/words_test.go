@@ -0,0 +1,210 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/api"
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/app"
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+// createWord creates a word through the API and returns it as stored
+func createWord(t *testing.T, s *apitest.Server, japanese, romaji, english string) models.Word {
+	t.Helper()
+	resp := s.Post("/api/v1/words", map[string]interface{}{
+		"japanese": japanese,
+		"romaji":   romaji,
+		"english":  english,
+		"parts":    []string{"noun"},
+	}).Status(http.StatusCreated)
+	return apitest.JSON[models.Word](resp)
+}
+
+func TestWordsAPI_CreateGetAndList(t *testing.T) {
+	s := apitest.New(t)
+
+	created := createWord(t, s, "水", "mizu", "water")
+	require.NotZero(t, created.ID)
+	assert.Equal(t, models.StringSlice{"noun"}, created.Parts)
+
+	detail := apitest.JSON[service.WordDetail](s.Get(fmt.Sprintf("/api/v1/words/%d", created.ID)).Status(http.StatusOK))
+	assert.Equal(t, "水", detail.Japanese)
+	assert.Equal(t, "mizu", detail.Romaji)
+	assert.Equal(t, "water", detail.English)
+	assert.Zero(t, detail.StudyStats.CorrectCount)
+
+	createWord(t, s, "火", "hi", "fire")
+	page := apitest.JSON[middleware.PaginatedResponse[service.Word]](s.Get("/api/v1/words?sort_by=english&order=asc&page_size=1").Status(http.StatusOK))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "fire", page.Items[0].English)
+	assert.Equal(t, 2, page.Pagination.TotalItems)
+	assert.Equal(t, 2, page.Pagination.TotalPages)
+}
+
+func TestWordsAPI_PatchAndDelete(t *testing.T) {
+	s := apitest.New(t)
+	word := createWord(t, s, "水", "mizu", "water")
+	path := fmt.Sprintf("/api/v1/words/%d", word.ID)
+
+	patched := apitest.JSON[service.WordDetail](s.Patch(path, map[string]interface{}{"english": "cold water"}).Status(http.StatusOK))
+	assert.Equal(t, "cold water", patched.English)
+	assert.Equal(t, "mizu", patched.Romaji)
+
+	s.Delete(path).Status(http.StatusNoContent)
+	s.Get(path).Status(http.StatusNotFound)
+}
+
+func TestWordsAPI_RejectsInvalidRequests(t *testing.T) {
+	s := apitest.New(t)
+
+	s.Get("/api/v1/words/abc").Status(http.StatusBadRequest)
+	s.Post("/api/v1/words", map[string]interface{}{"japanese": "水"}).Status(http.StatusBadRequest)
+
+	resp := s.Post("/api/v1/words", map[string]interface{}{
+		"japanese": "水",
+		"romaji":   "mizu",
+		"english":  "water",
+		"parts":    []string{"gerund"},
+	}).Status(http.StatusBadRequest)
+	assert.Equal(t, "Unknown parts of speech: gerund", resp.Error())
+}
+
+func TestWordsAPI_LegacyPathsAreDeprecated(t *testing.T) {
+	s := apitest.New(t)
+	word := createWord(t, s, "水", "mizu", "water")
+
+	resp := s.Get(fmt.Sprintf("/api/words/%d", word.ID)).Status(http.StatusOK)
+	assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+	assert.Equal(t, "水", apitest.JSON[service.WordDetail](resp).Japanese)
+}
+
+func TestWordsAPI_BodyLimits(t *testing.T) {
+	limits := api.DefaultBodyLimits()
+	limits.JSON = 256
+	limits.Import = 4 << 10
+	s := apitest.New(t, app.WithBodyLimits(limits))
+
+	long := strings.Repeat("w", 512)
+	resp := s.Post("/api/v1/words", map[string]interface{}{"japanese": "水", "romaji": "mizu", "english": long}).Status(http.StatusRequestEntityTooLarge)
+	assert.Equal(t, "Request bodies are limited to 256 bytes", resp.Error())
+
+	// Uploads take their own limit, under every prefix they are served at
+	for _, prefix := range []string{api.APIV1Prefix, api.LegacyAPIPrefix} {
+		resp = s.Post(prefix+"/words/import?dry_run=true", long)
+		assert.NotEqual(t, http.StatusRequestEntityTooLarge, resp.StatusCode, prefix)
+		s.Post(prefix+"/words/import?dry_run=true", strings.Repeat("w", 5<<10)).Status(http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestWordsAPI_ErrorEnvelope(t *testing.T) {
+	s := apitest.New(t)
+
+	word := createWord(t, s, "水", "mizu", "water")
+	resp := s.Post(fmt.Sprintf("/api/v1/words/%d/sentences", word.ID), map[string]interface{}{"english": "I drink water."}).Status(http.StatusBadRequest)
+	body := apitest.JSON[middleware.ErrorResponse](resp)
+	assert.Equal(t, middleware.CodeInvalidInput, body.Error.Code)
+	assert.Equal(t, resp.Header.Get(middleware.RequestIDHeader), body.Error.RequestID)
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "japanese", "rule": "required"}}, body.Error.Details)
+
+	resp = s.Get("/api/v1/words/999").Status(http.StatusNotFound)
+	assert.Equal(t, middleware.CodeNotFound, resp.ErrorCode())
+
+	resp = s.Get("/api/v1/nothing").Status(http.StatusNotFound)
+	assert.Equal(t, middleware.CodeNotFound, resp.ErrorCode())
+	assert.Equal(t, "No route for GET /api/v1/nothing", resp.Error())
+}
+
+func TestWordsAPI_MeaningLanguage(t *testing.T) {
+	s := apitest.New(t)
+	word := createWord(t, s, "水", "mizu", "water")
+	path := fmt.Sprintf("/api/v1/words/%d", word.ID)
+
+	detail := apitest.JSON[service.WordDetail](s.Get(path + "?lang=de").Status(http.StatusOK))
+	assert.Equal(t, "water", detail.Translation, "words without a German meaning fall back to English")
+	assert.Equal(t, models.LanguageEnglish, detail.TranslationLanguage)
+
+	s.Patch(path, map[string]interface{}{"german": "Wasser"}).Status(http.StatusOK)
+	detail = apitest.JSON[service.WordDetail](s.Get(path + "?lang=de").Status(http.StatusOK))
+	assert.Equal(t, "Wasser", detail.Translation)
+	assert.Equal(t, models.LanguageGerman, detail.TranslationLanguage)
+	assert.Equal(t, "water", detail.English)
+
+	page := apitest.JSON[middleware.PaginatedResponse[service.Word]](s.Get("/api/v1/words?lang=de").Status(http.StatusOK))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Wasser", page.Items[0].Translation)
+
+	v2 := apitest.JSON[api.WordDetailV2](s.Get(fmt.Sprintf("/api/v2/words/%d?lang=de", word.ID)).Status(http.StatusOK))
+	assert.Equal(t, "Wasser", v2.Translation)
+	assert.Equal(t, map[string]string{"de": "Wasser"}, v2.Translations)
+
+	s.Get(path + "?lang=fr").Status(http.StatusBadRequest)
+}
+
+func TestWordsAPI_LocalizedErrors(t *testing.T) {
+	s := apitest.New(t)
+	s.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+
+	resp := s.Get("/api/v1/words/999").Status(http.StatusNotFound)
+	assert.Equal(t, middleware.CodeNotFound, resp.ErrorCode(), "codes are not translated")
+	assert.Equal(t, "Wort nicht gefunden", resp.Error())
+	assert.Equal(t, "Ungültige Wort-ID", s.Get("/api/v1/words/abc").Status(http.StatusBadRequest).Error())
+
+	s.Header.Set("Accept-Language", "fr")
+	assert.Equal(t, "Word not found", s.Get("/api/v1/words/999").Status(http.StatusNotFound).Error())
+}
+
+// fixedLLM replies to every chat with the same text
+type fixedLLM string
+
+func (f fixedLLM) Chat(ctx context.Context, req llm.Request, onDelta func(string) error) (*llm.Response, error) {
+	return &llm.Response{Content: string(f), Usage: llm.Usage{PromptTokens: 50, CompletionTokens: 10}}, nil
+}
+
+func TestWordsAPI_ExplanationQuota(t *testing.T) {
+	router := llm.NewRouter(llm.RouterConfig{}, llm.Provider{Name: "fixed", Client: fixedLLM("Water to drink.")})
+	s := apitest.New(t, app.WithLanguageModel(router), app.WithAIQuota(service.AIQuota{Requests: 1}))
+	word := createWord(t, s, "水", "mizu", "water")
+	path := fmt.Sprintf("/api/v1/words/%d/explanation?level=beginner", word.ID)
+
+	s.Header.Set(middleware.ActorHeader, "alice")
+	s.Get(path).Status(http.StatusOK)
+
+	// The quota belongs to the client, not to the actor it claims to be
+	s.Header.Set(middleware.ActorHeader, "bob")
+	resp := s.Get(path).Status(http.StatusTooManyRequests)
+	assert.Equal(t, middleware.CodeRateLimited, resp.ErrorCode())
+}
+
+func TestWordsAPI_Transliterate(t *testing.T) {
+	s := apitest.New(t)
+	transliterate := func(text, to string) *apitest.Response {
+		return s.Get("/api/tools/transliterate?" + url.Values{"text": {text}, "to": {to}}.Encode())
+	}
+
+	type transliteration struct{ Text, To, Result string }
+	body := apitest.JSON[transliteration](transliterate("ラーメン o taberu", "hiragana").Status(http.StatusOK))
+	assert.Equal(t, "ラーメン o taberu", body.Text)
+	assert.Equal(t, "hiragana", body.To)
+	assert.Equal(t, "らーめん お たべる", body.Result)
+
+	body = apitest.JSON[transliteration](transliterate("sushi", "katakana").Status(http.StatusOK))
+	assert.Equal(t, "スシ", body.Result)
+	body = apitest.JSON[transliteration](transliterate("がっこう", "romaji").Status(http.StatusOK))
+	assert.Equal(t, "gakkou", body.Result)
+
+	assert.Equal(t, middleware.CodeInvalidInput, transliterate("sushi", "kanji").Status(http.StatusBadRequest).ErrorCode())
+	transliterate("", "romaji").Status(http.StatusBadRequest)
+	transliterate(strings.Repeat("a", 1001), "hiragana").Status(http.StatusBadRequest)
+}
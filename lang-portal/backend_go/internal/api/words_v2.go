@@ -0,0 +1,271 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The v2 words resource exposes the same WordService as /api/words using a
+// language-neutral schema. The service keeps the v1 field names, so the shims
+// below map term/reading/translation onto japanese/romaji/english.
+
+//go:embed openapi_v2.yaml
+var openAPIV2Spec []byte
+
+// WordV2 is the language-neutral representation of a word. Translation is
+// the meaning in the language asked for with the lang parameter, English by
+// default, in TranslationLanguage; Translations holds the meanings in the
+// other languages by language code.
+type WordV2 struct {
+	ID                  uint              `json:"id"`
+	Language            string            `json:"language"`
+	Term                string            `json:"term"`
+	Reading             string            `json:"reading"`
+	Script              string            `json:"script"`
+	ReadingScript       string            `json:"reading_script"`
+	Translation         string            `json:"translation"`
+	TranslationLanguage string            `json:"translation_language"`
+	Translations        map[string]string `json:"translations,omitempty"`
+	CorrectCount        int64             `json:"correct_count"`
+	WrongCount          int64             `json:"wrong_count"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+}
+
+// WordDetailV2 is the language-neutral representation of a word with its groups
+type WordDetailV2 struct {
+	WordV2
+	Groups []service.GroupInfo `json:"groups"`
+	// Version is sent back in If-Match with updates to detect concurrent changes
+	Version uint `json:"version"`
+}
+
+// WordInputV2 is the request body for creating or updating a word.
+// Translation is the English meaning; Translations holds the meanings in the
+// other languages by language code. Script and ReadingScript default to those
+// of the language.
+type WordInputV2 struct {
+	Language      string            `json:"language"`
+	Term          string            `json:"term" binding:"required"`
+	Reading       string            `json:"reading" binding:"required"`
+	Script        string            `json:"script"`
+	ReadingScript string            `json:"reading_script"`
+	Translation   string            `json:"translation" binding:"required"`
+	Translations  map[string]string `json:"translations"`
+	Parts         []string          `json:"parts" binding:"required,min=1"`
+}
+
+// wordToV2 maps a service word onto the v2 schema with its meaning in lang
+func wordToV2(w service.Word, lang string) WordV2 {
+	translation, translationLanguage := models.Meaning(w.English, w.German, lang)
+	return WordV2{
+		ID:                  w.ID,
+		Language:            w.Language,
+		Term:                w.Japanese,
+		Reading:             w.Romaji,
+		Script:              w.Script,
+		ReadingScript:       w.ReadingScript,
+		Translation:         translation,
+		TranslationLanguage: translationLanguage,
+		Translations:        translationsV2(w.German),
+		CorrectCount:        w.CorrectCount,
+		WrongCount:          w.WrongCount,
+		UpdatedAt:           w.UpdatedAt,
+	}
+}
+
+// wordDetailToV2 maps a service word detail onto the v2 schema with its
+// meaning in lang
+func wordDetailToV2(w *service.WordDetail, lang string) WordDetailV2 {
+	translation, translationLanguage := models.Meaning(w.English, w.German, lang)
+	return WordDetailV2{
+		WordV2: WordV2{
+			ID:                  w.ID,
+			Language:            w.Language,
+			Term:                w.Japanese,
+			Reading:             w.Romaji,
+			Script:              w.Script,
+			ReadingScript:       w.ReadingScript,
+			Translation:         translation,
+			TranslationLanguage: translationLanguage,
+			Translations:        translationsV2(w.German),
+			CorrectCount:        w.StudyStats.CorrectCount,
+			WrongCount:          w.StudyStats.WrongCount,
+			UpdatedAt:           w.UpdatedAt,
+		},
+		Groups:  w.Groups,
+		Version: w.Version,
+	}
+}
+
+// translationsV2 returns the meanings of a word besides English, nil when it
+// has none
+func translationsV2(german string) map[string]string {
+	if german == "" {
+		return nil
+	}
+	return map[string]string{models.LanguageGerman: german}
+}
+
+// toModel maps a v2 request body onto the storage model. The language is
+// checked by the word service.
+func (in WordInputV2) toModel() (models.Word, error) {
+	for lang := range in.Translations {
+		if lang != models.LanguageGerman {
+			return models.Word{}, service.NewServiceError(service.ErrCodeInvalidInput, "Unsupported translation language: "+lang, nil)
+		}
+	}
+	return models.Word{
+		Language:      in.Language,
+		Term:          in.Term,
+		Reading:       in.Reading,
+		Script:        in.Script,
+		ReadingScript: in.ReadingScript,
+		English:       in.Translation,
+		German:        in.Translations[models.LanguageGerman],
+		Parts:         models.StringSlice(in.Parts),
+	}, nil
+}
+
+// bindWordInputV2 parses a v2 request body, attaching any error to the context
+func bindWordInputV2(c *gin.Context) (models.Word, bool) {
+	var input WordInputV2
+	if err := c.ShouldBindJSON(&input); err != nil {
+		middleware.AbortInvalidBody(c, err)
+		return models.Word{}, false
+	}
+
+	word, err := input.toModel()
+	if err != nil {
+		c.Error(err)
+		return models.Word{}, false
+	}
+	return word, true
+}
+
+// V2 Word Handlers
+
+func ListWordsV2(s *service.WordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ginParams := middleware.GetPaginationParams(c)
+		lang, ok := meaningLanguage(c)
+		if !ok {
+			return
+		}
+		result, err := s.ListWords(c.Request.Context(), service.PaginationParams{
+			Page:     ginParams.Page,
+			PageSize: ginParams.PageSize,
+		}, wordListOptionsFromQuery(c))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		items := make([]WordV2, len(result.Items))
+		for i, item := range result.Items {
+			items[i] = wordToV2(item, lang)
+		}
+		etag := listETag(items, func(item WordV2) time.Time { return item.UpdatedAt }, result.TotalItems, ginParams)
+		if middleware.CheckNotModified(c, etag) {
+			return
+		}
+
+		c.JSON(http.StatusOK, middleware.NewPaginatedResponse(items, int(result.TotalItems), ginParams))
+	}
+}
+
+func GetWordV2(s *service.WordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
+			return
+		}
+		lang, ok := meaningLanguage(c)
+		if !ok {
+			return
+		}
+
+		word, err := s.GetWord(c.Request.Context(), uint(id))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+
+		etag := middleware.WeakETag(word.UpdatedAt, word.ID, word.StudyStats.CorrectCount, word.StudyStats.WrongCount, len(word.Groups), lang)
+		if middleware.CheckNotModified(c, etag) {
+			return
+		}
+
+		c.JSON(http.StatusOK, wordDetailToV2(word, lang))
+	}
+}
+
+func CreateWordV2(s *service.WordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		word, ok := bindWordInputV2(c)
+		if !ok {
+			return
+		}
+
+		if err := s.CreateWord(c.Request.Context(), &word); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, wordToV2(service.Word{
+			ID:            word.ID,
+			Language:      word.Language,
+			Japanese:      word.Term,
+			Romaji:        word.Reading,
+			Script:        word.Script,
+			ReadingScript: word.ReadingScript,
+			English:       word.English,
+			German:        word.German,
+			UpdatedAt:     word.UpdatedAt,
+		}, ""))
+	}
+}
+
+func UpdateWordV2(s *service.WordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			middleware.AbortInvalidInput(c, "Invalid word ID")
+			return
+		}
+
+		word, ok := bindWordInputV2(c)
+		if !ok || !bindIfMatch(c, &word.Version) {
+			return
+		}
+
+		if err := s.UpdateWord(c.Request.Context(), uint(id), &word); err != nil {
+			c.Error(err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListLanguagesV2 lists the target languages words can be kept in
+func ListLanguagesV2() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"items": models.TargetLanguages})
+	}
+}
+
+// GetOpenAPIV2 serves the OpenAPI document describing the v2 resources
+func GetOpenAPIV2() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/yaml", openAPIV2Spec)
+	}
+}
@@ -0,0 +1,162 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/api"
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil/apitest"
+)
+
+func TestWordsV2API_CreateAndGet(t *testing.T) {
+	s := apitest.New(t)
+
+	created := apitest.JSON[api.WordV2](s.Post("/api/v2/words", map[string]interface{}{
+		"term":         "水",
+		"reading":      "mizu",
+		"translation":  "water",
+		"translations": map[string]string{"de": "Wasser"},
+		"parts":        []string{"noun"},
+	}).Status(http.StatusCreated))
+	require.NotZero(t, created.ID)
+	assert.Equal(t, models.DefaultLanguage, created.Language)
+	assert.Equal(t, "水", created.Term)
+	assert.Equal(t, "mizu", created.Reading)
+	assert.Equal(t, "water", created.Translation)
+	assert.Equal(t, models.LanguageEnglish, created.TranslationLanguage)
+	assert.Equal(t, map[string]string{"de": "Wasser"}, created.Translations)
+
+	assert.Equal(t, "Jpan", created.Script, "scripts default to those of the language")
+	assert.Equal(t, "Latn", created.ReadingScript)
+
+	var stored models.Word
+	require.NoError(t, s.DB.First(&stored, created.ID).Error)
+	assert.Equal(t, "水", stored.Term)
+	assert.Equal(t, "mizu", stored.Reading)
+	assert.Equal(t, "Jpan", stored.Script)
+	assert.Equal(t, "Wasser", stored.German)
+	assert.Equal(t, models.StringSlice{"noun"}, stored.Parts)
+
+	detail := apitest.JSON[api.WordDetailV2](s.Get(fmt.Sprintf("/api/v2/words/%d", created.ID)).Status(http.StatusOK))
+	assert.Equal(t, created.ID, detail.ID)
+	assert.NotZero(t, detail.Version)
+	assert.Empty(t, detail.Groups)
+
+	s.Get("/api/v2/words/999").Status(http.StatusNotFound)
+}
+
+func TestWordsV2API_Scripts(t *testing.T) {
+	s := apitest.New(t)
+
+	mul := apitest.JSON[api.WordV2](s.Post("/api/v2/words", map[string]interface{}{
+		"language":    "ko",
+		"term":        "물",
+		"reading":     "mul",
+		"translation": "water",
+		"parts":       []string{"noun"},
+	}).Status(http.StatusCreated))
+	assert.Equal(t, "Kore", mul.Script)
+	assert.Equal(t, "Latn", mul.ReadingScript)
+
+	// A word may name the scripts it is written in
+	hangul := apitest.JSON[api.WordV2](s.Post("/api/v2/words", map[string]interface{}{
+		"language":    "ko",
+		"term":        "불",
+		"reading":     "bul",
+		"script":      "Hang",
+		"translation": "fire",
+		"parts":       []string{"noun"},
+	}).Status(http.StatusCreated))
+	assert.Equal(t, "Hang", hangul.Script)
+	assert.Equal(t, "Latn", hangul.ReadingScript)
+
+	// Updates keep the scripts unless they name others
+	path := fmt.Sprintf("/api/v2/words/%d", hangul.ID)
+	s.Put(path, map[string]interface{}{
+		"term": "불", "reading": "bul", "translation": "flame", "parts": []string{"noun"},
+	}).Status(http.StatusNoContent)
+	updated := apitest.JSON[api.WordDetailV2](s.Get(path).Status(http.StatusOK))
+	assert.Equal(t, "Hang", updated.Script)
+	assert.Equal(t, "flame", updated.Translation)
+
+	resp := s.Put(path, map[string]interface{}{
+		"term": "불", "reading": "bul", "script": "hangul", "translation": "flame", "parts": []string{"noun"},
+	}).Status(http.StatusBadRequest)
+	assert.Equal(t, middleware.CodeInvalidInput, resp.ErrorCode())
+}
+
+func TestWordsV2API_Update(t *testing.T) {
+	s := apitest.New(t)
+	word := createWord(t, s, "水", "mizu", "water")
+	path := fmt.Sprintf("/api/v2/words/%d", word.ID)
+	input := map[string]interface{}{
+		"term":        "水",
+		"reading":     "mizu",
+		"translation": "cold water",
+		"parts":       []string{"noun"},
+	}
+
+	version := apitest.JSON[api.WordDetailV2](s.Get(path).Status(http.StatusOK)).Version
+	s.Header.Set("If-Match", fmt.Sprintf(`"%d"`, version))
+	s.Put(path, input).Status(http.StatusNoContent)
+
+	detail := apitest.JSON[api.WordDetailV2](s.Get(path).Status(http.StatusOK))
+	assert.Equal(t, "cold water", detail.Translation)
+	assert.Greater(t, detail.Version, version)
+
+	// The version sent is out of date now
+	resp := s.Put(path, input).Status(http.StatusConflict)
+	assert.Equal(t, middleware.CodeConflict, resp.ErrorCode())
+
+	s.Header.Del("If-Match")
+	s.Put("/api/v2/words/999", input).Status(http.StatusNotFound)
+	s.Put("/api/v2/words/abc", input).Status(http.StatusBadRequest)
+}
+
+func TestWordsV2API_Validation(t *testing.T) {
+	s := apitest.New(t)
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{"term": "水", "reading": "mizu", "translation": "water", "parts": []string{"noun"}}
+	}
+	invalidField := func(body map[string]interface{}) middleware.FieldError {
+		t.Helper()
+		resp := s.Post("/api/v2/words", body).Status(http.StatusBadRequest)
+		envelope := apitest.JSON[struct {
+			Error struct {
+				Code    string                  `json:"code"`
+				Details []middleware.FieldError `json:"details"`
+			} `json:"error"`
+		}](resp)
+		assert.Equal(t, middleware.CodeInvalidInput, envelope.Error.Code)
+		require.Len(t, envelope.Error.Details, 1)
+		return envelope.Error.Details[0]
+	}
+
+	body := valid()
+	delete(body, "parts")
+	assert.Equal(t, middleware.FieldError{Field: "parts", Rule: "required"}, invalidField(body), "parts are required")
+
+	body = valid()
+	body["parts"] = []string{}
+	assert.Equal(t, middleware.FieldError{Field: "parts", Rule: "min"}, invalidField(body))
+
+	body = valid()
+	delete(body, "term")
+	assert.Equal(t, middleware.FieldError{Field: "term", Rule: "required"}, invalidField(body))
+
+	body = valid()
+	body["parts"] = []string{"gerund"}
+	assert.Equal(t, middleware.CodeInvalidInput, s.Post("/api/v2/words", body).Status(http.StatusBadRequest).ErrorCode())
+
+	body = valid()
+	body["language"] = "tlh"
+	s.Post("/api/v2/words", body).Status(http.StatusBadRequest)
+
+	s.Post("/api/v2/words", valid()).Status(http.StatusCreated)
+}
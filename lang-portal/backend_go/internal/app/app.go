@@ -0,0 +1,512 @@
+// Package app assembles the language portal: the repositories over a migrated
+// database, the services on top of them, the background jobs, the router and
+// the HTTP server. The server command and integration tests build the same
+// graph, configured through options.
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"gorm.io/gorm"
+
+	"lang-portal/backend_go/internal/api"
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/database"
+	"lang-portal/backend_go/internal/dictionary"
+	"lang-portal/backend_go/internal/embeddings"
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/mail"
+	"lang-portal/backend_go/internal/ocr"
+	"lang-portal/backend_go/internal/repository"
+	"lang-portal/backend_go/internal/service"
+	"lang-portal/backend_go/internal/speech"
+	"lang-portal/backend_go/internal/telemetry"
+	"lang-portal/backend_go/internal/web"
+)
+
+// DefaultAddr is the address the server listens on unless WithAddr is given
+const DefaultAddr = ":8081"
+
+// config collects the options. Everything left unset falls back to a default
+// that works without external services.
+type config struct {
+	logger          *log.Logger
+	addr            string
+	dbPath          string
+	serviceName     string
+	model           *llm.Router
+	quota           service.AIQuota
+	xpRules         service.XPRules
+	embeddings      embeddings.Provider
+	speech          speech.Recognizer
+	dictionary      dictionary.Provider
+	textReader      ocr.Reader
+	strokes         service.StrokeSource
+	audio           service.AudioSource
+	mail            mail.Sender
+	mailFrom        string
+	publicURL       string
+	launchKey       []byte
+	adminKey        string
+	certificateKey  []byte
+	reviewRetention int
+	sessionExpiry   time.Duration
+	rateLimits      middleware.RateLimitStore
+	cache           cache.Cache
+	events          events.Broker
+	frontend        fs.FS
+	tls             *TLS
+	bodyLimits      api.BodyLimits
+}
+
+// Option configures the application built by New
+type Option func(*config)
+
+// WithLogger sets the logger of the services and background jobs
+func WithLogger(logger *log.Logger) Option {
+	return func(c *config) { c.logger = logger }
+}
+
+// WithAddr sets the address the HTTP server listens on
+func WithAddr(addr string) Option {
+	return func(c *config) { c.addr = addr }
+}
+
+// WithDBPath sets the database file checked by /readyz. Without it only the
+// connection is checked, as for in-memory databases.
+func WithDBPath(path string) Option {
+	return func(c *config) { c.dbPath = path }
+}
+
+// WithServiceName sets the service name of the request spans
+func WithServiceName(name string) Option {
+	return func(c *config) { c.serviceName = name }
+}
+
+// WithLanguageModel enables the AI features, using model for generation
+func WithLanguageModel(model *llm.Router) Option {
+	return func(c *config) { c.model = model }
+}
+
+// WithAIQuota caps the daily AI usage per client, see middleware.Client
+func WithAIQuota(quota service.AIQuota) Option {
+	return func(c *config) { c.quota = quota }
+}
+
+// WithXPRules sets the experience points awarded for study
+func WithXPRules(rules service.XPRules) Option {
+	return func(c *config) { c.xpRules = rules }
+}
+
+// WithEmbeddings sets the provider used to find related words
+func WithEmbeddings(provider embeddings.Provider) Option {
+	return func(c *config) { c.embeddings = provider }
+}
+
+// WithSpeechRecognizer sets the recognizer grading spoken answers
+func WithSpeechRecognizer(recognizer speech.Recognizer) Option {
+	return func(c *config) { c.speech = recognizer }
+}
+
+// WithDictionary sets the provider of dictionary lookups
+func WithDictionary(provider dictionary.Provider) Option {
+	return func(c *config) { c.dictionary = provider }
+}
+
+// WithTextReader sets the reader extracting words from photographed text
+func WithTextReader(reader ocr.Reader) Option {
+	return func(c *config) { c.textReader = reader }
+}
+
+// WithStrokeSource sets the source of kanji stroke order diagrams
+func WithStrokeSource(source service.StrokeSource) Option {
+	return func(c *config) { c.strokes = source }
+}
+
+// WithAudioSource sets the source of word pronunciations
+func WithAudioSource(source service.AudioSource) Option {
+	return func(c *config) { c.audio = source }
+}
+
+// WithMail sends the reminders by email as well, from the address from and
+// linking to the frontend at publicURL
+func WithMail(sender mail.Sender, from, publicURL string) Option {
+	return func(c *config) {
+		c.mail = sender
+		c.mailFrom = from
+		c.publicURL = publicURL
+	}
+}
+
+// WithLaunchKey sets the key verifying study activity launch tokens
+func WithLaunchKey(key []byte) Option {
+	return func(c *config) { c.launchKey = key }
+}
+
+// WithBootstrapAdminKey accepts key as an API key granting the admin scope,
+// which creates the first stored keys
+func WithBootstrapAdminKey(key string) Option {
+	return func(c *config) { c.adminKey = key }
+}
+
+// WithCertificateKey sets the key signing group completion certificates
+func WithCertificateKey(key []byte) Option {
+	return func(c *config) { c.certificateKey = key }
+}
+
+// WithReviewRetention archives reviews older than months; zero keeps them all
+func WithReviewRetention(months int) Option {
+	return func(c *config) { c.reviewRetention = months }
+}
+
+// WithSessionExpiry closes study sessions idle for longer than idle; zero
+// keeps them open
+func WithSessionExpiry(idle time.Duration) Option {
+	return func(c *config) { c.sessionExpiry = idle }
+}
+
+// WithRateLimitStore keeps the rate limits in store, such as Redis shared by
+// all replicas, instead of in memory. The store is owned by the caller.
+func WithRateLimitStore(store middleware.RateLimitStore) Option {
+	return func(c *config) { c.rateLimits = store }
+}
+
+// WithCache caches the expensive aggregates in c, such as Redis shared by all
+// replicas, instead of in memory
+func WithCache(c cache.Cache) Option {
+	return func(cfg *config) { cfg.cache = c }
+}
+
+// WithEvents buffers the change events for live dashboards in broker, such as
+// a RedisHub shared by all replicas, instead of in memory. Shutdown closes it.
+func WithEvents(broker events.Broker) Option {
+	return func(c *config) { c.events = broker }
+}
+
+// WithBodyLimits caps the size of request bodies, api.DefaultBodyLimits by
+// default
+func WithBodyLimits(limits api.BodyLimits) Option {
+	return func(c *config) { c.bodyLimits = limits }
+}
+
+// WithFrontend serves the built frontend in assets under / next to the API
+func WithFrontend(assets fs.FS) Option {
+	return func(c *config) { c.frontend = assets }
+}
+
+// job is a background job running until shutdown
+type job struct {
+	run func(ctx context.Context)
+	// flush, if set, runs once the job has stopped
+	flush func(ctx context.Context) error
+	// stopTimeout is logged when the job does not stop within the grace period
+	stopTimeout string
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// App is the assembled application. Its database is owned by the caller.
+type App struct {
+	Services *api.Services
+	Router   *gin.Engine
+	Server   *http.Server
+	// Redirect is the plain HTTP server redirecting to HTTPS, nil unless TLS
+	// is configured with a redirect address
+	Redirect *http.Server
+
+	logger *log.Logger
+	events events.Broker
+	jobs   []*job
+	tls    *TLS
+}
+
+// New builds the application on db, which must already be migrated
+func New(db *gorm.DB, opts ...Option) (*App, error) {
+	if db == nil {
+		return nil, fmt.Errorf("app: database is required")
+	}
+	cfg := config{
+		logger:      log.Default(),
+		addr:        DefaultAddr,
+		serviceName: telemetry.DefaultServiceName,
+		xpRules:     service.DefaultXPRules(),
+		cache:       cache.NewMemory(),
+		events:      events.NewHub(events.DefaultBufferSize),
+		bodyLimits:  api.DefaultBodyLimits(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.mail != nil && (cfg.mailFrom == "" || cfg.publicURL == "") {
+		return nil, fmt.Errorf("app: sending mail needs a from address and a public URL")
+	}
+	if cfg.tls != nil {
+		if err := cfg.tls.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Initialize repositories
+	wordRepo := repository.NewWordRepository(db)
+	groupRepo := repository.NewGroupRepository(db)
+	studyRepo := repository.NewStudyRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	settingRepo := repository.NewSettingRepository(db)
+	shareRepo := repository.NewShareRepository(db)
+	goalRepo := repository.NewGoalRepository(db)
+	kanjiRepo := repository.NewKanjiRepository(db)
+	archiveRepo := repository.NewArchiveRepository(db)
+	sentenceRepo := repository.NewSentenceRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	tutorRepo := repository.NewTutorRepository(db)
+	promptRepo := repository.NewPromptRepository(db)
+	aiUsageRepo := repository.NewAIUsageRepository(db)
+	counterRepo := repository.NewCounterRepository(db)
+	cohortRepo := repository.NewCohortRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	summaryRepo := repository.NewDailySummaryRepository(db)
+	reviewArchiveRepo := repository.NewReviewArchiveRepository(db)
+	xpRepo := repository.NewXPRepository(db)
+
+	// Initialize services
+	baseService := service.NewBaseService(wordRepo, groupRepo, studyRepo, auditRepo, settingRepo).
+		WithCache(cfg.cache).
+		WithEvents(cfg.events).
+		WithEmbeddings(cfg.embeddings).
+		WithPrompts(promptRepo).
+		WithAIUsage(aiUsageRepo, cfg.quota).
+		WithXP(xpRepo, cfg.xpRules).
+		WithLogger(cfg.logger)
+	if cfg.model != nil {
+		baseService.WithLLM(cfg.model)
+	}
+	services := &api.Services{
+		Dashboard: service.NewDashboardService(baseService),
+		Word:      service.NewWordService(baseService),
+		Group:     service.NewGroupService(baseService),
+		Study: service.NewStudyService(baseService).
+			WithLaunchKey(cfg.launchKey).
+			WithSpeechRecognizer(cfg.speech).
+			WithAudioSource(cfg.audio).
+			WithGoals(goalRepo),
+		Search:      service.NewSearchService(baseService),
+		Audit:       service.NewAuditService(baseService),
+		Share:       service.NewShareService(baseService, shareRepo),
+		Preferences: service.NewPreferencesService(baseService),
+		Health:      service.NewHealthService(healthChecks(db, cfg.dbPath, cfg.cache, cfg.events, cfg.rateLimits, cfg.model)...),
+		Events:      service.NewEventService(baseService),
+		Goal: service.NewGoalService(baseService, goalRepo).
+			WithSigningKey(cfg.certificateKey),
+		Kanji: service.NewKanjiService(baseService, kanjiRepo).
+			WithStrokeSource(cfg.strokes),
+		Dictionary: service.NewDictionaryService(baseService, cfg.dictionary).
+			WithTextReader(cfg.textReader),
+		Archive:  service.NewArchiveService(baseService, archiveRepo),
+		Sentence: service.NewSentenceService(baseService, sentenceRepo),
+		Audio: service.NewAudioService(baseService).
+			WithAudioSource(cfg.audio),
+		APIKey:       service.NewAPIKeyService(baseService, apiKeyRepo).WithBootstrapKey(cfg.adminKey),
+		Tutor:        service.NewTutorService(baseService, tutorRepo),
+		Prompt:       service.NewPromptService(baseService),
+		AI:           service.NewAIService(baseService),
+		Counter:      service.NewCounterService(baseService, counterRepo),
+		Cohort:       service.NewCohortService(baseService, cohortRepo),
+		Notification: service.NewNotificationService(baseService, notificationRepo),
+		DailySummary: service.NewDailySummaryService(baseService, summaryRepo),
+	}
+
+	a := &App{
+		Services: services,
+		logger:   cfg.logger,
+		events:   cfg.events,
+	}
+
+	// Deliver the session events in the outbox to registered study apps; what
+	// the last requests recorded is delivered once polling has stopped
+	callbacks := service.NewActivityCallbacks(baseService, outboxRepo, nil, cfg.logger)
+	a.jobs = append(a.jobs, &job{
+		run:         callbacks.Run,
+		flush:       callbacks.Flush,
+		stopTimeout: "Activity callbacks did not stop in time",
+	})
+
+	// Fill the learners' inboxes from their study
+	notifier := service.NewNotifier(baseService, notificationRepo, cfg.logger)
+	if cfg.mail != nil {
+		notifier.WithMail(cfg.mail, cfg.mailFrom, cfg.publicURL)
+	}
+	a.jobs = append(a.jobs, &job{run: notifier.Run, stopTimeout: "Notifier did not stop in time"})
+
+	// Summarize each day's study once it has ended
+	summarizer := service.NewDailySummarizer(baseService, summaryRepo, cfg.logger)
+	a.jobs = append(a.jobs, &job{run: summarizer.Run, stopTimeout: "Daily summary job did not stop in time"})
+
+	// Repair review totals that drifted from the reviews
+	statsRecomputer := service.NewWordStatsRecomputer(baseService, cfg.logger)
+	a.jobs = append(a.jobs, &job{run: statsRecomputer.Run, stopTimeout: "Word stats job did not stop in time"})
+
+	// Archive reviews past the retention period
+	if cfg.reviewRetention > 0 {
+		archiver := service.NewReviewArchiver(baseService, reviewArchiveRepo, cfg.reviewRetention, cfg.logger)
+		a.jobs = append(a.jobs, &job{run: archiver.Run, stopTimeout: "Review archiver did not stop in time"})
+	}
+
+	// Close study sessions left open without reviews
+	if cfg.sessionExpiry > 0 {
+		expirer := service.NewSessionExpirer(baseService, cfg.sessionExpiry, cfg.logger)
+		a.jobs = append(a.jobs, &job{run: expirer.Run, stopTimeout: "Session expiry job did not stop in time"})
+	}
+
+	// Initialize router with middleware
+	router := gin.New() // Use gin.New() instead of gin.Default() to have more control over middleware
+
+	// Add security and stability middleware
+	router.Use(middleware.Recovery())                // Handle panics
+	router.Use(middleware.RequestID())               // Tag the request and its queries with an ID
+	router.Use(otelgin.Middleware(cfg.serviceName))  // Start a span per request
+	router.Use(middleware.SecurityHeaders())         // Add security headers
+	router.Use(middleware.CORS())                    // Handle CORS
+	router.Use(middleware.RequestLogger())           // Log requests
+	router.Use(middleware.Timeout(30 * time.Second)) // Request timeout
+	router.Use(gin.Logger())                         // Gin's built-in logger
+
+	// The routes come with their rate limits, the global one of 100 requests
+	// a second with bursts of 200 included
+	api.RegisterRoutes(router, services, cfg.rateLimits, cfg.bodyLimits)
+	// Unknown routes get the app's pages when the frontend is served and a
+	// JSON error otherwise
+	if cfg.frontend != nil {
+		router.NoRoute(web.Handler(cfg.frontend), middleware.NotFound())
+	} else {
+		router.NoRoute(middleware.NotFound())
+	}
+	a.Router = router
+
+	// Create HTTP server with timeouts
+	a.Server = &http.Server{
+		Addr:         cfg.addr,
+		Handler:      router,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	if cfg.tls != nil {
+		a.configureTLS(cfg.tls)
+	}
+	return a, nil
+}
+
+// Start starts the background jobs. They run until Shutdown.
+func (a *App) Start() {
+	for _, j := range a.jobs {
+		ctx, cancel := context.WithCancel(context.Background())
+		j.cancel = cancel
+		j.done = make(chan struct{})
+		go func(j *job) {
+			defer close(j.done)
+			j.run(ctx)
+		}(j)
+	}
+}
+
+// Shutdown stops the server and then the background jobs, in the order they
+// were started, within the deadline of ctx. Work a job missed is picked up
+// after the next start.
+func (a *App) Shutdown(ctx context.Context) {
+	// Answer waiting long polls now instead of at the end of their wait, then
+	// stop accepting requests and wait for the ones in flight
+	a.events.Close()
+	if err := a.Server.Shutdown(ctx); err != nil {
+		a.logger.Printf("Server forced to shutdown: %v", err)
+	}
+	if a.Redirect != nil {
+		if err := a.Redirect.Shutdown(ctx); err != nil {
+			a.logger.Printf("Redirect server forced to shutdown: %v", err)
+		}
+	}
+
+	for _, j := range a.jobs {
+		if j.cancel == nil {
+			continue
+		}
+		j.cancel()
+		select {
+		case <-j.done:
+			if j.flush == nil {
+				continue
+			}
+			if err := j.flush(ctx); err != nil {
+				a.logger.Printf("Pending activity callbacks are left in the outbox: %v", err)
+			}
+		case <-ctx.Done():
+			a.logger.Println(j.stopTimeout)
+		}
+	}
+}
+
+// healthChecks lists the dependencies probed by /readyz
+func healthChecks(db *gorm.DB, dbPath string, c cache.Cache, broker events.Broker, rateLimits middleware.RateLimitStore, model *llm.Router) []service.HealthCheck {
+	checks := []service.HealthCheck{
+		{
+			Name:     "database",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				// An open handle keeps working after the file is removed, so check the path too
+				if dbPath != "" {
+					if _, err := os.Stat(dbPath); err != nil {
+						return err
+					}
+				}
+				sqlDB, err := db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			},
+		},
+		{
+			Name:     "migrations",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				pending, err := database.PendingMigrations(db)
+				if err != nil {
+					return err
+				}
+				if pending > 0 {
+					return fmt.Errorf("%d migrations pending", pending)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "cache",
+			Check: func(ctx context.Context) error {
+				return cache.Ping(ctx, c)
+			},
+		},
+	}
+	if p, ok := broker.(cache.Pinger); ok {
+		// Without events live dashboards only miss updates
+		checks = append(checks, service.HealthCheck{Name: "events", Check: p.Ping})
+	}
+	if p, ok := rateLimits.(cache.Pinger); ok {
+		// Requests are let through while the store is unreachable
+		checks = append(checks, service.HealthCheck{Name: "rate_limits", Check: p.Ping})
+	}
+	if model != nil {
+		// The AI features are optional, so failing providers only degrade the service
+		checks = append(checks, service.HealthCheck{Name: "llm", Check: model.Check})
+	}
+	return checks
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/mail"
+	"lang-portal/backend_go/internal/testutil"
+)
+
+// discardSender accepts every message without sending it
+type discardSender struct{}
+
+func (discardSender) Send(context.Context, mail.Message) error { return nil }
+
+func TestApp_ServesTheAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testutil.SetupTestDB(t)
+	word := testutil.CreateTestWord(t, db)
+
+	a, err := New(db, WithLogger(log.New(io.Discard, "", 0)), WithSessionExpiry(time.Hour))
+	require.NoError(t, err)
+	a.Start()
+
+	srv := httptest.NewServer(a.Router)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/words/" + strconv.FormatUint(uint64(word.ID), 10))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, word.Term, body["japanese"])
+
+	ready, err := http.Get(srv.URL + "/readyz")
+	require.NoError(t, err)
+	ready.Body.Close()
+	assert.Equal(t, http.StatusOK, ready.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a.Shutdown(ctx)
+	for _, j := range a.jobs {
+		select {
+		case <-j.done:
+		default:
+			t.Fatal("background job still running after shutdown")
+		}
+	}
+}
+
+func TestNew_RequiresMailSettings(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+
+	_, err := New(db, WithMail(discardSender{}, "", ""))
+	assert.Error(t, err)
+}
+
+func TestNew_RejectsInvalidTLS(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	for name, tlsConfig := range map[string]TLS{
+		"nothing":          {},
+		"certificate only": {CertFile: "cert.pem"},
+		"both modes":       {CertFile: "cert.pem", KeyFile: "key.pem", Domains: []string{"portal.example"}, CacheDir: t.TempDir()},
+		"no cache":         {Domains: []string{"portal.example"}},
+	} {
+		_, err := New(db, WithLogger(log.New(io.Discard, "", 0)), WithTLS(tlsConfig))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestApp_RedirectsToHTTPS(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	a, err := New(db, WithLogger(log.New(io.Discard, "", 0)), WithAddr(":8443"),
+		WithTLS(TLS{Domains: []string{"portal.example"}, CacheDir: t.TempDir(), RedirectAddr: ":8080"}))
+	require.NoError(t, err)
+	require.NotNil(t, a.Redirect)
+	assert.Contains(t, a.Server.TLSConfig.NextProtos, "h2")
+
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{":8443", "https://portal.example:8443/api/v1/words?page=2"},
+		{":443", "https://portal.example/api/v1/words?page=2"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "http://portal.example:8080/api/v1/words?page=2", nil)
+		redirectToHTTPS(tt.addr).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+		assert.Equal(t, tt.want, w.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLS configures HTTPS. The server uses either the certificate in CertFile
+// and KeyFile or certificates obtained from Let's Encrypt for Domains, and
+// negotiates HTTP/2 with clients that support it.
+type TLS struct {
+	CertFile string
+	KeyFile  string
+
+	// Domains are the host names certificates are requested for; requests for
+	// other hosts get no certificate
+	Domains []string
+	// CacheDir keeps the obtained certificates and the account key across
+	// restarts, so that they are not requested again
+	CacheDir string
+	// Email is given to Let's Encrypt for notices about the certificates
+	Email string
+
+	// RedirectAddr is the address of a plain HTTP server redirecting to
+	// HTTPS, such as ":80"; it also answers the HTTP challenges of Let's
+	// Encrypt. Without it no plain HTTP is served.
+	RedirectAddr string
+}
+
+// autocert reports whether certificates are obtained from Let's Encrypt
+func (t *TLS) autocert() bool {
+	return len(t.Domains) > 0
+}
+
+// validate reports a configuration that cannot be served
+func (t *TLS) validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("app: TLS needs both a certificate and a key file")
+	}
+	if t.CertFile == "" && !t.autocert() {
+		return fmt.Errorf("app: TLS needs a certificate and key file or the domains to obtain certificates for")
+	}
+	if t.CertFile != "" && t.autocert() {
+		return fmt.Errorf("app: TLS takes either a certificate and key file or domains, not both")
+	}
+	if t.autocert() && t.CacheDir == "" {
+		return fmt.Errorf("app: obtaining certificates needs a cache directory")
+	}
+	return nil
+}
+
+// WithTLS serves HTTPS instead of plain HTTP, see TLS
+func WithTLS(t TLS) Option {
+	return func(c *config) { c.tls = &t }
+}
+
+// configureTLS sets up a.Server for HTTPS and creates the redirect server
+func (a *App) configureTLS(t *TLS) {
+	a.tls = t
+	if !t.autocert() {
+		if t.RedirectAddr != "" {
+			a.Redirect = redirectServer(t.RedirectAddr, redirectToHTTPS(a.Server.Addr))
+		}
+		return
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(t.Domains...),
+		Cache:      autocert.DirCache(t.CacheDir),
+		Email:      t.Email,
+	}
+	// The manager's configuration also answers TLS-ALPN challenges and
+	// offers HTTP/2
+	a.Server.TLSConfig = manager.TLSConfig()
+	if t.RedirectAddr != "" {
+		a.Redirect = redirectServer(t.RedirectAddr, manager.HTTPHandler(redirectToHTTPS(a.Server.Addr)))
+	}
+}
+
+// redirectServer returns the plain HTTP server at addr
+func redirectServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+}
+
+// redirectToHTTPS redirects every request to the same URL over HTTPS at the
+// port of tlsAddr, leaving the port out when it is 443
+func redirectToHTTPS(tlsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		// 308 keeps the method and body of API calls
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// ListenAndServe serves plain HTTP, or HTTPS and the redirect server when TLS
+// is configured, until Shutdown. It returns http.ErrServerClosed after a
+// shutdown.
+func (a *App) ListenAndServe() error {
+	if a.tls == nil {
+		return a.Server.ListenAndServe()
+	}
+	if a.Redirect != nil {
+		go func() {
+			if err := a.Redirect.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				a.logger.Printf("Failed to serve the HTTPS redirect: %v", err)
+			}
+		}()
+	}
+	if a.tls.autocert() {
+		return a.Server.ListenAndServeTLS("", "")
+	}
+	return a.Server.ListenAndServeTLS(a.tls.CertFile, a.tls.KeyFile)
+}
@@ -0,0 +1,61 @@
+// Package cache provides a key/value cache with per-entry expiry, used for
+// read-through caching of expensive aggregates. Values are stored as encoded
+// bytes so that the in-memory store can be swapped for a shared one such as Redis.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Cache stores encoded values under string keys. Implementations treat backend
+// failures as misses: a cache must never be the reason a request fails.
+type Cache interface {
+	// Get returns the value stored under key, if present and not expired
+	Get(key string) ([]byte, bool)
+	// Set stores value under key for ttl
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete removes the given keys; missing keys are ignored
+	Delete(keys ...string)
+}
+
+// Pinger is implemented by caches backed by an external service
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks that c is reachable. In-process caches are always reachable.
+func Ping(ctx context.Context, c Cache) error {
+	if p, ok := c.(Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// GetOrLoad returns the value cached under key, or calls load, caches its result
+// for ttl and returns it. Errors from load are returned and never cached. A nil
+// cache always calls load.
+func GetOrLoad[T any](c Cache, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if c != nil {
+		if data, ok := c.Get(key); ok {
+			var value T
+			if err := json.Unmarshal(data, &value); err == nil {
+				return value, nil
+			}
+			// Undecodable entries are treated as misses and overwritten below
+		}
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	if c != nil {
+		if data, err := json.Marshal(value); err == nil {
+			c.Set(key, data, ttl)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_Expiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+
+	m.Set("stats", []byte("1"), time.Minute)
+	value, ok := m.Get("stats")
+	require.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	now = now.Add(time.Minute)
+	_, ok = m.Get("stats")
+	assert.False(t, ok, "entries expire after their ttl")
+}
+
+func TestMemory_Delete(t *testing.T) {
+	m := NewMemory()
+	m.Set("a", []byte("1"), time.Minute)
+	m.Set("b", []byte("2"), time.Minute)
+
+	m.Delete("a", "missing")
+
+	_, ok := m.Get("a")
+	assert.False(t, ok)
+	_, ok = m.Get("b")
+	assert.True(t, ok)
+}
+
+func TestGetOrLoad(t *testing.T) {
+	type stats struct{ Total int }
+	m := NewMemory()
+
+	calls := 0
+	load := func() (*stats, error) {
+		calls++
+		return &stats{Total: 3}, nil
+	}
+
+	first, err := GetOrLoad(m, "stats", time.Minute, load)
+	require.NoError(t, err)
+	second, err := GetOrLoad(m, "stats", time.Minute, load)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls, "second call is served from the cache")
+	assert.Equal(t, first, second)
+
+	m.Delete("stats")
+	_, err = GetOrLoad(m, "stats", time.Minute, load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestGetOrLoad_ErrorsAreNotCached(t *testing.T) {
+	m := NewMemory()
+	failure := errors.New("boom")
+
+	_, err := GetOrLoad(m, "stats", time.Minute, func() (int, error) { return 0, failure })
+	assert.Equal(t, failure, err)
+
+	_, ok := m.Get("stats")
+	assert.False(t, ok)
+}
+
+func TestGetOrLoad_NilCache(t *testing.T) {
+	calls := 0
+	for i := 0; i < 2; i++ {
+		_, err := GetOrLoad[int](nil, "stats", time.Minute, func() (int, error) {
+			calls++
+			return 1, nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
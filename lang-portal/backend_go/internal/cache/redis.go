@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	_ Cache  = (*Memory)(nil)
+	_ Cache  = (*Redis)(nil)
+	_ Pinger = (*Redis)(nil)
+)
+
+// RedisKeyPrefix is put before the cache keys in Redis
+const RedisKeyPrefix = "lang-portal:cache:"
+
+// redisTimeout bounds each cache operation, so that a slow Redis costs a
+// request a miss rather than its deadline
+const redisTimeout = time.Second
+
+// Redis is a Cache shared by every server using the same Redis, so that an
+// entry deleted by one of them is gone for all
+type Redis struct {
+	client redis.UniversalClient
+}
+
+// NewRedis creates a cache on client, which is owned by the caller
+func NewRedis(client redis.UniversalClient) *Redis {
+	return &Redis{client: client}
+}
+
+// Get implements Cache
+func (r *Redis) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := r.client.Get(ctx, RedisKeyPrefix+key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set implements Cache
+func (r *Redis) Set(key string, value []byte, ttl time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	r.client.Set(ctx, RedisKeyPrefix+key, value, ttl)
+}
+
+// Delete implements Cache
+func (r *Redis) Delete(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = RedisKeyPrefix + key
+	}
+	r.client.Del(ctx, prefixed...)
+}
+
+// Ping implements Pinger
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	c, other := NewRedis(client), NewRedis(client)
+
+	c.Set("stats", []byte(`{"words":3}`), time.Minute)
+	value, ok := other.Get("stats")
+	assert.True(t, ok, "entries are shared")
+	assert.Equal(t, `{"words":3}`, string(value))
+	assert.True(t, server.Exists(RedisKeyPrefix+"stats"))
+
+	other.Delete("stats", "missing")
+	_, ok = c.Get("stats")
+	assert.False(t, ok, "deletes are shared")
+
+	c.Set("stats", []byte("1"), time.Minute)
+	server.FastForward(time.Minute)
+	_, ok = c.Get("stats")
+	assert.False(t, ok, "entries expire")
+	assert.NoError(t, c.Ping(context.Background()))
+}
+
+func TestRedis_UnreachableIsAMiss(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	c := NewRedis(client)
+	server.Close()
+
+	c.Set("stats", []byte("1"), time.Minute)
+	_, ok := c.Get("stats")
+	assert.False(t, ok)
+	assert.Error(t, Ping(context.Background(), c))
+
+	loads := 0
+	value, err := GetOrLoad(c, "stats", time.Minute, func() (int, error) {
+		loads++
+		return 7, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, value)
+	assert.Equal(t, 1, loads)
+}
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+// Backup writes a consistent copy of the SQLite database to path.
+// The target file must not exist yet.
+func Backup(db *gorm.DB, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup target %s already exists", path)
+	}
+	if err := db.Exec("VACUUM INTO ?", path).Error; err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
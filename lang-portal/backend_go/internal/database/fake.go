@@ -0,0 +1,272 @@
+package database
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// fakeBatchSize is the number of rows Fake inserts per statement
+const fakeBatchSize = 500
+
+// fakeActivityName names the study activity the fake sessions belong to
+const fakeActivityName = "Load Test"
+
+// fakeSyllables are the kana and romaji fake words are made of
+var fakeSyllables = [][2]string{
+	{"か", "ka"}, {"き", "ki"}, {"く", "ku"}, {"け", "ke"}, {"こ", "ko"},
+	{"さ", "sa"}, {"し", "shi"}, {"す", "su"}, {"せ", "se"}, {"そ", "so"},
+	{"た", "ta"}, {"ち", "chi"}, {"つ", "tsu"}, {"て", "te"}, {"と", "to"},
+	{"な", "na"}, {"に", "ni"}, {"ぬ", "nu"}, {"ね", "ne"}, {"の", "no"},
+	{"ま", "ma"}, {"み", "mi"}, {"む", "mu"}, {"め", "me"}, {"も", "mo"},
+	{"ら", "ra"}, {"り", "ri"}, {"る", "ru"}, {"れ", "re"}, {"ろ", "ro"},
+}
+
+// fakeParts are the parts of speech given to fake words
+var fakeParts = []string{"noun", "verb", "i-adjective", "na-adjective", "adverb"}
+
+// FakeOptions sizes the synthetic data Fake generates
+type FakeOptions struct {
+	Words    int
+	Groups   int
+	Sessions int
+	Reviews  int
+	// Learners the sessions are spread over; with none the sessions have no learner
+	Learners int
+	// Span is how far back from Now the sessions reach
+	Span time.Duration
+	Now  time.Time
+	// Seed makes the generated data reproducible
+	Seed int64
+}
+
+// DefaultFakeOptions returns options for about two years of study: 50,000
+// reviews in 2,500 sessions over 2,000 words in 40 groups
+func DefaultFakeOptions() FakeOptions {
+	return FakeOptions{
+		Words:    2000,
+		Groups:   40,
+		Sessions: 2500,
+		Reviews:  50000,
+		Learners: 1,
+		Span:     2 * 365 * 24 * time.Hour,
+		Now:      time.Now(),
+		Seed:     1,
+	}
+}
+
+func (o FakeOptions) validate() error {
+	switch {
+	case o.Words < 1, o.Groups < 1:
+		return fmt.Errorf("fake data needs at least one word and one group")
+	case o.Sessions < 0, o.Reviews < 0, o.Learners < 0:
+		return fmt.Errorf("fake data sizes must not be negative")
+	case o.Reviews > 0 && o.Sessions == 0:
+		return fmt.Errorf("fake reviews need at least one session")
+	case o.Span <= 0:
+		return fmt.Errorf("fake data span must be positive")
+	}
+	return nil
+}
+
+// FakeResult counts the rows Fake added
+type FakeResult struct {
+	Words       int
+	Groups      int
+	Memberships int
+	Sessions    int
+	Reviews     int
+}
+
+// Fake fills the database with synthetic words, groups, sessions and reviews
+// for benchmarking, in one transaction. Every word is in one or two groups and
+// every session reviews words of its group, each word with its own accuracy,
+// with the sessions spread evenly over the span. Groups are named "Load Test
+// NNN" and reused when they exist, so Fake can run more than once.
+//
+// Like Seed, Fake writes rows directly: the words' kanji, parts of speech and
+// ranks, their review schedule and progress, and the daily summaries are
+// computed afterwards.
+func Fake(db *gorm.DB, opts FakeOptions) (FakeResult, error) {
+	var result FakeResult
+	if err := opts.validate(); err != nil {
+		return result, err
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	start := opts.Now.Add(-opts.Span)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		activity := models.StudyActivity{
+			Name:         fakeActivityName,
+			Description:  "Synthetic sessions for load testing",
+			ThumbnailURL: "/thumbnails/load-test.png",
+			Modes:        models.StringSlice{},
+			Capabilities: models.StringSlice{},
+		}
+		if err := tx.Where(models.StudyActivity{Name: fakeActivityName}).FirstOrCreate(&activity).Error; err != nil {
+			return fmt.Errorf("failed to create the load test activity: %w", err)
+		}
+
+		groups := make([]models.Group, opts.Groups)
+		for i := range groups {
+			name := fmt.Sprintf("Load Test %03d", i+1)
+			groups[i] = models.Group{Name: name}
+			created := tx.Where(models.Group{Name: name}).FirstOrCreate(&groups[i])
+			if created.Error != nil {
+				return fmt.Errorf("failed to create group %s: %w", name, created.Error)
+			}
+			result.Groups += int(created.RowsAffected)
+		}
+
+		// Japanese text is unique, so fake words avoid the words already there
+		var existing []string
+		if err := tx.Model(&models.Word{}).Pluck("term", &existing).Error; err != nil {
+			return fmt.Errorf("failed to list words: %w", err)
+		}
+		used := make(map[string]bool, len(existing)+opts.Words)
+		for _, japanese := range existing {
+			used[japanese] = true
+		}
+		words := make([]models.Word, opts.Words)
+		for i := range words {
+			words[i] = fakeWord(rng, used, i, start.Add(time.Duration(rng.Int63n(int64(opts.Span)))))
+		}
+		if err := tx.Omit(clause.Associations).CreateInBatches(words, fakeBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create words: %w", err)
+		}
+		result.Words = len(words)
+
+		// Every word joins a group in turn, so no group is empty unless there
+		// are fewer words than groups, and a random second one a third of the
+		// time
+		groupWords := make([][]uint, len(groups))
+		memberships := make([]map[string]interface{}, 0, len(words)*4/3)
+		for i, word := range words {
+			joined := []int{i % len(groups)}
+			if second := rng.Intn(len(groups)); len(groups) > 1 && second != joined[0] && rng.Intn(3) == 0 {
+				joined = append(joined, second)
+			}
+			for _, g := range joined {
+				groupWords[g] = append(groupWords[g], word.ID)
+				memberships = append(memberships, map[string]interface{}{"word_id": word.ID, "group_id": groups[g].ID})
+			}
+		}
+		if err := tx.Table("word_groups").Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(memberships, fakeBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to add words to groups: %w", err)
+		}
+		result.Memberships = len(memberships)
+
+		if opts.Sessions == 0 {
+			return nil
+		}
+		var filled []int
+		for g, ids := range groupWords {
+			if len(ids) > 0 {
+				filled = append(filled, g)
+			}
+		}
+		sessions := make([]models.StudySession, opts.Sessions)
+		sessionGroups := make([]int, opts.Sessions)
+		step := opts.Span / time.Duration(opts.Sessions)
+		for i := range sessions {
+			sessionGroups[i] = filled[rng.Intn(len(filled))]
+			startedAt := start.Add(step*time.Duration(i) + time.Duration(rng.Int63n(int64(step)+1)))
+			completedAt := startedAt
+			sessions[i] = models.StudySession{
+				GroupID:         groups[sessionGroups[i]].ID,
+				StudyActivityID: activity.ID,
+				Client:          models.ClientInfo{DeviceLabel: "load test"},
+				CreatedAt:       startedAt,
+				CompletedAt:     &completedAt,
+			}
+			if opts.Learners > 0 {
+				sessions[i].Learner = fmt.Sprintf("loadtest-%d", rng.Intn(opts.Learners)+1)
+			}
+		}
+
+		// Each word is answered correctly with its own probability, between
+		// 50% and 95%, so accuracy and due dates vary between words
+		accuracy := make(map[uint]float64, len(words))
+		for _, word := range words {
+			accuracy[word.ID] = 0.5 + 0.45*rng.Float64()
+		}
+		// Reviews take turns between the sessions; reviewSessions holds the
+		// session of each review until the sessions have IDs
+		reviews := make([]models.WordReview, 0, opts.Reviews)
+		reviewSessions := make([]int, 0, opts.Reviews)
+		for i := 0; i < opts.Reviews; i++ {
+			s := i % len(sessions)
+			session := &sessions[s]
+			candidates := groupWords[sessionGroups[s]]
+			wordID := candidates[rng.Intn(len(candidates))]
+			answerTime := 800 + rng.Intn(7200)
+			reviewedAt := session.CompletedAt.Add(time.Duration(answerTime) * time.Millisecond)
+			*session.CompletedAt = reviewedAt
+
+			correct := rng.Float64() < accuracy[wordID]
+			grade := models.GradeAgain
+			if correct {
+				grade = []models.ReviewGrade{models.GradeHard, models.GradeGood, models.GradeGood, models.GradeEasy}[rng.Intn(4)]
+			}
+			reviews = append(reviews, models.WordReview{
+				WordID:       wordID,
+				Correct:      correct,
+				Grade:        grade,
+				AnswerTimeMs: &answerTime,
+				CreatedAt:    reviewedAt,
+			})
+			reviewSessions = append(reviewSessions, s)
+		}
+
+		if err := tx.Omit(clause.Associations).CreateInBatches(sessions, fakeBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create study sessions: %w", err)
+		}
+		result.Sessions = len(sessions)
+		for i := range reviews {
+			reviews[i].StudySessionID = sessions[reviewSessions[i]].ID
+		}
+		if err := tx.Omit(clause.Associations).CreateInBatches(reviews, fakeBatchSize).Error; err != nil {
+			return fmt.Errorf("failed to create reviews: %w", err)
+		}
+		result.Reviews = len(reviews)
+		return nil
+	})
+	if err != nil {
+		return FakeResult{}, err
+	}
+	return result, nil
+}
+
+// fakeWord makes up the n-th fake word from two to four syllables, with
+// Japanese text not in used, and adds it to used. Words get longer when they
+// keep clashing.
+func fakeWord(rng *rand.Rand, used map[string]bool, n int, createdAt time.Time) models.Word {
+	var japanese, romaji strings.Builder
+	for attempt := 0; japanese.Len() == 0 || used[japanese.String()]; attempt++ {
+		japanese.Reset()
+		romaji.Reset()
+		for i := 2 + rng.Intn(3) + attempt/10; i > 0; i-- {
+			syllable := fakeSyllables[rng.Intn(len(fakeSyllables))]
+			japanese.WriteString(syllable[0])
+			romaji.WriteString(syllable[1])
+		}
+	}
+	used[japanese.String()] = true
+	word := models.Word{
+		Term:      japanese.String(),
+		Reading:   romaji.String(),
+		English:   fmt.Sprintf("load test word %d", n+1),
+		Parts:     models.StringSlice{fakeParts[rng.Intn(len(fakeParts))]},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	word.DefaultScripts()
+	return word
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+func smallFakeOptions() FakeOptions {
+	return FakeOptions{
+		Words:    60,
+		Groups:   4,
+		Sessions: 20,
+		Reviews:  300,
+		Learners: 2,
+		Span:     30 * 24 * time.Hour,
+		Now:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Seed:     7,
+	}
+}
+
+func TestFake_GeneratesConsistentData(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, Migrate(db))
+	opts := smallFakeOptions()
+
+	result, err := Fake(db, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 60, result.Words)
+	assert.Equal(t, 4, result.Groups)
+	assert.GreaterOrEqual(t, result.Memberships, 60)
+	assert.Equal(t, 20, result.Sessions)
+	assert.Equal(t, 300, result.Reviews)
+
+	var outsideGroup int64
+	require.NoError(t, db.Table("word_review_items").
+		Joins("JOIN study_sessions ON study_sessions.id = word_review_items.study_session_id").
+		Where("NOT EXISTS (SELECT 1 FROM word_groups WHERE word_groups.word_id = word_review_items.word_id AND word_groups.group_id = study_sessions.group_id)").
+		Count(&outsideGroup).Error)
+	assert.Zero(t, outsideGroup, "reviews are of words in the session's group")
+
+	var first, last models.StudySession
+	require.NoError(t, db.Order("created_at").First(&first).Error)
+	require.NoError(t, db.Order("created_at DESC").First(&last).Error)
+	assert.False(t, first.CreatedAt.Before(opts.Now.Add(-opts.Span)))
+	assert.True(t, last.CreatedAt.Before(opts.Now))
+
+	var learners []string
+	require.NoError(t, db.Model(&models.StudySession{}).Distinct().Order("learner").Pluck("learner", &learners).Error)
+	assert.Equal(t, []string{"loadtest-1", "loadtest-2"}, learners)
+}
+
+func TestFake_Reproducible(t *testing.T) {
+	japanese := func() []string {
+		db := openMemoryDB(t)
+		require.NoError(t, Migrate(db))
+		_, err := Fake(db, smallFakeOptions())
+		require.NoError(t, err)
+		var words []string
+		require.NoError(t, db.Model(&models.Word{}).Order("id").Pluck("term", &words).Error)
+		return words
+	}
+	assert.Equal(t, japanese(), japanese())
+}
+
+func TestFake_RunsAgain(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, Migrate(db))
+	_, err := Fake(db, smallFakeOptions())
+	require.NoError(t, err)
+
+	result, err := Fake(db, smallFakeOptions())
+	require.NoError(t, err)
+	assert.Zero(t, result.Groups, "the groups are reused")
+	assert.Equal(t, 60, result.Words)
+
+	var words int64
+	require.NoError(t, db.Model(&models.Word{}).Count(&words).Error)
+	assert.EqualValues(t, 120, words)
+}
+
+func TestFake_InvalidOptions(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, Migrate(db))
+
+	opts := smallFakeOptions()
+	opts.Sessions = 0
+	_, err := Fake(db, opts)
+	assert.Error(t, err)
+
+	opts = smallFakeOptions()
+	opts.Groups = 0
+	_, err = Fake(db, opts)
+	assert.Error(t, err)
+}
@@ -1,18 +1,213 @@
 package database
 
 import (
-	"lang-portal/backend_go/internal/models"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
 
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"gorm.io/gorm"
+
+	"lang-portal/backend_go/internal/models"
 )
 
-// Migrate runs all database migrations
+// migrationFiles holds the versioned up/down SQL migrations. Every schema
+// change needs a new pair of files here; models are no longer auto-migrated.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// schemaModels lists every model backed by a table. It is only used to bring
+// databases created before versioned migrations up to date, and to check in
+// tests that the migrations produce the schema the models expect.
+var schemaModels = []interface{}{
+	&models.Word{},
+	&models.PartOfSpeech{},
+	&models.WordPart{},
+	&models.WordFrequency{},
+	&models.Group{},
+	&models.StudyActivity{},
+	&models.StudySession{},
+	&models.WordReview{},
+	&models.ActivityReviewBatch{},
+	&models.AuditEntry{},
+	&models.StatsShare{},
+	&models.Setting{},
+	&models.DataMigration{},
+	&models.GroupGoal{},
+	&models.GroupCertificate{},
+	&models.Kanji{},
+	&models.ExampleSentence{},
+	&models.OutboxEvent{},
+	&models.APIKey{},
+	&models.WordProgress{},
+	&models.WordStats{},
+	&models.WordVector{},
+	&models.Conversation{},
+	&models.Message{},
+	&models.PromptTemplate{},
+	&models.AIUsage{},
+	&models.CounterReview{},
+	&models.Cohort{},
+	&models.CohortMembership{},
+	&models.CohortGroup{},
+	&models.LearnerXP{},
+	&models.XPAward{},
+	&models.Notification{},
+	&models.NotificationPreference{},
+	&models.DailySummary{},
+	&models.ArchivedWordReview{},
+	&models.WordReviewMonth{},
+	&models.SessionWord{},
+}
+
+// Migrate applies all pending schema migrations, then any pending one-time data repairs
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&models.Word{},
-		&models.Group{},
-		&models.StudyActivity{},
-		&models.StudySession{},
-		&models.WordReview{},
-	)
+	m, src, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := adoptLegacySchema(db, m, src); err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return applyDataMigrations(db)
+}
+
+// SchemaVersion returns the currently applied migration version. dirty is true
+// when a migration failed part-way and the schema needs manual repair.
+func SchemaVersion(db *gorm.DB) (version uint, dirty bool, err error) {
+	m, src, err := newMigrator(db)
+	if err != nil {
+		return 0, false, err
+	}
+	defer src.Close()
+
+	version, dirty, err = m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// PendingMigrations returns the number of migrations not yet applied. It fails
+// when the schema is dirty, since a half-applied migration needs manual repair.
+func PendingMigrations(db *gorm.DB) (int, error) {
+	m, src, err := newMigrator(db)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	current, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		current, err = 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty", current)
+	}
+
+	pending := 0
+	version, err := src.First()
+	for err == nil {
+		if version > current {
+			pending++
+		}
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	return pending, nil
+}
+
+// newMigrator builds a migrator on top of the gorm connection pool. The
+// migrator itself is never closed, since that would close the shared pool.
+func newMigrator(db *gorm.DB) (*migrate.Migrate, source.Driver, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get database handle: %w", err)
+	}
+
+	src, err := newSource()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	driver, err := sqlite3.WithInstance(sqlDB, &sqlite3.Config{})
+	if err != nil {
+		src.Close()
+		return nil, nil, fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite3", driver)
+	if err != nil {
+		src.Close()
+		return nil, nil, fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+	return m, src, nil
+}
+
+// newSource opens the embedded migrations
+func newSource() (source.Driver, error) {
+	src, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return src, nil
+}
+
+// adoptLegacySchema handles databases created by AutoMigrate before versioned
+// migrations existed: they have tables but no recorded version. They are
+// auto-migrated one last time and then marked as fully migrated.
+func adoptLegacySchema(db *gorm.DB, m *migrate.Migrate, src source.Driver) error {
+	if _, _, err := m.Version(); !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+	if !db.Migrator().HasTable(&models.Word{}) {
+		return nil
+	}
+
+	if err := db.AutoMigrate(schemaModels...); err != nil {
+		return fmt.Errorf("failed to upgrade legacy schema: %w", err)
+	}
+
+	latest, err := latestVersion(src)
+	if err != nil {
+		return err
+	}
+	if err := m.Force(int(latest)); err != nil {
+		return fmt.Errorf("failed to record legacy schema version: %w", err)
+	}
+	return nil
+}
+
+// latestVersion returns the highest migration version available in src
+func latestVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
 }
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+func openMemoryDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+func columnNames(t *testing.T, db *gorm.DB, table string) []string {
+	columns, err := db.Migrator().ColumnTypes(table)
+	require.NoError(t, err)
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestMigrate_MatchesModels(t *testing.T) {
+	migrated := openMemoryDB(t)
+	require.NoError(t, Migrate(migrated))
+
+	auto := openMemoryDB(t)
+	require.NoError(t, auto.AutoMigrate(schemaModels...))
+
+	tables, err := auto.Migrator().GetTables()
+	require.NoError(t, err)
+	for _, table := range tables {
+		assert.True(t, migrated.Migrator().HasTable(table), "missing table %s", table)
+		assert.Subset(t, columnNames(t, migrated, table), columnNames(t, auto, table), "columns of %s", table)
+	}
+}
+
+func TestMigrate_Idempotent(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, Migrate(db))
+	require.NoError(t, Migrate(db))
+
+	version, dirty, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+
+	src, err := newSource()
+	require.NoError(t, err)
+	defer src.Close()
+	latest, err := latestVersion(src)
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+}
+
+func TestMigrate_AdoptsLegacySchema(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, db.AutoMigrate(schemaModels...))
+	word := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(word).Error)
+
+	require.NoError(t, Migrate(db))
+
+	version, dirty, err := SchemaVersion(db)
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	assert.NotZero(t, version)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Word{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	db := openMemoryDB(t)
+
+	pending, err := PendingMigrations(db)
+	require.NoError(t, err)
+	assert.NotZero(t, pending, "a new database has every migration pending")
+
+	require.NoError(t, Migrate(db))
+	pending, err = PendingMigrations(db)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+}
+
+func TestMigrate_LinksWordParts(t *testing.T) {
+	db := openMemoryDB(t)
+	m, src, err := newMigrator(db)
+	require.NoError(t, err)
+	defer src.Close()
+	require.NoError(t, m.Migrate(39))
+
+	// Later migrations add columns the model has, so the word is inserted as
+	// the schema stood
+	parts := models.StringSlice{"adjective", "slang"}
+	require.NoError(t, db.Exec("INSERT INTO words (id, japanese, romaji, english, parts) VALUES (1, 'やばい', 'yabai', 'awful', ?)", parts).Error)
+
+	require.NoError(t, Migrate(db))
+
+	var names []string
+	require.NoError(t, db.Table("word_parts").
+		Joins("JOIN parts_of_speech ON parts_of_speech.id = word_parts.part_id").
+		Where("word_parts.word_id = ?", 1).
+		Order("parts_of_speech.name").
+		Pluck("parts_of_speech.name", &names).Error)
+	assert.Equal(t, []string{"adjective", "slang"}, names, "tags in use join the vocabulary")
+}
+
+func TestMigrate_BackfillsWordStats(t *testing.T) {
+	db := openMemoryDB(t)
+	m, src, err := newMigrator(db)
+	require.NoError(t, err)
+	defer src.Close()
+	require.NoError(t, m.Migrate(41))
+
+	require.NoError(t, db.Exec("INSERT INTO words (id, japanese, romaji, english, parts) VALUES (1, '水', 'mizu', 'water', '[\"noun\"]'), (2, '火', 'hi', 'fire', '[\"noun\"]')").Error)
+	require.NoError(t, db.Exec(`INSERT INTO word_review_items (word_id, study_session_id, correct, created_at) VALUES
+		(1, 1, 1, '2025-03-01 09:00:00'), (1, 1, 0, '2025-03-02 09:00:00')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO word_review_archive (id, word_id, study_session_id, correct, created_at, archived_at) VALUES
+		(10, 1, 1, 1, '2024-01-01 09:00:00', '2025-01-01 00:00:00')`).Error)
+
+	require.NoError(t, Migrate(db))
+
+	var stats []models.WordStats
+	require.NoError(t, db.Find(&stats).Error)
+	require.Len(t, stats, 1, "words without reviews get no stats")
+	assert.Equal(t, uint(1), stats[0].WordID)
+	assert.Equal(t, int64(2), stats[0].CorrectCount)
+	assert.Equal(t, int64(1), stats[0].WrongCount)
+	assert.InDelta(t, 2.0/3, stats[0].SuccessRate, 1e-9)
+	require.NotNil(t, stats[0].LastReviewedAt)
+	assert.Equal(t, 2, stats[0].LastReviewedAt.Day())
+}
+
+func TestMigrate_LanguageNeutralWords(t *testing.T) {
+	db := openMemoryDB(t)
+	m, src, err := newMigrator(db)
+	require.NoError(t, err)
+	defer src.Close()
+	require.NoError(t, m.Migrate(44))
+
+	parts := models.StringSlice{"noun"}
+	require.NoError(t, db.Exec(`INSERT INTO words (id, language, japanese, romaji, english, parts) VALUES
+		(1, 'ja', '水', 'mizu', 'water', ?), (2, 'ko', '물', 'mul', 'water', ?)`, parts, parts).Error)
+	require.NoError(t, db.Exec("INSERT INTO groups (id, name, language, share_slug) VALUES (1, 'Basics', 'ja', 'basics')").Error)
+
+	require.NoError(t, Migrate(db))
+
+	var words []models.Word
+	require.NoError(t, db.Order("id").Find(&words).Error)
+	require.Len(t, words, 2)
+	assert.Equal(t, "水", words[0].Term)
+	assert.Equal(t, "mizu", words[0].Reading)
+	assert.Equal(t, "Jpan", words[0].Script)
+	assert.Equal(t, "Latn", words[0].ReadingScript)
+	assert.Equal(t, "물", words[1].Term)
+	assert.Equal(t, "Kore", words[1].Script)
+
+	var group models.Group
+	require.NoError(t, db.First(&group, 1).Error)
+	assert.Equal(t, "Basics", group.Name)
+	require.NotNil(t, group.ShareSlug)
+	assert.Equal(t, "basics", *group.ShareSlug)
+
+	// Group names are unique per language
+	require.NoError(t, db.Create(&models.Group{Name: "Basics", Language: "ko"}).Error)
+	assert.Error(t, db.Create(&models.Group{Name: "Basics", Language: "ko"}).Error)
+}
@@ -0,0 +1,2 @@
+-- Nothing to undo: the backfill does not change the schema
+SELECT 1;
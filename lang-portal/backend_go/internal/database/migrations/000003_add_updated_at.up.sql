@@ -0,0 +1,4 @@
+-- updated_at is part of the initial schema; backfill rows written before it
+-- was maintained so conditional GETs have a stable validator
+UPDATE words SET updated_at = created_at WHERE updated_at IS NULL;
+UPDATE groups SET updated_at = created_at WHERE updated_at IS NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_words_next_due_at;
+DROP INDEX IF EXISTS idx_words_last_reviewed_at;
+
+ALTER TABLE words DROP COLUMN next_due_at;
+ALTER TABLE words DROP COLUMN last_reviewed_at;
@@ -0,0 +1,12 @@
+-- Materialized review schedule, maintained on every review write
+ALTER TABLE words ADD COLUMN last_reviewed_at TIMESTAMP;
+ALTER TABLE words ADD COLUMN next_due_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_words_last_reviewed_at ON words(last_reviewed_at);
+CREATE INDEX IF NOT EXISTS idx_words_next_due_at ON words(next_due_at);
+
+-- Backfill from existing reviews; words become due one day after their last review
+UPDATE words SET last_reviewed_at = (
+    SELECT MAX(created_at) FROM word_review_items WHERE word_review_items.word_id = words.id
+);
+UPDATE words SET next_due_at = datetime(last_reviewed_at, '+1 day') WHERE last_reviewed_at IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Opt-in tokens for the public stats widget
+CREATE TABLE IF NOT EXISTS stats_shares (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT NOT NULL UNIQUE,
+    stats TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS settings;
+ALTER TABLE study_sessions DROP COLUMN review_order;
+ALTER TABLE words DROP COLUMN accuracy_ewma;
@@ -0,0 +1,12 @@
+-- Moving average of answer accuracy, maintained on every review write.
+-- Existing words are backfilled by `langctl stats recompute`.
+ALTER TABLE words ADD COLUMN accuracy_ewma REAL;
+
+-- Per-session override of the review order preference
+ALTER TABLE study_sessions ADD COLUMN review_order TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Optional JSON Schema describing an activity's launch settings
+ALTER TABLE study_activities ADD COLUMN settings_schema TEXT;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS group_certificates;
+DROP TABLE IF EXISTS group_goals;
@@ -0,0 +1,23 @@
+-- Mastery goals set on groups, and the certificates issued when they are met
+CREATE TABLE IF NOT EXISTS group_goals (
+    group_id INTEGER PRIMARY KEY,
+    min_accuracy REAL NOT NULL,
+    sessions INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS group_certificates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT NOT NULL UNIQUE,
+    group_id INTEGER NOT NULL,
+    group_name TEXT NOT NULL,
+    min_accuracy REAL NOT NULL,
+    sessions INTEGER NOT NULL,
+    accuracy REAL NOT NULL,
+    achieved_at TIMESTAMP NOT NULL,
+    signature TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_certificates_group_id ON group_certificates(group_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS word_kanji;
+DROP TABLE IF EXISTS kanji;
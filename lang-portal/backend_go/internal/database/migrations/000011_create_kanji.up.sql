@@ -0,0 +1,22 @@
+-- Kanji and the words that contain them
+CREATE TABLE IF NOT EXISTS kanji (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    character TEXT NOT NULL UNIQUE,
+    meanings TEXT NOT NULL DEFAULT '[]', -- JSON array of strings
+    onyomi TEXT NOT NULL DEFAULT '[]', -- JSON array of strings
+    kunyomi TEXT NOT NULL DEFAULT '[]', -- JSON array of strings
+    stroke_count INTEGER NOT NULL DEFAULT 0,
+    jlpt_level INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS word_kanji (
+    word_id INTEGER NOT NULL,
+    kanji_id INTEGER NOT NULL,
+    PRIMARY KEY (word_id, kanji_id),
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+    FOREIGN KEY (kanji_id) REFERENCES kanji(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_kanji_kanji_id ON word_kanji(kanji_id);
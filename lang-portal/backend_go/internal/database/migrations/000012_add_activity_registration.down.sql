@@ -0,0 +1,7 @@
+ALTER TABLE study_activities DROP COLUMN updated_at;
+ALTER TABLE study_activities DROP COLUMN callback_secret;
+ALTER TABLE study_activities DROP COLUMN callback_url;
+ALTER TABLE study_activities DROP COLUMN config;
+ALTER TABLE study_activities DROP COLUMN capabilities;
+ALTER TABLE study_activities DROP COLUMN modes;
+ALTER TABLE study_activities DROP COLUMN launch_url;
@@ -0,0 +1,9 @@
+-- Registration details of external study apps: where to launch them, what they
+-- support and need, their configuration and where to send session callbacks
+ALTER TABLE study_activities ADD COLUMN launch_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE study_activities ADD COLUMN modes TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE study_activities ADD COLUMN capabilities TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE study_activities ADD COLUMN config TEXT;
+ALTER TABLE study_activities ADD COLUMN callback_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE study_activities ADD COLUMN callback_secret TEXT NOT NULL DEFAULT '';
+ALTER TABLE study_activities ADD COLUMN updated_at TIMESTAMP;
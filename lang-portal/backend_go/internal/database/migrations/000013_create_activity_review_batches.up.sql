@@ -0,0 +1,12 @@
+-- Review batches submitted by external activities with a launch token; a batch
+-- ID is accepted once per session so that replayed submissions are rejected
+CREATE TABLE IF NOT EXISTS activity_review_batches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    study_session_id INTEGER NOT NULL,
+    batch_id TEXT NOT NULL,
+    review_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (study_session_id) REFERENCES study_sessions(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_activity_review_batches_session_batch ON activity_review_batches(study_session_id, batch_id);
@@ -0,0 +1,3 @@
+-- Time taken to answer a review, in milliseconds; NULL for reviews recorded
+-- without it
+ALTER TABLE word_review_items ADD COLUMN answer_time_ms INTEGER;
@@ -0,0 +1,3 @@
+-- Confidence grade of a review: again, hard, good or easy; empty for reviews
+-- recorded as plain right or wrong
+ALTER TABLE word_review_items ADD COLUMN grade TEXT NOT NULL DEFAULT '';
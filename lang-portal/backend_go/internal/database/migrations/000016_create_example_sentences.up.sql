@@ -0,0 +1,11 @@
+-- Example sentences showing a word in use
+CREATE TABLE IF NOT EXISTS example_sentences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    word_id INTEGER NOT NULL,
+    japanese TEXT NOT NULL,
+    english TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_example_sentences_word_id ON example_sentences(word_id);
@@ -0,0 +1,4 @@
+-- Edit counters of words and groups, bumped on every update so that clients can
+-- detect that a record changed since they read it
+ALTER TABLE words ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE groups ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,17 @@
+-- Session events recorded in the same transaction as the change they describe,
+-- delivered afterwards to activity callbacks with retries. Rows outlive resets
+-- of the study history, so there is no foreign key to study_sessions.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type TEXT NOT NULL,
+    study_session_id INTEGER NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_next_attempt_at ON outbox_events(next_attempt_at);
@@ -0,0 +1,2 @@
+ALTER TABLE study_sessions DROP COLUMN user_agent;
+ALTER TABLE study_sessions DROP COLUMN device_label;
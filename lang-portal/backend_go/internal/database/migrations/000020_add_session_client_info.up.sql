@@ -0,0 +1,3 @@
+-- Device a study session was started on, as reported by the client
+ALTER TABLE study_sessions ADD COLUMN device_label TEXT NOT NULL DEFAULT '';
+ALTER TABLE study_sessions ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- How repeated reviews of a word within one session are handled: '' records
+-- every review, 'reject' refuses repeats and 'upsert' replaces the earlier one
+ALTER TABLE study_activities ADD COLUMN review_dedup TEXT NOT NULL DEFAULT '';
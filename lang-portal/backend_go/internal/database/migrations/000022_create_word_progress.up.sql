@@ -0,0 +1,14 @@
+-- Progress state of each reviewed word, advanced with every review. Words
+-- without a row are new. Existing reviews are replayed by a data migration.
+CREATE TABLE IF NOT EXISTS word_progress (
+    word_id INTEGER PRIMARY KEY,
+    state TEXT NOT NULL DEFAULT 'new',
+    streak INTEGER NOT NULL DEFAULT 0,
+    lapses INTEGER NOT NULL DEFAULT 0,
+    reviews INTEGER NOT NULL DEFAULT 0,
+    state_changed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_progress_state ON word_progress(state);
@@ -0,0 +1,12 @@
+-- Embeddings of words for related-word suggestions, computed on demand by the
+-- configured embedding provider. source is the text that was embedded, so that
+-- vectors of edited words or of another model are recomputed.
+CREATE TABLE IF NOT EXISTS word_vectors (
+    word_id INTEGER PRIMARY KEY,
+    model TEXT NOT NULL,
+    source TEXT NOT NULL,
+    dimensions INTEGER NOT NULL,
+    vector BLOB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
+);
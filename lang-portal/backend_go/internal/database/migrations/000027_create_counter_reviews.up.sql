@@ -0,0 +1,12 @@
+-- Answers to counter word drills
+CREATE TABLE IF NOT EXISTS counter_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    counter TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    answer TEXT NOT NULL,
+    correct BOOLEAN NOT NULL,
+    answer_time_ms INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_counter_reviews_counter ON counter_reviews(counter);
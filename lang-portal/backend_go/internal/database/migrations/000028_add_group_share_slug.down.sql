@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_groups_share_slug;
+ALTER TABLE groups DROP COLUMN share_slug;
@@ -0,0 +1,3 @@
+-- Slug of a group published as a shared deck, NULL while it is private
+ALTER TABLE groups ADD COLUMN share_slug TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_share_slug ON groups(share_slug);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_study_sessions_learner;
+ALTER TABLE study_sessions DROP COLUMN learner;
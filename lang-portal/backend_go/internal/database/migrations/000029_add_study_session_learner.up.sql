@@ -0,0 +1,3 @@
+-- Actor that started each session, empty for sessions started before it was recorded
+ALTER TABLE study_sessions ADD COLUMN learner TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_study_sessions_learner ON study_sessions(learner);
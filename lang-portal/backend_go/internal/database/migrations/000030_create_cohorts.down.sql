@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS cohort_groups;
+DROP TABLE IF EXISTS cohort_memberships;
+DROP TABLE IF EXISTS cohorts;
@@ -0,0 +1,30 @@
+-- Classes run by teachers: their learners and the groups they study
+CREATE TABLE IF NOT EXISTS cohorts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    owner TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_cohorts_owner ON cohorts(owner);
+
+CREATE TABLE IF NOT EXISTS cohort_memberships (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    cohort_id INTEGER NOT NULL,
+    learner TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cohort_memberships_cohort_learner ON cohort_memberships(cohort_id, learner);
+
+CREATE TABLE IF NOT EXISTS cohort_groups (
+    cohort_id INTEGER NOT NULL,
+    group_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (cohort_id, group_id),
+    FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_cohort_groups_group_id ON cohort_groups(group_id);
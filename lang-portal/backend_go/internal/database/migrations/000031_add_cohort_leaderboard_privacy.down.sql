@@ -0,0 +1,2 @@
+ALTER TABLE cohort_memberships DROP COLUMN leaderboard_opt_out;
+ALTER TABLE cohort_memberships DROP COLUMN display_name;
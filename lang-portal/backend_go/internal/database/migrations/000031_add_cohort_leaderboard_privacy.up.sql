@@ -0,0 +1,3 @@
+-- Name a member is shown under on the cohort leaderboard, and whether they are left off it
+ALTER TABLE cohort_memberships ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE cohort_memberships ADD COLUMN leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
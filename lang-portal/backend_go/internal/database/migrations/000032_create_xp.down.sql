@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS xp_awards;
+DROP TABLE IF EXISTS learner_xp;
+ALTER TABLE study_sessions DROP COLUMN completed_at;
@@ -0,0 +1,24 @@
+-- When a session was marked complete, NULL while it is open
+ALTER TABLE study_sessions ADD COLUMN completed_at TIMESTAMP;
+
+-- Experience earned by each learner and the level it puts them at
+CREATE TABLE IF NOT EXISTS learner_xp (
+    learner TEXT PRIMARY KEY,
+    xp INTEGER NOT NULL DEFAULT 0,
+    level INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Each rewarded review, session and goal, once per learner
+CREATE TABLE IF NOT EXISTS xp_awards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    learner TEXT NOT NULL,
+    source TEXT NOT NULL,
+    source_id INTEGER NOT NULL,
+    study_session_id INTEGER,
+    amount INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_xp_awards_learner_source ON xp_awards(learner, source, source_id);
+CREATE INDEX IF NOT EXISTS idx_xp_awards_study_session_id ON xp_awards(study_session_id);
@@ -0,0 +1,11 @@
+-- Reminder email settings of each learner
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    learner TEXT PRIMARY KEY,
+    email TEXT NOT NULL DEFAULT '',
+    email_frequency TEXT NOT NULL DEFAULT 'off',
+    unsubscribe_token TEXT NOT NULL,
+    last_emailed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_preferences_unsubscribe_token ON notification_preferences(unsubscribe_token);
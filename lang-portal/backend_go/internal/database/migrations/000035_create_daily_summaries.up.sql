@@ -0,0 +1,12 @@
+-- Per-learner study of each day, precomputed by the nightly summary job
+CREATE TABLE IF NOT EXISTS daily_summaries (
+    learner TEXT NOT NULL,
+    day TEXT NOT NULL,
+    sessions INTEGER NOT NULL DEFAULT 0,
+    reviews INTEGER NOT NULL DEFAULT 0,
+    correct_reviews INTEGER NOT NULL DEFAULT 0,
+    new_words INTEGER NOT NULL DEFAULT 0,
+    active_minutes INTEGER NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (learner, day)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS word_review_months;
+DROP TABLE IF EXISTS word_review_archive;
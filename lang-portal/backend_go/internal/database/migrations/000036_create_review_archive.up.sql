@@ -0,0 +1,25 @@
+-- Reviews moved out of word_review_items once older than the retention period
+CREATE TABLE IF NOT EXISTS word_review_archive (
+    id INTEGER PRIMARY KEY,
+    word_id INTEGER NOT NULL,
+    study_session_id INTEGER NOT NULL,
+    correct BOOLEAN NOT NULL,
+    grade TEXT NOT NULL DEFAULT '',
+    answer_time_ms INTEGER,
+    created_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_review_archive_word_id ON word_review_archive(word_id);
+CREATE INDEX IF NOT EXISTS idx_word_review_archive_study_session_id ON word_review_archive(study_session_id);
+
+-- Totals of the archived reviews per word and month
+CREATE TABLE IF NOT EXISTS word_review_months (
+    word_id INTEGER NOT NULL,
+    month TEXT NOT NULL,
+    reviews INTEGER NOT NULL DEFAULT 0,
+    correct_reviews INTEGER NOT NULL DEFAULT 0,
+    timed_reviews INTEGER NOT NULL DEFAULT 0,
+    answer_time_ms INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (word_id, month)
+);
@@ -0,0 +1,2 @@
+-- Number of group words a study session covers, 0 for all of them
+ALTER TABLE study_sessions ADD COLUMN word_limit INTEGER NOT NULL DEFAULT 0;
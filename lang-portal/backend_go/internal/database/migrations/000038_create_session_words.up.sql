@@ -0,0 +1,9 @@
+-- Ordered word queue of each study session, generated when it starts
+CREATE TABLE IF NOT EXISTS session_words (
+    study_session_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    word_id INTEGER NOT NULL,
+    PRIMARY KEY (study_session_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_words_word_id ON session_words(word_id);
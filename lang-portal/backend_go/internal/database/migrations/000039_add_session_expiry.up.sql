@@ -0,0 +1,2 @@
+-- Set when a session left open without reviews is closed by the expiry job
+ALTER TABLE study_sessions ADD COLUMN expired_at TIMESTAMP;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS word_parts;
+DROP TABLE IF EXISTS parts_of_speech;
@@ -0,0 +1,36 @@
+-- Part-of-speech vocabulary and the parts each word is tagged with. words.parts
+-- keeps the ordered tags of a word; word_parts indexes them.
+CREATE TABLE IF NOT EXISTS parts_of_speech (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS word_parts (
+    word_id INTEGER NOT NULL,
+    part_id INTEGER NOT NULL,
+    PRIMARY KEY (word_id, part_id),
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE,
+    FOREIGN KEY (part_id) REFERENCES parts_of_speech(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_parts_part_id ON word_parts(part_id);
+
+-- The tags of the dictionary lookup and the bundled seed data
+INSERT OR IGNORE INTO parts_of_speech (name) VALUES
+    ('noun'), ('pronoun'), ('verb'), ('ichidan'), ('godan'), ('irregular'), ('suru'),
+    ('adjective'), ('i-adjective'), ('na-adjective'), ('adverb'), ('particle'),
+    ('counter'), ('suffix'), ('expression'), ('interjection'), ('conjunction'),
+    ('greeting'), ('farewell'), ('gratitude'), ('present'), ('basic'),
+    ('movement'), ('communication'), ('other');
+
+-- Keep the tags already in use valid, then link every word to its tags
+INSERT OR IGNORE INTO parts_of_speech (name)
+SELECT DISTINCT json_each.value
+FROM words, json_each(words.parts)
+WHERE json_each.value != '';
+
+INSERT OR IGNORE INTO word_parts (word_id, part_id)
+SELECT words.id, parts_of_speech.id
+FROM words, json_each(words.parts)
+JOIN parts_of_speech ON parts_of_speech.name = json_each.value;
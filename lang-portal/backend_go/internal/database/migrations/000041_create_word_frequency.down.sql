@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_words_frequency_rank;
+ALTER TABLE words DROP COLUMN frequency_rank;
+DROP TABLE IF EXISTS word_frequency;
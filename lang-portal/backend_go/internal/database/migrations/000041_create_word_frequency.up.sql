@@ -0,0 +1,14 @@
+-- Word frequency ranks imported from a frequency list, such as one based on
+-- BCCWJ. words.frequency_rank holds the rank of the matching entry.
+CREATE TABLE IF NOT EXISTS word_frequency (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    japanese TEXT NOT NULL,
+    reading TEXT NOT NULL DEFAULT '',
+    rank INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_frequency_japanese ON word_frequency(japanese);
+CREATE INDEX IF NOT EXISTS idx_word_frequency_reading ON word_frequency(reading);
+
+ALTER TABLE words ADD COLUMN frequency_rank INTEGER;
+CREATE INDEX IF NOT EXISTS idx_words_frequency_rank ON words(frequency_rank);
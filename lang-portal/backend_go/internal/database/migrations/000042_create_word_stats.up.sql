@@ -0,0 +1,28 @@
+-- Review totals of each reviewed word, archived reviews included, updated
+-- with every review so that word lists need not count reviews. Words without
+-- a row have never been reviewed.
+CREATE TABLE IF NOT EXISTS word_stats (
+    word_id INTEGER PRIMARY KEY,
+    correct_count INTEGER NOT NULL DEFAULT 0,
+    wrong_count INTEGER NOT NULL DEFAULT 0,
+    last_reviewed_at TIMESTAMP,
+    success_rate REAL NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (word_id) REFERENCES words(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_stats_success_rate ON word_stats(success_rate);
+
+INSERT INTO word_stats (word_id, correct_count, wrong_count, last_reviewed_at, success_rate)
+SELECT word_id,
+    SUM(CASE WHEN correct THEN 1 ELSE 0 END),
+    SUM(CASE WHEN correct THEN 0 ELSE 1 END),
+    MAX(created_at),
+    CAST(SUM(CASE WHEN correct THEN 1 ELSE 0 END) AS REAL) / COUNT(*)
+FROM (
+    SELECT word_id, correct, created_at FROM word_review_items
+    UNION ALL
+    SELECT word_id, correct, created_at FROM word_review_archive
+)
+WHERE word_id IN (SELECT id FROM words)
+GROUP BY word_id;
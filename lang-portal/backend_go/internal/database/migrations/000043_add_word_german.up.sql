@@ -0,0 +1,3 @@
+-- German meanings of words, alongside the English ones; empty when a word has
+-- no German translation yet
+ALTER TABLE words ADD COLUMN german TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_groups_language;
+DROP INDEX IF EXISTS idx_words_language;
+ALTER TABLE groups DROP COLUMN language;
+ALTER TABLE words DROP COLUMN language;
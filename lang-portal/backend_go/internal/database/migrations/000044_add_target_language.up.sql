@@ -0,0 +1,6 @@
+-- Words and groups belong to the language they teach, by ISO 639-1 code;
+-- everything before this migration is Japanese
+ALTER TABLE words ADD COLUMN language TEXT NOT NULL DEFAULT 'ja';
+ALTER TABLE groups ADD COLUMN language TEXT NOT NULL DEFAULT 'ja';
+CREATE INDEX IF NOT EXISTS idx_words_language ON words(language);
+CREATE INDEX IF NOT EXISTS idx_groups_language ON groups(language);
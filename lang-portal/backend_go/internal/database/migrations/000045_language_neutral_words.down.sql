@@ -0,0 +1,20 @@
+CREATE TABLE groups_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INTEGER NOT NULL DEFAULT 1,
+    share_slug TEXT,
+    language TEXT NOT NULL DEFAULT 'ja'
+);
+INSERT INTO groups_old (id, name, created_at, updated_at, version, share_slug, language)
+    SELECT id, name, created_at, updated_at, version, share_slug, language FROM groups;
+DROP TABLE groups;
+ALTER TABLE groups_old RENAME TO groups;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_share_slug ON groups(share_slug);
+CREATE INDEX IF NOT EXISTS idx_groups_language ON groups(language);
+
+ALTER TABLE words DROP COLUMN reading_script;
+ALTER TABLE words DROP COLUMN script;
+ALTER TABLE words RENAME COLUMN reading TO romaji;
+ALTER TABLE words RENAME COLUMN term TO japanese;
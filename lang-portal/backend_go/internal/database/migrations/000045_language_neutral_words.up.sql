@@ -0,0 +1,27 @@
+-- Words keep the term and reading of any target language, so the columns
+-- named after Japanese are renamed; each word records the ISO 15924 scripts
+-- of its term and reading, backfilled from its language
+ALTER TABLE words RENAME COLUMN japanese TO term;
+ALTER TABLE words RENAME COLUMN romaji TO reading;
+ALTER TABLE words ADD COLUMN script TEXT NOT NULL DEFAULT '';
+ALTER TABLE words ADD COLUMN reading_script TEXT NOT NULL DEFAULT 'Latn';
+UPDATE words SET script = CASE language WHEN 'ja' THEN 'Jpan' WHEN 'ko' THEN 'Kore' ELSE '' END;
+
+-- Group names are unique per language. SQLite cannot drop the column
+-- constraint on name, so the table is rebuilt.
+CREATE TABLE groups_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version INTEGER NOT NULL DEFAULT 1,
+    share_slug TEXT,
+    language TEXT NOT NULL DEFAULT 'ja'
+);
+INSERT INTO groups_new (id, name, created_at, updated_at, version, share_slug, language)
+    SELECT id, name, created_at, updated_at, version, share_slug, language FROM groups;
+DROP TABLE groups;
+ALTER TABLE groups_new RENAME TO groups;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_name_language ON groups(name, language);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_groups_share_slug ON groups(share_slug);
+CREATE INDEX IF NOT EXISTS idx_groups_language ON groups(language);
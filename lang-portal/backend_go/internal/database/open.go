@@ -0,0 +1,174 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Options configures the SQLite connection. The pragmas are set on every
+// pooled connection through the DSN.
+type Options struct {
+	// JournalMode is one of DELETE, TRUNCATE, PERSIST, MEMORY, WAL or OFF
+	JournalMode string
+	// Synchronous is one of OFF, NORMAL, FULL or EXTRA
+	Synchronous string
+	// BusyTimeout is how long a connection waits for a lock before failing
+	// with "database is locked"
+	BusyTimeout time.Duration
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// SlowQueryThreshold is the duration above which the query logger reports
+	// a query as slow; zero disables the check
+	SlowQueryThreshold time.Duration
+}
+
+// DefaultOptions returns settings suited to several concurrent study clients:
+// WAL lets readers proceed while a write is in progress, and writers queue on
+// the busy timeout instead of failing immediately.
+func DefaultOptions() Options {
+	return Options{
+		JournalMode:        "WAL",
+		Synchronous:        "NORMAL",
+		BusyTimeout:        5 * time.Second,
+		MaxOpenConns:       4,
+		MaxIdleConns:       4,
+		ConnMaxLifetime:    time.Hour,
+		SlowQueryThreshold: 200 * time.Millisecond,
+	}
+}
+
+// Environment variables read by OptionsFromEnv
+const (
+	EnvJournalMode     = "LANG_PORTAL_DB_JOURNAL_MODE"
+	EnvSynchronous     = "LANG_PORTAL_DB_SYNCHRONOUS"
+	EnvBusyTimeout     = "LANG_PORTAL_DB_BUSY_TIMEOUT"
+	EnvMaxOpenConns    = "LANG_PORTAL_DB_MAX_OPEN_CONNS"
+	EnvMaxIdleConns    = "LANG_PORTAL_DB_MAX_IDLE_CONNS"
+	EnvConnMaxLifetime = "LANG_PORTAL_DB_CONN_MAX_LIFETIME"
+	EnvSlowQuery       = "LANG_PORTAL_DB_SLOW_QUERY"
+)
+
+// OptionsFromEnv returns DefaultOptions overridden by any LANG_PORTAL_DB_*
+// environment variables that are set. Durations use Go syntax, e.g. "10s".
+func OptionsFromEnv() (Options, error) {
+	opts := DefaultOptions()
+
+	if v := os.Getenv(EnvJournalMode); v != "" {
+		opts.JournalMode = v
+	}
+	if v := os.Getenv(EnvSynchronous); v != "" {
+		opts.Synchronous = v
+	}
+	if v := os.Getenv(EnvBusyTimeout); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %w", EnvBusyTimeout, err)
+		}
+		opts.BusyTimeout = d
+	}
+	if v := os.Getenv(EnvMaxOpenConns); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %w", EnvMaxOpenConns, err)
+		}
+		opts.MaxOpenConns = n
+	}
+	if v := os.Getenv(EnvMaxIdleConns); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %w", EnvMaxIdleConns, err)
+		}
+		opts.MaxIdleConns = n
+	}
+	if v := os.Getenv(EnvConnMaxLifetime); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %w", EnvConnMaxLifetime, err)
+		}
+		opts.ConnMaxLifetime = d
+	}
+	if v := os.Getenv(EnvSlowQuery); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %w", EnvSlowQuery, err)
+		}
+		opts.SlowQueryThreshold = d
+	}
+
+	return opts, opts.validate()
+}
+
+func (o Options) validate() error {
+	switch strings.ToUpper(o.JournalMode) {
+	case "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF":
+	default:
+		return fmt.Errorf("invalid journal mode %q", o.JournalMode)
+	}
+	switch strings.ToUpper(o.Synchronous) {
+	case "OFF", "NORMAL", "FULL", "EXTRA":
+	default:
+		return fmt.Errorf("invalid synchronous setting %q", o.Synchronous)
+	}
+	if o.BusyTimeout < 0 {
+		return fmt.Errorf("busy timeout must not be negative")
+	}
+	if o.MaxOpenConns < 0 || o.MaxIdleConns < 0 {
+		return fmt.Errorf("connection limits must not be negative")
+	}
+	if o.SlowQueryThreshold < 0 {
+		return fmt.Errorf("slow query threshold must not be negative")
+	}
+	return nil
+}
+
+// dsn builds the go-sqlite3 connection string for path with the pragmas in opts
+func (o Options) dsn(path string) string {
+	params := url.Values{}
+	params.Set("_journal_mode", strings.ToUpper(o.JournalMode))
+	params.Set("_synchronous", strings.ToUpper(o.Synchronous))
+	params.Set("_busy_timeout", strconv.FormatInt(o.BusyTimeout.Milliseconds(), 10))
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + params.Encode()
+}
+
+// Open opens the SQLite database at path with the given options. The
+// directory of a new database is created if needed.
+func Open(path string, opts Options, config *gorm.Config) (*gorm.DB, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if dir := filepath.Dir(strings.TrimPrefix(path, "file:")); dir != "." && !strings.Contains(path, ":memory:") {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create the database directory: %w", err)
+		}
+	}
+
+	db, err := gorm.Open(sqlite.Open(opts.dsn(path)), config)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(opts.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(opts.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(opts.ConnMaxLifetime)
+
+	return db, nil
+}
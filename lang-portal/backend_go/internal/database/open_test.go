@@ -0,0 +1,67 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestOpen_AppliesPragmasAndPool(t *testing.T) {
+	opts := DefaultOptions()
+	opts.BusyTimeout = 2500 * time.Millisecond
+	opts.MaxOpenConns = 3
+
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"), opts, &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	var journalMode string
+	require.NoError(t, db.Raw("PRAGMA journal_mode").Scan(&journalMode).Error)
+	assert.Equal(t, "wal", journalMode)
+
+	var synchronous, busyTimeout int
+	require.NoError(t, db.Raw("PRAGMA synchronous").Scan(&synchronous).Error)
+	assert.Equal(t, 1, synchronous, "NORMAL")
+	require.NoError(t, db.Raw("PRAGMA busy_timeout").Scan(&busyTimeout).Error)
+	assert.Equal(t, 2500, busyTimeout)
+
+	assert.Equal(t, 3, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Setenv(EnvJournalMode, "delete")
+	t.Setenv(EnvBusyTimeout, "10s")
+	t.Setenv(EnvMaxOpenConns, "8")
+	t.Setenv(EnvSlowQuery, "50ms")
+
+	opts, err := OptionsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 50*time.Millisecond, opts.SlowQueryThreshold)
+	assert.Equal(t, "delete", opts.JournalMode)
+	assert.Equal(t, 10*time.Second, opts.BusyTimeout)
+	assert.Equal(t, 8, opts.MaxOpenConns)
+	assert.Equal(t, DefaultOptions().Synchronous, opts.Synchronous)
+}
+
+func TestOptionsFromEnv_Invalid(t *testing.T) {
+	tests := map[string]string{
+		EnvJournalMode:  "fast",
+		EnvSynchronous:  "sometimes",
+		EnvBusyTimeout:  "5",
+		EnvMaxOpenConns: "-1",
+		EnvSlowQuery:    "-1s",
+	}
+	for env, value := range tests {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			_, err := OptionsFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
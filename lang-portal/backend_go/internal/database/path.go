@@ -0,0 +1,71 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables read by PathFromEnv
+const (
+	// EnvPath is the path of the SQLite database
+	EnvPath = "LANG_PORTAL_DB"
+	// EnvURL is the database as a URL, such as sqlite:///data/words.db, for
+	// platforms that configure databases that way; EnvPath takes precedence
+	EnvURL = "DATABASE_URL"
+	// EnvDataDir is the directory relative database paths are resolved
+	// against, such as a volume mounted into a container whose working
+	// directory is read-only
+	EnvDataDir = "LANG_PORTAL_DATA_DIR"
+)
+
+// DefaultPath is the database file used when none is configured
+const DefaultPath = "words.db"
+
+// PathFromEnv returns the path of the SQLite database: EnvPath, otherwise
+// the file of EnvURL, otherwise DefaultPath. A relative path is taken to be
+// in EnvDataDir when that is set.
+func PathFromEnv() (string, error) {
+	path := os.Getenv(EnvPath)
+	if path == "" {
+		if raw := os.Getenv(EnvURL); raw != "" {
+			var err error
+			if path, err = PathFromURL(raw); err != nil {
+				return "", fmt.Errorf("invalid %s: %w", EnvURL, err)
+			}
+		}
+	}
+	if path == "" {
+		path = DefaultPath
+	}
+
+	if dir := os.Getenv(EnvDataDir); dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+// PathFromURL returns the file of a SQLite database URL: sqlite:///abs/path
+// and sqlite://rel/path (or sqlite3://), a file: URI or a plain path.
+// Connection settings are configured through the LANG_PORTAL_DB_* variables,
+// so URLs with a query are rejected.
+func PathFromURL(raw string) (string, error) {
+	path := raw
+	for _, scheme := range []string{"sqlite://", "sqlite3://", "file://", "file:"} {
+		if strings.HasPrefix(raw, scheme) {
+			path = strings.TrimPrefix(raw, scheme)
+			break
+		}
+	}
+	if scheme, _, ok := strings.Cut(path, "://"); ok {
+		return "", fmt.Errorf("unsupported database %s://, only SQLite is supported", scheme)
+	}
+	if strings.Contains(path, "?") {
+		return "", fmt.Errorf("%q has a query; set the connection options with the LANG_PORTAL_DB_* variables", raw)
+	}
+	if path == "" {
+		return "", fmt.Errorf("%q names no database file", raw)
+	}
+	return path, nil
+}
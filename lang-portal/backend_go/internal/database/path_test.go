@@ -0,0 +1,59 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestPathFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		url     string
+		dataDir string
+		want    string
+	}{
+		{name: "default", want: "words.db"},
+		{name: "path", path: "/srv/portal.db", url: "sqlite:///ignored.db", want: "/srv/portal.db"},
+		{name: "absolute URL", url: "sqlite:///data/words.db", want: "/data/words.db"},
+		{name: "relative URL", url: "sqlite://portal.db", want: "portal.db"},
+		{name: "file URI", url: "file:portal.db", want: "portal.db"},
+		{name: "data directory", dataDir: "/data", want: filepath.Join("/data", "words.db")},
+		{name: "relative path in data directory", path: "db/portal.db", dataDir: "/data", want: filepath.Join("/data", "db/portal.db")},
+		{name: "absolute path outside data directory", url: "sqlite:///srv/words.db", dataDir: "/data", want: "/srv/words.db"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvPath, tt.path)
+			t.Setenv(EnvURL, tt.url)
+			t.Setenv(EnvDataDir, tt.dataDir)
+
+			path, err := PathFromEnv()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, path)
+		})
+	}
+}
+
+func TestPathFromURL_Invalid(t *testing.T) {
+	for _, raw := range []string{"postgres://user@db/portal", "sqlite:///data/words.db?_journal_mode=WAL", "sqlite://"} {
+		_, err := PathFromURL(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestOpen_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "words.db")
+
+	db, err := Open(path, DefaultOptions(), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Ping())
+	assert.FileExists(t, path)
+	sqlDB.Close()
+}
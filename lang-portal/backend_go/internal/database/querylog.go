@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/telemetry"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// SlowQueryMetric counts queries that took longer than the slow query
+// threshold, by SQL operation and by the route of the request that ran them
+const SlowQueryMetric = "db.client.slow_queries"
+
+// QueryLogger is a gorm logger that tags every line with the ID and route of
+// the request that ran the query, logs queries slower than a threshold at WARN
+// and counts them in the SlowQueryMetric counter. Lookups that find nothing
+// are expected by the repositories and are not logged as errors.
+type QueryLogger struct {
+	out           *log.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	slowQueries   metric.Int64Counter
+}
+
+// NewQueryLogger creates a query logger writing to out at level. Queries
+// taking longer than slowThreshold are logged at WARN and counted; zero
+// disables the check. The counter is created on the global meter provider.
+func NewQueryLogger(out *log.Logger, level gormlogger.LogLevel, slowThreshold time.Duration) *QueryLogger {
+	// Creating a counter only fails for invalid names, and then returns a no-op one
+	slowQueries, _ := otel.Meter("lang-portal/database").Int64Counter(SlowQueryMetric,
+		metric.WithDescription("Database queries slower than the slow query threshold"),
+		metric.WithUnit("{query}"))
+	return &QueryLogger{
+		out:           out,
+		level:         level,
+		slowThreshold: slowThreshold,
+		slowQueries:   slowQueries,
+	}
+}
+
+// LogMode implements gormlogger.Interface
+func (l *QueryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info implements gormlogger.Interface
+func (l *QueryLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.printf(ctx, "INFO", msg, data...)
+	}
+}
+
+// Warn implements gormlogger.Interface
+func (l *QueryLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.printf(ctx, "WARN", msg, data...)
+	}
+}
+
+// Error implements gormlogger.Interface
+func (l *QueryLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.printf(ctx, "ERROR", msg, data...)
+	}
+}
+
+// Trace implements gormlogger.Interface. It is called after every query.
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	// Rendering the SQL is the costly part; skip it for queries nobody sees
+	if !slow && !failed && l.level < gormlogger.Info {
+		return
+	}
+
+	sql, rows := fc()
+	if slow {
+		l.slowQueries.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("db.operation.name", operation(sql)),
+			attribute.String("http.route", route(ctx)),
+		))
+	}
+	switch {
+	case failed && l.level >= gormlogger.Error:
+		l.printf(ctx, "ERROR", "%s [%s] [rows:%d] %s", err, elapsed, rows, sql)
+	case slow && l.level >= gormlogger.Warn:
+		l.printf(ctx, "WARN", "slow query over %s [%s] [rows:%d] %s", l.slowThreshold, elapsed, rows, sql)
+	case l.level >= gormlogger.Info:
+		l.printf(ctx, "INFO", "[%s] [rows:%d] %s", elapsed, rows, sql)
+	}
+}
+
+// printf writes a line prefixed with the level and the request of ctx
+func (l *QueryLogger) printf(ctx context.Context, level, format string, args ...interface{}) {
+	req, _ := telemetry.RequestFrom(ctx)
+	id := req.ID
+	if id == "" {
+		id = "-"
+	}
+	l.out.Printf("[%s] req=%s route=%s %s", level, id, route(ctx), fmt.Sprintf(format, args...))
+}
+
+// route returns the route of the request of ctx, or "-" outside requests
+func route(ctx context.Context) string {
+	if req, ok := telemetry.RequestFrom(ctx); ok && req.Route != "" {
+		return req.Route
+	}
+	return "-"
+}
+
+// operation returns the SQL operation of a statement, such as SELECT
+func operation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/telemetry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestQueryLogger(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	var out bytes.Buffer
+	logger := NewQueryLogger(log.New(&out, "", 0), gormlogger.Warn, 100*time.Millisecond)
+	ctx := telemetry.WithRequest(context.Background(), telemetry.Request{ID: "abc123", Route: "/api/words/:id"})
+	query := func(sql string) func() (string, int64) {
+		return func() (string, int64) { return sql, 1 }
+	}
+
+	// Fast queries are only logged at the info level
+	logger.Trace(ctx, time.Now(), query("SELECT 1"), nil)
+	assert.Empty(t, out.String())
+	logger.LogMode(gormlogger.Info).Trace(ctx, time.Now(), query("SELECT 1"), nil)
+	assert.Contains(t, out.String(), "[INFO] req=abc123 route=/api/words/:id")
+	out.Reset()
+
+	logger.Trace(ctx, time.Now().Add(-time.Second), query("select * from words"), nil)
+	assert.Contains(t, out.String(), "[WARN] req=abc123 route=/api/words/:id slow query over 100ms")
+	assert.Contains(t, out.String(), "select * from words")
+	out.Reset()
+
+	// Queries outside requests are counted too
+	logger.Trace(context.Background(), time.Now().Add(-time.Second), query("UPDATE words SET english = 'x'"), nil)
+	assert.Contains(t, out.String(), "[WARN] req=- route=-")
+	out.Reset()
+
+	logger.Trace(ctx, time.Now(), query("SELECT * FROM groups"), gorm.ErrRecordNotFound)
+	assert.Empty(t, out.String(), "lookups that find nothing are not errors")
+	logger.Trace(ctx, time.Now(), query("SELECT * FROM nope"), errors.New("no such table: nope"))
+	assert.Contains(t, out.String(), "[ERROR] req=abc123 route=/api/words/:id no such table: nope")
+
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	counts := map[string]int64{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != SlowQueryMetric {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				op, _ := point.Attributes.Value(attribute.Key("db.operation.name"))
+				route, _ := point.Attributes.Value(attribute.Key("http.route"))
+				counts[op.AsString()+" "+route.AsString()] = point.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"SELECT /api/words/:id": 1,
+		"UPDATE -":              1,
+	}, counts)
+}
+
+func TestQueryLogger_ThresholdDisabled(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewQueryLogger(log.New(&out, "", 0), gormlogger.Warn, 0)
+	logger.Trace(context.Background(), time.Now().Add(-time.Minute), func() (string, int64) {
+		t.Fatal("the SQL of an unlogged query is not rendered")
+		return "", 0
+	}, nil)
+	assert.Empty(t, out.String())
+}
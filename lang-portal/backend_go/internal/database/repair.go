@@ -0,0 +1,188 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// RepairAssociationsName identifies the association repair in data_migrations
+const RepairAssociationsName = "repair_associations"
+
+// errDryRun rolls back the repair transaction after counting the affected rows
+var errDryRun = errors.New("dry run")
+
+// RepairReport counts the rows removed (or, in a dry run, that would be removed)
+// from the association tables
+type RepairReport struct {
+	// ZeroIDWordGroups are word_groups rows with a zero word or group ID
+	ZeroIDWordGroups int64 `json:"zero_id_word_groups"`
+	// OrphanedWordGroups are word_groups rows whose word or group no longer exists
+	OrphanedWordGroups int64 `json:"orphaned_word_groups"`
+	// DuplicateWordGroups are repeated (word_id, group_id) rows beyond the first
+	DuplicateWordGroups int64 `json:"duplicate_word_groups"`
+	// ZeroIDReviews are word_review_items rows with a zero word or session ID
+	ZeroIDReviews int64 `json:"zero_id_reviews"`
+	// OrphanedReviews are word_review_items rows whose word or session no longer exists
+	OrphanedReviews int64 `json:"orphaned_reviews"`
+	// AlreadyApplied is set when the repair was recorded as done and nothing was checked
+	AlreadyApplied bool `json:"-"`
+	// DryRun is set when the changes were rolled back
+	DryRun bool `json:"-"`
+}
+
+// Total returns the number of rows removed
+func (r *RepairReport) Total() int64 {
+	return r.ZeroIDWordGroups + r.OrphanedWordGroups + r.DuplicateWordGroups + r.ZeroIDReviews + r.OrphanedReviews
+}
+
+func (r *RepairReport) String() string {
+	return fmt.Sprintf("word_groups: %d zero-ID, %d orphaned, %d duplicate; word_review_items: %d zero-ID, %d orphaned",
+		r.ZeroIDWordGroups, r.OrphanedWordGroups, r.DuplicateWordGroups, r.ZeroIDReviews, r.OrphanedReviews)
+}
+
+// RepairAssociations removes association rows left behind by historical bugs:
+// rows with zero IDs, rows pointing at deleted words, groups or sessions, and
+// duplicate word/group links. It then enforces uniqueness of word/group links,
+// which databases created before versioned migrations may lack.
+//
+// The repair runs once per database and is recorded in data_migrations. With
+// dryRun set the changes are rolled back and only the report is returned; a dry
+// run always checks, even if the repair was already applied.
+func RepairAssociations(db *gorm.DB, dryRun bool) (*RepairReport, error) {
+	if !dryRun {
+		applied, err := dataMigrationApplied(db, RepairAssociationsName)
+		if err != nil {
+			return nil, err
+		}
+		if applied {
+			return &RepairReport{AlreadyApplied: true}, nil
+		}
+	}
+
+	report := &RepairReport{DryRun: dryRun}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		steps := []struct {
+			count *int64
+			sql   string
+		}{
+			{&report.ZeroIDWordGroups, `DELETE FROM word_groups
+				WHERE word_id IS NULL OR word_id = 0 OR group_id IS NULL OR group_id = 0`},
+			{&report.OrphanedWordGroups, `DELETE FROM word_groups
+				WHERE word_id NOT IN (SELECT id FROM words) OR group_id NOT IN (SELECT id FROM groups)`},
+			{&report.DuplicateWordGroups, `DELETE FROM word_groups
+				WHERE rowid NOT IN (SELECT MIN(rowid) FROM word_groups GROUP BY word_id, group_id)`},
+			{&report.ZeroIDReviews, `DELETE FROM word_review_items
+				WHERE word_id IS NULL OR word_id = 0 OR study_session_id IS NULL OR study_session_id = 0`},
+			{&report.OrphanedReviews, `DELETE FROM word_review_items
+				WHERE word_id NOT IN (SELECT id FROM words) OR study_session_id NOT IN (SELECT id FROM study_sessions)`},
+		}
+		for _, step := range steps {
+			result := tx.Exec(step.sql)
+			if result.Error != nil {
+				return fmt.Errorf("failed to repair associations: %w", result.Error)
+			}
+			*step.count = result.RowsAffected
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_word_groups_word_group
+			ON word_groups(word_id, group_id)`).Error; err != nil {
+			return fmt.Errorf("failed to enforce unique word groups: %w", err)
+		}
+
+		details, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&models.DataMigration{Name: RepairAssociationsName, Report: details}).Error
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// BackfillWordProgressName identifies the word progress backfill in data_migrations
+const BackfillWordProgressName = "backfill_word_progress"
+
+// BackfillWordProgress gives every reviewed word without progress the progress
+// its reviews lead to, replaying them in order. Reviews made afterwards advance
+// progress as they are added. The backfill runs once per database and returns
+// the number of words it gave progress.
+func BackfillWordProgress(db *gorm.DB) (int, error) {
+	applied, err := dataMigrationApplied(db, BackfillWordProgressName)
+	if err != nil || applied {
+		return 0, err
+	}
+
+	var progress []models.WordProgress
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var reviews []models.WordReview
+		if err := tx.Where("word_id NOT IN (SELECT word_id FROM word_progress)").
+			Order("word_id ASC, created_at ASC, id ASC").
+			Find(&reviews).Error; err != nil {
+			return fmt.Errorf("failed to read reviews: %w", err)
+		}
+		now := time.Now()
+		for _, review := range reviews {
+			if len(progress) == 0 || progress[len(progress)-1].WordID != review.WordID {
+				progress = append(progress, models.WordProgress{WordID: review.WordID, UpdatedAt: now})
+			}
+			progress[len(progress)-1].Advance(review.EffectiveGrade(), review.CreatedAt)
+		}
+		if len(progress) > 0 {
+			if err := tx.CreateInBatches(&progress, 500).Error; err != nil {
+				return fmt.Errorf("failed to backfill word progress: %w", err)
+			}
+		}
+
+		details, err := json.Marshal(map[string]int{"words": len(progress)})
+		if err != nil {
+			return err
+		}
+		return tx.Create(&models.DataMigration{Name: BackfillWordProgressName, Report: details}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(progress), nil
+}
+
+// applyDataMigrations runs the one-time data repairs that have not been applied yet
+func applyDataMigrations(db *gorm.DB) error {
+	report, err := RepairAssociations(db, false)
+	if err != nil {
+		return err
+	}
+	if !report.AlreadyApplied && report.Total() > 0 {
+		log.Printf("Repaired association tables: %s", report)
+	}
+
+	words, err := BackfillWordProgress(db)
+	if err != nil {
+		return err
+	}
+	if words > 0 {
+		log.Printf("Backfilled the progress of %d reviewed words", words)
+	}
+	return nil
+}
+
+// dataMigrationApplied reports whether the named data migration has been recorded
+func dataMigrationApplied(db *gorm.DB, name string) (bool, error) {
+	var count int64
+	if err := db.Model(&models.DataMigration{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check data migration %s: %w", name, err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// seedBrokenAssociations builds a legacy database whose word_groups table has no
+// primary key, and fills the association tables with the rows old bugs left behind
+func seedBrokenAssociations(t *testing.T) *gorm.DB {
+	db := openMemoryDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE word_groups (word_id INTEGER, group_id INTEGER)`).Error)
+	require.NoError(t, db.AutoMigrate(schemaModels...))
+
+	word := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(word).Error)
+	group := &models.Group{Name: "Animals"}
+	require.NoError(t, db.Create(group).Error)
+	activity := &models.StudyActivity{Name: "Flashcards", Description: "Cards", ThumbnailURL: "/cards.png"}
+	require.NoError(t, db.Create(activity).Error)
+	session := &models.StudySession{GroupID: group.ID, StudyActivityID: activity.ID}
+	require.NoError(t, db.Create(session).Error)
+
+	for _, link := range [][2]uint{
+		{word.ID, group.ID},
+		{word.ID, group.ID}, // duplicate
+		{0, group.ID},       // zero word ID
+		{word.ID, 999},      // deleted group
+	} {
+		require.NoError(t, db.Exec(`INSERT INTO word_groups (word_id, group_id) VALUES (?, ?)`, link[0], link[1]).Error)
+	}
+	for _, review := range [][2]uint{
+		{word.ID, session.ID},
+		{0, session.ID}, // zero word ID
+		{word.ID, 999},  // deleted session
+	} {
+		require.NoError(t, db.Exec(`INSERT INTO word_review_items (word_id, study_session_id, correct) VALUES (?, ?, 1)`, review[0], review[1]).Error)
+	}
+	return db
+}
+
+func countRows(t *testing.T, db *gorm.DB, table string) int64 {
+	var count int64
+	require.NoError(t, db.Table(table).Count(&count).Error)
+	return count
+}
+
+func TestRepairAssociations_DryRun(t *testing.T) {
+	db := seedBrokenAssociations(t)
+
+	report, err := RepairAssociations(db, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, int64(1), report.ZeroIDWordGroups)
+	assert.Equal(t, int64(1), report.OrphanedWordGroups)
+	assert.Equal(t, int64(1), report.DuplicateWordGroups)
+	assert.Equal(t, int64(1), report.ZeroIDReviews)
+	assert.Equal(t, int64(1), report.OrphanedReviews)
+
+	// Nothing was changed or recorded
+	assert.Equal(t, int64(4), countRows(t, db, "word_groups"))
+	assert.Equal(t, int64(3), countRows(t, db, "word_review_items"))
+	assert.Zero(t, countRows(t, db, "data_migrations"))
+}
+
+func TestMigrate_RepairsAssociationsOnce(t *testing.T) {
+	db := seedBrokenAssociations(t)
+
+	require.NoError(t, Migrate(db))
+	assert.Equal(t, int64(1), countRows(t, db, "word_groups"))
+	assert.Equal(t, int64(1), countRows(t, db, "word_review_items"))
+
+	var recorded models.DataMigration
+	require.NoError(t, db.First(&recorded, "name = ?", RepairAssociationsName).Error)
+	assert.JSONEq(t, `{"zero_id_word_groups":1,"orphaned_word_groups":1,"duplicate_word_groups":1,"zero_id_reviews":1,"orphaned_reviews":1}`, string(recorded.Report))
+
+	// Links are unique from now on
+	var link struct{ WordID, GroupID uint }
+	require.NoError(t, db.Table("word_groups").Select("word_id, group_id").Scan(&link).Error)
+	assert.Error(t, db.Exec(`INSERT INTO word_groups (word_id, group_id) VALUES (?, ?)`, link.WordID, link.GroupID).Error)
+
+	report, err := RepairAssociations(db, false)
+	require.NoError(t, err)
+	assert.True(t, report.AlreadyApplied)
+	assert.Zero(t, report.Total())
+}
+
+func TestMigrate_BackfillsWordProgressOnce(t *testing.T) {
+	db := seedBrokenAssociations(t)
+	// Two more correct answers move the word from learning to review
+	require.NoError(t, db.Exec(`INSERT INTO word_review_items (word_id, study_session_id, correct)
+		SELECT word_id, study_session_id, 1 FROM word_review_items WHERE word_id <> 0 AND study_session_id <> 999`).Error)
+
+	require.NoError(t, Migrate(db))
+	var progress []models.WordProgress
+	require.NoError(t, db.Find(&progress).Error)
+	require.Len(t, progress, 1, "only reviews kept by the association repair are replayed")
+	assert.Equal(t, models.ProgressReview, progress[0].State)
+	assert.Equal(t, 2, progress[0].Reviews)
+
+	var recorded models.DataMigration
+	require.NoError(t, db.First(&recorded, "name = ?", BackfillWordProgressName).Error)
+	assert.JSONEq(t, `{"words":1}`, string(recorded.Report))
+
+	words, err := BackfillWordProgress(db)
+	require.NoError(t, err)
+	assert.Zero(t, words)
+}
@@ -1,105 +1,152 @@
 package database
 
 import (
-	"lang-portal/backend_go/internal/models"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
 
 	"gorm.io/gorm"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// seedFiles holds the seed profiles in profiles.json and the word lists they
+// load, JSON arrays in the format of `langctl words export`
+//
+//go:embed seeds/*.json
+var seedFiles embed.FS
+
+// Seed profiles. DefaultSeedProfile fills a new database unless another
+// profile is chosen.
+const (
+	SeedProfileMinimal = "minimal"
+	SeedProfileDemo    = "demo"
+	SeedProfileJLPTN5  = "jlpt-n5"
+	DefaultSeedProfile = SeedProfileDemo
 )
 
-// Seed populates the database with initial data
-func Seed(db *gorm.DB) error {
-	// Create study activities
-	activities := []models.StudyActivity{
-		{
-			Name: "Reading Practice",
-			Description: "Practice reading Japanese text",
-			ThumbnailURL: "/images/reading.png",
-		},
-		{
-			Name: "Writing Practice",
-			Description: "Practice writing Japanese characters",
-			ThumbnailURL: "/images/writing.png",
-		},
-		{
-			Name: "Listening Practice",
-			Description: "Practice listening to Japanese",
-			ThumbnailURL: "/images/listening.png",
-		},
-		{
-			Name: "Speaking Practice",
-			Description: "Practice speaking Japanese",
-			ThumbnailURL: "/images/speaking.png",
-		},
+// seedGroup names a word list of a profile and the group its words are added to
+type seedGroup struct {
+	File  string `json:"file"`
+	Group string `json:"group"`
+}
+
+// seedWord is an entry of a word list
+type seedWord struct {
+	Japanese string   `json:"japanese"`
+	Romaji   string   `json:"romaji"`
+	English  string   `json:"english"`
+	Parts    []string `json:"parts"`
+}
+
+// SeedResult counts the rows Seed added
+type SeedResult struct {
+	Groups int
+	Words  int
+	// Memberships are words added to a group, including existing words
+	Memberships int
+}
+
+// loadSeedProfiles reads the profile manifest
+func loadSeedProfiles() (map[string][]seedGroup, error) {
+	data, err := seedFiles.ReadFile("seeds/profiles.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed profiles: %w", err)
 	}
-	if err := db.Create(&activities).Error; err != nil {
-		return err
+	var profiles map[string][]seedGroup
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse seed profiles: %w", err)
 	}
+	return profiles, nil
+}
 
-	// Create groups
-	groups := []models.Group{
-		{Name: "Basic Greetings"},
-		{Name: "Numbers"},
-		{Name: "Days of the Week"},
-		{Name: "Common Verbs"},
+// SeedProfiles returns the names of the seed profiles, sorted
+func SeedProfiles() ([]string, error) {
+	profiles, err := loadSeedProfiles()
+	if err != nil {
+		return nil, err
 	}
-	if err := db.Create(&groups).Error; err != nil {
-		return err
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names, nil
+}
 
-	// Create words
-	words := []models.Word{
-		{
-			Japanese: "こんにちは",
-			Romaji:   "konnichiwa",
-			English:  "Hello",
-			Groups:   []models.Group{groups[0]},
-		},
-		{
-			Japanese: "さようなら",
-			Romaji:   "sayounara",
-			English:  "Goodbye",
-			Groups:   []models.Group{groups[0]},
-		},
-		{
-			Japanese: "一",
-			Romaji:   "ichi",
-			English:  "One",
-			Groups:   []models.Group{groups[1]},
-		},
-		{
-			Japanese: "二",
-			Romaji:   "ni",
-			English:  "Two",
-			Groups:   []models.Group{groups[1]},
-		},
-		{
-			Japanese: "月曜日",
-			Romaji:   "getsuyoubi",
-			English:  "Monday",
-			Groups:   []models.Group{groups[2]},
-		},
-		{
-			Japanese: "火曜日",
-			Romaji:   "kayoubi",
-			English:  "Tuesday",
-			Groups:   []models.Group{groups[2]},
-		},
-		{
-			Japanese: "食べる",
-			Romaji:   "taberu",
-			English:  "To eat",
-			Groups:   []models.Group{groups[3]},
-		},
-		{
-			Japanese: "飲む",
-			Romaji:   "nomu",
-			English:  "To drink",
-			Groups:   []models.Group{groups[3]},
-		},
+// loadSeedWords reads a word list of a profile
+func loadSeedWords(file string) ([]seedWord, error) {
+	data, err := seedFiles.ReadFile(path.Join("seeds", file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file %s: %w", file, err)
 	}
-	if err := db.Create(&words).Error; err != nil {
-		return err
+	var words []seedWord
+	if err := json.Unmarshal(data, &words); err != nil {
+		return nil, fmt.Errorf("failed to parse seed file %s: %w", file, err)
 	}
+	return words, nil
+}
 
-	return nil
+// Seed adds the groups and words of profile in one transaction. It can run
+// any number of times and on top of other profiles: groups are matched by
+// name and words by their Japanese text, and only the missing ones and their
+// group memberships are added, so words learners have edited are kept as they
+// are. The words are written directly, so their kanji, parts of speech and
+// frequency ranks are linked afterwards, like other words written outside the
+// word repository.
+func Seed(db *gorm.DB, profile string) (SeedResult, error) {
+	var result SeedResult
+
+	profiles, err := loadSeedProfiles()
+	if err != nil {
+		return result, err
+	}
+	groups, ok := profiles[profile]
+	if !ok {
+		names, _ := SeedProfiles()
+		return result, fmt.Errorf("unknown seed profile %q: use %s", profile, strings.Join(names, ", "))
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, sg := range groups {
+			words, err := loadSeedWords(sg.File)
+			if err != nil {
+				return err
+			}
+
+			group := models.Group{Name: sg.Group}
+			created := tx.Where(models.Group{Name: sg.Group}).FirstOrCreate(&group)
+			if created.Error != nil {
+				return fmt.Errorf("failed to seed group %s: %w", sg.Group, created.Error)
+			}
+			result.Groups += int(created.RowsAffected)
+
+			for _, sw := range words {
+				word := models.Word{Term: sw.Japanese, Reading: sw.Romaji, English: sw.English, Parts: sw.Parts}
+				if err := word.Validate(); err != nil {
+					return fmt.Errorf("invalid word %s in seed file %s: %w", sw.Japanese, sg.File, err)
+				}
+				word.DefaultScripts()
+				created := tx.Where("term = ?", sw.Japanese).Order("id").FirstOrCreate(&word)
+				if created.Error != nil {
+					return fmt.Errorf("failed to seed word %s: %w", sw.Japanese, created.Error)
+				}
+				result.Words += int(created.RowsAffected)
+
+				linked := tx.Exec("INSERT OR IGNORE INTO word_groups (word_id, group_id) VALUES (?, ?)", word.ID, group.ID)
+				if linked.Error != nil {
+					return fmt.Errorf("failed to add word %s to group %s: %w", sw.Japanese, sg.Group, linked.Error)
+				}
+				result.Memberships += int(linked.RowsAffected)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return SeedResult{}, err
+	}
+	return result, nil
 }
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+func TestSeedProfiles_Load(t *testing.T) {
+	names, err := SeedProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{SeedProfileDemo, SeedProfileJLPTN5, SeedProfileMinimal}, names)
+
+	profiles, err := loadSeedProfiles()
+	require.NoError(t, err)
+	for name, groups := range profiles {
+		require.NotEmpty(t, groups, name)
+		for _, sg := range groups {
+			words, err := loadSeedWords(sg.File)
+			require.NoError(t, err, name)
+			require.NotEmpty(t, words, sg.File)
+			for _, sw := range words {
+				word := models.Word{Term: sw.Japanese, Reading: sw.Romaji, English: sw.English, Parts: sw.Parts}
+				assert.NoError(t, word.Validate(), "%s in %s", sw.Japanese, sg.File)
+			}
+		}
+	}
+}
+
+func TestSeed_Idempotent(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, Migrate(db))
+
+	first, err := Seed(db, SeedProfileDemo)
+	require.NoError(t, err)
+	assert.Equal(t, 4, first.Groups)
+	assert.NotZero(t, first.Words)
+	assert.GreaterOrEqual(t, first.Memberships, first.Words)
+
+	second, err := Seed(db, SeedProfileDemo)
+	require.NoError(t, err)
+	assert.Equal(t, SeedResult{}, second)
+
+	var words int64
+	require.NoError(t, db.Model(&models.Word{}).Count(&words).Error)
+	assert.EqualValues(t, first.Words, words)
+}
+
+func TestSeed_OnTopOfAnotherProfile(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, Migrate(db))
+
+	_, err := Seed(db, SeedProfileDemo)
+	require.NoError(t, err)
+	var edited models.Word
+	require.NoError(t, db.Where("term = ?", "こんにちは").First(&edited).Error)
+	require.NoError(t, db.Model(&edited).Update("english", "good afternoon").Error)
+
+	result, err := Seed(db, SeedProfileJLPTN5)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Groups, "the shared groups are reused")
+
+	var word models.Word
+	require.NoError(t, db.Where("term = ?", "こんにちは").First(&word).Error)
+	assert.Equal(t, "good afternoon", word.English)
+	var copies int64
+	require.NoError(t, db.Model(&models.Word{}).Where("term = ?", "こんにちは").Count(&copies).Error)
+	assert.EqualValues(t, 1, copies)
+}
+
+func TestSeed_UnknownProfile(t *testing.T) {
+	db := openMemoryDB(t)
+	require.NoError(t, Migrate(db))
+
+	_, err := Seed(db, "everything")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "demo, jlpt-n5, minimal")
+}
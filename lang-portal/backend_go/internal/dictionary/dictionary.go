@@ -0,0 +1,119 @@
+// Package dictionary looks up Japanese words in an external dictionary, either
+// the Jisho API or an offline copy of JMdict, and converts entries into words.
+package dictionary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+)
+
+// ErrUnavailable is returned when the dictionary cannot be reached or loaded
+var ErrUnavailable = errors.New("dictionary unavailable")
+
+// Provider looks up dictionary entries
+type Provider interface {
+	// Lookup returns the entries matching query, which may be Japanese (written
+	// form or reading) or English, best match first
+	Lookup(ctx context.Context, query string) ([]Entry, error)
+}
+
+// Entry is a dictionary entry
+type Entry struct {
+	// ID identifies the entry within its provider: the JMdict sequence number,
+	// or the Jisho slug
+	ID     string  `json:"id"`
+	Common bool    `json:"common"`
+	Forms  []Form  `json:"forms"`
+	Senses []Sense `json:"senses"`
+}
+
+// Form is a way of writing an entry. Written is empty for kana-only words.
+type Form struct {
+	Written string `json:"written,omitempty"`
+	Reading string `json:"reading"`
+}
+
+// Sense is one meaning of an entry
+type Sense struct {
+	Glosses       []string `json:"glosses"`
+	PartsOfSpeech []string `json:"parts_of_speech"`
+}
+
+// Word converts the entry into a word using its first form and the given
+// sense. The parts of speech are mapped onto the tags used by the seed data,
+// such as "verb" and "godan".
+func (e *Entry) Word(sense int) (*models.Word, error) {
+	if len(e.Forms) == 0 {
+		return nil, fmt.Errorf("entry %s has no forms", e.ID)
+	}
+	if sense < 0 || sense >= len(e.Senses) {
+		return nil, fmt.Errorf("entry %s has no sense %d", e.ID, sense)
+	}
+
+	form := e.Forms[0]
+	japanese := form.Written
+	if japanese == "" {
+		japanese = form.Reading
+	}
+	return &models.Word{
+		Term:    japanese,
+		Reading: jpn.ToRomaji(form.Reading),
+		English: strings.Join(e.Senses[sense].Glosses, "; "),
+		Parts:   Parts(e.Senses[sense].PartsOfSpeech),
+	}, nil
+}
+
+// partTags maps fragments of JMdict and Jisho part-of-speech descriptions to
+// word part tags, checked in order
+var partTags = []struct {
+	fragment string
+	tags     []string
+}{
+	{"ichidan", []string{"verb", "ichidan"}},
+	{"godan", []string{"verb", "godan"}},
+	{"kuru verb", []string{"verb", "irregular"}},
+	{"suru", []string{"verb", "suru"}},
+	{"keiyodoshi", []string{"adjective", "na-adjective"}},
+	{"na-adjective", []string{"adjective", "na-adjective"}},
+	{"keiyoushi", []string{"adjective", "i-adjective"}},
+	{"i-adjective", []string{"adjective", "i-adjective"}},
+	{"adverb", []string{"adverb"}},
+	{"pronoun", []string{"pronoun"}},
+	{"noun", []string{"noun"}},
+	{"particle", []string{"particle"}},
+	{"counter", []string{"counter"}},
+	{"expression", []string{"expression"}},
+	{"interjection", []string{"interjection"}},
+	{"conjunction", []string{"conjunction"}},
+}
+
+// Parts maps part-of-speech descriptions onto word part tags. Descriptions
+// without a known tag are dropped; if none is known the result is "other".
+func Parts(partsOfSpeech []string) models.StringSlice {
+	var parts models.StringSlice
+	seen := make(map[string]bool)
+	for _, pos := range partsOfSpeech {
+		pos = strings.ToLower(pos)
+		for _, pt := range partTags {
+			if !strings.Contains(pos, pt.fragment) {
+				continue
+			}
+			for _, tag := range pt.tags {
+				if !seen[tag] {
+					seen[tag] = true
+					parts = append(parts, tag)
+				}
+			}
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return models.StringSlice{"other"}
+	}
+	return parts
+}
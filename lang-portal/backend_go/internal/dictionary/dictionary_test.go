@@ -0,0 +1,126 @@
+package dictionary
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+const testJMdict = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE JMdict [
+<!ELEMENT JMdict (entry*)>
+<!ENTITY v1 "Ichidan verb">
+<!ENTITY vt "transitive verb">
+<!ENTITY n "noun (common) (futsuumeishi)">
+]>
+<JMdict>
+<entry>
+<ent_seq>1358280</ent_seq>
+<k_ele><keb>食べる</keb><ke_pri>ichi1</ke_pri></k_ele>
+<k_ele><keb>喰べる</keb></k_ele>
+<r_ele><reb>たべる</reb><re_pri>ichi1</re_pri></r_ele>
+<sense><pos>&v1;</pos><pos>&vt;</pos><gloss>to eat</gloss></sense>
+<sense><gloss>to live on (e.g. a salary)</gloss><gloss xml:lang="ger">leben</gloss></sense>
+</entry>
+<entry>
+<ent_seq>1000000</ent_seq>
+<r_ele><reb>ゲーム</reb></r_ele>
+<sense><pos>&n;</pos><gloss>game</gloss></sense>
+</entry>
+</JMdict>`
+
+func TestJMdict_Lookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "JMdict_e")
+	require.NoError(t, os.WriteFile(path, []byte(testJMdict), 0o644))
+	dict := NewJMdict(path)
+
+	for _, query := range []string{"食べる", "たべる", "To Eat"} {
+		entries, err := dict.Lookup(context.Background(), query)
+		require.NoError(t, err)
+		require.Len(t, entries, 1, query)
+		assert.Equal(t, "1358280", entries[0].ID)
+	}
+
+	entries, err := dict.Lookup(context.Background(), "食べる")
+	require.NoError(t, err)
+	entry := entries[0]
+	assert.True(t, entry.Common)
+	assert.Equal(t, []Form{{Written: "食べる", Reading: "たべる"}, {Written: "喰べる", Reading: "たべる"}}, entry.Forms)
+	require.Len(t, entry.Senses, 2)
+	assert.Equal(t, []string{"Ichidan verb", "transitive verb"}, entry.Senses[0].PartsOfSpeech)
+	assert.Equal(t, []string{"to live on (e.g. a salary)"}, entry.Senses[1].Glosses)
+	assert.Equal(t, entry.Senses[0].PartsOfSpeech, entry.Senses[1].PartsOfSpeech)
+
+	entries, err = dict.Lookup(context.Background(), "ゲーム")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, []Form{{Reading: "ゲーム"}}, entries[0].Forms)
+
+	entries, err = dict.Lookup(context.Background(), "猫")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = NewJMdict(filepath.Join(t.TempDir(), "missing")).Lookup(context.Background(), "猫")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestJisho_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "食べる", r.URL.Query().Get("keyword"))
+		w.Write([]byte(`{"meta":{"status":200},"data":[{"slug":"食べる","is_common":true,
+			"japanese":[{"word":"食べる","reading":"たべる"}],
+			"senses":[{"english_definitions":["to eat"],"parts_of_speech":["Ichidan verb","Transitive verb"]}]}]}`))
+	}))
+	defer server.Close()
+
+	entries, err := (&Jisho{URL: server.URL}).Lookup(context.Background(), "食べる")
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{{
+		ID:     "食べる",
+		Common: true,
+		Forms:  []Form{{Written: "食べる", Reading: "たべる"}},
+		Senses: []Sense{{Glosses: []string{"to eat"}, PartsOfSpeech: []string{"Ichidan verb", "Transitive verb"}}},
+	}}, entries)
+
+	server.Close()
+	_, err = (&Jisho{URL: server.URL}).Lookup(context.Background(), "食べる")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestEntry_Word(t *testing.T) {
+	entry := Entry{
+		ID:    "1358280",
+		Forms: []Form{{Written: "食べる", Reading: "たべる"}},
+		Senses: []Sense{
+			{Glosses: []string{"to eat"}, PartsOfSpeech: []string{"Ichidan verb", "transitive verb"}},
+			{Glosses: []string{"to live on", "to subsist on"}},
+		},
+	}
+
+	word, err := entry.Word(0)
+	require.NoError(t, err)
+	assert.Equal(t, &models.Word{Term: "食べる", Reading: "taberu", English: "to eat", Parts: models.StringSlice{"verb", "ichidan"}}, word)
+
+	word, err = entry.Word(1)
+	require.NoError(t, err)
+	assert.Equal(t, "to live on; to subsist on", word.English)
+	assert.Equal(t, models.StringSlice{"other"}, word.Parts)
+
+	_, err = entry.Word(2)
+	assert.Error(t, err)
+}
+
+func TestParts(t *testing.T) {
+	assert.Equal(t, models.StringSlice{"verb", "godan"}, Parts([]string{"Godan verb with 'mu' ending", "Transitive verb"}))
+	assert.Equal(t, models.StringSlice{"noun", "verb", "suru"}, Parts([]string{"Noun", "Suru verb"}))
+	assert.Equal(t, models.StringSlice{"adjective", "na-adjective"}, Parts([]string{"adjectival nouns or quasi-adjectives (keiyodoshi)"}))
+	assert.Equal(t, models.StringSlice{"pronoun"}, Parts([]string{"pronoun"}))
+}
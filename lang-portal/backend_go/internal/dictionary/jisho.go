@@ -0,0 +1,83 @@
+package dictionary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DefaultJishoURL is the word search endpoint of the Jisho API
+const DefaultJishoURL = "https://jisho.org/api/v1/search/words"
+
+// maxJishoResponse caps the size of a Jisho response
+const maxJishoResponse = 4 << 20
+
+// Jisho looks words up with the Jisho API
+type Jisho struct {
+	// URL is the word search endpoint, DefaultJishoURL when empty
+	URL    string
+	Client *http.Client
+}
+
+type jishoResponse struct {
+	Data []struct {
+		Slug     string `json:"slug"`
+		IsCommon bool   `json:"is_common"`
+		Japanese []struct {
+			Word    string `json:"word"`
+			Reading string `json:"reading"`
+		} `json:"japanese"`
+		Senses []struct {
+			EnglishDefinitions []string `json:"english_definitions"`
+			PartsOfSpeech      []string `json:"parts_of_speech"`
+		} `json:"senses"`
+	} `json:"data"`
+}
+
+// Lookup implements Provider
+func (j *Jisho) Lookup(ctx context.Context, query string) ([]Entry, error) {
+	endpoint := j.URL
+	if endpoint == "" {
+		endpoint = DefaultJishoURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?keyword="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := j.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: jisho answered %s", ErrUnavailable, resp.Status)
+	}
+
+	var body jishoResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJishoResponse)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: decoding jisho response: %v", ErrUnavailable, err)
+	}
+
+	entries := make([]Entry, 0, len(body.Data))
+	for _, item := range body.Data {
+		entry := Entry{ID: item.Slug, Common: item.IsCommon}
+		for _, japanese := range item.Japanese {
+			entry.Forms = append(entry.Forms, Form{Written: japanese.Word, Reading: japanese.Reading})
+		}
+		for _, sense := range item.Senses {
+			entry.Senses = append(entry.Senses, Sense{Glosses: sense.EnglishDefinitions, PartsOfSpeech: sense.PartsOfSpeech})
+		}
+		if len(entry.Forms) > 0 && len(entry.Senses) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
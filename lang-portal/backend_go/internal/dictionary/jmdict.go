@@ -0,0 +1,206 @@
+package dictionary
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// JMdict looks words up in an offline copy of the JMdict XML file (JMdict_e or
+// the full JMdict, of which only the English glosses are kept). The file is
+// loaded into memory on the first lookup.
+type JMdict struct {
+	Path string
+
+	once    sync.Once
+	loadErr error
+	entries []Entry
+	// index maps written forms, readings and lowercase glosses to entries
+	index map[string][]int
+}
+
+// NewJMdict creates a provider reading the JMdict file at path
+func NewJMdict(path string) *JMdict {
+	return &JMdict{Path: path}
+}
+
+// Lookup implements Provider. Written forms and readings match exactly;
+// English queries match whole glosses, ignoring case. Common entries come first.
+func (j *JMdict) Lookup(_ context.Context, query string) ([]Entry, error) {
+	j.once.Do(func() {
+		j.loadErr = j.load()
+	})
+	if j.loadErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, j.loadErr)
+	}
+
+	matches := j.index[strings.ToLower(strings.TrimSpace(query))]
+	entries := make([]Entry, len(matches))
+	for i, m := range matches {
+		entries[i] = j.entries[m]
+	}
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].Common && !entries[b].Common })
+	return entries, nil
+}
+
+func (j *JMdict) load() error {
+	f, err := os.Open(j.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entries, err := parseJMdict(f)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string][]int)
+	add := func(key string, i int) {
+		key = strings.ToLower(key)
+		if n := len(index[key]); n == 0 || index[key][n-1] != i {
+			index[key] = append(index[key], i)
+		}
+	}
+	for i, entry := range entries {
+		for _, form := range entry.Forms {
+			if form.Written != "" {
+				add(form.Written, i)
+			}
+			add(form.Reading, i)
+		}
+		for _, sense := range entry.Senses {
+			for _, gloss := range sense.Glosses {
+				add(gloss, i)
+			}
+		}
+	}
+	j.entries, j.index = entries, index
+	return nil
+}
+
+// jmdictEntity matches the entity declarations of the JMdict DTD, which name
+// the part-of-speech codes, e.g. <!ENTITY v1 "Ichidan verb">
+var jmdictEntity = regexp.MustCompile(`<!ENTITY\s+(\S+)\s+"([^"]*)">`)
+
+type jmdictEntry struct {
+	Sequence string `xml:"ent_seq"`
+	Kanji    []struct {
+		Text     string   `xml:"keb"`
+		Priority []string `xml:"ke_pri"`
+	} `xml:"k_ele"`
+	Readings []struct {
+		Text       string    `xml:"reb"`
+		NoKanji    *struct{} `xml:"re_nokanji"`
+		Restricted []string  `xml:"re_restr"`
+		Priority   []string  `xml:"re_pri"`
+	} `xml:"r_ele"`
+	Senses []struct {
+		PartsOfSpeech []string `xml:"pos"`
+		Glosses       []struct {
+			Text string `xml:",chardata"`
+			Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+		} `xml:"gloss"`
+	} `xml:"sense"`
+}
+
+// parseJMdict reads the entries of a JMdict XML document. A reading is paired
+// with each written form it applies to; a sense without a part of speech has
+// the parts of speech of the sense before it, as in the JMdict conventions.
+func parseJMdict(r io.Reader) ([]Entry, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Entity = make(map[string]string)
+
+	var entries []Entry
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing JMdict: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			for _, m := range jmdictEntity.FindAllStringSubmatch(string(t), -1) {
+				decoder.Entity[m[1]] = m[2]
+			}
+		case xml.StartElement:
+			if t.Name.Local != "entry" {
+				continue
+			}
+			var raw jmdictEntry
+			if err := decoder.DecodeElement(&raw, &t); err != nil {
+				return nil, fmt.Errorf("parsing JMdict: %w", err)
+			}
+			if entry, ok := convertJMdict(&raw); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+}
+
+func convertJMdict(raw *jmdictEntry) (Entry, bool) {
+	entry := Entry{ID: raw.Sequence}
+	for _, k := range raw.Kanji {
+		entry.Common = entry.Common || len(k.Priority) > 0
+	}
+	for _, r := range raw.Readings {
+		entry.Common = entry.Common || len(r.Priority) > 0
+		if len(raw.Kanji) == 0 || r.NoKanji != nil {
+			entry.Forms = append(entry.Forms, Form{Reading: r.Text})
+			continue
+		}
+		for _, k := range raw.Kanji {
+			if len(r.Restricted) == 0 || contains(r.Restricted, k.Text) {
+				entry.Forms = append(entry.Forms, Form{Written: k.Text, Reading: r.Text})
+			}
+		}
+	}
+	// Written forms first, in the order of the kanji elements
+	sort.SliceStable(entry.Forms, func(a, b int) bool {
+		return kanjiOrder(raw, entry.Forms[a].Written) < kanjiOrder(raw, entry.Forms[b].Written)
+	})
+
+	var partsOfSpeech []string
+	for _, s := range raw.Senses {
+		if len(s.PartsOfSpeech) > 0 {
+			partsOfSpeech = s.PartsOfSpeech
+		}
+		var glosses []string
+		for _, g := range s.Glosses {
+			if g.Lang == "" || g.Lang == "eng" {
+				glosses = append(glosses, g.Text)
+			}
+		}
+		if len(glosses) > 0 {
+			entry.Senses = append(entry.Senses, Sense{Glosses: glosses, PartsOfSpeech: partsOfSpeech})
+		}
+	}
+	return entry, len(entry.Forms) > 0 && len(entry.Senses) > 0
+}
+
+func kanjiOrder(raw *jmdictEntry, written string) int {
+	for i, k := range raw.Kanji {
+		if k.Text == written {
+			return i
+		}
+	}
+	return len(raw.Kanji)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+// Package embeddings turns texts into vectors with an embedding model, so that
+// words with similar meanings can be found by comparing their vectors.
+package embeddings
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnavailable is returned when the embedding provider cannot be reached
+var ErrUnavailable = errors.New("embeddings unavailable")
+
+// Provider embeds texts
+type Provider interface {
+	// Model names the embedding model; vectors of different models are not
+	// comparable
+	Model() string
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Encode packs a vector into little-endian float32s for storage
+func Encode(vector []float32) []byte {
+	data := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+	return data
+}
+
+// Decode unpacks a vector packed by Encode
+func Decode(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("vector of %d bytes is not a list of float32s", len(data))
+	}
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector, nil
+}
+
+// Cosine is the cosine similarity of two vectors, from -1 (opposite) to 1
+// (same direction). It is 0 for vectors of different lengths or zero vectors.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
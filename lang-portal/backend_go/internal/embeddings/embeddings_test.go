@@ -0,0 +1,57 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	vector := []float32{0.5, -1.25, 3}
+	decoded, err := Decode(Encode(vector))
+	require.NoError(t, err)
+	assert.Equal(t, vector, decoded)
+
+	_, err = Decode([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestCosine(t *testing.T) {
+	assert.InDelta(t, 1, Cosine([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0, Cosine([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.InDelta(t, -1, Cosine([]float32{1, 0}, []float32{-3, 0}), 1e-9)
+	assert.Zero(t, Cosine([]float32{1}, []float32{1, 0}), "lengths differ")
+	assert.Zero(t, Cosine([]float32{0, 0}, []float32{1, 0}), "zero vector")
+}
+
+func TestOpenAI_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "nomic-embed-text", body.Model)
+		assert.Equal(t, []string{"猫", "犬"}, body.Input)
+		// Items may come back in any order
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	provider := &OpenAI{URL: server.URL, APIKey: "secret", ModelName: "nomic-embed-text"}
+	assert.Equal(t, "nomic-embed-text", provider.Model())
+	vectors, err := provider.Embed(context.Background(), []string{"猫", "犬"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+
+	server.Close()
+	_, err = provider.Embed(context.Background(), []string{"猫"})
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, DefaultOpenAIModel, (&OpenAI{}).Model())
+}
@@ -0,0 +1,102 @@
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OpenAI API defaults
+const (
+	// DefaultOpenAIURL is the embeddings endpoint of the OpenAI API
+	DefaultOpenAIURL = "https://api.openai.com/v1/embeddings"
+	// DefaultOpenAIModel is the embedding model used when none is set
+	DefaultOpenAIModel = "text-embedding-3-small"
+)
+
+// maxOpenAIResponse caps the size of an embeddings response
+const maxOpenAIResponse = 64 << 20
+
+// OpenAI embeds texts with an OpenAI-compatible embeddings API: the OpenAI API,
+// or a local server exposing the same endpoint such as Ollama's
+type OpenAI struct {
+	// URL is the embeddings endpoint, DefaultOpenAIURL when empty
+	URL string
+	// APIKey is sent as a bearer token when set
+	APIKey string
+	// ModelName is the embedding model, DefaultOpenAIModel when empty
+	ModelName string
+	Client    *http.Client
+}
+
+// Model implements Provider
+func (o *OpenAI) Model() string {
+	if o.ModelName == "" {
+		return DefaultOpenAIModel
+	}
+	return o.ModelName
+}
+
+// Embed implements Provider
+func (o *OpenAI) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(map[string]interface{}{"model": o.Model(), "input": texts})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := o.URL
+	if endpoint == "" {
+		endpoint = DefaultOpenAIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+	client := o.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: embeddings API answered %s", ErrUnavailable, resp.Status)
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOpenAIResponse)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: decoding embeddings response: %v", ErrUnavailable, err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("%w: embedding for unknown input %d", ErrUnavailable, item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, fmt.Errorf("%w: no embedding for input %d", ErrUnavailable, i)
+		}
+	}
+	return vectors, nil
+}
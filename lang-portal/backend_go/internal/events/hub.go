@@ -0,0 +1,215 @@
+// Package events buffers application events, such as new study sessions and
+// reviews, for clients that keep dashboards up to date. Clients read events
+// after an opaque cursor, so a client that reconnects sees what it missed.
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event types published by the services
+const (
+	TypeWordCreated         = "word.created"
+	TypeWordDeleted         = "word.deleted"
+	TypeGroupDeleted        = "group.deleted"
+	TypeStudySessionCreated = "study_session.created"
+	TypeWordReviewCreated   = "word_review.created"
+	TypeStudyHistoryReset   = "study_history.reset"
+	TypeDataReset           = "data.reset"
+	TypeDataRestored        = "data.restored"
+)
+
+// DefaultBufferSize is the number of recent events kept for clients to catch up on
+const DefaultBufferSize = 256
+
+// ErrInvalidCursor is returned for cursors that were not issued by a hub
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Event is a single change notification
+type Event struct {
+	ID        uint64      `json:"id"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Batch is the result of reading events after a cursor
+type Batch struct {
+	Events []Event `json:"events"`
+	// Cursor is passed back to read the events after this batch
+	Cursor string `json:"cursor"`
+	// Missed is set when events after the given cursor are no longer buffered,
+	// for instance after a restart; clients should reload their state
+	Missed bool `json:"missed"`
+}
+
+// Broker buffers published events for readers. Hub keeps them in the process;
+// RedisHub shares them between every server using the same Redis.
+type Broker interface {
+	// Publish records an event and wakes up waiting readers
+	Publish(eventType string, data interface{}) Event
+	// Read returns up to limit events published after cursor without waiting
+	Read(cursor string, limit int) (*Batch, error)
+	// Wait is like Read but blocks until there are new events, ctx is done or
+	// the broker is closed
+	Wait(ctx context.Context, cursor string, limit int) (*Batch, error)
+	// Close answers the waiting readers
+	Close()
+}
+
+// Hub keeps the most recent events in a fixed-size buffer and wakes up readers
+// waiting for new ones. It is safe for concurrent use.
+type Hub struct {
+	mu        sync.Mutex
+	epoch     string
+	buffer    []Event
+	size      int
+	lastID    uint64
+	changed   chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewHub creates a hub buffering up to size events. A non-positive size uses
+// DefaultBufferSize.
+func NewHub(size int) *Hub {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &Hub{
+		// Cursors from a previous process are recognised as stale by their epoch
+		epoch:   newEpoch(),
+		size:    size,
+		changed: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+}
+
+// Close answers the readers waiting for events, and any that wait later, with
+// the events they already have, so that long polls end when the server stops.
+// Events can still be published and read after Close.
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+// Publish records an event and wakes up waiting readers
+func (h *Hub) Publish(eventType string, data interface{}) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event := Event{ID: h.lastID, Type: eventType, Data: data, CreatedAt: time.Now().UTC()}
+	if len(h.buffer) == h.size {
+		copy(h.buffer, h.buffer[1:])
+		h.buffer = h.buffer[:h.size-1]
+	}
+	h.buffer = append(h.buffer, event)
+
+	close(h.changed)
+	h.changed = make(chan struct{})
+	return event
+}
+
+// Read returns up to limit events published after cursor without waiting. An
+// empty cursor returns no events and the current cursor, so new clients start
+// from now.
+func (h *Hub) Read(cursor string, limit int) (*Batch, error) {
+	batch, _, err := h.read(cursor, limit)
+	return batch, err
+}
+
+// Wait is like Read but, when there are no new events, blocks until one is
+// published or ctx is done. Running out of time is not an error: an empty batch
+// is returned, as it is once the hub is closed.
+func (h *Hub) Wait(ctx context.Context, cursor string, limit int) (*Batch, error) {
+	return wait(ctx, h.closed, cursor, func() (*Batch, <-chan struct{}, error) {
+		return h.read(cursor, limit)
+	})
+}
+
+// wait reads until there is something to return: new or missed events, the
+// cursor of a new reader, or nothing once ctx is done or closed is closed.
+// read returns a channel closed by the next publish.
+func wait(ctx context.Context, closed <-chan struct{}, cursor string, read func() (*Batch, <-chan struct{}, error)) (*Batch, error) {
+	for {
+		batch, changed, err := read()
+		if err != nil || len(batch.Events) > 0 || batch.Missed || cursor == "" {
+			return batch, err
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return batch, nil
+		case <-closed:
+			return batch, nil
+		}
+	}
+}
+
+func (h *Hub) read(cursor string, limit int) (*Batch, <-chan struct{}, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	batch := &Batch{Events: []Event{}, Cursor: h.cursor(h.lastID)}
+	if cursor == "" {
+		return batch, h.changed, nil
+	}
+
+	epoch, after, err := parseCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Cursors from another process, or ahead of this one, cannot be resumed
+	if epoch != h.epoch || after > h.lastID {
+		batch.Missed = true
+		return batch, h.changed, nil
+	}
+
+	oldest := h.lastID + 1
+	if len(h.buffer) > 0 {
+		oldest = h.buffer[0].ID
+	}
+	if after+1 < oldest {
+		batch.Missed = true
+	}
+
+	for _, event := range h.buffer {
+		if event.ID <= after {
+			continue
+		}
+		if limit > 0 && len(batch.Events) == limit {
+			break
+		}
+		batch.Events = append(batch.Events, event)
+	}
+	if n := len(batch.Events); n > 0 {
+		batch.Cursor = h.cursor(batch.Events[n-1].ID)
+	} else if batch.Missed {
+		batch.Cursor = h.cursor(h.lastID)
+	} else {
+		batch.Cursor = h.cursor(after)
+	}
+	return batch, h.changed, nil
+}
+
+func (h *Hub) cursor(id uint64) string {
+	return h.epoch + "." + strconv.FormatUint(id, 10)
+}
+
+func parseCursor(cursor string) (epoch string, id uint64, err error) {
+	epoch, rawID, ok := strings.Cut(cursor, ".")
+	if !ok || epoch == "" {
+		return "", 0, ErrInvalidCursor
+	}
+	id, err = strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return "", 0, ErrInvalidCursor
+	}
+	return epoch, id, nil
+}
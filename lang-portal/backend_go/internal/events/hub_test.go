@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_ReadAfterCursor(t *testing.T) {
+	hub := NewHub(10)
+
+	start, err := hub.Read("", 0)
+	require.NoError(t, err)
+	assert.Empty(t, start.Events)
+
+	hub.Publish(TypeWordCreated, map[string]uint{"id": 1})
+	hub.Publish(TypeWordDeleted, map[string]uint{"id": 1})
+
+	batch, err := hub.Read(start.Cursor, 0)
+	require.NoError(t, err)
+	require.Len(t, batch.Events, 2)
+	assert.Equal(t, TypeWordCreated, batch.Events[0].Type)
+	assert.Equal(t, TypeWordDeleted, batch.Events[1].Type)
+	assert.False(t, batch.Missed)
+
+	// Reading from the returned cursor yields nothing new
+	next, err := hub.Read(batch.Cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, next.Events)
+	assert.Equal(t, batch.Cursor, next.Cursor)
+}
+
+func TestHub_ReadRespectsLimit(t *testing.T) {
+	hub := NewHub(10)
+	start, _ := hub.Read("", 0)
+	for i := 0; i < 3; i++ {
+		hub.Publish(TypeWordCreated, nil)
+	}
+
+	first, err := hub.Read(start.Cursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, first.Events, 2)
+
+	rest, err := hub.Read(first.Cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, rest.Events, 1)
+	assert.Equal(t, uint64(3), rest.Events[0].ID)
+}
+
+func TestHub_ReportsMissedEvents(t *testing.T) {
+	hub := NewHub(2)
+	start, _ := hub.Read("", 0)
+	for i := 0; i < 3; i++ {
+		hub.Publish(TypeWordCreated, nil)
+	}
+
+	batch, err := hub.Read(start.Cursor, 0)
+	require.NoError(t, err)
+	assert.True(t, batch.Missed, "the first event was dropped from the buffer")
+	assert.Len(t, batch.Events, 2)
+
+	// Cursors from another hub (e.g. before a restart) cannot be resumed
+	other := NewHub(2)
+	other.epoch = "previous"
+	stale, _ := other.Read("", 0)
+	batch, err = hub.Read(stale.Cursor, 0)
+	require.NoError(t, err)
+	assert.True(t, batch.Missed)
+	assert.Empty(t, batch.Events)
+
+	_, err = hub.Read("garbage", 0)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestHub_WaitWakesOnPublish(t *testing.T) {
+	hub := NewHub(10)
+	start, _ := hub.Read("", 0)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		hub.Publish(TypeWordReviewCreated, nil)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	batch, err := hub.Wait(ctx, start.Cursor, 0)
+	require.NoError(t, err)
+	require.Len(t, batch.Events, 1)
+	assert.Equal(t, TypeWordReviewCreated, batch.Events[0].Type)
+}
+
+func TestHub_WaitTimesOut(t *testing.T) {
+	hub := NewHub(10)
+	start, _ := hub.Read("", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	batch, err := hub.Wait(ctx, start.Cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, batch.Events)
+	assert.Equal(t, start.Cursor, batch.Cursor)
+}
+
+func TestHub_CloseEndsWaits(t *testing.T) {
+	hub := NewHub(10)
+	start, _ := hub.Read("", 0)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		hub.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	batch, err := hub.Wait(ctx, start.Cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, batch.Events)
+	assert.Equal(t, start.Cursor, batch.Cursor)
+
+	// Later waits return at once, with the events already published
+	hub.Close()
+	hub.Publish(TypeWordCreated, nil)
+	batch, err = hub.Wait(ctx, start.Cursor, 0)
+	require.NoError(t, err)
+	assert.Len(t, batch.Events, 1)
+	batch, err = hub.Wait(ctx, batch.Cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, batch.Events)
+}
@@ -0,0 +1,250 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	_ Broker = (*Hub)(nil)
+	_ Broker = (*RedisHub)(nil)
+)
+
+// Redis keys of a RedisHub. The last ID counts the events, the stream buffers
+// the most recent ones under their ID, and the channel announces new ones to
+// every server. The epoch is replaced when Redis loses the keys, which makes
+// the cursors handed out before stale.
+const (
+	redisKeyPrefix   = "lang-portal:events:"
+	redisEpochKey    = redisKeyPrefix + "epoch"
+	redisLastIDKey   = redisKeyPrefix + "last-id"
+	redisStreamKey   = redisKeyPrefix + "stream"
+	redisPublishedCh = redisKeyPrefix + "published"
+)
+
+// publishEvent numbers an event and appends it to the stream in one step, so
+// that the stream stays in ID order however many servers publish
+var publishEvent = redis.NewScript(`
+redis.call("SET", KEYS[1], ARGV[1], "NX")
+local id = redis.call("INCR", KEYS[2])
+redis.call("XADD", KEYS[3], "MAXLEN", ARGV[2], id .. "-0", "type", ARGV[3], "data", ARGV[4], "created_at", ARGV[5])
+redis.call("PUBLISH", ARGV[6], id)
+return id
+`)
+
+// RedisHub is a Broker keeping the most recent events in Redis, so that a
+// client may poll any replica and sees the events published by all of them.
+// Waiting readers are woken through a Redis subscription. It is safe for
+// concurrent use.
+type RedisHub struct {
+	client redis.UniversalClient
+	pubsub *redis.PubSub
+	size   int
+	logger *log.Logger
+
+	mu        sync.Mutex
+	changed   chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewRedisHub creates a hub on client buffering up to size events, sharing
+// them with the other hubs on the same Redis. A non-positive size uses
+// DefaultBufferSize. Events that cannot be published are logged to logger and
+// dropped. Close stops the subscription; the client is owned by the caller.
+func NewRedisHub(ctx context.Context, client redis.UniversalClient, size int, logger *log.Logger) (*RedisHub, error) {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	h := &RedisHub{
+		client:  client,
+		pubsub:  client.Subscribe(ctx, redisPublishedCh),
+		size:    size,
+		logger:  logger,
+		changed: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+	// Wait for the subscription so that no publish after this returns is missed
+	if _, err := h.pubsub.Receive(ctx); err != nil {
+		h.pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+	go h.listen()
+	return h, nil
+}
+
+// listen wakes up the waiting readers on every announced event until the
+// subscription is closed. The subscription reconnects by itself; readers
+// waiting meanwhile are woken by the next event or their deadline.
+func (h *RedisHub) listen() {
+	for range h.pubsub.Channel() {
+		h.mu.Lock()
+		close(h.changed)
+		h.changed = make(chan struct{})
+		h.mu.Unlock()
+	}
+}
+
+// Close implements Broker. Events can still be published and read after Close.
+func (h *RedisHub) Close() {
+	h.closeOnce.Do(func() {
+		close(h.closed)
+		h.pubsub.Close()
+	})
+}
+
+// Ping checks that Redis is reachable
+func (h *RedisHub) Ping(ctx context.Context) error {
+	return h.client.Ping(ctx).Err()
+}
+
+// Publish implements Broker. The returned event has no ID if it could not be
+// published.
+func (h *RedisHub) Publish(eventType string, data interface{}) Event {
+	event := Event{Type: eventType, Data: data, CreatedAt: time.Now().UTC()}
+	encoded, err := json.Marshal(data)
+	if err == nil {
+		var id int64
+		id, err = publishEvent.Run(context.Background(), h.client,
+			[]string{redisEpochKey, redisLastIDKey, redisStreamKey},
+			newEpoch(), h.size, eventType, encoded, event.CreatedAt.Format(time.RFC3339Nano), redisPublishedCh).Int64()
+		event.ID = uint64(id)
+	}
+	if err != nil {
+		h.logger.Printf("Failed to publish %s event: %v", eventType, err)
+	}
+	return event
+}
+
+// Read implements Broker
+func (h *RedisHub) Read(cursor string, limit int) (*Batch, error) {
+	batch, _, err := h.read(context.Background(), cursor, limit)
+	return batch, err
+}
+
+// Wait implements Broker
+func (h *RedisHub) Wait(ctx context.Context, cursor string, limit int) (*Batch, error) {
+	return wait(ctx, h.closed, cursor, func() (*Batch, <-chan struct{}, error) {
+		return h.read(ctx, cursor, limit)
+	})
+}
+
+func (h *RedisHub) read(ctx context.Context, cursor string, limit int) (*Batch, <-chan struct{}, error) {
+	// Take the channel before reading so that an event published meanwhile
+	// still wakes the reader
+	h.mu.Lock()
+	changed := h.changed
+	h.mu.Unlock()
+
+	var after uint64
+	var cursorEpoch string
+	if cursor != "" {
+		var err error
+		if cursorEpoch, after, err = parseCursor(cursor); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// One transaction, so that the epoch, count and buffer agree
+	var epoch, lastID *redis.StringCmd
+	var oldest, entries *redis.XMessageSliceCmd
+	_, err := h.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, redisEpochKey, newEpoch(), 0)
+		epoch = pipe.Get(ctx, redisEpochKey)
+		lastID = pipe.Get(ctx, redisLastIDKey)
+		oldest = pipe.XRangeN(ctx, redisStreamKey, "-", "+", 1)
+		start := strconv.FormatUint(after+1, 10) + "-0"
+		if limit > 0 {
+			entries = pipe.XRangeN(ctx, redisStreamKey, start, "+", int64(limit))
+		} else {
+			entries = pipe.XRange(ctx, redisStreamKey, start, "+")
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, nil, err
+	}
+	last, err := lastID.Uint64()
+	if err != nil && err != redis.Nil {
+		return nil, nil, err
+	}
+	cursorOf := func(id uint64) string { return epoch.Val() + "." + strconv.FormatUint(id, 10) }
+
+	batch := &Batch{Events: []Event{}, Cursor: cursorOf(last)}
+	if cursor == "" {
+		return batch, changed, nil
+	}
+	// Cursors from before Redis lost the events, or ahead of it, cannot be resumed
+	if cursorEpoch != epoch.Val() || after > last {
+		batch.Missed = true
+		return batch, changed, nil
+	}
+
+	first := last + 1
+	if len(oldest.Val()) > 0 {
+		if first, err = streamEventID(oldest.Val()[0].ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if after+1 < first {
+		batch.Missed = true
+	}
+
+	for _, message := range entries.Val() {
+		event, err := decodeEvent(message)
+		if err != nil {
+			return nil, nil, err
+		}
+		batch.Events = append(batch.Events, event)
+	}
+	if n := len(batch.Events); n > 0 {
+		batch.Cursor = cursorOf(batch.Events[n-1].ID)
+	} else if !batch.Missed {
+		batch.Cursor = cursorOf(after)
+	}
+	return batch, changed, nil
+}
+
+// decodeEvent turns a stream entry back into the event. The data stays
+// encoded, so that it is written to clients as it was published.
+func decodeEvent(message redis.XMessage) (Event, error) {
+	id, err := streamEventID(message.ID)
+	if err != nil {
+		return Event{}, err
+	}
+	event := Event{ID: id, Type: fmt.Sprint(message.Values["type"])}
+	if data := fmt.Sprint(message.Values["data"]); data != "null" {
+		event.Data = json.RawMessage(data)
+	}
+	event.CreatedAt, err = time.Parse(time.RFC3339Nano, fmt.Sprint(message.Values["created_at"]))
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid event %s: %w", message.ID, err)
+	}
+	return event, nil
+}
+
+// streamEventID returns the event ID of a stream entry ID, "<id>-0"
+func streamEventID(streamID string) (uint64, error) {
+	raw, _, _ := strings.Cut(streamID, "-")
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid event ID %q: %w", streamID, err)
+	}
+	return id, nil
+}
+
+// newEpoch returns an epoch distinct from earlier ones
+func newEpoch() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redisHubs returns n hubs of size sharing one Redis, as replicas would
+func redisHubs(t *testing.T, size, n int) (*miniredis.Miniredis, []*RedisHub) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	hubs := make([]*RedisHub, n)
+	for i := range hubs {
+		hub, err := NewRedisHub(context.Background(), client, size, nil)
+		require.NoError(t, err)
+		t.Cleanup(hub.Close)
+		hubs[i] = hub
+	}
+	return server, hubs
+}
+
+func TestRedisHub_SharedBetweenReplicas(t *testing.T) {
+	_, hubs := redisHubs(t, 10, 2)
+	first, second := hubs[0], hubs[1]
+
+	start, err := second.Read("", 0)
+	require.NoError(t, err)
+	assert.Empty(t, start.Events)
+
+	published := first.Publish(TypeWordCreated, map[string]uint{"id": 1})
+	assert.Equal(t, uint64(1), published.ID)
+	second.Publish(TypeWordDeleted, nil)
+
+	batch, err := second.Read(start.Cursor, 0)
+	require.NoError(t, err)
+	require.Len(t, batch.Events, 2)
+	assert.False(t, batch.Missed)
+	assert.Equal(t, TypeWordCreated, batch.Events[0].Type)
+	data, err := json.Marshal(batch.Events[0].Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1}`, string(data))
+	assert.Equal(t, TypeWordDeleted, batch.Events[1].Type)
+	assert.Nil(t, batch.Events[1].Data)
+	assert.WithinDuration(t, published.CreatedAt, batch.Events[0].CreatedAt, 0)
+
+	// Either replica resumes from the cursor, a batch at a time
+	limited, err := first.Read(start.Cursor, 1)
+	require.NoError(t, err)
+	require.Len(t, limited.Events, 1)
+	rest, err := second.Read(limited.Cursor, 0)
+	require.NoError(t, err)
+	require.Len(t, rest.Events, 1)
+	assert.Equal(t, uint64(2), rest.Events[0].ID)
+	assert.Equal(t, batch.Cursor, rest.Cursor)
+
+	next, err := first.Read(batch.Cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, next.Events)
+	assert.Equal(t, batch.Cursor, next.Cursor)
+}
+
+func TestRedisHub_ReportsMissedEvents(t *testing.T) {
+	server, hubs := redisHubs(t, 2, 1)
+	hub := hubs[0]
+
+	start, _ := hub.Read("", 0)
+	for i := 0; i < 3; i++ {
+		hub.Publish(TypeWordCreated, nil)
+	}
+	batch, err := hub.Read(start.Cursor, 0)
+	require.NoError(t, err)
+	assert.True(t, batch.Missed, "the first event was trimmed from the stream")
+	assert.Len(t, batch.Events, 2)
+
+	// Once Redis lost the events the cursors handed out before are stale
+	server.FlushAll()
+	hub.Publish(TypeWordCreated, nil)
+	batch, err = hub.Read(batch.Cursor, 0)
+	require.NoError(t, err)
+	assert.True(t, batch.Missed)
+	assert.Empty(t, batch.Events)
+
+	_, err = hub.Read("garbage", 0)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestRedisHub_WaitWakesOnPublishElsewhere(t *testing.T) {
+	_, hubs := redisHubs(t, 10, 2)
+	start, _ := hubs[0].Read("", 0)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		hubs[1].Publish(TypeWordReviewCreated, nil)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	batch, err := hubs[0].Wait(ctx, start.Cursor, 0)
+	require.NoError(t, err)
+	require.Len(t, batch.Events, 1)
+	assert.Equal(t, TypeWordReviewCreated, batch.Events[0].Type)
+}
+
+func TestRedisHub_CloseEndsWaits(t *testing.T) {
+	_, hubs := redisHubs(t, 10, 1)
+	hub := hubs[0]
+	start, _ := hub.Read("", 0)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		hub.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	batch, err := hub.Wait(ctx, start.Cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, batch.Events)
+	assert.Equal(t, start.Cursor, batch.Cursor)
+}
@@ -0,0 +1,77 @@
+// Package i18n translates the messages the API reports to clients, such as
+// the messages of error responses. Messages are written in English in the
+// code and looked up by their English text in the catalogs under locales,
+// one JSON object per language.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Languages messages are available in. English is the language of the code.
+const (
+	English = "en"
+	German  = "de"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs holds the translations of messages by language
+var catalogs = loadCatalogs()
+
+// matcher picks the supported language closest to an Accept-Language header,
+// English when none is close
+var matcher = language.NewMatcher([]language.Tag{language.English, language.German})
+
+func loadCatalogs() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: %v", err))
+	}
+	catalogs := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: %v", err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", file.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return catalogs
+}
+
+// Negotiate returns the language to answer a request with the given
+// Accept-Language header in
+func Negotiate(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return English
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return English
+	}
+	_, index, _ := matcher.Match(tags...)
+	if index == 1 {
+		return German
+	}
+	return English
+}
+
+// Translate returns message in lang, or message itself when it has no
+// translation
+func Translate(lang, message string) string {
+	if translated, ok := catalogs[lang][message]; ok {
+		return translated
+	}
+	return message
+}
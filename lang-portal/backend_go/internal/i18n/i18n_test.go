@@ -0,0 +1,34 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", English},
+		{"de", German},
+		{"de-AT,de;q=0.9,en;q=0.8", German},
+		{"en-US,en;q=0.9,de;q=0.8", English},
+		{"fr-FR,fr;q=0.9", English},
+		{"fr;q=0.9,de;q=0.5", German},
+		{"not a header;;", English},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.header), tt.header)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "Wort nicht gefunden", Translate(German, "Word not found"))
+	assert.Equal(t, "Failed to fetch word: database is locked",
+		Translate(German, "Failed to fetch word: database is locked"), "messages are translated whole")
+	assert.Equal(t, "Word not found", Translate(English, "Word not found"))
+	assert.Equal(t, "Something new", Translate(German, "Something new"), "untranslated messages stay English")
+	assert.Equal(t, "Word not found", Translate("fr", "Word not found"))
+}
@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+)
+
+// ParseFrequencyList parses a word frequency list, such as one derived from
+// BCCWJ, with one word per line: rank, japanese and an optional reading,
+// separated by tabs or commas. A first line in plain ASCII without a numeric
+// rank, such as "rank,lemma,reading", is a header. Lines without a rank are
+// ranked by their position in the list, and blank lines and lines starting
+// with # are skipped. Readings are converted to hiragana.
+func ParseFrequencyList(data []byte) ([]models.WordFrequency, error) {
+	var entries []models.WordFrequency
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := splitFrequencyLine(text)
+
+		rank, err := strconv.Atoi(fields[0])
+		switch {
+		case err == nil && len(fields) > 1:
+			if rank < 1 {
+				return nil, fmt.Errorf("line %d: rank must be positive", line)
+			}
+			fields = fields[1:]
+		case err == nil:
+			return nil, fmt.Errorf("line %d: missing word", line)
+		case len(entries) == 0 && isASCII(text):
+			continue
+		default:
+			rank = len(entries) + 1
+		}
+
+		entry := models.WordFrequency{Japanese: fields[0], Rank: rank}
+		if entry.Japanese == "" {
+			return nil, fmt.Errorf("line %d: missing word", line)
+		}
+		if len(fields) > 1 {
+			entry.Reading = jpn.ToHiragana(fields[1])
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the frequency list: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("the frequency list is empty")
+	}
+	return entries, nil
+}
+
+// splitFrequencyLine splits a line on tabs, or on commas when it has none
+func splitFrequencyLine(text string) []string {
+	sep := "\t"
+	if !strings.Contains(text, sep) {
+		sep = ","
+	}
+	fields := strings.Split(text, sep)
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+func isASCII(text string) bool {
+	for _, r := range text {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,480 @@
+// Package importer parses word lists exported by other tools, such as Memrise
+// course CSVs and generic two- or three-column lists, as well as the JSON seed
+// format. It detects the format and the meaning of each column and reports
+// every row with the problems found, so the result can be previewed before
+// any word is created.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+)
+
+// Formats. FormatAuto detects JSON or a delimited list.
+const (
+	FormatAuto    = "auto"
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatMemrise = "memrise"
+)
+
+// Column roles. A reading column holds kana and is transliterated into romaji
+// when there is no romaji column.
+const (
+	ColumnJapanese = "japanese"
+	ColumnRomaji   = "romaji"
+	ColumnReading  = "reading"
+	ColumnEnglish  = "english"
+	ColumnParts    = "parts"
+	ColumnSkip     = "skip"
+)
+
+// MaxRows caps the number of words in one import
+const MaxRows = 5000
+
+// DefaultPart tags imported words whose list has no parts of speech
+const DefaultPart = "other"
+
+// Options overrides what Parse would otherwise detect
+type Options struct {
+	// Format is one of the Format constants; empty means FormatAuto
+	Format string
+	// Columns names the role of each column, in order. Columns beyond the
+	// list are skipped.
+	Columns []string
+	// Header states whether the first row is a header
+	Header *bool
+}
+
+// Record is a word as imported
+type Record struct {
+	Japanese string   `json:"japanese"`
+	Romaji   string   `json:"romaji"`
+	English  string   `json:"english"`
+	Parts    []string `json:"parts"`
+}
+
+// Word converts the record into a word
+func (r *Record) Word() *models.Word {
+	return &models.Word{
+		Term:    r.Japanese,
+		Reading: r.Romaji,
+		English: r.English,
+		Parts:   models.StringSlice(r.Parts),
+	}
+}
+
+// Row is an imported record and the line it came from. Rows with an Error are
+// not imported.
+type Row struct {
+	Line int `json:"line"`
+	Record
+	Error string `json:"error,omitempty"`
+}
+
+// Result is a parsed word list
+type Result struct {
+	Format string `json:"format"`
+	// Delimiter separates the columns of a delimited list
+	Delimiter string `json:"delimiter,omitempty"`
+	Header    bool   `json:"header"`
+	// Columns is the role of each column of a delimited list
+	Columns []string `json:"columns,omitempty"`
+	Rows    []Row    `json:"rows"`
+}
+
+// Parse reads a word list. Its errors describe what is wrong with the data or
+// the options, in terms meant for the person importing.
+func Parse(data []byte, opts Options) (*Result, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+
+	format := opts.Format
+	if format == "" || format == FormatAuto {
+		format = FormatCSV
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+			format = FormatJSON
+		}
+	}
+
+	var result *Result
+	var err error
+	switch format {
+	case FormatJSON:
+		result, err = parseJSON(data)
+	case FormatCSV, FormatMemrise:
+		result, err = parseDelimited(data, format, opts)
+	default:
+		return nil, errors.New("format must be auto, json, csv or memrise")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]int)
+	for i := range result.Rows {
+		row := &result.Rows[i]
+		if row.Error == "" {
+			row.Error = validate(&row.Record)
+		}
+		if row.Error != "" {
+			continue
+		}
+		if line, ok := seen[row.Japanese]; ok {
+			row.Error = fmt.Sprintf("duplicate of line %d", line)
+			continue
+		}
+		seen[row.Japanese] = row.Line
+	}
+	return result, nil
+}
+
+func parseJSON(data []byte) (*Result, error) {
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %v", err)
+	}
+	if len(records) > MaxRows {
+		return nil, fmt.Errorf("at most %d words can be imported at once", MaxRows)
+	}
+
+	rows := make([]Row, len(records))
+	for i, record := range records {
+		if len(record.Parts) == 0 {
+			record.Parts = []string{DefaultPart}
+		}
+		rows[i] = Row{Line: i + 1, Record: record}
+	}
+	return &Result{Format: FormatJSON, Rows: rows}, nil
+}
+
+func parseDelimited(data []byte, format string, opts Options) (*Result, error) {
+	delimiter := detectDelimiter(data)
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var cells [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", format, err)
+		}
+		if len(cells) > MaxRows {
+			return nil, fmt.Errorf("at most %d words can be imported at once", MaxRows)
+		}
+		line, _ := reader.FieldPos(0)
+		cells = append(cells, record)
+		lines = append(lines, line)
+	}
+
+	headerRoles, memrise := headerColumns(cells[0])
+	header := len(headerRoles) > 0 && !containsJapanese(strings.Join(cells[0], ""))
+	if opts.Header != nil {
+		header = *opts.Header
+	}
+	if header && memrise && format == FormatCSV {
+		format = FormatMemrise
+	}
+	body, bodyLines := cells, lines
+	if header {
+		body, bodyLines = cells[1:], lines[1:]
+	}
+	if len(body) == 0 {
+		return nil, errors.New("the file has no words")
+	}
+
+	var columns []string
+	switch {
+	case len(opts.Columns) > 0:
+		columns = opts.Columns
+		for _, role := range columns {
+			if !validRole(role) {
+				return nil, fmt.Errorf("unknown column %q; columns are japanese, romaji, reading, english, parts or skip", role)
+			}
+		}
+	case header:
+		columns = make([]string, len(cells[0]))
+		for i := range columns {
+			columns[i] = ColumnSkip
+			if role, ok := headerRoles[i]; ok {
+				columns[i] = role
+			}
+		}
+	default:
+		var err error
+		if columns, err = detectColumns(body); err != nil {
+			return nil, err
+		}
+	}
+	if !hasRole(columns, ColumnJapanese) || !hasRole(columns, ColumnEnglish) {
+		return nil, errors.New("could not tell which columns hold the japanese and english; name the columns")
+	}
+
+	rows := make([]Row, len(body))
+	for i, values := range body {
+		rows[i] = Row{Line: bodyLines[i], Record: recordFrom(values, columns)}
+	}
+	return &Result{Format: format, Delimiter: string(delimiter), Header: header, Columns: columns, Rows: rows}, nil
+}
+
+// detectDelimiter picks the most frequent of tab, semicolon and comma on the first line
+func detectDelimiter(data []byte) rune {
+	first := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		first = data[:i]
+	}
+	delimiter, best := ',', bytes.Count(first, []byte(","))
+	for _, candidate := range []rune{'\t', ';'} {
+		if n := bytes.Count(first, []byte(string(candidate))); n > best {
+			delimiter, best = candidate, n
+		}
+	}
+	return delimiter
+}
+
+// headerNames maps header cells, lowercased, to column roles
+var headerNames = map[string]string{
+	"japanese": ColumnJapanese, "word": ColumnJapanese, "kanji": ColumnJapanese, "learnable": ColumnJapanese,
+	"term": ColumnJapanese, "expression": ColumnJapanese, "vocab": ColumnJapanese, "vocabulary": ColumnJapanese, "front": ColumnJapanese,
+	"romaji": ColumnRomaji, "romanization": ColumnRomaji, "romanisation": ColumnRomaji, "transliteration": ColumnRomaji,
+	"reading": ColumnReading, "kana": ColumnReading, "hiragana": ColumnReading, "furigana": ColumnReading, "pronunciation": ColumnReading,
+	"english": ColumnEnglish, "definition": ColumnEnglish, "meaning": ColumnEnglish, "meanings": ColumnEnglish,
+	"translation": ColumnEnglish, "back": ColumnEnglish, "gloss": ColumnEnglish,
+	"parts": ColumnParts, "part of speech": ColumnParts, "pos": ColumnParts, "word type": ColumnParts,
+	"level": ColumnSkip, "notes": ColumnSkip, "tags": ColumnSkip, "audio": ColumnSkip, "id": ColumnSkip,
+}
+
+// memriseNames are header cells specific to Memrise course exports
+var memriseNames = map[string]bool{"level": true, "learnable": true, "definition": true}
+
+// headerColumns returns the roles of the recognized header cells by column,
+// and whether the header looks like a Memrise course export
+func headerColumns(cells []string) (map[int]string, bool) {
+	roles := make(map[int]string)
+	memrise := false
+	for i, cell := range cells {
+		name := strings.ToLower(strings.TrimSpace(cell))
+		if role, ok := headerNames[name]; ok {
+			roles[i] = role
+		}
+		memrise = memrise || memriseNames[name]
+	}
+	return roles, memrise
+}
+
+// detectColumns guesses the roles of the columns of a headerless list from
+// their contents: the column written in Japanese script is the japanese, a
+// second one in kana only is the reading, and of the columns in Latin script
+// the one that reads most like romaji is the romaji and the next the english.
+func detectColumns(rows [][]string) ([]string, error) {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	sample := rows
+	if len(sample) > 50 {
+		sample = sample[:50]
+	}
+
+	japanese := make([]float64, width)
+	kana := make([]float64, width)
+	romaji := make([]float64, width)
+	for _, row := range sample {
+		for i, value := range row {
+			value = strings.TrimSpace(value)
+			switch {
+			case value == "":
+			case isKanaOnly(value):
+				kana[i]++
+				japanese[i]++
+			case containsJapanese(value):
+				japanese[i]++
+			case looksLikeRomaji(value):
+				romaji[i]++
+			}
+		}
+	}
+
+	columns := make([]string, width)
+	for i := range columns {
+		columns[i] = ColumnSkip
+	}
+	best := func(scores []float64, pick func(int) bool) int {
+		index := -1
+		for i, score := range scores {
+			if columns[i] == ColumnSkip && pick(i) && (index < 0 || score > scores[index]) {
+				index = i
+			}
+		}
+		return index
+	}
+	half := float64(len(sample)) / 2
+
+	jp := best(japanese, func(i int) bool { return japanese[i] > half })
+	if jp < 0 {
+		return nil, errors.New("could not find a column in Japanese; name the columns")
+	}
+	columns[jp] = ColumnJapanese
+	if reading := best(kana, func(i int) bool { return kana[i] > half }); reading >= 0 {
+		columns[reading] = ColumnReading
+	}
+
+	latin := func(i int) bool { return japanese[i] <= half }
+	var latinColumns int
+	for i := range columns {
+		if columns[i] == ColumnSkip && latin(i) {
+			latinColumns++
+		}
+	}
+	if latinColumns >= 2 {
+		columns[best(romaji, latin)] = ColumnRomaji
+	}
+	for i := range columns {
+		if columns[i] == ColumnSkip && latin(i) {
+			columns[i] = ColumnEnglish
+			break
+		}
+	}
+	return columns, nil
+}
+
+func recordFrom(values []string, columns []string) Record {
+	var record Record
+	var reading string
+	for i, role := range columns {
+		if i >= len(values) {
+			break
+		}
+		value := strings.TrimSpace(values[i])
+		switch role {
+		case ColumnJapanese:
+			record.Japanese = value
+		case ColumnRomaji:
+			record.Romaji = value
+		case ColumnReading:
+			reading = value
+		case ColumnEnglish:
+			record.English = value
+		case ColumnParts:
+			record.Parts = splitParts(value)
+		}
+	}
+
+	if record.Romaji == "" {
+		switch {
+		case reading != "":
+			record.Romaji = jpn.ToRomaji(reading)
+		case isKanaOnly(record.Japanese):
+			record.Romaji = jpn.ToRomaji(record.Japanese)
+		}
+	}
+	if len(record.Parts) == 0 {
+		record.Parts = []string{DefaultPart}
+	}
+	return record
+}
+
+func splitParts(value string) []string {
+	var parts []string
+	for _, part := range strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return r == ',' || r == ';' || r == '/' || r == '|'
+	}) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// validate returns why a record cannot be imported, or ""
+func validate(r *Record) string {
+	switch {
+	case r.Japanese == "":
+		return "missing japanese"
+	case r.English == "":
+		return "missing english"
+	case r.Romaji == "":
+		return "missing romaji; add a romaji or reading column"
+	case containsJapanese(r.Romaji):
+		return "romaji is not in Latin script"
+	}
+	return ""
+}
+
+func validRole(role string) bool {
+	switch role {
+	case ColumnJapanese, ColumnRomaji, ColumnReading, ColumnEnglish, ColumnParts, ColumnSkip:
+		return true
+	}
+	return false
+}
+
+func hasRole(columns []string, role string) bool {
+	for _, c := range columns {
+		if c == role {
+			return true
+		}
+	}
+	return false
+}
+
+func containsJapanese(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKanaOnly reports whether s is written in kana, ignoring punctuation
+func isKanaOnly(s string) bool {
+	kana := false
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー':
+			kana = true
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return false
+		}
+	}
+	return kana
+}
+
+// romajiWord matches a word made of Hepburn syllables
+var romajiWord = regexp.MustCompile(`^(?:(?:kk|gg|ss|zz|tt|dd|pp|bb|tch|cch)?(?:ky|gy|sh|ch|ts|ny|hy|by|py|my|ry|[kgsztdnhbpmyrwfjv])?[aiueoāīūēō]|n'?|-)+$`)
+
+// looksLikeRomaji reports whether every word of s reads as romaji
+func looksLikeRomaji(s string) bool {
+	words := strings.Fields(strings.ToLower(s))
+	if len(words) == 0 {
+		return false
+	}
+	for _, word := range words {
+		if !romajiWord.MatchString(strings.Trim(word, ".,!?")) {
+			return false
+		}
+	}
+	return true
+}
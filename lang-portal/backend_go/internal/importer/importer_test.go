@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+func TestParse_Memrise(t *testing.T) {
+	data := "Level,Learnable,Definition,Pronunciation\n" +
+		"1,食べる,to eat,たべる\n" +
+		"1,水,water,\n" +
+		"2,ありがとう,thank you,\n"
+
+	result, err := Parse([]byte(data), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, FormatMemrise, result.Format)
+	assert.True(t, result.Header)
+	assert.Equal(t, []string{ColumnSkip, ColumnJapanese, ColumnEnglish, ColumnReading}, result.Columns)
+	require.Len(t, result.Rows, 3)
+
+	assert.Equal(t, Row{Line: 2, Record: Record{Japanese: "食べる", Romaji: "taberu", English: "to eat", Parts: []string{DefaultPart}}}, result.Rows[0])
+	assert.Equal(t, "missing romaji; add a romaji or reading column", result.Rows[1].Error)
+	assert.Equal(t, "arigatou", result.Rows[2].Romaji)
+	assert.Empty(t, result.Rows[2].Error)
+}
+
+func TestParse_DetectsColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		columns []string
+		first   Record
+	}{
+		{
+			name:    "two columns",
+			data:    "ねこ,cat\nいぬ,dog\n",
+			columns: []string{ColumnJapanese, ColumnEnglish},
+			first:   Record{Japanese: "ねこ", Romaji: "neko", English: "cat", Parts: []string{DefaultPart}},
+		},
+		{
+			name:    "english first",
+			data:    "cat;ねこ\ndog;いぬ\n",
+			columns: []string{ColumnEnglish, ColumnJapanese},
+			first:   Record{Japanese: "ねこ", Romaji: "neko", English: "cat", Parts: []string{DefaultPart}},
+		},
+		{
+			name:    "three columns",
+			data:    "食べる\ttaberu\tto eat\n飲む\tnomu\tto drink\n",
+			columns: []string{ColumnJapanese, ColumnRomaji, ColumnEnglish},
+			first:   Record{Japanese: "食べる", Romaji: "taberu", English: "to eat", Parts: []string{DefaultPart}},
+		},
+		{
+			name:    "english before romaji",
+			data:    "食べる,to eat,taberu\n飲む,to drink,nomu\n",
+			columns: []string{ColumnJapanese, ColumnEnglish, ColumnRomaji},
+			first:   Record{Japanese: "食べる", Romaji: "taberu", English: "to eat", Parts: []string{DefaultPart}},
+		},
+		{
+			name:    "kana reading",
+			data:    "食べる,たべる,to eat\n飲む,のむ,to drink\n",
+			columns: []string{ColumnJapanese, ColumnReading, ColumnEnglish},
+			first:   Record{Japanese: "食べる", Romaji: "taberu", English: "to eat", Parts: []string{DefaultPart}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse([]byte(tt.data), Options{})
+			require.NoError(t, err)
+			assert.Equal(t, FormatCSV, result.Format)
+			assert.False(t, result.Header)
+			assert.Equal(t, tt.columns, result.Columns)
+			require.Len(t, result.Rows, 2)
+			assert.Equal(t, tt.first, result.Rows[0].Record)
+			assert.Equal(t, 1, result.Rows[0].Line)
+		})
+	}
+}
+
+func TestParse_Options(t *testing.T) {
+	header := false
+	result, err := Parse([]byte("word,meaning,parts\n本,book,noun\n"), Options{
+		Header:  &header,
+		Columns: []string{ColumnJapanese, ColumnEnglish, ColumnParts},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 2)
+	assert.Equal(t, "word", result.Rows[0].Japanese)
+	assert.Equal(t, []string{"noun"}, result.Rows[1].Parts)
+	assert.Equal(t, "missing romaji; add a romaji or reading column", result.Rows[1].Error)
+
+	_, err = Parse([]byte("本,book\n"), Options{Columns: []string{"kanji", ColumnEnglish}})
+	assert.Error(t, err)
+	_, err = Parse([]byte("本,book\n"), Options{Format: "xlsx"})
+	assert.Error(t, err)
+}
+
+func TestParse_JSON(t *testing.T) {
+	data := `[{"japanese":"本","romaji":"hon","english":"book","parts":["noun"]},
+		{"japanese":"本","romaji":"hon","english":"book"},
+		{"japanese":"水","english":"water"}]`
+
+	result, err := Parse([]byte(data), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, result.Format)
+	require.Len(t, result.Rows, 3)
+	assert.Empty(t, result.Rows[0].Error)
+	assert.Equal(t, "duplicate of line 1", result.Rows[1].Error)
+	assert.Equal(t, "missing romaji; add a romaji or reading column", result.Rows[2].Error)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, data := range []string{"", "cat,dog\nbird,fish\n", "japanese,english\n"} {
+		_, err := Parse([]byte(data), Options{})
+		assert.Error(t, err, data)
+	}
+}
+
+func TestParseFrequencyList(t *testing.T) {
+	entries, err := ParseFrequencyList([]byte("rank\tlemma\treading\n# BCCWJ\n1\t食べる\tタベル\n\n3\t猫\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []models.WordFrequency{
+		{Japanese: "食べる", Reading: "たべる", Rank: 1},
+		{Japanese: "猫", Rank: 3},
+	}, entries)
+
+	entries, err = ParseFrequencyList([]byte("する,スル\nいる\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []models.WordFrequency{
+		{Japanese: "する", Reading: "する", Rank: 1},
+		{Japanese: "いる", Rank: 2},
+	}, entries, "unnumbered lines are ranked by position")
+
+	for _, data := range []string{"", "# only a comment\n", "0\t猫\n", "5\n"} {
+		_, err := ParseFrequencyList([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,195 @@
+package jpn
+
+import "strings"
+
+// MaxCounterNumber is the largest number counter readings are given for
+const MaxCounterNumber = 99
+
+// Sound change classes of counters: how the counter and the number before it
+// change when read together
+const (
+	// soundPlain counters change neither: 二枚 にまい
+	soundPlain = iota
+	// soundGeminate counters starting with k, s or t double their consonant
+	// after 1, 6, 8 and 10: 一個 いっこ, 六個 ろっこ
+	soundGeminate
+	// soundH counters starting with h also become p after those numbers and b
+	// after 3: 一本 いっぽん, 三本 さんぼん
+	soundH
+)
+
+// Counter is a counter word with the rules its readings follow. Irregular
+// readings are listed by number: exact ones only for that number, and tails
+// for 1 to 10 also at the end of larger numbers (四人 and 十四人 both end in
+// よにん).
+type Counter struct {
+	Symbol  string `json:"counter"`
+	Reading string `json:"reading"`
+	Usage   string `json:"usage"`
+	// Max is the largest number the counter is used with
+	Max int `json:"max"`
+
+	sound int
+	exact map[int][]string
+	tails map[int][]string
+}
+
+// counters are the counters drilled, in the order they are usually learned
+var counters = []Counter{
+	{Symbol: "つ", Reading: "つ", Usage: "general things", Max: 10, exact: map[int][]string{
+		1: {"ひとつ"}, 2: {"ふたつ"}, 3: {"みっつ"}, 4: {"よっつ"}, 5: {"いつつ"},
+		6: {"むっつ"}, 7: {"ななつ"}, 8: {"やっつ"}, 9: {"ここのつ"}, 10: {"とお"},
+	}},
+	{Symbol: "人", Reading: "にん", Usage: "people", Max: MaxCounterNumber,
+		exact: map[int][]string{1: {"ひとり"}, 2: {"ふたり"}},
+		tails: map[int][]string{4: {"よにん"}, 7: {"ななにん", "しちにん"}},
+	},
+	{Symbol: "本", Reading: "ほん", Usage: "long, thin objects such as pens and bottles", Max: MaxCounterNumber, sound: soundH},
+	{Symbol: "匹", Reading: "ひき", Usage: "small animals", Max: MaxCounterNumber, sound: soundH},
+	{Symbol: "杯", Reading: "はい", Usage: "cups and glasses of drink", Max: MaxCounterNumber, sound: soundH},
+	{Symbol: "分", Reading: "ふん", Usage: "minutes", Max: 59, sound: soundH, tails: map[int][]string{
+		3: {"さんぷん"}, 4: {"よんぷん"},
+	}},
+	{Symbol: "枚", Reading: "まい", Usage: "flat objects such as paper and shirts", Max: MaxCounterNumber, sound: soundPlain},
+	{Symbol: "台", Reading: "だい", Usage: "machines and vehicles", Max: MaxCounterNumber, sound: soundPlain},
+	{Symbol: "個", Reading: "こ", Usage: "small, round objects", Max: MaxCounterNumber, sound: soundGeminate},
+	{Symbol: "回", Reading: "かい", Usage: "times, occurrences", Max: MaxCounterNumber, sound: soundGeminate},
+	{Symbol: "階", Reading: "かい", Usage: "floors of a building", Max: MaxCounterNumber, sound: soundGeminate, tails: map[int][]string{
+		3: {"さんがい", "さんかい"},
+	}},
+	{Symbol: "冊", Reading: "さつ", Usage: "books and magazines", Max: MaxCounterNumber, sound: soundGeminate},
+	{Symbol: "歳", Reading: "さい", Usage: "years of age", Max: MaxCounterNumber, sound: soundGeminate, exact: map[int][]string{
+		20: {"はたち", "にじゅっさい", "にじっさい"},
+	}},
+}
+
+// digitReadings are the readings of 1 to 9 before a counter
+var digitReadings = [...]string{"", "いち", "に", "さん", "よん", "ご", "ろく", "なな", "はち", "きゅう"}
+
+// kanjiDigits are the kanji numerals 1 to 9
+var kanjiDigits = [...]string{"", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+
+// hRow maps the h-row kana a counter may start with to their p and b forms
+var hRow = map[rune][2]string{
+	'は': {"ぱ", "ば"}, 'ひ': {"ぴ", "び"}, 'ふ': {"ぷ", "ぶ"}, 'へ': {"ぺ", "べ"}, 'ほ': {"ぽ", "ぼ"},
+}
+
+// Counters returns the counters with readings
+func Counters() []Counter {
+	return append([]Counter(nil), counters...)
+}
+
+// FindCounter returns the counter written symbol
+func FindCounter(symbol string) (Counter, bool) {
+	for _, counter := range counters {
+		if counter.Symbol == symbol {
+			return counter, true
+		}
+	}
+	return Counter{}, false
+}
+
+// Text writes number with the counter in kanji numerals: 二十三本
+func (c Counter) Text(number int) string {
+	return KanjiNumber(number) + c.Symbol
+}
+
+// Readings returns the readings of number with the counter in hiragana, the
+// most common first, or nil when the counter is not used with number. Above
+// ten the sound changes follow the last digit, or ten for multiples of ten.
+func (c Counter) Readings(number int) []string {
+	if number < 1 || number > c.Max {
+		return nil
+	}
+	if readings, ok := c.exact[number]; ok {
+		return append([]string(nil), readings...)
+	}
+
+	prefix, last := "", number
+	if number > 10 {
+		last = number % 10
+		if tens := number / 10; tens > 1 {
+			prefix = digitReadings[tens]
+		}
+		if last == 0 {
+			last = 10
+		} else {
+			prefix += "じゅう"
+		}
+	}
+	readings := c.tail(last)
+	for i := range readings {
+		readings[i] = prefix + readings[i]
+	}
+	return readings
+}
+
+// tail returns the readings of 1 to 10 with the counter, after the sound
+// changes of the counter's class
+func (c Counter) tail(number int) []string {
+	if readings, ok := c.tails[number]; ok {
+		return append([]string(nil), readings...)
+	}
+	base := c.Reading
+	geminated := base
+	voiced := base
+	if c.sound == soundH {
+		first := []rune(base)[0]
+		if forms, ok := hRow[first]; ok {
+			rest := string([]rune(base)[1:])
+			geminated = forms[0] + rest
+			voiced = forms[1] + rest
+		}
+	}
+
+	if c.sound == soundPlain {
+		if number == 10 {
+			return []string{"じゅう" + base}
+		}
+		return []string{digitReadings[number] + base}
+	}
+	switch number {
+	case 1:
+		return []string{"いっ" + geminated}
+	case 3:
+		return []string{"さん" + voiced}
+	case 6:
+		return []string{"ろっ" + geminated}
+	case 8:
+		return []string{"はっ" + geminated, "はち" + base}
+	case 10:
+		return []string{"じゅっ" + geminated, "じっ" + geminated}
+	}
+	return []string{digitReadings[number] + base}
+}
+
+// CheckCounterAnswer reports whether answer, in kana or romaji, is one of
+// readings. Spelling differences such as long vowels are ignored.
+func CheckCounterAnswer(answer string, readings []string) bool {
+	key := NormalizeRomaji(strings.TrimSpace(answer))
+	if key == "" {
+		return false
+	}
+	for _, reading := range readings {
+		if NormalizeRomaji(reading) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// KanjiNumber writes 1 to 99 in kanji numerals: 二十三
+func KanjiNumber(number int) string {
+	if number < 1 || number > MaxCounterNumber {
+		return ""
+	}
+	var b strings.Builder
+	if tens := number / 10; tens > 0 {
+		if tens > 1 {
+			b.WriteString(kanjiDigits[tens])
+		}
+		b.WriteString("十")
+	}
+	b.WriteString(kanjiDigits[number%10])
+	return b.String()
+}
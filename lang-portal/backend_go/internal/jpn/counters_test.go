@@ -0,0 +1,69 @@
+package jpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter_Readings(t *testing.T) {
+	cases := []struct {
+		counter string
+		number  int
+		want    []string
+	}{
+		{"本", 1, []string{"いっぽん"}},
+		{"本", 2, []string{"にほん"}},
+		{"本", 3, []string{"さんぼん"}},
+		{"本", 6, []string{"ろっぽん"}},
+		{"本", 8, []string{"はっぽん", "はちほん"}},
+		{"本", 10, []string{"じゅっぽん", "じっぽん"}},
+		{"本", 23, []string{"にじゅうさんぼん"}},
+		{"本", 30, []string{"さんじゅっぽん", "さんじっぽん"}},
+		{"本", 11, []string{"じゅういっぽん"}},
+		{"分", 3, []string{"さんぷん"}},
+		{"分", 60, nil},
+		{"匹", 1, []string{"いっぴき"}},
+		{"枚", 7, []string{"ななまい"}},
+		{"枚", 10, []string{"じゅうまい"}},
+		{"個", 6, []string{"ろっこ"}},
+		{"冊", 1, []string{"いっさつ"}},
+		{"階", 3, []string{"さんがい", "さんかい"}},
+		{"歳", 20, []string{"はたち", "にじゅっさい", "にじっさい"}},
+		{"人", 1, []string{"ひとり"}},
+		{"人", 4, []string{"よにん"}},
+		{"人", 14, []string{"じゅうよにん"}},
+		{"人", 5, []string{"ごにん"}},
+		{"つ", 9, []string{"ここのつ"}},
+		{"つ", 11, nil},
+		{"本", 0, nil},
+	}
+	for _, tc := range cases {
+		counter, ok := FindCounter(tc.counter)
+		require.True(t, ok, tc.counter)
+		assert.Equal(t, tc.want, counter.Readings(tc.number), "%d%s", tc.number, tc.counter)
+	}
+}
+
+func TestKanjiNumber(t *testing.T) {
+	assert.Equal(t, "三", KanjiNumber(3))
+	assert.Equal(t, "十", KanjiNumber(10))
+	assert.Equal(t, "十一", KanjiNumber(11))
+	assert.Equal(t, "二十三", KanjiNumber(23))
+	assert.Equal(t, "九十", KanjiNumber(90))
+	assert.Empty(t, KanjiNumber(100))
+
+	counter, _ := FindCounter("本")
+	assert.Equal(t, "二十三本", counter.Text(23))
+}
+
+func TestCheckCounterAnswer(t *testing.T) {
+	counter, _ := FindCounter("本")
+	readings := counter.Readings(3)
+	assert.True(t, CheckCounterAnswer("さんぼん", readings))
+	assert.True(t, CheckCounterAnswer("サンボン", readings))
+	assert.True(t, CheckCounterAnswer(" sanbon ", readings))
+	assert.False(t, CheckCounterAnswer("さんほん", readings))
+	assert.False(t, CheckCounterAnswer("", readings))
+}
@@ -0,0 +1,231 @@
+package jpn
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Segment is a run of a word's text: kanji with their Reading in hiragana, or
+// kana and anything else with an empty Reading
+type Segment struct {
+	Text    string `json:"text"`
+	Reading string `json:"reading,omitempty"`
+}
+
+// syllables transliterates romaji syllables to hiragana, built from the kana
+// tables with the Kunrei-shiki spellings and wo for を added. Kana that are
+// rarely meant, such as ゐ or small vowels, are left out so that the common one
+// wins.
+var syllables = func() map[string]string {
+	rare := "ゐゑをぢづぁぃぅぇぉゃゅょゎ"
+	table := make(map[string]string)
+	for kana, romaji := range monographs {
+		if !strings.ContainsRune(rare, kana) {
+			table[romaji] = string(kana)
+		}
+	}
+	for kana, romaji := range digraphs {
+		if !strings.Contains(kana, "ぢ") {
+			table[romaji] = kana
+		}
+	}
+	for romaji, kana := range map[string]string{
+		"si": "し", "ti": "ち", "tu": "つ", "hu": "ふ", "zi": "じ", "wo": "を",
+		"sya": "しゃ", "syu": "しゅ", "syo": "しょ",
+		"tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+		"zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+		"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	} {
+		table[romaji] = kana
+	}
+	return table
+}()
+
+// longMarks spells out vowels written with a macron or circumflex
+var longMarks = strings.NewReplacer(
+	"ā", "aa", "ī", "ii", "ū", "uu", "ē", "ee", "ō", "ou",
+	"â", "aa", "î", "ii", "û", "uu", "ê", "ee", "ô", "ou",
+)
+
+// RomajiToHiragana transliterates a romaji reading to hiragana. Spaces and
+// hyphens are ignored, a doubled consonant is a small っ and n is ん unless a
+// vowel or y follows it. ok is false when part of the text is not romaji.
+func RomajiToHiragana(text string) (string, bool) {
+	text = longMarks.Replace(strings.ToLower(strings.TrimSpace(text)))
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r) || r == '-':
+			return -1
+		case r == '’':
+			return '\''
+		}
+		return r
+	}, text)
+
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\'':
+			i++
+			continue
+		case c == 'n' && (i+1 == len(text) || !isVowel(text[i+1]) && text[i+1] != 'y'):
+			b.WriteString("ん")
+			i++
+			continue
+		case i+1 < len(text) && !isVowel(c) && (text[i+1] == c || c == 't' && strings.HasPrefix(text[i+1:], "ch")):
+			b.WriteString("っ")
+			i++
+			continue
+		}
+
+		matched := false
+		for size := 3; size > 0; size-- {
+			if i+size > len(text) {
+				continue
+			}
+			if kana, ok := syllables[text[i:i+size]]; ok {
+				b.WriteString(kana)
+				i += size
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// readingAlternatives are the kana a reading may have in place of a kana of the
+// word: particles read differently from how they are written
+var readingAlternatives = map[rune]string{'は': "[はわ]", 'へ': "[へえ]", 'を': "[をお]"}
+
+// Furigana splits word into segments and gives each run of kanji its part of
+// reading, a reading of the whole word in kana: 食べる read たべる gives 食 (た)
+// and べる. When the reading cannot be lined up with the kana of the word,
+// the whole word is a single segment with the whole reading. A word without
+// kanji needs no reading and is a single segment without one.
+func Furigana(word, reading string) []Segment {
+	runs := kanjiRuns(word)
+	reading = ToHiragana(strings.TrimSpace(reading))
+	if !hasKanji(word) || reading == "" {
+		return []Segment{{Text: word}}
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, run := range runs {
+		if run.Reading != "" {
+			pattern.WriteString("(.+?)")
+			continue
+		}
+		for _, r := range ToHiragana(run.Text) {
+			if alternative, ok := readingAlternatives[r]; ok {
+				pattern.WriteString(alternative)
+			} else {
+				pattern.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+	}
+	pattern.WriteString("$")
+
+	match := regexp.MustCompile(pattern.String()).FindStringSubmatch(reading)
+	if match == nil {
+		return []Segment{{Text: word, Reading: reading}}
+	}
+	segments := make([]Segment, len(runs))
+	group := 1
+	for i, run := range runs {
+		segments[i] = Segment{Text: run.Text}
+		if run.Reading != "" {
+			segments[i].Reading = match[group]
+			group++
+		}
+	}
+	return segments
+}
+
+// Reading returns the reading of segments in hiragana, taking kana as written,
+// or "" when a run of kanji has no reading
+func Reading(segments []Segment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		switch {
+		case segment.Reading != "":
+			b.WriteString(segment.Reading)
+		case hasKanji(segment.Text):
+			return ""
+		default:
+			b.WriteString(ToHiragana(segment.Text))
+		}
+	}
+	return b.String()
+}
+
+// kanjiRuns splits text into runs of kanji and of everything else. Kanji runs
+// have a placeholder Reading to tell them apart.
+func kanjiRuns(text string) []Segment {
+	var runs []Segment
+	for _, r := range text {
+		kanji := isKanji(r) || r == 'ヶ'
+		if len(runs) == 0 || (runs[len(runs)-1].Reading != "") != kanji {
+			run := Segment{}
+			if kanji {
+				run.Reading = "?"
+			}
+			runs = append(runs, run)
+		}
+		runs[len(runs)-1].Text += string(r)
+	}
+	return runs
+}
+
+// Ruby renders segments as HTML, with each reading in a <ruby> annotation
+// and <rp> parentheses for browsers without ruby support. Text is escaped.
+func Ruby(segments []Segment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		if segment.Reading == "" {
+			b.WriteString(html.EscapeString(segment.Text))
+			continue
+		}
+		b.WriteString("<ruby>")
+		b.WriteString(html.EscapeString(segment.Text))
+		b.WriteString("<rp>(</rp><rt>")
+		b.WriteString(html.EscapeString(segment.Reading))
+		b.WriteString("</rt><rp>)</rp></ruby>")
+	}
+	return b.String()
+}
+
+// RubySentence renders sentence as HTML with the readings of a word's kanji,
+// given as the word's segments, wherever the word occurs in it. The kana after
+// the last kanji are left out of the match, so conjugated forms (食べた for
+// 食べる) are annotated too. The rest of the sentence is escaped.
+func RubySentence(sentence string, segments []Segment) string {
+	last := -1
+	for i, segment := range segments {
+		if segment.Reading != "" {
+			last = i
+		}
+	}
+	if last < 0 {
+		return html.EscapeString(sentence)
+	}
+
+	var stem strings.Builder
+	for _, segment := range segments[:last+1] {
+		stem.WriteString(segment.Text)
+	}
+	annotated := Ruby(segments[:last+1])
+
+	parts := strings.Split(sentence, stem.String())
+	for i := range parts {
+		parts[i] = html.EscapeString(parts[i])
+	}
+	return strings.Join(parts, annotated)
+}
@@ -0,0 +1,52 @@
+package jpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRomajiToHiragana(t *testing.T) {
+	cases := map[string]string{
+		"taberu":       "たべる",
+		"konnichiwa":   "こんにちわ",
+		"kon'ya":       "こんや",
+		"gakkou":       "がっこう",
+		"matcha":       "まっちゃ",
+		"Tōkyō":        "とうきょう",
+		"shinbun":      "しんぶん",
+		"benkyou suru": "べんきょうする",
+		"tukue":        "つくえ",
+	}
+	for romaji, want := range cases {
+		got, ok := RomajiToHiragana(romaji)
+		assert.True(t, ok, romaji)
+		assert.Equal(t, want, got, romaji)
+	}
+
+	_, ok := RomajiToHiragana("taberu!")
+	assert.False(t, ok)
+}
+
+func TestFurigana(t *testing.T) {
+	assert.Equal(t, []Segment{{Text: "食", Reading: "た"}, {Text: "べる"}}, Furigana("食べる", "たべる"))
+	assert.Equal(t, []Segment{{Text: "勉強", Reading: "べんきょう"}, {Text: "する"}}, Furigana("勉強する", "べんきょうする"))
+	assert.Equal(t, []Segment{{Text: "お"}, {Text: "茶", Reading: "ちゃ"}}, Furigana("お茶", "オチャ"), "katakana readings are converted")
+	assert.Equal(t, []Segment{{Text: "今日", Reading: "きょう"}, {Text: "は"}}, Furigana("今日は", "きょうわ"), "particles may be read differently")
+	assert.Equal(t, []Segment{{Text: "取", Reading: "と"}, {Text: "り"}, {Text: "扱", Reading: "あつか"}, {Text: "い"}}, Furigana("取り扱い", "とりあつかい"))
+	assert.Equal(t, []Segment{{Text: "ラーメン"}}, Furigana("ラーメン", "らーめん"), "kana needs no reading")
+	assert.Equal(t, []Segment{{Text: "食べる", Reading: "のむ"}}, Furigana("食べる", "のむ"), "readings that do not line up cover the word")
+}
+
+func TestReading(t *testing.T) {
+	assert.Equal(t, "きょうは", Reading(Furigana("今日は", "きょうわ")), "kana are read as written")
+	assert.Equal(t, "らーめん", Reading(Furigana("ラーメン", "")))
+	assert.Empty(t, Reading(Furigana("食べる", "")), "kanji without a reading")
+}
+
+func TestRuby(t *testing.T) {
+	segments := Furigana("食べる", "たべる")
+	assert.Equal(t, "<ruby>食<rp>(</rp><rt>た</rt><rp>)</rp></ruby>べる", Ruby(segments))
+	assert.Equal(t, "パンを<ruby>食<rp>(</rp><rt>た</rt><rp>)</rp></ruby>べた &lt;b&gt;", RubySentence("パンを食べた <b>", segments))
+	assert.Equal(t, "ラーメン &amp; パン", RubySentence("ラーメン & パン", Furigana("ラーメン", "")))
+}
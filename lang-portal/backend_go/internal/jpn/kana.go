@@ -0,0 +1,57 @@
+package jpn
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Scripts text can be transliterated to
+const (
+	ScriptHiragana = "hiragana"
+	ScriptKatakana = "katakana"
+	ScriptRomaji   = "romaji"
+)
+
+// Scripts lists the scripts text can be transliterated to
+var Scripts = []string{ScriptHiragana, ScriptKatakana, ScriptRomaji}
+
+// romajiWord matches a word of romaji within other text
+var romajiWord = regexp.MustCompile(`(?i)[a-zāīūēōâîûêô]+(?:['’][a-zāīūēōâîûêô]+)*`)
+
+// ToKatakana converts the hiragana in text to katakana, leaving everything
+// else unchanged
+func ToKatakana(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + ('ァ' - 'ぁ')
+		}
+		return r
+	}, text)
+}
+
+// Transliterate writes the kana and romaji in text in script, one of Scripts.
+// Kana are written in romaji with ToRomaji; to write kana, words of romaji
+// are transliterated with RomajiToHiragana and the kana converted to the
+// script. Kanji, punctuation and words that are not romaji are kept, so
+// mixed input such as ラーメン o taberu comes out whole. ok is false for an
+// unknown script.
+func Transliterate(text, script string) (string, bool) {
+	switch script {
+	case ScriptRomaji:
+		return ToRomaji(text), true
+	case ScriptHiragana, ScriptKatakana:
+	default:
+		return "", false
+	}
+
+	kana := ToHiragana(romajiWord.ReplaceAllStringFunc(text, func(word string) string {
+		if hiragana, ok := RomajiToHiragana(word); ok {
+			return hiragana
+		}
+		return word
+	}))
+	if script == ScriptKatakana {
+		kana = ToKatakana(kana)
+	}
+	return kana, true
+}
@@ -0,0 +1,47 @@
+package jpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToKatakana(t *testing.T) {
+	assert.Equal(t, "ラーメン", ToKatakana("らーめん"))
+	assert.Equal(t, "ガッコウ", ToKatakana("がっこう"))
+	assert.Equal(t, "食ベル", ToKatakana("食べる"), "kanji are kept")
+}
+
+func TestTransliterate(t *testing.T) {
+	cases := map[string]map[string]string{
+		ScriptHiragana: {
+			"taberu":            "たべる",
+			"カタカナ":              "かたかな",
+			"ラーメン o taberu":     "らーめん お たべる",
+			"Tōkyō ni ikimasu.": "とうきょう に いきます.",
+			"kon'ya":            "こんや",
+			"日本語 wo benkyou":    "日本語 を べんきょう",
+		},
+		ScriptKatakana: {
+			"raamen":  "ラアメン",
+			"ひらがな":    "ヒラガナ",
+			"koohii!": "コオヒイ!",
+		},
+		ScriptRomaji: {
+			"たべる":    "taberu",
+			"ラーメン":   "raamen",
+			"がっこう":   "gakkou",
+			"taberu": "taberu",
+		},
+	}
+	for script, texts := range cases {
+		for text, want := range texts {
+			got, ok := Transliterate(text, script)
+			assert.True(t, ok, script)
+			assert.Equal(t, want, got, "%s to %s", text, script)
+		}
+	}
+
+	_, ok := Transliterate("taberu", "kanji")
+	assert.False(t, ok)
+}
@@ -0,0 +1,61 @@
+package jpn
+
+import (
+	"strings"
+	"unicode"
+)
+
+// macrons maps long-vowel marks to the plain vowel; long vowels are collapsed
+// by NormalizeRomaji anyway
+var macrons = strings.NewReplacer(
+	"ā", "a", "ī", "i", "ū", "u", "ē", "e", "ō", "o",
+	"â", "a", "î", "i", "û", "u", "ê", "e", "ô", "o",
+)
+
+// romanizations rewrites Hepburn spellings to their Kunrei-shiki equivalents,
+// so that both systems (and wapuro spellings) compare equal. Longer patterns
+// come first.
+var romanizations = strings.NewReplacer(
+	"shi", "si", "sh", "sy",
+	"chi", "ti", "ch", "ty",
+	"tsu", "tu",
+	"fu", "hu",
+	"ji", "zi", "jy", "zy", "j", "zy",
+	"di", "zi", "du", "zu",
+	"mb", "nb", "mp", "np",
+	"nn", "n",
+)
+
+// longVowels collapses the spellings of long vowels to a single vowel
+var longVowels = strings.NewReplacer(
+	"ou", "o", "oo", "o",
+	"uu", "u", "aa", "a", "ii", "i",
+	"ei", "e", "ee", "e",
+)
+
+// NormalizeRomaji reduces a romaji or kana answer to a key that is equal for
+// the common ways of writing the same reading: case, spaces, apostrophes and
+// hyphens are ignored, kana is transliterated, long vowels (ō, ou, oo, oh) are
+// collapsed and Hepburn, Kunrei-shiki and wapuro spellings (shi/si, tsu/tu,
+// ja/zya, shimbun/shinbun, nn/n) are unified. The key is meant for comparison
+// only; distinct words may share it.
+func NormalizeRomaji(text string) string {
+	text = macrons.Replace(strings.ToLower(ToRomaji(text)))
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\'' || r == '-' || r == '’' {
+			return -1
+		}
+		return r
+	}, text)
+	text = romanizations.Replace(text)
+
+	// "oh" marks a long vowel only before a consonant or at the end
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == 'h' && i > 0 && text[i-1] == 'o' && (i+1 == len(text) || !isVowel(text[i+1]) && text[i+1] != 'y') {
+			continue
+		}
+		b.WriteByte(text[i])
+	}
+	return longVowels.Replace(b.String())
+}
@@ -0,0 +1,39 @@
+package jpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRomaji(t *testing.T) {
+	same := [][]string{
+		{"kyou", "kyō", "Kyoo", "kyoh", "きょう", "キョウ"},
+		{"ohayou", "ohayō", "ohayo", "おはよう"},
+		{"shinbun", "shimbun", "sinbun", "しんぶん"},
+		{"sensei", "sensee", "sensē"},
+		{"ocha", "otya", "おちゃ"},
+		{"jisho", "zisyo", "jisyo"},
+		{"tsukue", "tukue"},
+		{"fuyu", "huyu"},
+		{"kin'en", "kinen", "kin-en", "きんえん"},
+		{"konnichiwa", "konichiwa", "kon nichi wa"},
+		{"matcha", "mattya", "まっちゃ"},
+		{"senpai", "sempai"},
+	}
+	for _, group := range same {
+		want := NormalizeRomaji(group[0])
+		for _, variant := range group[1:] {
+			assert.Equal(t, want, NormalizeRomaji(variant), "%s vs %s", group[0], variant)
+		}
+	}
+
+	different := [][2]string{
+		{"kyou", "kyuu"},
+		{"obasan", "okaasan"},
+		{"taberu", "nomu"},
+	}
+	for _, pair := range different {
+		assert.NotEqual(t, NormalizeRomaji(pair[0]), NormalizeRomaji(pair[1]), "%s vs %s", pair[0], pair[1])
+	}
+}
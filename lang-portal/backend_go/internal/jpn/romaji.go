@@ -0,0 +1,122 @@
+// Package jpn provides Japanese text utilities shared by the services, such
+// as transliteration between kana and romaji.
+package jpn
+
+import "strings"
+
+// digraphs romanizes two-kana combinations: youon and the small vowels used in
+// loanwords. They are matched before single kana.
+var digraphs = map[string]string{
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+}
+
+// monographs romanizes single kana (modified Hepburn)
+var monographs = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// ToHiragana converts the katakana in text to hiragana, leaving everything
+// else unchanged
+func ToHiragana(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - ('ァ' - 'ぁ')
+		}
+		return r
+	}, text)
+}
+
+// ToRomaji transliterates the kana in text to modified Hepburn romaji. A small
+// っ doubles the following consonant, ー repeats the previous vowel and ん is
+// written n' before a vowel or y. Other characters, including kanji, are kept.
+func ToRomaji(text string) string {
+	runes := []rune(ToHiragana(text))
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		syllable, size := romanize(runes[i:])
+		switch runes[i] {
+		case 'っ':
+			next, _ := romanize(runes[i+1:])
+			switch {
+			case strings.HasPrefix(next, "ch"):
+				b.WriteByte('t')
+			case next != "" && !isVowel(next[0]) && next[0] != 'n':
+				b.WriteByte(next[0])
+			default:
+				b.WriteString("tsu")
+			}
+			size = 1
+		case 'ん':
+			b.WriteByte('n')
+			if next, _ := romanize(runes[i+1:]); next != "" && (isVowel(next[0]) || next[0] == 'y') {
+				b.WriteByte('\'')
+			}
+		case 'ー':
+			if out := b.String(); out != "" && isVowel(out[len(out)-1]) {
+				b.WriteByte(out[len(out)-1])
+			}
+			size = 1
+		default:
+			if syllable == "" {
+				b.WriteRune(runes[i])
+				size = 1
+			} else {
+				b.WriteString(syllable)
+			}
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// romanize returns the romaji of the kana at the start of runes and the number
+// of runes it covers, or "" when runes does not start with a known kana
+func romanize(runes []rune) (string, int) {
+	if len(runes) >= 2 {
+		if s, ok := digraphs[string(runes[:2])]; ok {
+			return s, 2
+		}
+	}
+	if len(runes) >= 1 {
+		if s, ok := monographs[runes[0]]; ok {
+			return s, 1
+		}
+	}
+	return "", 0
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
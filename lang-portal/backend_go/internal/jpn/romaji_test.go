@@ -0,0 +1,34 @@
+package jpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToRomaji(t *testing.T) {
+	tests := map[string]string{
+		"たべる":     "taberu",
+		"きょう":     "kyou",
+		"がっこう":    "gakkou",
+		"まっちゃ":    "matcha",
+		"しんぶん":    "shinbun",
+		"きんえん":    "kin'en",
+		"こんや":     "kon'ya",
+		"コーヒー":    "koohii",
+		"パーティー":   "paatii",
+		"ファイル":    "fairu",
+		"ちょっと":    "chotto",
+		"あっ":      "atsu",
+		"食べる":     "食beru",
+		"テスト 123": "tesuto 123",
+	}
+	for kana, want := range tests {
+		assert.Equal(t, want, ToRomaji(kana), kana)
+	}
+}
+
+func TestToHiragana(t *testing.T) {
+	assert.Equal(t, "かたかな", ToHiragana("カタカナ"))
+	assert.Equal(t, "ひらがな ABC", ToHiragana("ひらがな ABC"))
+}
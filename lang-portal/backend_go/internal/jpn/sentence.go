@@ -0,0 +1,65 @@
+package jpn
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ClozeBlank is the placeholder that replaces the blanked word of a sentence
+const ClozeBlank = "＿＿"
+
+// conjugatingEndings are the final kana of dictionary forms that change when a
+// verb or い-adjective is conjugated
+const conjugatingEndings = "うくぐすつぬぶむるい"
+
+// WordForms returns the spellings of a dictionary-form word to look for in a
+// sentence, longest first: the word itself and, for words that conjugate, the
+// stem their forms share (食べ for 食べる, 高 for 高い, 勉強 for 勉強する). A
+// stem of a single kana is left out, as it would match almost anywhere.
+func WordForms(word string) []string {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return nil
+	}
+	forms := []string{word}
+
+	var stem string
+	if noun, ok := strings.CutSuffix(word, "する"); ok {
+		stem = noun
+	} else if last, size := utf8.DecodeLastRuneInString(word); strings.ContainsRune(conjugatingEndings, last) {
+		stem = word[:len(word)-size]
+	}
+	if stem != "" && (utf8.RuneCountInString(stem) > 1 || hasKanji(stem)) {
+		forms = append(forms, stem)
+	}
+	return forms
+}
+
+// BlankWord replaces the first occurrence in sentence of the longest of forms
+// that occurs at all with blank. It returns the sentence with the blank and the
+// text it replaced, or ok false when no form occurs.
+func BlankWord(sentence string, forms []string, blank string) (text, removed string, ok bool) {
+	sorted := append([]string(nil), forms...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, form := range sorted {
+		if form == "" {
+			continue
+		}
+		if i := strings.Index(sentence, form); i >= 0 {
+			return sentence[:i] + blank + sentence[i+len(form):], form, true
+		}
+	}
+	return sentence, "", false
+}
+
+func hasKanji(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package jpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordForms(t *testing.T) {
+	assert.Equal(t, []string{"食べる", "食べ"}, WordForms("食べる"))
+	assert.Equal(t, []string{"高い", "高"}, WordForms("高い"))
+	assert.Equal(t, []string{"勉強する", "勉強"}, WordForms("勉強する"))
+	assert.Equal(t, []string{"先生"}, WordForms("先生"))
+	assert.Equal(t, []string{"いぬ"}, WordForms("いぬ"), "single kana stems match too much")
+	assert.Nil(t, WordForms(" "))
+}
+
+func TestBlankWord(t *testing.T) {
+	text, removed, ok := BlankWord("毎朝パンを食べます。", WordForms("食べる"), ClozeBlank)
+	assert.True(t, ok)
+	assert.Equal(t, "毎朝パンを＿＿ます。", text)
+	assert.Equal(t, "食べ", removed)
+
+	text, removed, ok = BlankWord("昨日勉強する時間がなかった。", WordForms("勉強する"), ClozeBlank)
+	assert.True(t, ok)
+	assert.Equal(t, "昨日＿＿時間がなかった。", text)
+	assert.Equal(t, "勉強する", removed, "the whole word is preferred to its stem")
+
+	_, _, ok = BlankWord("猫が好きです。", WordForms("犬"), ClozeBlank)
+	assert.False(t, ok)
+}
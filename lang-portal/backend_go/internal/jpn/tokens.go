@@ -0,0 +1,74 @@
+package jpn
+
+import (
+	"strings"
+	"unicode"
+)
+
+// okuriganaStops are the particles that end the kana written after a kanji
+// word; they are rarely part of the word itself
+const okuriganaStops = "はがをにでともへやの"
+
+// maxOkurigana caps the kana taken after a kanji word
+const maxOkurigana = 4
+
+// Token is a word found in running text. Surface is the text as written, with
+// the kana following its kanji; Stem is the kanji alone, to look up when the
+// surface is an inflected form. Stem is empty when the token has no kana.
+type Token struct {
+	Surface string `json:"surface"`
+	Stem    string `json:"stem,omitempty"`
+}
+
+// Tokens splits Japanese text, such as the output of OCR, into likely words
+// without a dictionary: runs of kanji with the kana written after them, up to
+// the next particle, and runs of at least two katakana. Text in hiragana only
+// is mostly grammar and is skipped, as is anything not Japanese. Tokens are
+// returned in order and may repeat.
+func Tokens(text string) []Token {
+	runes := []rune(text)
+	var tokens []Token
+	for i := 0; i < len(runes); {
+		switch {
+		case isKanji(runes[i]):
+			start := i
+			for i < len(runes) && isKanji(runes[i]) {
+				i++
+			}
+			stem := string(runes[start:i])
+			end := i
+			for end < len(runes) && end-i < maxOkurigana && isHiragana(runes[end]) && !strings.ContainsRune(okuriganaStops, runes[end]) {
+				end++
+			}
+			token := Token{Surface: string(runes[start:end])}
+			if end > i {
+				token.Stem = stem
+			}
+			tokens = append(tokens, token)
+			i = end
+		case isKatakana(runes[i]):
+			start := i
+			for i < len(runes) && isKatakana(runes[i]) {
+				i++
+			}
+			if i-start > 1 {
+				tokens = append(tokens, Token{Surface: string(runes[start:i])})
+			}
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r) || r == '々'
+}
+
+func isHiragana(r rune) bool {
+	return unicode.Is(unicode.Hiragana, r)
+}
+
+func isKatakana(r rune) bool {
+	return unicode.Is(unicode.Katakana, r) || r == 'ー'
+}
@@ -0,0 +1,24 @@
+package jpn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokens(t *testing.T) {
+	assert.Equal(t, []Token{
+		{Surface: "毎朝"},
+		{Surface: "パン"},
+		{Surface: "食べます", Stem: "食"},
+	}, Tokens("毎朝パンを食べます。"))
+
+	assert.Equal(t, []Token{
+		{Surface: "人々"},
+		{Surface: "ラーメン"},
+		{Surface: "好き", Stem: "好"},
+		{Surface: "ラーメン"},
+	}, Tokens("人々はラーメンが好き！ ラーメン、ね"), "particles end okurigana and tokens repeat")
+
+	assert.Empty(t, Tokens("これはペ hello 123"), "hiragana, single katakana and latin text are skipped")
+}
@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBedrockModel is the Bedrock model used when none is set
+const DefaultBedrockModel = "amazon.nova-lite-v1:0"
+
+// Bedrock completes chats with the Amazon Bedrock Converse API, or a gateway
+// exposing the same API. Requests are authenticated with a Bedrock API key
+// sent as a bearer token rather than signed. The Converse streaming API uses
+// a binary event format, so replies are not streamed: a streaming caller gets
+// the whole reply as a single piece.
+type Bedrock struct {
+	// URL is the runtime endpoint, such as
+	// https://bedrock-runtime.us-east-1.amazonaws.com
+	URL string
+	// APIKey is sent as a bearer token when set
+	APIKey string
+	// Model is the model ID, DefaultBedrockModel when empty
+	Model  string
+	Client *http.Client
+}
+
+type bedrockContent struct {
+	Text string `json:"text"`
+}
+
+type bedrockMessage struct {
+	Role    string           `json:"role"`
+	Content []bedrockContent `json:"content"`
+}
+
+type bedrockRequest struct {
+	Messages        []bedrockMessage `json:"messages"`
+	System          []bedrockContent `json:"system,omitempty"`
+	InferenceConfig *bedrockConfig   `json:"inferenceConfig,omitempty"`
+}
+
+type bedrockConfig struct {
+	MaxTokens int `json:"maxTokens"`
+}
+
+type bedrockResponse struct {
+	Output struct {
+		Message bedrockMessage `json:"message"`
+	} `json:"output"`
+	Usage struct {
+		InputTokens  int `json:"inputTokens"`
+		OutputTokens int `json:"outputTokens"`
+	} `json:"usage"`
+}
+
+// Chat implements Client
+func (b *Bedrock) Chat(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	model := b.Model
+	if model == "" {
+		model = DefaultBedrockModel
+	}
+	// System messages are passed apart from the conversation
+	var body bedrockRequest
+	for _, m := range req.Messages {
+		if m.Role == RoleSystem {
+			body.System = append(body.System, bedrockContent{Text: m.Content})
+			continue
+		}
+		body.Messages = append(body.Messages, bedrockMessage{Role: m.Role, Content: []bedrockContent{{Text: m.Content}}})
+	}
+	if req.MaxTokens > 0 {
+		body.InferenceConfig = &bedrockConfig{MaxTokens: req.MaxTokens}
+	}
+
+	endpoint := strings.TrimSuffix(b.URL, "/") + "/model/" + url.PathEscape(model) + "/converse"
+	resp, err := postJSON(ctx, b.Client, endpoint, bearer(b.APIKey), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var completion bedrockResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&completion); err != nil {
+		return nil, fmt.Errorf("%w: decoding chat response: %v", ErrUnavailable, err)
+	}
+	var content strings.Builder
+	for _, part := range completion.Output.Message.Content {
+		content.WriteString(part.Text)
+	}
+	result := &Response{
+		Content: content.String(),
+		Usage:   Usage{PromptTokens: completion.Usage.InputTokens, CompletionTokens: completion.Usage.OutputTokens},
+	}
+	if onDelta != nil && result.Content != "" {
+		if err := onDelta(result.Content); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// maxResponseSize caps the size of a chat response, streamed or not
+const maxResponseSize = 4 << 20
+
+// postJSON sends body as JSON to endpoint and returns the response when it is
+// a success. Failures to reach the endpoint and error statuses are ErrUnavailable.
+func postJSON(ctx context.Context, client *http.Client, endpoint string, header http.Header, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: chat API answered %s", ErrUnavailable, resp.Status)
+	}
+	return resp, nil
+}
+
+// bearer returns the header authenticating with token, if any
+func bearer(token string) http.Header {
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header
+}
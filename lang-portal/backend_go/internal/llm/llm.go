@@ -0,0 +1,55 @@
+// Package llm talks to large language models for the AI features, such as the
+// tutor, through a chat completions API.
+package llm
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnavailable is returned when the model cannot be reached or answers with
+// an error
+var ErrUnavailable = errors.New("language model unavailable")
+
+// Message roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is a turn of a chat
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is a chat to complete
+type Request struct {
+	Messages []Message
+	// MaxTokens caps the length of the reply; zero leaves it to the model
+	MaxTokens int
+}
+
+// Usage counts the tokens of a completion
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Response is a completed reply
+type Response struct {
+	Content string
+	Usage   Usage
+	// Provider names the provider that replied, when the reply went through a
+	// Router
+	Provider string
+}
+
+// Client completes chats
+type Client interface {
+	// Chat completes the chat in req. If onDelta is not nil the reply is
+	// streamed: onDelta is called with each piece of text as it arrives, and an
+	// error it returns aborts the completion.
+	Chat(ctx context.Context, req Request, onDelta func(string) error) (*Response, error)
+}
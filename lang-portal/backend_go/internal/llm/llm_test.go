@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAI_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body openAIRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, DefaultOpenAIModel, body.Model)
+		assert.Equal(t, []Message{{Role: RoleUser, Content: "こんにちは"}}, body.Messages)
+
+		if !body.Stream {
+			w.Write([]byte(`{"choices":[{"message":{"content":"やあ"}}],"usage":{"prompt_tokens":5,"completion_tokens":2}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"や\"}}]}\n\n" +
+			": keep-alive\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"あ\"}}]}\n\n" +
+			"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":2}}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client := &OpenAI{URL: server.URL, APIKey: "secret"}
+	req := Request{Messages: []Message{{Role: RoleUser, Content: "こんにちは"}}}
+
+	resp, err := client.Chat(context.Background(), req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &Response{Content: "やあ", Usage: Usage{PromptTokens: 5, CompletionTokens: 2}}, resp)
+
+	var deltas []string
+	resp, err = client.Chat(context.Background(), req, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"や", "あ"}, deltas)
+	assert.Equal(t, &Response{Content: "やあ", Usage: Usage{PromptTokens: 5, CompletionTokens: 2}}, resp)
+
+	stop := errors.New("client went away")
+	_, err = client.Chat(context.Background(), req, func(string) error { return stop })
+	assert.ErrorIs(t, err, stop)
+
+	server.Close()
+	_, err = client.Chat(context.Background(), req, nil)
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestOllama_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ollamaRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, DefaultOllamaModel, body.Model)
+		assert.Equal(t, 50, body.Options.NumPredict)
+
+		if !body.Stream {
+			w.Write([]byte(`{"message":{"role":"assistant","content":"やあ"},"done":true,"prompt_eval_count":5,"eval_count":2}`))
+			return
+		}
+		w.Write([]byte(`{"message":{"content":"や"},"done":false}` + "\n" +
+			`{"message":{"content":"あ"},"done":false}` + "\n" +
+			`{"message":{"content":""},"done":true,"prompt_eval_count":5,"eval_count":2}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := &Ollama{URL: server.URL}
+	req := Request{Messages: []Message{{Role: RoleUser, Content: "こんにちは"}}, MaxTokens: 50}
+	want := &Response{Content: "やあ", Usage: Usage{PromptTokens: 5, CompletionTokens: 2}}
+
+	resp, err := client.Chat(context.Background(), req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, resp)
+
+	var deltas []string
+	resp, err = client.Chat(context.Background(), req, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"や", "あ"}, deltas)
+	assert.Equal(t, want, resp)
+}
+
+func TestBedrock_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/model/"+DefaultBedrockModel+"/converse", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body bedrockRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []bedrockContent{{Text: "Be brief"}}, body.System, "system messages are passed apart")
+		assert.Equal(t, []bedrockMessage{{Role: RoleUser, Content: []bedrockContent{{Text: "こんにちは"}}}}, body.Messages)
+		w.Write([]byte(`{"output":{"message":{"role":"assistant","content":[{"text":"や"},{"text":"あ"}]}},"usage":{"inputTokens":5,"outputTokens":2}}`))
+	}))
+	defer server.Close()
+
+	client := &Bedrock{URL: server.URL + "/", APIKey: "secret"}
+	req := Request{Messages: []Message{{Role: RoleSystem, Content: "Be brief"}, {Role: RoleUser, Content: "こんにちは"}}}
+
+	var deltas []string
+	resp, err := client.Chat(context.Background(), req, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"やあ"}, deltas, "replies arrive whole")
+	assert.Equal(t, &Response{Content: "やあ", Usage: Usage{PromptTokens: 5, CompletionTokens: 2}}, resp)
+}
+
+func TestRender(t *testing.T) {
+	template := "Use {{word}} in a sentence for a {{ level }} learner. Then {{word}} again."
+	assert.Equal(t, []string{"word", "level"}, Variables(template))
+
+	rendered, err := Render(template, map[string]string{"word": "食べる", "level": "beginner", "unused": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, "Use 食べる in a sentence for a beginner learner. Then 食べる again.", rendered)
+
+	rendered, err = Render("Literal {{word}}", map[string]string{"word": "{{level}}"})
+	require.NoError(t, err)
+	assert.Equal(t, "Literal {{level}}", rendered, "values are not rendered")
+
+	_, err = Render(template, map[string]string{"word": "食べる"})
+	assert.ErrorIs(t, err, ErrMissingVariable)
+	assert.ErrorContains(t, err, "level")
+
+	assert.Empty(t, Variables("No {{ Variables }} or {single} braces"))
+}
@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Ollama defaults
+const (
+	// DefaultOllamaURL is the chat endpoint of a local Ollama server
+	DefaultOllamaURL = "http://localhost:11434/api/chat"
+	// DefaultOllamaModel is the chat model used when none is set
+	DefaultOllamaModel = "llama3.2"
+)
+
+// Ollama completes chats with a model run locally by Ollama
+type Ollama struct {
+	// URL is the chat endpoint, DefaultOllamaURL when empty
+	URL string
+	// Model is the chat model, which must have been pulled; DefaultOllamaModel
+	// when empty
+	Model  string
+	Client *http.Client
+}
+
+type ollamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Options  ollamaOptions `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	NumPredict int `json:"num_predict,omitempty"`
+}
+
+// ollamaResponse is a completion, or a chunk of a streamed one; token counts
+// come with the final chunk
+type ollamaResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error"`
+}
+
+// Chat implements Client
+func (o *Ollama) Chat(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	model := o.Model
+	if model == "" {
+		model = DefaultOllamaModel
+	}
+	endpoint := o.URL
+	if endpoint == "" {
+		endpoint = DefaultOllamaURL
+	}
+	body := ollamaRequest{
+		Model:    model,
+		Messages: req.Messages,
+		Stream:   onDelta != nil,
+		Options:  ollamaOptions{NumPredict: req.MaxTokens},
+	}
+	resp, err := postJSON(ctx, o.Client, endpoint, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// A streamed reply is one JSON object per line; an unstreamed one is a
+	// single object
+	var content strings.Builder
+	result := &Response{}
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxResponseSize))
+	scanner.Buffer(make([]byte, 64<<10), maxResponseSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var chunk ollamaResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return nil, fmt.Errorf("%w: decoding chat response: %v", ErrUnavailable, err)
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnavailable, chunk.Error)
+		}
+		if text := chunk.Message.Content; text != "" {
+			content.WriteString(text)
+			if onDelta != nil {
+				if err := onDelta(text); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if chunk.Done {
+			result.Usage = Usage{PromptTokens: chunk.PromptEvalCount, CompletionTokens: chunk.EvalCount}
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading chat response: %v", ErrUnavailable, err)
+	}
+	result.Content = content.String()
+	return result, nil
+}
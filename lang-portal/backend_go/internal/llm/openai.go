@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI API defaults
+const (
+	// DefaultOpenAIURL is the chat completions endpoint of the OpenAI API
+	DefaultOpenAIURL = "https://api.openai.com/v1/chat/completions"
+	// DefaultOpenAIModel is the chat model used when none is set
+	DefaultOpenAIModel = "gpt-4o-mini"
+)
+
+// OpenAI completes chats with an OpenAI-compatible chat completions API: the
+// OpenAI API, or a local server exposing the same endpoint
+type OpenAI struct {
+	// URL is the chat completions endpoint, DefaultOpenAIURL when empty
+	URL string
+	// APIKey is sent as a bearer token when set
+	APIKey string
+	// Model is the chat model, DefaultOpenAIModel when empty
+	Model  string
+	Client *http.Client
+}
+
+type openAIRequest struct {
+	Model         string         `json:"model"`
+	Messages      []Message      `json:"messages"`
+	MaxTokens     int            `json:"max_tokens,omitempty"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIResponse is a completion, or a chunk of a streamed one
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+}
+
+// Chat implements Client
+func (o *OpenAI) Chat(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	model := o.Model
+	if model == "" {
+		model = DefaultOpenAIModel
+	}
+	body := openAIRequest{Model: model, Messages: req.Messages, MaxTokens: req.MaxTokens}
+	if onDelta != nil {
+		body.Stream = true
+		body.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+	endpoint := o.URL
+	if endpoint == "" {
+		endpoint = DefaultOpenAIURL
+	}
+	resp, err := postJSON(ctx, o.Client, endpoint, bearer(o.APIKey), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if onDelta == nil {
+		var completion openAIResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&completion); err != nil {
+			return nil, fmt.Errorf("%w: decoding chat response: %v", ErrUnavailable, err)
+		}
+		result := &Response{}
+		if len(completion.Choices) > 0 {
+			result.Content = completion.Choices[0].Message.Content
+		}
+		if completion.Usage != nil {
+			result.Usage = *completion.Usage
+		}
+		return result, nil
+	}
+	return readStream(io.LimitReader(resp.Body, maxResponseSize), onDelta)
+}
+
+// readStream reads a streamed completion, sent as server-sent events with one
+// chunk per data line and a final [DONE]
+func readStream(body io.Reader, onDelta func(string) error) (*Response, error) {
+	var content strings.Builder
+	result := &Response{}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk openAIResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("%w: decoding chat stream: %v", ErrUnavailable, err)
+		}
+		if chunk.Usage != nil {
+			result.Usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := chunk.Choices[0].Delta.Content
+		content.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading chat stream: %v", ErrUnavailable, err)
+	}
+	result.Content = content.String()
+	return result, nil
+}
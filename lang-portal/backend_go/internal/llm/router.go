@@ -0,0 +1,285 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Router defaults
+const (
+	DefaultTimeout          = 60 * time.Second
+	DefaultRetries          = 2
+	DefaultBackoff          = 500 * time.Millisecond
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+)
+
+// Circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Provider is a named model backend
+type Provider struct {
+	Name   string
+	Client Client
+}
+
+// RouterConfig tunes how a Router calls its providers. Zero values take the
+// defaults; a negative Retries disables retries.
+type RouterConfig struct {
+	// Timeout bounds each attempt, including a streamed reply
+	Timeout time.Duration
+	// Retries is the number of further attempts at a provider after it fails
+	Retries int
+	// Backoff is the wait before the first retry, doubling for each one after
+	Backoff time.Duration
+	// FailureThreshold is the number of consecutive failures that opens a
+	// provider's circuit, skipping it for Cooldown
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+// ProviderStats reports the use and health of a provider since the start
+type ProviderStats struct {
+	Name             string     `json:"name"`
+	State            string     `json:"state"`
+	Requests         int64      `json:"requests"`
+	Failures         int64      `json:"failures"`
+	PromptTokens     int64      `json:"prompt_tokens"`
+	CompletionTokens int64      `json:"completion_tokens"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastErrorAt      *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Router is a Client over several providers, tried in order. Each attempt is
+// bounded by a timeout and retried with backoff while the provider is
+// unavailable, before failing over to the next provider. A provider that keeps
+// failing has its circuit opened and is skipped until a cooldown has passed,
+// so callers fail fast instead of waiting on it. A reply that has started
+// streaming is never retried, as its start has been delivered already.
+type Router struct {
+	config    RouterConfig
+	providers []*routedProvider
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// routedProvider is a provider with its circuit breaker and counters
+type routedProvider struct {
+	Provider
+
+	mu    sync.Mutex
+	stats ProviderStats
+	// failures counts consecutive failures
+	failures  int
+	openUntil time.Time
+	// probing is set while the single trial call of a half-open circuit runs
+	probing bool
+}
+
+// NewRouter creates a router over providers, in order of preference
+func NewRouter(config RouterConfig, providers ...Provider) *Router {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Retries == 0 {
+		config.Retries = DefaultRetries
+	}
+	if config.Retries < 0 {
+		config.Retries = 0
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultBackoff
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultCooldown
+	}
+
+	r := &Router{config: config, now: time.Now, sleep: sleep}
+	for _, p := range providers {
+		r.providers = append(r.providers, &routedProvider{Provider: p, stats: ProviderStats{Name: p.Name}})
+	}
+	return r
+}
+
+// Chat implements Client. Response.Provider names the provider that replied.
+func (r *Router) Chat(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	var lastErr error
+	for _, p := range r.providers {
+		for attempt := 0; attempt <= r.config.Retries; attempt++ {
+			if attempt > 0 {
+				if err := r.sleep(ctx, r.config.Backoff<<(attempt-1)); err != nil {
+					return nil, err
+				}
+			}
+			if !p.allow(r.now()) {
+				break
+			}
+
+			resp, started, err := r.attempt(ctx, p, req, onDelta)
+			if err == nil {
+				p.succeeded(resp.Usage)
+				resp.Provider = p.Name
+				return resp, nil
+			}
+			if ctx.Err() != nil {
+				// The caller gave up; that says nothing about the provider
+				p.release()
+				return nil, ctx.Err()
+			}
+			if !errors.Is(err, ErrUnavailable) {
+				p.release()
+				return nil, err
+			}
+			opened := p.failed(err, r.now(), r.config.FailureThreshold, r.config.Cooldown)
+			lastErr = fmt.Errorf("%s: %w", p.Name, err)
+			if started {
+				return nil, lastErr
+			}
+			if opened {
+				break
+			}
+		}
+	}
+	if lastErr == nil {
+		return nil, fmt.Errorf("%w: every provider is failing", ErrUnavailable)
+	}
+	return nil, lastErr
+}
+
+// attempt calls a provider once within the timeout, reporting whether any of
+// the reply was streamed
+func (r *Router) attempt(ctx context.Context, p *routedProvider, req Request, onDelta func(string) error) (*Response, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	started := false
+	var deliver func(string) error
+	if onDelta != nil {
+		deliver = func(text string) error {
+			started = true
+			return onDelta(text)
+		}
+	}
+	resp, err := p.Client.Chat(ctx, req, deliver)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, ErrUnavailable) {
+		err = fmt.Errorf("%w: no reply within %s", ErrUnavailable, r.config.Timeout)
+	}
+	return resp, started, err
+}
+
+// Stats reports the use and health of each provider, in order of preference
+func (r *Router) Stats() []ProviderStats {
+	now := r.now()
+	stats := make([]ProviderStats, len(r.providers))
+	for i, p := range r.providers {
+		p.mu.Lock()
+		stats[i] = p.stats
+		stats[i].State = p.state(now)
+		p.mu.Unlock()
+	}
+	return stats
+}
+
+// Check returns an error when every provider's circuit is open, so that no
+// request would be attempted
+func (r *Router) Check(ctx context.Context) error {
+	now := r.now()
+	for _, p := range r.providers {
+		p.mu.Lock()
+		state := p.state(now)
+		p.mu.Unlock()
+		if state != StateOpen {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: every provider is failing", ErrUnavailable)
+}
+
+// allow reports whether the provider may be called. Once the cooldown of an
+// open circuit has passed, a single trial call is let through.
+func (p *routedProvider) allow(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch p.state(now) {
+	case StateOpen:
+		return false
+	case StateHalfOpen:
+		if p.probing {
+			return false
+		}
+		p.probing = true
+	}
+	p.stats.Requests++
+	return true
+}
+
+// succeeded closes the circuit and counts the tokens of a reply
+func (p *routedProvider) succeeded(usage Usage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures = 0
+	p.openUntil = time.Time{}
+	p.probing = false
+	p.stats.PromptTokens += int64(usage.PromptTokens)
+	p.stats.CompletionTokens += int64(usage.CompletionTokens)
+}
+
+// failed counts a failure, opening the circuit once there are threshold in a
+// row, and reports whether the circuit is open
+func (p *routedProvider) failed(err error, now time.Time, threshold int, cooldown time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probing = false
+	p.failures++
+	p.stats.Failures++
+	p.stats.LastError = err.Error()
+	at := now
+	p.stats.LastErrorAt = &at
+	if p.failures < threshold {
+		return false
+	}
+	p.openUntil = now.Add(cooldown)
+	return true
+}
+
+// release ends a call that neither succeeded nor failed, such as one the
+// caller cancelled
+func (p *routedProvider) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probing = false
+}
+
+// state returns the circuit state; p.mu must be held
+func (p *routedProvider) state(now time.Time) string {
+	switch {
+	case p.openUntil.IsZero():
+		return StateClosed
+	case now.Before(p.openUntil):
+		return StateOpen
+	}
+	return StateHalfOpen
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,142 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedClient answers with its errors in turn, then with reply
+type scriptedClient struct {
+	errs   []error
+	pieces []string
+	calls  int
+}
+
+func (c *scriptedClient) Chat(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	c.calls++
+	if onDelta != nil {
+		for _, piece := range c.pieces {
+			if err := onDelta(piece); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Response{Content: "ok", Usage: Usage{PromptTokens: 10, CompletionTokens: 3}}, nil
+}
+
+// hangingClient never replies
+type hangingClient struct{}
+
+func (hangingClient) Chat(ctx context.Context, req Request, onDelta func(string) error) (*Response, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// testRouter returns a router over providers with a controllable clock that
+// records its backoff waits instead of sleeping
+func testRouter(config RouterConfig, providers ...Provider) (*Router, *time.Time, *[]time.Duration) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+	r := NewRouter(config, providers...)
+	r.now = func() time.Time { return now }
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return r, &now, &waits
+}
+
+func TestRouter_RetriesThenFailsOver(t *testing.T) {
+	down := fmt.Errorf("%w: 503", ErrUnavailable)
+	primary := &scriptedClient{errs: []error{down, down, down}}
+	secondary := &scriptedClient{}
+	r, _, waits := testRouter(RouterConfig{Backoff: 100 * time.Millisecond},
+		Provider{Name: "openai", Client: primary}, Provider{Name: "ollama", Client: secondary})
+
+	resp, err := r.Chat(context.Background(), Request{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ollama", resp.Provider)
+	assert.Equal(t, 3, primary.calls, "one attempt and two retries")
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *waits)
+
+	stats := r.Stats()
+	assert.Equal(t, ProviderStats{Name: "openai", State: StateClosed, Requests: 3, Failures: 3, LastError: down.Error(), LastErrorAt: stats[0].LastErrorAt}, stats[0])
+	assert.Equal(t, ProviderStats{Name: "ollama", State: StateClosed, Requests: 1, PromptTokens: 10, CompletionTokens: 3}, stats[1])
+}
+
+func TestRouter_CircuitBreaker(t *testing.T) {
+	down := fmt.Errorf("%w: 503", ErrUnavailable)
+	client := &scriptedClient{errs: []error{down, down, down, down}}
+	r, now, _ := testRouter(RouterConfig{Retries: -1, FailureThreshold: 2, Cooldown: time.Minute}, Provider{Name: "openai", Client: client})
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Chat(context.Background(), Request{}, nil)
+		assert.ErrorIs(t, err, ErrUnavailable)
+	}
+	assert.Equal(t, StateOpen, r.Stats()[0].State)
+	assert.ErrorIs(t, r.Check(context.Background()), ErrUnavailable)
+
+	// An open circuit fails fast without calling the provider
+	_, err := r.Chat(context.Background(), Request{}, nil)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, 2, client.calls)
+
+	// After the cooldown a trial call is let through; failing reopens the circuit
+	*now = now.Add(time.Minute)
+	assert.Equal(t, StateHalfOpen, r.Stats()[0].State)
+	assert.NoError(t, r.Check(context.Background()))
+	_, err = r.Chat(context.Background(), Request{}, nil)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, StateOpen, r.Stats()[0].State)
+
+	// and succeeding closes it
+	*now = now.Add(time.Minute)
+	client.errs = nil
+	_, err = r.Chat(context.Background(), Request{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, r.Stats()[0].State)
+	assert.Equal(t, 4, client.calls)
+}
+
+func TestRouter_DoesNotRetryStartedOrCallerErrors(t *testing.T) {
+	down := fmt.Errorf("%w: connection reset", ErrUnavailable)
+	streaming := &scriptedClient{pieces: []string{"や"}, errs: []error{down}}
+	backup := &scriptedClient{}
+	r, _, _ := testRouter(RouterConfig{}, Provider{Name: "openai", Client: streaming}, Provider{Name: "ollama", Client: backup})
+
+	var deltas []string
+	_, err := r.Chat(context.Background(), Request{}, func(text string) error {
+		deltas = append(deltas, text)
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, []string{"や"}, deltas, "a started reply is not repeated")
+	assert.Zero(t, backup.calls)
+
+	stop := errors.New("client went away")
+	_, err = r.Chat(context.Background(), Request{}, func(string) error { return stop })
+	assert.ErrorIs(t, err, stop)
+	assert.Zero(t, backup.calls)
+	assert.Equal(t, int64(1), r.Stats()[0].Failures, "caller errors are not provider failures")
+}
+
+func TestRouter_Timeout(t *testing.T) {
+	r, _, _ := testRouter(RouterConfig{Timeout: 10 * time.Millisecond, Retries: -1}, Provider{Name: "slow", Client: hangingClient{}})
+
+	_, err := r.Chat(context.Background(), Request{}, nil)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorContains(t, err, "no reply within 10ms")
+}
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrMissingVariable is returned when a prompt template refers to a variable
+// that has no value
+var ErrMissingVariable = errors.New("missing template variable")
+
+// templateVariable matches a {{variable}} in a prompt template; spaces inside
+// the braces are allowed
+var templateVariable = regexp.MustCompile(`\{\{\s*([a-z_][a-z0-9_]*)\s*\}\}`)
+
+// Variables returns the names of the variables a prompt template refers to, in
+// order of first use
+func Variables(template string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templateVariable.FindAllStringSubmatch(template, -1) {
+		if name := match[1]; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Render replaces the {{variables}} of a prompt template with their values.
+// Values are inserted as they are and not rendered themselves.
+func Render(template string, values map[string]string) (string, error) {
+	var missing []string
+	for _, name := range Variables(template) {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingVariable, strings.Join(missing, ", "))
+	}
+	return templateVariable.ReplaceAllStringFunc(template, func(match string) string {
+		return values[templateVariable.FindStringSubmatch(match)[1]]
+	}), nil
+}
@@ -0,0 +1,91 @@
+// Package mail sends email, through an SMTP server or an HTTP API in the style
+// of SendGrid's, and renders the reminder emails sent to learners.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"time"
+)
+
+// ErrUnavailable is returned when the mail server or API cannot be reached or
+// refuses a message
+var ErrUnavailable = errors.New("mail delivery unavailable")
+
+// Message is an email with a plain text body and an optional HTML alternative.
+// From and To are addresses such as "Lang Portal <noreply@example.com>".
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// Headers are extra headers, such as List-Unsubscribe
+	Headers map[string]string
+}
+
+// Sender sends email
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// encode renders msg in MIME format, as sent over SMTP
+func encode(msg Message, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	header := map[string]string{
+		"From":         msg.From,
+		"To":           msg.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date":         date.Format(time.RFC1123Z),
+		"MIME-Version": "1.0",
+	}
+	for name, value := range msg.Headers {
+		header[name] = value
+	}
+
+	body := &bytes.Buffer{}
+	parts := multipart.NewWriter(body)
+	alternatives := []struct{ contentType, content string }{{"text/plain", msg.Text}}
+	if msg.HTML != "" {
+		alternatives = append(alternatives, struct{ contentType, content string }{"text/html", msg.HTML})
+	}
+	for _, alternative := range alternatives {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alternative.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write([]byte(alternative.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	header["Content-Type"] = "multipart/alternative; boundary=" + parts.Boundary()
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, header[name])
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage() Message {
+	return Message{
+		From:    "Lang Portal <noreply@example.com>",
+		To:      "yuki@example.com",
+		Subject: "今日の復習",
+		Text:    "You have 5 reviews due today.",
+		HTML:    "<p>You have 5 reviews due today.</p>",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/u/abc>"},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	data, err := encode(testMessage(), time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	parsed, err := netmail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "今日の復習", subject)
+	assert.Equal(t, "<https://example.com/u/abc>", parsed.Header.Get("List-Unsubscribe"))
+	assert.Contains(t, parsed.Header.Get("Content-Type"), "multipart/alternative")
+	body, err := io.ReadAll(parsed.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "text/plain; charset=utf-8")
+	assert.Contains(t, string(body), "text/html; charset=utf-8")
+}
+
+// fakeSMTPServer accepts one message on a local port and returns its envelope
+// and data on the channel
+func fakeSMTPServer(t *testing.T) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	received := make(chan []string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var lines []string
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "MAIL"), strings.HasPrefix(line, "RCPT"):
+				lines = append(lines, line)
+				reply("250 OK")
+			case line == "DATA":
+				reply("354 Go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+					lines = append(lines, strings.TrimRight(data, "\r\n"))
+				}
+				reply("250 Queued")
+			case line == "QUIT":
+				reply("221 Bye")
+				received <- lines
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTP_Send(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, (&SMTP{Addr: addr}).Send(ctx, testMessage()))
+	lines := <-received
+	assert.Equal(t, "MAIL FROM:<noreply@example.com>", strings.Split(lines[0], " BODY")[0])
+	assert.Equal(t, "RCPT TO:<yuki@example.com>", lines[1])
+	assert.Contains(t, lines, "To: yuki@example.com")
+
+	err := (&SMTP{Addr: addr}).Send(ctx, Message{From: "noreply@example.com", To: "not an address"})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnavailable)
+
+	err = (&SMTP{Addr: addr}).Send(ctx, testMessage())
+	assert.ErrorIs(t, err, ErrUnavailable, "the server has closed")
+}
+
+func TestSendGrid_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body sendGridRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, sendGridAddress{Email: "noreply@example.com", Name: "Lang Portal"}, body.From)
+		assert.Equal(t, []sendGridAddress{{Email: "yuki@example.com"}}, body.Personalizations[0].To)
+		assert.Equal(t, "今日の復習", body.Subject)
+		require.Len(t, body.Content, 2)
+		assert.Equal(t, "text/plain", body.Content[0].Type)
+		assert.Equal(t, "<https://example.com/u/abc>", body.Headers["List-Unsubscribe"])
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := &SendGrid{URL: server.URL, APIKey: "secret"}
+	require.NoError(t, sender.Send(context.Background(), testMessage()))
+
+	server.Close()
+	assert.ErrorIs(t, sender.Send(context.Background(), testMessage()), ErrUnavailable)
+}
+
+func TestRenderReminder(t *testing.T) {
+	reminder := Reminder{DueReviews: 12, Streak: 1, Level: 3, XP: 320, UnreadNotifications: 2, UnsubscribeURL: "https://example.com/u/abc"}
+	msg, err := RenderReminder(ReminderDaily, reminder)
+	require.NoError(t, err)
+	assert.Equal(t, "12 reviews due today", msg.Subject)
+	assert.Contains(t, msg.Text, "Your study streak is 1 day;")
+	assert.Contains(t, msg.Text, "2 unread notifications")
+	assert.Contains(t, msg.HTML, `<a href="https://example.com/u/abc">`)
+	assert.Equal(t, "<https://example.com/u/abc>", msg.Headers["List-Unsubscribe"])
+
+	reminder.WeekSessions, reminder.WeekReviews, reminder.WeekAccuracy = 4, 40, 85
+	msg, err = RenderReminder(ReminderWeekly, reminder)
+	require.NoError(t, err)
+	assert.Equal(t, "Your week of Japanese study", msg.Subject)
+	assert.Contains(t, msg.Text, "Reviews: 40 (85% correct)")
+
+	_, err = RenderReminder("hourly", reminder)
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+// Reminder emails
+const (
+	ReminderDaily  = "daily"
+	ReminderWeekly = "weekly"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Each reminder has a text template defining "subject" and "body", and an HTML
+// template for the same body
+var (
+	textReminders = template.Must(template.ParseFS(templates, "templates/*.txt"))
+	htmlReminders = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/*.html"))
+)
+
+// Reminder is what a reminder email tells a learner. The weekly reminder also
+// sums up the last seven days.
+type Reminder struct {
+	DueReviews          int64
+	Streak              int
+	Level               int
+	XP                  int64
+	UnreadNotifications int64
+	WeekSessions        int64
+	WeekReviews         int64
+	// WeekAccuracy is the percentage of correct reviews in the last seven days
+	WeekAccuracy   int
+	UnsubscribeURL string
+}
+
+// RenderReminder renders the daily or weekly reminder. The message carries
+// List-Unsubscribe headers for one-click unsubscribing; From and To are left
+// for the caller.
+func RenderReminder(kind string, reminder Reminder) (Message, error) {
+	if kind != ReminderDaily && kind != ReminderWeekly {
+		return Message{}, fmt.Errorf("unknown reminder %q", kind)
+	}
+	var subject, text, html bytes.Buffer
+	if err := textReminders.ExecuteTemplate(&subject, kind+"-subject", reminder); err != nil {
+		return Message{}, err
+	}
+	if err := textReminders.ExecuteTemplate(&text, kind+".txt", reminder); err != nil {
+		return Message{}, err
+	}
+	if err := htmlReminders.ExecuteTemplate(&html, kind+".html", reminder); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Subject: subject.String(),
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + reminder.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
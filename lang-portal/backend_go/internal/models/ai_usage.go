@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AIUsage records the tokens of one request to the language model: the user
+// it was made for, the AI feature that made it and the provider that replied
+type AIUsage struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	User             string    `gorm:"not null;index:idx_ai_usage_user_created" json:"user"`
+	Feature          string    `gorm:"not null" json:"feature"`
+	Provider         string    `gorm:"not null;default:''" json:"provider"`
+	PromptTokens     int       `gorm:"not null;default:0" json:"prompt_tokens"`
+	CompletionTokens int       `gorm:"not null;default:0" json:"completion_tokens"`
+	CreatedAt        time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_ai_usage_user_created" json:"created_at"`
+}
+
+// TableName specifies the table name for the AIUsage model
+func (AIUsage) TableName() string {
+	return "ai_usage"
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Scopes granted to API keys
+const (
+	// ScopeRead allows reading requests (GET and HEAD)
+	ScopeRead = "read"
+	// ScopeWrite allows requests that change data
+	ScopeWrite = "write"
+	// ScopeAdmin allows the admin endpoints, including key management
+	ScopeAdmin = "admin"
+)
+
+// APIKey authenticates a machine client, such as a script or an external
+// activity, sent in the X-API-Key header. The key itself is shown once when it
+// is created; only its SHA-256 hash is stored.
+type APIKey struct {
+	ID         uint        `gorm:"primarykey" json:"id"`
+	Name       string      `gorm:"not null" json:"name"`
+	Prefix     string      `gorm:"not null" json:"prefix"`
+	KeyHash    string      `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes     StringSlice `gorm:"type:json;not null" json:"scopes"`
+	LastUsedAt *time.Time  `json:"last_used_at"`
+	RevokedAt  *time.Time  `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time   `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the APIKey model
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HasScope reports whether the key grants scope. The admin scope grants every
+// other scope, and write grants read.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == ScopeAdmin || (granted == ScopeWrite && scope == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
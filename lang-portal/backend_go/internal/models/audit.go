@@ -0,0 +1,122 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditActionDelete       = "delete"
+	AuditActionRemove       = "remove"
+	AuditActionResetHistory = "reset_history"
+	AuditActionFullReset    = "full_reset"
+	AuditActionImport       = "import"
+	AuditActionRestore      = "restore"
+	AuditActionRegister     = "register"
+	AuditActionCreate       = "create"
+	AuditActionRevoke       = "revoke"
+	AuditActionPublish      = "publish"
+	AuditActionUnpublish    = "unpublish"
+)
+
+// Audit entity types
+const (
+	AuditEntityWord      = "word"
+	AuditEntityGroup     = "group"
+	AuditEntityWordGroup = "word_group"
+	AuditEntityGroupGoal = "group_goal"
+	AuditEntityStudy     = "study_history"
+	AuditEntityActivity  = "study_activity"
+	AuditEntitySentence  = "example_sentence"
+	AuditEntityAPIKey    = "api_key"
+	AuditEntityCohort    = "cohort"
+	AuditEntityAll       = "all"
+)
+
+// RawJSON is a JSON document stored verbatim in a text column
+type RawJSON []byte
+
+// Value implements the driver.Valuer interface
+func (j RawJSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (j *RawJSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append(RawJSON(nil), v...)
+	case string:
+		*j = RawJSON(v)
+	default:
+		return errors.New("unsupported type for RawJSON")
+	}
+	return nil
+}
+
+// MarshalJSON embeds the stored document as-is
+func (j RawJSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON stores a copy of the raw document
+func (j *RawJSON) UnmarshalJSON(data []byte) error {
+	*j = append(RawJSON(nil), data...)
+	return nil
+}
+
+// AuditEntry records a destructive operation together with the state of the
+// affected entity before and after it
+type AuditEntry struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	Actor      string    `gorm:"not null;index" json:"actor"`
+	Action     string    `gorm:"not null;index" json:"action"`
+	EntityType string    `gorm:"not null;index:idx_audit_entries_entity" json:"entity_type"`
+	EntityID   *uint     `gorm:"index:idx_audit_entries_entity" json:"entity_id"`
+	Before     RawJSON   `gorm:"type:text" json:"before"`
+	After      RawJSON   `gorm:"type:text" json:"after"`
+	CreatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
+
+// TableName specifies the table name for the AuditEntry model
+func (AuditEntry) TableName() string {
+	return "audit_entries"
+}
+
+// NewAuditEntry builds an audit entry, encoding before and after as JSON.
+// A nil before or after is stored as NULL.
+func NewAuditEntry(actor, action, entityType string, entityID *uint, before, after interface{}) (*AuditEntry, error) {
+	entry := &AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+
+	if before != nil {
+		data, err := json.Marshal(before)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before = data
+	}
+	if after != nil {
+		data, err := json.Marshal(after)
+		if err != nil {
+			return nil, err
+		}
+		entry.After = data
+	}
+	return entry, nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Cohort is a class a teacher runs: the learners in it and the groups they
+// study. Owner is the teacher's actor; only they manage the cohort, and its
+// members see its leaderboard.
+type Cohort struct {
+	ID        uint               `gorm:"primarykey" json:"id"`
+	Name      string             `gorm:"not null" json:"name" validate:"required,min=1,max=100"`
+	Owner     string             `gorm:"not null;index" json:"owner"`
+	CreatedAt time.Time          `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Members   []CohortMembership `gorm:"foreignKey:CohortID" json:"members,omitempty"`
+	Groups    []CohortGroup      `gorm:"foreignKey:CohortID" json:"groups,omitempty"`
+}
+
+// TableName specifies the table name for the Cohort model
+func (Cohort) TableName() string {
+	return "cohorts"
+}
+
+// Validate validates the Cohort model
+func (c *Cohort) Validate() error {
+	return validate.Struct(c)
+}
+
+// MaxDisplayNameLength bounds the name a learner is shown under on leaderboards
+const MaxDisplayNameLength = 50
+
+// CohortMembership puts a learner, the actor their study sessions are recorded
+// under, in a cohort. The learner chooses the DisplayName shown on the cohort's
+// leaderboard, or to be left off it.
+type CohortMembership struct {
+	ID                uint      `gorm:"primarykey" json:"id"`
+	CohortID          uint      `gorm:"not null;uniqueIndex:idx_cohort_memberships_cohort_learner" json:"cohort_id"`
+	Learner           string    `gorm:"not null;uniqueIndex:idx_cohort_memberships_cohort_learner" json:"learner"`
+	DisplayName       string    `gorm:"not null;default:''" json:"display_name"`
+	LeaderboardOptOut bool      `gorm:"not null;default:false" json:"leaderboard_opt_out"`
+	CreatedAt         time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the CohortMembership model
+func (CohortMembership) TableName() string {
+	return "cohort_memberships"
+}
+
+// CohortGroup assigns a group to a cohort
+type CohortGroup struct {
+	CohortID  uint      `gorm:"primaryKey" json:"cohort_id"`
+	GroupID   uint      `gorm:"primaryKey;index" json:"group_id"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the CohortGroup model
+func (CohortGroup) TableName() string {
+	return "cohort_groups"
+}
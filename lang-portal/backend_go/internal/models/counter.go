@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// CounterReview is an answer to a counter drill: the reading given for a
+// number with a counter word, such as さんぼん for 三本
+type CounterReview struct {
+	ID      uint   `gorm:"primarykey" json:"id"`
+	Counter string `gorm:"not null;index" json:"counter"`
+	Number  int    `gorm:"not null" json:"number"`
+	Answer  string `gorm:"not null" json:"answer"`
+	Correct bool   `gorm:"not null" json:"correct"`
+	// AnswerTimeMs is the time taken to answer, or nil when not reported
+	AnswerTimeMs *int      `gorm:"column:answer_time_ms" json:"answer_time_ms,omitempty"`
+	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the CounterReview model
+func (CounterReview) TableName() string {
+	return "counter_reviews"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DailySummary is a learner's study on one UTC day, precomputed by the nightly
+// summary job so that historical analytics need not scan every review
+type DailySummary struct {
+	Learner string `gorm:"primarykey" json:"-"`
+	// Day is the UTC date as YYYY-MM-DD
+	Day string `gorm:"primarykey" json:"day"`
+	// Sessions counts the sessions with reviews on the day
+	Sessions       int64 `gorm:"not null;default:0" json:"sessions"`
+	Reviews        int64 `gorm:"not null;default:0" json:"reviews"`
+	CorrectReviews int64 `gorm:"not null;default:0" json:"correct_reviews"`
+	// NewWords counts the words the learner answered correctly for the first time
+	NewWords int64 `gorm:"not null;default:0" json:"new_words"`
+	// ActiveMinutes counts the minutes with at least one review
+	ActiveMinutes int64     `gorm:"not null;default:0" json:"active_minutes"`
+	ComputedAt    time.Time `gorm:"not null" json:"-"`
+}
+
+// TableName specifies the table name for the DailySummary model
+func (DailySummary) TableName() string {
+	return "daily_summaries"
+}
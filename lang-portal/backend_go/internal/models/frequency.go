@@ -0,0 +1,15 @@
+package models
+
+// WordFrequency is an entry of an imported word frequency list. Rank 1 is the
+// most frequent word. Reading is in hiragana, or empty when the list has none.
+type WordFrequency struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	Japanese string `gorm:"not null;index" json:"japanese"`
+	Reading  string `gorm:"not null;default:'';index" json:"reading"`
+	Rank     int    `gorm:"not null" json:"rank"`
+}
+
+// TableName specifies the table name for the WordFrequency model
+func (WordFrequency) TableName() string {
+	return "word_frequency"
+}
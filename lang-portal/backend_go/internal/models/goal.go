@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// GroupGoal is the mastery goal set on a group: a minimum accuracy over the
+// group's most recent study sessions
+type GroupGoal struct {
+	GroupID uint `gorm:"primarykey" json:"group_id"`
+	// MinAccuracy is the share of correct reviews required, between 0 and 1
+	MinAccuracy float64 `gorm:"not null" json:"min_accuracy" validate:"gt=0,lte=1"`
+	// Sessions is the number of most recent sessions the accuracy is measured over
+	Sessions  int       `gorm:"not null" json:"sessions" validate:"min=1,max=100"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName specifies the table name for the GroupGoal model
+func (GroupGoal) TableName() string {
+	return "group_goals"
+}
+
+// Validate validates the GroupGoal model
+func (g *GroupGoal) Validate() error {
+	return validate.Struct(g)
+}
+
+// GroupCertificate records that a group's goal was attained. The record is
+// signed by the server so that a shared copy can be verified.
+type GroupCertificate struct {
+	ID          uint      `gorm:"primarykey" json:"-"`
+	Token       string    `gorm:"not null;uniqueIndex" json:"id"`
+	GroupID     uint      `gorm:"not null;index" json:"group_id"`
+	GroupName   string    `gorm:"not null" json:"group_name"`
+	MinAccuracy float64   `gorm:"not null" json:"min_accuracy"`
+	Sessions    int       `gorm:"not null" json:"sessions"`
+	Accuracy    float64   `gorm:"not null" json:"accuracy"`
+	AchievedAt  time.Time `gorm:"not null" json:"achieved_at"`
+	Signature   string    `gorm:"not null" json:"signature"`
+}
+
+// TableName specifies the table name for the GroupCertificate model
+func (GroupCertificate) TableName() string {
+	return "group_certificates"
+}
@@ -4,11 +4,19 @@ import (
 	"time"
 )
 
-// Group represents a thematic group of words
+// Group represents a thematic group of words. Version counts updates of the
+// group, so that clients can detect that it changed since they read it.
+// ShareSlug is set while the group is published as a shared deck. Language
+// is the target language of the group; only words of that language are added
+// to it, and group names are unique per language.
 type Group struct {
 	ID        uint           `gorm:"primarykey" json:"id"`
-	Name      string         `gorm:"not null;uniqueIndex" json:"name" validate:"required,min=1"`
+	Name      string         `gorm:"not null;uniqueIndex:idx_groups_name_language" json:"name" validate:"required,min=1"`
+	Language  string         `gorm:"not null;default:'ja';index;uniqueIndex:idx_groups_name_language" json:"language"`
 	CreatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Version   uint           `gorm:"not null;default:1" json:"version"`
+	ShareSlug *string        `gorm:"uniqueIndex" json:"share_slug,omitempty"`
 	Words     []Word         `gorm:"many2many:word_groups;" json:"words,omitempty"`
 	Sessions  []StudySession `gorm:"foreignKey:GroupID" json:"sessions,omitempty"`
 }
@@ -57,7 +57,7 @@ func TestGroup_GetWordCount(t *testing.T) {
 			name: "single word",
 			group: Group{
 				Words: []Word{
-					{Japanese: "テスト"},
+					{Term: "テスト"},
 				},
 			},
 			want: 1,
@@ -66,9 +66,9 @@ func TestGroup_GetWordCount(t *testing.T) {
 			name: "multiple words",
 			group: Group{
 				Words: []Word{
-					{Japanese: "テスト"},
-					{Japanese: "こんにちは"},
-					{Japanese: "さようなら"},
+					{Term: "テスト"},
+					{Term: "こんにちは"},
+					{Term: "さようなら"},
 				},
 			},
 			want: 3,
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+	"unicode"
+)
+
+// Kanji is a kanji character and its dictionary data. A row is created for
+// every kanji found in a word's Japanese text; the dictionary fields are
+// filled in separately and stay empty until then.
+type Kanji struct {
+	ID        uint        `gorm:"primarykey" json:"id"`
+	Character string      `gorm:"not null;uniqueIndex" json:"character" validate:"required"`
+	Meanings  StringSlice `gorm:"type:json;not null" json:"meanings"`
+	Onyomi    StringSlice `gorm:"type:json;not null" json:"onyomi"`
+	Kunyomi   StringSlice `gorm:"type:json;not null" json:"kunyomi"`
+	// StrokeCount is 0 when unknown
+	StrokeCount int `gorm:"not null;default:0" json:"stroke_count" validate:"min=0,max=84"`
+	// JLPTLevel is 1 (N1) to 5 (N5), or 0 when unknown
+	JLPTLevel int       `gorm:"column:jlpt_level;not null;default:0" json:"jlpt_level" validate:"min=0,max=5"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Words     []Word    `gorm:"many2many:word_kanji;" json:"words,omitempty"`
+}
+
+// TableName specifies the table name for the Kanji model
+func (Kanji) TableName() string {
+	return "kanji"
+}
+
+// Validate validates the Kanji model
+func (k *Kanji) Validate() error {
+	return validate.Struct(k)
+}
+
+// IsKanji reports whether r is a kanji. Iteration marks such as 々 are not.
+func IsKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r) && (r < 0x3000 || r > 0x303f)
+}
+
+// KanjiIn returns the distinct kanji in text, in order of first appearance
+func KanjiIn(text string) []string {
+	var characters []string
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if IsKanji(r) && !seen[r] {
+			seen[r] = true
+			characters = append(characters, string(r))
+		}
+	}
+	return characters
+}
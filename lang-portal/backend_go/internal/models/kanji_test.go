@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKanjiIn(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"食べる", []string{"食"}},
+		{"日本語", []string{"日", "本", "語"}},
+		{"人々", []string{"人"}},
+		{"大学生の大学", []string{"大", "学", "生"}},
+		{"テスト", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, KanjiIn(tt.text), tt.text)
+	}
+}
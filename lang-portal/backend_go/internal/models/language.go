@@ -0,0 +1,76 @@
+package models
+
+// Target languages, the languages vocabulary is studied in, by ISO 639-1 code
+const (
+	LanguageJapanese = "ja"
+	LanguageKorean   = "ko"
+)
+
+// DefaultLanguage is the target language of words and groups created without
+// one, and of everything stored before languages were tracked
+const DefaultLanguage = LanguageJapanese
+
+// TargetLanguage describes a language vocabulary can be kept in. Words keep
+// the term in the language's own scripts and a romanized reading of it.
+type TargetLanguage struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// Script is the ISO 15924 code words of the language record for their
+	// term unless they name another one
+	Script string `json:"script"`
+	// Scripts are the ISO 15924 codes of the scripts terms are written in
+	Scripts []string `json:"scripts"`
+	// ReadingScript is the ISO 15924 code of the script of readings
+	ReadingScript string `json:"reading_script"`
+	// Romanization names the system readings are written in
+	Romanization string `json:"romanization"`
+}
+
+// TargetLanguages lists the languages vocabulary can be kept in
+var TargetLanguages = []TargetLanguage{
+	{
+		Code:          LanguageJapanese,
+		Name:          "Japanese",
+		Script:        "Jpan",
+		Scripts:       []string{"Hani", "Hira", "Kana"},
+		ReadingScript: "Latn",
+		Romanization:  "Hepburn",
+	},
+	{
+		Code:          LanguageKorean,
+		Name:          "Korean",
+		Script:        "Kore",
+		Scripts:       []string{"Hang", "Hani"},
+		ReadingScript: "Latn",
+		Romanization:  "Revised Romanization",
+	},
+}
+
+// FindTargetLanguage returns the target language with code
+func FindTargetLanguage(code string) (TargetLanguage, bool) {
+	for _, language := range TargetLanguages {
+		if language.Code == code {
+			return language, true
+		}
+	}
+	return TargetLanguage{}, false
+}
+
+// DefaultScripts sets the scripts of the word's term and reading that are
+// empty to those of its language, the default language if it has none
+func (w *Word) DefaultScripts() {
+	code := w.Language
+	if code == "" {
+		code = DefaultLanguage
+	}
+	language, ok := FindTargetLanguage(code)
+	if !ok {
+		return
+	}
+	if w.Script == "" {
+		w.Script = language.Script
+	}
+	if w.ReadingScript == "" {
+		w.ReadingScript = language.ReadingScript
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// DataMigration records a one-time data repair so that it runs only once per database
+type DataMigration struct {
+	Name      string    `gorm:"primarykey" json:"name"`
+	Report    RawJSON   `gorm:"type:text" json:"report,omitempty"`
+	AppliedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"applied_at"`
+}
+
+// TableName specifies the table name for the DataMigration model
+func (DataMigration) TableName() string {
+	return "data_migrations"
+}
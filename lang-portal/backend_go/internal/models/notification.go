@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Kinds of notification
+const (
+	// NotificationKindReviewsDue reminds a learner of the reviews due today
+	NotificationKindReviewsDue = "reviews_due"
+	// NotificationKindStreakAtRisk warns a learner who studied yesterday but
+	// not yet today that their streak ends at midnight UTC
+	NotificationKindStreakAtRisk = "streak_at_risk"
+	// NotificationKindAchievement tells a learner about a level they reached
+	NotificationKindAchievement = "achievement"
+)
+
+// Notification is a message in a learner's in-app inbox. Key identifies what it
+// is about, such as the day of a reminder, so that each thing is notified once
+// per recipient. A notification is unread until ReadAt is set.
+type Notification struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	Recipient string     `gorm:"not null;uniqueIndex:idx_notifications_recipient_key" json:"-"`
+	Kind      string     `gorm:"not null" json:"kind"`
+	Key       string     `gorm:"not null;uniqueIndex:idx_notifications_recipient_key" json:"-"`
+	Title     string     `gorm:"not null" json:"title"`
+	Body      string     `gorm:"not null;default:''" json:"body"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// How often reminder emails are sent
+const (
+	EmailFrequencyOff    = "off"
+	EmailFrequencyDaily  = "daily"
+	EmailFrequencyWeekly = "weekly"
+)
+
+// NotificationPreference is how a learner wants to be reminded by email.
+// UnsubscribeToken is put in the links of their emails, so that they can turn
+// reminders off without signing in.
+type NotificationPreference struct {
+	Learner          string     `gorm:"primarykey" json:"-"`
+	Email            string     `gorm:"not null;default:''" json:"email"`
+	EmailFrequency   string     `gorm:"not null;default:'off'" json:"email_frequency"`
+	UnsubscribeToken string     `gorm:"not null;uniqueIndex" json:"-"`
+	LastEmailedAt    *time.Time `json:"last_emailed_at"`
+	UpdatedAt        time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName specifies the table name for the NotificationPreference model
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
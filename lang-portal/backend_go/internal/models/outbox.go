@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// OutboxEvent is a study session event recorded in the same transaction as the
+// change it describes, so that it is delivered even if the server stops right
+// after the commit. An event is pending until DeliveredAt or FailedAt is set;
+// FailedAt marks an event given up after too many failed attempts.
+type OutboxEvent struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	Type           string     `gorm:"not null" json:"type"`
+	StudySessionID uint       `gorm:"not null" json:"study_session_id"`
+	Payload        string     `gorm:"not null;default:'{}'" json:"payload"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	LastError      string     `gorm:"not null;default:''" json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	FailedAt       *time.Time `json:"failed_at"`
+	CreatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// PartOfSpeech is a tag of the part-of-speech vocabulary. Words may only be
+// tagged with parts in the vocabulary; the tags of each word are linked
+// through WordPart so that filtering and statistics by part are index lookups.
+type PartOfSpeech struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"not null;uniqueIndex" json:"name"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the PartOfSpeech model
+func (PartOfSpeech) TableName() string {
+	return "parts_of_speech"
+}
+
+// WordPart links a word to one of the parts of speech in its Parts
+type WordPart struct {
+	WordID uint `gorm:"primarykey;autoIncrement:false" json:"word_id"`
+	PartID uint `gorm:"primarykey;autoIncrement:false;index" json:"part_id"`
+}
+
+// TableName specifies the table name for the WordPart model
+func (WordPart) TableName() string {
+	return "word_parts"
+}
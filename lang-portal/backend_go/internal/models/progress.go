@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// Word progress states. A word starts new, is learning once reviewed, moves to
+// review after a run of correct answers and is mastered after a longer run. A
+// wrong answer in review or mastered lapses the word until it is relearned.
+const (
+	ProgressNew      = "new"
+	ProgressLearning = "learning"
+	ProgressReview   = "review"
+	ProgressMastered = "mastered"
+	ProgressLapsed   = "lapsed"
+)
+
+// ProgressStates lists the word progress states in the order words move through them
+var ProgressStates = []string{ProgressNew, ProgressLearning, ProgressReview, ProgressMastered, ProgressLapsed}
+
+// Consecutive correct answers needed for progress transitions
+const (
+	// learningStreak moves a learning word to review
+	learningStreak = 2
+	// masteredStreak moves a word in review to mastered
+	masteredStreak = 5
+	// relearnStreak moves a lapsed word back to review
+	relearnStreak = 2
+)
+
+// WordProgress is the persisted progress state of a word, advanced by every
+// review. Words without a record are new.
+type WordProgress struct {
+	WordID uint   `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	State  string `gorm:"not null;default:'new'" json:"state"`
+	// Streak counts the consecutive correct answers up to the last review
+	Streak int `gorm:"not null;default:0" json:"streak"`
+	// Lapses counts how often the word fell out of review or mastered
+	Lapses  int `gorm:"not null;default:0" json:"lapses"`
+	Reviews int `gorm:"not null;default:0" json:"reviews"`
+	// StateChangedAt is the time of the review that moved the word to its state
+	StateChangedAt *time.Time `json:"state_changed_at,omitempty"`
+	UpdatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+// TableName specifies the table name for the WordProgress model
+func (WordProgress) TableName() string {
+	return "word_progress"
+}
+
+// Advance applies a review with grade made at reviewedAt and reports whether
+// the word changed state. An easy answer moves a new or learning word straight
+// to review.
+func (p *WordProgress) Advance(grade ReviewGrade, reviewedAt time.Time) bool {
+	if p.State == "" {
+		p.State = ProgressNew
+	}
+	correct := grade.Correct()
+	p.Reviews++
+	if correct {
+		p.Streak++
+	} else {
+		p.Streak = 0
+	}
+
+	next := p.State
+	switch p.State {
+	case ProgressNew, ProgressLearning:
+		next = ProgressLearning
+		if grade == GradeEasy || p.Streak >= learningStreak {
+			next = ProgressReview
+		}
+	case ProgressReview:
+		if !correct {
+			next = ProgressLapsed
+		} else if p.Streak >= masteredStreak {
+			next = ProgressMastered
+		}
+	case ProgressMastered:
+		if !correct {
+			next = ProgressLapsed
+		}
+	case ProgressLapsed:
+		if p.Streak >= relearnStreak {
+			next = ProgressReview
+		}
+	}
+
+	if next == p.State {
+		return false
+	}
+	if next == ProgressLapsed {
+		p.Lapses++
+	}
+	p.State = next
+	p.StateChangedAt = &reviewedAt
+	return true
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordProgress_Advance(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	var p WordProgress
+	answer := func(grade ReviewGrade) string {
+		start = start.Add(time.Hour)
+		p.Advance(grade, start)
+		return p.State
+	}
+
+	assert.Equal(t, ProgressLearning, answer(GradeAgain))
+	assert.Equal(t, ProgressLearning, answer(GradeGood))
+	assert.Equal(t, ProgressReview, answer(GradeHard))
+	assert.Equal(t, ProgressReview, answer(GradeGood))
+	assert.Equal(t, ProgressReview, answer(GradeGood))
+	assert.Equal(t, ProgressMastered, answer(GradeGood))
+	assert.Equal(t, start, *p.StateChangedAt)
+	assert.Equal(t, ProgressMastered, answer(GradeEasy))
+
+	// A wrong answer lapses the word until it is relearned
+	assert.Equal(t, ProgressLapsed, answer(GradeAgain))
+	assert.Equal(t, ProgressLapsed, answer(GradeGood))
+	assert.Equal(t, ProgressReview, answer(GradeGood))
+	assert.Equal(t, ProgressLapsed, answer(GradeAgain))
+	assert.Equal(t, 2, p.Lapses)
+	assert.Equal(t, 0, p.Streak)
+	assert.Equal(t, 11, p.Reviews)
+}
+
+func TestWordProgress_AdvanceEasy(t *testing.T) {
+	var p WordProgress
+	changed := p.Advance(GradeEasy, time.Now())
+	assert.True(t, changed)
+	assert.Equal(t, ProgressReview, p.State, "an easy first answer skips learning")
+
+	assert.False(t, p.Advance(GradeGood, time.Now()))
+	assert.Equal(t, 2, p.Streak)
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// PromptTemplate is a version of the prompt of an AI feature, with
+// {{variables}} filled in when it is used. Versions are never changed; editing
+// a prompt adds a version and the latest version is the one in use.
+type PromptTemplate struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_prompt_templates_name_version" json:"name"`
+	Version   int       `gorm:"not null;uniqueIndex:idx_prompt_templates_name_version" json:"version"`
+	Body      string    `gorm:"not null" json:"body"`
+	Author    string    `gorm:"not null;default:''" json:"author"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the PromptTemplate model
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ArchivedWordReview is a review moved out of word_review_items once it is
+// older than the retention period. It keeps the columns of WordReview.
+type ArchivedWordReview struct {
+	ID             uint        `gorm:"primarykey" json:"id"`
+	WordID         uint        `gorm:"not null;index" json:"word_id"`
+	StudySessionID uint        `gorm:"not null;index" json:"study_session_id"`
+	Correct        bool        `gorm:"not null" json:"correct"`
+	Grade          ReviewGrade `gorm:"not null;default:''" json:"grade,omitempty"`
+	AnswerTimeMs   *int        `gorm:"column:answer_time_ms" json:"answer_time_ms,omitempty"`
+	CreatedAt      time.Time   `gorm:"not null" json:"created_at"`
+	ArchivedAt     time.Time   `gorm:"not null" json:"archived_at"`
+}
+
+// TableName specifies the table name for the ArchivedWordReview model
+func (ArchivedWordReview) TableName() string {
+	return "word_review_archive"
+}
+
+// WordReviewMonth totals the archived reviews of a word in one UTC month, so
+// that word statistics need not read the archive
+type WordReviewMonth struct {
+	WordID uint `gorm:"primarykey" json:"word_id"`
+	// Month is the UTC month as YYYY-MM
+	Month          string `gorm:"primarykey" json:"month"`
+	Reviews        int64  `gorm:"not null;default:0" json:"reviews"`
+	CorrectReviews int64  `gorm:"not null;default:0" json:"correct_reviews"`
+	// TimedReviews counts the reviews with an answer time and AnswerTimeMs sums
+	// their answer times
+	TimedReviews int64 `gorm:"not null;default:0" json:"timed_reviews"`
+	AnswerTimeMs int64 `gorm:"column:answer_time_ms;not null;default:0" json:"answer_time_ms"`
+}
+
+// TableName specifies the table name for the WordReviewMonth model
+func (WordReviewMonth) TableName() string {
+	return "word_review_months"
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// ExampleSentence is a sentence showing a word in use. The Japanese text
+// contains the word, or the stem of its conjugated form, so that it can be
+// blanked out for cloze questions.
+type ExampleSentence struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	WordID    uint      `gorm:"not null;index" json:"word_id" validate:"required"`
+	Japanese  string    `gorm:"not null" json:"japanese" validate:"required,max=200"`
+	English   string    `gorm:"not null;default:''" json:"english" validate:"max=400"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	Word      Word      `gorm:"foreignKey:WordID" json:"-" validate:"-"`
+}
+
+// TableName specifies the table name for the ExampleSentence model
+func (ExampleSentence) TableName() string {
+	return "example_sentences"
+}
+
+// Validate validates the ExampleSentence model
+func (s *ExampleSentence) Validate() error {
+	return validate.Struct(s)
+}
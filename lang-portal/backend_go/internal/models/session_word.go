@@ -0,0 +1,16 @@
+package models
+
+// SessionWord is a word at one position of a study session's queue. The queue
+// is generated from the session's group and review order when the session
+// starts, so that activities present the words in the same order across
+// reconnects.
+type SessionWord struct {
+	StudySessionID uint `gorm:"primarykey;autoIncrement:false" json:"study_session_id"`
+	Position       int  `gorm:"primarykey;autoIncrement:false" json:"position"`
+	WordID         uint `gorm:"not null;index" json:"word_id"`
+}
+
+// TableName specifies the table name for the SessionWord model
+func (SessionWord) TableName() string {
+	return "session_words"
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Setting keys
+const (
+	SettingReviewOrder            = "review_order"
+	SettingMasteredMinReviews     = "mastered_min_reviews"
+	SettingMasteredMinSuccessRate = "mastered_min_success_rate"
+	SettingMinSessionWords        = "min_session_words"
+	// SettingCertificateSigningKey holds the generated key signing group certificates
+	SettingCertificateSigningKey = "certificate_signing_key"
+	// SettingLaunchSigningKey holds the generated key signing activity launch tokens
+	SettingLaunchSigningKey = "launch_signing_key"
+)
+
+// Setting is a single persisted preference
+type Setting struct {
+	Key       string    `gorm:"primarykey" json:"key"`
+	Value     string    `gorm:"not null" json:"value"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName specifies the table name for the Setting model
+func (Setting) TableName() string {
+	return "settings"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// Stats that can be exposed through a share token
+const (
+	ShareStatStreak        = "streak"
+	ShareStatWordsLearned  = "words_learned"
+	ShareStatTotalSessions = "total_sessions"
+	ShareStatSuccessRate   = "success_rate"
+)
+
+// StatsShare is an opt-in token granting unauthenticated read access to a
+// chosen subset of study statistics
+type StatsShare struct {
+	ID        uint        `gorm:"primarykey" json:"id"`
+	Token     string      `gorm:"not null;uniqueIndex" json:"token"`
+	Stats     StringSlice `gorm:"type:json;not null" json:"stats"`
+	CreatedAt time.Time   `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the StatsShare model
+func (StatsShare) TableName() string {
+	return "stats_shares"
+}
+
+// Shares reports whether the given stat is exposed by the share
+func (s *StatsShare) Shares(stat string) bool {
+	for _, shared := range s.Stats {
+		if shared == stat {
+			return true
+		}
+	}
+	return false
+}
@@ -1,17 +1,52 @@
 package models
 
 import (
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // StudyActivity represents a specific study activity type
 type StudyActivity struct {
-	ID           uint           `gorm:"primarykey" json:"id"`
-	Name         string         `gorm:"not null;uniqueIndex" json:"name" validate:"required,min=1"`
-	Description  string         `gorm:"not null" json:"description" validate:"required,min=1"`
-	ThumbnailURL string         `gorm:"not null" json:"thumbnail_url" validate:"required,url"`
-	CreatedAt    time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	Sessions     []StudySession `gorm:"foreignKey:StudyActivityID" json:"sessions,omitempty"`
+	ID             uint    `gorm:"primarykey" json:"id"`
+	Name           string  `gorm:"not null;uniqueIndex" json:"name" validate:"required,min=1"`
+	Description    string  `gorm:"not null" json:"description" validate:"required,min=1"`
+	ThumbnailURL   string  `gorm:"not null" json:"thumbnail_url" validate:"required,url|startswith=/"`
+	SettingsSchema RawJSON `gorm:"type:text" json:"settings_schema,omitempty"`
+	// LaunchURL opens the activity for a session; registered apps receive the
+	// session and group IDs as query parameters
+	LaunchURL string `gorm:"not null;default:''" json:"launch_url,omitempty" validate:"omitempty,url"`
+	// Modes lists the study modes the activity supports, such as typing or listening
+	Modes StringSlice `gorm:"type:json;not null" json:"modes"`
+	// Capabilities lists what the client needs to run the activity, such as audio
+	Capabilities StringSlice `gorm:"type:json;not null" json:"capabilities"`
+	// Config is the activity-level configuration, a JSON object passed to the app
+	Config RawJSON `gorm:"type:text" json:"config,omitempty"`
+	// CallbackURL receives the events of the activity's sessions, signed with
+	// CallbackSecret when it is set
+	CallbackURL    string `gorm:"not null;default:''" json:"callback_url,omitempty" validate:"omitempty,url"`
+	CallbackSecret string `gorm:"not null;default:''" json:"-"`
+	// ReviewDedup controls repeated reviews of a word within one session of the
+	// activity; empty records every review
+	ReviewDedup string         `gorm:"not null;default:''" json:"review_dedup,omitempty" validate:"omitempty,oneof=reject upsert"`
+	CreatedAt   time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+	Sessions    []StudySession `gorm:"foreignKey:StudyActivityID" json:"sessions,omitempty"`
+}
+
+// Review deduplication modes of a study activity
+const (
+	// ReviewDedupReject refuses a review of a word already reviewed in the session
+	ReviewDedupReject = "reject"
+	// ReviewDedupUpsert replaces the earlier review of the word in the session
+	ReviewDedupUpsert = "upsert"
+)
+
+// ValidReviewDedup reports whether mode is a known review deduplication mode;
+// empty keeps every review
+func ValidReviewDedup(mode string) bool {
+	return mode == "" || mode == ReviewDedupReject || mode == ReviewDedupUpsert
 }
 
 // TableName specifies the table name for the StudyActivity model
@@ -24,17 +59,69 @@ func (a *StudyActivity) Validate() error {
 	return validate.Struct(a)
 }
 
-// StudySession represents a study session
+// SessionLaunchURL returns the launch URL for a session, with the session and
+// group IDs added as query parameters, or "" when the activity has none
+func (a *StudyActivity) SessionLaunchURL(sessionID, groupID uint) string {
+	if a.LaunchURL == "" {
+		return ""
+	}
+	u, err := url.Parse(a.LaunchURL)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("session_id", strconv.FormatUint(uint64(sessionID), 10))
+	q.Set("group_id", strconv.FormatUint(uint64(groupID), 10))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// StudySession represents a study session. Learner is the actor that started
+// it, such as the name of an API key or the actor header; cohorts aggregate the
+// sessions of their members by it. CompletedAt is set when the learner marks
+// the session complete; ExpiredAt when it was left open without reviews and
+// closed by the expiry job instead. WordLimit caps how many of the group's words, picked
+// by the review order, the session covers; 0 covers all of them.
 type StudySession struct {
 	ID              uint          `gorm:"primarykey" json:"id"`
 	GroupID         uint          `gorm:"not null;index" json:"group_id" validate:"required"`
 	StudyActivityID uint          `gorm:"not null;index" json:"study_activity_id" validate:"required"`
+	ReviewOrder     string        `gorm:"not null;default:''" json:"review_order,omitempty"`
+	WordLimit       int           `gorm:"not null;default:0" json:"word_limit,omitempty"`
+	Learner         string        `gorm:"not null;default:'';index" json:"learner,omitempty"`
+	Client          ClientInfo    `gorm:"embedded" json:"client"`
 	CreatedAt       time.Time     `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	CompletedAt     *time.Time    `json:"completed_at,omitempty"`
+	ExpiredAt       *time.Time    `json:"expired_at,omitempty"`
 	Group           Group         `gorm:"foreignKey:GroupID" json:"group,omitempty"`
 	Activity        StudyActivity `gorm:"foreignKey:StudyActivityID" json:"activity,omitempty"`
 	Reviews         []WordReview  `gorm:"foreignKey:StudySessionID" json:"reviews,omitempty"`
 }
 
+// Limits on the client details recorded with a study session
+const (
+	MaxDeviceLabelLength = 50
+	MaxUserAgentLength   = 255
+)
+
+// ClientInfo describes the device a study session was started on. The device
+// label is chosen by the learner, such as "phone" or "desktop"; the user agent
+// is recorded as sent by the client.
+type ClientInfo struct {
+	DeviceLabel string `gorm:"not null;default:''" json:"device_label"`
+	UserAgent   string `gorm:"not null;default:''" json:"user_agent"`
+}
+
+// NewClientInfo returns the client details of a session with the device label
+// trimmed and the user agent cut to MaxUserAgentLength bytes
+func NewClientInfo(deviceLabel, userAgent string) ClientInfo {
+	userAgent = strings.TrimSpace(userAgent)
+	if len(userAgent) > MaxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:MaxUserAgentLength], "")
+	}
+	return ClientInfo{DeviceLabel: strings.TrimSpace(deviceLabel), UserAgent: userAgent}
+}
+
 // TableName specifies the table name for the StudySession model
 func (StudySession) TableName() string {
 	return "study_sessions"
@@ -56,6 +143,70 @@ func (s *StudySession) GetStudyStats() (totalReviews, correctReviews int) {
 	return
 }
 
+// ReviewGrade is the recall confidence of a review
+type ReviewGrade string
+
+// Review grades, from forgotten to effortless
+const (
+	GradeAgain ReviewGrade = "again"
+	GradeHard  ReviewGrade = "hard"
+	GradeGood  ReviewGrade = "good"
+	GradeEasy  ReviewGrade = "easy"
+)
+
+// Valid reports whether g is empty or one of the review grades
+func (g ReviewGrade) Valid() bool {
+	switch g {
+	case "", GradeAgain, GradeHard, GradeGood, GradeEasy:
+		return true
+	}
+	return false
+}
+
+// Correct reports whether the grade counts as a correct answer: every grade
+// but again does
+func (g ReviewGrade) Correct() bool {
+	return g != GradeAgain
+}
+
+// EffectiveGrade returns the review's grade, or good or again for an ungraded
+// correct or wrong answer
+func (r *WordReview) EffectiveGrade() ReviewGrade {
+	if r.Grade != "" {
+		return r.Grade
+	}
+	if r.Correct {
+		return GradeGood
+	}
+	return GradeAgain
+}
+
+// MaxAnswerTimeMs is the longest accepted answer time; a longer pause says
+// nothing about how hard the word was
+const MaxAnswerTimeMs = 10 * 60 * 1000
+
+// ValidAnswerTime reports whether an answer time is unset or within 0 and MaxAnswerTimeMs
+func ValidAnswerTime(ms *int) bool {
+	return ms == nil || (*ms >= 0 && *ms <= MaxAnswerTimeMs)
+}
+
+// AverageAnswerTimeMs returns the mean answer time of the session's timed
+// reviews, or nil when none was timed
+func (s *StudySession) AverageAnswerTimeMs() *float64 {
+	total, timed := 0, 0
+	for _, review := range s.Reviews {
+		if review.AnswerTimeMs != nil {
+			total += *review.AnswerTimeMs
+			timed++
+		}
+	}
+	if timed == 0 {
+		return nil
+	}
+	avg := float64(total) / float64(timed)
+	return &avg
+}
+
 // GetSuccessRate returns the success rate for the session
 func (s *StudySession) GetSuccessRate() float64 {
 	totalReviews, correctReviews := s.GetStudyStats()
@@ -65,15 +216,18 @@ func (s *StudySession) GetSuccessRate() float64 {
 	return float64(correctReviews) / float64(totalReviews) * 100
 }
 
-// WordReview represents a word review in a study session
+// WordReview represents a word review in a study session. Grade and AnswerTimeMs
+// are only set by activities that ask for a confidence grade or time the answer.
 type WordReview struct {
 	ID             uint         `gorm:"primarykey" json:"id"`
 	WordID         uint         `gorm:"not null;index" json:"word_id" validate:"required"`
 	StudySessionID uint         `gorm:"not null;index" json:"study_session_id" validate:"required"`
-	Correct        bool         `gorm:"not null" json:"correct" validate:"required"`
+	Correct        bool         `gorm:"not null" json:"correct"`
+	Grade          ReviewGrade  `gorm:"not null;default:''" json:"grade,omitempty"`
+	AnswerTimeMs   *int         `gorm:"column:answer_time_ms" json:"answer_time_ms,omitempty"`
 	CreatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	Word           Word         `gorm:"foreignKey:WordID" json:"word,omitempty"`
-	StudySession   StudySession `gorm:"foreignKey:StudySessionID" json:"study_session,omitempty"`
+	Word           Word         `gorm:"foreignKey:WordID" json:"word,omitempty" validate:"-"`
+	StudySession   StudySession `gorm:"foreignKey:StudySessionID" json:"study_session,omitempty" validate:"-"`
 }
 
 // TableName specifies the table name for the WordReview model
@@ -85,3 +239,18 @@ func (WordReview) TableName() string {
 func (r *WordReview) Validate() error {
 	return validate.Struct(r)
 }
+
+// ActivityReviewBatch records a batch of reviews submitted by an external
+// activity, so that a replayed batch is rejected
+type ActivityReviewBatch struct {
+	ID             uint      `gorm:"primarykey" json:"-"`
+	StudySessionID uint      `gorm:"not null;uniqueIndex:idx_activity_review_batches_session_batch,priority:1" json:"study_session_id"`
+	BatchID        string    `gorm:"not null;uniqueIndex:idx_activity_review_batches_session_batch,priority:2" json:"batch_id"`
+	ReviewCount    int       `gorm:"not null;default:0" json:"review_count"`
+	CreatedAt      time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the ActivityReviewBatch model
+func (ActivityReviewBatch) TableName() string {
+	return "activity_review_batches"
+}
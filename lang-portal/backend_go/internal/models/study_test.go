@@ -48,6 +48,15 @@ func TestStudyActivity_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "site-relative thumbnail path",
+			activity: StudyActivity{
+				Name:         "Test Activity",
+				Description:  "Test Description",
+				ThumbnailURL: "/images/reading.png",
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -62,6 +71,13 @@ func TestStudyActivity_Validate(t *testing.T) {
 	}
 }
 
+func TestStudyActivity_SessionLaunchURL(t *testing.T) {
+	activity := StudyActivity{LaunchURL: "https://typing.example.com/launch?lang=ja"}
+	assert.Equal(t, "https://typing.example.com/launch?group_id=4&lang=ja&session_id=12", activity.SessionLaunchURL(12, 4))
+
+	assert.Empty(t, (&StudyActivity{}).SessionLaunchURL(12, 4))
+}
+
 func TestStudySession_Validate(t *testing.T) {
 	validGroup := Group{Name: "Test Group"}
 	validActivity := StudyActivity{Name: "Test Activity", Description: "desc", ThumbnailURL: "https://example.com/img.jpg"}
@@ -165,6 +181,38 @@ func TestStudySession_GetStudyStats(t *testing.T) {
 	}
 }
 
+func TestStudySession_AverageAnswerTimeMs(t *testing.T) {
+	fast, slow := 500, 1500
+	session := StudySession{Reviews: []WordReview{{Correct: true}}}
+	assert.Nil(t, session.AverageAnswerTimeMs())
+
+	session.Reviews = append(session.Reviews,
+		WordReview{Correct: true, AnswerTimeMs: &fast},
+		WordReview{Correct: false, AnswerTimeMs: &slow},
+	)
+	avg := session.AverageAnswerTimeMs()
+	if assert.NotNil(t, avg) {
+		assert.Equal(t, 1000.0, *avg)
+	}
+}
+
+func TestWordReview_EffectiveGrade(t *testing.T) {
+	assert.Equal(t, GradeGood, (&WordReview{Correct: true}).EffectiveGrade())
+	assert.Equal(t, GradeAgain, (&WordReview{Correct: false}).EffectiveGrade())
+	assert.Equal(t, GradeEasy, (&WordReview{Correct: true, Grade: GradeEasy}).EffectiveGrade())
+	assert.True(t, GradeHard.Correct())
+	assert.False(t, GradeAgain.Correct())
+	assert.False(t, ReviewGrade("meh").Valid())
+}
+
+func TestValidAnswerTime(t *testing.T) {
+	zero, negative, tooLong := 0, -1, MaxAnswerTimeMs+1
+	assert.True(t, ValidAnswerTime(nil))
+	assert.True(t, ValidAnswerTime(&zero))
+	assert.False(t, ValidAnswerTime(&negative))
+	assert.False(t, ValidAnswerTime(&tooLong))
+}
+
 func TestStudySession_GetSuccessRate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -223,7 +271,7 @@ func TestStudySession_GetSuccessRate(t *testing.T) {
 }
 
 func TestWordReview_Validate(t *testing.T) {
-	validWord := Word{Japanese: "テスト", Romaji: "tesuto", English: "test", Parts: StringSlice{"noun"}}
+	validWord := Word{Term: "テスト", Reading: "tesuto", English: "test", Parts: StringSlice{"noun"}}
 	validSession := StudySession{GroupID: 1, StudyActivityID: 1, Group: Group{Name: "Test Group"}, Activity: StudyActivity{Name: "Test Activity", Description: "desc", ThumbnailURL: "https://example.com/img.jpg"}}
 	tests := []struct {
 		name    string
@@ -241,6 +289,15 @@ func TestWordReview_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "wrong answer without loaded associations",
+			review: WordReview{
+				WordID:         1,
+				StudySessionID: 1,
+				Correct:        false,
+			},
+			wantErr: false,
+		},
 		{
 			name: "zero word ID",
 			review: WordReview{
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Tutor message roles
+const (
+	MessageRoleUser      = "user"
+	MessageRoleAssistant = "assistant"
+)
+
+// Conversation is a chat with the tutor. Context is the learner's weak words
+// when the conversation started, which the tutor is told about on every turn.
+type Conversation struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Title     string    `gorm:"not null;default:''" json:"title"`
+	User      string    `gorm:"not null;index" json:"user"`
+	Context   string    `gorm:"not null;default:''" json:"context"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Messages  []Message `gorm:"foreignKey:ConversationID" json:"messages,omitempty"`
+}
+
+// TableName specifies the table name for the Conversation model
+func (Conversation) TableName() string {
+	return "conversations"
+}
+
+// Message is a turn of a tutor conversation. Token counts are set on the
+// tutor's replies.
+type Message struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	ConversationID   uint      `gorm:"not null;index" json:"conversation_id"`
+	Role             string    `gorm:"not null" json:"role"`
+	Content          string    `gorm:"not null" json:"content"`
+	PromptTokens     int       `gorm:"not null;default:0" json:"prompt_tokens,omitempty"`
+	CompletionTokens int       `gorm:"not null;default:0" json:"completion_tokens,omitempty"`
+	CreatedAt        time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the Message model
+func (Message) TableName() string {
+	return "conversation_messages"
+}
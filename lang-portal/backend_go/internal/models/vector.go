@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// WordVector is the embedding of a word by an embedding model. Source is the
+// text that was embedded; a vector whose source no longer matches the word is
+// stale.
+type WordVector struct {
+	WordID     uint      `gorm:"primaryKey;autoIncrement:false"`
+	Model      string    `gorm:"not null"`
+	Source     string    `gorm:"not null"`
+	Dimensions int       `gorm:"not null"`
+	Vector     []byte    `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for the WordVector model
+func (WordVector) TableName() string {
+	return "word_vectors"
+}
@@ -34,18 +34,90 @@ func (s *StringSlice) Scan(value interface{}) error {
 	return nil
 }
 
-// Word represents a vocabulary word
+// Word represents a vocabulary word. LastReviewedAt, NextDueAt and AccuracyEWMA are
+// maintained on every review write so that due/stale/recent queries are indexed
+// range scans and difficulty ordering needs no review aggregation.
+// Version counts updates of the word's content, so that clients can detect that
+// it changed since they read it. FrequencyRank is the rank of the word in the
+// imported frequency list, nil when the list does not contain it. German is
+// the German meaning, empty when the word has none. Language is the target
+// language of the word; Term and Reading hold its term and romanized reading,
+// written in the ISO 15924 scripts Script and ReadingScript. API v1 names them
+// japanese and romaji.
 type Word struct {
-	ID        uint         `gorm:"primarykey" json:"id"`
-	Japanese  string       `gorm:"not null;index" json:"japanese" validate:"required,min=1"`
-	Romaji    string       `gorm:"not null" json:"romaji" validate:"required,min=1"`
-	English   string       `gorm:"not null" json:"english" validate:"required,min=1"`
-	Parts     StringSlice  `gorm:"type:json;not null" json:"parts" validate:"required,min=1"`
-	CreatedAt time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	Groups    []Group      `gorm:"many2many:word_groups;" json:"groups,omitempty"`
-	Reviews   []WordReview `gorm:"foreignKey:WordID" json:"reviews,omitempty"`
+	ID             uint         `gorm:"primarykey" json:"id"`
+	Language       string       `gorm:"not null;default:'ja';index" json:"language"`
+	Term           string       `gorm:"not null;index" json:"japanese" validate:"required,min=1"`
+	Reading        string       `gorm:"not null" json:"romaji" validate:"required,min=1"`
+	Script         string       `gorm:"not null;default:''" json:"script"`
+	ReadingScript  string       `gorm:"not null;default:'Latn'" json:"reading_script"`
+	English        string       `gorm:"not null" json:"english" validate:"required,min=1"`
+	German         string       `gorm:"not null;default:''" json:"german"`
+	Parts          StringSlice  `gorm:"type:json;not null" json:"parts" validate:"required,min=1"`
+	CreatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Version        uint         `gorm:"not null;default:1" json:"version"`
+	LastReviewedAt *time.Time   `gorm:"index" json:"last_reviewed_at"`
+	NextDueAt      *time.Time   `gorm:"index" json:"next_due_at"`
+	AccuracyEWMA   *float64     `gorm:"column:accuracy_ewma" json:"accuracy_ewma"`
+	FrequencyRank  *int         `gorm:"index" json:"frequency_rank"`
+	Groups         []Group      `gorm:"many2many:word_groups;" json:"groups,omitempty"`
+	Kanji          []Kanji      `gorm:"many2many:word_kanji;" json:"kanji,omitempty"`
+	Reviews        []WordReview `gorm:"foreignKey:WordID" json:"reviews,omitempty"`
+	// Progress is nil for new words
+	Progress *WordProgress `gorm:"foreignKey:WordID" json:"progress,omitempty"`
+	// Stats is nil for words that were never reviewed
+	Stats *WordStats `gorm:"foreignKey:WordID" json:"stats,omitempty"`
 }
 
+// Languages word meanings are kept in, as selected with the lang parameter
+const (
+	LanguageEnglish = "en"
+	LanguageGerman  = "de"
+)
+
+// MeaningLanguages lists the languages word meanings are kept in, English first
+var MeaningLanguages = []string{LanguageEnglish, LanguageGerman}
+
+// Meaning returns the meaning of a word in lang from its English and German
+// meanings, and the language of the returned meaning: English when the word
+// has no meaning in lang.
+func Meaning(english, german, lang string) (meaning, language string) {
+	if lang == LanguageGerman && german != "" {
+		return german, LanguageGerman
+	}
+	return english, LanguageEnglish
+}
+
+// ProgressState returns the progress state of the word, new when it has no
+// progress record. Progress must be loaded.
+func (w *Word) ProgressState() string {
+	if w.Progress == nil {
+		return ProgressNew
+	}
+	return w.Progress.State
+}
+
+// ReviewCounts returns the correct and wrong reviews of the word, archived
+// ones included. Stats must be loaded.
+func (w *Word) ReviewCounts() (correct, wrong int64) {
+	if w.Stats == nil {
+		return 0, 0
+	}
+	return w.Stats.CorrectCount, w.Stats.WrongCount
+}
+
+// Review scheduling intervals
+const (
+	minReviewInterval   = 24 * time.Hour
+	easyReviewInterval  = 4 * 24 * time.Hour
+	maxReviewInterval   = 180 * 24 * time.Hour
+	retryReviewInterval = 10 * time.Minute
+)
+
+// accuracyEWMAWeight is the weight of the latest answer in AccuracyEWMA
+const accuracyEWMAWeight = 0.3
+
 // TableName specifies the table name for the Word model
 func (Word) TableName() string {
 	return "words"
@@ -79,3 +151,61 @@ func (w *Word) GetSuccessRate() float64 {
 	}
 	return float64(correctCount) / float64(total) * 100
 }
+
+// ScheduleReview records an ungraded review at reviewedAt and computes the next
+// due time: a correct answer is scheduled as good, a wrong one as again.
+func (w *Word) ScheduleReview(correct bool, reviewedAt time.Time) {
+	grade := GradeAgain
+	if correct {
+		grade = GradeGood
+	}
+	w.ScheduleGradedReview(grade, reviewedAt)
+}
+
+// ScheduleGradedReview records a review at reviewedAt and computes the next due
+// time from its grade. Good doubles the previous interval and hard grows it by a
+// fifth (at least one day either way), easy triples it (at least four days), and
+// again makes the word due shortly. Intervals are capped at 180 days.
+func (w *Word) ScheduleGradedReview(grade ReviewGrade, reviewedAt time.Time) {
+	interval := retryReviewInterval
+	if grade != GradeAgain {
+		var previous time.Duration
+		if w.LastReviewedAt != nil && w.NextDueAt != nil {
+			previous = w.NextDueAt.Sub(*w.LastReviewedAt)
+		}
+
+		floor, factor := minReviewInterval, 2.0
+		switch grade {
+		case GradeHard:
+			factor = 1.2
+		case GradeEasy:
+			floor, factor = easyReviewInterval, 3
+		}
+		interval = time.Duration(float64(previous) * factor)
+		if interval < floor {
+			interval = floor
+		}
+		if interval > maxReviewInterval {
+			interval = maxReviewInterval
+		}
+	}
+
+	nextDue := reviewedAt.Add(interval)
+	w.LastReviewedAt = &reviewedAt
+	w.NextDueAt = &nextDue
+}
+
+// RecordAccuracy folds an answer into the exponentially weighted moving average
+// of the word's accuracy. The first answer sets the average to 0 or 1.
+func (w *Word) RecordAccuracy(correct bool) {
+	score := 0.0
+	if correct {
+		score = 1
+	}
+	if w.AccuracyEWMA == nil {
+		w.AccuracyEWMA = &score
+		return
+	}
+	ewma := accuracyEWMAWeight*score + (1-accuracyEWMAWeight)*(*w.AccuracyEWMA)
+	w.AccuracyEWMA = &ewma
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// WordStats are the review totals of a word, archived reviews included, kept
+// up to date as reviews are recorded so that word lists need not count
+// reviews. Words without a record have never been reviewed.
+type WordStats struct {
+	WordID         uint       `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	CorrectCount   int64      `gorm:"not null;default:0" json:"correct_count"`
+	WrongCount     int64      `gorm:"not null;default:0" json:"wrong_count"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	// SuccessRate is the share of correct reviews, from 0 to 1
+	SuccessRate float64   `gorm:"not null;default:0" json:"success_rate"`
+	UpdatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+// TableName specifies the table name for the WordStats model
+func (WordStats) TableName() string {
+	return "word_stats"
+}
+
+// Record adds a review made at reviewedAt to the totals
+func (s *WordStats) Record(correct bool, reviewedAt time.Time) {
+	if correct {
+		s.CorrectCount++
+	} else {
+		s.WrongCount++
+	}
+	if s.LastReviewedAt == nil || reviewedAt.After(*s.LastReviewedAt) {
+		s.LastReviewedAt = &reviewedAt
+	}
+	s.SuccessRate = float64(s.CorrectCount) / float64(s.CorrectCount+s.WrongCount)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,50 +17,50 @@ func TestWord_Validate(t *testing.T) {
 		{
 			name: "valid word",
 			word: Word{
-				Japanese: "テスト",
-				Romaji:   "tesuto",
-				English:  "test",
-				Parts:    StringSlice{"noun"},
+				Term:    "テスト",
+				Reading: "tesuto",
+				English: "test",
+				Parts:   StringSlice{"noun"},
 			},
 			wantErr: false,
 		},
 		{
 			name: "empty japanese",
 			word: Word{
-				Japanese: "",
-				Romaji:   "tesuto",
-				English:  "test",
-				Parts:    StringSlice{"noun"},
+				Term:    "",
+				Reading: "tesuto",
+				English: "test",
+				Parts:   StringSlice{"noun"},
 			},
 			wantErr: true,
 		},
 		{
 			name: "empty romaji",
 			word: Word{
-				Japanese: "テスト",
-				Romaji:   "",
-				English:  "test",
-				Parts:    StringSlice{"noun"},
+				Term:    "テスト",
+				Reading: "",
+				English: "test",
+				Parts:   StringSlice{"noun"},
 			},
 			wantErr: true,
 		},
 		{
 			name: "empty english",
 			word: Word{
-				Japanese: "テスト",
-				Romaji:   "tesuto",
-				English:  "",
-				Parts:    StringSlice{"noun"},
+				Term:    "テスト",
+				Reading: "tesuto",
+				English: "",
+				Parts:   StringSlice{"noun"},
 			},
 			wantErr: true,
 		},
 		{
 			name: "empty parts",
 			word: Word{
-				Japanese: "テスト",
-				Romaji:   "tesuto",
-				English:  "test",
-				Parts:    StringSlice{},
+				Term:    "テスト",
+				Reading: "tesuto",
+				English: "test",
+				Parts:   StringSlice{},
 			},
 			wantErr: true,
 		},
@@ -236,3 +237,89 @@ func TestStringSlice_Value_Scan(t *testing.T) {
 		})
 	}
 }
+
+func TestWord_ScheduleReview(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	lastReviewed := now.Add(-4 * 24 * time.Hour)
+	lastDue := now.Add(-1 * 24 * time.Hour) // previous interval: 3 days
+
+	tests := []struct {
+		name         string
+		word         Word
+		correct      bool
+		wantInterval time.Duration
+	}{
+		{
+			name:         "first correct review",
+			word:         Word{},
+			correct:      true,
+			wantInterval: 24 * time.Hour,
+		},
+		{
+			name:         "correct review doubles interval",
+			word:         Word{LastReviewedAt: &lastReviewed, NextDueAt: &lastDue},
+			correct:      true,
+			wantInterval: 6 * 24 * time.Hour,
+		},
+		{
+			name:         "wrong review resets interval",
+			word:         Word{LastReviewedAt: &lastReviewed, NextDueAt: &lastDue},
+			correct:      false,
+			wantInterval: 10 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.word.ScheduleReview(tt.correct, now)
+			require.NotNil(t, tt.word.LastReviewedAt)
+			require.NotNil(t, tt.word.NextDueAt)
+			assert.Equal(t, now, *tt.word.LastReviewedAt)
+			assert.Equal(t, now.Add(tt.wantInterval), *tt.word.NextDueAt)
+		})
+	}
+}
+
+func TestWord_ScheduleGradedReview(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	lastReviewed := now.Add(-12 * 24 * time.Hour)
+	lastDue := now.Add(-2 * 24 * time.Hour) // previous interval: 10 days
+	longReviewed := now.Add(-100 * 24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		word         Word
+		grade        ReviewGrade
+		wantInterval time.Duration
+	}{
+		{"first hard review", Word{}, GradeHard, 24 * time.Hour},
+		{"first easy review", Word{}, GradeEasy, 4 * 24 * time.Hour},
+		{"again", Word{LastReviewedAt: &lastReviewed, NextDueAt: &lastDue}, GradeAgain, 10 * time.Minute},
+		{"hard grows interval by a fifth", Word{LastReviewedAt: &lastReviewed, NextDueAt: &lastDue}, GradeHard, 12 * 24 * time.Hour},
+		{"good doubles interval", Word{LastReviewedAt: &lastReviewed, NextDueAt: &lastDue}, GradeGood, 20 * 24 * time.Hour},
+		{"easy triples interval", Word{LastReviewedAt: &lastReviewed, NextDueAt: &lastDue}, GradeEasy, 30 * 24 * time.Hour},
+		{"easy is capped", Word{LastReviewedAt: &longReviewed, NextDueAt: &now}, GradeEasy, 180 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.word.ScheduleGradedReview(tt.grade, now)
+			require.NotNil(t, tt.word.NextDueAt)
+			assert.Equal(t, now.Add(tt.wantInterval), *tt.word.NextDueAt)
+		})
+	}
+}
+
+func TestWord_RecordAccuracy(t *testing.T) {
+	word := &Word{}
+
+	word.RecordAccuracy(false)
+	assert.NotNil(t, word.AccuracyEWMA)
+	assert.Equal(t, 0.0, *word.AccuracyEWMA)
+
+	word.RecordAccuracy(true)
+	assert.InDelta(t, 0.3, *word.AccuracyEWMA, 1e-9)
+
+	word.RecordAccuracy(true)
+	assert.InDelta(t, 0.51, *word.AccuracyEWMA, 1e-9)
+}
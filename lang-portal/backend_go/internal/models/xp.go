@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Sources of experience points
+const (
+	// XPSourceReview is a correct word review; SourceID is the review
+	XPSourceReview = "review"
+	// XPSourceSession is a completed study session; SourceID is the session
+	XPSourceSession = "session"
+	// XPSourceGoal is a group goal met by completing a session; SourceID is the
+	// group
+	XPSourceGoal = "goal"
+)
+
+// LearnerXP is the experience a learner, the actor their study sessions are
+// recorded under, has earned and the level it puts them at
+type LearnerXP struct {
+	Learner   string    `gorm:"primarykey" json:"learner"`
+	XP        int64     `gorm:"not null;default:0" json:"xp"`
+	Level     int       `gorm:"not null;default:1" json:"level"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName specifies the table name for the LearnerXP model
+func (LearnerXP) TableName() string {
+	return "learner_xp"
+}
+
+// XPAward records the experience given to a learner for one thing. Each thing
+// is rewarded once per learner. StudySessionID is the session it was earned in.
+type XPAward struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	Learner        string    `gorm:"not null;uniqueIndex:idx_xp_awards_learner_source" json:"learner"`
+	Source         string    `gorm:"not null;uniqueIndex:idx_xp_awards_learner_source" json:"source"`
+	SourceID       uint      `gorm:"not null;uniqueIndex:idx_xp_awards_learner_source" json:"source_id"`
+	StudySessionID *uint     `gorm:"index" json:"study_session_id,omitempty"`
+	Amount         int64     `gorm:"not null" json:"amount"`
+	CreatedAt      time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for the XPAward model
+func (XPAward) TableName() string {
+	return "xp_awards"
+}
@@ -0,0 +1,179 @@
+// Package moderation checks text written by the language model before it is
+// shown to learners or stored: that it is within length, in the expected
+// language, and free of profanity and personal data.
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Languages text may be expected in
+const (
+	// Japanese text is written mostly in Japanese script, with some kana
+	Japanese = "japanese"
+	// English text is written mostly in Latin letters
+	English = "english"
+	// Mixed text is English and Japanese, such as a tutor's reply
+	Mixed = "mixed"
+)
+
+// Names of the rules a text can break
+const (
+	RuleEmpty     = "empty"
+	RuleLength    = "length"
+	RuleLanguage  = "language"
+	RuleProfanity = "profanity"
+	RulePII       = "pii"
+)
+
+// Share of the letters of a text that must be in the expected script
+const (
+	japaneseShare = 0.5
+	scriptShare   = 0.9
+)
+
+// Rejection is the rule a text broke and why
+type Rejection struct {
+	Rule   string
+	Reason string
+}
+
+func (r *Rejection) Error() string {
+	return r.Rule + ": " + r.Reason
+}
+
+// Policy is what a text must satisfy. MaxLength is in characters; zero does
+// not limit the length.
+type Policy struct {
+	Language  string
+	MaxLength int
+}
+
+// Check returns a *Rejection for the first rule of policy that text breaks,
+// or nil when it passes them all
+func Check(text string, policy Policy) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return &Rejection{Rule: RuleEmpty, Reason: "the text is empty"}
+	}
+	if policy.MaxLength > 0 && utf8.RuneCountInString(text) > policy.MaxLength {
+		return &Rejection{Rule: RuleLength, Reason: "the text is longer than the limit"}
+	}
+	if reason := language(text, policy.Language); reason != "" {
+		return &Rejection{Rule: RuleLanguage, Reason: reason}
+	}
+	if word := profanity(text); word != "" {
+		return &Rejection{Rule: RuleProfanity, Reason: "the text contains " + quote(word)}
+	}
+	if kind := personalData(text); kind != "" {
+		return &Rejection{Rule: RulePII, Reason: "the text contains " + kind}
+	}
+	return nil
+}
+
+// language returns why text is not in the expected language, or "" when it is
+func language(text, expected string) string {
+	var letters, japanese, kana, latin int
+	for _, r := range text {
+		switch {
+		case isKana(r):
+			kana++
+			japanese++
+		case unicode.Is(unicode.Han, r):
+			japanese++
+		case r < utf8.RuneSelf && unicode.IsLetter(r):
+			latin++
+		case !unicode.IsLetter(r):
+			continue
+		}
+		letters++
+	}
+	if letters == 0 {
+		return "the text has no letters"
+	}
+
+	switch expected {
+	case Japanese:
+		// Chinese shares the kanji but not the kana
+		if float64(japanese) < japaneseShare*float64(letters) || kana == 0 {
+			return "the text is not Japanese"
+		}
+	case English:
+		if float64(latin) < scriptShare*float64(letters) {
+			return "the text is not English"
+		}
+	case Mixed:
+		if float64(latin+japanese) < scriptShare*float64(letters) {
+			return "the text is not in English or Japanese"
+		}
+	}
+	return ""
+}
+
+func isKana(r rune) bool {
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// profaneWords are matched as whole words, ignoring case
+var profaneWords = regexp.MustCompile(`(?i)\b(fuck\w*|motherfuck\w*|shit\w*|bullshit|bitch\w*|cunt\w*|asshole\w*|dickhead\w*|bastard\w*|whore\w*|slut\w*|wank\w*)\b`)
+
+// profaneJapanese are matched anywhere, as Japanese is not written with spaces
+var profaneJapanese = []string{"死ね", "くたばれ", "ちんこ", "ちんぽ", "まんこ", "きちがい", "キチガイ"}
+
+// profanity returns the first profane word in text, or ""
+func profanity(text string) string {
+	if word := profaneWords.FindString(text); word != "" {
+		return word
+	}
+	for _, word := range profaneJapanese {
+		if strings.Contains(text, word) {
+			return word
+		}
+	}
+	return ""
+}
+
+// Personal data a text must not contain
+var (
+	emailPattern = regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)*\.[a-zA-Z]{2,}`)
+	// Phone numbers of 10 or more digits, optionally with a country code and
+	// separators, such as 090-1234-5678 or +81 3 1234 5678
+	phonePattern = regexp.MustCompile(`(\+\d{1,3}[\s-]?)?\(?\d{2,4}\)?[\s-]?\d{2,4}[\s-]?\d{3,4}`)
+	// Card numbers of 13 to 19 digits, optionally in groups
+	cardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+// personalData returns the kind of personal data in text, or ""
+func personalData(text string) string {
+	if emailPattern.MatchString(text) {
+		return "an email address"
+	}
+	if cardPattern.MatchString(text) {
+		return "a card number"
+	}
+	for _, match := range phonePattern.FindAllString(text, -1) {
+		if digits(match) >= 10 {
+			return "a phone number"
+		}
+	}
+	return ""
+}
+
+// digits counts the digits in s
+func digits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// quote quotes a word in a rejection reason
+func quote(word string) string {
+	return `"` + word + `"`
+}
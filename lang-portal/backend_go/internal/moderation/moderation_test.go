@@ -0,0 +1,46 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	japanese := Policy{Language: Japanese, MaxLength: 40}
+	english := Policy{Language: English}
+	mixed := Policy{Language: Mixed}
+
+	cases := []struct {
+		text   string
+		policy Policy
+		rule   string
+	}{
+		{"毎朝パンを食べます。", japanese, ""},
+		{"I eat bread every morning.", english, ""},
+		{"食べる (taberu) means \"to eat\". Try: 何を食べますか？", mixed, ""},
+		{"Call me at 3pm, I'll be at gate 12.", english, ""},
+		{"   ", japanese, RuleEmpty},
+		{"毎朝パンを食べます。毎朝パンを食べます。", Policy{Language: Japanese, MaxLength: 15}, RuleLength},
+		{"I eat bread every morning.", japanese, RuleLanguage},
+		{"我每天早上吃面包。", japanese, RuleLanguage},
+		{"Я ем хлеб каждое утро.", english, RuleLanguage},
+		{"저는 매일 아침 빵을 먹어요.", mixed, RuleLanguage},
+		{"What the FUCK is this word?", english, RuleProfanity},
+		{"お前なんか死ね。", japanese, RuleProfanity},
+		{"Write to taro@example.com for help.", english, RulePII},
+		{"Ring 090-1234-5678 tonight.", english, RulePII},
+		{"My card is 4111 1111 1111 1111.", english, RulePII},
+	}
+	for _, tc := range cases {
+		err := Check(tc.text, tc.policy)
+		if tc.rule == "" {
+			assert.NoError(t, err, tc.text)
+			continue
+		}
+		var rejection *Rejection
+		require.ErrorAs(t, err, &rejection, tc.text)
+		assert.Equal(t, tc.rule, rejection.Rule, tc.text)
+	}
+}
@@ -0,0 +1,37 @@
+// Package ocr reads Japanese text from images, such as photos of manga pages or
+// menus, either with a local Tesseract install or with the Google Cloud Vision
+// API.
+package ocr
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrUnavailable is returned when the text recognizer cannot be reached or run
+var ErrUnavailable = errors.New("text recognition unavailable")
+
+// Reader reads the text in images
+type Reader interface {
+	// Read returns the text in image, a picture in the format named by
+	// contentType such as image/png or image/jpeg
+	Read(ctx context.Context, image []byte, contentType string) (string, error)
+}
+
+// imageTypes are the image formats both readers accept
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/bmp":  true,
+	"image/tiff": true,
+}
+
+// Supported reports whether images of contentType can be read, ignoring
+// parameters
+func Supported(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return imageTypes[strings.ToLower(strings.TrimSpace(mediaType))]
+}
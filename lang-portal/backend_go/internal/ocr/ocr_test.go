@@ -0,0 +1,58 @@
+package ocr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupported(t *testing.T) {
+	assert.True(t, Supported("image/png"))
+	assert.True(t, Supported("IMAGE/JPEG; charset=binary"))
+	assert.False(t, Supported("application/pdf"))
+}
+
+func TestVision_Read(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("key"))
+		var body visionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.Requests, 1)
+		assert.Equal(t, "PNG", string(body.Requests[0].Image.Content))
+		assert.Equal(t, []string{"ja"}, body.Requests[0].ImageContext.LanguageHints)
+		w.Write([]byte(`{"responses":[{"fullTextAnnotation":{"text":"ラーメン\n900円"}}]}`))
+	}))
+	defer server.Close()
+
+	vision := &Vision{URL: server.URL, APIKey: "secret"}
+	text, err := vision.Read(context.Background(), []byte("PNG"), "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, "ラーメン\n900円", text)
+
+	_, err = vision.Read(context.Background(), []byte("PDF"), "application/pdf")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnavailable)
+
+	server.Close()
+	_, err = vision.Read(context.Background(), []byte("PNG"), "image/png")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestTesseract_Read(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "tesseract")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$4\"\ncat\n"), 0o755))
+
+	text, err := (&Tesseract{Path: script}).Read(context.Background(), []byte("お茶"), "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, "jpn+jpn_vert\nお茶", text)
+
+	_, err = (&Tesseract{Path: filepath.Join(t.TempDir(), "missing")}).Read(context.Background(), nil, "image/jpeg")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
@@ -0,0 +1,49 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Tesseract defaults
+const (
+	// DefaultTesseractPath is the Tesseract program looked up in PATH
+	DefaultTesseractPath = "tesseract"
+	// DefaultTesseractLanguages reads horizontal and vertical Japanese
+	DefaultTesseractLanguages = "jpn+jpn_vert"
+)
+
+// Tesseract reads images with a local Tesseract install, which needs the
+// trained data of its languages
+type Tesseract struct {
+	// Path is the Tesseract program, DefaultTesseractPath when empty
+	Path string
+	// Languages are the Tesseract languages, DefaultTesseractLanguages when empty
+	Languages string
+}
+
+// Read implements Reader. The image is passed on standard input.
+func (t *Tesseract) Read(ctx context.Context, image []byte, contentType string) (string, error) {
+	if !Supported(contentType) {
+		return "", fmt.Errorf("unsupported image type %q", contentType)
+	}
+	path, languages := t.Path, t.Languages
+	if path == "" {
+		path = DefaultTesseractPath
+	}
+	if languages == "" {
+		languages = DefaultTesseractLanguages
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "stdin", "stdout", "-l", languages)
+	cmd.Stdin = bytes.NewReader(image)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %v: %s", ErrUnavailable, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,106 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// DefaultVisionURL is the image annotation endpoint of the Google Cloud Vision API
+const DefaultVisionURL = "https://vision.googleapis.com/v1/images:annotate"
+
+// maxVisionResponse caps the size of an annotation response
+const maxVisionResponse = 8 << 20
+
+// Vision reads images with the document text detection of the Google Cloud
+// Vision API
+type Vision struct {
+	// URL is the annotation endpoint, DefaultVisionURL when empty
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+type visionRequest struct {
+	Requests []visionImageRequest `json:"requests"`
+}
+
+type visionImageRequest struct {
+	Image struct {
+		Content []byte `json:"content"`
+	} `json:"image"`
+	Features     []visionFeature `json:"features"`
+	ImageContext struct {
+		LanguageHints []string `json:"languageHints"`
+	} `json:"imageContext"`
+}
+
+type visionFeature struct {
+	Type string `json:"type"`
+}
+
+type visionResponse struct {
+	Responses []struct {
+		FullTextAnnotation struct {
+			Text string `json:"text"`
+		} `json:"fullTextAnnotation"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"responses"`
+}
+
+// Read implements Reader
+func (v *Vision) Read(ctx context.Context, image []byte, contentType string) (string, error) {
+	if !Supported(contentType) {
+		return "", fmt.Errorf("unsupported image type %q", contentType)
+	}
+
+	var request visionImageRequest
+	request.Image.Content = image
+	request.Features = []visionFeature{{Type: "DOCUMENT_TEXT_DETECTION"}}
+	request.ImageContext.LanguageHints = []string{"ja"}
+	body, err := json.Marshal(visionRequest{Requests: []visionImageRequest{request}})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := v.URL
+	if endpoint == "" {
+		endpoint = DefaultVisionURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"?key="+url.QueryEscape(v.APIKey), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: vision answered %s", ErrUnavailable, resp.Status)
+	}
+
+	var annotations visionResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxVisionResponse)).Decode(&annotations); err != nil {
+		return "", fmt.Errorf("%w: decoding vision response: %v", ErrUnavailable, err)
+	}
+	if len(annotations.Responses) == 0 {
+		return "", nil
+	}
+	if e := annotations.Responses[0].Error; e != nil {
+		return "", fmt.Errorf("%w: vision: %s", ErrUnavailable, e.Message)
+	}
+	return annotations.Responses[0].FullTextAnnotation.Text, nil
+}
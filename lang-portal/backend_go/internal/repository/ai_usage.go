@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AIUsageFilter narrows down the usage summarized by Summarize.
+// Zero values are ignored.
+type AIUsageFilter struct {
+	User      string
+	TimeRange TimeRange
+}
+
+// AIUsageTotals adds up the requests of the AI features and their tokens
+type AIUsageTotals struct {
+	Requests         int64 `json:"requests"`
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+// Tokens returns the prompt and completion tokens together
+func (t AIUsageTotals) Tokens() int64 {
+	return t.PromptTokens + t.CompletionTokens
+}
+
+// AIUsageLimit caps the requests and tokens of a user since a time. Zero
+// values are unlimited.
+type AIUsageLimit struct {
+	Since    time.Time
+	Requests int64
+	Tokens   int64
+}
+
+// AIUsageSummary is the usage of one AI feature by one user through one provider
+type AIUsageSummary struct {
+	User     string `json:"user"`
+	Feature  string `json:"feature"`
+	Provider string `json:"provider"`
+	AIUsageTotals
+}
+
+// AIUsageRepository handles database operations for AI usage records
+type AIUsageRepository struct {
+	*BaseRepository
+}
+
+// NewAIUsageRepository creates a new AI usage repository
+func NewAIUsageRepository(db *gorm.DB) *AIUsageRepository {
+	return &AIUsageRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Record stores the usage of a request, filling in its reservation if it has
+// one
+func (r *AIUsageRepository) Record(ctx context.Context, usage *models.AIUsage) error {
+	if usage.ID == 0 {
+		return r.db.WithContext(ctx).Create(usage).Error
+	}
+	return r.db.WithContext(ctx).Model(usage).Updates(map[string]interface{}{
+		"provider":          usage.Provider,
+		"prompt_tokens":     usage.PromptTokens,
+		"completion_tokens": usage.CompletionTokens,
+	}).Error
+}
+
+// Reserve stores a request of usage's user, without tokens yet, if the user is
+// still within limit, and reports whether it did. The check and the insert are
+// one statement, so concurrent requests cannot all pass the last free slot.
+func (r *AIUsageRepository) Reserve(ctx context.Context, usage *models.AIUsage, limit AIUsageLimit) (bool, error) {
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+	var ids []uint
+	err := r.db.WithContext(ctx).Raw(`INSERT INTO ai_usage (user, feature, provider, prompt_tokens, completion_tokens, created_at)
+		SELECT ?, ?, ?, 0, 0, ?
+		WHERE (? = 0 OR (SELECT COUNT(*) FROM ai_usage
+				WHERE user = ? AND julianday(created_at) >= julianday(?)) < ?)
+			AND (? = 0 OR (SELECT COALESCE(SUM(prompt_tokens + completion_tokens), 0) FROM ai_usage
+				WHERE user = ? AND julianday(created_at) >= julianday(?)) < ?)
+		RETURNING id`,
+		usage.User, usage.Feature, usage.Provider, usage.CreatedAt,
+		limit.Requests, usage.User, limit.Since, limit.Requests,
+		limit.Tokens, usage.User, limit.Since, limit.Tokens).
+		Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return false, err
+	}
+	usage.ID = ids[0]
+	return true, nil
+}
+
+// Release deletes the reservation of a request that was not made
+func (r *AIUsageRepository) Release(ctx context.Context, usage *models.AIUsage) error {
+	return r.db.WithContext(ctx).Delete(&models.AIUsage{}, usage.ID).Error
+}
+
+// TotalsSince adds up the usage of a user since the given time
+func (r *AIUsageRepository) TotalsSince(ctx context.Context, user string, since time.Time) (*AIUsageTotals, error) {
+	var totals AIUsageTotals
+	err := r.db.WithContext(ctx).Model(&models.AIUsage{}).
+		Select(`COUNT(*) AS requests,
+			COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) AS completion_tokens`).
+		Where("user = ? AND julianday(created_at) >= julianday(?)", user, since).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// Summarize adds up usage by user, feature and provider, most tokens first
+func (r *AIUsageRepository) Summarize(ctx context.Context, filter AIUsageFilter) ([]AIUsageSummary, error) {
+	query := r.db.WithContext(ctx).Model(&models.AIUsage{}).
+		Select(`user, feature, provider,
+			COUNT(*) AS requests,
+			SUM(prompt_tokens) AS prompt_tokens,
+			SUM(completion_tokens) AS completion_tokens`)
+	if filter.User != "" {
+		query = query.Where("user = ?", filter.User)
+	}
+	if !filter.TimeRange.Start.IsZero() {
+		query = query.Where("julianday(created_at) >= julianday(?)", filter.TimeRange.Start)
+	}
+	if !filter.TimeRange.End.IsZero() {
+		query = query.Where("julianday(created_at) <= julianday(?)", filter.TimeRange.End)
+	}
+
+	var summaries []AIUsageSummary
+	err := query.Group("user, feature, provider").
+		Order("SUM(prompt_tokens) + SUM(completion_tokens) DESC, user ASC, feature ASC, provider ASC").
+		Scan(&summaries).Error
+	return summaries, err
+}
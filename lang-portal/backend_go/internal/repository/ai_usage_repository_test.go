@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIUsageRepository_Totals(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewAIUsageRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, usage := range []models.AIUsage{
+		{User: "alice", Feature: "tutor", Provider: "openai", PromptTokens: 100, CompletionTokens: 20, CreatedAt: now.Add(-time.Hour)},
+		{User: "alice", Feature: "tutor", Provider: "ollama", PromptTokens: 50, CompletionTokens: 10, CreatedAt: now},
+		{User: "alice", Feature: "word_explanation", Provider: "openai", PromptTokens: 30, CompletionTokens: 40, CreatedAt: now.Add(-48 * time.Hour)},
+		{User: "bob", Feature: "tutor", Provider: "openai", PromptTokens: 500, CompletionTokens: 5, CreatedAt: now},
+	} {
+		require.NoError(t, repo.Record(ctx, &usage))
+	}
+
+	totals, err := repo.TotalsSince(ctx, "alice", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, AIUsageTotals{Requests: 2, PromptTokens: 150, CompletionTokens: 30}, *totals)
+	assert.Equal(t, int64(180), totals.Tokens())
+
+	none, err := repo.TotalsSince(ctx, "carol", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, none.Requests)
+
+	all, err := repo.Summarize(ctx, AIUsageFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, "bob", all[0].User, "most tokens first")
+
+	recent, err := repo.Summarize(ctx, AIUsageFilter{User: "alice", TimeRange: TimeRange{Start: now.Add(-24 * time.Hour)}})
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	assert.Equal(t, AIUsageSummary{User: "alice", Feature: "tutor", Provider: "openai", AIUsageTotals: AIUsageTotals{Requests: 1, PromptTokens: 100, CompletionTokens: 20}}, recent[0])
+	assert.Equal(t, "ollama", recent[1].Provider)
+}
+
+func TestAIUsageRepository_Reserve(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewAIUsageRepository(db)
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	require.NoError(t, repo.Record(ctx, &models.AIUsage{User: "ip:192.0.2.1", Feature: "tutor", PromptTokens: 10, CreatedAt: today.Add(-time.Hour)}))
+	limit := AIUsageLimit{Since: today, Requests: 2}
+	var reserved []*models.AIUsage
+	for i := 0; i < 3; i++ {
+		usage := &models.AIUsage{User: "ip:192.0.2.1", Feature: "tutor"}
+		ok, err := repo.Reserve(ctx, usage, limit)
+		require.NoError(t, err)
+		if ok {
+			assert.NotZero(t, usage.ID)
+			reserved = append(reserved, usage)
+		}
+	}
+	require.Len(t, reserved, 2, "yesterday's request does not count")
+
+	other, err := repo.Reserve(ctx, &models.AIUsage{User: "key:3", Feature: "tutor"}, limit)
+	require.NoError(t, err)
+	assert.True(t, other, "quotas are per user")
+
+	// A reservation is filled in by Record and given back by Release
+	reserved[0].Provider, reserved[0].PromptTokens, reserved[0].CompletionTokens = "openai", 300, 40
+	require.NoError(t, repo.Record(ctx, reserved[0]))
+	require.NoError(t, repo.Release(ctx, reserved[1]))
+	totals, err := repo.TotalsSince(ctx, "ip:192.0.2.1", today)
+	require.NoError(t, err)
+	assert.Equal(t, AIUsageTotals{Requests: 1, PromptTokens: 300, CompletionTokens: 40}, *totals)
+
+	ok, err := repo.Reserve(ctx, &models.AIUsage{User: "ip:192.0.2.1", Feature: "tutor"}, AIUsageLimit{Since: today, Tokens: 340})
+	require.NoError(t, err)
+	assert.False(t, ok, "tokens used up")
+	ok, err = repo.Reserve(ctx, &models.AIUsage{User: "ip:192.0.2.1", Feature: "tutor"}, AIUsageLimit{Since: today, Requests: 2, Tokens: 341})
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository handles database operations for API keys
+type APIKeyRepository struct {
+	*BaseRepository
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create stores a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// List returns every API key, revoked ones included, newest first
+func (r *APIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.db.WithContext(ctx).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByHash retrieves an API key by the hash of the key
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Revoke marks an API key as revoked at the given time. Revoking a revoked key
+// keeps its original revocation time.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ?", id).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", at))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed records that an API key was used at the given time
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewAPIKeyRepository(db)
+	ctx := context.Background()
+
+	first := &models.APIKey{Name: "cli", Prefix: "lp_abcdef", KeyHash: "hash-1", Scopes: models.StringSlice{models.ScopeRead}}
+	second := &models.APIKey{Name: "importer", Prefix: "lp_ghijkl", KeyHash: "hash-2", Scopes: models.StringSlice{models.ScopeWrite}}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+	assert.Error(t, repo.Create(ctx, &models.APIKey{Name: "copy", Prefix: "lp_abcdef", KeyHash: "hash-1", Scopes: models.StringSlice{}}), "hashes are unique")
+
+	key, err := repo.GetByHash(ctx, "hash-2")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, key.ID)
+	assert.Equal(t, models.StringSlice{models.ScopeWrite}, key.Scopes)
+	_, err = repo.GetByHash(ctx, "hash-3")
+	assert.Equal(t, ErrNotFound, err)
+
+	used := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.TouchLastUsed(ctx, first.ID, used))
+	revoked := used.Add(time.Minute)
+	require.NoError(t, repo.Revoke(ctx, first.ID, revoked))
+	require.NoError(t, repo.Revoke(ctx, first.ID, revoked.Add(time.Hour)))
+	assert.Equal(t, ErrNotFound, repo.Revoke(ctx, 99, revoked))
+
+	key, err = repo.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, key.LastUsedAt)
+	assert.True(t, used.Equal(*key.LastUsedAt))
+	require.NotNil(t, key.RevokedAt)
+	assert.True(t, revoked.Equal(*key.RevokedAt), "revoking again keeps the first time")
+
+	keys, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, second.ID, keys[0].ID)
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// archiveBatchSize is the number of rows inserted per statement on restore
+const archiveBatchSize = 500
+
+// restoreTables are the tables an archive restore empties, in reverse order of
+// dependencies. Kanji are kept so their dictionary data survives; words are
+// linked to them again as they are restored.
+var restoreTables = []string{"activity_review_batches", "word_review_items", "word_review_archive", "word_review_months", "session_words", "study_sessions", "word_groups", "group_goals", "groups", "word_kanji", "word_parts", "example_sentences", "words"}
+
+// ArchiveData holds every row an account archive covers. Associations on the
+// models are not loaded; rows refer to each other by ID.
+type ArchiveData struct {
+	Words      []models.Word
+	Groups     []models.Group
+	WordGroups []WordGroup
+	Sessions   []models.StudySession
+	Reviews    []models.WordReview
+	Sentences  []models.ExampleSentence
+	Settings   []models.Setting
+}
+
+// ArchiveRepository reads and replaces the data of the whole account
+type ArchiveRepository struct {
+	*BaseRepository
+}
+
+// NewArchiveRepository creates a new archive repository
+func NewArchiveRepository(db *gorm.DB) *ArchiveRepository {
+	return &ArchiveRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// Dump reads the words, groups and study history in a single transaction, so
+// the archive is consistent. Settings are not read.
+func (r *ArchiveRepository) Dump(ctx context.Context) (*ArchiveData, error) {
+	data := &ArchiveData{}
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Order("id ASC").Find(&data.Words).Error; err != nil {
+			return err
+		}
+		if err := tx.Order("id ASC").Find(&data.Groups).Error; err != nil {
+			return err
+		}
+		if err := tx.Order("group_id ASC, word_id ASC").Find(&data.WordGroups).Error; err != nil {
+			return err
+		}
+		if err := tx.Order("id ASC").Find(&data.Sessions).Error; err != nil {
+			return err
+		}
+		// Archived reviews are read back, so a restore makes them live again
+		if err := tx.Table(allWordReviews + " AS word_review_items").Order("id ASC").Find(&data.Reviews).Error; err != nil {
+			return err
+		}
+		return tx.Order("id ASC").Find(&data.Sentences).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// CountRestore returns the number of rows per table Restore deletes
+func (r *ArchiveRepository) CountRestore(ctx context.Context) (map[string]int64, error) {
+	return countRows(r.db.WithContext(ctx), restoreTables)
+}
+
+// Restore replaces all words, groups, their goals and study history with data,
+// keeping the IDs it carries, and overwrites the settings it contains. Study
+// activities, other settings and the audit log are kept. guard, if not nil, is
+// called with the rows about to be deleted before anything is changed.
+func (r *ArchiveRepository) Restore(ctx context.Context, data *ArchiveData, guard ResetGuard) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := checkReset(tx, restoreTables, guard); err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordProgress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordStats{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordVector{}).Error; err != nil {
+			return err
+		}
+		for _, table := range restoreTables {
+			if err := tx.Exec("DELETE FROM " + table).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(data.Words) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&data.Words, archiveBatchSize).Error; err != nil {
+				return err
+			}
+			for i := range data.Words {
+				if err := addPartsOfSpeech(tx, data.Words[i].Parts); err != nil {
+					return err
+				}
+				if err := linkWordParts(tx, &data.Words[i]); err != nil {
+					return err
+				}
+				if err := linkWordKanji(tx, &data.Words[i]); err != nil {
+					return err
+				}
+			}
+			if err := rankWords(tx.Where("1=1")); err != nil {
+				return err
+			}
+		}
+		if len(data.Groups) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&data.Groups, archiveBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(data.WordGroups) > 0 {
+			if err := tx.CreateInBatches(&data.WordGroups, archiveBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(data.Sessions) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&data.Sessions, archiveBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(data.Reviews) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&data.Reviews, archiveBatchSize).Error; err != nil {
+				return err
+			}
+			// Archives carry the review schedule but not progress and stats, which are rebuilt
+			if err := replayReviews(tx, nil, func(wordID uint, reviews []models.WordReview) error {
+				if err := replayWordProgress(tx, wordID, reviews); err != nil {
+					return err
+				}
+				return replayWordStats(tx, wordID, reviews)
+			}); err != nil {
+				return err
+			}
+		}
+		if len(data.Sentences) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&data.Sentences, archiveBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(data.Settings) > 0 {
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&data.Settings).Error
+		}
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRepository_DumpAndRestore(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewArchiveRepository(db)
+	ctx := context.Background()
+
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := &models.Word{Term: "食べる", Reading: "taberu", English: "to eat", Parts: models.StringSlice{"verb"}}
+	require.NoError(t, NewWordRepository(db).Create(ctx, word))
+	group := &models.Group{Name: "Verbs"}
+	require.NoError(t, db.Create(group).Error)
+	require.NoError(t, NewGroupRepository(db).AddWord(ctx, group.ID, word.ID))
+	session := &models.StudySession{GroupID: group.ID, StudyActivityID: activity.ID}
+	require.NoError(t, db.Create(session).Error)
+	require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}).Error)
+	require.NoError(t, db.Create(&models.ExampleSentence{WordID: word.ID, Japanese: "パンを食べます。"}).Error)
+
+	data, err := repo.Dump(ctx)
+	require.NoError(t, err)
+	require.Len(t, data.Words, 1)
+	require.Len(t, data.Groups, 1)
+	assert.Equal(t, []WordGroup{{GroupID: group.ID, WordID: word.ID}}, data.WordGroups)
+	require.Len(t, data.Sessions, 1)
+	require.Len(t, data.Reviews, 1)
+	require.Len(t, data.Sentences, 1)
+
+	counts, err := repo.CountRestore(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), counts["words"])
+	assert.Equal(t, int64(1), counts["word_kanji"])
+
+	// A rejecting guard leaves everything in place
+	errAbort := errors.New("abort")
+	assert.Equal(t, errAbort, repo.Restore(ctx, data, func(map[string]int64) error { return errAbort }))
+
+	// Restore over a changed database, with a word and a setting added
+	require.NoError(t, NewWordRepository(db).Create(ctx, &models.Word{Term: "猫", Reading: "neko", English: "cat", Parts: models.StringSlice{"noun"}}))
+	data.Settings = []models.Setting{{Key: models.SettingReviewOrder, Value: "random"}}
+	require.NoError(t, repo.Restore(ctx, data, nil))
+
+	restored, err := repo.Dump(ctx)
+	require.NoError(t, err)
+	require.Len(t, restored.Words, 1)
+	assert.Equal(t, word.ID, restored.Words[0].ID)
+	assert.Equal(t, "食べる", restored.Words[0].Term)
+	assert.Equal(t, data.WordGroups, restored.WordGroups)
+	require.Len(t, restored.Sessions, 1)
+	assert.Equal(t, session.ID, restored.Sessions[0].ID)
+	assert.Equal(t, activity.ID, restored.Sessions[0].StudyActivityID)
+	require.Len(t, restored.Reviews, 1)
+	assert.True(t, restored.Reviews[0].Correct)
+	require.Len(t, restored.Sentences, 1)
+	assert.Equal(t, "パンを食べます。", restored.Sentences[0].Japanese)
+
+	order, err := NewSettingRepository(db).Get(ctx, models.SettingReviewOrder)
+	require.NoError(t, err)
+	assert.Equal(t, "random", order)
+
+	// Restored words are linked to their kanji again
+	var links int64
+	require.NoError(t, db.Model(&WordKanji{}).Where("word_id = ?", word.ID).Count(&links).Error)
+	assert.Equal(t, int64(1), links)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows down the audit entries returned by List.
+// Zero values are ignored.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   *uint
+	TimeRange  TimeRange
+}
+
+// AuditRepository handles database operations for audit entries
+type AuditRepository struct {
+	*BaseRepository
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create records a new audit entry
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// List retrieves a paginated list of audit entries, newest first
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter, params PaginationParams) (*PaginatedResult[models.AuditEntry], error) {
+	var entries []models.AuditEntry
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuditEntry{})
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	query = r.WithTimeRange(query, "created_at", filter.TimeRange)
+
+	paginatedQuery, err := r.Paginate(query.Order("created_at DESC, id DESC"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := paginatedQuery.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(total) + params.PageSize - 1) / params.PageSize
+	return &PaginatedResult[models.AuditEntry]{
+		Items:      entries,
+		TotalItems: total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository_CreateAndList(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewAuditRepository(db)
+
+	wordID := uint(3)
+	deleted, err := models.NewAuditEntry("alice", models.AuditActionDelete, models.AuditEntityWord, &wordID, map[string]string{"japanese": "犬"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), deleted))
+
+	reset, err := models.NewAuditEntry("bob", models.AuditActionResetHistory, models.AuditEntityStudy, nil, map[string]int64{"study_sessions": 2}, nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), reset))
+
+	all, err := repo.List(context.Background(), AuditFilter{}, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), all.TotalItems)
+
+	byActor, err := repo.List(context.Background(), AuditFilter{Actor: "alice"}, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, byActor.Items, 1)
+	assert.Equal(t, models.AuditEntityWord, byActor.Items[0].EntityType)
+	assert.Equal(t, wordID, *byActor.Items[0].EntityID)
+	assert.JSONEq(t, `{"japanese":"犬"}`, string(byActor.Items[0].Before))
+	assert.Nil(t, byActor.Items[0].After)
+
+	byEntity, err := repo.List(context.Background(), AuditFilter{EntityType: models.AuditEntityWord, EntityID: &wordID}, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), byEntity.TotalItems)
+
+	future, err := repo.List(context.Background(), AuditFilter{TimeRange: TimeRange{Start: time.Now().Add(time.Hour)}}, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), future.TotalItems)
+}
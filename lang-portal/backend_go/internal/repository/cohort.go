@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CohortRepository handles database operations for cohorts, their members and
+// their groups
+type CohortRepository struct {
+	*BaseRepository
+}
+
+// NewCohortRepository creates a new cohort repository
+func NewCohortRepository(db *gorm.DB) *CohortRepository {
+	return &CohortRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// Create creates a new cohort
+func (r *CohortRepository) Create(ctx context.Context, cohort *models.Cohort) error {
+	if err := cohort.Validate(); err != nil {
+		return ErrInvalidInput
+	}
+	return r.db.WithContext(ctx).Create(cohort).Error
+}
+
+// GetByID retrieves a cohort by ID, with its members and groups
+func (r *CohortRepository) GetByID(ctx context.Context, id uint) (*models.Cohort, error) {
+	var cohort models.Cohort
+	err := r.db.WithContext(ctx).
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("learner ASC") }).
+		Preload("Groups", func(db *gorm.DB) *gorm.DB { return db.Order("group_id ASC") }).
+		First(&cohort, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &cohort, nil
+}
+
+// ListByOwner retrieves the cohorts of a teacher, newest first, without their
+// members and groups
+func (r *CohortRepository) ListByOwner(ctx context.Context, owner string) ([]models.Cohort, error) {
+	var cohorts []models.Cohort
+	err := r.db.WithContext(ctx).Where("owner = ?", owner).Order("id DESC").Find(&cohorts).Error
+	return cohorts, err
+}
+
+// Delete deletes a cohort with its memberships and group assignments
+func (r *CohortRepository) Delete(ctx context.Context, id uint) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("cohort_id = ?", id).Delete(&models.CohortMembership{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("cohort_id = ?", id).Delete(&models.CohortGroup{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Cohort{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// AddMember adds a learner to a cohort. A learner already in it returns
+// ErrAlreadyExists.
+func (r *CohortRepository) AddMember(ctx context.Context, membership *models.CohortMembership) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.CohortMembership{}).
+			Where("cohort_id = ? AND learner = ?", membership.CohortID, membership.Learner).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrAlreadyExists
+		}
+		return tx.Create(membership).Error
+	})
+}
+
+// UpdateMember saves the display name and leaderboard choice of a learner in a
+// cohort, or returns ErrNotFound when they are not in it
+func (r *CohortRepository) UpdateMember(ctx context.Context, membership *models.CohortMembership) error {
+	result := r.db.WithContext(ctx).Model(&models.CohortMembership{}).
+		Where("cohort_id = ? AND learner = ?", membership.CohortID, membership.Learner).
+		Updates(map[string]interface{}{
+			"display_name":        membership.DisplayName,
+			"leaderboard_opt_out": membership.LeaderboardOptOut,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RemoveMember removes a learner from a cohort, or returns ErrNotFound when
+// they are not in it
+func (r *CohortRepository) RemoveMember(ctx context.Context, cohortID uint, learner string) error {
+	result := r.db.WithContext(ctx).Where("cohort_id = ? AND learner = ?", cohortID, learner).Delete(&models.CohortMembership{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddGroup assigns a group to a cohort. Assigning it again changes nothing.
+func (r *CohortRepository) AddGroup(ctx context.Context, cohortID, groupID uint) error {
+	return r.db.WithContext(ctx).
+		Where(models.CohortGroup{CohortID: cohortID, GroupID: groupID}).
+		FirstOrCreate(&models.CohortGroup{}).Error
+}
+
+// RemoveGroup unassigns a group from a cohort, or returns ErrNotFound when it
+// is not assigned
+func (r *CohortRepository) RemoveGroup(ctx context.Context, cohortID, groupID uint) error {
+	result := r.db.WithContext(ctx).Where("cohort_id = ? AND group_id = ?", cohortID, groupID).Delete(&models.CohortGroup{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// LearnerStudyStats holds the study activity of one learner in the groups of a
+// cohort
+type LearnerStudyStats struct {
+	Learner        string
+	Sessions       int64
+	Reviews        int64
+	CorrectReviews int64
+	LastStudiedAt  time.Time
+}
+
+// GetLearnerStats aggregates the sessions of the cohort's members in the
+// cohort's groups started since the given time, or ever when it is zero, and
+// their reviews, by learner. Members without such sessions are left out.
+func (r *CohortRepository) GetLearnerStats(ctx context.Context, cohortID uint, since time.Time) ([]LearnerStudyStats, error) {
+	var rows []struct {
+		Learner        string
+		Sessions       int64
+		Reviews        int64
+		CorrectReviews int64
+		// Aggregates lose the column type, so the time is formatted in SQL
+		LastStudiedAt string
+	}
+	query := r.db.WithContext(ctx).Table("study_sessions").
+		Select(`study_sessions.learner,
+			COUNT(DISTINCT study_sessions.id) AS sessions,
+			COUNT(word_review_items.id) AS reviews,
+			COALESCE(SUM(CASE WHEN word_review_items.correct THEN 1 ELSE 0 END), 0) AS correct_reviews,
+			strftime('%Y-%m-%dT%H:%M:%fZ', MAX(julianday(study_sessions.created_at))) AS last_studied_at`).
+		Joins("JOIN cohort_memberships ON cohort_memberships.learner = study_sessions.learner AND cohort_memberships.cohort_id = ?", cohortID).
+		Joins("JOIN cohort_groups ON cohort_groups.group_id = study_sessions.group_id AND cohort_groups.cohort_id = ?", cohortID).
+		Joins("LEFT JOIN " + allWordReviews + " AS word_review_items ON word_review_items.study_session_id = study_sessions.id")
+	if !since.IsZero() {
+		query = query.Where("julianday(study_sessions.created_at) >= julianday(?)", since)
+	}
+	err := query.Group("study_sessions.learner").
+		Order("study_sessions.learner ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]LearnerStudyStats, len(rows))
+	for i, row := range rows {
+		last, err := time.Parse(time.RFC3339Nano, row.LastStudiedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing last study time of learner %q: %w", row.Learner, err)
+		}
+		stats[i] = LearnerStudyStats{
+			Learner:        row.Learner,
+			Sessions:       row.Sessions,
+			Reviews:        row.Reviews,
+			CorrectReviews: row.CorrectReviews,
+			LastStudiedAt:  last,
+		}
+	}
+	return stats, nil
+}
+
+// LearnerStudyDay is a UTC day, as YYYY-MM-DD, on which a learner studied
+type LearnerStudyDay struct {
+	Learner string
+	Day     string
+}
+
+// GetLearnerStudyDays returns the days since the given time on which the
+// cohort's members started sessions in the cohort's groups, by learner and
+// newest day first
+func (r *CohortRepository) GetLearnerStudyDays(ctx context.Context, cohortID uint, since time.Time) ([]LearnerStudyDay, error) {
+	var days []LearnerStudyDay
+	err := r.db.WithContext(ctx).Table("study_sessions").
+		Select("DISTINCT study_sessions.learner, date(study_sessions.created_at) AS day").
+		Joins("JOIN cohort_memberships ON cohort_memberships.learner = study_sessions.learner AND cohort_memberships.cohort_id = ?", cohortID).
+		Joins("JOIN cohort_groups ON cohort_groups.group_id = study_sessions.group_id AND cohort_groups.cohort_id = ?", cohortID).
+		Where("julianday(study_sessions.created_at) >= julianday(?)", since).
+		Order("study_sessions.learner ASC, day DESC").
+		Scan(&days).Error
+	return days, err
+}
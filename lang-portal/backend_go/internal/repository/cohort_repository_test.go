@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCohortRepository_Members(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewCohortRepository(db)
+	ctx := context.Background()
+
+	cohort := &models.Cohort{Name: "Class 1A", Owner: "teacher"}
+	require.NoError(t, repo.Create(ctx, cohort))
+	group := testutil.CreateTestGroup(t, db)
+
+	require.NoError(t, repo.AddMember(ctx, &models.CohortMembership{CohortID: cohort.ID, Learner: "yuki"}))
+	assert.Equal(t, ErrAlreadyExists, repo.AddMember(ctx, &models.CohortMembership{CohortID: cohort.ID, Learner: "yuki"}))
+	require.NoError(t, repo.AddGroup(ctx, cohort.ID, group.ID))
+	require.NoError(t, repo.AddGroup(ctx, cohort.ID, group.ID))
+
+	fetched, err := repo.GetByID(ctx, cohort.ID)
+	require.NoError(t, err)
+	require.Len(t, fetched.Members, 1)
+	assert.Equal(t, "yuki", fetched.Members[0].Learner)
+
+	require.NoError(t, repo.UpdateMember(ctx, &models.CohortMembership{CohortID: cohort.ID, Learner: "yuki", DisplayName: "Yuki", LeaderboardOptOut: true}))
+	assert.Equal(t, ErrNotFound, repo.UpdateMember(ctx, &models.CohortMembership{CohortID: cohort.ID, Learner: "ken"}))
+	fetched, err = repo.GetByID(ctx, cohort.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Yuki", fetched.Members[0].DisplayName)
+	assert.True(t, fetched.Members[0].LeaderboardOptOut)
+	require.Len(t, fetched.Groups, 1)
+	assert.Equal(t, group.ID, fetched.Groups[0].GroupID)
+
+	cohorts, err := repo.ListByOwner(ctx, "teacher")
+	require.NoError(t, err)
+	assert.Len(t, cohorts, 1)
+
+	assert.Equal(t, ErrNotFound, repo.RemoveMember(ctx, cohort.ID, "ken"))
+	require.NoError(t, repo.RemoveMember(ctx, cohort.ID, "yuki"))
+	require.NoError(t, repo.RemoveGroup(ctx, cohort.ID, group.ID))
+	assert.Equal(t, ErrNotFound, repo.RemoveGroup(ctx, cohort.ID, group.ID))
+
+	require.NoError(t, repo.Delete(ctx, cohort.ID))
+	_, err = repo.GetByID(ctx, cohort.ID)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestCohortRepository_GetLearnerStats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewCohortRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	assigned := testutil.CreateTestGroup(t, db)
+	other := &models.Group{Name: "Other"}
+	require.NoError(t, db.Create(other).Error)
+	activity := testutil.CreateTestStudyActivity(t, db)
+
+	cohort := &models.Cohort{Name: "Class 1A", Owner: "teacher"}
+	require.NoError(t, repo.Create(ctx, cohort))
+	require.NoError(t, repo.AddGroup(ctx, cohort.ID, assigned.ID))
+	for _, learner := range []string{"yuki", "ken"} {
+		require.NoError(t, repo.AddMember(ctx, &models.CohortMembership{CohortID: cohort.ID, Learner: learner}))
+	}
+
+	session := func(learner string, groupID uint) *models.StudySession {
+		s := &models.StudySession{GroupID: groupID, StudyActivityID: activity.ID, Learner: learner}
+		require.NoError(t, db.Create(s).Error)
+		return s
+	}
+	yuki := session("yuki", assigned.ID)
+	testutil.CreateTestWordReview(t, db, word.ID, yuki.ID)
+	require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: yuki.ID, Correct: false}).Error)
+	session("yuki", assigned.ID)
+	// Sessions in other groups and of learners outside the cohort do not count
+	testutil.CreateTestWordReview(t, db, word.ID, session("yuki", other.ID).ID)
+	testutil.CreateTestWordReview(t, db, word.ID, session("mei", assigned.ID).ID)
+
+	stats, err := repo.GetLearnerStats(ctx, cohort.ID, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "ken has no sessions")
+	assert.Equal(t, "yuki", stats[0].Learner)
+	assert.Equal(t, int64(2), stats[0].Sessions)
+	assert.Equal(t, int64(2), stats[0].Reviews)
+	assert.Equal(t, int64(1), stats[0].CorrectReviews)
+	assert.False(t, stats[0].LastStudiedAt.IsZero())
+}
+
+func TestCohortRepository_RecentStudy(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewCohortRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	cohort := &models.Cohort{Name: "Class 1A", Owner: "teacher"}
+	require.NoError(t, repo.Create(ctx, cohort))
+	require.NoError(t, repo.AddGroup(ctx, cohort.ID, group.ID))
+	require.NoError(t, repo.AddMember(ctx, &models.CohortMembership{CohortID: cohort.ID, Learner: "yuki"}))
+
+	now := time.Now().UTC()
+	for _, at := range []time.Time{now, now, now.AddDate(0, 0, -1), now.AddDate(0, 0, -10)} {
+		session := &models.StudySession{GroupID: group.ID, StudyActivityID: activity.ID, Learner: "yuki", CreatedAt: at}
+		require.NoError(t, db.Create(session).Error)
+		testutil.CreateTestWordReview(t, db, word.ID, session.ID)
+	}
+
+	stats, err := repo.GetLearnerStats(ctx, cohort.ID, now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].Sessions)
+	assert.Equal(t, int64(3), stats[0].Reviews)
+
+	days, err := repo.GetLearnerStudyDays(ctx, cohort.ID, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, days, 3, "two sessions today count once")
+	assert.Equal(t, now.Format("2006-01-02"), days[0].Day)
+	assert.Equal(t, now.AddDate(0, 0, -1).Format("2006-01-02"), days[1].Day)
+	assert.Equal(t, "yuki", days[2].Learner)
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CounterStats counts the answers to the drills of a counter word
+type CounterStats struct {
+	Counter string
+	Reviews int64
+	Correct int64
+}
+
+// CounterRepository handles database operations for counter drill reviews
+type CounterRepository struct {
+	*BaseRepository
+}
+
+// NewCounterRepository creates a new counter drill repository
+func NewCounterRepository(db *gorm.DB) *CounterRepository {
+	return &CounterRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateReview records an answer to a counter drill
+func (r *CounterRepository) CreateReview(ctx context.Context, review *models.CounterReview) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+// GetStats counts the answers by counter, for the counters that have any
+func (r *CounterRepository) GetStats(ctx context.Context) ([]CounterStats, error) {
+	var stats []CounterStats
+	err := r.db.WithContext(ctx).Model(&models.CounterReview{}).
+		Select("counter, COUNT(*) AS reviews, SUM(CASE WHEN correct THEN 1 ELSE 0 END) AS correct").
+		Group("counter").
+		Order("counter ASC").
+		Scan(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterRepository_Stats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewCounterRepository(db)
+	ctx := context.Background()
+
+	for _, review := range []models.CounterReview{
+		{Counter: "本", Number: 3, Answer: "さんぼん", Correct: true},
+		{Counter: "本", Number: 1, Answer: "いちほん", Correct: false},
+		{Counter: "枚", Number: 2, Answer: "にまい", Correct: true},
+	} {
+		require.NoError(t, repo.CreateReview(ctx, &review))
+	}
+
+	stats, err := repo.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []CounterStats{
+		{Counter: "本", Reviews: 2, Correct: 1},
+		{Counter: "枚", Reviews: 1, Correct: 1},
+	}, stats)
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DailySummaryRepository handles database operations for the precomputed daily
+// study summaries
+type DailySummaryRepository struct {
+	*BaseRepository
+}
+
+// NewDailySummaryRepository creates a new daily summary repository
+func NewDailySummaryRepository(db *gorm.DB) *DailySummaryRepository {
+	return &DailySummaryRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// Compute aggregates the reviews from the start of the from day up to the start
+// of the to day, archived ones included, into one summary per learner and day,
+// replacing the summaries stored for those days, and returns how many were
+// stored. Reviews in sessions without a learner are left out.
+func (r *DailySummaryRepository) Compute(ctx context.Context, from, to time.Time) (int, error) {
+	from, to = from.UTC().Truncate(24*time.Hour), to.UTC().Truncate(24*time.Hour)
+	if !from.Before(to) {
+		return 0, nil
+	}
+
+	var summaries []models.DailySummary
+	err := r.db.WithContext(ctx).Table(allWordReviews+" AS word_review_items").
+		Select(`study_sessions.learner AS learner,
+			date(word_review_items.created_at) AS day,
+			COUNT(DISTINCT study_sessions.id) AS sessions,
+			COUNT(*) AS reviews,
+			SUM(CASE WHEN word_review_items.correct THEN 1 ELSE 0 END) AS correct_reviews,
+			COUNT(DISTINCT strftime('%Y-%m-%d %H:%M', word_review_items.created_at)) AS active_minutes`).
+		Joins("JOIN study_sessions ON study_sessions.id = word_review_items.study_session_id").
+		Where("study_sessions.learner <> ''").
+		Where("julianday(word_review_items.created_at) >= julianday(?) AND julianday(word_review_items.created_at) < julianday(?)", from, to).
+		Group("study_sessions.learner, day").
+		Order("learner ASC, day ASC").
+		Scan(&summaries).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var learned []struct {
+		Learner  string
+		Day      string
+		NewWords int64
+	}
+	firstCorrect := r.db.Table(allWordReviews+" AS word_review_items").
+		Select("study_sessions.learner AS learner, word_review_items.word_id, MIN(julianday(word_review_items.created_at)) AS first_correct").
+		Joins("JOIN study_sessions ON study_sessions.id = word_review_items.study_session_id").
+		Where("study_sessions.learner <> '' AND word_review_items.correct = ?", true).
+		Group("study_sessions.learner, word_review_items.word_id")
+	err = r.db.WithContext(ctx).Table("(?) AS first_correct_reviews", firstCorrect).
+		Select("learner, date(first_correct) AS day, COUNT(*) AS new_words").
+		Where("first_correct >= julianday(?) AND first_correct < julianday(?)", from, to).
+		Group("learner, day").
+		Scan(&learned).Error
+	if err != nil {
+		return 0, err
+	}
+
+	index := make(map[[2]string]int, len(summaries))
+	now := time.Now().UTC()
+	for i := range summaries {
+		index[[2]string{summaries[i].Learner, summaries[i].Day}] = i
+		summaries[i].ComputedAt = now
+	}
+	for _, l := range learned {
+		// A word is only learned through a review, so its summary exists
+		if i, ok := index[[2]string{l.Learner, l.Day}]; ok {
+			summaries[i].NewWords = l.NewWords
+		}
+	}
+
+	err = r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("day >= ? AND day < ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+			Delete(&models.DailySummary{}).Error; err != nil {
+			return err
+		}
+		if len(summaries) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(summaries, 500).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(summaries), nil
+}
+
+// List retrieves a learner's summaries of the days from and to, both given as
+// YYYY-MM-DD and included, oldest first. Days without study have no summary.
+func (r *DailySummaryRepository) List(ctx context.Context, learner, from, to string) ([]models.DailySummary, error) {
+	var summaries []models.DailySummary
+	err := r.db.WithContext(ctx).
+		Where("learner = ? AND day >= ? AND day <= ?", learner, from, to).
+		Order("day ASC").
+		Find(&summaries).Error
+	return summaries, err
+}
+
+// LastDay returns the latest day with a summary as YYYY-MM-DD, or an empty
+// string before any were computed
+func (r *DailySummaryRepository) LastDay(ctx context.Context) (string, error) {
+	var day *string
+	err := r.db.WithContext(ctx).Model(&models.DailySummary{}).
+		Select("MAX(day)").
+		Row().Scan(&day)
+	if err != nil || day == nil {
+		return "", err
+	}
+	return *day, nil
+}
+
+// FirstReviewDay returns the day of the earliest review, archived or not, as
+// YYYY-MM-DD, or an empty string without reviews
+func (r *DailySummaryRepository) FirstReviewDay(ctx context.Context) (string, error) {
+	var day *string
+	err := r.db.WithContext(ctx).Table(allWordReviews + " AS word_review_items").
+		Select("date(MIN(created_at))").
+		Row().Scan(&day)
+	if err != nil || day == nil {
+		return "", err
+	}
+	return *day, nil
+}
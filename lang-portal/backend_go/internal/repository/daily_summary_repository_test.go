@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailySummaryRepository_Compute(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewDailySummaryRepository(db)
+	ctx := context.Background()
+
+	day, err := repo.FirstReviewDay(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, day)
+
+	first := testutil.CreateTestWord(t, db)
+	second := &models.Word{Term: "本", Reading: "hon", English: "book", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(second).Error)
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+
+	monday := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	session := func(learner string, at time.Time) uint {
+		s := &models.StudySession{GroupID: group.ID, StudyActivityID: activity.ID, Learner: learner, CreatedAt: at}
+		require.NoError(t, db.Create(s).Error)
+		return s.ID
+	}
+	review := func(sessionID, wordID uint, correct bool, at time.Time) {
+		require.NoError(t, db.Create(&models.WordReview{WordID: wordID, StudySessionID: sessionID, Correct: correct, CreatedAt: at}).Error)
+	}
+	yukiMonday := session("yuki", monday)
+	review(yukiMonday, first.ID, true, monday)
+	review(yukiMonday, first.ID, false, monday.Add(30*time.Second))
+	review(yukiMonday, second.ID, false, monday.Add(5*time.Minute))
+	yukiTuesday := session("yuki", tuesday)
+	review(yukiTuesday, first.ID, true, tuesday)
+	review(yukiTuesday, second.ID, true, tuesday.Add(10*time.Second))
+	review(session("ken", monday), first.ID, true, monday)
+	review(session("", monday), second.ID, true, monday)
+
+	stored, err := repo.Compute(ctx, monday, tuesday)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored, "only Monday, and not the session without a learner")
+
+	stored, err = repo.Compute(ctx, monday, tuesday.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored, "computing again replaces Monday's summaries")
+
+	summaries, err := repo.List(ctx, "yuki", "2025-03-10", "2025-03-11")
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, models.DailySummary{Learner: "yuki", Day: "2025-03-10", Sessions: 1, Reviews: 3, CorrectReviews: 1, NewWords: 1, ActiveMinutes: 2},
+		withoutComputedAt(summaries[0]))
+	assert.Equal(t, models.DailySummary{Learner: "yuki", Day: "2025-03-11", Sessions: 1, Reviews: 2, CorrectReviews: 2, NewWords: 1, ActiveMinutes: 1},
+		withoutComputedAt(summaries[1]))
+	assert.False(t, summaries[0].ComputedAt.IsZero())
+
+	summaries, err = repo.List(ctx, "ken", "2025-03-11", "2025-03-31")
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+
+	day, err = repo.LastDay(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-11", day)
+	day, err = repo.FirstReviewDay(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-10", day)
+}
+
+func withoutComputedAt(summary models.DailySummary) models.DailySummary {
+	summary.ComputedAt = time.Time{}
+	return summary
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"lang-portal/backend_go/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GoalRepository handles database operations for group goals and the
+// certificates issued when they are attained
+type GoalRepository struct {
+	*BaseRepository
+}
+
+// NewGoalRepository creates a new goal repository
+func NewGoalRepository(db *gorm.DB) *GoalRepository {
+	return &GoalRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// SessionAccuracy is the review record of one study session
+type SessionAccuracy struct {
+	SessionID      uint
+	TotalReviews   int64
+	CorrectReviews int64
+}
+
+// GetGoal retrieves the goal of a group
+func (r *GoalRepository) GetGoal(ctx context.Context, groupID uint) (*models.GroupGoal, error) {
+	var goal models.GroupGoal
+	if err := r.db.WithContext(ctx).Where("group_id = ?", groupID).First(&goal).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// SetGoal creates or replaces the goal of a group
+func (r *GoalRepository) SetGoal(ctx context.Context, goal *models.GroupGoal) error {
+	if err := goal.Validate(); err != nil {
+		return ErrInvalidInput
+	}
+	goal.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_accuracy", "sessions", "updated_at"}),
+	}).Create(goal).Error
+}
+
+// DeleteGoal removes the goal of a group
+func (r *GoalRepository) DeleteGoal(ctx context.Context, groupID uint) error {
+	result := r.db.WithContext(ctx).Where("group_id = ?", groupID).Delete(&models.GroupGoal{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetRecentSessionAccuracy retrieves the review record of the group's most
+// recent sessions that have reviews, newest first
+func (r *GoalRepository) GetRecentSessionAccuracy(ctx context.Context, groupID uint, limit int) ([]SessionAccuracy, error) {
+	var sessions []SessionAccuracy
+	err := r.db.WithContext(ctx).Table("study_sessions").
+		Select(`study_sessions.id AS session_id,
+			COUNT(word_review_items.id) AS total_reviews,
+			SUM(CASE WHEN word_review_items.correct THEN 1 ELSE 0 END) AS correct_reviews`).
+		Joins("JOIN word_review_items ON word_review_items.study_session_id = study_sessions.id").
+		Where("study_sessions.group_id = ?", groupID).
+		Group("study_sessions.id").
+		Order("study_sessions.created_at DESC, study_sessions.id DESC").
+		Limit(limit).
+		Scan(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// CreateCertificate stores an issued certificate
+func (r *GoalRepository) CreateCertificate(ctx context.Context, certificate *models.GroupCertificate) error {
+	return r.db.WithContext(ctx).Create(certificate).Error
+}
+
+// GetLatestCertificate retrieves the most recent certificate issued for a group
+func (r *GoalRepository) GetLatestCertificate(ctx context.Context, groupID uint) (*models.GroupCertificate, error) {
+	var certificate models.GroupCertificate
+	if err := r.db.WithContext(ctx).Where("group_id = ?", groupID).
+		Order("achieved_at DESC, id DESC").
+		First(&certificate).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// GetCertificateByToken retrieves a certificate by its public token
+func (r *GoalRepository) GetCertificateByToken(ctx context.Context, token string) (*models.GroupCertificate, error) {
+	var certificate models.GroupCertificate
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&certificate).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &certificate, nil
+}
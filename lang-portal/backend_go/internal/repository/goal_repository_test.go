@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoalRepository_SetGoal(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGoalRepository(db)
+	group := testutil.CreateTestGroup(t, db)
+
+	_, err := repo.GetGoal(context.Background(), group.ID)
+	assert.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, repo.SetGoal(context.Background(), &models.GroupGoal{GroupID: group.ID, MinAccuracy: 0.9, Sessions: 3}))
+	require.NoError(t, repo.SetGoal(context.Background(), &models.GroupGoal{GroupID: group.ID, MinAccuracy: 0.8, Sessions: 5}))
+	assert.Equal(t, ErrInvalidInput, repo.SetGoal(context.Background(), &models.GroupGoal{GroupID: group.ID, MinAccuracy: 1.5, Sessions: 5}))
+
+	goal, err := repo.GetGoal(context.Background(), group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, goal.MinAccuracy)
+	assert.Equal(t, 5, goal.Sessions)
+
+	require.NoError(t, repo.DeleteGoal(context.Background(), group.ID))
+	assert.Equal(t, ErrNotFound, repo.DeleteGoal(context.Background(), group.ID))
+}
+
+func TestGoalRepository_GetRecentSessionAccuracy(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGoalRepository(db)
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	results := [][]bool{{false, false}, {true, false}, {}, {true, true, true}}
+	var sessionIDs []uint
+	for i, reviews := range results {
+		session := &models.StudySession{GroupID: group.ID, StudyActivityID: activity.ID, CreatedAt: start.Add(time.Duration(i) * time.Hour)}
+		require.NoError(t, db.Create(session).Error)
+		sessionIDs = append(sessionIDs, session.ID)
+		for _, correct := range reviews {
+			require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: correct}).Error)
+		}
+	}
+
+	sessions, err := repo.GetRecentSessionAccuracy(context.Background(), group.ID, 2)
+	require.NoError(t, err)
+	// The session without reviews is skipped
+	require.Len(t, sessions, 2)
+	assert.Equal(t, SessionAccuracy{SessionID: sessionIDs[3], TotalReviews: 3, CorrectReviews: 3}, sessions[0])
+	assert.Equal(t, SessionAccuracy{SessionID: sessionIDs[1], TotalReviews: 2, CorrectReviews: 1}, sessions[1])
+}
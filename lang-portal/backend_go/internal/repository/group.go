@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"lang-portal/backend_go/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -22,18 +24,22 @@ func NewGroupRepository(db *gorm.DB) *GroupRepository {
 	return &GroupRepository{BaseRepository: NewBaseRepository(db)}
 }
 
-// Create creates a new group
-func (r *GroupRepository) Create(group *models.Group) error {
+// Create creates a new group. A group without a language is in the default
+// language.
+func (r *GroupRepository) Create(ctx context.Context, group *models.Group) error {
 	if err := group.Validate(); err != nil {
 		return ErrInvalidInput
 	}
-	return r.db.Create(group).Error
+	if group.Language == "" {
+		group.Language = models.DefaultLanguage
+	}
+	return r.db.WithContext(ctx).Create(group).Error
 }
 
-// GetByID retrieves a group by ID
-func (r *GroupRepository) GetByID(id uint) (*models.Group, error) {
+// GetByID retrieves a group by ID, with its words
+func (r *GroupRepository) GetByID(ctx context.Context, id uint) (*models.Group, error) {
 	var group models.Group
-	if err := r.db.Preload("Words").Preload("Words.Reviews").First(&group, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Words").First(&group, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrNotFound
 		}
@@ -42,10 +48,33 @@ func (r *GroupRepository) GetByID(id uint) (*models.Group, error) {
 	return &group, nil
 }
 
-// GetByName retrieves a group by name
-func (r *GroupRepository) GetByName(name string) (*models.Group, error) {
+// groupWordCount selects the number of words in each group, as a subquery so
+// that groups are counted without loading their words. The alias keeps it
+// apart from word_groups joined by the outer query.
+const groupWordCount = "(SELECT COUNT(*) FROM word_groups AS counted WHERE counted.group_id = groups.id) AS word_count"
+
+// GetSummary retrieves a group by ID with its word count. MasteredCount is not
+// computed and is always 0.
+func (r *GroupRepository) GetSummary(ctx context.Context, id uint) (*GroupSummary, error) {
+	var groups []GroupSummary
+	if err := r.db.WithContext(ctx).Model(&models.Group{}).
+		Select("groups.*, "+groupWordCount).
+		Where("groups.id = ?", id).
+		Limit(1).
+		Scan(&groups).Error; err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, ErrNotFound
+	}
+	return &groups[0], nil
+}
+
+// GetByName retrieves the group of language named name; group names are
+// unique per language
+func (r *GroupRepository) GetByName(ctx context.Context, name, language string) (*models.Group, error) {
 	var group models.Group
-	if err := r.db.Where("name = ?", name).First(&group).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("name = ? AND language = ?", name, language).First(&group).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrNotFound
 		}
@@ -54,27 +83,120 @@ func (r *GroupRepository) GetByName(name string) (*models.Group, error) {
 	return &group, nil
 }
 
-// List retrieves a paginated list of groups
-func (r *GroupRepository) List(params PaginationParams) (*PaginatedResult[models.Group], error) {
-	var groups []models.Group
+// GetByShareSlug retrieves a published group by its share slug, with its words
+func (r *GroupRepository) GetByShareSlug(ctx context.Context, slug string) (*models.Group, error) {
+	var group models.Group
+	if err := r.db.WithContext(ctx).Preload("Words").Where("share_slug = ?", slug).First(&group).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+// SetShareSlug publishes a group under slug, or unpublishes it when slug is
+// nil. A slug another group is published under returns ErrAlreadyExists.
+func (r *GroupRepository) SetShareSlug(ctx context.Context, id uint, slug *string) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if slug != nil {
+			var taken int64
+			if err := tx.Model(&models.Group{}).Where("share_slug = ? AND id <> ?", *slug, id).Count(&taken).Error; err != nil {
+				return err
+			}
+			if taken > 0 {
+				return ErrAlreadyExists
+			}
+		}
+		result := tx.Model(&models.Group{}).Where("id = ?", id).Update("share_slug", slug)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// GroupListOptions orders a group list and configures its aggregates
+type GroupListOptions struct {
+	// Sort is by one of the group sort fields; the zero value orders by ID
+	Sort SortParams
+	// Language keeps only the groups of this target language when not empty
+	Language string
+	// MasteredMinReviews and MasteredMinSuccessRate (0-1) define mastered words
+	MasteredMinReviews     int
+	MasteredMinSuccessRate float64
+}
+
+// Group list sort fields
+const (
+	GroupSortName          = "name"
+	GroupSortCreatedAt     = "created_at"
+	GroupSortWordCount     = "word_count"
+	GroupSortMasteredCount = "mastered_count"
+)
+
+// groupSortColumns maps each sort field to the expression it orders by
+var groupSortColumns = map[string]string{
+	GroupSortName:          "groups.name",
+	GroupSortCreatedAt:     "groups.created_at",
+	GroupSortWordCount:     "word_count",
+	GroupSortMasteredCount: "mastered_count",
+}
+
+// ValidGroupSortField reports whether field is a supported group sort field
+func ValidGroupSortField(field string) bool {
+	_, ok := groupSortColumns[field]
+	return ok
+}
+
+// GroupSummary is a group with aggregates over its words
+type GroupSummary struct {
+	models.Group
+	WordCount     int64
+	MasteredCount int64
+}
+
+// List retrieves a paginated list of groups with their word and mastered word
+// counts, computed in the same query
+func (r *GroupRepository) List(ctx context.Context, params PaginationParams, opts GroupListOptions) (*PaginatedResult[GroupSummary], error) {
+	var groups []GroupSummary
 	var total int64
 
-	query := r.db.Model(&models.Group{})
-	paginatedQuery, err := r.Paginate(query, params)
-	if err != nil {
+	inLanguage := func(query *gorm.DB) *gorm.DB {
+		if opts.Language != "" {
+			query = query.Where("groups.language = ?", opts.Language)
+		}
+		return query
+	}
+	if err := inLanguage(r.db.WithContext(ctx).Model(&models.Group{})).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
-	if err := paginatedQuery.Preload("Words").Preload("Words.Reviews").Find(&groups).Error; err != nil {
-		return nil, err
+	query := inLanguage(r.db.WithContext(ctx).Model(&models.Group{})).
+		Select(`groups.*,
+			COUNT(word_groups.word_id) AS word_count,
+			COALESCE(SUM(CASE WHEN `+wordMastered+` THEN 1 ELSE 0 END), 0) AS mastered_count`,
+			opts.MasteredMinReviews, opts.MasteredMinSuccessRate).
+		Joins("LEFT JOIN word_groups ON word_groups.group_id = groups.id").
+		Joins("LEFT JOIN words ON words.id = word_groups.word_id").
+		Joins(wordStatsJoin).
+		Group("groups.id")
+	query, ok := opts.Sort.order(query, groupSortColumns, "groups.id ASC")
+	if !ok {
+		query = query.Order("groups.id ASC")
 	}
 
-	if err := query.Count(&total).Error; err != nil {
+	// Offset directly: counting this query would drop the aggregates it sorts by
+	offset := (params.Page - 1) * params.PageSize
+	if err := query.Offset(offset).Limit(params.PageSize).Scan(&groups).Error; err != nil {
 		return nil, err
 	}
 
 	totalPages := (int(total) + params.PageSize - 1) / params.PageSize
-	return &PaginatedResult[models.Group]{
+	return &PaginatedResult[GroupSummary]{
 		Items:      groups,
 		TotalItems: total,
 		Page:       params.Page,
@@ -83,44 +205,117 @@ func (r *GroupRepository) List(params PaginationParams) (*PaginatedResult[models
 	}, nil
 }
 
+// ListAll retrieves all groups ordered by name, without associations
+func (r *GroupRepository) ListAll(ctx context.Context) ([]models.Group, error) {
+	var groups []models.Group
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
 // Update updates a group
-func (r *GroupRepository) Update(group *models.Group) error {
+func (r *GroupRepository) Update(ctx context.Context, group *models.Group) error {
 	if err := group.Validate(); err != nil {
 		return ErrInvalidInput
 	}
-	return r.db.Save(group).Error
+	now := time.Now()
+	db := r.db.WithContext(ctx)
+	result := db.Model(&models.Group{}).
+		Where("id = ? AND version = ?", group.ID, group.Version).
+		Updates(map[string]interface{}{
+			"name":       group.Name,
+			"updated_at": now,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.updateMiss(db, &models.Group{}, group.ID)
+	}
+	group.UpdatedAt = now
+	group.Version++
+	return nil
 }
 
 // Delete deletes a group and its associations
-func (r *GroupRepository) Delete(id uint) error {
-	return r.WithTransaction(func(tx *gorm.DB) error {
+func (r *GroupRepository) Delete(ctx context.Context, id uint) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
 		// Delete word-group associations
 		if err := tx.Where("group_id = ?", id).Delete(&WordGroup{}).Error; err != nil {
 			return err
 		}
+		// Delete the group's goal; certificates already issued are kept
+		if err := tx.Where("group_id = ?", id).Delete(&models.GroupGoal{}).Error; err != nil {
+			return err
+		}
+		// Unassign the group from cohorts
+		if err := tx.Where("group_id = ?", id).Delete(&models.CohortGroup{}).Error; err != nil {
+			return err
+		}
 		// Delete the group
 		return tx.Delete(&models.Group{}, id).Error
 	})
 }
 
 // AddWord adds a word to a group
-func (r *GroupRepository) AddWord(groupID, wordID uint) error {
+func (r *GroupRepository) AddWord(ctx context.Context, groupID, wordID uint) error {
 	wordGroup := WordGroup{
 		GroupID: groupID,
 		WordID:  wordID,
 	}
-	return r.db.Create(&wordGroup).Error
+	return r.db.WithContext(ctx).Create(&wordGroup).Error
+}
+
+// CreateWithWords creates a group and adds the given words to it in one
+// transaction. A group without a language is in the default language.
+func (r *GroupRepository) CreateWithWords(ctx context.Context, group *models.Group, wordIDs []uint) error {
+	if err := group.Validate(); err != nil {
+		return ErrInvalidInput
+	}
+	if group.Language == "" {
+		group.Language = models.DefaultLanguage
+	}
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+		for _, wordID := range wordIDs {
+			if err := tx.Create(&WordGroup{GroupID: group.ID, WordID: wordID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReplaceWords sets the words of a group to exactly wordIDs in one transaction
+func (r *GroupRepository) ReplaceWords(ctx context.Context, groupID uint, wordIDs []uint) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&WordGroup{}).Error; err != nil {
+			return err
+		}
+		for _, wordID := range wordIDs {
+			if err := tx.Create(&WordGroup{GroupID: groupID, WordID: wordID}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Group{}).Where("id = ?", groupID).Update("updated_at", time.Now()).Error
+	})
 }
 
 // RemoveWord removes a word from a group
-func (r *GroupRepository) RemoveWord(groupID, wordID uint) error {
-	return r.db.Where("group_id = ? AND word_id = ?", groupID, wordID).Delete(&WordGroup{}).Error
+func (r *GroupRepository) RemoveWord(ctx context.Context, groupID, wordID uint) error {
+	return r.db.WithContext(ctx).Where("group_id = ? AND word_id = ?", groupID, wordID).Delete(&WordGroup{}).Error
 }
 
-// GetStudyStats retrieves study statistics for a group
-func (r *GroupRepository) GetStudyStats(id uint) (totalSessions, totalReviews, correctReviews int, err error) {
+// GetStudyStats retrieves study statistics for a group: its study sessions and
+// the reviews made in them. Reviews of the group's words made while studying
+// another group count towards that group, not this one.
+func (r *GroupRepository) GetStudyStats(ctx context.Context, id uint) (totalSessions, totalReviews, correctReviews int, err error) {
 	var group models.Group
-	if err := r.db.Preload("Words").Preload("Words.Reviews").First(&group, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Sessions.Reviews").First(&group, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return 0, 0, 0, ErrNotFound
 		}
@@ -130,30 +325,58 @@ func (r *GroupRepository) GetStudyStats(id uint) (totalSessions, totalReviews, c
 	return
 }
 
+// GroupWordStats is the review record of one word in a group, across all sessions
+type GroupWordStats struct {
+	WordID         uint
+	Term           string
+	Reading        string
+	English        string
+	CorrectCount   int64
+	WrongCount     int64
+	LastReviewedAt *time.Time
+}
+
+// GetWordStats retrieves the review record of every word in a group with a
+// single aggregate query, ordered like the group's raw word list
+func (r *GroupRepository) GetWordStats(ctx context.Context, groupID uint) ([]GroupWordStats, error) {
+	var stats []GroupWordStats
+	err := r.db.WithContext(ctx).Table("words").
+		Select(`words.id AS word_id, words.term, words.reading, words.english, words.last_reviewed_at,
+			COALESCE(word_stats.correct_count, 0) AS correct_count,
+			COALESCE(word_stats.wrong_count, 0) AS wrong_count`).
+		Joins("JOIN word_groups ON word_groups.word_id = words.id").
+		Joins(wordStatsJoin).
+		Where("word_groups.group_id = ?", groupID).
+		Order("words.term ASC, words.id ASC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 // GetGroupsByWord retrieves groups containing a specific word
-func (r *GroupRepository) GetGroupsByWord(wordID uint, params PaginationParams) (*PaginatedResult[models.Group], error) {
-	var groups []models.Group
+func (r *GroupRepository) GetGroupsByWord(ctx context.Context, wordID uint, params PaginationParams) (*PaginatedResult[GroupSummary], error) {
+	var groups []GroupSummary
 	var total int64
 
-	query := r.db.Model(&models.Group{}).
+	query := r.db.WithContext(ctx).Model(&models.Group{}).
 		Joins("JOIN word_groups ON word_groups.group_id = groups.id").
 		Where("word_groups.word_id = ?", wordID)
 
-	paginatedQuery, err := r.Paginate(query, params)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := paginatedQuery.Preload("Words").Preload("Words.Reviews").Find(&groups).Error; err != nil {
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
-	if err := query.Count(&total).Error; err != nil {
+	offset := (params.Page - 1) * params.PageSize
+	if err := query.Select("groups.*, " + groupWordCount).
+		Offset(offset).Limit(params.PageSize).
+		Scan(&groups).Error; err != nil {
 		return nil, err
 	}
 
 	totalPages := (int(total) + params.PageSize - 1) / params.PageSize
-	return &PaginatedResult[models.Group]{
+	return &PaginatedResult[GroupSummary]{
 		Items:      groups,
 		TotalItems: total,
 		Page:       params.Page,
@@ -163,19 +386,31 @@ func (r *GroupRepository) GetGroupsByWord(wordID uint, params PaginationParams)
 }
 
 // GetTotalGroupCount returns the total number of groups
-func (r *GroupRepository) GetTotalGroupCount() (int64, error) {
+func (r *GroupRepository) GetTotalGroupCount(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Group{}).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Group{}).Count(&count).Error
 	return count, err
 }
 
 // GetActiveGroupCount returns the number of groups that have been studied
-func (r *GroupRepository) GetActiveGroupCount() (int64, error) {
+func (r *GroupRepository) GetActiveGroupCount(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Group{}).
+	err := r.db.WithContext(ctx).Model(&models.Group{}).
 		Joins("JOIN word_groups ON word_groups.group_id = groups.id").
-		Joins("JOIN word_review_items ON word_review_items.word_id = word_groups.word_id").
+		Where("word_groups.word_id IN (SELECT word_id FROM word_review_items UNION SELECT word_id FROM word_review_months)").
 		Distinct().
 		Count(&count).Error
 	return count, err
 }
+
+// Search returns groups whose name contains the query
+func (r *GroupRepository) Search(ctx context.Context, query string, limit int) ([]models.Group, error) {
+	var groups []models.Group
+	if err := r.db.WithContext(ctx).Where(`name LIKE ? ESCAPE '\'`, containsPattern(query)).
+		Order("name ASC").
+		Limit(limit).
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupRepository_CreateWithWords(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+
+	word := testutil.CreateTestWord(t, db)
+	group := &models.Group{Name: "Suggested"}
+	require.NoError(t, repo.CreateWithWords(context.Background(), group, []uint{word.ID}))
+
+	fetched, err := repo.GetByID(context.Background(), group.ID)
+	require.NoError(t, err)
+	require.Len(t, fetched.Words, 1)
+	assert.Equal(t, word.ID, fetched.Words[0].ID)
+
+	// A failing insert rolls back the group
+	err = repo.CreateWithWords(context.Background(), &models.Group{Name: "Broken"}, []uint{word.ID, word.ID})
+	assert.Error(t, err)
+	_, err = repo.GetByName(context.Background(), "Broken", models.DefaultLanguage)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestGroupRepository_UpdateChecksVersion(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+	ctx := context.Background()
+
+	group := &models.Group{Name: "Animals"}
+	require.NoError(t, repo.Create(ctx, group))
+	stale := *group
+
+	group.Name = "Pets"
+	require.NoError(t, repo.Update(ctx, group))
+	assert.Equal(t, uint(2), group.Version)
+
+	stale.Name = "Wildlife"
+	assert.Equal(t, ErrConflict, repo.Update(ctx, &stale))
+
+	// Changing the words of a group does not change its version
+	word := testutil.CreateTestWord(t, db)
+	require.NoError(t, repo.ReplaceWords(ctx, group.ID, []uint{word.ID}))
+	fetched, err := repo.GetByID(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Pets", fetched.Name)
+	assert.Equal(t, uint(2), fetched.Version)
+}
+
+func TestGroupRepository_WordCounts(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+	ctx := context.Background()
+
+	shared := testutil.CreateTestWord(t, db)
+	other := &models.Word{Term: "本", Reading: "hon", English: "book", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(other).Error)
+	big := &models.Group{Name: "Big"}
+	require.NoError(t, repo.CreateWithWords(ctx, big, []uint{shared.ID, other.ID}))
+	small := &models.Group{Name: "Small"}
+	require.NoError(t, repo.CreateWithWords(ctx, small, []uint{shared.ID}))
+	empty := &models.Group{Name: "Empty"}
+	require.NoError(t, repo.Create(ctx, empty))
+
+	summary, err := repo.GetSummary(ctx, big.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Big", summary.Name)
+	assert.Equal(t, int64(2), summary.WordCount)
+	assert.Empty(t, summary.Words, "words are counted, not loaded")
+
+	summary, err = repo.GetSummary(ctx, empty.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), summary.WordCount)
+
+	_, err = repo.GetSummary(ctx, 999)
+	assert.Equal(t, ErrNotFound, err)
+
+	result, err := repo.GetGroupsByWord(ctx, shared.ID, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, int64(2), result.TotalItems)
+	counts := map[string]int64{}
+	for _, g := range result.Items {
+		counts[g.Name] = g.WordCount
+	}
+	assert.Equal(t, map[string]int64{"Big": 2, "Small": 1}, counts)
+}
+
+func TestGroupRepository_GetWordStats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+	studyRepo := NewStudyRepository(db)
+
+	reviewed := testutil.CreateTestWord(t, db)
+	unreviewed := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(unreviewed).Error)
+	group := &models.Group{Name: "Animals"}
+	require.NoError(t, repo.CreateWithWords(context.Background(), group, []uint{reviewed.ID, unreviewed.ID}))
+
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	for _, correct := range []bool{true, true, false} {
+		require.NoError(t, studyRepo.AddWordReview(context.Background(), &models.WordReview{WordID: reviewed.ID, StudySessionID: session.ID, Correct: correct}))
+	}
+
+	stats, err := repo.GetWordStats(context.Background(), group.ID)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	byID := map[uint]GroupWordStats{}
+	for _, s := range stats {
+		byID[s.WordID] = s
+	}
+	assert.Equal(t, int64(2), byID[reviewed.ID].CorrectCount)
+	assert.Equal(t, int64(1), byID[reviewed.ID].WrongCount)
+	assert.NotNil(t, byID[reviewed.ID].LastReviewedAt)
+	assert.Equal(t, reviewed.Term, byID[reviewed.ID].Term)
+
+	assert.Zero(t, byID[unreviewed.ID].CorrectCount)
+	assert.Zero(t, byID[unreviewed.ID].WrongCount)
+	assert.Nil(t, byID[unreviewed.ID].LastReviewedAt)
+}
+
+func TestGroupRepository_GetStudyStats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+	studyRepo := NewStudyRepository(db)
+
+	word := testutil.CreateTestWord(t, db)
+	group := &models.Group{Name: "Animals"}
+	require.NoError(t, repo.CreateWithWords(context.Background(), group, []uint{word.ID}))
+	activity := testutil.CreateTestStudyActivity(t, db)
+	for _, correct := range []bool{true, false} {
+		session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+		require.NoError(t, studyRepo.AddWordReview(context.Background(), &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: correct}))
+	}
+
+	// Reviews of the word while studying another group count there
+	other := &models.Group{Name: "Tests"}
+	require.NoError(t, repo.CreateWithWords(context.Background(), other, []uint{word.ID}))
+	otherSession := testutil.CreateTestStudySession(t, db, other.ID, activity.ID)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, studyRepo.AddWordReview(context.Background(), &models.WordReview{WordID: word.ID, StudySessionID: otherSession.ID, Correct: true}))
+	}
+	// A session of the group without reviews still counts as a session
+	testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	sessions, reviews, correct, err := repo.GetStudyStats(context.Background(), group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sessions)
+	assert.Equal(t, 2, reviews, "only reviews made in the group's sessions count")
+	assert.Equal(t, 1, correct)
+
+	sessions, reviews, correct, err = repo.GetStudyStats(context.Background(), other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sessions)
+	assert.Equal(t, 3, reviews)
+	assert.Equal(t, 3, correct)
+
+	_, _, _, err = repo.GetStudyStats(context.Background(), 999)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestGroupRepository_ReplaceWords(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+
+	first := testutil.CreateTestWord(t, db)
+	second := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(second).Error)
+	group := &models.Group{Name: "Queue"}
+	require.NoError(t, repo.CreateWithWords(context.Background(), group, []uint{first.ID}))
+
+	require.NoError(t, repo.ReplaceWords(context.Background(), group.ID, []uint{second.ID}))
+
+	fetched, err := repo.GetByID(context.Background(), group.ID)
+	require.NoError(t, err)
+	require.Len(t, fetched.Words, 1)
+	assert.Equal(t, second.ID, fetched.Words[0].ID)
+}
+
+func TestGroupRepository_ListMastery(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+
+	mastered := testutil.CreateTestWord(t, db)
+	learning := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(learning).Error)
+	full := &models.Group{Name: "Full"}
+	require.NoError(t, repo.CreateWithWords(context.Background(), full, []uint{mastered.ID, learning.ID}))
+	empty := &models.Group{Name: "Empty"}
+	require.NoError(t, repo.CreateWithWords(context.Background(), empty, nil))
+
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, full.ID, activity.ID)
+	for _, review := range []models.WordReview{
+		{WordID: mastered.ID, Correct: true},
+		{WordID: mastered.ID, Correct: true},
+		{WordID: mastered.ID, Correct: false},
+		{WordID: learning.ID, Correct: true},
+	} {
+		review.StudySessionID = session.ID
+		require.NoError(t, db.Create(&review).Error)
+	}
+	// Written directly, so their stats are recomputed
+	_, err := NewWordRepository(db).RecomputeWordStats(context.Background())
+	require.NoError(t, err)
+
+	result, err := repo.List(context.Background(), PaginationParams{Page: 1, PageSize: 10}, GroupListOptions{MasteredMinReviews: 2, MasteredMinSuccessRate: 0.6})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.TotalItems)
+	require.Len(t, result.Items, 2)
+
+	assert.Equal(t, "Full", result.Items[0].Name)
+	assert.Equal(t, int64(2), result.Items[0].WordCount)
+	assert.Equal(t, int64(1), result.Items[0].MasteredCount)
+	assert.Equal(t, "Empty", result.Items[1].Name)
+	assert.Zero(t, result.Items[1].WordCount)
+	assert.Zero(t, result.Items[1].MasteredCount)
+
+	// Sorted by an aggregate or a column, ties broken by ID
+	for sort, want := range map[SortParams][]string{
+		{Field: GroupSortWordCount}:                 {"Empty", "Full"},
+		{Field: GroupSortName}:                      {"Empty", "Full"},
+		{Field: GroupSortName, Desc: true}:          {"Full", "Empty"},
+		{Field: GroupSortMasteredCount, Desc: true}: {"Full", "Empty"},
+	} {
+		result, err := repo.List(context.Background(), PaginationParams{Page: 1, PageSize: 10}, GroupListOptions{Sort: sort, MasteredMinReviews: 2, MasteredMinSuccessRate: 0.6})
+		require.NoError(t, err)
+		require.Len(t, result.Items, 2)
+		assert.Equal(t, want, []string{result.Items[0].Name, result.Items[1].Name}, sort.Field)
+	}
+}
+
+func TestGroupRepository_ShareSlug(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewGroupRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := &models.Group{Name: "JLPT N5"}
+	require.NoError(t, repo.CreateWithWords(ctx, group, []uint{word.ID}))
+	other := &models.Group{Name: "JLPT N4"}
+	require.NoError(t, repo.Create(ctx, other))
+
+	slug := "jlpt-n5"
+	require.NoError(t, repo.SetShareSlug(ctx, group.ID, &slug))
+	shared, err := repo.GetByShareSlug(ctx, slug)
+	require.NoError(t, err)
+	assert.Equal(t, group.ID, shared.ID)
+	require.Len(t, shared.Words, 1)
+
+	// Publishing again under the same slug is fine, another group is not
+	assert.NoError(t, repo.SetShareSlug(ctx, group.ID, &slug))
+	assert.Equal(t, ErrAlreadyExists, repo.SetShareSlug(ctx, other.ID, &slug))
+	assert.Equal(t, ErrNotFound, repo.SetShareSlug(ctx, 999, nil))
+
+	require.NoError(t, repo.SetShareSlug(ctx, group.ID, nil))
+	_, err = repo.GetByShareSlug(ctx, slug)
+	assert.Equal(t, ErrNotFound, err)
+}
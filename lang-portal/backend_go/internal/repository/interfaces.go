@@ -1,58 +1,288 @@
 package repository
 
-import "lang-portal/backend_go/internal/models"
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+)
 
 // WordRepositoryInterface defines the interface for word repository operations.
 type WordRepositoryInterface interface {
-	Create(word *models.Word) error
-	GetByID(id uint) (*models.Word, error)
-	List(params PaginationParams) (*PaginatedResult[models.Word], error)
-	Update(word *models.Word) error
-	Delete(id uint) error
-	GetStudyStats(wordID uint) (correctCount int64, wrongCount int64, err error)
-	GetWordsByGroup(groupID uint, params PaginationParams) (*PaginatedResult[models.Word], error)
-	GetWordsByGroupRaw(groupID uint) ([]models.Word, error)
-	GetTotalWordCount() (int64, error)
-	GetStudiedWordCount() (int64, error)
-	GetByJapanese(japanese string) (*models.Word, error)
+	Create(ctx context.Context, word *models.Word) error
+	GetByID(ctx context.Context, id uint) (*models.Word, error)
+	List(ctx context.Context, params PaginationParams, opts WordListOptions) (*PaginatedResult[models.Word], error)
+	Update(ctx context.Context, word *models.Word) error
+	Delete(ctx context.Context, id uint) error
+	GetStudyStats(ctx context.Context, wordID uint) (correctCount int64, wrongCount int64, err error)
+	RecomputeWordStats(ctx context.Context) (int64, error)
+	GetAverageAnswerTime(ctx context.Context, wordID uint) (*float64, error)
+	GetWordsByGroup(ctx context.Context, groupID uint, params PaginationParams, opts WordListOptions) (*PaginatedResult[models.Word], error)
+	GetWordsByGroupRaw(ctx context.Context, groupID uint) ([]models.Word, error)
+	GetTotalWordCount(ctx context.Context) (int64, error)
+	GetStudiedWordCount(ctx context.Context) (int64, error)
+	GetByTerm(ctx context.Context, term string) (*models.Word, error)
+	GetDueWords(ctx context.Context, asOf time.Time, limit int) ([]models.Word, error)
+	Search(ctx context.Context, query string, limit int) ([]models.Word, error)
+	FindWords(ctx context.Context, filter WordFilter) ([]models.Word, error)
+	GetReviewedParts(ctx context.Context, minWords, limit int) ([]string, error)
+	ListPartsOfSpeech(ctx context.Context) ([]PartOfSpeechSummary, error)
+	CountWordsByProgressState(ctx context.Context) (map[string]int64, error)
+	GetWordVectors(ctx context.Context, model string) ([]models.WordVector, error)
+	SaveWordVectors(ctx context.Context, vectors []models.WordVector) error
+	CountDueByDay(ctx context.Context, until time.Time) ([]DueDay, error)
+	ListAfter(ctx context.Context, afterID uint, limit int) ([]models.Word, error)
 }
 
 // GroupRepositoryInterface defines the interface for group repository operations.
 // Add methods as they are identified from GroupService usage.
 type GroupRepositoryInterface interface {
-	Create(group *models.Group) error
-	GetByID(id uint) (*models.Group, error)
-	GetByName(name string) (*models.Group, error)
-	List(params PaginationParams) (*PaginatedResult[models.Group], error)
-	Update(group *models.Group) error
-	Delete(id uint) error
-	AddWord(groupID, wordID uint) error
-	RemoveWord(groupID, wordID uint) error
-	GetStudyStats(id uint) (totalSessions, totalReviews, correctReviews int, err error) // Matches method in actual repo
-	GetGroupsByWord(wordID uint, params PaginationParams) (*PaginatedResult[models.Group], error)
-	GetTotalGroupCount() (int64, error)
-	GetActiveGroupCount() (int64, error) // Added from GroupRepository
+	Create(ctx context.Context, group *models.Group) error
+	GetByID(ctx context.Context, id uint) (*models.Group, error)
+	GetSummary(ctx context.Context, id uint) (*GroupSummary, error)
+	GetByName(ctx context.Context, name, language string) (*models.Group, error)
+	GetByShareSlug(ctx context.Context, slug string) (*models.Group, error)
+	SetShareSlug(ctx context.Context, id uint, slug *string) error
+	List(ctx context.Context, params PaginationParams, opts GroupListOptions) (*PaginatedResult[GroupSummary], error)
+	ListAll(ctx context.Context) ([]models.Group, error)
+	Update(ctx context.Context, group *models.Group) error
+	Delete(ctx context.Context, id uint) error
+	AddWord(ctx context.Context, groupID, wordID uint) error
+	CreateWithWords(ctx context.Context, group *models.Group, wordIDs []uint) error
+	RemoveWord(ctx context.Context, groupID, wordID uint) error
+	ReplaceWords(ctx context.Context, groupID uint, wordIDs []uint) error
+	GetStudyStats(ctx context.Context, id uint) (totalSessions, totalReviews, correctReviews int, err error) // Matches method in actual repo
+	GetWordStats(ctx context.Context, groupID uint) ([]GroupWordStats, error)
+	GetGroupsByWord(ctx context.Context, wordID uint, params PaginationParams) (*PaginatedResult[GroupSummary], error)
+	GetTotalGroupCount(ctx context.Context) (int64, error)
+	GetActiveGroupCount(ctx context.Context) (int64, error) // Added from GroupRepository
+	Search(ctx context.Context, query string, limit int) ([]models.Group, error)
 }
 
 // StudyRepositoryInterface defines the interface for study repository operations.
 // Add methods as they are identified from StudyService usage.
 type StudyRepositoryInterface interface {
-	CreateStudyActivity(activity *models.StudyActivity) error
-	GetStudyActivityByID(id uint) (*models.StudyActivity, error)
-	ListStudyActivities(params PaginationParams) (*PaginatedResult[models.StudyActivity], error)
+	CreateStudyActivity(ctx context.Context, activity *models.StudyActivity) error
+	GetStudyActivityByID(ctx context.Context, id uint) (*models.StudyActivity, error)
+	GetStudyActivityByName(ctx context.Context, name string) (*models.StudyActivity, error)
+	ListStudyActivities(ctx context.Context, params PaginationParams) (*PaginatedResult[models.StudyActivity], error)
+	ListAllStudyActivities(ctx context.Context) ([]models.StudyActivity, error)
+	SearchStudyActivities(ctx context.Context, query string, limit int) ([]models.StudyActivity, error)
+	UpsertStudyActivities(ctx context.Context, activities []models.StudyActivity) (created, updated int, err error)
+
+	CreateStudySession(ctx context.Context, session *models.StudySession) error
+	GetStudySessionByID(ctx context.Context, id uint) (*models.StudySession, error)
+	ListStudySessions(ctx context.Context, params PaginationParams) (*PaginatedResult[models.StudySession], error)
+	GetStudySessionsByGroup(ctx context.Context, groupID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error)
+	GetStudySessionsByActivity(ctx context.Context, activityID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error)
+	GetGroupStudySessions(ctx context.Context, groupID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error)
+	CompleteStudySession(ctx context.Context, id uint) (bool, error)
+	GetActiveStudySession(ctx context.Context, learner string) (*models.StudySession, error)
+	ExpireStaleSessions(ctx context.Context, idleSince, at time.Time) (int64, error)
+	GetSessionReviewStats(ctx context.Context, sessionID uint) (totalReviews, correctReviews int64, err error)
+	GetGroupSessionReviewStats(ctx context.Context, sessionID, groupID uint) (totalReviews, correctReviews int64, err error)
+	CreateSessionWords(ctx context.Context, sessionID uint, wordIDs []uint) error
+	GetSessionQueue(ctx context.Context, sessionID uint) ([]SessionQueueItem, error)
+
+	AddWordReview(ctx context.Context, review *models.WordReview) error
+	AddActivityReviewBatch(ctx context.Context, batch *models.ActivityReviewBatch, reviews []models.WordReview) error
+	GetWordReviewsBySession(ctx context.Context, sessionID uint, params PaginationParams) (*PaginatedResult[models.WordReview], error)
+
+	GetLastStudySession(ctx context.Context) (*models.StudySession, error)
+	GetStudyStats(ctx context.Context) (totalSessions, totalReviews, correctReviews int64, err error)
+	GetStudyStreak(ctx context.Context) (int, error)
+	GetActiveGroups(ctx context.Context) (int64, error)
+	GetDeviceStudyStats(ctx context.Context) ([]DeviceStudyStats, error)
+	GetPartStudyStats(ctx context.Context) ([]PartStudyStats, error)
+	GetLanguageStudyStats(ctx context.Context) ([]LanguageStudyStats, error)
+	GetProgressHistory(ctx context.Context, interval string, since time.Time) ([]ProgressBucket, error)
+	GetOpenMistakes(ctx context.Context, since time.Time) ([]models.WordReview, error)
+	CountStudyHistory(ctx context.Context) (map[string]int64, error)
+	ResetStudyHistory(ctx context.Context, guard ResetGuard) error
+	CountAllData(ctx context.Context) (map[string]int64, error)
+	ResetAllData(ctx context.Context, guard ResetGuard) error
+}
+
+// AuditRepositoryInterface defines the interface for audit repository operations.
+type AuditRepositoryInterface interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filter AuditFilter, params PaginationParams) (*PaginatedResult[models.AuditEntry], error)
+}
+
+// ShareRepositoryInterface defines the interface for stats share repository operations.
+type ShareRepositoryInterface interface {
+	Create(ctx context.Context, share *models.StatsShare) error
+	GetByToken(ctx context.Context, token string) (*models.StatsShare, error)
+	DeleteByToken(ctx context.Context, token string) error
+}
+
+// SettingRepositoryInterface defines the interface for setting repository operations.
+type SettingRepositoryInterface interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string) error
+}
+
+// GoalRepositoryInterface defines the interface for group goal repository operations.
+type GoalRepositoryInterface interface {
+	GetGoal(ctx context.Context, groupID uint) (*models.GroupGoal, error)
+	SetGoal(ctx context.Context, goal *models.GroupGoal) error
+	DeleteGoal(ctx context.Context, groupID uint) error
+	GetRecentSessionAccuracy(ctx context.Context, groupID uint, limit int) ([]SessionAccuracy, error)
+	CreateCertificate(ctx context.Context, certificate *models.GroupCertificate) error
+	GetLatestCertificate(ctx context.Context, groupID uint) (*models.GroupCertificate, error)
+	GetCertificateByToken(ctx context.Context, token string) (*models.GroupCertificate, error)
+}
+
+// KanjiRepositoryInterface defines the interface for kanji repository operations.
+type KanjiRepositoryInterface interface {
+	List(ctx context.Context, params PaginationParams, opts KanjiListOptions) (*PaginatedResult[KanjiSummary], error)
+	GetByCharacter(ctx context.Context, character string, opts KanjiListOptions) (*KanjiSummary, error)
+	Update(ctx context.Context, kanji *models.Kanji) error
+	GetWords(ctx context.Context, kanjiID uint, params PaginationParams) (*PaginatedResult[models.Word], error)
+	LinkUnlinkedWords(ctx context.Context) (int, error)
+}
+
+// SentenceRepositoryInterface defines the interface for example sentence repository operations.
+type SentenceRepositoryInterface interface {
+	Create(ctx context.Context, sentence *models.ExampleSentence) error
+	ListByWord(ctx context.Context, wordID uint) ([]models.ExampleSentence, error)
+	ListByGroup(ctx context.Context, groupID uint) ([]models.ExampleSentence, error)
+	Delete(ctx context.Context, wordID, id uint) error
+}
+
+// ArchiveRepositoryInterface defines the interface for account archive operations.
+type ArchiveRepositoryInterface interface {
+	Dump(ctx context.Context) (*ArchiveData, error)
+	CountRestore(ctx context.Context) (map[string]int64, error)
+	Restore(ctx context.Context, data *ArchiveData, guard ResetGuard) error
+}
 
-	CreateStudySession(session *models.StudySession) error
-	GetStudySessionByID(id uint) (*models.StudySession, error)
-	ListStudySessions(params PaginationParams) (*PaginatedResult[models.StudySession], error)
-	GetStudySessionsByGroup(groupID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error)
-	GetStudySessionsByActivity(activityID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error)
+// OutboxRepositoryInterface defines the interface for outbox repository operations.
+type OutboxRepositoryInterface interface {
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id uint, at time.Time) error
+	MarkFailed(ctx context.Context, id uint, cause string, failedAt time.Time, retryAt *time.Time) error
+	PruneSettled(ctx context.Context, before time.Time) (int64, error)
+}
+
+// APIKeyRepositoryInterface defines the interface for API key repository operations.
+type APIKeyRepositoryInterface interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	List(ctx context.Context) ([]models.APIKey, error)
+	GetByID(ctx context.Context, id uint) (*models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+// TutorRepositoryInterface defines the interface for tutor conversation repository operations.
+type TutorRepositoryInterface interface {
+	CreateConversation(ctx context.Context, conversation *models.Conversation) error
+	GetConversation(ctx context.Context, id uint) (*models.Conversation, error)
+	ListConversations(ctx context.Context, user string, params PaginationParams) (*PaginatedResult[models.Conversation], error)
+	AddExchange(ctx context.Context, conversationID uint, message, reply *models.Message) error
+	DeleteConversation(ctx context.Context, id uint) error
+}
 
-	AddWordReview(review *models.WordReview) error
-	GetWordReviewsBySession(sessionID uint, params PaginationParams) (*PaginatedResult[models.WordReview], error)
+// PromptRepositoryInterface defines the interface for prompt template repository operations.
+type PromptRepositoryInterface interface {
+	GetLatest(ctx context.Context, name string) (*models.PromptTemplate, error)
+	GetVersion(ctx context.Context, name string, version int) (*models.PromptTemplate, error)
+	ListVersions(ctx context.Context, name string) ([]models.PromptTemplate, error)
+	AddVersion(ctx context.Context, template *models.PromptTemplate) error
+}
+
+// AIUsageRepositoryInterface defines the interface for AI usage repository operations.
+type AIUsageRepositoryInterface interface {
+	Record(ctx context.Context, usage *models.AIUsage) error
+	Reserve(ctx context.Context, usage *models.AIUsage, limit AIUsageLimit) (bool, error)
+	Release(ctx context.Context, usage *models.AIUsage) error
+	TotalsSince(ctx context.Context, user string, since time.Time) (*AIUsageTotals, error)
+	Summarize(ctx context.Context, filter AIUsageFilter) ([]AIUsageSummary, error)
+}
+
+// CounterRepositoryInterface defines the interface for counter drill repository operations.
+type CounterRepositoryInterface interface {
+	CreateReview(ctx context.Context, review *models.CounterReview) error
+	GetStats(ctx context.Context) ([]CounterStats, error)
+}
 
-	GetLastStudySession() (*models.StudySession, error)
-	GetStudyStats() (totalSessions, totalReviews, correctReviews int64, err error)
-	GetStudyStreak() (int, error)
-	GetActiveGroups() (int64, error)
-	ResetStudyHistory() error
+// CohortRepositoryInterface defines the interface for cohort repository operations.
+type CohortRepositoryInterface interface {
+	Create(ctx context.Context, cohort *models.Cohort) error
+	GetByID(ctx context.Context, id uint) (*models.Cohort, error)
+	ListByOwner(ctx context.Context, owner string) ([]models.Cohort, error)
+	Delete(ctx context.Context, id uint) error
+	AddMember(ctx context.Context, membership *models.CohortMembership) error
+	UpdateMember(ctx context.Context, membership *models.CohortMembership) error
+	RemoveMember(ctx context.Context, cohortID uint, learner string) error
+	AddGroup(ctx context.Context, cohortID, groupID uint) error
+	RemoveGroup(ctx context.Context, cohortID, groupID uint) error
+	GetLearnerStats(ctx context.Context, cohortID uint, since time.Time) ([]LearnerStudyStats, error)
+	GetLearnerStudyDays(ctx context.Context, cohortID uint, since time.Time) ([]LearnerStudyDay, error)
 }
+
+// XPRepositoryInterface defines the interface for experience point repository operations.
+type XPRepositoryInterface interface {
+	Award(ctx context.Context, award *models.XPAward, level func(xp int64) int) (*models.LearnerXP, bool, error)
+	Get(ctx context.Context, learner string) (*models.LearnerXP, error)
+	SessionTotal(ctx context.Context, learner string, sessionID uint) (int64, error)
+}
+
+// NotificationRepositoryInterface defines the interface for notification repository operations.
+type NotificationRepositoryInterface interface {
+	Create(ctx context.Context, notification *models.Notification) (bool, error)
+	List(ctx context.Context, recipient string, unreadOnly bool, params PaginationParams) (*PaginatedResult[models.Notification], error)
+	CountUnread(ctx context.Context, recipient string) (int64, error)
+	MarkRead(ctx context.Context, recipient string, id uint, at time.Time) (*models.Notification, error)
+	MarkAllRead(ctx context.Context, recipient string, at time.Time) (int64, error)
+	PruneRead(ctx context.Context, before time.Time) (int64, error)
+	GetStudyDays(ctx context.Context, since time.Time) ([]LearnerStudyDay, error)
+	CountDueWords(ctx context.Context, before time.Time) (int64, error)
+	ListLevels(ctx context.Context) ([]models.LearnerXP, error)
+	GetPreference(ctx context.Context, learner string) (*models.NotificationPreference, error)
+	SavePreference(ctx context.Context, preference *models.NotificationPreference) error
+	Unsubscribe(ctx context.Context, token string) error
+	ListEmailSubscribers(ctx context.Context) ([]models.NotificationPreference, error)
+	MarkEmailed(ctx context.Context, learner string, at time.Time) error
+	GetStudySince(ctx context.Context, learner string, since time.Time) (*LearnerStudyStats, error)
+}
+
+// DailySummaryRepositoryInterface defines the interface for daily summary repository operations.
+type DailySummaryRepositoryInterface interface {
+	Compute(ctx context.Context, from, to time.Time) (int, error)
+	List(ctx context.Context, learner, from, to string) ([]models.DailySummary, error)
+	LastDay(ctx context.Context) (string, error)
+	FirstReviewDay(ctx context.Context) (string, error)
+}
+
+// ReviewArchiveRepositoryInterface defines the interface for review archive repository operations.
+type ReviewArchiveRepositoryInterface interface {
+	Archive(ctx context.Context, before, at time.Time) (int64, error)
+}
+
+// The services depend only on the interfaces above, so that they can be tested
+// with mocks; these assertions keep the repositories implementing them.
+var (
+	_ WordRepositoryInterface          = (*WordRepository)(nil)
+	_ GroupRepositoryInterface         = (*GroupRepository)(nil)
+	_ StudyRepositoryInterface         = (*StudyRepository)(nil)
+	_ AuditRepositoryInterface         = (*AuditRepository)(nil)
+	_ ShareRepositoryInterface         = (*ShareRepository)(nil)
+	_ SettingRepositoryInterface       = (*SettingRepository)(nil)
+	_ GoalRepositoryInterface          = (*GoalRepository)(nil)
+	_ KanjiRepositoryInterface         = (*KanjiRepository)(nil)
+	_ SentenceRepositoryInterface      = (*SentenceRepository)(nil)
+	_ ArchiveRepositoryInterface       = (*ArchiveRepository)(nil)
+	_ OutboxRepositoryInterface        = (*OutboxRepository)(nil)
+	_ APIKeyRepositoryInterface        = (*APIKeyRepository)(nil)
+	_ TutorRepositoryInterface         = (*TutorRepository)(nil)
+	_ PromptRepositoryInterface        = (*PromptRepository)(nil)
+	_ AIUsageRepositoryInterface       = (*AIUsageRepository)(nil)
+	_ CounterRepositoryInterface       = (*CounterRepository)(nil)
+	_ CohortRepositoryInterface        = (*CohortRepository)(nil)
+	_ XPRepositoryInterface            = (*XPRepository)(nil)
+	_ NotificationRepositoryInterface  = (*NotificationRepository)(nil)
+	_ DailySummaryRepositoryInterface  = (*DailySummaryRepository)(nil)
+	_ ReviewArchiveRepositoryInterface = (*ReviewArchiveRepository)(nil)
+)
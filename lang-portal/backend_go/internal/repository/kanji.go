@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WordKanji represents the many-to-many relationship between words and the
+// kanji in their Japanese text
+type WordKanji struct {
+	WordID  uint `gorm:"primaryKey"`
+	KanjiID uint `gorm:"primaryKey"`
+}
+
+// TableName specifies the table name for the WordKanji model
+func (WordKanji) TableName() string {
+	return "word_kanji"
+}
+
+// KanjiRepository handles database operations for kanji
+type KanjiRepository struct {
+	*BaseRepository
+}
+
+// NewKanjiRepository creates a new kanji repository
+func NewKanjiRepository(db *gorm.DB) *KanjiRepository {
+	return &KanjiRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// KanjiListOptions filters and orders a kanji list and configures its aggregates
+type KanjiListOptions struct {
+	// JLPTLevel keeps only kanji of that level when non-zero
+	JLPTLevel int
+	// Sort is by one of the kanji sort fields; the zero value puts the kanji
+	// found in the most words first
+	Sort                   SortParams
+	MasteredMinReviews     int
+	MasteredMinSuccessRate float64
+}
+
+// Kanji list sort fields
+const (
+	KanjiSortCharacter     = "character"
+	KanjiSortStrokeCount   = "stroke_count"
+	KanjiSortJLPTLevel     = "jlpt_level"
+	KanjiSortWordCount     = "word_count"
+	KanjiSortMasteredCount = "mastered_count"
+)
+
+// kanjiSortColumns maps each sort field to the expression it orders by
+var kanjiSortColumns = map[string]string{
+	KanjiSortCharacter:     "kanji.character",
+	KanjiSortStrokeCount:   "kanji.stroke_count",
+	KanjiSortJLPTLevel:     "kanji.jlpt_level",
+	KanjiSortWordCount:     "word_count",
+	KanjiSortMasteredCount: "mastered_count",
+}
+
+// ValidKanjiSortField reports whether field is a supported kanji sort field
+func ValidKanjiSortField(field string) bool {
+	_, ok := kanjiSortColumns[field]
+	return ok
+}
+
+// KanjiSummary is a kanji with the study progress of the words containing it
+type KanjiSummary struct {
+	models.Kanji
+	WordCount int64
+	// StudiedCount is the number of words reviewed at least once
+	StudiedCount  int64
+	MasteredCount int64
+	CorrectCount  int64
+	WrongCount    int64
+}
+
+// kanjiSummarySelect aggregates the words linked to each kanji. It takes the
+// mastery thresholds as arguments.
+const kanjiSummarySelect = `kanji.*,
+	COUNT(words.id) AS word_count,
+	COUNT(word_stats.word_id) AS studied_count,
+	COALESCE(SUM(CASE WHEN ` + wordMastered + ` THEN 1 ELSE 0 END), 0) AS mastered_count,
+	COALESCE(SUM(word_stats.correct_count), 0) AS correct_count,
+	COALESCE(SUM(word_stats.wrong_count), 0) AS wrong_count`
+
+func (r *KanjiRepository) summaries(ctx context.Context, opts KanjiListOptions) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&models.Kanji{}).
+		Select(kanjiSummarySelect, opts.MasteredMinReviews, opts.MasteredMinSuccessRate).
+		Joins("LEFT JOIN word_kanji ON word_kanji.kanji_id = kanji.id").
+		Joins("LEFT JOIN words ON words.id = word_kanji.word_id").
+		Joins(wordStatsJoin).
+		Group("kanji.id")
+}
+
+// List retrieves a paginated list of kanji with their progress, the kanji
+// found in the most words first
+func (r *KanjiRepository) List(ctx context.Context, params PaginationParams, opts KanjiListOptions) (*PaginatedResult[KanjiSummary], error) {
+	filter := func(query *gorm.DB) *gorm.DB {
+		if opts.JLPTLevel != 0 {
+			query = query.Where("kanji.jlpt_level = ?", opts.JLPTLevel)
+		}
+		return query
+	}
+
+	var total int64
+	if err := filter(r.db.WithContext(ctx).Model(&models.Kanji{})).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	var kanji []KanjiSummary
+	query, ok := opts.Sort.order(filter(r.summaries(ctx, opts)), kanjiSortColumns, "kanji.id ASC")
+	if !ok {
+		query = query.Order("word_count DESC, kanji.id ASC")
+	}
+	if err := query.
+		Offset(offset).Limit(params.PageSize).
+		Scan(&kanji).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(total) + params.PageSize - 1) / params.PageSize
+	return &PaginatedResult[KanjiSummary]{
+		Items:      kanji,
+		TotalItems: total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetByCharacter retrieves a kanji with its progress
+func (r *KanjiRepository) GetByCharacter(ctx context.Context, character string, opts KanjiListOptions) (*KanjiSummary, error) {
+	var kanji []KanjiSummary
+	if err := r.summaries(ctx, opts).Where("kanji.character = ?", character).Scan(&kanji).Error; err != nil {
+		return nil, err
+	}
+	if len(kanji) == 0 {
+		return nil, ErrNotFound
+	}
+	return &kanji[0], nil
+}
+
+// Update stores the dictionary data of an existing kanji
+func (r *KanjiRepository) Update(ctx context.Context, kanji *models.Kanji) error {
+	if err := kanji.Validate(); err != nil {
+		return ErrInvalidInput
+	}
+	return r.db.WithContext(ctx).Save(kanji).Error
+}
+
+// GetWords retrieves the words containing a kanji
+func (r *KanjiRepository) GetWords(ctx context.Context, kanjiID uint, params PaginationParams) (*PaginatedResult[models.Word], error) {
+	query := r.db.WithContext(ctx).Model(&models.Word{}).
+		Joins("JOIN word_kanji ON word_kanji.word_id = words.id").
+		Where("word_kanji.kanji_id = ?", kanjiID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	var words []models.Word
+	if err := query.Preload("Progress").Order("words.id ASC").Offset(offset).Limit(params.PageSize).Find(&words).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(total) + params.PageSize - 1) / params.PageSize
+	return &PaginatedResult[models.Word]{
+		Items:      words,
+		TotalItems: total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// LinkUnlinkedWords links the words that have no kanji links yet to the kanji
+// in their Japanese text, covering words written without the word repository,
+// such as seed data. It returns the number of words linked.
+func (r *KanjiRepository) LinkUnlinkedWords(ctx context.Context) (int, error) {
+	var words []models.Word
+	if err := r.db.WithContext(ctx).Select("id", "term").
+		Where("id NOT IN (SELECT word_id FROM word_kanji)").
+		Find(&words).Error; err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		for i := range words {
+			if len(models.KanjiIn(words[i].Term)) == 0 {
+				continue
+			}
+			if err := linkWordKanji(tx, &words[i]); err != nil {
+				return err
+			}
+			linked++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return linked, nil
+}
+
+// linkWordKanji replaces the kanji links of a word with the kanji in its
+// Japanese text, creating kanji rows that do not exist yet. Words of other
+// languages are not linked, as the kanji data is Japanese.
+func linkWordKanji(tx *gorm.DB, word *models.Word) error {
+	if err := tx.Where("word_id = ?", word.ID).Delete(&WordKanji{}).Error; err != nil {
+		return err
+	}
+	if word.Language != "" && word.Language != models.LanguageJapanese {
+		return nil
+	}
+	characters := models.KanjiIn(word.Term)
+	if len(characters) == 0 {
+		return nil
+	}
+
+	rows := make([]models.Kanji, len(characters))
+	for i, character := range characters {
+		rows[i] = models.Kanji{Character: character}
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "character"}},
+		DoNothing: true,
+	}).Create(&rows).Error; err != nil {
+		return err
+	}
+
+	var ids []uint
+	if err := tx.Model(&models.Kanji{}).Where("character IN ?", characters).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	links := make([]WordKanji, len(ids))
+	for i, id := range ids {
+		links[i] = WordKanji{WordID: word.ID, KanjiID: id}
+	}
+	return tx.Create(&links).Error
+}
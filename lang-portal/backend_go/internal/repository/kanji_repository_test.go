@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+)
+
+func TestKanjiRepository_LinksWords(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	wordRepo := NewWordRepository(db)
+	repo := NewKanjiRepository(db)
+	ctx := context.Background()
+
+	university := &models.Word{Term: "大学", Reading: "daigaku", English: "university", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, wordRepo.Create(ctx, university))
+	big := &models.Word{Term: "大きい", Reading: "ookii", English: "big", Parts: models.StringSlice{"adjective"}}
+	require.NoError(t, wordRepo.Create(ctx, big))
+
+	result, err := repo.List(ctx, PaginationParams{Page: 1, PageSize: 10}, KanjiListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.TotalItems)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, "大", result.Items[0].Character)
+	assert.Equal(t, int64(2), result.Items[0].WordCount)
+	assert.Equal(t, "学", result.Items[1].Character)
+	assert.Equal(t, int64(1), result.Items[1].WordCount)
+
+	sorted, err := repo.List(ctx, PaginationParams{Page: 1, PageSize: 10}, KanjiListOptions{Sort: SortParams{Field: KanjiSortWordCount}})
+	require.NoError(t, err)
+	require.Len(t, sorted.Items, 2)
+	assert.Equal(t, "学", sorted.Items[0].Character)
+
+	// Changing the Japanese text relinks the word
+	university.Term = "学生"
+	require.NoError(t, wordRepo.Update(ctx, university))
+	kanji, err := repo.GetByCharacter(ctx, "大", KanjiListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), kanji.WordCount)
+
+	words, err := repo.GetWords(ctx, kanji.ID, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, words.Items, 1)
+	assert.Equal(t, big.ID, words.Items[0].ID)
+
+	require.NoError(t, wordRepo.Delete(ctx, big.ID))
+	kanji, err = repo.GetByCharacter(ctx, "大", KanjiListOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, kanji.WordCount)
+
+	_, err = repo.GetByCharacter(ctx, "猫", KanjiListOptions{})
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestKanjiRepository_Progress(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewKanjiRepository(db)
+	ctx := context.Background()
+
+	// Written directly, as seed data is, so the words start unlinked
+	eat := &models.Word{Term: "食べる", Reading: "taberu", English: "to eat", Parts: models.StringSlice{"verb"}}
+	food := &models.Word{Term: "食べ物", Reading: "tabemono", English: "food", Parts: models.StringSlice{"noun"}}
+	kana := testutil.CreateTestWord(t, db)
+	require.NoError(t, db.Create(eat).Error)
+	require.NoError(t, db.Create(food).Error)
+
+	linked, err := repo.LinkUnlinkedWords(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, linked)
+	linked, err = repo.LinkUnlinkedWords(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, linked)
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	for _, review := range []models.WordReview{
+		{WordID: eat.ID, Correct: true},
+		{WordID: eat.ID, Correct: true},
+		{WordID: food.ID, Correct: false},
+		{WordID: kana.ID, Correct: true},
+	} {
+		review.StudySessionID = session.ID
+		require.NoError(t, db.Create(&review).Error)
+	}
+	_, err = NewWordRepository(db).RecomputeWordStats(ctx)
+	require.NoError(t, err)
+
+	kanji, err := repo.GetByCharacter(ctx, "食", KanjiListOptions{MasteredMinReviews: 2, MasteredMinSuccessRate: 0.8})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), kanji.WordCount)
+	assert.Equal(t, int64(2), kanji.StudiedCount)
+	assert.Equal(t, int64(1), kanji.MasteredCount)
+	assert.Equal(t, int64(2), kanji.CorrectCount)
+	assert.Equal(t, int64(1), kanji.WrongCount)
+
+	kanji.JLPTLevel = 5
+	kanji.Meanings = models.StringSlice{"eat", "food"}
+	require.NoError(t, repo.Update(ctx, &kanji.Kanji))
+	result, err := repo.List(ctx, PaginationParams{Page: 1, PageSize: 10}, KanjiListOptions{JLPTLevel: 5})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, models.StringSlice{"eat", "food"}, result.Items[0].Meanings)
+
+	kanji.StrokeCount = -1
+	assert.Equal(t, ErrInvalidInput, repo.Update(ctx, &kanji.Kanji))
+}
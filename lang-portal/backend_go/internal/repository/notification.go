@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository handles database operations for the in-app inbox and
+// reads the study the notification job looks at
+type NotificationRepository struct {
+	*BaseRepository
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// Create stores a notification unless its recipient already has one with the
+// same key, and reports whether it was stored
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipient"}, {Name: "key"}},
+		DoNothing: true,
+	}).Create(notification)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// List retrieves a page of a recipient's notifications, newest first,
+// optionally only the unread ones
+func (r *NotificationRepository) List(ctx context.Context, recipient string, unreadOnly bool, params PaginationParams) (*PaginatedResult[models.Notification], error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("recipient = ?", recipient)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	paginatedQuery, err := r.Paginate(query.Order("created_at DESC, id DESC"), params)
+	if err != nil {
+		return nil, err
+	}
+	if err := paginatedQuery.Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(total) + params.PageSize - 1) / params.PageSize
+	return &PaginatedResult[models.Notification]{
+		Items:      notifications,
+		TotalItems: total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// CountUnread counts a recipient's unread notifications
+func (r *NotificationRepository) CountUnread(ctx context.Context, recipient string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("recipient = ? AND read_at IS NULL", recipient).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of a recipient's notifications read at the given time and
+// returns it. Marking a read notification again keeps its first read time.
+// Other recipients' notifications are ErrNotFound.
+func (r *NotificationRepository) MarkRead(ctx context.Context, recipient string, id uint, at time.Time) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND recipient = ?", id, recipient).First(&notification).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrNotFound
+			}
+			return err
+		}
+		if notification.ReadAt != nil {
+			return nil
+		}
+		notification.ReadAt = &at
+		return tx.Model(&notification).Update("read_at", at).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllRead marks a recipient's unread notifications read at the given time
+// and returns how many there were
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, recipient string, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("recipient = ? AND read_at IS NULL", recipient).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// PruneRead deletes the notifications read before the given time
+func (r *NotificationRepository) PruneRead(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("read_at IS NOT NULL AND read_at < ?", before).
+		Delete(&models.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+// GetStudyDays returns the days since the given time on which each learner
+// started sessions, by learner and newest day first. Sessions without a
+// learner are left out.
+func (r *NotificationRepository) GetStudyDays(ctx context.Context, since time.Time) ([]LearnerStudyDay, error) {
+	var days []LearnerStudyDay
+	err := r.db.WithContext(ctx).Model(&models.StudySession{}).
+		Select("DISTINCT learner, date(created_at) AS day").
+		Where("learner <> '' AND julianday(created_at) >= julianday(?)", since).
+		Order("learner ASC, day DESC").
+		Scan(&days).Error
+	return days, err
+}
+
+// CountDueWords counts the words whose next review is due before the given time
+func (r *NotificationRepository) CountDueWords(ctx context.Context, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Word{}).
+		Where("next_due_at IS NOT NULL AND next_due_at < ?", before).
+		Count(&count).Error
+	return count, err
+}
+
+// ListLevels returns the experience of the learners above the first level
+func (r *NotificationRepository) ListLevels(ctx context.Context) ([]models.LearnerXP, error) {
+	var levels []models.LearnerXP
+	err := r.db.WithContext(ctx).Where("level > 1").Order("learner ASC").Find(&levels).Error
+	return levels, err
+}
+
+// GetPreference retrieves a learner's reminder settings, or returns ErrNotFound
+// when they have not set any
+func (r *NotificationRepository) GetPreference(ctx context.Context, learner string) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	if err := r.db.WithContext(ctx).Where("learner = ?", learner).First(&preference).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// SavePreference creates or replaces a learner's reminder settings, keeping
+// when they were last emailed
+func (r *NotificationRepository) SavePreference(ctx context.Context, preference *models.NotificationPreference) error {
+	preference.UpdatedAt = time.Now().UTC()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "learner"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "email_frequency", "unsubscribe_token", "updated_at"}),
+	}).Create(preference).Error
+}
+
+// Unsubscribe turns off the reminder emails of the learner with the given
+// unsubscribe token, or returns ErrNotFound for an unknown token
+func (r *NotificationRepository) Unsubscribe(ctx context.Context, token string) error {
+	result := r.db.WithContext(ctx).Model(&models.NotificationPreference{}).
+		Where("unsubscribe_token = ?", token).
+		Updates(map[string]interface{}{
+			"email_frequency": models.EmailFrequencyOff,
+			"updated_at":      time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListEmailSubscribers returns the settings of the learners who get reminder
+// emails
+func (r *NotificationRepository) ListEmailSubscribers(ctx context.Context) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("email_frequency <> ? AND email <> ''", models.EmailFrequencyOff).
+		Order("learner ASC").
+		Find(&preferences).Error
+	return preferences, err
+}
+
+// MarkEmailed records when a learner was last sent a reminder email
+func (r *NotificationRepository) MarkEmailed(ctx context.Context, learner string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.NotificationPreference{}).
+		Where("learner = ?", learner).
+		UpdateColumn("last_emailed_at", at).Error
+}
+
+// GetStudySince aggregates the sessions a learner started since the given time
+// and their reviews. LastStudiedAt is left zero.
+func (r *NotificationRepository) GetStudySince(ctx context.Context, learner string, since time.Time) (*LearnerStudyStats, error) {
+	stats := LearnerStudyStats{Learner: learner}
+	err := r.db.WithContext(ctx).Table("study_sessions").
+		Select(`COUNT(DISTINCT study_sessions.id) AS sessions,
+			COUNT(word_review_items.id) AS reviews,
+			COALESCE(SUM(CASE WHEN word_review_items.correct THEN 1 ELSE 0 END), 0) AS correct_reviews`).
+		Joins("LEFT JOIN word_review_items ON word_review_items.study_session_id = study_sessions.id").
+		Where("study_sessions.learner = ? AND julianday(study_sessions.created_at) >= julianday(?)", learner, since).
+		Row().Scan(&stats.Sessions, &stats.Reviews, &stats.CorrectReviews)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRepository_Inbox(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewNotificationRepository(db)
+	ctx := context.Background()
+
+	for _, key := range []string{"due:2025-03-09", "due:2025-03-10"} {
+		created, err := repo.Create(ctx, &models.Notification{Recipient: "yuki", Kind: models.NotificationKindReviewsDue, Key: key, Title: "Reviews due"})
+		require.NoError(t, err)
+		assert.True(t, created)
+	}
+	created, err := repo.Create(ctx, &models.Notification{Recipient: "yuki", Kind: models.NotificationKindReviewsDue, Key: "due:2025-03-10", Title: "Again"})
+	require.NoError(t, err)
+	assert.False(t, created, "the same key is notified once per recipient")
+	created, err = repo.Create(ctx, &models.Notification{Recipient: "ken", Kind: models.NotificationKindReviewsDue, Key: "due:2025-03-10", Title: "Reviews due"})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	page, err := repo.List(ctx, "yuki", false, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "due:2025-03-10", page.Items[0].Key, "newest first")
+	assert.Equal(t, int64(2), page.TotalItems)
+
+	// Other recipients' notifications cannot be marked
+	_, err = repo.MarkRead(ctx, "ken", page.Items[1].ID, time.Now())
+	assert.Equal(t, ErrNotFound, err)
+
+	readAt := time.Now().UTC().Add(-time.Hour)
+	read, err := repo.MarkRead(ctx, "yuki", page.Items[1].ID, readAt)
+	require.NoError(t, err)
+	require.NotNil(t, read.ReadAt)
+	read, err = repo.MarkRead(ctx, "yuki", page.Items[1].ID, time.Now())
+	require.NoError(t, err)
+	assert.WithinDuration(t, readAt, *read.ReadAt, time.Second, "the first read time is kept")
+
+	unread, err := repo.CountUnread(ctx, "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unread)
+	page, err = repo.List(ctx, "yuki", true, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "due:2025-03-10", page.Items[0].Key)
+
+	marked, err := repo.MarkAllRead(ctx, "yuki", time.Now().UTC())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), marked)
+	unread, err = repo.CountUnread(ctx, "ken")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unread)
+
+	pruned, err := repo.PruneRead(ctx, time.Now().UTC().Add(-30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned, "only the notification read an hour ago is pruned")
+}
+
+func TestNotificationRepository_StudySources(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewNotificationRepository(db)
+	ctx := context.Background()
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	now := time.Now().UTC()
+	for _, session := range []models.StudySession{
+		{Learner: "yuki", CreatedAt: now},
+		{Learner: "yuki", CreatedAt: now},
+		{Learner: "yuki", CreatedAt: now.AddDate(0, 0, -1)},
+		{Learner: "ken", CreatedAt: now.AddDate(0, 0, -40)},
+		{CreatedAt: now},
+	} {
+		session.GroupID, session.StudyActivityID = group.ID, activity.ID
+		require.NoError(t, db.Create(&session).Error)
+	}
+
+	days, err := repo.GetStudyDays(ctx, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	assert.Equal(t, []LearnerStudyDay{
+		{Learner: "yuki", Day: now.Format("2006-01-02")},
+		{Learner: "yuki", Day: now.AddDate(0, 0, -1).Format("2006-01-02")},
+	}, days)
+
+	overdue, later := now.Add(-time.Hour), now.Add(48*time.Hour)
+	for i, dueAt := range []*time.Time{&overdue, &later, nil} {
+		word := &models.Word{Term: string(rune('あ' + i)), Reading: "a", English: "a", Parts: models.StringSlice{"noun"}, NextDueAt: dueAt}
+		require.NoError(t, db.Create(word).Error)
+	}
+	count, err := repo.CountDueWords(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, db.Create(&models.LearnerXP{Learner: "yuki", XP: 150, Level: 2}).Error)
+	require.NoError(t, db.Create(&models.LearnerXP{Learner: "ken", XP: 20, Level: 1}).Error)
+	levels, err := repo.ListLevels(ctx)
+	require.NoError(t, err)
+	require.Len(t, levels, 1)
+	assert.Equal(t, "yuki", levels[0].Learner)
+}
+
+func TestNotificationRepository_Preferences(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewNotificationRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetPreference(ctx, "yuki")
+	assert.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, repo.SavePreference(ctx, &models.NotificationPreference{Learner: "yuki", Email: "yuki@example.com", EmailFrequency: models.EmailFrequencyDaily, UnsubscribeToken: "t1"}))
+	require.NoError(t, repo.SavePreference(ctx, &models.NotificationPreference{Learner: "ken", Email: "ken@example.com", EmailFrequency: models.EmailFrequencyOff, UnsubscribeToken: "t2"}))
+	emailedAt := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.MarkEmailed(ctx, "yuki", emailedAt))
+
+	// Saving again replaces the settings but keeps when the learner was emailed
+	require.NoError(t, repo.SavePreference(ctx, &models.NotificationPreference{Learner: "yuki", Email: "yuki@example.org", EmailFrequency: models.EmailFrequencyWeekly, UnsubscribeToken: "t1"}))
+	preference, err := repo.GetPreference(ctx, "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, "yuki@example.org", preference.Email)
+	assert.Equal(t, models.EmailFrequencyWeekly, preference.EmailFrequency)
+	require.NotNil(t, preference.LastEmailedAt)
+	assert.True(t, emailedAt.Equal(*preference.LastEmailedAt))
+
+	subscribers, err := repo.ListEmailSubscribers(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscribers, 1)
+	assert.Equal(t, "yuki", subscribers[0].Learner)
+
+	require.NoError(t, repo.Unsubscribe(ctx, "t1"))
+	assert.Equal(t, ErrNotFound, repo.Unsubscribe(ctx, "unknown"))
+	subscribers, err = repo.ListEmailSubscribers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, subscribers)
+}
+
+func TestNotificationRepository_GetStudySince(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewNotificationRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	now := time.Now().UTC()
+	for _, at := range []time.Time{now, now.AddDate(0, 0, -3), now.AddDate(0, 0, -10)} {
+		session := &models.StudySession{GroupID: group.ID, StudyActivityID: activity.ID, Learner: "yuki", CreatedAt: at}
+		require.NoError(t, db.Create(session).Error)
+		testutil.CreateTestWordReview(t, db, word.ID, session.ID)
+	}
+
+	stats, err := repo.GetStudySince(ctx, "yuki", now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Sessions)
+	assert.Equal(t, int64(2), stats.Reviews)
+
+	stats, err = repo.GetStudySince(ctx, "ken", now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+	assert.Zero(t, stats.Sessions)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// OutboxRepository reads and settles the study session events recorded in the
+// outbox by the study repository
+type OutboxRepository struct {
+	*BaseRepository
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// ListDue returns up to limit pending events due for an attempt at now, oldest
+// first. The outbox is polled every second, so the query is only logged when it
+// is slow or fails.
+func (r *OutboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	var pending []models.OutboxEvent
+	quiet := r.db.Session(&gorm.Session{Logger: r.db.Logger.LogMode(gormlogger.Warn)})
+	err := quiet.WithContext(ctx).
+		Where("delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now).
+		Order("id").
+		Limit(limit).
+		Find(&pending).Error
+	if err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// MarkDelivered settles an event delivered at the given time
+func (r *OutboxRepository) MarkDelivered(ctx context.Context, id uint, at time.Time) error {
+	return r.settle(ctx, id, map[string]interface{}{
+		"attempts":     gorm.Expr("attempts + 1"),
+		"delivered_at": at,
+	})
+}
+
+// MarkFailed records a failed attempt to deliver an event. The event is tried
+// again at retryAt, or given up at failedAt when retryAt is nil.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uint, cause string, failedAt time.Time, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": cause,
+	}
+	if retryAt != nil {
+		updates["next_attempt_at"] = *retryAt
+	} else {
+		updates["failed_at"] = failedAt
+	}
+	return r.settle(ctx, id, updates)
+}
+
+// PruneSettled deletes the events delivered or given up before the given time
+// and returns how many were deleted
+func (r *OutboxRepository) PruneSettled(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("delivered_at < ? OR failed_at < ?", before, before).
+		Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *OutboxRepository) settle(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// enqueueOutbox records an event about a study session within the transaction
+// of the change it describes. It is due for delivery right away.
+func enqueueOutbox(tx *gorm.DB, eventType string, sessionID uint, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.OutboxEvent{
+		Type:           eventType,
+		StudySessionID: sessionID,
+		Payload:        string(payload),
+		NextAttemptAt:  time.Now(),
+	}).Error
+}
+
+// enqueueSessionCreated records the creation of a study session
+func enqueueSessionCreated(tx *gorm.DB, session *models.StudySession) error {
+	return enqueueOutbox(tx, events.TypeStudySessionCreated, session.ID, map[string]uint{
+		"id":                session.ID,
+		"group_id":          session.GroupID,
+		"study_activity_id": session.StudyActivityID,
+	})
+}
+
+// enqueueReviewCreated records a review added to a study session
+func enqueueReviewCreated(tx *gorm.DB, review *models.WordReview) error {
+	return enqueueOutbox(tx, events.TypeWordReviewCreated, review.StudySessionID, map[string]interface{}{
+		"study_session_id": review.StudySessionID,
+		"word_id":          review.WordID,
+		"correct":          review.Correct,
+		"grade":            review.EffectiveGrade(),
+	})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository_RecordsStudyEvents(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	studyRepo := NewStudyRepository(db)
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+
+	session := &models.StudySession{GroupID: group.ID, StudyActivityID: activity.ID}
+	require.NoError(t, studyRepo.CreateStudySession(ctx, session))
+	require.NoError(t, studyRepo.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}))
+	require.NoError(t, studyRepo.AddActivityReviewBatch(ctx, &models.ActivityReviewBatch{StudySessionID: session.ID, BatchID: "batch-1"}, []models.WordReview{
+		{WordID: word.ID, Correct: false},
+	}))
+
+	// Changes that are rolled back record nothing
+	assert.Equal(t, ErrNotFound, studyRepo.AddWordReview(ctx, &models.WordReview{WordID: word.ID + 100, StudySessionID: session.ID, Correct: true}))
+	assert.Equal(t, ErrNotFound, studyRepo.AddActivityReviewBatch(ctx, &models.ActivityReviewBatch{StudySessionID: session.ID, BatchID: "batch-2"}, []models.WordReview{
+		{WordID: word.ID, Correct: true},
+		{WordID: word.ID + 100, Correct: true},
+	}))
+
+	now := time.Now()
+	due, err := repo.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 3)
+	assert.Equal(t, events.TypeStudySessionCreated, due[0].Type)
+	assert.JSONEq(t, `{"id":1,"group_id":1,"study_activity_id":1}`, due[0].Payload)
+	for _, event := range due[1:] {
+		assert.Equal(t, events.TypeWordReviewCreated, event.Type)
+		assert.Equal(t, session.ID, event.StudySessionID)
+	}
+	assert.JSONEq(t, `{"study_session_id":1,"word_id":1,"correct":false,"grade":"again"}`, due[2].Payload)
+
+	limited, err := repo.ListDue(ctx, now, 1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
+
+func TestOutboxRepository_Settle(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, enqueueOutbox(db, events.TypeStudySessionCreated, 1, map[string]uint{"id": 1}))
+	}
+
+	require.NoError(t, repo.MarkDelivered(ctx, 1, now))
+	retryAt := now.Add(time.Minute)
+	require.NoError(t, repo.MarkFailed(ctx, 2, "unexpected status 503", now, &retryAt))
+	require.NoError(t, repo.MarkFailed(ctx, 3, "unexpected status 410", now, nil))
+	assert.Equal(t, ErrNotFound, repo.MarkDelivered(ctx, 99, now))
+
+	due, err := repo.ListDue(ctx, now.Add(time.Second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due, "the retry is not due yet")
+
+	due, err = repo.ListDue(ctx, retryAt, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, uint(2), due[0].ID)
+	assert.Equal(t, 1, due[0].Attempts)
+	assert.Equal(t, "unexpected status 503", due[0].LastError)
+
+	// Only settled events are pruned
+	pruned, err := repo.PruneSettled(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+
+	var left []models.OutboxEvent
+	require.NoError(t, db.Find(&left).Error)
+	require.Len(t, left, 1)
+	assert.Equal(t, uint(2), left[0].ID)
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UnknownPartsError is returned when a word is tagged with parts of speech
+// that are not in the vocabulary
+type UnknownPartsError struct {
+	Parts []string
+}
+
+func (e *UnknownPartsError) Error() string {
+	return "unknown parts of speech: " + strings.Join(e.Parts, ", ")
+}
+
+// PartOfSpeechSummary is a part of speech with the number of words tagged with it
+type PartOfSpeechSummary struct {
+	Name      string `json:"name"`
+	WordCount int64  `json:"word_count"`
+}
+
+// ListPartsOfSpeech returns the part-of-speech vocabulary in name order
+func (r *WordRepository) ListPartsOfSpeech(ctx context.Context) ([]PartOfSpeechSummary, error) {
+	var parts []PartOfSpeechSummary
+	err := r.db.WithContext(ctx).Model(&models.PartOfSpeech{}).
+		Select("parts_of_speech.name, COUNT(word_parts.word_id) AS word_count").
+		Joins("LEFT JOIN word_parts ON word_parts.part_id = parts_of_speech.id").
+		Group("parts_of_speech.id").
+		Order("parts_of_speech.name ASC").
+		Scan(&parts).Error
+	if err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// LinkUnlinkedParts links the words that have no part links yet to the parts
+// in their Parts, covering words written without the word repository, such as
+// seed data. Parts missing from the vocabulary are added to it. It returns the
+// number of words linked.
+func (r *WordRepository) LinkUnlinkedParts(ctx context.Context) (int, error) {
+	var words []models.Word
+	if err := r.db.WithContext(ctx).Select("id", "parts").
+		Where("id NOT IN (SELECT word_id FROM word_parts)").
+		Find(&words).Error; err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		for i := range words {
+			if len(words[i].Parts) == 0 {
+				continue
+			}
+			if err := addPartsOfSpeech(tx, words[i].Parts); err != nil {
+				return err
+			}
+			if err := linkWordParts(tx, &words[i]); err != nil {
+				return err
+			}
+			linked++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return linked, nil
+}
+
+// addPartsOfSpeech adds the names missing from the part-of-speech vocabulary
+func addPartsOfSpeech(tx *gorm.DB, names []string) error {
+	names = distinctParts(names)
+	if len(names) == 0 {
+		return nil
+	}
+	rows := make([]models.PartOfSpeech, len(names))
+	for i, name := range names {
+		rows[i] = models.PartOfSpeech{Name: name}
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&rows).Error
+}
+
+// linkWordParts replaces the part links of a word with the parts in its Parts.
+// It fails with an UnknownPartsError if any of them is not in the vocabulary.
+func linkWordParts(tx *gorm.DB, word *models.Word) error {
+	if err := tx.Where("word_id = ?", word.ID).Delete(&models.WordPart{}).Error; err != nil {
+		return err
+	}
+	names := distinctParts(word.Parts)
+	if len(names) == 0 {
+		return nil
+	}
+
+	var parts []models.PartOfSpeech
+	if err := tx.Where("name IN ?", names).Find(&parts).Error; err != nil {
+		return err
+	}
+	if len(parts) < len(names) {
+		known := make(map[string]bool, len(parts))
+		for _, part := range parts {
+			known[part.Name] = true
+		}
+		unknown := &UnknownPartsError{}
+		for _, name := range names {
+			if !known[name] {
+				unknown.Parts = append(unknown.Parts, name)
+			}
+		}
+		return unknown
+	}
+
+	links := make([]models.WordPart, len(parts))
+	for i, part := range parts {
+		links[i] = models.WordPart{WordID: word.ID, PartID: part.ID}
+	}
+	return tx.Create(&links).Error
+}
+
+// distinctParts returns the non-empty names in order of first appearance
+func distinctParts(names []string) []string {
+	var distinct []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			distinct = append(distinct, name)
+		}
+	}
+	return distinct
+}
+
+// wordHasPart restricts a words query to the words tagged with part
+func wordHasPart(query *gorm.DB, part string) *gorm.DB {
+	return query.Where(`words.id IN (
+		SELECT word_parts.word_id FROM word_parts
+		JOIN parts_of_speech ON parts_of_speech.id = word_parts.part_id
+		WHERE parts_of_speech.name = ?)`, part)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PromptRepository handles database operations for prompt templates
+type PromptRepository struct {
+	*BaseRepository
+}
+
+// NewPromptRepository creates a new prompt template repository
+func NewPromptRepository(db *gorm.DB) *PromptRepository {
+	return &PromptRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// GetLatest retrieves the latest version of a prompt
+func (r *PromptRepository) GetLatest(ctx context.Context, name string) (*models.PromptTemplate, error) {
+	var template models.PromptTemplate
+	err := r.db.WithContext(ctx).Where("name = ?", name).Order("version DESC").First(&template).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetVersion retrieves a version of a prompt
+func (r *PromptRepository) GetVersion(ctx context.Context, name string, version int) (*models.PromptTemplate, error) {
+	var template models.PromptTemplate
+	err := r.db.WithContext(ctx).Where("name = ? AND version = ?", name, version).First(&template).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ListVersions retrieves every version of a prompt, newest first
+func (r *PromptRepository) ListVersions(ctx context.Context, name string) ([]models.PromptTemplate, error) {
+	var templates []models.PromptTemplate
+	err := r.db.WithContext(ctx).Where("name = ?", name).Order("version DESC").Find(&templates).Error
+	return templates, err
+}
+
+// AddVersion stores template as the next version of its prompt, setting its
+// version
+func (r *PromptRepository) AddVersion(ctx context.Context, template *models.PromptTemplate) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.PromptTemplate{}).Where("name = ?", template.Name).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		template.ID = 0
+		template.Version = latest + 1
+		return tx.Create(template).Error
+	})
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptRepository_Versions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewPromptRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetLatest(ctx, "tutor")
+	assert.Equal(t, ErrNotFound, err)
+
+	for _, body := range []string{"first {{level}}", "second {{level}}"} {
+		require.NoError(t, repo.AddVersion(ctx, &models.PromptTemplate{Name: "tutor", Body: body, Author: "admin"}))
+	}
+	other := &models.PromptTemplate{Name: "word_explanation", Body: "explain {{word}}"}
+	require.NoError(t, repo.AddVersion(ctx, other))
+	assert.Equal(t, 1, other.Version, "versions are numbered per prompt")
+
+	latest, err := repo.GetLatest(ctx, "tutor")
+	require.NoError(t, err)
+	assert.Equal(t, 2, latest.Version)
+	assert.Equal(t, "second {{level}}", latest.Body)
+
+	first, err := repo.GetVersion(ctx, "tutor", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "first {{level}}", first.Body)
+	_, err = repo.GetVersion(ctx, "tutor", 3)
+	assert.Equal(t, ErrNotFound, err)
+
+	versions, err := repo.ListVersions(ctx, "tutor")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+}
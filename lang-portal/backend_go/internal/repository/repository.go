@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,6 +14,12 @@ var (
 	ErrNotFound      = errors.New("record not found")
 	ErrInvalidInput  = errors.New("invalid input")
 	ErrAlreadyExists = errors.New("record already exists")
+	// ErrConflict is returned by updates of a record that was updated since it
+	// was read
+	ErrConflict = errors.New("record was modified concurrently")
+	// ErrDuplicateReview is returned when a word is reviewed again in a session
+	// whose activity rejects repeated reviews
+	ErrDuplicateReview = errors.New("word already reviewed in this session")
 )
 
 // PaginationParams represents common pagination parameters
@@ -20,6 +28,27 @@ type PaginationParams struct {
 	PageSize int
 }
 
+// SortParams orders a list. Field is one of the sort fields of the list, which
+// each repository maps to a column; the zero value keeps the list's default order.
+type SortParams struct {
+	Field string
+	Desc  bool
+}
+
+// order orders query by the column columns maps the sort field to, then by
+// tieBreaker. It reports false, leaving query unchanged, for unknown fields.
+func (s SortParams) order(query *gorm.DB, columns map[string]string, tieBreaker string) (*gorm.DB, bool) {
+	column, ok := columns[s.Field]
+	if !ok {
+		return query, false
+	}
+	direction := " ASC"
+	if s.Desc {
+		direction = " DESC"
+	}
+	return query.Order(column + direction).Order(tieBreaker), true
+}
+
 // PaginatedResult represents a paginated result set
 type PaginatedResult[T any] struct {
 	Items      []T
@@ -40,8 +69,8 @@ func NewBaseRepository(db *gorm.DB) *BaseRepository {
 }
 
 // WithTransaction executes the given function within a transaction
-func (r *BaseRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
-	tx := r.db.Begin()
+func (r *BaseRepository) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	tx := r.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return tx.Error
 	}
@@ -54,6 +83,19 @@ func (r *BaseRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
 	return tx.Commit().Error
 }
 
+// updateMiss explains a versioned update of the record id of model that changed
+// no rows: ErrNotFound if the record is gone, ErrConflict if its version moved on
+func (r *BaseRepository) updateMiss(db *gorm.DB, model interface{}, id uint) error {
+	var count int64
+	if err := db.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return ErrConflict
+}
+
 // Paginate applies pagination to a query
 func (r *BaseRepository) Paginate(query *gorm.DB, params PaginationParams) (*gorm.DB, error) {
 	var total int64
@@ -81,3 +123,10 @@ func (r *BaseRepository) WithTimeRange(query *gorm.DB, field string, tr TimeRang
 	}
 	return query
 }
+
+// containsPattern builds a LIKE pattern matching values that contain q,
+// escaping LIKE wildcards with a backslash
+func containsPattern(q string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(q) + "%"
+}
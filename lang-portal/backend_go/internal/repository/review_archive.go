@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// reviewArchiveBatchSize is the number of reviews archived per transaction
+const reviewArchiveBatchSize = 1000
+
+// allWordReviews is a table expression of every review, archived or not, with
+// the columns of word_review_items. Aliased as word_review_items, it serves the
+// queries written for the live table.
+const allWordReviews = `(SELECT id, word_id, study_session_id, correct, grade, answer_time_ms, created_at FROM word_review_items
+	UNION ALL SELECT id, word_id, study_session_id, correct, grade, answer_time_ms, created_at FROM word_review_archive)`
+
+// ReviewArchiveRepository moves old reviews out of word_review_items
+type ReviewArchiveRepository struct {
+	*BaseRepository
+}
+
+// NewReviewArchiveRepository creates a new review archive repository
+func NewReviewArchiveRepository(db *gorm.DB) *ReviewArchiveRepository {
+	return &ReviewArchiveRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// Archive moves the reviews recorded before the given time to the archive,
+// adding them to the monthly totals of their words, and returns how many were
+// moved. Reviews are moved in batches, each in its own transaction, so an
+// interrupted run leaves every review either live or archived.
+func (r *ReviewArchiveRepository) Archive(ctx context.Context, before, at time.Time) (int64, error) {
+	var archived int64
+	for {
+		var ids []uint
+		err := r.db.WithContext(ctx).Model(&models.WordReview{}).
+			Where("julianday(created_at) < julianday(?)", before).
+			Order("id ASC").
+			Limit(reviewArchiveBatchSize).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return archived, err
+		}
+		if len(ids) == 0 {
+			return archived, nil
+		}
+
+		err = r.WithTransaction(ctx, func(tx *gorm.DB) error {
+			err := tx.Exec(`INSERT INTO word_review_months (word_id, month, reviews, correct_reviews, timed_reviews, answer_time_ms)
+				SELECT word_id, strftime('%Y-%m', created_at), COUNT(*),
+					SUM(CASE WHEN correct THEN 1 ELSE 0 END),
+					COUNT(answer_time_ms), COALESCE(SUM(answer_time_ms), 0)
+				FROM word_review_items WHERE id IN ?
+				GROUP BY word_id, strftime('%Y-%m', created_at)
+				ON CONFLICT (word_id, month) DO UPDATE SET
+					reviews = reviews + excluded.reviews,
+					correct_reviews = correct_reviews + excluded.correct_reviews,
+					timed_reviews = timed_reviews + excluded.timed_reviews,
+					answer_time_ms = answer_time_ms + excluded.answer_time_ms`, ids).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Exec(`INSERT INTO word_review_archive (id, word_id, study_session_id, correct, grade, answer_time_ms, created_at, archived_at)
+				SELECT id, word_id, study_session_id, correct, grade, answer_time_ms, created_at, ?
+				FROM word_review_items WHERE id IN ?`, at, ids).Error
+			if err != nil {
+				return err
+			}
+			return tx.Where("id IN ?", ids).Delete(&models.WordReview{}).Error
+		})
+		if err != nil {
+			return archived, err
+		}
+		archived += int64(len(ids))
+	}
+}
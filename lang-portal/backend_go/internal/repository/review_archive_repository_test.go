@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewArchiveRepository_Archive(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewReviewArchiveRepository(db)
+	words := NewWordRepository(db)
+	groups := NewGroupRepository(db)
+	study := NewStudyRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := testutil.CreateTestGroup(t, db)
+	require.NoError(t, groups.AddWord(ctx, group.ID, word.ID))
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	january := time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)
+	february := time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC)
+	ms := func(n int) *int { return &n }
+	for _, review := range []models.WordReview{
+		{Correct: true, AnswerTimeMs: ms(1000), CreatedAt: january},
+		{Correct: true, CreatedAt: january.Add(time.Minute)},
+		{Correct: false, CreatedAt: january.Add(2 * time.Minute)},
+		{Correct: false, AnswerTimeMs: ms(3000), CreatedAt: february},
+		{Correct: true, CreatedAt: time.Now().UTC()},
+	} {
+		review.WordID, review.StudySessionID = word.ID, session.ID
+		require.NoError(t, db.Create(&review).Error)
+	}
+	drifted, err := words.RecomputeWordStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), drifted)
+
+	archived, err := repo.Archive(ctx, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Now().UTC())
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), archived)
+
+	var live, archive int64
+	require.NoError(t, db.Model(&models.WordReview{}).Count(&live).Error)
+	require.NoError(t, db.Model(&models.ArchivedWordReview{}).Count(&archive).Error)
+	assert.Equal(t, int64(1), live)
+	assert.Equal(t, int64(4), archive)
+	var months []models.WordReviewMonth
+	require.NoError(t, db.Order("month ASC").Find(&months).Error)
+	assert.Equal(t, []models.WordReviewMonth{
+		{WordID: word.ID, Month: "2025-01", Reviews: 3, CorrectReviews: 2, TimedReviews: 1, AnswerTimeMs: 1000},
+		{WordID: word.ID, Month: "2025-02", Reviews: 1, TimedReviews: 1, AnswerTimeMs: 3000},
+	}, months)
+
+	archived, err = repo.Archive(ctx, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Now().UTC())
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	// Statistics merge the archive
+	correct, wrong, err := words.GetStudyStats(ctx, word.ID)
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{3, 2}, [2]int64{correct, wrong})
+	avg, err := words.GetAverageAnswerTime(ctx, word.ID)
+	require.NoError(t, err)
+	require.NotNil(t, avg)
+	assert.Equal(t, 2000.0, *avg)
+	_, total, totalCorrect, err := study.GetStudyStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{5, 3}, [2]int64{total, totalCorrect})
+	total, totalCorrect, err = study.GetSessionReviewStats(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{5, 3}, [2]int64{total, totalCorrect})
+	reviews, err := study.GetWordReviewsBySession(ctx, session.ID, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), reviews.TotalItems)
+	assert.Len(t, reviews.Items, 5)
+	buckets, err := study.GetProgressHistory(ctx, ProgressIntervalMonth, january.AddDate(0, 0, -19))
+	require.NoError(t, err)
+	require.NotEmpty(t, buckets)
+	assert.Equal(t, ProgressBucket{Period: "2025-01-01", Reviews: 3, CorrectReviews: 2, WordsLearned: 1, ActiveMinutes: 3}, buckets[0])
+
+	require.NoError(t, db.Where("1=1").Delete(&models.WordReview{}).Error)
+	active, err := groups.GetActiveGroupCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), active, "archived reviews count as study")
+
+	require.NoError(t, words.Delete(ctx, word.ID))
+	require.NoError(t, db.Model(&models.ArchivedWordReview{}).Count(&archive).Error)
+	assert.Zero(t, archive)
+	require.NoError(t, db.Model(&models.WordReviewMonth{}).Count(&archive).Error)
+	assert.Zero(t, archive)
+}
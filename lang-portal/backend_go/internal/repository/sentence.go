@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SentenceRepository handles database operations for example sentences
+type SentenceRepository struct {
+	*BaseRepository
+}
+
+// NewSentenceRepository creates a new example sentence repository
+func NewSentenceRepository(db *gorm.DB) *SentenceRepository {
+	return &SentenceRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create creates a new example sentence
+func (r *SentenceRepository) Create(ctx context.Context, sentence *models.ExampleSentence) error {
+	if err := sentence.Validate(); err != nil {
+		return ErrInvalidInput
+	}
+	return r.db.WithContext(ctx).Omit("Word").Create(sentence).Error
+}
+
+// ListByWord retrieves the example sentences of a word, oldest first
+func (r *SentenceRepository) ListByWord(ctx context.Context, wordID uint) ([]models.ExampleSentence, error) {
+	sentences := []models.ExampleSentence{}
+	if err := r.db.WithContext(ctx).Where("word_id = ?", wordID).
+		Order("id ASC").
+		Find(&sentences).Error; err != nil {
+		return nil, err
+	}
+	return sentences, nil
+}
+
+// ListByGroup retrieves the example sentences of the words in a group, with
+// their word loaded
+func (r *SentenceRepository) ListByGroup(ctx context.Context, groupID uint) ([]models.ExampleSentence, error) {
+	var sentences []models.ExampleSentence
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN word_groups ON word_groups.word_id = example_sentences.word_id").
+		Where("word_groups.group_id = ?", groupID).
+		Preload("Word").
+		Order("example_sentences.id ASC").
+		Find(&sentences).Error; err != nil {
+		return nil, err
+	}
+	return sentences, nil
+}
+
+// Delete removes an example sentence of a word
+func (r *SentenceRepository) Delete(ctx context.Context, wordID, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND word_id = ?", id, wordID).Delete(&models.ExampleSentence{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
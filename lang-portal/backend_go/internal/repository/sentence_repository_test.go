@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentenceRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewSentenceRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := testutil.CreateTestGroup(t, db)
+	require.NoError(t, NewGroupRepository(db).AddWord(ctx, group.ID, word.ID))
+	other := &models.Group{Name: "Other"}
+	require.NoError(t, db.Create(other).Error)
+
+	sentence := &models.ExampleSentence{WordID: word.ID, Japanese: word.Term + "です。", English: "It is a test."}
+	require.NoError(t, repo.Create(ctx, sentence))
+	assert.Equal(t, ErrInvalidInput, repo.Create(ctx, &models.ExampleSentence{WordID: word.ID}))
+
+	sentences, err := repo.ListByWord(ctx, word.ID)
+	require.NoError(t, err)
+	require.Len(t, sentences, 1)
+	assert.Equal(t, "It is a test.", sentences[0].English)
+
+	inGroup, err := repo.ListByGroup(ctx, group.ID)
+	require.NoError(t, err)
+	require.Len(t, inGroup, 1)
+	assert.Equal(t, word.Term, inGroup[0].Word.Term)
+	inOther, err := repo.ListByGroup(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Empty(t, inOther)
+
+	assert.Equal(t, ErrNotFound, repo.Delete(ctx, word.ID+1, sentence.ID), "sentence belongs to another word")
+	require.NoError(t, repo.Delete(ctx, word.ID, sentence.ID))
+	assert.Equal(t, ErrNotFound, repo.Delete(ctx, word.ID, sentence.ID))
+
+	// Deleting a word deletes its sentences
+	require.NoError(t, repo.Create(ctx, &models.ExampleSentence{WordID: word.ID, Japanese: word.Term}))
+	require.NoError(t, NewWordRepository(db).Delete(ctx, word.ID))
+	var count int64
+	require.NoError(t, db.Model(&models.ExampleSentence{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SessionQueueItem is a word of a study session's queue. Reviewed is set once
+// the word has a review in the session.
+type SessionQueueItem struct {
+	Position int
+	WordID   uint
+	Term     string
+	Reading  string
+	English  string
+	Reviewed bool
+}
+
+// CreateSessionWords stores wordIDs, in order, as the queue of a study session.
+// A session that already has a queue keeps it.
+func (r *StudyRepository) CreateSessionWords(ctx context.Context, sessionID uint, wordIDs []uint) error {
+	if len(wordIDs) == 0 {
+		return nil
+	}
+	rows := make([]models.SessionWord, len(wordIDs))
+	for i, id := range wordIDs {
+		rows[i] = models.SessionWord{StudySessionID: sessionID, Position: i, WordID: id}
+	}
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.SessionWord{}).Where("study_session_id = ?", sessionID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 500).Error
+	})
+}
+
+// GetSessionQueue returns the queue of a study session by position, empty if
+// none was stored. Words deleted since the queue was generated are left out.
+func (r *StudyRepository) GetSessionQueue(ctx context.Context, sessionID uint) ([]SessionQueueItem, error) {
+	var items []SessionQueueItem
+	err := r.db.WithContext(ctx).Table("session_words").
+		Select(`session_words.position, session_words.word_id, words.term, words.reading, words.english,
+			EXISTS (SELECT 1 FROM `+allWordReviews+` AS reviews
+				WHERE reviews.study_session_id = session_words.study_session_id AND reviews.word_id = session_words.word_id) AS reviewed`).
+		Joins("JOIN words ON words.id = session_words.word_id").
+		Where("session_words.study_session_id = ?", sessionID).
+		Order("session_words.position ASC").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
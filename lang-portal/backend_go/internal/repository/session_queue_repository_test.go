@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudyRepository_SessionQueue(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewStudyRepository(db)
+	words := NewWordRepository(db)
+	ctx := context.Background()
+
+	first := testutil.CreateTestWord(t, db)
+	second := &models.Word{Term: "ねこ", Reading: "neko", English: "cat", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(second).Error)
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	queue, err := repo.GetSessionQueue(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	require.NoError(t, repo.CreateSessionWords(ctx, session.ID, []uint{second.ID, first.ID}))
+	require.NoError(t, repo.CreateSessionWords(ctx, session.ID, []uint{first.ID}), "a stored queue is kept")
+	testutil.CreateTestWordReview(t, db, second.ID, session.ID)
+
+	queue, err = repo.GetSessionQueue(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.Equal(t, SessionQueueItem{Position: 0, WordID: second.ID, Term: "ねこ", Reading: "neko", English: "cat", Reviewed: true}, queue[0])
+	assert.Equal(t, 1, queue[1].Position)
+	assert.Equal(t, first.ID, queue[1].WordID)
+	assert.False(t, queue[1].Reviewed)
+
+	require.NoError(t, words.Delete(ctx, second.ID))
+	queue, err = repo.GetSessionQueue(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, first.ID, queue[0].WordID)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettingRepository handles database operations for persisted preferences
+type SettingRepository struct {
+	*BaseRepository
+}
+
+// NewSettingRepository creates a new setting repository
+func NewSettingRepository(db *gorm.DB) *SettingRepository {
+	return &SettingRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Get retrieves the value of a setting
+func (r *SettingRepository) Get(ctx context.Context, key string) (string, error) {
+	var setting models.Setting
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&setting).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return setting.Value, nil
+}
+
+// Set creates or replaces the value of a setting
+func (r *SettingRepository) Set(ctx context.Context, key, value string) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&models.Setting{Key: key, Value: value}).Error
+}
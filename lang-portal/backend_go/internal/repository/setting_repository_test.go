@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingRepository_GetSet(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewSettingRepository(db)
+
+	_, err := repo.Get(context.Background(), "review_order")
+	assert.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, repo.Set(context.Background(), "review_order", "random"))
+	require.NoError(t, repo.Set(context.Background(), "review_order", "oldest_first"))
+
+	value, err := repo.Get(context.Background(), "review_order")
+	require.NoError(t, err)
+	assert.Equal(t, "oldest_first", value)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ShareRepository handles database operations for stats share tokens
+type ShareRepository struct {
+	*BaseRepository
+}
+
+// NewShareRepository creates a new share repository
+func NewShareRepository(db *gorm.DB) *ShareRepository {
+	return &ShareRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Create stores a new share token
+func (r *ShareRepository) Create(ctx context.Context, share *models.StatsShare) error {
+	return r.db.WithContext(ctx).Create(share).Error
+}
+
+// GetByToken retrieves a share by its token
+func (r *ShareRepository) GetByToken(ctx context.Context, token string) (*models.StatsShare, error) {
+	var share models.StatsShare
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&share).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// DeleteByToken revokes a share token
+func (r *ShareRepository) DeleteByToken(ctx context.Context, token string) error {
+	result := r.db.WithContext(ctx).Where("token = ?", token).Delete(&models.StatsShare{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
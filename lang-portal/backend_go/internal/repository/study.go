@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+	"fmt"
 	"lang-portal/backend_go/internal/models"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
@@ -18,17 +21,29 @@ func NewStudyRepository(db *gorm.DB) *StudyRepository {
 }
 
 // CreateStudyActivity creates a new study activity
-func (r *StudyRepository) CreateStudyActivity(activity *models.StudyActivity) error {
+func (r *StudyRepository) CreateStudyActivity(ctx context.Context, activity *models.StudyActivity) error {
 	if err := activity.Validate(); err != nil {
 		return ErrInvalidInput
 	}
-	return r.db.Create(activity).Error
+	return r.db.WithContext(ctx).Create(activity).Error
 }
 
 // GetStudyActivityByID retrieves a study activity by ID
-func (r *StudyRepository) GetStudyActivityByID(id uint) (*models.StudyActivity, error) {
+func (r *StudyRepository) GetStudyActivityByID(ctx context.Context, id uint) (*models.StudyActivity, error) {
 	var activity models.StudyActivity
-	if err := r.db.Preload("Sessions").First(&activity, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Sessions").First(&activity, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// GetStudyActivityByName retrieves a study activity by its unique name
+func (r *StudyRepository) GetStudyActivityByName(ctx context.Context, name string) (*models.StudyActivity, error) {
+	var activity models.StudyActivity
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&activity).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrNotFound
 		}
@@ -38,11 +53,11 @@ func (r *StudyRepository) GetStudyActivityByID(id uint) (*models.StudyActivity,
 }
 
 // ListStudyActivities retrieves a paginated list of study activities
-func (r *StudyRepository) ListStudyActivities(params PaginationParams) (*PaginatedResult[models.StudyActivity], error) {
+func (r *StudyRepository) ListStudyActivities(ctx context.Context, params PaginationParams) (*PaginatedResult[models.StudyActivity], error) {
 	var activities []models.StudyActivity
 	var total int64
 
-	query := r.db.Model(&models.StudyActivity{})
+	query := r.db.WithContext(ctx).Model(&models.StudyActivity{})
 	paginatedQuery, err := r.Paginate(query, params)
 	if err != nil {
 		return nil, err
@@ -66,21 +81,35 @@ func (r *StudyRepository) ListStudyActivities(params PaginationParams) (*Paginat
 	}, nil
 }
 
-// CreateStudySession creates a new study session
-func (r *StudyRepository) CreateStudySession(session *models.StudySession) error {
+// ListAllStudyActivities retrieves all study activities without pagination
+func (r *StudyRepository) ListAllStudyActivities(ctx context.Context) ([]models.StudyActivity, error) {
+	var activities []models.StudyActivity
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&activities).Error; err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// CreateStudySession creates a new study session and records its creation in the outbox
+func (r *StudyRepository) CreateStudySession(ctx context.Context, session *models.StudySession) error {
 	// Validation of GroupID and StudyActivityID existence is handled by the service layer.
 	// Model-level validation (session.Validate()) is too strict here as it would
 	// require fully populated Group and Activity sub-structs, which are not part of the input payload.
 	// if err := session.Validate(); err != nil { // Removed this validation
 	// 	return ErrInvalidInput
 	// }
-	return r.db.Create(session).Error
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return enqueueSessionCreated(tx, session)
+	})
 }
 
 // GetStudySessionByID retrieves a study session by ID
-func (r *StudyRepository) GetStudySessionByID(id uint) (*models.StudySession, error) {
+func (r *StudyRepository) GetStudySessionByID(ctx context.Context, id uint) (*models.StudySession, error) {
 	var session models.StudySession
-	if err := r.db.Preload("Activity").
+	if err := r.db.WithContext(ctx).Preload("Activity").
 		Preload("Group").
 		Preload("Reviews").
 		Preload("Reviews.Word").
@@ -94,11 +123,11 @@ func (r *StudyRepository) GetStudySessionByID(id uint) (*models.StudySession, er
 }
 
 // ListStudySessions retrieves a paginated list of study sessions
-func (r *StudyRepository) ListStudySessions(params PaginationParams) (*PaginatedResult[models.StudySession], error) {
+func (r *StudyRepository) ListStudySessions(ctx context.Context, params PaginationParams) (*PaginatedResult[models.StudySession], error) {
 	var sessions []models.StudySession
 	var total int64
 
-	query := r.db.Model(&models.StudySession{})
+	query := r.db.WithContext(ctx).Model(&models.StudySession{})
 	paginatedQuery, err := r.Paginate(query, params)
 	if err != nil {
 		return nil, err
@@ -127,11 +156,11 @@ func (r *StudyRepository) ListStudySessions(params PaginationParams) (*Paginated
 }
 
 // GetStudySessionsByGroup retrieves study sessions for a specific group
-func (r *StudyRepository) GetStudySessionsByGroup(groupID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error) {
+func (r *StudyRepository) GetStudySessionsByGroup(ctx context.Context, groupID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error) {
 	var sessions []models.StudySession
 	var total int64
 
-	query := r.db.Model(&models.StudySession{}).Where("group_id = ?", groupID)
+	query := r.db.WithContext(ctx).Model(&models.StudySession{}).Where("group_id = ?", groupID)
 	paginatedQuery, err := r.Paginate(query, params)
 	if err != nil {
 		return nil, err
@@ -160,11 +189,11 @@ func (r *StudyRepository) GetStudySessionsByGroup(groupID uint, params Paginatio
 }
 
 // GetStudySessionsByActivity retrieves study sessions for a specific activity
-func (r *StudyRepository) GetStudySessionsByActivity(activityID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error) {
+func (r *StudyRepository) GetStudySessionsByActivity(ctx context.Context, activityID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error) {
 	var sessions []models.StudySession
 	var total int64
 
-	query := r.db.Model(&models.StudySession{}).Where("study_activity_id = ?", activityID)
+	query := r.db.WithContext(ctx).Model(&models.StudySession{}).Where("study_activity_id = ?", activityID)
 	paginatedQuery, err := r.Paginate(query, params)
 	if err != nil {
 		return nil, err
@@ -192,20 +221,281 @@ func (r *StudyRepository) GetStudySessionsByActivity(activityID uint, params Pag
 	}, nil
 }
 
-// AddWordReview adds a word review to a study session
-func (r *StudyRepository) AddWordReview(review *models.WordReview) error {
+// GetGroupStudySessions retrieves study sessions that reviewed at least one word belonging to a group
+func (r *StudyRepository) GetGroupStudySessions(ctx context.Context, groupID uint, params PaginationParams) (*PaginatedResult[models.StudySession], error) {
+	var sessions []models.StudySession
+	var total int64
+
+	sessionIDs := r.db.WithContext(ctx).Table(allWordReviews+" AS word_review_items").
+		Select("word_review_items.study_session_id").
+		Joins("JOIN word_groups ON word_groups.word_id = word_review_items.word_id").
+		Where("word_groups.group_id = ?", groupID)
+
+	query := r.db.WithContext(ctx).Model(&models.StudySession{}).Where("study_sessions.id IN (?)", sessionIDs)
+	paginatedQuery, err := r.Paginate(query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := paginatedQuery.Preload("Activity").
+		Preload("Group").
+		Preload("Reviews").
+		Order("created_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(total) + params.PageSize - 1) / params.PageSize
+	return &PaginatedResult[models.StudySession]{
+		Items:      sessions,
+		TotalItems: total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// CompleteStudySession marks a study session complete now, and reports whether
+// it was still open. A session completed before keeps its completion time, and
+// an expired one stays expired.
+func (r *StudyRepository) CompleteStudySession(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.StudySession{}).
+		Where("id = ? AND completed_at IS NULL AND expired_at IS NULL", id).
+		Update("completed_at", time.Now().UTC())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetActiveStudySession retrieves the most recent study session of learner that
+// is neither completed nor expired
+func (r *StudyRepository) GetActiveStudySession(ctx context.Context, learner string) (*models.StudySession, error) {
+	var session models.StudySession
+	if err := r.db.WithContext(ctx).Preload("Activity").
+		Preload("Group").
+		Preload("Reviews").
+		Where("learner = ? AND completed_at IS NULL AND expired_at IS NULL", learner).
+		Order("created_at DESC, id DESC").
+		First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ExpireStaleSessions sets expired_at to at on the open sessions without a
+// review, or a start, since idleSince and returns how many were expired
+func (r *StudyRepository) ExpireStaleSessions(ctx context.Context, idleSince, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.StudySession{}).
+		Where("completed_at IS NULL AND expired_at IS NULL").
+		Where(`julianday(COALESCE((SELECT MAX(created_at) FROM word_review_items
+			WHERE word_review_items.study_session_id = study_sessions.id), study_sessions.created_at)) < julianday(?)`, idleSince.UTC()).
+		Update("expired_at", at.UTC())
+	return result.RowsAffected, result.Error
+}
+
+// GetSessionReviewStats counts the reviews in a session, archived ones included
+func (r *StudyRepository) GetSessionReviewStats(ctx context.Context, sessionID uint) (totalReviews, correctReviews int64, err error) {
+	var stats struct {
+		Total   int64
+		Correct int64
+	}
+	err = r.db.WithContext(ctx).Table(allWordReviews+" AS word_review_items").
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN correct THEN 1 ELSE 0 END), 0) AS correct").
+		Where("study_session_id = ?", sessionID).
+		Scan(&stats).Error
+	return stats.Total, stats.Correct, err
+}
+
+// GetGroupSessionReviewStats counts the reviews in a session for words belonging
+// to a group, archived ones included
+func (r *StudyRepository) GetGroupSessionReviewStats(ctx context.Context, sessionID, groupID uint) (totalReviews, correctReviews int64, err error) {
+	query := func() *gorm.DB {
+		return r.db.WithContext(ctx).Table(allWordReviews+" AS word_review_items").
+			Joins("JOIN word_groups ON word_groups.word_id = word_review_items.word_id").
+			Where("word_review_items.study_session_id = ? AND word_groups.group_id = ?", sessionID, groupID)
+	}
+
+	if err := query().Count(&totalReviews).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := query().Where("word_review_items.correct = ?", true).Count(&correctReviews).Error; err != nil {
+		return 0, 0, err
+	}
+	return totalReviews, correctReviews, nil
+}
+
+// AddWordReview adds a word review to a study session, schedules the word and
+// records the review in the outbox, in a single transaction. A repeated review
+// of a word in the session is handled by the review dedup mode of the session's
+// activity and may return ErrDuplicateReview.
+func (r *StudyRepository) AddWordReview(ctx context.Context, review *models.WordReview) error {
 	if err := review.Validate(); err != nil {
 		return ErrInvalidInput
 	}
-	return r.db.Create(review).Error
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		dedup, err := sessionReviewDedup(tx, review.StudySessionID)
+		if err != nil {
+			return err
+		}
+		return addReview(tx, dedup, review)
+	})
+}
+
+// AddActivityReviewBatch adds the reviews an external activity submitted for the
+// batch's session, schedules the reviewed words and records the reviews in the
+// outbox, in a single transaction. A batch ID already recorded for the session
+// returns ErrAlreadyExists; a review of a missing word returns ErrNotFound; a
+// repeated review the activity rejects returns ErrDuplicateReview. In each case
+// nothing is added.
+func (r *StudyRepository) AddActivityReviewBatch(ctx context.Context, batch *models.ActivityReviewBatch, reviews []models.WordReview) error {
+	for i := range reviews {
+		reviews[i].StudySessionID = batch.StudySessionID
+		if err := reviews[i].Validate(); err != nil {
+			return ErrInvalidInput
+		}
+	}
+	batch.ReviewCount = len(reviews)
+
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var seen int64
+		if err := tx.Model(&models.ActivityReviewBatch{}).
+			Where("study_session_id = ? AND batch_id = ?", batch.StudySessionID, batch.BatchID).
+			Count(&seen).Error; err != nil {
+			return err
+		}
+		if seen > 0 {
+			return ErrAlreadyExists
+		}
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+
+		dedup, err := sessionReviewDedup(tx, batch.StudySessionID)
+		if err != nil {
+			return err
+		}
+		for i := range reviews {
+			if err := addReview(tx, dedup, &reviews[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// sessionReviewDedup returns the review dedup mode of the activity of a session
+func sessionReviewDedup(tx *gorm.DB, sessionID uint) (string, error) {
+	var modes []string
+	err := tx.Table("study_sessions").
+		Joins("JOIN study_activities ON study_activities.id = study_sessions.study_activity_id").
+		Where("study_sessions.id = ?", sessionID).
+		Pluck("study_activities.review_dedup", &modes).Error
+	if err != nil || len(modes) == 0 {
+		return "", err
+	}
+	return modes[0], nil
+}
+
+// addReview records a review, schedules its word and adds it to the outbox. With
+// a dedup mode, an earlier review of the word in the session either rejects the
+// review or is overwritten by it, keeping its ID.
+func addReview(tx *gorm.DB, dedup string, review *models.WordReview) error {
+	if dedup != "" {
+		var existing models.WordReview
+		err := tx.Where("study_session_id = ? AND word_id = ?", review.StudySessionID, review.WordID).
+			Order("id ASC").
+			First(&existing).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+		case err != nil:
+			return err
+		case dedup == models.ReviewDedupReject:
+			return ErrDuplicateReview
+		default:
+			return replaceReview(tx, &existing, review)
+		}
+	}
+
+	if err := tx.Create(review).Error; err != nil {
+		return err
+	}
+	if err := scheduleWordReview(tx, review); err != nil {
+		return err
+	}
+	if err := advanceWordProgress(tx, review); err != nil {
+		return err
+	}
+	if err := recordWordStats(tx, review); err != nil {
+		return err
+	}
+	return enqueueReviewCreated(tx, review)
+}
+
+// replaceReview overwrites an earlier review with a new answer and replays the
+// word's schedule, progress and stats, since the earlier answer was already
+// applied to them
+func replaceReview(tx *gorm.DB, existing, review *models.WordReview) error {
+	review.ID = existing.ID
+	if review.CreatedAt.IsZero() {
+		review.CreatedAt = time.Now()
+	}
+	if err := tx.Model(existing).UpdateColumns(map[string]interface{}{
+		"correct":        review.Correct,
+		"grade":          review.Grade,
+		"answer_time_ms": review.AnswerTimeMs,
+		"created_at":     review.CreatedAt,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := replayReviews(tx, []uint{review.WordID}, func(wordID uint, reviews []models.WordReview) error {
+		return replayWord(tx, wordID, reviews)
+	}); err != nil {
+		return err
+	}
+	return enqueueReviewCreated(tx, review)
+}
+
+// scheduleWordReview updates the word's materialized last-review and next-due columns for a new review
+func scheduleWordReview(tx *gorm.DB, review *models.WordReview) error {
+	var word models.Word
+	if err := tx.Select("id", "last_reviewed_at", "next_due_at", "accuracy_ewma").First(&word, review.WordID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	reviewedAt := review.CreatedAt
+	if reviewedAt.IsZero() {
+		reviewedAt = time.Now()
+	}
+	word.ScheduleGradedReview(review.EffectiveGrade(), reviewedAt)
+	word.RecordAccuracy(review.Correct)
+
+	// UpdateColumns leaves updated_at alone: reviewing a word does not change its content
+	return tx.Model(&models.Word{}).Where("id = ?", word.ID).UpdateColumns(map[string]interface{}{
+		"last_reviewed_at": word.LastReviewedAt,
+		"next_due_at":      word.NextDueAt,
+		"accuracy_ewma":    word.AccuracyEWMA,
+	}).Error
 }
 
-// GetWordReviewsBySession retrieves word reviews for a specific study session
-func (r *StudyRepository) GetWordReviewsBySession(sessionID uint, params PaginationParams) (*PaginatedResult[models.WordReview], error) {
+// GetWordReviewsBySession retrieves word reviews for a specific study session,
+// archived ones included
+func (r *StudyRepository) GetWordReviewsBySession(ctx context.Context, sessionID uint, params PaginationParams) (*PaginatedResult[models.WordReview], error) {
 	var reviews []models.WordReview
 	var total int64
 
-	query := r.db.Model(&models.WordReview{}).Where("study_session_id = ?", sessionID)
+	query := r.db.WithContext(ctx).Table(allWordReviews+" AS word_review_items").Where("study_session_id = ?", sessionID)
 	paginatedQuery, err := r.Paginate(query, params)
 	if err != nil {
 		return nil, err
@@ -232,9 +522,9 @@ func (r *StudyRepository) GetWordReviewsBySession(sessionID uint, params Paginat
 }
 
 // GetLastStudySession retrieves the most recent study session
-func (r *StudyRepository) GetLastStudySession() (*models.StudySession, error) {
+func (r *StudyRepository) GetLastStudySession(ctx context.Context) (*models.StudySession, error) {
 	var session models.StudySession
-	if err := r.db.Preload("Activity").
+	if err := r.db.WithContext(ctx).Preload("Activity").
 		Preload("Group").
 		Order("created_at DESC").
 		First(&session).Error; err != nil {
@@ -247,26 +537,36 @@ func (r *StudyRepository) GetLastStudySession() (*models.StudySession, error) {
 }
 
 // GetStudyStats retrieves overall study statistics
-func (r *StudyRepository) GetStudyStats() (totalSessions, totalReviews, correctReviews int64, err error) {
-	if err := r.db.Model(&models.StudySession{}).Count(&totalSessions).Error; err != nil {
+func (r *StudyRepository) GetStudyStats(ctx context.Context) (totalSessions, totalReviews, correctReviews int64, err error) {
+	if err := r.db.WithContext(ctx).Model(&models.StudySession{}).Count(&totalSessions).Error; err != nil {
 		return 0, 0, 0, err
 	}
 
-	if err := r.db.Model(&models.WordReview{}).Count(&totalReviews).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.WordReview{}).Count(&totalReviews).Error; err != nil {
 		return 0, 0, 0, err
 	}
 
-	if err := r.db.Model(&models.WordReview{}).Where("correct = ?", true).Count(&correctReviews).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.WordReview{}).Where("correct = ?", true).Count(&correctReviews).Error; err != nil {
 		return 0, 0, 0, err
 	}
 
-	return
+	// Archived reviews are counted from their monthly totals
+	var archived struct {
+		Reviews        int64
+		CorrectReviews int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.WordReviewMonth{}).
+		Select("COALESCE(SUM(reviews), 0) AS reviews, COALESCE(SUM(correct_reviews), 0) AS correct_reviews").
+		Scan(&archived).Error; err != nil {
+		return 0, 0, 0, err
+	}
+	return totalSessions, totalReviews + archived.Reviews, correctReviews + archived.CorrectReviews, nil
 }
 
 // GetStudyStreak retrieves the current study streak in days
-func (r *StudyRepository) GetStudyStreak() (int, error) {
+func (r *StudyRepository) GetStudyStreak(ctx context.Context) (int, error) {
 	var sessions []models.StudySession
-	if err := r.db.Order("created_at DESC").Find(&sessions).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&sessions).Error; err != nil {
 		return 0, err
 	}
 
@@ -300,26 +600,489 @@ func (r *StudyRepository) GetStudyStreak() (int, error) {
 	return currentStreak, nil
 }
 
+// Progress history intervals
+const (
+	ProgressIntervalDay   = "day"
+	ProgressIntervalWeek  = "week"
+	ProgressIntervalMonth = "month"
+)
+
+// progressBuckets maps each interval to the SQL expression of the UTC date its
+// buckets start on; %s is the timestamp. Weeks start on Monday.
+var progressBuckets = map[string]string{
+	ProgressIntervalDay:   "date(%s)",
+	ProgressIntervalWeek:  "date(%s, 'weekday 0', '-6 days')",
+	ProgressIntervalMonth: "date(%s, 'start of month')",
+}
+
+// ProgressBucket holds the review activity of one period
+type ProgressBucket struct {
+	// Period is the UTC date the bucket starts on, as YYYY-MM-DD
+	Period         string
+	Reviews        int64
+	CorrectReviews int64
+	// ActiveMinutes counts the distinct minutes with at least one review
+	ActiveMinutes int64
+	// WordsLearned counts the words answered correctly for the first time
+	WordsLearned int64
+}
+
+// GetProgressHistory aggregates reviews since the given time, archived ones
+// included, into buckets of interval, oldest first. Periods without activity are
+// omitted.
+func (r *StudyRepository) GetProgressHistory(ctx context.Context, interval string, since time.Time) ([]ProgressBucket, error) {
+	bucket, ok := progressBuckets[interval]
+	if !ok {
+		return nil, ErrInvalidInput
+	}
+
+	var buckets []ProgressBucket
+	err := r.db.WithContext(ctx).Table(allWordReviews+" AS word_review_items").
+		Select(fmt.Sprintf(`%s AS period,
+			COUNT(*) AS reviews,
+			SUM(CASE WHEN correct THEN 1 ELSE 0 END) AS correct_reviews,
+			COUNT(DISTINCT strftime('%%Y-%%m-%%d %%H:%%M', created_at)) AS active_minutes`, fmt.Sprintf(bucket, "created_at"))).
+		Where("julianday(created_at) >= julianday(?)", since).
+		Group("period").
+		Order("period ASC").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var learned []struct {
+		Period       string
+		WordsLearned int64
+	}
+	firstCorrect := r.db.Table(allWordReviews+" AS word_review_items").
+		Select("word_id, MIN(julianday(created_at)) AS first_correct").
+		Where("correct = ?", true).
+		Group("word_id")
+	err = r.db.WithContext(ctx).Table("(?) AS first_correct_reviews", firstCorrect).
+		Select(fmt.Sprintf("%s AS period, COUNT(*) AS words_learned", fmt.Sprintf(bucket, "first_correct"))).
+		Where("first_correct >= julianday(?)", since).
+		Group("period").
+		Scan(&learned).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range learned {
+		i := sort.Search(len(buckets), func(i int) bool { return buckets[i].Period >= l.Period })
+		if i == len(buckets) || buckets[i].Period != l.Period {
+			// A word is only learned through a review, so its bucket exists
+			continue
+		}
+		buckets[i].WordsLearned = l.WordsLearned
+	}
+	return buckets, nil
+}
+
+// DeviceStudyStats holds the study activity of the sessions started on one
+// device
+type DeviceStudyStats struct {
+	// DeviceLabel is empty for sessions started without a label
+	DeviceLabel    string
+	Sessions       int64
+	Reviews        int64
+	CorrectReviews int64
+	LastStudiedAt  time.Time
+}
+
+// GetDeviceStudyStats aggregates sessions and their reviews by the device label
+// the sessions were started with, most sessions first
+func (r *StudyRepository) GetDeviceStudyStats(ctx context.Context) ([]DeviceStudyStats, error) {
+	var rows []struct {
+		DeviceLabel    string
+		Sessions       int64
+		Reviews        int64
+		CorrectReviews int64
+		// Aggregates lose the column type, so the time is formatted in SQL
+		LastStudiedAt string
+	}
+	err := r.db.WithContext(ctx).Table("study_sessions").
+		Select(`study_sessions.device_label,
+			COUNT(DISTINCT study_sessions.id) AS sessions,
+			COUNT(word_review_items.id) AS reviews,
+			COALESCE(SUM(CASE WHEN word_review_items.correct THEN 1 ELSE 0 END), 0) AS correct_reviews,
+			strftime('%Y-%m-%dT%H:%M:%fZ', MAX(julianday(study_sessions.created_at))) AS last_studied_at`).
+		Joins("LEFT JOIN word_review_items ON word_review_items.study_session_id = study_sessions.id").
+		Group("study_sessions.device_label").
+		Order("sessions DESC, study_sessions.device_label ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]DeviceStudyStats, len(rows))
+	for i, row := range rows {
+		last, err := time.Parse(time.RFC3339Nano, row.LastStudiedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing last study time of device %q: %w", row.DeviceLabel, err)
+		}
+		stats[i] = DeviceStudyStats{
+			DeviceLabel:    row.DeviceLabel,
+			Sessions:       row.Sessions,
+			Reviews:        row.Reviews,
+			CorrectReviews: row.CorrectReviews,
+			LastStudiedAt:  last,
+		}
+	}
+	return stats, nil
+}
+
+// PartStudyStats holds the reviews of the words tagged with one part of speech
+type PartStudyStats struct {
+	Part           string
+	Words          int64
+	Reviews        int64
+	CorrectReviews int64
+}
+
+// GetPartStudyStats aggregates reviews, archived ones included, by the parts of
+// speech of the reviewed words, most reviews first. A word tagged with several
+// parts counts towards each of them.
+func (r *StudyRepository) GetPartStudyStats(ctx context.Context) ([]PartStudyStats, error) {
+	var stats []PartStudyStats
+	err := r.db.WithContext(ctx).Table(allWordReviews + " AS word_review_items").
+		Select(`parts_of_speech.name AS part,
+			COUNT(DISTINCT word_parts.word_id) AS words,
+			COUNT(*) AS reviews,
+			COALESCE(SUM(CASE WHEN word_review_items.correct THEN 1 ELSE 0 END), 0) AS correct_reviews`).
+		Joins("JOIN word_parts ON word_parts.word_id = word_review_items.word_id").
+		Joins("JOIN parts_of_speech ON parts_of_speech.id = word_parts.part_id").
+		Group("parts_of_speech.id").
+		Order("reviews DESC, part ASC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// LanguageStudyStats holds the vocabulary of one target language and the
+// study of it: the sessions of its groups and the reviews of its words,
+// archived ones included
+type LanguageStudyStats struct {
+	Language       string
+	Words          int64
+	StudiedWords   int64
+	Groups         int64
+	Sessions       int64
+	Reviews        int64
+	CorrectReviews int64
+}
+
+// GetLanguageStudyStats aggregates words, groups, sessions and reviews by
+// target language. Only languages with words or groups are returned, ordered
+// by code.
+func (r *StudyRepository) GetLanguageStudyStats(ctx context.Context) ([]LanguageStudyStats, error) {
+	db := r.db.WithContext(ctx)
+	var words []struct {
+		Language     string
+		Words        int64
+		StudiedWords int64
+	}
+	if err := db.Model(&models.Word{}).
+		Select("language, COUNT(*) AS words, COUNT(last_reviewed_at) AS studied_words").
+		Group("language").
+		Scan(&words).Error; err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Language string
+		Groups   int64
+		Sessions int64
+	}
+	if err := db.Model(&models.Group{}).
+		Select("groups.language, COUNT(DISTINCT groups.id) AS groups, COUNT(study_sessions.id) AS sessions").
+		Joins("LEFT JOIN study_sessions ON study_sessions.group_id = groups.id").
+		Group("groups.language").
+		Scan(&groups).Error; err != nil {
+		return nil, err
+	}
+	var reviews []struct {
+		Language       string
+		Reviews        int64
+		CorrectReviews int64
+	}
+	if err := db.Table(allWordReviews + " AS word_review_items").
+		Select(`words.language,
+			COUNT(*) AS reviews,
+			COALESCE(SUM(CASE WHEN word_review_items.correct THEN 1 ELSE 0 END), 0) AS correct_reviews`).
+		Joins("JOIN words ON words.id = word_review_items.word_id").
+		Group("words.language").
+		Scan(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	byLanguage := make(map[string]*LanguageStudyStats)
+	stats := func(language string) *LanguageStudyStats {
+		if byLanguage[language] == nil {
+			byLanguage[language] = &LanguageStudyStats{Language: language}
+		}
+		return byLanguage[language]
+	}
+	for _, row := range words {
+		s := stats(row.Language)
+		s.Words, s.StudiedWords = row.Words, row.StudiedWords
+	}
+	for _, row := range groups {
+		s := stats(row.Language)
+		s.Groups, s.Sessions = row.Groups, row.Sessions
+	}
+	for _, row := range reviews {
+		s := stats(row.Language)
+		s.Reviews, s.CorrectReviews = row.Reviews, row.CorrectReviews
+	}
+
+	result := make([]LanguageStudyStats, 0, len(byLanguage))
+	for _, s := range byLanguage {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Language < result[j].Language })
+	return result, nil
+}
+
 // GetActiveGroups retrieves the number of groups that have been studied
-func (r *StudyRepository) GetActiveGroups() (int64, error) {
+func (r *StudyRepository) GetActiveGroups(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.StudySession{}).
+	err := r.db.WithContext(ctx).Model(&models.StudySession{}).
 		Distinct("group_id").
 		Count(&count).Error
 	return count, err
 }
 
-// ResetStudyHistory resets all study-related data
-func (r *StudyRepository) ResetStudyHistory() error {
-	return r.WithTransaction(func(tx *gorm.DB) error {
-		// Delete word reviews
+// GetOpenMistakes retrieves the incorrect reviews made since the given time that
+// have not been followed by a correct review of the same word, newest first,
+// with their words
+func (r *StudyRepository) GetOpenMistakes(ctx context.Context, since time.Time) ([]models.WordReview, error) {
+	var reviews []models.WordReview
+	err := r.db.WithContext(ctx).Preload("Word").
+		Where("word_review_items.correct = ? AND word_review_items.created_at >= ?", false, since).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM word_review_items AS later
+			WHERE later.word_id = word_review_items.word_id AND later.correct = ? AND later.id > word_review_items.id
+		)`, true).
+		Order("word_review_items.id DESC").
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// Tables emptied by the resets, in the order their rows are deleted
+var (
+	studyHistoryTables = []string{"activity_review_batches", "word_review_items", "word_review_archive", "word_review_months", "session_words", "study_sessions"}
+	allDataTables      = []string{"activity_review_batches", "word_review_items", "word_review_archive", "word_review_months", "session_words", "study_sessions", "word_groups", "group_goals", "cohort_groups", "groups", "word_kanji", "kanji", "word_parts", "example_sentences", "words"}
+)
+
+// ResetGuard inspects the number of rows per table a reset is about to delete,
+// within the reset's transaction. Returning an error aborts the reset.
+type ResetGuard func(counts map[string]int64) error
+
+// CountStudyHistory returns the number of rows per table ResetStudyHistory deletes
+func (r *StudyRepository) CountStudyHistory(ctx context.Context) (map[string]int64, error) {
+	return countRows(r.db.WithContext(ctx), studyHistoryTables)
+}
+
+// ResetStudyHistory resets all study-related data except the experience
+// learners earned. guard, if not nil, is called with the rows about to be
+// deleted before anything is changed.
+func (r *StudyRepository) ResetStudyHistory(ctx context.Context, guard ResetGuard) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := checkReset(tx, studyHistoryTables, guard); err != nil {
+			return err
+		}
+		// Delete word reviews, archived ones and their totals
 		if err := tx.Where("1=1").Delete(&models.WordReview{}).Error; err != nil {
 			return err
 		}
-		// Delete study sessions
+		if err := tx.Where("1=1").Delete(&models.ArchivedWordReview{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordReviewMonth{}).Error; err != nil {
+			return err
+		}
+		// Delete study sessions and their queues
+		if err := tx.Where("1=1").Delete(&models.SessionWord{}).Error; err != nil {
+			return err
+		}
 		if err := tx.Where("1=1").Delete(&models.StudySession{}).Error; err != nil {
 			return err
 		}
+		// Clear materialized review schedule, progress and stats
+		if err := tx.Where("1=1").Delete(&models.WordProgress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordStats{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Word{}).Where("1=1").UpdateColumns(map[string]interface{}{
+			"last_reviewed_at": nil,
+			"next_due_at":      nil,
+			"accuracy_ewma":    nil,
+		}).Error
+	})
+}
+
+// CountAllData returns the number of rows per table ResetAllData deletes
+func (r *StudyRepository) CountAllData(ctx context.Context) (map[string]int64, error) {
+	return countRows(r.db.WithContext(ctx), allDataTables)
+}
+
+// ResetAllData deletes all words, groups, their goals and study history. Study
+// activities, settings, certificates, cohorts (without their groups), experience
+// and the audit log are kept. guard, if not
+// nil, is called with the rows about to be deleted before anything is changed.
+func (r *StudyRepository) ResetAllData(ctx context.Context, guard ResetGuard) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := checkReset(tx, allDataTables, guard); err != nil {
+			return err
+		}
+		// Progress, stats and embeddings are derived from the words and go first
+		if err := tx.Where("1=1").Delete(&models.WordProgress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordStats{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordVector{}).Error; err != nil {
+			return err
+		}
+		// Delete in reverse order of dependencies
+		for _, table := range allDataTables {
+			if err := tx.Exec("DELETE FROM " + table).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func checkReset(tx *gorm.DB, tables []string, guard ResetGuard) error {
+	if guard == nil {
+		return nil
+	}
+	counts, err := countRows(tx, tables)
+	if err != nil {
+		return err
+	}
+	return guard(counts)
+}
+
+// countRows returns the number of rows in each of the given tables
+func countRows(db *gorm.DB, tables []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var count int64
+		if err := db.Table(table).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
+// SearchStudyActivities returns study activities whose name or description contains the query
+func (r *StudyRepository) SearchStudyActivities(ctx context.Context, query string, limit int) ([]models.StudyActivity, error) {
+	pattern := containsPattern(query)
+	var activities []models.StudyActivity
+	if err := r.db.WithContext(ctx).Where(`name LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\'`, pattern, pattern).
+		Order("id ASC").
+		Limit(limit).
+		Find(&activities).Error; err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// UpsertStudyActivities creates or updates study activities matched by name in a
+// single transaction. Existing activities keep their ID and sessions, and their
+// callback secret unless a new one is given.
+func (r *StudyRepository) UpsertStudyActivities(ctx context.Context, activities []models.StudyActivity) (created, updated int, err error) {
+	for i := range activities {
+		if err := activities[i].Validate(); err != nil {
+			return 0, 0, ErrInvalidInput
+		}
+	}
+
+	err = r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		for i := range activities {
+			activity := &activities[i]
+
+			var existing models.StudyActivity
+			err := tx.Where("name = ?", activity.Name).First(&existing).Error
+			if err == gorm.ErrRecordNotFound {
+				if err := tx.Create(activity).Error; err != nil {
+					return err
+				}
+				created++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			updates := map[string]interface{}{
+				"description":     activity.Description,
+				"thumbnail_url":   activity.ThumbnailURL,
+				"settings_schema": activity.SettingsSchema,
+				"launch_url":      activity.LaunchURL,
+				"modes":           activity.Modes,
+				"capabilities":    activity.Capabilities,
+				"config":          activity.Config,
+				"callback_url":    activity.CallbackURL,
+				"review_dedup":    activity.ReviewDedup,
+				"updated_at":      now,
+			}
+			if activity.CallbackSecret != "" {
+				updates["callback_secret"] = activity.CallbackSecret
+			} else {
+				activity.CallbackSecret = existing.CallbackSecret
+			}
+			if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+				return err
+			}
+			activity.ID = existing.ID
+			activity.CreatedAt = existing.CreatedAt
+			activity.UpdatedAt = &now
+			updated++
+		}
 		return nil
 	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
+}
+
+// RecomputeReviewSchedule rebuilds every word's last-review, next-due and accuracy
+// columns, its progress and its stats by replaying its review history in order. It
+// returns the number of words that have at least one review.
+func (r *StudyRepository) RecomputeReviewSchedule(ctx context.Context) (int64, error) {
+	var reviewed int64
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Word{}).Where("1=1").UpdateColumns(map[string]interface{}{
+			"last_reviewed_at": nil,
+			"next_due_at":      nil,
+			"accuracy_ewma":    nil,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordProgress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1=1").Delete(&models.WordStats{}).Error; err != nil {
+			return err
+		}
+
+		return replayReviews(tx, nil, func(wordID uint, reviews []models.WordReview) error {
+			reviewed++
+			return replayWord(tx, wordID, reviews)
+		})
+	})
+	return reviewed, err
 }
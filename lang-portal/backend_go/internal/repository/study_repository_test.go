@@ -0,0 +1,693 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStudyRepo(t *testing.T) (*StudyRepository, func()) {
+	db := testutil.SetupTestDB(t)
+	repo := NewStudyRepository(db)
+	cleanup := func() { testutil.CleanupTestDB(t, db) }
+	return repo, cleanup
+}
+
+func TestStudyRepository_GetGroupStudySessions(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	group := testutil.CreateTestGroup(t, db)
+	otherGroup := &models.Group{Name: "Other Group"}
+	require.NoError(t, db.Create(otherGroup).Error)
+	activity := testutil.CreateTestStudyActivity(t, db)
+
+	groupWord := testutil.CreateTestWord(t, db)
+	otherWord := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(otherWord).Error)
+	require.NoError(t, db.Model(group).Association("Words").Append(groupWord))
+	require.NoError(t, db.Model(otherGroup).Association("Words").Append(otherWord))
+
+	// Session reviewing the group's word (one correct, one wrong) plus an unrelated word
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	require.NoError(t, db.Create(&models.WordReview{WordID: groupWord.ID, StudySessionID: session.ID, Correct: true}).Error)
+	require.NoError(t, db.Create(&models.WordReview{WordID: groupWord.ID, StudySessionID: session.ID, Correct: false}).Error)
+	require.NoError(t, db.Create(&models.WordReview{WordID: otherWord.ID, StudySessionID: session.ID, Correct: true}).Error)
+
+	// Session that only reviewed the other group's word
+	unrelated := testutil.CreateTestStudySession(t, db, otherGroup.ID, activity.ID)
+	require.NoError(t, db.Create(&models.WordReview{WordID: otherWord.ID, StudySessionID: unrelated.ID, Correct: true}).Error)
+
+	result, err := repo.GetGroupStudySessions(context.Background(), group.ID, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.TotalItems)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, session.ID, result.Items[0].ID)
+
+	total, correct, err := repo.GetGroupSessionReviewStats(context.Background(), session.ID, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, int64(1), correct)
+}
+
+func TestStudyRepository_CompleteStudySession(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+	ctx := context.Background()
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}).Error)
+	require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: false}).Error)
+
+	completed, err := repo.CompleteStudySession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.True(t, completed)
+	fetched, err := repo.GetStudySessionByID(ctx, session.ID)
+	require.NoError(t, err)
+	require.NotNil(t, fetched.CompletedAt)
+
+	completed, err = repo.CompleteStudySession(ctx, session.ID)
+	require.NoError(t, err)
+	assert.False(t, completed, "a completed session keeps its completion time")
+
+	total, correct, err := repo.GetSessionReviewStats(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, int64(1), correct)
+}
+
+func TestStudyRepository_AddWordReviewSchedulesWord(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	require.NoError(t, repo.AddWordReview(context.Background(), &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}))
+
+	var fetched models.Word
+	require.NoError(t, db.First(&fetched, word.ID).Error)
+	require.NotNil(t, fetched.LastReviewedAt)
+	require.NotNil(t, fetched.NextDueAt)
+	assert.True(t, fetched.NextDueAt.After(*fetched.LastReviewedAt))
+	require.NotNil(t, fetched.AccuracyEWMA)
+	assert.Equal(t, 1.0, *fetched.AccuracyEWMA)
+
+	require.NoError(t, repo.ResetStudyHistory(context.Background(), nil))
+	var reset models.Word
+	require.NoError(t, db.First(&reset, word.ID).Error)
+	assert.Nil(t, reset.LastReviewedAt)
+	assert.Nil(t, reset.NextDueAt)
+	assert.Nil(t, reset.AccuracyEWMA)
+}
+
+func TestStudyRepository_AddWordReviewUsesGrade(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+	ctx := context.Background()
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	require.NoError(t, repo.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true, Grade: models.GradeEasy}))
+
+	interval := func() time.Duration {
+		var fetched models.Word
+		require.NoError(t, db.First(&fetched, word.ID).Error)
+		require.NotNil(t, fetched.NextDueAt)
+		return fetched.NextDueAt.Sub(*fetched.LastReviewedAt)
+	}
+	assert.Equal(t, 4*24*time.Hour, interval())
+
+	// Replaying the history keeps the grade
+	_, err := repo.RecomputeReviewSchedule(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4*24*time.Hour, interval())
+}
+
+func TestStudyRepository_AddActivityReviewBatch(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+	ctx := context.Background()
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	batch := &models.ActivityReviewBatch{StudySessionID: session.ID, BatchID: "batch-1"}
+	require.NoError(t, repo.AddActivityReviewBatch(ctx, batch, []models.WordReview{
+		{WordID: word.ID, Correct: true},
+		{WordID: word.ID, Correct: false},
+	}))
+	assert.Equal(t, 2, batch.ReviewCount)
+
+	var scheduled models.Word
+	require.NoError(t, db.First(&scheduled, word.ID).Error)
+	assert.NotNil(t, scheduled.NextDueAt)
+
+	// A replayed batch is rejected without adding reviews
+	err := repo.AddActivityReviewBatch(ctx, &models.ActivityReviewBatch{StudySessionID: session.ID, BatchID: "batch-1"}, []models.WordReview{{WordID: word.ID, Correct: true}})
+	assert.Equal(t, ErrAlreadyExists, err)
+
+	// A batch with a missing word is rolled back entirely
+	err = repo.AddActivityReviewBatch(ctx, &models.ActivityReviewBatch{StudySessionID: session.ID, BatchID: "batch-2"}, []models.WordReview{
+		{WordID: word.ID, Correct: true},
+		{WordID: word.ID + 100, Correct: true},
+	})
+	assert.Equal(t, ErrNotFound, err)
+
+	var reviews, batches int64
+	require.NoError(t, db.Model(&models.WordReview{}).Where("study_session_id = ?", session.ID).Count(&reviews).Error)
+	require.NoError(t, db.Model(&models.ActivityReviewBatch{}).Count(&batches).Error)
+	assert.Equal(t, int64(2), reviews)
+	assert.Equal(t, int64(1), batches)
+}
+
+func TestStudyRepository_AddWordReviewDedup(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+	ctx := context.Background()
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	countReviews := func() int64 {
+		var n int64
+		require.NoError(t, db.Model(&models.WordReview{}).Where("study_session_id = ?", session.ID).Count(&n).Error)
+		return n
+	}
+
+	// Without a mode every review is recorded
+	require.NoError(t, repo.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: false}))
+	require.NoError(t, repo.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}))
+	assert.Equal(t, int64(2), countReviews())
+
+	require.NoError(t, db.Model(activity).Update("review_dedup", models.ReviewDedupReject).Error)
+	err := repo.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true})
+	assert.Equal(t, ErrDuplicateReview, err)
+	err = repo.AddActivityReviewBatch(ctx, &models.ActivityReviewBatch{StudySessionID: session.ID, BatchID: "retry"}, []models.WordReview{{WordID: word.ID, Correct: true}})
+	assert.Equal(t, ErrDuplicateReview, err)
+	assert.Equal(t, int64(2), countReviews())
+
+	// Upserting overwrites the first review of the word and replays its schedule
+	require.NoError(t, db.Model(activity).Update("review_dedup", models.ReviewDedupUpsert).Error)
+	var first models.WordReview
+	require.NoError(t, db.Where("study_session_id = ?", session.ID).Order("id ASC").First(&first).Error)
+	answer := &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true, Grade: models.GradeEasy}
+	require.NoError(t, repo.AddWordReview(ctx, answer))
+	assert.Equal(t, first.ID, answer.ID)
+	assert.Equal(t, int64(2), countReviews())
+
+	var replaced models.WordReview
+	require.NoError(t, db.First(&replaced, first.ID).Error)
+	assert.True(t, replaced.Correct)
+	assert.Equal(t, models.GradeEasy, replaced.Grade)
+
+	var scheduled models.Word
+	require.NoError(t, db.First(&scheduled, word.ID).Error)
+	require.NotNil(t, scheduled.AccuracyEWMA)
+	assert.InDelta(t, 1.0, *scheduled.AccuracyEWMA, 1e-9, "both remaining reviews are correct")
+	var outboxed int64
+	require.NoError(t, db.Model(&models.OutboxEvent{}).Count(&outboxed).Error)
+	assert.Equal(t, int64(3), outboxed, "every accepted review is recorded, the replacement too")
+}
+
+func TestStudyRepository_RecomputeReviewSchedule(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	unreviewed := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(unreviewed).Error)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	first := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(48 * time.Hour)
+	require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true, CreatedAt: first}).Error)
+	require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true, CreatedAt: second}).Error)
+
+	// Stale value on a word without reviews must be cleared
+	require.NoError(t, db.Model(unreviewed).UpdateColumn("next_due_at", first).Error)
+
+	reviewed, err := repo.RecomputeReviewSchedule(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), reviewed)
+
+	var fetched models.Word
+	require.NoError(t, db.First(&fetched, word.ID).Error)
+	require.NotNil(t, fetched.LastReviewedAt)
+	require.NotNil(t, fetched.NextDueAt)
+	assert.True(t, fetched.LastReviewedAt.Equal(second))
+	// Day one interval after the first review, doubled after the second
+	assert.True(t, fetched.NextDueAt.Equal(second.Add(48*time.Hour)))
+
+	var cleared models.Word
+	require.NoError(t, db.First(&cleared, unreviewed.ID).Error)
+	assert.Nil(t, cleared.NextDueAt)
+
+	// Progress is rebuilt from the same reviews
+	var progress models.WordProgress
+	require.NoError(t, db.First(&progress, "word_id = ?", word.ID).Error)
+	assert.Equal(t, models.ProgressReview, progress.State)
+	assert.True(t, progress.StateChangedAt.Equal(second))
+	var progressRows int64
+	require.NoError(t, db.Model(&models.WordProgress{}).Count(&progressRows).Error)
+	assert.Equal(t, int64(1), progressRows)
+}
+
+func TestStudyRepository_AddWordReviewAdvancesProgress(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+	ctx := context.Background()
+	words := NewWordRepository(db)
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	other := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(other).Error)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	require.NoError(t, repo.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}))
+	fetched, err := words.GetByID(ctx, word.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProgressLearning, fetched.ProgressState())
+
+	require.NoError(t, repo.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}))
+	fetched, err = words.GetByID(ctx, word.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProgressReview, fetched.ProgressState())
+	assert.Equal(t, 2, fetched.Progress.Streak)
+
+	counts, err := words.CountWordsByProgressState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		models.ProgressNew:      1,
+		models.ProgressLearning: 0,
+		models.ProgressReview:   1,
+		models.ProgressMastered: 0,
+		models.ProgressLapsed:   0,
+	}, counts)
+
+	// Deleting the word removes its progress
+	require.NoError(t, words.Delete(ctx, word.ID))
+	var left int64
+	require.NoError(t, db.Model(&models.WordProgress{}).Count(&left).Error)
+	assert.Zero(t, left)
+}
+
+func TestStudyRepository_UpsertStudyActivities(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	existing := testutil.CreateTestStudyActivity(t, db)
+
+	created, updated, err := repo.UpsertStudyActivities(context.Background(), []models.StudyActivity{
+		{
+			Name:           existing.Name,
+			Description:    "Updated Description",
+			ThumbnailURL:   "https://example.com/test.png",
+			SettingsSchema: models.RawJSON(`{"type":"object"}`),
+		},
+		{
+			Name:         "Flashcards",
+			Description:  "Flip through cards",
+			ThumbnailURL: "https://example.com/flashcards.png",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 1, updated)
+
+	var fetched models.StudyActivity
+	require.NoError(t, db.First(&fetched, existing.ID).Error)
+	assert.Equal(t, "Updated Description", fetched.Description)
+	assert.JSONEq(t, `{"type":"object"}`, string(fetched.SettingsSchema))
+
+	var count int64
+	require.NoError(t, db.Model(&models.StudyActivity{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	// Invalid activities reject the whole batch
+	_, _, err = repo.UpsertStudyActivities(context.Background(), []models.StudyActivity{
+		{Name: "Quiz", Description: "Quiz", ThumbnailURL: "https://example.com/quiz.png"},
+		{Name: "Broken", Description: "No thumbnail"},
+	})
+	assert.Equal(t, ErrInvalidInput, err)
+	require.NoError(t, db.Model(&models.StudyActivity{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestStudyRepository_UpsertStudyActivities_KeepsCallbackSecret(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	registration := models.StudyActivity{
+		Name:           "Typing Tutor",
+		Description:    "Type what you hear",
+		ThumbnailURL:   "https://typing.example.com/icon.png",
+		LaunchURL:      "https://typing.example.com/launch",
+		Modes:          models.StringSlice{"typing"},
+		Capabilities:   models.StringSlice{"keyboard"},
+		Config:         models.RawJSON(`{"ime":true}`),
+		CallbackURL:    "https://typing.example.com/callback",
+		CallbackSecret: "0123456789abcdef",
+	}
+	_, _, err := repo.UpsertStudyActivities(context.Background(), []models.StudyActivity{registration})
+	require.NoError(t, err)
+
+	// Registering again without a secret keeps the stored one
+	registration.CallbackSecret = ""
+	registration.Modes = models.StringSlice{"typing", "dictation"}
+	activities := []models.StudyActivity{registration}
+	_, updated, err := repo.UpsertStudyActivities(context.Background(), activities)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, "0123456789abcdef", activities[0].CallbackSecret)
+
+	var fetched models.StudyActivity
+	require.NoError(t, db.First(&fetched, activities[0].ID).Error)
+	assert.Equal(t, "0123456789abcdef", fetched.CallbackSecret)
+	assert.Equal(t, models.StringSlice{"typing", "dictation"}, fetched.Modes)
+	assert.Equal(t, models.StringSlice{"keyboard"}, fetched.Capabilities)
+	assert.JSONEq(t, `{"ime":true}`, string(fetched.Config))
+	assert.Equal(t, "https://typing.example.com/launch", fetched.LaunchURL)
+	assert.NotNil(t, fetched.UpdatedAt)
+}
+
+func TestStudyRepository_ResetGuard(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	require.NoError(t, repo.AddWordReview(context.Background(), &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}))
+
+	counts, err := repo.CountStudyHistory(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"activity_review_batches": 0, "word_review_items": 1, "word_review_archive": 0, "word_review_months": 0, "session_words": 0, "study_sessions": 1}, counts)
+
+	// A failing guard leaves everything in place
+	abort := assert.AnError
+	err = repo.ResetAllData(context.Background(), func(seen map[string]int64) error {
+		assert.Equal(t, int64(1), seen["words"])
+		assert.Equal(t, int64(1), seen["groups"])
+		return abort
+	})
+	assert.ErrorIs(t, err, abort)
+	all, err := repo.CountAllData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), all["words"])
+	assert.Equal(t, int64(1), all["word_review_items"])
+
+	require.NoError(t, repo.ResetAllData(context.Background(), func(map[string]int64) error { return nil }))
+	all, err = repo.CountAllData(context.Background())
+	require.NoError(t, err)
+	for table, count := range all {
+		assert.Zero(t, count, table)
+	}
+	var activities int64
+	require.NoError(t, db.Model(&models.StudyActivity{}).Count(&activities).Error)
+	assert.Equal(t, int64(1), activities)
+}
+
+func TestStudyRepository_GetOpenMistakes(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	fixed := testutil.CreateTestWord(t, db)
+	open := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	stale := &models.Word{Term: "猫", Reading: "neko", English: "cat", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(open).Error)
+	require.NoError(t, db.Create(stale).Error)
+
+	now := time.Now()
+	reviews := []models.WordReview{
+		{WordID: stale.ID, Correct: false, CreatedAt: now.AddDate(0, 0, -30)},
+		{WordID: fixed.ID, Correct: false, CreatedAt: now.Add(-3 * time.Hour)},
+		{WordID: open.ID, Correct: false, CreatedAt: now.Add(-2 * time.Hour)},
+		{WordID: open.ID, Correct: true, CreatedAt: now.Add(-90 * time.Minute)},
+		{WordID: fixed.ID, Correct: true, CreatedAt: now.Add(-time.Hour)},
+		{WordID: open.ID, Correct: false, CreatedAt: now.Add(-30 * time.Minute)},
+		{WordID: open.ID, Correct: false, CreatedAt: now.Add(-10 * time.Minute)},
+	}
+	for i := range reviews {
+		reviews[i].StudySessionID = session.ID
+		require.NoError(t, db.Create(&reviews[i]).Error)
+	}
+
+	mistakes, err := repo.GetOpenMistakes(context.Background(), now.AddDate(0, 0, -7))
+	require.NoError(t, err)
+
+	// Only the mistakes on open since its last correct answer remain, newest first
+	require.Len(t, mistakes, 2)
+	assert.Equal(t, reviews[6].ID, mistakes[0].ID)
+	assert.Equal(t, reviews[5].ID, mistakes[1].ID)
+	assert.Equal(t, "犬", mistakes[0].Word.Term)
+}
+
+func TestStudyRepository_GetProgressHistory(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	first := testutil.CreateTestWord(t, db)
+	second := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, db.Create(second).Error)
+
+	// Monday 2025-03-03 and Wednesday 2025-03-05 fall in one week, 2025-03-10 in the next
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC) }
+	reviews := []models.WordReview{
+		{WordID: first.ID, Correct: false, CreatedAt: at(1, 9, 0)},
+		{WordID: first.ID, Correct: false, CreatedAt: at(3, 9, 0)},
+		{WordID: first.ID, Correct: true, CreatedAt: at(3, 9, 0).Add(30 * time.Second)},
+		{WordID: second.ID, Correct: true, CreatedAt: at(5, 20, 15)},
+		{WordID: first.ID, Correct: true, CreatedAt: at(10, 8, 0)},
+	}
+	for i := range reviews {
+		reviews[i].StudySessionID = session.ID
+		require.NoError(t, db.Create(&reviews[i]).Error)
+	}
+
+	daily, err := repo.GetProgressHistory(context.Background(), ProgressIntervalDay, at(2, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []ProgressBucket{
+		{Period: "2025-03-03", Reviews: 2, CorrectReviews: 1, ActiveMinutes: 1, WordsLearned: 1},
+		{Period: "2025-03-05", Reviews: 1, CorrectReviews: 1, ActiveMinutes: 1, WordsLearned: 1},
+		{Period: "2025-03-10", Reviews: 1, CorrectReviews: 1, ActiveMinutes: 1},
+	}, daily)
+
+	weekly, err := repo.GetProgressHistory(context.Background(), ProgressIntervalWeek, at(3, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []ProgressBucket{
+		{Period: "2025-03-03", Reviews: 3, CorrectReviews: 2, ActiveMinutes: 2, WordsLearned: 2},
+		{Period: "2025-03-10", Reviews: 1, CorrectReviews: 1, ActiveMinutes: 1},
+	}, weekly)
+
+	monthly, err := repo.GetProgressHistory(context.Background(), ProgressIntervalMonth, at(1, 0, 0))
+	require.NoError(t, err)
+	require.Len(t, monthly, 1)
+	assert.Equal(t, ProgressBucket{Period: "2025-03-01", Reviews: 5, CorrectReviews: 3, ActiveMinutes: 4, WordsLearned: 2}, monthly[0])
+
+	_, err = repo.GetProgressHistory(context.Background(), "year", at(1, 0, 0))
+	assert.Equal(t, ErrInvalidInput, err)
+}
+
+func TestStudyRepository_GetDeviceStudyStats(t *testing.T) {
+	repo, cleanup := setupStudyRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	word := testutil.CreateTestWord(t, db)
+
+	at := func(day int) time.Time { return time.Date(2025, 3, day, 9, 0, 0, 0, time.UTC) }
+	sessions := []models.StudySession{
+		{Client: models.ClientInfo{DeviceLabel: "phone", UserAgent: "Mobile"}, CreatedAt: at(1)},
+		{Client: models.ClientInfo{DeviceLabel: "phone", UserAgent: "Mobile"}, CreatedAt: at(4)},
+		{Client: models.ClientInfo{DeviceLabel: "desktop"}, CreatedAt: at(2)},
+		{CreatedAt: at(3)},
+	}
+	for i := range sessions {
+		sessions[i].GroupID = group.ID
+		sessions[i].StudyActivityID = activity.ID
+		require.NoError(t, repo.CreateStudySession(context.Background(), &sessions[i]))
+	}
+	for _, review := range []models.WordReview{
+		{StudySessionID: sessions[0].ID, Correct: true},
+		{StudySessionID: sessions[0].ID, Correct: false},
+		{StudySessionID: sessions[1].ID, Correct: true},
+		{StudySessionID: sessions[2].ID, Correct: true},
+	} {
+		review.WordID = word.ID
+		require.NoError(t, db.Create(&review).Error)
+	}
+
+	stats, err := repo.GetDeviceStudyStats(context.Background())
+	require.NoError(t, err)
+	// Sessions without reviews count, and unlabelled sessions are grouped together
+	assert.Equal(t, []DeviceStudyStats{
+		{DeviceLabel: "phone", Sessions: 2, Reviews: 3, CorrectReviews: 2, LastStudiedAt: at(4)},
+		{DeviceLabel: "", Sessions: 1, LastStudiedAt: at(3)},
+		{DeviceLabel: "desktop", Sessions: 1, Reviews: 1, CorrectReviews: 1, LastStudiedAt: at(2)},
+	}, stats)
+}
+
+func TestStudyRepository_ActiveSessionAndExpiry(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewStudyRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	sessions := []models.StudySession{
+		{Learner: "yuki", CreatedAt: now.Add(-72 * time.Hour)},
+		{Learner: "yuki", CreatedAt: now.Add(-48 * time.Hour)},
+		{Learner: "yuki", CreatedAt: now.Add(-30 * time.Hour)},
+		{Learner: "ken", CreatedAt: now.Add(-time.Hour)},
+	}
+	for i := range sessions {
+		sessions[i].GroupID, sessions[i].StudyActivityID = group.ID, activity.ID
+		require.NoError(t, repo.CreateStudySession(ctx, &sessions[i]))
+	}
+	completed, err := repo.CompleteStudySession(ctx, sessions[2].ID)
+	require.NoError(t, err)
+	require.True(t, completed)
+	// A recent review keeps the oldest session alive
+	require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: sessions[0].ID, Correct: true, CreatedAt: now.Add(-2 * time.Hour)}).Error)
+
+	active, err := repo.GetActiveStudySession(ctx, "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, sessions[1].ID, active.ID, "the most recent open session")
+	assert.Equal(t, group.ID, active.Group.ID)
+
+	expired, err := repo.ExpireStaleSessions(ctx, now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+
+	active, err = repo.GetActiveStudySession(ctx, "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, sessions[0].ID, active.ID)
+	assert.Len(t, active.Reviews, 1)
+
+	var stale models.StudySession
+	require.NoError(t, db.First(&stale, sessions[1].ID).Error)
+	require.NotNil(t, stale.ExpiredAt)
+	assert.Nil(t, stale.CompletedAt)
+	completed, err = repo.CompleteStudySession(ctx, stale.ID)
+	require.NoError(t, err)
+	assert.False(t, completed, "expired sessions stay expired")
+
+	_, err = repo.GetActiveStudySession(ctx, "mika")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStudyRepository_GetPartStudyStats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewStudyRepository(db)
+	ctx := context.Background()
+
+	verb := &models.Word{Term: "たべる", Reading: "taberu", English: "to eat", Parts: models.StringSlice{"verb"}}
+	both := &models.Word{Term: "べんきょう", Reading: "benkyou", English: "study", Parts: models.StringSlice{"noun", "verb"}}
+	unseen := &models.Word{Term: "あかい", Reading: "akai", English: "red", Parts: models.StringSlice{"adjective"}}
+	for _, w := range []*models.Word{verb, both, unseen} {
+		require.NoError(t, NewWordRepository(db).Create(ctx, w))
+	}
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	for _, review := range []models.WordReview{
+		{WordID: verb.ID, Correct: true},
+		{WordID: verb.ID, Correct: false},
+		{WordID: both.ID, Correct: true},
+	} {
+		review.StudySessionID = session.ID
+		require.NoError(t, db.Create(&review).Error)
+	}
+	require.NoError(t, db.Create(&models.ArchivedWordReview{WordID: both.ID, StudySessionID: session.ID, Correct: true, CreatedAt: time.Now(), ArchivedAt: time.Now()}).Error)
+
+	stats, err := repo.GetPartStudyStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []PartStudyStats{
+		{Part: "verb", Words: 2, Reviews: 4, CorrectReviews: 3},
+		{Part: "noun", Words: 1, Reviews: 2, CorrectReviews: 2},
+	}, stats)
+}
+
+func TestStudyRepository_GetLanguageStudyStats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewStudyRepository(db)
+	ctx := context.Background()
+
+	words := NewWordRepository(db)
+	mizu := &models.Word{Term: "水", Reading: "mizu", English: "water", Parts: models.StringSlice{"noun"}}
+	mul := &models.Word{Language: models.LanguageKorean, Term: "물", Reading: "mul", English: "water", Parts: models.StringSlice{"noun"}}
+	bul := &models.Word{Language: models.LanguageKorean, Term: "불", Reading: "bul", English: "fire", Parts: models.StringSlice{"noun"}}
+	for _, w := range []*models.Word{mizu, mul, bul} {
+		require.NoError(t, words.Create(ctx, w))
+	}
+	korean := &models.Group{Name: "Korean basics", Language: models.LanguageKorean}
+	require.NoError(t, NewGroupRepository(db).Create(ctx, korean))
+	testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, korean.ID, activity.ID)
+	for _, review := range []models.WordReview{
+		{WordID: mul.ID, Correct: true},
+		{WordID: bul.ID, Correct: false},
+	} {
+		review.StudySessionID = session.ID
+		require.NoError(t, db.Create(&review).Error)
+	}
+	now := time.Now()
+	require.NoError(t, db.Model(mul).Update("last_reviewed_at", now).Error)
+
+	stats, err := repo.GetLanguageStudyStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []LanguageStudyStats{
+		{Language: models.LanguageJapanese, Words: 1, Groups: 1},
+		{Language: models.LanguageKorean, Words: 2, StudiedWords: 1, Groups: 1, Sessions: 1, Reviews: 2, CorrectReviews: 1},
+	}, stats)
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TutorRepository handles database operations for tutor conversations
+type TutorRepository struct {
+	*BaseRepository
+}
+
+// NewTutorRepository creates a new tutor repository
+func NewTutorRepository(db *gorm.DB) *TutorRepository {
+	return &TutorRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// CreateConversation stores a new conversation
+func (r *TutorRepository) CreateConversation(ctx context.Context, conversation *models.Conversation) error {
+	return r.db.WithContext(ctx).Create(conversation).Error
+}
+
+// GetConversation retrieves a conversation with its messages, oldest first
+func (r *TutorRepository) GetConversation(ctx context.Context, id uint) (*models.Conversation, error) {
+	var conversation models.Conversation
+	err := r.db.WithContext(ctx).
+		Preload("Messages", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&conversation, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// ListConversations retrieves a paginated list of a user's conversations
+// without their messages, most recently active first
+func (r *TutorRepository) ListConversations(ctx context.Context, user string, params PaginationParams) (*PaginatedResult[models.Conversation], error) {
+	query := r.db.WithContext(ctx).Model(&models.Conversation{}).Where("user = ?", user)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var conversations []models.Conversation
+	offset := (params.Page - 1) * params.PageSize
+	if err := query.Order("updated_at DESC, id DESC").Offset(offset).Limit(params.PageSize).Find(&conversations).Error; err != nil {
+		return nil, err
+	}
+
+	return &PaginatedResult[models.Conversation]{
+		Items:      conversations,
+		TotalItems: total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: (int(total) + params.PageSize - 1) / params.PageSize,
+	}, nil
+}
+
+// AddExchange stores a learner's message and the tutor's reply to it, and
+// marks the conversation as active
+func (r *TutorRepository) AddExchange(ctx context.Context, conversationID uint, message, reply *models.Message) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.Conversation{}).Where("id = ?", conversationID).Update("updated_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		for _, m := range []*models.Message{message, reply} {
+			m.ConversationID = conversationID
+			if err := tx.Create(m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteConversation deletes a conversation and its messages
+func (r *TutorRepository) DeleteConversation(ctx context.Context, id uint) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("conversation_id = ?", id).Delete(&models.Message{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Conversation{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
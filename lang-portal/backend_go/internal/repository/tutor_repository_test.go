@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTutorRepository_Conversation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewTutorRepository(db)
+	ctx := context.Background()
+
+	first := &models.Conversation{Title: "Verbs", User: "alice", Context: "- 食べる (taberu): to eat"}
+	second := &models.Conversation{User: "alice"}
+	other := &models.Conversation{User: "bob"}
+	for _, c := range []*models.Conversation{first, second, other} {
+		require.NoError(t, repo.CreateConversation(ctx, c))
+	}
+
+	require.NoError(t, repo.AddExchange(ctx, first.ID,
+		&models.Message{Role: models.MessageRoleUser, Content: "How do I say eat?"},
+		&models.Message{Role: models.MessageRoleAssistant, Content: "食べる (taberu)", PromptTokens: 40, CompletionTokens: 8}))
+	assert.Equal(t, ErrNotFound, repo.AddExchange(ctx, 999,
+		&models.Message{Role: models.MessageRoleUser, Content: "?"},
+		&models.Message{Role: models.MessageRoleAssistant, Content: "!"}))
+
+	conversation, err := repo.GetConversation(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, conversation.Messages, 2)
+	assert.Equal(t, models.MessageRoleUser, conversation.Messages[0].Role)
+	assert.Equal(t, "食べる (taberu)", conversation.Messages[1].Content)
+	assert.Equal(t, 8, conversation.Messages[1].CompletionTokens)
+
+	// The conversation with the latest message comes first
+	result, err := repo.ListConversations(ctx, "alice", PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.TotalItems)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, first.ID, result.Items[0].ID)
+	assert.Empty(t, result.Items[0].Messages)
+
+	require.NoError(t, repo.DeleteConversation(ctx, first.ID))
+	_, err = repo.GetConversation(ctx, first.ID)
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, ErrNotFound, repo.DeleteConversation(ctx, first.ID))
+	var messages int64
+	require.NoError(t, db.Model(&models.Message{}).Count(&messages).Error)
+	assert.Zero(t, messages)
+}
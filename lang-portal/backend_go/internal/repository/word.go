@@ -1,6 +1,10 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"time"
+
 	"lang-portal/backend_go/internal/models"
 
 	"gorm.io/gorm"
@@ -16,18 +20,35 @@ func NewWordRepository(db *gorm.DB) *WordRepository {
 	return &WordRepository{BaseRepository: NewBaseRepository(db)}
 }
 
-// Create creates a new word
-func (r *WordRepository) Create(word *models.Word) error {
+// Create creates a new word and links it to the kanji in its Japanese text.
+// A word without a language is in the default language, and one without
+// scripts in those of its language.
+func (r *WordRepository) Create(ctx context.Context, word *models.Word) error {
 	if err := word.Validate(); err != nil {
 		return ErrInvalidInput
 	}
-	return r.db.Create(word).Error
+	if word.Language == "" {
+		word.Language = models.DefaultLanguage
+	}
+	word.DefaultScripts()
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Create(word).Error; err != nil {
+			return err
+		}
+		if err := linkWordParts(tx, word); err != nil {
+			return err
+		}
+		if err := rankWordFrequency(tx, word); err != nil {
+			return err
+		}
+		return linkWordKanji(tx, word)
+	})
 }
 
 // GetByID retrieves a word by ID
-func (r *WordRepository) GetByID(id uint) (*models.Word, error) {
+func (r *WordRepository) GetByID(ctx context.Context, id uint) (*models.Word, error) {
 	var word models.Word
-	if err := r.db.Preload("Groups").Preload("Reviews").First(&word, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Groups").Preload("Reviews").Preload("Progress").First(&word, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrNotFound
 		}
@@ -36,10 +57,10 @@ func (r *WordRepository) GetByID(id uint) (*models.Word, error) {
 	return &word, nil
 }
 
-// GetByJapanese retrieves a word by Japanese text
-func (r *WordRepository) GetByJapanese(japanese string) (*models.Word, error) {
+// GetByTerm retrieves a word by its term
+func (r *WordRepository) GetByTerm(ctx context.Context, term string) (*models.Word, error) {
 	var word models.Word
-	if err := r.db.Where("japanese = ?", japanese).First(&word).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("term = ?", term).First(&word).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrNotFound
 		}
@@ -48,22 +69,23 @@ func (r *WordRepository) GetByJapanese(japanese string) (*models.Word, error) {
 	return &word, nil
 }
 
-// List retrieves a paginated list of words
-func (r *WordRepository) List(params PaginationParams) (*PaginatedResult[models.Word], error) {
+// List retrieves a paginated list of words, filtered and ordered by opts
+func (r *WordRepository) List(ctx context.Context, params PaginationParams, opts WordListOptions) (*PaginatedResult[models.Word], error) {
 	var words []models.Word
 	var total int64
 
-	query := r.db.Model(&models.Word{})
-	paginatedQuery, err := r.Paginate(query, params)
-	if err != nil {
+	// Count on a separate query: the paginated one carries the offset and limit
+	if err := opts.filter(r.db.WithContext(ctx).Model(&models.Word{})).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
-	if err := paginatedQuery.Preload("Groups").Preload("Reviews").Find(&words).Error; err != nil {
+	query := opts.apply(r.db.WithContext(ctx).Model(&models.Word{}))
+	paginatedQuery, err := r.Paginate(query, params)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := query.Count(&total).Error; err != nil {
+	if err := paginatedQuery.Preload("Groups").Preload("Progress").Preload("Stats").Find(&words).Error; err != nil {
 		return nil, err
 	}
 
@@ -77,63 +99,146 @@ func (r *WordRepository) List(params PaginationParams) (*PaginatedResult[models.
 	}, nil
 }
 
-// Update updates a word
-func (r *WordRepository) Update(word *models.Word) error {
+// Update updates a word and relinks it to the kanji in its Japanese text
+func (r *WordRepository) Update(ctx context.Context, word *models.Word) error {
 	if err := word.Validate(); err != nil {
 		return ErrInvalidInput
 	}
-	return r.db.Save(word).Error
+	now := time.Now()
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		// Only the version that was read is replaced; review fields are left to
+		// the review writes
+		result := tx.Model(&models.Word{}).
+			Where("id = ? AND version = ?", word.ID, word.Version).
+			Updates(map[string]interface{}{
+				"term":           word.Term,
+				"reading":        word.Reading,
+				"script":         word.Script,
+				"reading_script": word.ReadingScript,
+				"english":        word.English,
+				"german":         word.German,
+				"parts":          word.Parts,
+				"updated_at":     now,
+				"version":        gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return r.updateMiss(tx, &models.Word{}, word.ID)
+		}
+		if err := linkWordParts(tx, word); err != nil {
+			return err
+		}
+		if err := rankWordFrequency(tx, word); err != nil {
+			return err
+		}
+		return linkWordKanji(tx, word)
+	})
+	if err != nil {
+		return err
+	}
+	word.UpdatedAt = now
+	word.Version++
+	return nil
 }
 
 // Delete deletes a word and its associated records
-func (r *WordRepository) Delete(id uint) error {
-	return r.WithTransaction(func(tx *gorm.DB) error {
+func (r *WordRepository) Delete(ctx context.Context, id uint) error {
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
 		// Delete word-group associations
 		if err := tx.Exec("DELETE FROM word_groups WHERE word_id = ?", id).Error; err != nil {
 			return err
 		}
-		// Delete word reviews
+		// Delete word-kanji associations
+		if err := tx.Where("word_id = ?", id).Delete(&WordKanji{}).Error; err != nil {
+			return err
+		}
+		// Delete word-part associations
+		if err := tx.Where("word_id = ?", id).Delete(&models.WordPart{}).Error; err != nil {
+			return err
+		}
+		// Delete word progress and stats
+		if err := tx.Where("word_id = ?", id).Delete(&models.WordProgress{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("word_id = ?", id).Delete(&models.WordStats{}).Error; err != nil {
+			return err
+		}
+		// Delete word embeddings
+		if err := tx.Where("word_id = ?", id).Delete(&models.WordVector{}).Error; err != nil {
+			return err
+		}
+		// Delete word reviews, archived ones and their totals
 		if err := tx.Where("word_id = ?", id).Delete(&models.WordReview{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("word_id = ?", id).Delete(&models.ArchivedWordReview{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("word_id = ?", id).Delete(&models.WordReviewMonth{}).Error; err != nil {
+			return err
+		}
+		// Delete the word from session queues
+		if err := tx.Where("word_id = ?", id).Delete(&models.SessionWord{}).Error; err != nil {
+			return err
+		}
+		// Delete example sentences
+		if err := tx.Where("word_id = ?", id).Delete(&models.ExampleSentence{}).Error; err != nil {
+			return err
+		}
 		// Delete the word
 		return tx.Delete(&models.Word{}, "id = ?", id).Error
 	})
 }
 
-// GetStudyStats retrieves study statistics for a word
-func (r *WordRepository) GetStudyStats(wordID uint) (int64, int64, error) {
-	var correct, wrong int64
-	err := r.db.Model(&models.WordReview{}).Where("word_id = ? AND correct = ?", wordID, true).Count(&correct).Error
-	if err != nil {
+// GetStudyStats retrieves the correct and wrong review counts of a word,
+// archived reviews included, from its stats
+func (r *WordRepository) GetStudyStats(ctx context.Context, wordID uint) (int64, int64, error) {
+	var stats models.WordStats
+	if err := r.db.WithContext(ctx).Where("word_id = ?", wordID).Limit(1).Find(&stats).Error; err != nil {
 		return 0, 0, err
 	}
-	err = r.db.Model(&models.WordReview{}).Where("word_id = ? AND correct = ?", wordID, false).Count(&wrong).Error
-	if err != nil {
-		return 0, 0, err
+	return stats.CorrectCount, stats.WrongCount, nil
+}
+
+// GetAverageAnswerTime returns the mean answer time of a word's timed reviews in
+// milliseconds, archived ones included, or nil when none was timed
+func (r *WordRepository) GetAverageAnswerTime(ctx context.Context, wordID uint) (*float64, error) {
+	var avg sql.NullFloat64
+	err := r.db.WithContext(ctx).Raw(`SELECT CAST(SUM(total) AS REAL) / SUM(timed) FROM (
+			SELECT SUM(answer_time_ms) AS total, COUNT(answer_time_ms) AS timed FROM word_review_items WHERE word_id = ?
+			UNION ALL SELECT SUM(answer_time_ms), SUM(timed_reviews) FROM word_review_months WHERE word_id = ?
+		) WHERE timed > 0`, wordID, wordID).
+		Scan(&avg).Error
+	if err != nil || !avg.Valid {
+		return nil, err
 	}
-	return correct, wrong, nil
+	return &avg.Float64, nil
 }
 
 // GetWordsByGroup retrieves words belonging to a group
-func (r *WordRepository) GetWordsByGroup(groupID uint, params PaginationParams) (*PaginatedResult[models.Word], error) {
+func (r *WordRepository) GetWordsByGroup(ctx context.Context, groupID uint, params PaginationParams, opts WordListOptions) (*PaginatedResult[models.Word], error) {
 	var words []models.Word
 	var total int64
 
-	query := r.db.Model(&models.Word{}).
-		Joins("JOIN word_groups ON word_groups.word_id = words.id").
-		Where("word_groups.group_id = ?", groupID)
+	inGroup := func(query *gorm.DB) *gorm.DB {
+		return query.Joins("JOIN word_groups ON word_groups.word_id = words.id").
+			Where("word_groups.group_id = ?", groupID)
+	}
 
-	paginatedQuery, err := r.Paginate(query, params)
-	if err != nil {
+	// Count on a separate query: the paginated one carries the offset and limit
+	if err := opts.filter(inGroup(r.db.WithContext(ctx).Model(&models.Word{}))).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
-	if err := paginatedQuery.Preload("Groups").Preload("Reviews").Find(&words).Error; err != nil {
+	query := opts.apply(inGroup(r.db.WithContext(ctx).Model(&models.Word{})))
+	paginatedQuery, err := r.Paginate(query, params)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := query.Count(&total).Error; err != nil {
+	if err := paginatedQuery.Preload("Groups").Preload("Progress").Preload("Stats").Find(&words).Error; err != nil {
 		return nil, err
 	}
 
@@ -147,15 +252,15 @@ func (r *WordRepository) GetWordsByGroup(groupID uint, params PaginationParams)
 	}, nil
 }
 
-// GetWordsByGroupRaw retrieves a simplified list of words in a group (id, japanese, romaji, english only)
-func (r *WordRepository) GetWordsByGroupRaw(groupID uint) ([]models.Word, error) {
+// GetWordsByGroupRaw retrieves a simplified list of words in a group (id, term, reading, english only)
+func (r *WordRepository) GetWordsByGroupRaw(ctx context.Context, groupID uint) ([]models.Word, error) {
 	var words []models.Word
 	
-	err := r.db.Model(&models.Word{}).
-		Select("words.id, words.japanese, words.romaji, words.english").
+	err := r.db.WithContext(ctx).Model(&models.Word{}).
+		Select("words.id, words.term, words.reading, words.english, words.last_reviewed_at, words.accuracy_ewma").
 		Joins("JOIN word_groups ON word_groups.word_id = words.id").
 		Where("word_groups.group_id = ?", groupID).
-		Order("words.japanese ASC").
+		Order("words.term ASC").
 		Find(&words).Error
 
 	if err != nil {
@@ -166,20 +271,57 @@ func (r *WordRepository) GetWordsByGroupRaw(groupID uint) ([]models.Word, error)
 }
 
 // GetTotalWordCount returns the total number of words
-func (r *WordRepository) GetTotalWordCount() (int64, error) {
+func (r *WordRepository) GetTotalWordCount(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Word{}).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Word{}).Count(&count).Error
 	return count, err
 }
 
 // GetStudiedWordCount returns the number of words that have been studied
-func (r *WordRepository) GetStudiedWordCount() (int64, error) {
+func (r *WordRepository) GetStudiedWordCount(ctx context.Context) (int64, error) {
 	var count int64
-	if err := r.db.Model(&models.Word{}).
-		Joins("JOIN word_reviews ON word_reviews.word_id = words.id").
-		Distinct("words.id").
+	if err := r.db.WithContext(ctx).Model(&models.Word{}).
+		Where("last_reviewed_at IS NOT NULL").
 		Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
+
+// GetDueWords retrieves words whose next review is due at or before asOf, most overdue first
+func (r *WordRepository) GetDueWords(ctx context.Context, asOf time.Time, limit int) ([]models.Word, error) {
+	var words []models.Word
+	if err := r.db.WithContext(ctx).Where("next_due_at IS NOT NULL AND next_due_at <= ?", asOf).
+		Order("next_due_at ASC").
+		Limit(limit).
+		Find(&words).Error; err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// Search returns words whose japanese, romaji or english contains the query
+func (r *WordRepository) Search(ctx context.Context, query string, limit int) ([]models.Word, error) {
+	pattern := containsPattern(query)
+	var words []models.Word
+	if err := r.db.WithContext(ctx).Where(`term LIKE ? ESCAPE '\' OR reading LIKE ? ESCAPE '\' OR english LIKE ? ESCAPE '\'`, pattern, pattern, pattern).
+		Order("id ASC").
+		Limit(limit).
+		Find(&words).Error; err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// ListAfter retrieves up to limit words with an ID above afterID, in ID order.
+// Passing the last ID of each batch as the next afterID walks every word
+// without holding them all in memory.
+func (r *WordRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]models.Word, error) {
+	var words []models.Word
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&words).Error
+	return words, err
+}
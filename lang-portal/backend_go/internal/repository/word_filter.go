@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// WordFilter selects words for analysis queries such as group suggestions.
+// Zero values are ignored.
+type WordFilter struct {
+	// Part restricts results to words tagged with this part of speech
+	Part string
+	// CreatedSince restricts results to words added at or after this time
+	CreatedSince time.Time
+	// ReviewedOnly restricts results to words with an accuracy estimate
+	ReviewedOnly bool
+	// WeakestFirst orders by accuracy, lowest first, instead of newest first
+	WeakestFirst bool
+	Limit        int
+}
+
+// FindWords returns the words matching filter
+func (r *WordRepository) FindWords(ctx context.Context, filter WordFilter) ([]models.Word, error) {
+	query := r.db.WithContext(ctx).Model(&models.Word{})
+	if filter.Part != "" {
+		query = wordHasPart(query, filter.Part)
+	}
+	if !filter.CreatedSince.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedSince)
+	}
+	if filter.ReviewedOnly {
+		query = query.Where("accuracy_ewma IS NOT NULL")
+	}
+	if filter.WeakestFirst {
+		query = query.Order("accuracy_ewma IS NULL, accuracy_ewma ASC, last_reviewed_at ASC, id ASC")
+	} else {
+		query = query.Order("created_at DESC, id DESC")
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var words []models.Word
+	if err := query.Find(&words).Error; err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// GetReviewedParts returns the parts of speech tagged on at least minWords reviewed
+// words, most common first
+func (r *WordRepository) GetReviewedParts(ctx context.Context, minWords, limit int) ([]string, error) {
+	var parts []string
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT parts_of_speech.name AS part
+		FROM words
+		JOIN word_parts ON word_parts.word_id = words.id
+		JOIN parts_of_speech ON parts_of_speech.id = word_parts.part_id
+		WHERE words.accuracy_ewma IS NOT NULL
+		GROUP BY parts_of_speech.id
+		HAVING COUNT(*) >= ?
+		ORDER BY COUNT(*) DESC, part ASC
+		LIMIT ?`, minWords, limit).Scan(&parts).Error; err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
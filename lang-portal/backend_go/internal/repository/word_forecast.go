@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// DueDay counts the words whose next review falls on one UTC day
+type DueDay struct {
+	// Day is the UTC date, as YYYY-MM-DD
+	Day   string
+	Words int64
+}
+
+// CountDueByDay counts the words due for review before until by the UTC day
+// they are due, earliest first. Overdue words are counted on the day they
+// became due.
+func (r *WordRepository) CountDueByDay(ctx context.Context, until time.Time) ([]DueDay, error) {
+	var days []DueDay
+	err := r.db.WithContext(ctx).Model(&models.Word{}).
+		Select("date(next_due_at) AS day, COUNT(*) AS words").
+		Where("next_due_at IS NOT NULL AND next_due_at < ?", until).
+		Group("day").
+		Order("day ASC").
+		Scan(&days).Error
+	return days, err
+}
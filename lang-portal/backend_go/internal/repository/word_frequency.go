@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// frequencyBatchSize is the number of frequency entries inserted per statement
+const frequencyBatchSize = 1000
+
+// wordFrequencyRank is the rank of the best frequency entry written as the
+// word's japanese, or read as it for kana-only words
+const wordFrequencyRank = `(SELECT MIN(word_frequency.rank) FROM word_frequency
+	WHERE word_frequency.japanese = words.term OR word_frequency.reading = words.term)`
+
+// ReplaceWordFrequencies replaces the frequency list with entries and ranks
+// every word against it. It returns the number of words found in the list.
+func (r *WordRepository) ReplaceWordFrequencies(ctx context.Context, entries []models.WordFrequency) (int64, error) {
+	var matched int64
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("1=1").Delete(&models.WordFrequency{}).Error; err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			if err := tx.CreateInBatches(&entries, frequencyBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if err := rankWords(tx.Where("1=1")); err != nil {
+			return err
+		}
+		return tx.Model(&models.Word{}).Where("frequency_rank IS NOT NULL").Count(&matched).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return matched, nil
+}
+
+// RankUnrankedWords ranks the words that have no frequency rank against the
+// frequency list, covering words written without the word repository, such
+// as seed data
+func (r *WordRepository) RankUnrankedWords(ctx context.Context) error {
+	return rankWords(r.db.WithContext(ctx).Where("frequency_rank IS NULL"))
+}
+
+// rankWords sets the frequency rank of the words matching query, clearing it
+// for words the frequency list does not contain
+func rankWords(query *gorm.DB) error {
+	return query.Model(&models.Word{}).
+		UpdateColumn("frequency_rank", gorm.Expr(wordFrequencyRank)).Error
+}
+
+// rankWordFrequency sets the frequency rank of a word from the frequency list
+func rankWordFrequency(tx *gorm.DB, word *models.Word) error {
+	var rank sql.NullInt64
+	if err := tx.Raw(`SELECT MIN(rank) FROM word_frequency WHERE japanese = ? OR reading = ?`,
+		word.Term, word.Term).Row().Scan(&rank); err != nil {
+		return err
+	}
+	word.FrequencyRank = nil
+	if rank.Valid {
+		value := int(rank.Int64)
+		word.FrequencyRank = &value
+	}
+	return tx.Model(&models.Word{}).Where("id = ?", word.ID).UpdateColumn("frequency_rank", word.FrequencyRank).Error
+}
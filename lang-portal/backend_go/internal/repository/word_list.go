@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// WordListOptions orders and filters word lists
+type WordListOptions struct {
+	Sort   WordSort
+	Status WordStatusFilter
+	// Part keeps only the words tagged with this part of speech when not empty
+	Part string
+	// Language keeps only the words of this target language when not empty
+	Language string
+}
+
+// Word list sort fields
+const (
+	WordSortJapanese     = "japanese"
+	WordSortRomaji       = "romaji"
+	WordSortEnglish      = "english"
+	WordSortCreatedAt    = "created_at"
+	WordSortCorrectCount = "correct_count"
+	WordSortSuccessRate  = "success_rate"
+	// WordSortFrequencyRank puts the most frequent words first in ascending order
+	WordSortFrequencyRank = "frequency_rank"
+)
+
+// wordSortColumns maps each sort field to the expression it orders by. Only
+// these expressions ever reach the ORDER BY clause.
+var wordSortColumns = map[string]string{
+	WordSortJapanese:      "words.term",
+	WordSortRomaji:        "words.reading",
+	WordSortEnglish:       "words.english",
+	WordSortCreatedAt:     "words.created_at",
+	WordSortCorrectCount:  "COALESCE(word_stats.correct_count, 0)",
+	WordSortSuccessRate:   wordSuccessRate,
+	WordSortFrequencyRank: "words.frequency_rank",
+}
+
+// Word study statuses, derived from review counts and success rate
+const (
+	// WordStatusUnstudied words have never been reviewed
+	WordStatusUnstudied = "unstudied"
+	// WordStatusLearning words have been reviewed but are not mastered yet
+	WordStatusLearning = "learning"
+	// WordStatusMastered words meet both mastery thresholds
+	WordStatusMastered = "mastered"
+)
+
+// wordStatsJoin attaches the review totals of each word, archived reviews
+// included, for sorting and filtering by study statistics. Words without
+// reviews get NULL totals.
+const wordStatsJoin = "LEFT JOIN word_stats ON word_stats.word_id = words.id"
+
+const (
+	wordReviewCount = "(word_stats.correct_count + word_stats.wrong_count)"
+	wordSuccessRate = "word_stats.success_rate"
+	wordMastered    = wordReviewCount + " >= ? AND " + wordSuccessRate + " >= ?"
+)
+
+// WordSort orders a word list by one of the word list sort fields. The zero
+// value orders by ID.
+type WordSort = SortParams
+
+// ValidWordSortField reports whether field is a supported sort field
+func ValidWordSortField(field string) bool {
+	_, ok := wordSortColumns[field]
+	return ok
+}
+
+// WordStatusFilter restricts a word list to one study status. The zero value
+// matches every word.
+type WordStatusFilter struct {
+	Status string
+	// MasteredMinReviews and MasteredMinSuccessRate (0-1) define mastered words
+	MasteredMinReviews     int
+	MasteredMinSuccessRate float64
+}
+
+// ValidWordStatus reports whether status is a supported study status
+func ValidWordStatus(status string) bool {
+	switch status {
+	case WordStatusUnstudied, WordStatusLearning, WordStatusMastered:
+		return true
+	}
+	return false
+}
+
+// filter joins review statistics when needed and applies the status, part
+// and language filters
+func (o WordListOptions) filter(query *gorm.DB) *gorm.DB {
+	if o.Part != "" {
+		query = wordHasPart(query, o.Part)
+	}
+	if o.Language != "" {
+		query = query.Where("words.language = ?", o.Language)
+	}
+	needsStats := o.Status.Status != "" ||
+		o.Sort.Field == WordSortCorrectCount || o.Sort.Field == WordSortSuccessRate
+	if needsStats {
+		query = query.Joins(wordStatsJoin)
+	}
+
+	status := o.Status
+	switch status.Status {
+	case WordStatusUnstudied:
+		query = query.Where("word_stats.word_id IS NULL")
+	case WordStatusLearning:
+		query = query.Where("word_stats.word_id IS NOT NULL AND NOT ("+wordMastered+")",
+			status.MasteredMinReviews, status.MasteredMinSuccessRate)
+	case WordStatusMastered:
+		query = query.Where(wordMastered, status.MasteredMinReviews, status.MasteredMinSuccessRate)
+	}
+	return query
+}
+
+// apply filters and orders query. Unknown sort fields fall back to ordering by ID.
+func (o WordListOptions) apply(query *gorm.DB) *gorm.DB {
+	query = o.filter(query)
+
+	if o.Sort.Field == WordSortSuccessRate {
+		// Words that were never reviewed have no rate and go last either way
+		query = query.Order("word_stats.word_id IS NULL")
+	}
+	if o.Sort.Field == WordSortFrequencyRank {
+		// Words missing from the frequency list go last either way
+		query = query.Order("words.frequency_rank IS NULL")
+	}
+	if ordered, ok := o.Sort.order(query, wordSortColumns, "words.id ASC"); ok {
+		return ordered
+	}
+	return query.Order("words.id ASC")
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CountWordsByProgressState returns the number of words in each progress
+// state. Every state is present; words without progress count as new.
+func (r *WordRepository) CountWordsByProgressState(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		State string
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Word{}).
+		Select("COALESCE(word_progress.state, ?) AS state, COUNT(*) AS count", models.ProgressNew).
+		Joins("LEFT JOIN word_progress ON word_progress.word_id = words.id").
+		Group("word_progress.state").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(models.ProgressStates))
+	for _, state := range models.ProgressStates {
+		counts[state] = 0
+	}
+	for _, row := range rows {
+		counts[row.State] += row.Count
+	}
+	return counts, nil
+}
+
+// advanceWordProgress moves the progress of the reviewed word on by a new review
+func advanceWordProgress(tx *gorm.DB, review *models.WordReview) error {
+	progress := models.WordProgress{WordID: review.WordID}
+	if err := tx.Where("word_id = ?", review.WordID).Limit(1).Find(&progress).Error; err != nil {
+		return err
+	}
+	reviewedAt := review.CreatedAt
+	if reviewedAt.IsZero() {
+		reviewedAt = time.Now()
+	}
+	progress.Advance(review.EffectiveGrade(), reviewedAt)
+	return saveWordProgress(tx, &progress)
+}
+
+// saveWordProgress inserts or replaces the progress of a word
+func saveWordProgress(tx *gorm.DB, progress *models.WordProgress) error {
+	progress.UpdatedAt = time.Now()
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "word_id"}},
+		UpdateAll: true,
+	}).Create(progress).Error
+}
+
+// replayReviews calls replay for every word with reviews, or only for the
+// given words, with the word's reviews, archived ones included, in the order
+// they were made
+func replayReviews(tx *gorm.DB, wordIDs []uint, replay func(wordID uint, reviews []models.WordReview) error) error {
+	query := tx.Table(allWordReviews + " AS word_review_items").Order("word_id ASC, created_at ASC, id ASC")
+	if len(wordIDs) > 0 {
+		query = query.Where("word_id IN ?", wordIDs)
+	}
+	var reviews []models.WordReview
+	if err := query.Find(&reviews).Error; err != nil {
+		return err
+	}
+
+	for start := 0; start < len(reviews); {
+		end := start + 1
+		for end < len(reviews) && reviews[end].WordID == reviews[start].WordID {
+			end++
+		}
+		if err := replay(reviews[start].WordID, reviews[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// replayWord rebuilds the schedule, accuracy, progress and stats of a word
+// from all of its reviews
+func replayWord(tx *gorm.DB, wordID uint, reviews []models.WordReview) error {
+	word := models.Word{ID: wordID}
+	for _, review := range reviews {
+		word.ScheduleGradedReview(review.EffectiveGrade(), review.CreatedAt)
+		word.RecordAccuracy(review.Correct)
+	}
+	if err := tx.Model(&models.Word{}).Where("id = ?", wordID).UpdateColumns(map[string]interface{}{
+		"last_reviewed_at": word.LastReviewedAt,
+		"next_due_at":      word.NextDueAt,
+		"accuracy_ewma":    word.AccuracyEWMA,
+	}).Error; err != nil {
+		return err
+	}
+	if err := replayWordProgress(tx, wordID, reviews); err != nil {
+		return err
+	}
+	return replayWordStats(tx, wordID, reviews)
+}
+
+// replayWordProgress rebuilds the progress of a word from all of its reviews
+func replayWordProgress(tx *gorm.DB, wordID uint, reviews []models.WordReview) error {
+	progress := models.WordProgress{WordID: wordID}
+	for _, review := range reviews {
+		progress.Advance(review.EffectiveGrade(), review.CreatedAt)
+	}
+	return saveWordProgress(tx, &progress)
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"lang-portal/backend_go/internal/models"
 	"lang-portal/backend_go/internal/testutil"
@@ -22,18 +24,18 @@ func TestWordRepository_CreateAndGet(t *testing.T) {
 	defer cleanup()
 
 	word := &models.Word{
-		Japanese: "こんにちは",
-		Romaji:   "konnichiwa",
-		English:  "hello",
-		Parts:    models.StringSlice{"greeting"},
+		Term:    "こんにちは",
+		Reading: "konnichiwa",
+		English: "hello",
+		Parts:   models.StringSlice{"greeting"},
 	}
-	err := repo.Create(word)
+	err := repo.Create(context.Background(), word)
 	require.NoError(t, err)
 	assert.NotZero(t, word.ID)
 
-	fetched, err := repo.GetByID(word.ID)
+	fetched, err := repo.GetByID(context.Background(), word.ID)
 	require.NoError(t, err)
-	assert.Equal(t, word.Japanese, fetched.Japanese)
+	assert.Equal(t, word.Term, fetched.Term)
 	assert.Equal(t, word.English, fetched.English)
 }
 
@@ -41,15 +43,15 @@ func TestWordRepository_Update(t *testing.T) {
 	repo, cleanup := setupWordRepo(t)
 	defer cleanup()
 	word := &models.Word{
-		Japanese: "ありがとう",
-		Romaji:   "arigatou",
-		English:  "thanks",
-		Parts:    models.StringSlice{"greeting"},
+		Term:    "ありがとう",
+		Reading: "arigatou",
+		English: "thanks",
+		Parts:   models.StringSlice{"greeting"},
 	}
-	err := repo.Create(word)
+	err := repo.Create(context.Background(), word)
 	require.NoError(t, err)
 	word.English = "thank you"
-	err = repo.Update(word)
+	err = repo.Update(context.Background(), word)
 	require.NoError(t, err)
 	assert.Equal(t, "thank you", word.English)
 }
@@ -58,16 +60,16 @@ func TestWordRepository_Delete(t *testing.T) {
 	repo, cleanup := setupWordRepo(t)
 	defer cleanup()
 	word := &models.Word{
-		Japanese: "さようなら",
-		Romaji:   "sayounara",
-		English:  "goodbye",
-		Parts:    models.StringSlice{"greeting"},
+		Term:    "さようなら",
+		Reading: "sayounara",
+		English: "goodbye",
+		Parts:   models.StringSlice{"greeting"},
 	}
-	err := repo.Create(word)
+	err := repo.Create(context.Background(), word)
 	require.NoError(t, err)
-	err = repo.Delete(word.ID)
+	err = repo.Delete(context.Background(), word.ID)
 	require.NoError(t, err)
-	_, err = repo.GetByID(word.ID)
+	_, err = repo.GetByID(context.Background(), word.ID)
 	assert.Error(t, err)
 }
 
@@ -76,37 +78,450 @@ func TestWordRepository_List(t *testing.T) {
 	defer cleanup()
 	for i := 0; i < 15; i++ {
 		word := &models.Word{
-			Japanese: "単語" + string(rune('A'+i)),
-			Romaji:   "tango" + string(rune('A'+i)),
-			English:  "word" + string(rune('A'+i)),
-			Parts:    models.StringSlice{"noun"},
+			Term:    "単語" + string(rune('A'+i)),
+			Reading: "tango" + string(rune('A'+i)),
+			English: "word" + string(rune('A'+i)),
+			Parts:   models.StringSlice{"noun"},
 		}
-		require.NoError(t, repo.Create(word))
+		require.NoError(t, repo.Create(context.Background(), word))
 	}
 	params := PaginationParams{Page: 1, PageSize: 10}
-	result, err := repo.List(params)
+	result, err := repo.List(context.Background(), params, WordListOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, 10, len(result.Items))
 	assert.Equal(t, int64(15), result.TotalItems)
 	assert.Equal(t, 2, result.TotalPages)
 }
 
+func TestWordRepository_ListSorted(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	// correct/wrong reviews per word; "c" is never reviewed
+	words := map[string][2]int{"a": {1, 3}, "b": {2, 0}, "c": {0, 0}, "d": {3, 1}}
+	ids := map[string]uint{}
+	for _, romaji := range []string{"b", "d", "a", "c"} {
+		word := &models.Word{Term: "語" + romaji, Reading: romaji, English: "word " + romaji, Parts: models.StringSlice{"noun"}}
+		require.NoError(t, repo.Create(context.Background(), word))
+		ids[romaji] = word.ID
+		for i := 0; i < words[romaji][0]; i++ {
+			require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: 1, Correct: true}).Error)
+		}
+		for i := 0; i < words[romaji][1]; i++ {
+			require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: 1, Correct: false}).Error)
+		}
+	}
+	_, err := repo.RecomputeWordStats(context.Background())
+	require.NoError(t, err)
+
+	romajiOf := func(result *PaginatedResult[models.Word]) []string {
+		out := make([]string, len(result.Items))
+		for i, w := range result.Items {
+			out[i] = w.Reading
+		}
+		return out
+	}
+	all := PaginationParams{Page: 1, PageSize: 10}
+
+	tests := []struct {
+		sort WordSort
+		want []string
+	}{
+		{WordSort{}, []string{"b", "d", "a", "c"}},
+		{WordSort{Field: WordSortRomaji}, []string{"a", "b", "c", "d"}},
+		{WordSort{Field: WordSortEnglish, Desc: true}, []string{"d", "c", "b", "a"}},
+		{WordSort{Field: WordSortCorrectCount, Desc: true}, []string{"d", "b", "a", "c"}},
+		{WordSort{Field: WordSortCorrectCount}, []string{"c", "a", "b", "d"}},
+		// Unreviewed words go last in both directions
+		{WordSort{Field: WordSortSuccessRate, Desc: true}, []string{"b", "d", "a", "c"}},
+		{WordSort{Field: WordSortSuccessRate}, []string{"a", "d", "b", "c"}},
+	}
+	for _, tt := range tests {
+		result, err := repo.List(context.Background(), all, WordListOptions{Sort: tt.sort})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, romajiOf(result), "sort %+v", tt.sort)
+	}
+
+	// Later pages keep the order and the total
+	page, err := repo.List(context.Background(), PaginationParams{Page: 2, PageSize: 3}, WordListOptions{Sort: WordSort{Field: WordSortRomaji}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d"}, romajiOf(page))
+	assert.Equal(t, int64(4), page.TotalItems)
+}
+
+func TestWordRepository_ListByStatus(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	db := repo.db
+
+	// correct/wrong reviews per word
+	reviews := map[string][2]int{"new": {0, 0}, "shaky": {1, 2}, "few": {2, 0}, "solid": {4, 1}}
+	for _, romaji := range []string{"new", "shaky", "few", "solid"} {
+		word := &models.Word{Term: "語" + romaji, Reading: romaji, English: romaji, Parts: models.StringSlice{"noun"}}
+		require.NoError(t, repo.Create(context.Background(), word))
+		for i := 0; i < reviews[romaji][0]; i++ {
+			require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: 1, Correct: true}).Error)
+		}
+		for i := 0; i < reviews[romaji][1]; i++ {
+			require.NoError(t, db.Create(&models.WordReview{WordID: word.ID, StudySessionID: 1, Correct: false}).Error)
+		}
+	}
+	_, err := repo.RecomputeWordStats(context.Background())
+	require.NoError(t, err)
+
+	all := PaginationParams{Page: 1, PageSize: 10}
+	tests := []struct {
+		status string
+		want   []string
+	}{
+		{WordStatusUnstudied, []string{"new"}},
+		// "few" has a perfect rate but too few reviews
+		{WordStatusLearning, []string{"shaky", "few"}},
+		{WordStatusMastered, []string{"solid"}},
+	}
+	for _, tt := range tests {
+		opts := WordListOptions{Status: WordStatusFilter{Status: tt.status, MasteredMinReviews: 5, MasteredMinSuccessRate: 0.8}}
+		result, err := repo.List(context.Background(), all, opts)
+		require.NoError(t, err)
+		var got []string
+		for _, w := range result.Items {
+			got = append(got, w.Reading)
+		}
+		assert.Equal(t, tt.want, got, tt.status)
+		assert.Equal(t, int64(len(tt.want)), result.TotalItems, tt.status)
+	}
+}
+
 func TestWordRepository_Stats(t *testing.T) {
 	repo, cleanup := setupWordRepo(t)
 	defer cleanup()
 	word := &models.Word{
-		Japanese: "テスト",
-		Romaji:   "tesuto",
-		English:  "test",
-		Parts:    models.StringSlice{"noun"},
+		Term:    "テスト",
+		Reading: "tesuto",
+		English: "test",
+		Parts:   models.StringSlice{"noun"},
 	}
-	require.NoError(t, repo.Create(word))
+	require.NoError(t, repo.Create(context.Background(), word))
 	// Add reviews
 	db := repo.db
 	db.Create(&models.WordReview{WordID: word.ID, Correct: true})
 	db.Create(&models.WordReview{WordID: word.ID, Correct: false})
-	correct, wrong, err := repo.GetStudyStats(word.ID)
+	_, err := repo.RecomputeWordStats(context.Background())
+	require.NoError(t, err)
+	correct, wrong, err := repo.GetStudyStats(context.Background(), word.ID)
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), correct)
 	assert.Equal(t, int64(1), wrong)
 }
+
+func TestWordRepository_GetAverageAnswerTime(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+	word := &models.Word{Term: "速い", Reading: "hayai", English: "fast", Parts: models.StringSlice{"adjective"}}
+	require.NoError(t, repo.Create(ctx, word))
+
+	avg, err := repo.GetAverageAnswerTime(ctx, word.ID)
+	require.NoError(t, err)
+	assert.Nil(t, avg, "no timed reviews")
+
+	fast, slow := 800, 2400
+	require.NoError(t, repo.db.Create(&models.WordReview{WordID: word.ID, Correct: true, AnswerTimeMs: &fast}).Error)
+	require.NoError(t, repo.db.Create(&models.WordReview{WordID: word.ID, Correct: false, AnswerTimeMs: &slow}).Error)
+	require.NoError(t, repo.db.Create(&models.WordReview{WordID: word.ID, Correct: true}).Error)
+
+	avg, err = repo.GetAverageAnswerTime(ctx, word.ID)
+	require.NoError(t, err)
+	require.NotNil(t, avg)
+	assert.Equal(t, 1600.0, *avg, "untimed reviews are ignored")
+}
+
+func TestWordRepository_UpdateSetsUpdatedAt(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	word := &models.Word{
+		Term:    "水",
+		Reading: "mizu",
+		English: "water",
+		Parts:   models.StringSlice{"noun"},
+	}
+	require.NoError(t, repo.Create(context.Background(), word))
+	assert.False(t, word.UpdatedAt.IsZero())
+	created := word.UpdatedAt
+
+	word.English = "cold water"
+	require.NoError(t, repo.Update(context.Background(), word))
+
+	fetched, err := repo.GetByID(context.Background(), word.ID)
+	require.NoError(t, err)
+	assert.False(t, fetched.UpdatedAt.Before(created))
+}
+
+func TestWordRepository_UpdateChecksVersion(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+	word := &models.Word{
+		Term:    "火",
+		Reading: "hi",
+		English: "fire",
+		Parts:   models.StringSlice{"noun"},
+	}
+	require.NoError(t, repo.Create(ctx, word))
+	assert.Equal(t, uint(1), word.Version)
+
+	// Two clients read the same version
+	first, err := repo.GetByID(ctx, word.ID)
+	require.NoError(t, err)
+	second, err := repo.GetByID(ctx, word.ID)
+	require.NoError(t, err)
+
+	first.English = "flame"
+	require.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, uint(2), first.Version)
+
+	second.Reading = "ka"
+	assert.Equal(t, ErrConflict, repo.Update(ctx, second))
+
+	fetched, err := repo.GetByID(ctx, word.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "flame", fetched.English)
+	assert.Equal(t, "hi", fetched.Reading, "the stale update changed nothing")
+	assert.Equal(t, uint(2), fetched.Version)
+
+	require.NoError(t, repo.Delete(ctx, word.ID))
+	assert.Equal(t, ErrNotFound, repo.Update(ctx, fetched))
+}
+
+func TestWordRepository_Search(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	for _, w := range []*models.Word{
+		{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}},
+		{Term: "子犬", Reading: "koinu", English: "puppy", Parts: models.StringSlice{"noun"}},
+		{Term: "猫", Reading: "neko", English: "100% cat", Parts: models.StringSlice{"noun"}},
+	} {
+		require.NoError(t, repo.Create(context.Background(), w))
+	}
+
+	words, err := repo.Search(context.Background(), "inu", 10)
+	require.NoError(t, err)
+	assert.Len(t, words, 2)
+
+	words, err = repo.Search(context.Background(), "DOG", 10)
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	assert.Equal(t, "犬", words[0].Term)
+
+	// LIKE wildcards in the query are matched literally
+	words, err = repo.Search(context.Background(), "%", 10)
+	require.NoError(t, err)
+	require.Len(t, words, 1)
+	assert.Equal(t, "猫", words[0].Term)
+}
+
+func TestWordRepository_FindWords(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+
+	accuracy := func(v float64) *float64 { return &v }
+	words := []*models.Word{
+		{Term: "食べる", Reading: "taberu", English: "to eat", Parts: models.StringSlice{"verb", "ichidan"}, AccuracyEWMA: accuracy(0.9)},
+		{Term: "行く", Reading: "iku", English: "to go", Parts: models.StringSlice{"verb", "godan"}, AccuracyEWMA: accuracy(0.2)},
+		{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}, AccuracyEWMA: accuracy(0.5)},
+		{Term: "猫", Reading: "neko", English: "cat", Parts: models.StringSlice{"noun"}},
+	}
+	for _, w := range words {
+		require.NoError(t, repo.Create(context.Background(), w))
+	}
+
+	weakest, err := repo.FindWords(context.Background(), WordFilter{ReviewedOnly: true, WeakestFirst: true, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, weakest, 2)
+	assert.Equal(t, "行く", weakest[0].Term)
+	assert.Equal(t, "犬", weakest[1].Term)
+
+	verbs, err := repo.FindWords(context.Background(), WordFilter{Part: "verb", WeakestFirst: true})
+	require.NoError(t, err)
+	require.Len(t, verbs, 2)
+	assert.Equal(t, "行く", verbs[0].Term)
+
+	parts, err := repo.GetReviewedParts(context.Background(), 2, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"verb"}, parts, "noun has only one reviewed word")
+}
+
+func TestWordRepository_CountDueByDay(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	for i, due := range []*time.Time{at(1, 8), at(3, 9), at(3, 23), at(4, 0), at(10, 12), nil} {
+		word := &models.Word{Term: string(rune('a' + i)), Reading: "r", English: "e", Parts: models.StringSlice{"noun"}, NextDueAt: due}
+		require.NoError(t, repo.Create(ctx, word))
+	}
+
+	days, err := repo.CountDueByDay(ctx, *at(5, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []DueDay{
+		{Day: "2025-03-01", Words: 1},
+		{Day: "2025-03-03", Words: 2},
+		{Day: "2025-03-04", Words: 1},
+	}, days)
+}
+
+func TestWordRepository_WordVectors(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	word := &models.Word{Term: "猫", Reading: "neko", English: "cat", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, repo.Create(ctx, word))
+
+	require.NoError(t, repo.SaveWordVectors(ctx, []models.WordVector{
+		{WordID: word.ID, Model: "m1", Source: "猫 (neko): cat", Dimensions: 1, Vector: []byte{1, 2, 3, 4}},
+		{WordID: word.ID + 100, Model: "m1", Source: "gone", Dimensions: 1, Vector: []byte{1, 2, 3, 4}},
+	}))
+	vectors, err := repo.GetWordVectors(ctx, "m1")
+	require.NoError(t, err)
+	require.Len(t, vectors, 1, "vectors of missing words are skipped")
+	assert.Equal(t, word.ID, vectors[0].WordID)
+
+	// A vector of another model replaces the stored one
+	require.NoError(t, repo.SaveWordVectors(ctx, []models.WordVector{
+		{WordID: word.ID, Model: "m2", Source: "猫 (neko): cat", Dimensions: 2, Vector: make([]byte, 8)},
+	}))
+	vectors, err = repo.GetWordVectors(ctx, "m1")
+	require.NoError(t, err)
+	assert.Empty(t, vectors)
+
+	require.NoError(t, repo.Delete(ctx, word.ID))
+	vectors, err = repo.GetWordVectors(ctx, "m2")
+	require.NoError(t, err)
+	assert.Empty(t, vectors)
+}
+
+func TestWordRepository_ListAfter(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, japanese := range []string{"犬", "猫", "鳥"} {
+		require.NoError(t, repo.Create(ctx, &models.Word{Term: japanese, Reading: "x", English: "x", Parts: models.StringSlice{"noun"}}))
+	}
+
+	first, err := repo.ListAfter(ctx, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	assert.Equal(t, "犬", first[0].Term)
+	rest, err := repo.ListAfter(ctx, first[1].ID, 2)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, "鳥", rest[0].Term)
+	none, err := repo.ListAfter(ctx, rest[0].ID, 2)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestWordRepository_PartsOfSpeech(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	err := repo.Create(ctx, &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun", "animal"}})
+	var unknown *UnknownPartsError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, []string{"animal"}, unknown.Parts)
+	count, err := repo.GetTotalWordCount(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count, "the word is not created")
+
+	word := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, repo.Create(ctx, word))
+	word.Parts = models.StringSlice{"noun", "counter"}
+	require.NoError(t, repo.Update(ctx, word))
+
+	// Words written without the repository are linked, adding their tags
+	seeded := &models.Word{Term: "猫", Reading: "neko", English: "cat", Parts: models.StringSlice{"noun", "animal"}}
+	require.NoError(t, repo.db.Create(seeded).Error)
+	linked, err := repo.LinkUnlinkedParts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, linked)
+
+	parts, err := repo.ListPartsOfSpeech(ctx)
+	require.NoError(t, err)
+	counts := make(map[string]int64)
+	for _, part := range parts {
+		counts[part.Name] = part.WordCount
+	}
+	assert.Equal(t, int64(2), counts["noun"])
+	assert.Equal(t, int64(1), counts["counter"])
+	assert.Equal(t, int64(1), counts["animal"])
+	assert.Equal(t, int64(0), counts["verb"])
+
+	list, err := repo.List(ctx, PaginationParams{Page: 1, PageSize: 10}, WordListOptions{Part: "animal"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "猫", list.Items[0].Term)
+
+	require.NoError(t, repo.Delete(ctx, seeded.ID))
+	parts, err = repo.ListPartsOfSpeech(ctx)
+	require.NoError(t, err)
+	for _, part := range parts {
+		if part.Name == "animal" {
+			assert.Zero(t, part.WordCount)
+		}
+	}
+}
+
+func TestWordRepository_WordFrequencies(t *testing.T) {
+	repo, cleanup := setupWordRepo(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	eat := &models.Word{Term: "食べる", Reading: "taberu", English: "to eat", Parts: models.StringSlice{"verb"}}
+	cat := &models.Word{Term: "ねこ", Reading: "neko", English: "cat", Parts: models.StringSlice{"noun"}}
+	dog := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	for _, w := range []*models.Word{eat, cat, dog} {
+		require.NoError(t, repo.Create(ctx, w))
+		assert.Nil(t, w.FrequencyRank)
+	}
+
+	matched, err := repo.ReplaceWordFrequencies(ctx, []models.WordFrequency{
+		{Japanese: "食べる", Reading: "たべる", Rank: 250},
+		{Japanese: "猫", Reading: "ねこ", Rank: 1800},
+		{Japanese: "見る", Reading: "みる", Rank: 90},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), matched, "ねこ matches by reading")
+
+	list, err := repo.List(ctx, PaginationParams{Page: 1, PageSize: 10}, WordListOptions{Sort: SortParams{Field: WordSortFrequencyRank, Desc: true}})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 3)
+	assert.Equal(t, []string{"ねこ", "食べる", "犬"}, []string{list.Items[0].Term, list.Items[1].Term, list.Items[2].Term}, "unranked words go last")
+
+	see := &models.Word{Term: "見る", Reading: "miru", English: "to see", Parts: models.StringSlice{"verb"}}
+	require.NoError(t, repo.Create(ctx, see))
+	require.NotNil(t, see.FrequencyRank)
+	assert.Equal(t, 90, *see.FrequencyRank)
+
+	see.Term = "観る"
+	require.NoError(t, repo.Update(ctx, see))
+	assert.Nil(t, see.FrequencyRank)
+
+	// A new list replaces the ranks of every word
+	_, err = repo.ReplaceWordFrequencies(ctx, []models.WordFrequency{{Japanese: "犬", Rank: 700}})
+	require.NoError(t, err)
+	got, err := repo.GetByID(ctx, eat.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.FrequencyRank)
+	got, err = repo.GetByID(ctx, dog.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.FrequencyRank)
+	assert.Equal(t, 700, *got.FrequencyRank)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// wordStatsTotals counts the correct and wrong reviews of every reviewed word,
+// archived ones included, from the reviews themselves
+const wordStatsTotals = `SELECT word_id,
+		SUM(CASE WHEN correct THEN 1 ELSE 0 END) AS correct_count,
+		SUM(CASE WHEN correct THEN 0 ELSE 1 END) AS wrong_count,
+		MAX(created_at) AS last_reviewed_at
+	FROM ` + allWordReviews + `
+	GROUP BY word_id`
+
+// recordWordStats adds a new review to the stats of its word
+func recordWordStats(tx *gorm.DB, review *models.WordReview) error {
+	stats := models.WordStats{WordID: review.WordID}
+	if err := tx.Where("word_id = ?", review.WordID).Limit(1).Find(&stats).Error; err != nil {
+		return err
+	}
+	reviewedAt := review.CreatedAt
+	if reviewedAt.IsZero() {
+		reviewedAt = time.Now()
+	}
+	stats.Record(review.Correct, reviewedAt)
+	return saveWordStats(tx, &stats)
+}
+
+// replayWordStats rebuilds the stats of a word from all of its reviews
+func replayWordStats(tx *gorm.DB, wordID uint, reviews []models.WordReview) error {
+	stats := models.WordStats{WordID: wordID}
+	for _, review := range reviews {
+		stats.Record(review.Correct, review.CreatedAt)
+	}
+	return saveWordStats(tx, &stats)
+}
+
+// saveWordStats inserts or replaces the stats of a word
+func saveWordStats(tx *gorm.DB, stats *models.WordStats) error {
+	stats.UpdatedAt = time.Now()
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "word_id"}},
+		UpdateAll: true,
+	}).Create(stats).Error
+}
+
+// RecomputeWordStats checks the stored review totals of every word against
+// its reviews and, when any differ, rebuilds all of them in one transaction.
+// It returns the number of words whose totals were wrong or missing.
+func (r *WordRepository) RecomputeWordStats(ctx context.Context) (int64, error) {
+	var drifted int64
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		err := tx.Raw(`SELECT COUNT(DISTINCT word_id) FROM (
+				SELECT word_id FROM (SELECT word_id, correct_count, wrong_count FROM (` + wordStatsTotals + `)
+					EXCEPT SELECT word_id, correct_count, wrong_count FROM word_stats)
+				UNION ALL
+				SELECT word_id FROM (SELECT word_id, correct_count, wrong_count FROM word_stats
+					EXCEPT SELECT word_id, correct_count, wrong_count FROM (` + wordStatsTotals + `))
+			)`).Scan(&drifted).Error
+		if err != nil || drifted == 0 {
+			return err
+		}
+
+		if err := tx.Where("1=1").Delete(&models.WordStats{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO word_stats (word_id, correct_count, wrong_count, last_reviewed_at, success_rate, updated_at)
+			SELECT word_id, correct_count, wrong_count, last_reviewed_at,
+				CAST(correct_count AS REAL) / (correct_count + wrong_count), ?
+			FROM (`+wordStatsTotals+`)`, time.Now()).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return drifted, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+)
+
+func TestWordStats_MaintainedOnReview(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	words := NewWordRepository(db)
+	study := NewStudyRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	first := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i, correct := range []bool{true, true, false, true} {
+		review := &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: correct, CreatedAt: first.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, study.AddWordReview(ctx, review))
+	}
+
+	var stats models.WordStats
+	require.NoError(t, db.First(&stats, "word_id = ?", word.ID).Error)
+	assert.Equal(t, int64(3), stats.CorrectCount)
+	assert.Equal(t, int64(1), stats.WrongCount)
+	assert.Equal(t, 0.75, stats.SuccessRate)
+	require.NotNil(t, stats.LastReviewedAt)
+	assert.True(t, first.Add(3*time.Minute).Equal(*stats.LastReviewedAt))
+
+	// The totals agree with the reviews, so the recompute has nothing to fix
+	drifted, err := words.RecomputeWordStats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, drifted)
+
+	listed, err := words.List(ctx, PaginationParams{Page: 1, PageSize: 10}, WordListOptions{})
+	require.NoError(t, err)
+	require.Len(t, listed.Items, 1)
+	correct, wrong := listed.Items[0].ReviewCounts()
+	assert.Equal(t, [2]int64{3, 1}, [2]int64{correct, wrong})
+}
+
+func TestWordStats_ReplacedReview(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	words := NewWordRepository(db)
+	study := NewStudyRepository(db)
+	ctx := context.Background()
+
+	word := testutil.CreateTestWord(t, db)
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	require.NoError(t, db.Model(activity).Update("review_dedup", models.ReviewDedupUpsert).Error)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+
+	require.NoError(t, study.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: false}))
+	require.NoError(t, study.AddWordReview(ctx, &models.WordReview{WordID: word.ID, StudySessionID: session.ID, Correct: true}))
+
+	correct, wrong, err := words.GetStudyStats(ctx, word.ID)
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{1, 0}, [2]int64{correct, wrong})
+}
+
+func TestWordStats_RecomputeRepairsDrift(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	words := NewWordRepository(db)
+	study := NewStudyRepository(db)
+	ctx := context.Background()
+
+	reviewed := testutil.CreateTestWord(t, db)
+	deleted := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: models.StringSlice{"noun"}}
+	require.NoError(t, words.Create(ctx, deleted))
+	group := testutil.CreateTestGroup(t, db)
+	activity := testutil.CreateTestStudyActivity(t, db)
+	session := testutil.CreateTestStudySession(t, db, group.ID, activity.ID)
+	require.NoError(t, study.AddWordReview(ctx, &models.WordReview{WordID: reviewed.ID, StudySessionID: session.ID, Correct: true}))
+	require.NoError(t, study.AddWordReview(ctx, &models.WordReview{WordID: deleted.ID, StudySessionID: session.ID, Correct: true}))
+
+	// One review written behind the repository's back, and totals left for
+	// reviews that are gone
+	require.NoError(t, db.Create(&models.WordReview{WordID: reviewed.ID, StudySessionID: session.ID, Correct: false}).Error)
+	require.NoError(t, db.Where("word_id = ?", deleted.ID).Delete(&models.WordReview{}).Error)
+
+	drifted, err := words.RecomputeWordStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), drifted)
+
+	correct, wrong, err := words.GetStudyStats(ctx, reviewed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, [2]int64{1, 1}, [2]int64{correct, wrong})
+	var stats models.WordStats
+	require.NoError(t, db.First(&stats, "word_id = ?", reviewed.ID).Error)
+	assert.Equal(t, 0.5, stats.SuccessRate)
+	var left int64
+	require.NoError(t, db.Model(&models.WordStats{}).Where("word_id = ?", deleted.ID).Count(&left).Error)
+	assert.Zero(t, left)
+
+	// Resetting the study history clears the totals
+	require.NoError(t, study.ResetStudyHistory(ctx, nil))
+	require.NoError(t, db.Model(&models.WordStats{}).Count(&left).Error)
+	assert.Zero(t, left)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// wordVectorBatchSize caps the vectors written per statement
+const wordVectorBatchSize = 100
+
+// GetWordVectors returns the stored vectors of an embedding model
+func (r *WordRepository) GetWordVectors(ctx context.Context, model string) ([]models.WordVector, error) {
+	var vectors []models.WordVector
+	if err := r.db.WithContext(ctx).Where("model = ?", model).Find(&vectors).Error; err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
+
+// SaveWordVectors inserts the vectors, replacing those stored for the same
+// words. Vectors of words deleted in the meantime are skipped.
+func (r *WordRepository) SaveWordVectors(ctx context.Context, vectors []models.WordVector) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+	wordIDs := make([]uint, len(vectors))
+	for i, vector := range vectors {
+		wordIDs[i] = vector.WordID
+	}
+
+	return r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&models.Word{}).Where("id IN ?", wordIDs).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		exists := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			exists[id] = true
+		}
+
+		now := time.Now()
+		kept := make([]models.WordVector, 0, len(vectors))
+		for _, vector := range vectors {
+			if exists[vector.WordID] {
+				vector.UpdatedAt = now
+				kept = append(kept, vector)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "word_id"}},
+			UpdateAll: true,
+		}).CreateInBatches(kept, wordVectorBatchSize).Error
+	})
+}
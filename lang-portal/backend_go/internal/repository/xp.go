@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// XPRepository handles database operations for experience points
+type XPRepository struct {
+	*BaseRepository
+}
+
+// NewXPRepository creates a new experience points repository
+func NewXPRepository(db *gorm.DB) *XPRepository {
+	return &XPRepository{BaseRepository: NewBaseRepository(db)}
+}
+
+// Award records an award and adds it to the learner's experience, setting
+// their level with level. An award for something the learner was already
+// rewarded for changes nothing and reports false. The learner's experience
+// after the award is returned either way.
+func (r *XPRepository) Award(ctx context.Context, award *models.XPAward, level func(xp int64) int) (*models.LearnerXP, bool, error) {
+	var learner models.LearnerXP
+	var awarded bool
+	err := r.WithTransaction(ctx, func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.XPAward{}).
+			Where("learner = ? AND source = ? AND source_id = ?", award.Learner, award.Source, award.SourceID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if err := tx.Where(models.LearnerXP{Learner: award.Learner}).
+			Attrs(models.LearnerXP{Level: level(0)}).
+			FirstOrCreate(&learner).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil
+		}
+
+		if err := tx.Create(award).Error; err != nil {
+			return err
+		}
+		learner.XP += award.Amount
+		learner.Level = level(learner.XP)
+		awarded = true
+		return tx.Model(&learner).Updates(map[string]interface{}{
+			"xp":         learner.XP,
+			"level":      learner.Level,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &learner, awarded, nil
+}
+
+// Get retrieves the experience of a learner, or returns ErrNotFound when they
+// have not earned any
+func (r *XPRepository) Get(ctx context.Context, learner string) (*models.LearnerXP, error) {
+	var xp models.LearnerXP
+	if err := r.db.WithContext(ctx).Where("learner = ?", learner).First(&xp).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &xp, nil
+}
+
+// SessionTotal sums the experience a learner earned in a study session
+func (r *XPRepository) SessionTotal(ctx context.Context, learner string, sessionID uint) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.XPAward{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("learner = ? AND study_session_id = ?", learner, sessionID).
+		Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXPRepository_Award(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	repo := NewXPRepository(db)
+	ctx := context.Background()
+	level := func(xp int64) int { return 1 + int(xp/100) }
+	session := uint(7)
+
+	_, err := repo.Get(ctx, "yuki")
+	assert.Equal(t, ErrNotFound, err)
+
+	xp, awarded, err := repo.Award(ctx, &models.XPAward{Learner: "yuki", Source: models.XPSourceReview, SourceID: 1, StudySessionID: &session, Amount: 60}, level)
+	require.NoError(t, err)
+	assert.True(t, awarded)
+	assert.Equal(t, int64(60), xp.XP)
+	assert.Equal(t, 1, xp.Level)
+
+	xp, awarded, err = repo.Award(ctx, &models.XPAward{Learner: "yuki", Source: models.XPSourceSession, SourceID: 7, StudySessionID: &session, Amount: 50}, level)
+	require.NoError(t, err)
+	assert.True(t, awarded)
+	assert.Equal(t, int64(110), xp.XP)
+	assert.Equal(t, 2, xp.Level)
+
+	// The same review is not rewarded twice, but another learner may be
+	xp, awarded, err = repo.Award(ctx, &models.XPAward{Learner: "yuki", Source: models.XPSourceReview, SourceID: 1, StudySessionID: &session, Amount: 60}, level)
+	require.NoError(t, err)
+	assert.False(t, awarded)
+	assert.Equal(t, int64(110), xp.XP)
+	_, awarded, err = repo.Award(ctx, &models.XPAward{Learner: "ken", Source: models.XPSourceReview, SourceID: 1, Amount: 10}, level)
+	require.NoError(t, err)
+	assert.True(t, awarded)
+
+	stored, err := repo.Get(ctx, "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, int64(110), stored.XP)
+	assert.Equal(t, 2, stored.Level)
+
+	total, err := repo.SessionTotal(ctx, "yuki", session)
+	require.NoError(t, err)
+	assert.Equal(t, int64(110), total)
+	total, err = repo.SessionTotal(ctx, "ken", session)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
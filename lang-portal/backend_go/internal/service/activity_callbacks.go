@@ -0,0 +1,209 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Headers sent with activity callbacks
+const (
+	CallbackEventHeader     = "X-Lang-Portal-Event"
+	CallbackSignatureHeader = "X-Lang-Portal-Signature"
+)
+
+// activityCallbackTimeout bounds a single callback request
+const activityCallbackTimeout = 10 * time.Second
+
+// Outbox delivery settings. The retry delay doubles after every failed
+// attempt, so an event is given up about an hour after it was recorded.
+const (
+	outboxPollInterval  = time.Second
+	outboxPruneInterval = time.Hour
+	outboxRetention     = 7 * 24 * time.Hour
+	outboxBatchSize     = 50
+	maxCallbackAttempts = 8
+	callbackRetryDelay  = 30 * time.Second
+)
+
+// errSessionGone gives up the callbacks of a session deleted since its event
+var errSessionGone = errors.New("study session no longer exists")
+
+// ActivityCallback is the body posted to the callback URL of an activity when
+// one of its sessions changes. EventID identifies the event across retries.
+type ActivityCallback struct {
+	EventID         uint            `json:"event_id"`
+	Event           string          `json:"event"`
+	StudyActivityID uint            `json:"study_activity_id"`
+	StudySessionID  uint            `json:"study_session_id"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// ActivityCallbacks delivers the session events recorded in the outbox to the
+// callback URL of the session's activity. The study repository records events
+// in the transaction of the change they describe, so a restart does not lose
+// them. Failed callbacks are retried with exponential backoff; a receiver may
+// see an event more than once and should ignore repeated event IDs.
+type ActivityCallbacks struct {
+	*BaseService
+	outbox repository.OutboxRepositoryInterface
+	client *http.Client
+	logger *log.Logger
+}
+
+// NewActivityCallbacks creates a callback dispatcher reading from outbox. A nil
+// client uses one with a 10 second timeout.
+func NewActivityCallbacks(base *BaseService, outbox repository.OutboxRepositoryInterface, client *http.Client, logger *log.Logger) *ActivityCallbacks {
+	if client == nil {
+		client = &http.Client{Timeout: activityCallbackTimeout}
+	}
+	return &ActivityCallbacks{BaseService: base, outbox: outbox, client: client, logger: logger}
+}
+
+// Run polls the outbox and delivers due events until ctx is done. Delivered
+// and given up events are pruned after a week.
+func (c *ActivityCallbacks) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	var pruned time.Time
+	for {
+		now := time.Now()
+		if _, err := c.Dispatch(ctx, now); err != nil && ctx.Err() == nil {
+			c.logger.Printf("Activity callbacks: %v", err)
+		}
+		if now.Sub(pruned) >= outboxPruneInterval {
+			if _, err := c.outbox.PruneSettled(ctx, now.Add(-outboxRetention)); err != nil && ctx.Err() == nil {
+				c.logger.Printf("Failed to prune the outbox: %v", err)
+			}
+			pruned = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch attempts the outbox events due at now, in the order they were
+// recorded, and returns how many were settled
+func (c *ActivityCallbacks) Dispatch(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "ActivityCallbacks.Dispatch")
+	defer span.End()
+
+	due, err := c.outbox.ListDue(ctx, now, outboxBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the outbox: %w", err)
+	}
+	for i, event := range due {
+		if err := c.settle(ctx, event, c.deliver(ctx, event), now); err != nil {
+			return i, fmt.Errorf("failed to settle outbox event %d: %w", event.ID, err)
+		}
+	}
+	return len(due), nil
+}
+
+// Flush delivers the events that are due until none are left or ctx is done.
+// It is called on shutdown, after Run has returned, so that callbacks for the
+// last requests are not held back until the next start. Failed events stay in
+// the outbox for their retry.
+func (c *ActivityCallbacks) Flush(ctx context.Context) error {
+	for {
+		settled, err := c.Dispatch(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if settled < outboxBatchSize {
+			return nil
+		}
+	}
+}
+
+// settle records the outcome of an attempt to deliver an event
+func (c *ActivityCallbacks) settle(ctx context.Context, event models.OutboxEvent, err error, now time.Time) error {
+	if err == nil {
+		return c.outbox.MarkDelivered(ctx, event.ID, now)
+	}
+
+	attempts := event.Attempts + 1
+	if attempts >= maxCallbackAttempts || errors.Is(err, errSessionGone) {
+		c.logger.Printf("Gave up activity callback %d for session %d after %d attempts: %v", event.ID, event.StudySessionID, attempts, err)
+		return c.outbox.MarkFailed(ctx, event.ID, err.Error(), now, nil)
+	}
+	retryAt := now.Add(callbackRetryDelay << event.Attempts)
+	c.logger.Printf("Activity callback %d for session %d failed, retrying at %s: %v", event.ID, event.StudySessionID, retryAt.Format(time.RFC3339), err)
+	return c.outbox.MarkFailed(ctx, event.ID, err.Error(), now, &retryAt)
+}
+
+// deliver posts an event to its session's activity's callback URL. Events of
+// activities without one are delivered without a request.
+func (c *ActivityCallbacks) deliver(ctx context.Context, event models.OutboxEvent) error {
+	session, err := c.studyRepo.GetStudySessionByID(ctx, event.StudySessionID)
+	if err == repository.ErrNotFound {
+		return errSessionGone
+	}
+	if err != nil {
+		return err
+	}
+	activity := session.Activity
+	if activity.CallbackURL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(ActivityCallback{
+		EventID:         event.ID,
+		Event:           event.Type,
+		StudyActivityID: activity.ID,
+		StudySessionID:  event.StudySessionID,
+		Data:            json.RawMessage(event.Payload),
+		CreatedAt:       event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return c.post(ctx, activity.CallbackURL, activity.CallbackSecret, event.Type, body)
+}
+
+func (c *ActivityCallbacks) post(ctx context.Context, url, secret, eventType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(CallbackEventHeader, eventType)
+	if secret != "" {
+		req.Header.Set(CallbackSignatureHeader, SignCallback(secret, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// SignCallback returns the signature header value of a callback body: the hex
+// HMAC-SHA256 of the body keyed with the callback secret, prefixed with "sha256="
+func SignCallback(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
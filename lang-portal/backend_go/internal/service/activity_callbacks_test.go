@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSessionRepository implements the session lookup of StudyRepositoryInterface
+type mockSessionRepository struct {
+	repository.StudyRepositoryInterface
+	mock.Mock
+}
+
+func (m *mockSessionRepository) GetStudySessionByID(ctx context.Context, id uint) (*models.StudySession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StudySession), args.Error(1)
+}
+
+// mockOutboxRepository implements OutboxRepositoryInterface
+type mockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *mockOutboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.OutboxEvent), args.Error(1)
+}
+
+func (m *mockOutboxRepository) MarkDelivered(ctx context.Context, id uint, at time.Time) error {
+	return m.Called(id, at).Error(0)
+}
+
+func (m *mockOutboxRepository) MarkFailed(ctx context.Context, id uint, cause string, failedAt time.Time, retryAt *time.Time) error {
+	return m.Called(id, cause, failedAt, retryAt).Error(0)
+}
+
+func (m *mockOutboxRepository) PruneSettled(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestActivityCallbacks_DispatchSignsSessionEvents(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{r.Header, body}
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	sessions := new(mockSessionRepository)
+	sessions.On("GetStudySessionByID", uint(7)).Return(&models.StudySession{
+		ID:              7,
+		StudyActivityID: 3,
+		Activity:        models.StudyActivity{ID: 3, CallbackURL: server.URL, CallbackSecret: "0123456789abcdef"},
+	}, nil)
+	outbox := new(mockOutboxRepository)
+	outbox.On("ListDue", now, outboxBatchSize).Return([]models.OutboxEvent{{
+		ID:             11,
+		Type:           events.TypeWordReviewCreated,
+		StudySessionID: 7,
+		Payload:        `{"correct":true,"study_session_id":7,"word_id":1}`,
+	}}, nil)
+	outbox.On("MarkDelivered", uint(11), now).Return(nil)
+	callbacks := NewActivityCallbacks(NewBaseService(nil, nil, sessions, nil, nil), outbox, server.Client(), log.New(io.Discard, "", 0))
+
+	settled, err := callbacks.Dispatch(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, settled)
+
+	req := <-requests
+	assert.Equal(t, events.TypeWordReviewCreated, req.header.Get(CallbackEventHeader))
+	assert.Equal(t, SignCallback("0123456789abcdef", req.body), req.header.Get(CallbackSignatureHeader))
+
+	var callback ActivityCallback
+	require.NoError(t, json.Unmarshal(req.body, &callback))
+	assert.Equal(t, uint(11), callback.EventID)
+	assert.Equal(t, uint(3), callback.StudyActivityID)
+	assert.Equal(t, uint(7), callback.StudySessionID)
+	assert.JSONEq(t, `{"correct":true,"study_session_id":7,"word_id":1}`, string(callback.Data))
+	outbox.AssertExpectations(t)
+}
+
+func TestActivityCallbacks_DispatchRetriesFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	sessions := new(mockSessionRepository)
+	sessions.On("GetStudySessionByID", uint(7)).Return(&models.StudySession{
+		ID:       7,
+		Activity: models.StudyActivity{ID: 3, CallbackURL: server.URL},
+	}, nil)
+	sessions.On("GetStudySessionByID", uint(8)).Return(&models.StudySession{ID: 8, Activity: models.StudyActivity{ID: 4}}, nil)
+	sessions.On("GetStudySessionByID", uint(9)).Return(nil, repository.ErrNotFound)
+
+	outbox := new(mockOutboxRepository)
+	outbox.On("ListDue", now, outboxBatchSize).Return([]models.OutboxEvent{
+		{ID: 1, Type: events.TypeStudySessionCreated, StudySessionID: 7, Payload: "{}"},
+		{ID: 2, Type: events.TypeStudySessionCreated, StudySessionID: 7, Payload: "{}", Attempts: 2},
+		{ID: 3, Type: events.TypeStudySessionCreated, StudySessionID: 7, Payload: "{}", Attempts: maxCallbackAttempts - 1},
+		{ID: 4, Type: events.TypeStudySessionCreated, StudySessionID: 8, Payload: "{}"},
+		{ID: 5, Type: events.TypeStudySessionCreated, StudySessionID: 9, Payload: "{}"},
+	}, nil)
+	firstRetry := now.Add(callbackRetryDelay)
+	thirdRetry := now.Add(4 * callbackRetryDelay)
+	status := "unexpected status 503 Service Unavailable"
+	outbox.On("MarkFailed", uint(1), status, now, &firstRetry).Return(nil)
+	outbox.On("MarkFailed", uint(2), status, now, &thirdRetry).Return(nil)
+	outbox.On("MarkFailed", uint(3), status, now, (*time.Time)(nil)).Return(nil)
+	// Activities without a callback URL have nothing to deliver
+	outbox.On("MarkDelivered", uint(4), now).Return(nil)
+	// Events of deleted sessions are given up at once
+	outbox.On("MarkFailed", uint(5), errSessionGone.Error(), now, (*time.Time)(nil)).Return(nil)
+	callbacks := NewActivityCallbacks(NewBaseService(nil, nil, sessions, nil, nil), outbox, server.Client(), log.New(io.Discard, "", 0))
+
+	settled, err := callbacks.Dispatch(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 5, settled)
+	outbox.AssertExpectations(t)
+}
+
+func TestActivityCallbacks_FlushDrainsDueEvents(t *testing.T) {
+	sessions := new(mockSessionRepository)
+	sessions.On("GetStudySessionByID", uint(7)).Return(&models.StudySession{ID: 7}, nil)
+
+	full := make([]models.OutboxEvent, outboxBatchSize)
+	for i := range full {
+		full[i] = models.OutboxEvent{ID: uint(i + 1), Type: events.TypeWordReviewCreated, StudySessionID: 7}
+	}
+	outbox := new(mockOutboxRepository)
+	outbox.On("ListDue", mock.Anything, outboxBatchSize).Return(full, nil).Once()
+	outbox.On("ListDue", mock.Anything, outboxBatchSize).Return([]models.OutboxEvent{
+		{ID: uint(outboxBatchSize + 1), Type: events.TypeWordReviewCreated, StudySessionID: 7},
+	}, nil).Once()
+	outbox.On("MarkDelivered", mock.Anything, mock.Anything).Return(nil)
+	callbacks := NewActivityCallbacks(NewBaseService(nil, nil, sessions, nil, nil), outbox, nil, log.New(io.Discard, "", 0))
+
+	require.NoError(t, callbacks.Flush(context.Background()))
+	outbox.AssertNumberOfCalls(t, "ListDue", 2)
+	outbox.AssertNumberOfCalls(t, "MarkDelivered", outboxBatchSize+1)
+
+	// A cancelled flush leaves the events for the next start
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	outbox.On("ListDue", mock.Anything, outboxBatchSize).Return([]models.OutboxEvent(nil), context.Canceled)
+	assert.ErrorIs(t, callbacks.Flush(ctx), context.Canceled)
+}
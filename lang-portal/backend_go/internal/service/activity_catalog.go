@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// ActivityCatalogVersion is the format version written by ExportActivityCatalog
+// and accepted by ImportActivityCatalog
+const ActivityCatalogVersion = 1
+
+// ActivityCatalog is a portable description of the study activities offered by an
+// instance. Activities are identified by name; IDs are local to each instance.
+type ActivityCatalog struct {
+	Version    int                    `json:"version"`
+	ExportedAt time.Time              `json:"exported_at"`
+	Activities []ActivityCatalogEntry `json:"activities"`
+}
+
+// ActivityCatalogEntry describes a single activity in a catalog. Thumbnails are
+// exported as references, not embedded; callback secrets are not exported.
+type ActivityCatalogEntry struct {
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	ThumbnailURL   string          `json:"thumbnail_url"`
+	SettingsSchema json.RawMessage `json:"settings_schema,omitempty"`
+	LaunchURL      string          `json:"launch_url,omitempty"`
+	Modes          []string        `json:"modes,omitempty"`
+	Capabilities   []string        `json:"capabilities,omitempty"`
+	Config         json.RawMessage `json:"config,omitempty"`
+	CallbackURL    string          `json:"callback_url,omitempty"`
+	// ReviewDedup is how repeated reviews of a word in a session are handled:
+	// "reject", "upsert", or empty to record every review
+	ReviewDedup string `json:"review_dedup,omitempty"`
+}
+
+// activityTag matches mode and capability names
+var activityTag = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// maxActivityTags caps the number of modes and of capabilities of an activity
+const maxActivityTags = 20
+
+// activity validates the entry and converts it to a model. Errors name the activity.
+func (e ActivityCatalogEntry) activity() (models.StudyActivity, error) {
+	name := strings.TrimSpace(e.Name)
+	if name == "" {
+		return models.StudyActivity{}, NewServiceError(ErrCodeInvalidInput, "Activity name must not be empty", nil)
+	}
+	invalid := func(message string) error {
+		return NewServiceError(ErrCodeInvalidInput, message+": "+name, nil)
+	}
+
+	schema, ok := jsonObject(e.SettingsSchema)
+	if !ok {
+		return models.StudyActivity{}, invalid("Settings schema must be a JSON object")
+	}
+	config, ok := jsonObject(e.Config)
+	if !ok {
+		return models.StudyActivity{}, invalid("Config must be a JSON object")
+	}
+	for _, tags := range [][]string{e.Modes, e.Capabilities} {
+		if len(tags) > maxActivityTags {
+			return models.StudyActivity{}, invalid(fmt.Sprintf("At most %d modes and %d capabilities are allowed", maxActivityTags, maxActivityTags))
+		}
+		for _, tag := range tags {
+			if !activityTag.MatchString(tag) {
+				return models.StudyActivity{}, invalid(fmt.Sprintf("Invalid mode or capability %q, use lowercase letters, digits, _ and -", tag))
+			}
+		}
+	}
+	if !models.ValidReviewDedup(e.ReviewDedup) {
+		return models.StudyActivity{}, invalid(fmt.Sprintf("Unknown review dedup mode %q, use reject or upsert", e.ReviewDedup))
+	}
+	for _, raw := range []string{e.LaunchURL, e.CallbackURL} {
+		if raw == "" {
+			continue
+		}
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return models.StudyActivity{}, invalid("Launch and callback URLs must be absolute http or https URLs")
+		}
+	}
+
+	return models.StudyActivity{
+		Name:           name,
+		Description:    e.Description,
+		ThumbnailURL:   e.ThumbnailURL,
+		SettingsSchema: models.RawJSON(schema),
+		LaunchURL:      e.LaunchURL,
+		Modes:          models.StringSlice(e.Modes),
+		Capabilities:   models.StringSlice(e.Capabilities),
+		Config:         models.RawJSON(config),
+		CallbackURL:    e.CallbackURL,
+		ReviewDedup:    e.ReviewDedup,
+	}, nil
+}
+
+// jsonObject trims raw and reports whether it is empty, null or an object;
+// empty and null become ""
+func jsonObject(raw json.RawMessage) (string, bool) {
+	value := strings.TrimSpace(string(raw))
+	if value == "null" {
+		value = ""
+	}
+	return value, value == "" || strings.HasPrefix(value, "{")
+}
+
+func newActivityCatalogEntry(a models.StudyActivity) ActivityCatalogEntry {
+	return ActivityCatalogEntry{
+		Name:           a.Name,
+		Description:    a.Description,
+		ThumbnailURL:   a.ThumbnailURL,
+		SettingsSchema: json.RawMessage(a.SettingsSchema),
+		LaunchURL:      a.LaunchURL,
+		Modes:          a.Modes,
+		Capabilities:   a.Capabilities,
+		Config:         json.RawMessage(a.Config),
+		CallbackURL:    a.CallbackURL,
+		ReviewDedup:    a.ReviewDedup,
+	}
+}
+
+// ActivityImportResult reports how an imported catalog was applied
+type ActivityImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// ExportActivityCatalog returns every study activity as a catalog
+func (s *StudyService) ExportActivityCatalog(ctx context.Context) (*ActivityCatalog, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.ExportActivityCatalog")
+	defer span.End()
+
+	activities, err := s.studyRepo.ListAllStudyActivities(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list study activities", err)
+	}
+
+	catalog := &ActivityCatalog{
+		Version:    ActivityCatalogVersion,
+		ExportedAt: time.Now().UTC(),
+		Activities: make([]ActivityCatalogEntry, len(activities)),
+	}
+	for i, a := range activities {
+		catalog.Activities[i] = newActivityCatalogEntry(a)
+	}
+	return catalog, nil
+}
+
+// ImportActivityCatalog creates activities missing from this instance and updates
+// the ones that already exist, matched by name. Activities not in the catalog are
+// left untouched.
+func (s *StudyService) ImportActivityCatalog(ctx context.Context, catalog ActivityCatalog, actor string) (*ActivityImportResult, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.ImportActivityCatalog")
+	defer span.End()
+
+	if catalog.Version != ActivityCatalogVersion {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Unsupported catalog version", nil)
+	}
+	if len(catalog.Activities) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Catalog contains no activities", nil)
+	}
+
+	seen := make(map[string]bool, len(catalog.Activities))
+	activities := make([]models.StudyActivity, len(catalog.Activities))
+	for i, entry := range catalog.Activities {
+		activity, err := entry.activity()
+		if err != nil {
+			return nil, err
+		}
+		if seen[activity.Name] {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Duplicate activity in catalog: "+activity.Name, nil)
+		}
+		seen[activity.Name] = true
+		activities[i] = activity
+	}
+
+	created, updated, err := s.studyRepo.UpsertStudyActivities(ctx, activities)
+	if err != nil {
+		if err == repository.ErrInvalidInput {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Catalog contains an invalid activity", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to import study activities", err)
+	}
+
+	result := &ActivityImportResult{Created: created, Updated: updated}
+	if err := s.recordAudit(ctx, actor, models.AuditActionImport, models.AuditEntityActivity, nil, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// builtinActivity returns the study activity named like template, creating it
+// from template the first time. Activities the server generates content for,
+// such as cloze and listening quizzes, record their sessions under these.
+func (s *BaseService) builtinActivity(ctx context.Context, template models.StudyActivity) (*models.StudyActivity, error) {
+	activity, err := s.studyRepo.GetStudyActivityByName(ctx, template.Name)
+	if err == nil {
+		return activity, nil
+	}
+	if err != repository.ErrNotFound {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study activity", err)
+	}
+
+	activity = &template
+	if err := s.studyRepo.CreateStudyActivity(ctx, activity); err != nil {
+		// Created concurrently by another request
+		if existing, getErr := s.studyRepo.GetStudyActivityByName(ctx, template.Name); getErr == nil {
+			return existing, nil
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create study activity", err)
+	}
+	return activity, nil
+}
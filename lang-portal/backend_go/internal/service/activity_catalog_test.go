@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStudyService_ImportActivityCatalog_InvalidInput(t *testing.T) {
+	studyService := NewStudyService(NewBaseService(nil, nil, nil, nil, nil))
+
+	valid := ActivityCatalogEntry{Name: "Flashcards", Description: "Flip through cards", ThumbnailURL: "https://example.com/f.png"}
+	tests := []struct {
+		name    string
+		catalog ActivityCatalog
+	}{
+		{"unsupported version", ActivityCatalog{Version: 2, Activities: []ActivityCatalogEntry{valid}}},
+		{"empty catalog", ActivityCatalog{Version: ActivityCatalogVersion}},
+		{"blank name", ActivityCatalog{Version: ActivityCatalogVersion, Activities: []ActivityCatalogEntry{{Name: " "}}}},
+		{"duplicate name", ActivityCatalog{Version: ActivityCatalogVersion, Activities: []ActivityCatalogEntry{valid, valid}}},
+		{"schema not an object", ActivityCatalog{Version: ActivityCatalogVersion, Activities: []ActivityCatalogEntry{
+			{Name: "Quiz", Description: "Quiz", ThumbnailURL: "https://example.com/q.png", SettingsSchema: json.RawMessage(`[1]`)},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := studyService.ImportActivityCatalog(context.Background(), tt.catalog, "test")
+			assert.Error(t, err)
+			assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+		})
+	}
+}
+
+func TestStudyService_RegisterStudyActivity_InvalidInput(t *testing.T) {
+	studyService := NewStudyService(NewBaseService(nil, nil, nil, nil, nil))
+
+	entry := ActivityCatalogEntry{Name: "Typing Tutor", Description: "Type what you hear", ThumbnailURL: "https://example.com/t.png"}
+	with := func(change func(*ActivityRegistration)) ActivityRegistration {
+		reg := ActivityRegistration{ActivityCatalogEntry: entry}
+		change(&reg)
+		return reg
+	}
+	tests := []struct {
+		name string
+		reg  ActivityRegistration
+	}{
+		{"relative launch URL", with(func(r *ActivityRegistration) { r.LaunchURL = "/typing" })},
+		{"non-http callback URL", with(func(r *ActivityRegistration) { r.CallbackURL = "ftp://example.com/cb" })},
+		{"invalid mode", with(func(r *ActivityRegistration) { r.Modes = []string{"Typing Mode"} })},
+		{"config not an object", with(func(r *ActivityRegistration) { r.Config = json.RawMessage(`"fast"`) })},
+		{"unknown review dedup mode", with(func(r *ActivityRegistration) { r.ReviewDedup = "ignore" })},
+		{"short secret", with(func(r *ActivityRegistration) { r.CallbackSecret = "secret" })},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := studyService.RegisterStudyActivity(context.Background(), tt.reg, "test")
+			assert.Error(t, err)
+			assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// LaunchTokenTTL is how long a launch token is accepted after it was issued
+const LaunchTokenTTL = time.Hour
+
+// LaunchTokenParam is the query parameter carrying the token in launch URLs
+const LaunchTokenParam = "launch_token"
+
+// Launch token errors
+var (
+	ErrInvalidLaunchToken = errors.New("invalid launch token")
+	ErrExpiredLaunchToken = errors.New("expired launch token")
+)
+
+// LaunchClaims identify the session, group and user an activity was launched for
+type LaunchClaims struct {
+	SessionID  uint   `json:"sid"`
+	GroupID    uint   `json:"gid"`
+	ActivityID uint   `json:"aid"`
+	User       string `json:"sub"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+// ActivityLaunch is a new session of an external activity, with the URL that
+// opens it and the token the activity authenticates with
+type ActivityLaunch struct {
+	Session     StudySessionInfo `json:"session"`
+	LaunchURL   string           `json:"launch_url"`
+	LaunchToken string           `json:"launch_token"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// WithLaunchKey signs launch tokens with key. Without one, a key is generated on
+// first use and kept in the settings table.
+func (s *StudyService) WithLaunchKey(key []byte) *StudyService {
+	s.launchKey.set(key)
+	return s
+}
+
+// LaunchStudyActivity creates a study session of an external activity for a
+// group and returns its launch URL with a signed token identifying the session,
+// group and user. The session records the client it was launched from.
+func (s *StudyService) LaunchStudyActivity(ctx context.Context, activityID, groupID uint, user string, client models.ClientInfo) (*ActivityLaunch, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.LaunchStudyActivity")
+	defer span.End()
+
+	activity, err := s.studyRepo.GetStudyActivityByID(ctx, activityID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Study activity not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study activity", err)
+	}
+	if activity.LaunchURL == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Study activity has no launch URL", nil)
+	}
+	// Load the key before creating the session, so that no session is left
+	// behind without a token
+	key, err := s.launchKey.get(ctx, s.settingRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.StudySession{GroupID: groupID, StudyActivityID: activityID, Learner: user, Client: client}
+	if err := s.CreateStudySession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().UTC().Add(LaunchTokenTTL).Truncate(time.Second)
+	token := signLaunchToken(key, LaunchClaims{
+		SessionID:  session.ID,
+		GroupID:    groupID,
+		ActivityID: activityID,
+		User:       user,
+		ExpiresAt:  expiresAt.Unix(),
+	})
+
+	launchURL, err := url.Parse(activity.SessionLaunchURL(session.ID, groupID))
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Invalid launch URL", err)
+	}
+	q := launchURL.Query()
+	q.Set(LaunchTokenParam, token)
+	launchURL.RawQuery = q.Encode()
+
+	return &ActivityLaunch{
+		Session:     newStudySessionInfo(*session),
+		LaunchURL:   launchURL.String(),
+		LaunchToken: token,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// signLaunchToken encodes the claims and their HMAC-SHA256 as two base64url
+// parts separated by a dot
+func signLaunchToken(key []byte, claims LaunchClaims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(launchSignature(key, encoded))
+}
+
+// parseLaunchToken verifies a token signed with key and returns its claims
+func parseLaunchToken(key []byte, token string, now time.Time) (*LaunchClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidLaunchToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, launchSignature(key, encoded)) {
+		return nil, ErrInvalidLaunchToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidLaunchToken
+	}
+
+	var claims LaunchClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.SessionID == 0 {
+		return nil, ErrInvalidLaunchToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredLaunchToken
+	}
+	return &claims, nil
+}
+
+func launchSignature(key []byte, encoded string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// maxActivityReviews caps the reviews in a single batch
+const maxActivityReviews = 500
+
+// batchIDPattern matches the batch IDs chosen by activities
+var batchIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// ActivityReviewBatch is a batch of reviews an external activity submits for the
+// session its launch token identifies
+type ActivityReviewBatch struct {
+	// BatchID is chosen by the activity and accepted once per session, so that
+	// a replayed or retried submission is not counted twice
+	BatchID string           `json:"batch_id"`
+	Reviews []ActivityReview `json:"reviews"`
+}
+
+// ActivityReview is a single answer within a batch
+type ActivityReview struct {
+	WordID       uint               `json:"word_id"`
+	Correct      bool               `json:"correct"`
+	Grade        models.ReviewGrade `json:"grade,omitempty"`
+	AnswerTimeMs *int               `json:"answer_time_ms,omitempty"`
+}
+
+// ActivityReviewResult reports an accepted batch
+type ActivityReviewResult struct {
+	StudySessionID uint   `json:"study_session_id"`
+	BatchID        string `json:"batch_id"`
+	Accepted       int    `json:"accepted"`
+}
+
+// SubmitActivityReviews adds a batch of reviews to the session identified by a
+// launch token. A batch is added completely or not at all.
+func (s *StudyService) SubmitActivityReviews(ctx context.Context, token string, batch ActivityReviewBatch) (*ActivityReviewResult, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.SubmitActivityReviews")
+	defer span.End()
+
+	key, err := s.launchKey.get(ctx, s.settingRepo)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := parseLaunchToken(key, token, time.Now())
+	if err == ErrExpiredLaunchToken {
+		return nil, NewServiceError(ErrCodeUnauthorized, "Launch token has expired", nil)
+	}
+	if err != nil {
+		return nil, NewServiceError(ErrCodeUnauthorized, "Invalid launch token", nil)
+	}
+
+	if !batchIDPattern.MatchString(batch.BatchID) {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Batch ID must be 1 to 64 letters, digits, '.', '_', ':' or '-'", nil)
+	}
+	if len(batch.Reviews) == 0 || len(batch.Reviews) > maxActivityReviews {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("A batch must contain 1 to %d reviews", maxActivityReviews), nil)
+	}
+
+	// The session may have been deleted by a reset since the token was issued
+	session, err := s.studyRepo.GetStudySessionByID(ctx, claims.SessionID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeUnauthorized, "Launch token refers to a missing study session", nil)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
+	}
+	if session.StudyActivityID != claims.ActivityID || session.GroupID != claims.GroupID {
+		return nil, NewServiceError(ErrCodeUnauthorized, "Invalid launch token", nil)
+	}
+
+	reviews := make([]models.WordReview, len(batch.Reviews))
+	for i, r := range batch.Reviews {
+		reviews[i] = models.WordReview{WordID: r.WordID, Correct: r.Correct, Grade: r.Grade, AnswerTimeMs: r.AnswerTimeMs}
+		if err := checkReview(&reviews[i]); err != nil {
+			return nil, err
+		}
+	}
+	record := &models.ActivityReviewBatch{StudySessionID: session.ID, BatchID: batch.BatchID}
+	if err := s.studyRepo.AddActivityReviewBatch(ctx, record, reviews); err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return nil, NewServiceError(ErrCodeConflict, "Batch has already been submitted: "+batch.BatchID, nil)
+		case repository.ErrNotFound:
+			return nil, NewServiceError(ErrCodeInvalidInput, "Batch reviews a word that does not exist", nil)
+		case repository.ErrDuplicateReview:
+			return nil, NewServiceError(ErrCodeConflict, "Batch reviews a word already reviewed in this session", nil)
+		case repository.ErrInvalidInput:
+			return nil, NewServiceError(ErrCodeInvalidInput, "Every review needs a word_id", nil)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to add reviews", err)
+	}
+
+	s.awardReviewXP(ctx, session, reviews...)
+	s.invalidateDashboard()
+	for _, review := range reviews {
+		s.publish(events.TypeWordReviewCreated, map[string]interface{}{
+			"study_session_id": session.ID,
+			"word_id":          review.WordID,
+			"correct":          review.Correct,
+			"grade":            review.EffectiveGrade(),
+		})
+	}
+	return &ActivityReviewResult{StudySessionID: session.ID, BatchID: batch.BatchID, Accepted: len(reviews)}, nil
+}
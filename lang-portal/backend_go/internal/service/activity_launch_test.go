@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+func TestParseLaunchToken(t *testing.T) {
+	key := []byte("secret")
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	claims := LaunchClaims{SessionID: 7, GroupID: 2, ActivityID: 3, User: "ip:127.0.0.1", ExpiresAt: now.Add(LaunchTokenTTL).Unix()}
+	token := signLaunchToken(key, claims)
+
+	parsed, err := parseLaunchToken(key, token, now)
+	require.NoError(t, err)
+	assert.Equal(t, claims, *parsed)
+
+	_, err = parseLaunchToken(key, token, now.Add(LaunchTokenTTL))
+	assert.Equal(t, ErrExpiredLaunchToken, err)
+
+	other := signLaunchToken(key, LaunchClaims{SessionID: 8, ExpiresAt: claims.ExpiresAt})
+	otherClaims, _, _ := strings.Cut(other, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	for name, bad := range map[string]string{
+		"other key":      signLaunchToken([]byte("other"), claims),
+		"no signature":   token[:len(token)-5],
+		"no separator":   "abc",
+		"swapped claims": otherClaims + "." + signature,
+	} {
+		_, err := parseLaunchToken(key, bad, now)
+		assert.Equal(t, ErrInvalidLaunchToken, err, name)
+	}
+}
+
+func TestStoredKey_KeepsGeneratedKey(t *testing.T) {
+	key := storedKey{setting: "test_key"}
+	first, err := key.get(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, first, signingKeyBytes)
+
+	second, err := key.get(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	configured := storedKey{setting: "test_key"}
+	configured.set([]byte("configured"))
+	got, err := configured.get(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("configured"), got)
+}
+
+func TestStudyService_SubmitActivityReviews_Rejects(t *testing.T) {
+	studyService := NewStudyService(NewBaseService(nil, nil, nil, nil, nil)).WithLaunchKey([]byte("secret"))
+	valid := signLaunchToken([]byte("secret"), LaunchClaims{SessionID: 1, GroupID: 1, ActivityID: 1, ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	expired := signLaunchToken([]byte("secret"), LaunchClaims{SessionID: 1, GroupID: 1, ActivityID: 1, ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	batch := ActivityReviewBatch{BatchID: "b1", Reviews: []ActivityReview{{WordID: 1, Correct: true}}}
+
+	tests := []struct {
+		name  string
+		token string
+		batch ActivityReviewBatch
+		code  string
+	}{
+		{"forged token", signLaunchToken([]byte("other"), LaunchClaims{SessionID: 1, ExpiresAt: time.Now().Add(time.Minute).Unix()}), batch, ErrCodeUnauthorized},
+		{"expired token", expired, batch, ErrCodeUnauthorized},
+		{"missing batch ID", valid, ActivityReviewBatch{Reviews: batch.Reviews}, ErrCodeInvalidInput},
+		{"invalid batch ID", valid, ActivityReviewBatch{BatchID: "batch 1", Reviews: batch.Reviews}, ErrCodeInvalidInput},
+		{"empty batch", valid, ActivityReviewBatch{BatchID: "b1"}, ErrCodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := studyService.SubmitActivityReviews(context.Background(), tt.token, tt.batch)
+			require.Error(t, err)
+			assert.Equal(t, tt.code, err.(*ServiceError).Code)
+		})
+	}
+}
+
+func TestCheckReview(t *testing.T) {
+	tooLong := models.MaxAnswerTimeMs + 1
+	assert.Error(t, checkReview(&models.WordReview{Grade: "meh"}))
+	assert.Error(t, checkReview(&models.WordReview{AnswerTimeMs: &tooLong}))
+
+	hard := &models.WordReview{Grade: models.GradeHard}
+	require.NoError(t, checkReview(hard))
+	assert.True(t, hard.Correct, "graded reviews are correct unless graded again")
+
+	again := &models.WordReview{Correct: true, Grade: models.GradeAgain}
+	require.NoError(t, checkReview(again))
+	assert.False(t, again.Correct)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// minCallbackSecretLength is the shortest accepted callback signing secret
+const minCallbackSecretLength = 16
+
+// ActivityRegistration is sent by an external study app to register itself, or
+// to update its registration, under its activity name
+type ActivityRegistration struct {
+	ActivityCatalogEntry
+	// CallbackSecret signs the callbacks sent to CallbackURL. It is stored but
+	// never returned; an empty secret keeps the one already registered.
+	CallbackSecret string `json:"callback_secret,omitempty"`
+}
+
+// RegisteredActivity is the registration of a study app as stored
+type RegisteredActivity struct {
+	StudyActivityInfo
+	CallbackURL string `json:"callback_url,omitempty"`
+	// SignedCallbacks reports whether a callback secret is registered
+	SignedCallbacks bool `json:"signed_callbacks"`
+	// Created is false when an existing registration was updated
+	Created bool `json:"-"`
+}
+
+// RegisterStudyActivity creates or updates the study activity of an external app,
+// matched by name. Existing sessions of the activity are kept.
+func (s *StudyService) RegisterStudyActivity(ctx context.Context, reg ActivityRegistration, actor string) (*RegisteredActivity, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.RegisterStudyActivity")
+	defer span.End()
+
+	activity, err := reg.activity()
+	if err != nil {
+		return nil, err
+	}
+	if reg.CallbackSecret != "" && len(reg.CallbackSecret) < minCallbackSecretLength {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Callback secret must be at least 16 characters", nil)
+	}
+	activity.CallbackSecret = reg.CallbackSecret
+
+	activities := []models.StudyActivity{activity}
+	created, _, err := s.studyRepo.UpsertStudyActivities(ctx, activities)
+	if err != nil {
+		if err == repository.ErrInvalidInput {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Invalid study activity", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to register study activity", err)
+	}
+	activity = activities[0]
+
+	id := activity.ID
+	if err := s.recordAudit(ctx, actor, models.AuditActionRegister, models.AuditEntityActivity, &id, nil, newActivityCatalogEntry(activity)); err != nil {
+		return nil, err
+	}
+
+	return &RegisteredActivity{
+		StudyActivityInfo: newStudyActivityInfo(activity),
+		CallbackURL:       activity.CallbackURL,
+		SignedCallbacks:   activity.CallbackSecret != "",
+		Created:           created > 0,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/llm"
+)
+
+// providerReporter is implemented by language models that report on their
+// providers, such as llm.Router
+type providerReporter interface {
+	Stats() []llm.ProviderStats
+}
+
+// AIService reports on the language model behind the AI features
+type AIService struct {
+	*BaseService
+}
+
+// NewAIService creates a new AI service
+func NewAIService(base *BaseService) *AIService {
+	return &AIService{BaseService: base}
+}
+
+// ListProviders returns the language model providers in the order they are
+// tried, with their circuit state and the requests, failures and tokens
+// counted since the start
+func (s *AIService) ListProviders(ctx context.Context) ([]llm.ProviderStats, error) {
+	_, span := tracer.Start(ctx, "AIService.ListProviders")
+	defer span.End()
+
+	if s.llm == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "AI features are not available", nil)
+	}
+	reporter, ok := s.llm.(providerReporter)
+	if !ok {
+		return []llm.ProviderStats{}, nil
+	}
+	return reporter.Stats(), nil
+}
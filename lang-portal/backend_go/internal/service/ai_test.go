@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/llm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIService_ListProviders(t *testing.T) {
+	base := NewBaseService(nil, nil, nil, nil, nil)
+	s := NewAIService(base)
+
+	_, err := s.ListProviders(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+
+	base.WithLLM(&scriptedLLM{})
+	providers, err := s.ListProviders(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, providers, "plain clients do not report on providers")
+
+	base.WithLLM(llm.NewRouter(llm.RouterConfig{}, llm.Provider{Name: "openai", Client: &scriptedLLM{pieces: []string{"やあ"}}}))
+	_, err = base.complete(context.Background(), "alice", PromptTutor, llm.Request{}, nil)
+	require.NoError(t, err)
+	providers, err = s.ListProviders(context.Background())
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	assert.Equal(t, "openai", providers[0].Name)
+	assert.Equal(t, llm.StateClosed, providers[0].State)
+	assert.Equal(t, int64(1), providers[0].Requests)
+	assert.Equal(t, int64(9), providers[0].CompletionTokens)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// defaultUsageWindow is the period AI usage is reported for without a since
+const defaultUsageWindow = 30 * 24 * time.Hour
+
+// AIQuota caps what each client, an API key or an IP address, may use of the AI
+// features per day, which starts at midnight UTC. Zero values are unlimited.
+type AIQuota struct {
+	Requests int64
+	Tokens   int64
+}
+
+// AIUsageFilter narrows down the usage reported by GetUsage
+type AIUsageFilter struct {
+	User  string
+	Since time.Time
+	Until time.Time
+}
+
+// AIUsageReport is the usage of the AI features over a period, by user,
+// feature and provider, most tokens first. Features are named after their
+// prompts.
+type AIUsageReport struct {
+	Since  time.Time                   `json:"since"`
+	Until  *time.Time                  `json:"until,omitempty"`
+	Totals repository.AIUsageTotals    `json:"totals"`
+	Items  []repository.AIUsageSummary `json:"items"`
+}
+
+// WithAIUsage records the tokens of every request of the AI features in repo
+// and holds each client to quota. Without it usage is only counted per provider
+// since the start.
+func (s *BaseService) WithAIUsage(repo repository.AIUsageRepositoryInterface, quota AIQuota) *BaseService {
+	s.aiUsage = repo
+	s.aiQuota = quota
+	return s
+}
+
+// GetUsage reports the usage of the AI features, over the last 30 days unless
+// filter sets a period
+func (s *AIService) GetUsage(ctx context.Context, filter AIUsageFilter) (*AIUsageReport, error) {
+	ctx, span := tracer.Start(ctx, "AIService.GetUsage")
+	defer span.End()
+
+	if s.aiUsage == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "AI usage is not recorded", nil)
+	}
+	if filter.Since.IsZero() {
+		filter.Since = time.Now().Add(-defaultUsageWindow)
+	}
+	if !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return nil, NewServiceError(ErrCodeInvalidInput, "until must not be before since", nil)
+	}
+
+	summaries, err := s.aiUsage.Summarize(ctx, repository.AIUsageFilter{
+		User:      filter.User,
+		TimeRange: repository.TimeRange{Start: filter.Since, End: filter.Until},
+	})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to summarize AI usage", err)
+	}
+
+	report := &AIUsageReport{Since: filter.Since, Items: []repository.AIUsageSummary{}}
+	if !filter.Until.IsZero() {
+		report.Until = &filter.Until
+	}
+	for _, summary := range summaries {
+		report.Totals.Requests += summary.Requests
+		report.Totals.PromptTokens += summary.PromptTokens
+		report.Totals.CompletionTokens += summary.CompletionTokens
+		report.Items = append(report.Items, summary)
+	}
+	return report, nil
+}
+
+// chat sends a request of feature to the language model for client, once the
+// client is within its quota, and records the tokens of the reply. The request
+// is counted before the model is asked, so concurrent requests cannot all pass
+// the quota; one the model does not answer is not counted.
+func (s *BaseService) chat(ctx context.Context, client, feature string, req llm.Request, onDelta func(string) error) (*llm.Response, error) {
+	usage := &models.AIUsage{User: client, Feature: feature}
+	reserved, err := s.reserveAIQuota(ctx, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.llm.Chat(ctx, req, onDelta)
+	if err != nil {
+		if reserved {
+			// Keep the model's error rather than this one
+			_ = s.aiUsage.Release(context.WithoutCancel(ctx), usage)
+		}
+		if errors.Is(err, llm.ErrUnavailable) {
+			return nil, NewServiceError(ErrCodeInternal, "Language model is unavailable", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to generate content", err)
+	}
+
+	if s.aiUsage != nil {
+		usage.Provider = response.Provider
+		usage.PromptTokens = response.Usage.PromptTokens
+		usage.CompletionTokens = response.Usage.CompletionTokens
+		if err := s.aiUsage.Record(ctx, usage); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to record AI usage", err)
+		}
+	}
+	return response, nil
+}
+
+// reserveAIQuota counts the request of usage towards its client's daily quota,
+// returning a rate limit error once the quota is used up. It reports whether a
+// request was reserved, which there is not without a quota.
+func (s *BaseService) reserveAIQuota(ctx context.Context, usage *models.AIUsage) (bool, error) {
+	if s.aiUsage == nil || s.aiQuota == (AIQuota{}) {
+		return false, nil
+	}
+	reserved, err := s.aiUsage.Reserve(ctx, usage, repository.AIUsageLimit{
+		Since:    time.Now().UTC().Truncate(24 * time.Hour),
+		Requests: s.aiQuota.Requests,
+		Tokens:   s.aiQuota.Tokens,
+	})
+	if err != nil {
+		return false, NewServiceError(ErrCodeInternal, "Failed to check AI usage", err)
+	}
+	if reserved {
+		return true, nil
+	}
+
+	var limits []string
+	if s.aiQuota.Requests > 0 {
+		limits = append(limits, fmt.Sprintf("%d requests", s.aiQuota.Requests))
+	}
+	if s.aiQuota.Tokens > 0 {
+		limits = append(limits, fmt.Sprintf("%d tokens", s.aiQuota.Tokens))
+	}
+	return false, NewServiceError(ErrCodeRateLimited, "Daily AI quota of "+strings.Join(limits, " or ")+" used up; it resets at midnight UTC", nil)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAIUsageRepository is a mock implementation of AIUsageRepositoryInterface
+type mockAIUsageRepository struct {
+	mock.Mock
+}
+
+func (m *mockAIUsageRepository) Record(ctx context.Context, usage *models.AIUsage) error {
+	return m.Called(usage).Error(0)
+}
+
+func (m *mockAIUsageRepository) Reserve(ctx context.Context, usage *models.AIUsage, limit repository.AIUsageLimit) (bool, error) {
+	args := m.Called(usage, limit)
+	if args.Bool(0) {
+		usage.ID = 1
+	}
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockAIUsageRepository) Release(ctx context.Context, usage *models.AIUsage) error {
+	return m.Called(usage).Error(0)
+}
+
+func (m *mockAIUsageRepository) TotalsSince(ctx context.Context, user string, since time.Time) (*repository.AIUsageTotals, error) {
+	args := m.Called(user, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.AIUsageTotals), args.Error(1)
+}
+
+func (m *mockAIUsageRepository) Summarize(ctx context.Context, filter repository.AIUsageFilter) ([]repository.AIUsageSummary, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]repository.AIUsageSummary), args.Error(1)
+}
+
+func TestBaseService_Chat_RecordsUsage(t *testing.T) {
+	usageRepo := new(mockAIUsageRepository)
+	usageRepo.On("Record", &models.AIUsage{User: "alice", Feature: PromptWordExplanation, Provider: "ollama", PromptTokens: 120, CompletionTokens: 9}).Return(nil).Once()
+	router := llm.NewRouter(llm.RouterConfig{}, llm.Provider{Name: "ollama", Client: &scriptedLLM{pieces: []string{"はい"}}})
+	base := NewBaseService(nil, nil, nil, nil, nil).WithLLM(router).WithAIUsage(usageRepo, AIQuota{})
+
+	reply, err := base.complete(context.Background(), "alice", PromptWordExplanation, llm.Request{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "はい", reply)
+	usageRepo.AssertExpectations(t)
+
+	usageRepo.On("Record", mock.Anything).Return(errors.New("disk full"))
+	_, err = base.complete(context.Background(), "alice", PromptWordExplanation, llm.Request{}, nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInternal, err.(*ServiceError).Code, "usage must be recorded")
+}
+
+func TestBaseService_Chat_ReservesQuota(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	limit := repository.AIUsageLimit{Since: today, Requests: 10, Tokens: 1000}
+	usageRepo := new(mockAIUsageRepository)
+	usageRepo.On("Reserve", &models.AIUsage{User: "key:3", Feature: PromptTutor}, limit).Return(true, nil).Once()
+	usageRepo.On("Record", &models.AIUsage{ID: 1, User: "key:3", Feature: PromptTutor, Provider: "ollama", PromptTokens: 120, CompletionTokens: 9}).Return(nil).Once()
+	router := llm.NewRouter(llm.RouterConfig{}, llm.Provider{Name: "ollama", Client: &scriptedLLM{pieces: []string{"はい"}}})
+	base := NewBaseService(nil, nil, nil, nil, nil).WithLLM(router).WithAIUsage(usageRepo, AIQuota{Requests: 10, Tokens: 1000})
+
+	_, err := base.chat(context.Background(), "key:3", PromptTutor, llm.Request{}, nil)
+	require.NoError(t, err)
+	usageRepo.AssertExpectations(t)
+
+	usageRepo.On("Reserve", mock.Anything, limit).Return(false, nil).Once()
+	_, err = base.chat(context.Background(), "key:3", PromptTutor, llm.Request{}, nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeRateLimited, err.(*ServiceError).Code)
+	assert.Contains(t, err.Error(), "10 requests or 1000 tokens")
+
+	unlimited := new(mockAIUsageRepository)
+	unlimited.On("Record", mock.Anything).Return(nil)
+	base = NewBaseService(nil, nil, nil, nil, nil).WithLLM(router).WithAIUsage(unlimited, AIQuota{})
+	_, err = base.chat(context.Background(), "key:3", PromptTutor, llm.Request{}, nil)
+	require.NoError(t, err)
+	unlimited.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+}
+
+func TestBaseService_Chat_ReleasesUnansweredRequests(t *testing.T) {
+	usageRepo := new(mockAIUsageRepository)
+	usageRepo.On("Reserve", mock.Anything, mock.Anything).Return(true, nil)
+	usageRepo.On("Release", &models.AIUsage{ID: 1, User: "ip:192.0.2.1", Feature: PromptTutor}).Return(nil).Once()
+	client := &scriptedLLM{err: fmt.Errorf("%w: status 503", llm.ErrUnavailable)}
+	base := NewBaseService(nil, nil, nil, nil, nil).WithLLM(client).WithAIUsage(usageRepo, AIQuota{Requests: 10})
+
+	_, err := base.chat(context.Background(), "ip:192.0.2.1", PromptTutor, llm.Request{}, nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInternal, err.(*ServiceError).Code)
+	usageRepo.AssertExpectations(t)
+	usageRepo.AssertNotCalled(t, "Record", mock.Anything)
+}
+
+func TestTutorService_SendMessage_OverQuota(t *testing.T) {
+	tutorRepo := new(mockTutorRepository)
+	tutorRepo.On("GetConversation", uint(4)).Return(&models.Conversation{ID: 4, User: "alice"}, nil)
+	wordRepo := new(mockWordRepository)
+	wordRepo.On("CountWordsByProgressState").Return(map[string]int64{}, nil)
+	usageRepo := new(mockAIUsageRepository)
+	usageRepo.On("Reserve", &models.AIUsage{User: "ip:192.0.2.1", Feature: PromptTutor}, mock.Anything).Return(false, nil)
+	client := &scriptedLLM{pieces: []string{"はい"}}
+	base := NewBaseService(wordRepo, nil, nil, nil, nil).WithLLM(client).WithAIUsage(usageRepo, AIQuota{Requests: 3})
+
+	_, err := NewTutorService(base, tutorRepo).SendMessage(context.Background(), "alice", "ip:192.0.2.1", 4, "hi", nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeRateLimited, err.(*ServiceError).Code)
+	assert.Empty(t, client.request.Messages, "the model is not asked once the quota is used up")
+	tutorRepo.AssertNotCalled(t, "AddExchange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAIService_GetUsage(t *testing.T) {
+	_, err := NewAIService(NewBaseService(nil, nil, nil, nil, nil)).GetUsage(context.Background(), AIUsageFilter{})
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+
+	usageRepo := new(mockAIUsageRepository)
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	usageRepo.On("Summarize", repository.AIUsageFilter{User: "alice", TimeRange: repository.TimeRange{Start: since}}).Return([]repository.AIUsageSummary{
+		{User: "alice", Feature: PromptTutor, Provider: "openai", AIUsageTotals: repository.AIUsageTotals{Requests: 2, PromptTokens: 300, CompletionTokens: 40}},
+		{User: "alice", Feature: PromptWordExplanation, Provider: "openai", AIUsageTotals: repository.AIUsageTotals{Requests: 1, PromptTokens: 80, CompletionTokens: 120}},
+	}, nil)
+	s := NewAIService(NewBaseService(nil, nil, nil, nil, nil).WithAIUsage(usageRepo, AIQuota{}))
+
+	report, err := s.GetUsage(context.Background(), AIUsageFilter{User: "alice", Since: since})
+	require.NoError(t, err)
+	assert.Equal(t, repository.AIUsageTotals{Requests: 3, PromptTokens: 380, CompletionTokens: 160}, report.Totals)
+	assert.Len(t, report.Items, 2)
+	assert.Nil(t, report.Until)
+
+	_, err = s.GetUsage(context.Background(), AIUsageFilter{Since: since, Until: since.Add(-time.Hour)})
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
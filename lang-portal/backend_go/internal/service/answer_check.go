@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Fields a typed answer can be checked against
+const (
+	AnswerAgainstEnglish = "english"
+	AnswerAgainstRomaji  = "romaji"
+)
+
+// Match qualities of a typed answer, from best to worst
+const (
+	MatchExact      = "exact"
+	MatchNormalized = "normalized"
+	MatchTypo       = "typo"
+	MatchWrong      = "wrong"
+)
+
+// maxAnswerLength caps the length of a typed answer
+const maxAnswerLength = 200
+
+// AnswerCheck is a typed answer for a word
+type AnswerCheck struct {
+	WordID uint   `json:"word_id"`
+	Answer string `json:"answer"`
+	// Against is english, romaji or empty to accept either
+	Against string `json:"against,omitempty"`
+}
+
+// AnswerCheckResult reports how well a typed answer matches the word. Exact,
+// normalized and typo matches are correct; Expected is the accepted answer that
+// matched, or the closest one.
+type AnswerCheckResult struct {
+	WordID         uint               `json:"word_id"`
+	Correct        bool               `json:"correct"`
+	Quality        string             `json:"quality"`
+	MatchedField   string             `json:"matched_field,omitempty"`
+	Expected       string             `json:"expected"`
+	SuggestedGrade models.ReviewGrade `json:"suggested_grade"`
+}
+
+// acceptedAnswer is a spelling accepted for a word, with its comparison key
+type acceptedAnswer struct {
+	field, text, key string
+	normalize        func(string) string
+}
+
+// CheckAnswer compares a typed answer with a word's English meanings and
+// reading. Case, spacing and punctuation never matter; English answers may omit
+// "to" and articles and match any one of several meanings separated by commas,
+// semicolons or slashes; romaji answers may use any common romanization, long
+// vowel spelling or kana. A near miss by one or two letters is a typo.
+func (s *StudyService) CheckAnswer(ctx context.Context, check AnswerCheck) (*AnswerCheckResult, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.CheckAnswer")
+	defer span.End()
+
+	switch check.Against {
+	case "", AnswerAgainstEnglish, AnswerAgainstRomaji:
+	default:
+		return nil, NewServiceError(ErrCodeInvalidInput, "against must be english or romaji", nil)
+	}
+	if len(check.Answer) > maxAnswerLength {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Answer is too long", nil)
+	}
+
+	word, err := s.wordRepo.GetByID(ctx, check.WordID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Word not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
+	}
+
+	result := gradeAnswer(check.Answer, acceptedAnswers(word, check.Against))
+	result.WordID = word.ID
+	return result, nil
+}
+
+// acceptedAnswers lists the answers accepted for a word in the given field, or
+// in both fields when against is empty
+func acceptedAnswers(word *models.Word, against string) []acceptedAnswer {
+	var accepted []acceptedAnswer
+	if against != AnswerAgainstRomaji {
+		for _, meaning := range strings.FieldsFunc(word.English, func(r rune) bool { return r == ',' || r == ';' || r == '/' }) {
+			if meaning = strings.TrimSpace(meaning); meaning != "" {
+				accepted = append(accepted, acceptedAnswer{AnswerAgainstEnglish, meaning, normalizeEnglish(meaning), normalizeEnglish})
+			}
+		}
+	}
+	if against != AnswerAgainstEnglish {
+		for _, reading := range []string{word.Reading, word.Term} {
+			if reading != "" {
+				accepted = append(accepted, acceptedAnswer{AnswerAgainstRomaji, reading, jpn.NormalizeRomaji(reading), jpn.NormalizeRomaji})
+			}
+		}
+	}
+	return accepted
+}
+
+// gradeAnswer returns the best match of answer among the accepted answers
+func gradeAnswer(answer string, accepted []acceptedAnswer) *AnswerCheckResult {
+	answer = strings.TrimSpace(answer)
+	best := &AnswerCheckResult{Quality: MatchWrong, SuggestedGrade: models.GradeAgain}
+	bestDistance := -1
+
+	for _, a := range accepted {
+		key := a.normalize(answer)
+		distance := editDistance(key, a.key)
+
+		quality := MatchWrong
+		switch {
+		case answer == "" || key == "":
+		case strings.EqualFold(answer, a.text):
+			quality = MatchExact
+		case distance == 0:
+			quality = MatchNormalized
+		case distance <= typoAllowance(a.key):
+			quality = MatchTypo
+		}
+
+		if qualityRank(quality) > qualityRank(best.Quality) ||
+			quality == best.Quality && (bestDistance < 0 || distance < bestDistance) {
+			best.Quality, best.MatchedField, best.Expected = quality, a.field, a.text
+			bestDistance = distance
+		}
+	}
+
+	switch best.Quality {
+	case MatchExact, MatchNormalized:
+		best.Correct, best.SuggestedGrade = true, models.GradeGood
+	case MatchTypo:
+		best.Correct, best.SuggestedGrade = true, models.GradeHard
+	default:
+		best.MatchedField = ""
+	}
+	return best
+}
+
+func qualityRank(quality string) int {
+	switch quality {
+	case MatchExact:
+		return 3
+	case MatchNormalized:
+		return 2
+	case MatchTypo:
+		return 1
+	}
+	return 0
+}
+
+// typoAllowance is the number of edits tolerated as a typo: none for short
+// answers, where one letter changes the word, one up to eight letters and two
+// beyond
+func typoAllowance(key string) int {
+	switch n := len([]rune(key)); {
+	case n < 4:
+		return 0
+	case n <= 8:
+		return 1
+	}
+	return 2
+}
+
+// englishFillers are words an English answer may omit
+var englishFillers = map[string]bool{"to": true, "a": true, "an": true, "the": true}
+
+// normalizeEnglish lower-cases an English answer, drops parenthesized notes,
+// punctuation and filler words, and collapses spaces
+func normalizeEnglish(text string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth > 0:
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '\'' || r == '’':
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	kept := words[:0]
+	for _, w := range words {
+		if !englishFillers[w] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(kept, " ")
+}
+
+// editDistance is the optimal string alignment distance between a and b,
+// counted in runes: Levenshtein distance where swapping two adjacent letters,
+// the most common typing slip, is a single edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+func TestGradeAnswer(t *testing.T) {
+	word := &models.Word{Term: "勉強する", Reading: "benkyou suru", English: "to study; to learn (something)"}
+
+	tests := []struct {
+		answer  string
+		against string
+		quality string
+		field   string
+	}{
+		{"to study", "", MatchExact, AnswerAgainstEnglish},
+		{"Study", "", MatchNormalized, AnswerAgainstEnglish},
+		{"learn", "", MatchNormalized, AnswerAgainstEnglish},
+		{"stduy", "", MatchTypo, AnswerAgainstEnglish},
+		{"benkyō suru", "", MatchNormalized, AnswerAgainstRomaji},
+		{"benkyosuru", "", MatchNormalized, AnswerAgainstRomaji},
+		{"べんきょうする", "", MatchNormalized, AnswerAgainstRomaji},
+		{"勉強する", "", MatchExact, AnswerAgainstRomaji},
+		{"benkyu suru", "", MatchTypo, AnswerAgainstRomaji},
+		{"study", AnswerAgainstRomaji, MatchWrong, ""},
+		{"benkyou suru", AnswerAgainstEnglish, MatchWrong, ""},
+		{"to sleep", "", MatchWrong, ""},
+		{"", "", MatchWrong, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			result := gradeAnswer(tt.answer, acceptedAnswers(word, tt.against))
+			assert.Equal(t, tt.quality, result.Quality)
+			assert.Equal(t, tt.field, result.MatchedField)
+			assert.Equal(t, tt.quality != MatchWrong, result.Correct)
+		})
+	}
+}
+
+func TestGradeAnswer_ShortWordsAllowNoTypos(t *testing.T) {
+	word := &models.Word{Term: "犬", Reading: "inu", English: "dog"}
+
+	assert.Equal(t, MatchWrong, gradeAnswer("dig", acceptedAnswers(word, "")).Quality)
+	result := gradeAnswer("cat", acceptedAnswers(word, AnswerAgainstEnglish))
+	assert.Equal(t, "dog", result.Expected, "the closest answer is reported")
+	assert.Equal(t, models.GradeAgain, result.SuggestedGrade)
+}
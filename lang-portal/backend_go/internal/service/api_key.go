@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// APIKeyPrefix starts every API key, so that a leaked key is easy to recognise
+const APIKeyPrefix = "lp_"
+
+const (
+	// apiKeyBytes is the amount of randomness in an API key
+	apiKeyBytes = 32
+	// apiKeyShownLength is the number of leading characters of a key stored to
+	// tell keys apart
+	apiKeyShownLength = len(APIKeyPrefix) + 6
+	// maxAPIKeyNameLength bounds the name of a key
+	maxAPIKeyNameLength = 100
+	// apiKeyTouchInterval limits how often the last use of a key is written, so
+	// that a busy client does not turn every request into a write
+	apiKeyTouchInterval = time.Minute
+	// BootstrapAPIKeyName is the name of the key authenticated by the bootstrap
+	// key, as it appears in the audit log
+	BootstrapAPIKeyName = "bootstrap"
+)
+
+// NewAPIKey holds the name and scopes of a key to create
+type NewAPIKey struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// CreatedAPIKey is a new API key along with the key itself, which is not
+// stored and cannot be retrieved again
+type CreatedAPIKey struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyService manages the API keys of machine clients and authenticates
+// requests made with them
+type APIKeyService struct {
+	*BaseService
+	keyRepo repository.APIKeyRepositoryInterface
+	now     func() time.Time
+	// bootstrap is a key from the configuration granting the admin scope, so
+	// that the first keys can be created; empty without one
+	bootstrap string
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(base *BaseService, keyRepo repository.APIKeyRepositoryInterface) *APIKeyService {
+	return &APIKeyService{
+		BaseService: base,
+		keyRepo:     keyRepo,
+		now:         time.Now,
+	}
+}
+
+// WithBootstrapKey accepts secret as a key granting the admin scope. It is not
+// stored, so it works on a fresh database and is what creates the first keys.
+func (s *APIKeyService) WithBootstrapKey(secret string) *APIKeyService {
+	s.bootstrap = secret
+	return s
+}
+
+// CreateAPIKey creates a key granting the given scopes
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, req NewAPIKey, actor string) (*CreatedAPIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.CreateAPIKey")
+	defer span.End()
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Name must be 1 to 100 characters", nil)
+	}
+	if len(req.Scopes) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "At least one scope must be granted", nil)
+	}
+	seen := make(map[string]bool, len(req.Scopes))
+	var scopes models.StringSlice
+	for _, scope := range req.Scopes {
+		switch scope {
+		case models.ScopeRead, models.ScopeWrite, models.ScopeAdmin:
+		default:
+			return nil, NewServiceError(ErrCodeInvalidInput, "Unknown scope: "+scope, nil)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	buf := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to generate API key", err)
+	}
+	secret := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	key := &models.APIKey{
+		Name:    name,
+		Prefix:  secret[:apiKeyShownLength],
+		KeyHash: hashAPIKey(secret),
+		Scopes:  scopes,
+	}
+	if err := s.keyRepo.Create(ctx, key); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create API key", err)
+	}
+	if err := s.recordAudit(ctx, actor, models.AuditActionCreate, models.AuditEntityAPIKey, &key.ID, nil, key); err != nil {
+		return nil, err
+	}
+	return &CreatedAPIKey{APIKey: *key, Key: secret}, nil
+}
+
+// ListAPIKeys returns every API key, revoked ones included, newest first
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.ListAPIKeys")
+	defer span.End()
+
+	keys, err := s.keyRepo.List(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list API keys", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes a key; requests made with it are rejected from then on
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uint, actor string) error {
+	ctx, span := tracer.Start(ctx, "APIKeyService.RevokeAPIKey")
+	defer span.End()
+
+	key, err := s.keyRepo.GetByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return NewServiceError(ErrCodeNotFound, "API key not found", err)
+	}
+	if err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to fetch API key", err)
+	}
+	if err := s.keyRepo.Revoke(ctx, id, s.now()); err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to revoke API key", err)
+	}
+	return s.recordAudit(ctx, actor, models.AuditActionRevoke, models.AuditEntityAPIKey, &id, key, nil)
+}
+
+// Authenticate returns the key a request was made with. Unknown and revoked
+// keys are rejected as unauthorized.
+func (s *APIKeyService) Authenticate(ctx context.Context, secret string) (*models.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	if s.bootstrap != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(s.bootstrap)) == 1 {
+		return &models.APIKey{Name: BootstrapAPIKeyName, Scopes: models.StringSlice{models.ScopeAdmin}}, nil
+	}
+	if !strings.HasPrefix(secret, APIKeyPrefix) {
+		return nil, NewServiceError(ErrCodeUnauthorized, "Invalid API key", nil)
+	}
+	key, err := s.keyRepo.GetByHash(ctx, hashAPIKey(secret))
+	if err == repository.ErrNotFound {
+		return nil, NewServiceError(ErrCodeUnauthorized, "Invalid API key", nil)
+	}
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch API key", err)
+	}
+	if key.RevokedAt != nil {
+		return nil, NewServiceError(ErrCodeUnauthorized, "API key has been revoked", nil)
+	}
+
+	now := s.now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		// The last use is informational; failing to record it does not fail the request
+		if err := s.keyRepo.TouchLastUsed(ctx, key.ID, now); err == nil {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
+
+// hashAPIKey returns the stored form of a key. Keys are long and random, so a
+// plain SHA-256 is enough to make a leaked table useless.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
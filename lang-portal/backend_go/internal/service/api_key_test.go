@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAPIKeyRepository is a mock implementation of APIKeyRepositoryInterface
+type mockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *mockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return m.Called(key).Error(0)
+}
+
+func (m *mockAPIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyRepository) GetByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyRepository) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return m.Called(id, at).Error(0)
+}
+
+func (m *mockAPIKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return m.Called(id, at).Error(0)
+}
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	keys := new(mockAPIKeyRepository)
+	audit := new(mockAuditRepository)
+	keyService := NewAPIKeyService(NewBaseService(nil, nil, nil, audit, nil), keys)
+
+	var stored *models.APIKey
+	keys.On("Create", mock.AnythingOfType("*models.APIKey")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.APIKey)
+		stored.ID = 4
+	}).Return(nil)
+	audit.On("Create", mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.Action == models.AuditActionCreate && entry.EntityType == models.AuditEntityAPIKey &&
+			!strings.Contains(string(entry.After), stored.KeyHash)
+	})).Return(nil)
+
+	created, err := keyService.CreateAPIKey(context.Background(), NewAPIKey{
+		Name:   " import script ",
+		Scopes: []string{models.ScopeWrite, models.ScopeRead, models.ScopeWrite},
+	}, "tester")
+	require.NoError(t, err)
+	assert.Equal(t, "import script", created.Name)
+	assert.Equal(t, models.StringSlice{models.ScopeWrite, models.ScopeRead}, created.Scopes)
+	assert.True(t, strings.HasPrefix(created.Key, APIKeyPrefix))
+	assert.Equal(t, created.Key[:apiKeyShownLength], created.Prefix)
+	// Only the hash of the key is stored
+	assert.Equal(t, hashAPIKey(created.Key), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, created.Key)
+	keys.AssertExpectations(t)
+	audit.AssertExpectations(t)
+
+	for _, req := range []NewAPIKey{
+		{Name: "", Scopes: []string{models.ScopeRead}},
+		{Name: "ci", Scopes: nil},
+		{Name: "ci", Scopes: []string{"delete"}},
+	} {
+		_, err := keyService.CreateAPIKey(context.Background(), req, "tester")
+		require.Error(t, err)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	keys := new(mockAPIKeyRepository)
+	keyService := NewAPIKeyService(NewBaseService(nil, nil, nil, nil, nil), keys)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	keyService.now = func() time.Time { return now }
+
+	recent := now.Add(-10 * time.Second)
+	revoked := now.Add(-time.Hour)
+	keys.On("GetByHash", hashAPIKey("lp_fresh")).Return(&models.APIKey{ID: 1}, nil)
+	keys.On("GetByHash", hashAPIKey("lp_recent")).Return(&models.APIKey{ID: 2, LastUsedAt: &recent}, nil)
+	keys.On("GetByHash", hashAPIKey("lp_revoked")).Return(&models.APIKey{ID: 3, RevokedAt: &revoked}, nil)
+	keys.On("GetByHash", hashAPIKey("lp_unknown")).Return(nil, repository.ErrNotFound)
+	keys.On("TouchLastUsed", uint(1), now).Return(nil)
+
+	key, err := keyService.Authenticate(context.Background(), "lp_fresh")
+	require.NoError(t, err)
+	assert.Equal(t, &now, key.LastUsedAt)
+
+	// Keys used within the last minute are not written again
+	key, err = keyService.Authenticate(context.Background(), "lp_recent")
+	require.NoError(t, err)
+	assert.Equal(t, &recent, key.LastUsedAt)
+	keys.AssertNumberOfCalls(t, "TouchLastUsed", 1)
+
+	for _, secret := range []string{"lp_revoked", "lp_unknown", "fresh"} {
+		_, err := keyService.Authenticate(context.Background(), secret)
+		require.Error(t, err, secret)
+		assert.Equal(t, ErrCodeUnauthorized, err.(*ServiceError).Code, secret)
+	}
+}
+
+func TestAPIKeyService_AuthenticateBootstrapKey(t *testing.T) {
+	keys := new(mockAPIKeyRepository)
+	keyService := NewAPIKeyService(NewBaseService(nil, nil, nil, nil, nil), keys).WithBootstrapKey("s3cret-admin")
+
+	key, err := keyService.Authenticate(context.Background(), "s3cret-admin")
+	require.NoError(t, err)
+	assert.Equal(t, BootstrapAPIKeyName, key.Name)
+	assert.True(t, key.HasScope(models.ScopeAdmin))
+	keys.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+
+	keys.On("GetByHash", hashAPIKey("lp_other")).Return(nil, repository.ErrNotFound)
+	_, err = keyService.Authenticate(context.Background(), "lp_other")
+	require.Error(t, err)
+
+	// Without a bootstrap key an empty secret is not accepted
+	_, err = NewAPIKeyService(NewBaseService(nil, nil, nil, nil, nil), keys).Authenticate(context.Background(), "")
+	require.Error(t, err)
+}
+
+func TestAPIKeyService_RevokeAPIKey(t *testing.T) {
+	keys := new(mockAPIKeyRepository)
+	keyService := NewAPIKeyService(NewBaseService(nil, nil, nil, nil, nil), keys)
+
+	keys.On("GetByID", uint(1)).Return(&models.APIKey{ID: 1}, nil)
+	keys.On("GetByID", uint(2)).Return(nil, repository.ErrNotFound)
+	keys.On("Revoke", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+
+	assert.NoError(t, keyService.RevokeAPIKey(context.Background(), 1, "tester"))
+	err := keyService.RevokeAPIKey(context.Background(), 2, "tester")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+	keys.AssertExpectations(t)
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	read := &models.APIKey{Scopes: models.StringSlice{models.ScopeRead}}
+	write := &models.APIKey{Scopes: models.StringSlice{models.ScopeWrite}}
+	admin := &models.APIKey{Scopes: models.StringSlice{models.ScopeAdmin}}
+
+	assert.True(t, read.HasScope(models.ScopeRead))
+	assert.False(t, read.HasScope(models.ScopeWrite))
+	assert.True(t, write.HasScope(models.ScopeRead))
+	assert.False(t, write.HasScope(models.ScopeAdmin))
+	assert.True(t, admin.HasScope(models.ScopeWrite))
+}
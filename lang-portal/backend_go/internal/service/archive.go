@@ -0,0 +1,582 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// ArchiveVersion is the format version written by ExportArchive and accepted by
+// RestoreArchive
+const ArchiveVersion = 1
+
+// ArchiveFileName is the name of the archive inside a zipped export
+const ArchiveFileName = "lang-portal-archive.json"
+
+// ArchiveActionRestore is the action reported in an ArchiveRestorePlan
+const ArchiveActionRestore = "restore_archive"
+
+// maxArchiveEntrySize caps the uncompressed size of a zipped archive
+const maxArchiveEntrySize = 256 << 20
+
+// Archive is a portable copy of the account: words and their example
+// sentences, groups, study history and preferences. Rows refer to each other by the IDs they had when exported,
+// which are kept on restore. Study sessions name their activity, since activity
+// IDs are local to each instance.
+type Archive struct {
+	Version     int               `json:"version"`
+	ExportedAt  time.Time         `json:"exported_at"`
+	Words       []ArchiveWord     `json:"words"`
+	Groups      []ArchiveGroup    `json:"groups"`
+	Sessions    []ArchiveSession  `json:"study_sessions"`
+	Reviews     []ArchiveReview   `json:"word_reviews"`
+	Sentences   []ArchiveSentence `json:"example_sentences,omitempty"`
+	Preferences *Preferences      `json:"preferences,omitempty"`
+}
+
+// ArchiveWord is a word with its review schedule. Archives written before
+// languages were tracked have no language; their words are Japanese. Words
+// without scripts are written in those of their language.
+type ArchiveWord struct {
+	ID             uint       `json:"id"`
+	Language       string     `json:"language,omitempty"`
+	Japanese       string     `json:"japanese"`
+	Romaji         string     `json:"romaji"`
+	Script         string     `json:"script,omitempty"`
+	ReadingScript  string     `json:"reading_script,omitempty"`
+	English        string     `json:"english"`
+	German         string     `json:"german,omitempty"`
+	Parts          []string   `json:"parts"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	NextDueAt      *time.Time `json:"next_due_at,omitempty"`
+	AccuracyEWMA   *float64   `json:"accuracy_ewma,omitempty"`
+}
+
+// ArchiveGroup is a group and the IDs of its words
+type ArchiveGroup struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Language  string    `json:"language,omitempty"`
+	WordIDs   []uint    `json:"word_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ArchiveSession is a study session
+type ArchiveSession struct {
+	ID          uint      `json:"id"`
+	GroupID     uint      `json:"group_id"`
+	Activity    string    `json:"activity"`
+	ReviewOrder string    `json:"review_order,omitempty"`
+	WordLimit   int       `json:"word_limit,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ArchiveReview is a single word review
+type ArchiveReview struct {
+	ID             uint               `json:"id"`
+	WordID         uint               `json:"word_id"`
+	StudySessionID uint               `json:"study_session_id"`
+	Correct        bool               `json:"correct"`
+	Grade          models.ReviewGrade `json:"grade,omitempty"`
+	AnswerTimeMs   *int               `json:"answer_time_ms,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// ArchiveSentence is an example sentence of a word
+type ArchiveSentence struct {
+	ID        uint      `json:"id"`
+	WordID    uint      `json:"word_id"`
+	Japanese  string    `json:"japanese"`
+	English   string    `json:"english,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ArchiveRestorePlan reports what restoring an archive would do. As with resets,
+// the token is derived from the rows to be deleted and from the archive, so it
+// only confirms restoring the same archive over unchanged data.
+type ArchiveRestorePlan struct {
+	Action     string    `json:"action"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Deletes is the number of rows per table that would be deleted
+	Deletes map[string]int64 `json:"deletes"`
+	// Creates is the number of rows per table that would be restored
+	Creates           map[string]int `json:"creates"`
+	ConfirmationToken string         `json:"confirmation_token,omitempty"`
+}
+
+// ArchiveService exports and restores account archives
+type ArchiveService struct {
+	*BaseService
+	archiveRepo repository.ArchiveRepositoryInterface
+}
+
+// NewArchiveService creates a new archive service
+func NewArchiveService(base *BaseService, archiveRepo repository.ArchiveRepositoryInterface) *ArchiveService {
+	return &ArchiveService{BaseService: base, archiveRepo: archiveRepo}
+}
+
+// ExportArchive returns the whole account as an archive
+func (s *ArchiveService) ExportArchive(ctx context.Context) (*Archive, error) {
+	ctx, span := tracer.Start(ctx, "ArchiveService.ExportArchive")
+	defer span.End()
+
+	data, err := s.archiveRepo.Dump(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to read account data", err)
+	}
+	activities, err := s.studyRepo.ListAllStudyActivities(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list study activities", err)
+	}
+	activityNames := make(map[uint]string, len(activities))
+	for _, a := range activities {
+		activityNames[a.ID] = a.Name
+	}
+
+	order, err := s.defaultReviewOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	minReviews, minRate, err := s.masteryThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Words:      make([]ArchiveWord, len(data.Words)),
+		Groups:     make([]ArchiveGroup, len(data.Groups)),
+		Sessions:   make([]ArchiveSession, len(data.Sessions)),
+		Reviews:    make([]ArchiveReview, len(data.Reviews)),
+		Sentences:  make([]ArchiveSentence, len(data.Sentences)),
+		Preferences: &Preferences{
+			ReviewOrder:            order,
+			MasteredMinReviews:     minReviews,
+			MasteredMinSuccessRate: minRate,
+		},
+	}
+	for i, w := range data.Words {
+		archive.Words[i] = ArchiveWord{
+			ID:             w.ID,
+			Language:       w.Language,
+			Japanese:       w.Term,
+			Romaji:         w.Reading,
+			Script:         w.Script,
+			ReadingScript:  w.ReadingScript,
+			English:        w.English,
+			German:         w.German,
+			Parts:          nonNil(w.Parts),
+			CreatedAt:      w.CreatedAt,
+			UpdatedAt:      w.UpdatedAt,
+			LastReviewedAt: w.LastReviewedAt,
+			NextDueAt:      w.NextDueAt,
+			AccuracyEWMA:   w.AccuracyEWMA,
+		}
+	}
+	groupWords := make(map[uint][]uint)
+	for _, wg := range data.WordGroups {
+		groupWords[wg.GroupID] = append(groupWords[wg.GroupID], wg.WordID)
+	}
+	for i, g := range data.Groups {
+		wordIDs := groupWords[g.ID]
+		if wordIDs == nil {
+			wordIDs = []uint{}
+		}
+		archive.Groups[i] = ArchiveGroup{
+			ID:        g.ID,
+			Name:      g.Name,
+			Language:  g.Language,
+			WordIDs:   wordIDs,
+			CreatedAt: g.CreatedAt,
+			UpdatedAt: g.UpdatedAt,
+		}
+	}
+	for i, session := range data.Sessions {
+		archive.Sessions[i] = ArchiveSession{
+			ID:          session.ID,
+			GroupID:     session.GroupID,
+			Activity:    activityNames[session.StudyActivityID],
+			ReviewOrder: session.ReviewOrder,
+			WordLimit:   session.WordLimit,
+			CreatedAt:   session.CreatedAt,
+		}
+	}
+	for i, r := range data.Reviews {
+		archive.Reviews[i] = ArchiveReview{
+			ID:             r.ID,
+			WordID:         r.WordID,
+			StudySessionID: r.StudySessionID,
+			Correct:        r.Correct,
+			Grade:          r.Grade,
+			AnswerTimeMs:   r.AnswerTimeMs,
+			CreatedAt:      r.CreatedAt,
+		}
+	}
+	for i, sentence := range data.Sentences {
+		archive.Sentences[i] = ArchiveSentence{
+			ID:        sentence.ID,
+			WordID:    sentence.WordID,
+			Japanese:  sentence.Japanese,
+			English:   sentence.English,
+			CreatedAt: sentence.CreatedAt,
+		}
+	}
+	return archive, nil
+}
+
+// PlanArchiveRestore reads an archive, as JSON or zipped, and reports what
+// RestoreArchive would do with it, without changing anything
+func (s *ArchiveService) PlanArchiveRestore(ctx context.Context, raw []byte) (*ArchiveRestorePlan, error) {
+	ctx, span := tracer.Start(ctx, "ArchiveService.PlanArchiveRestore")
+	defer span.End()
+
+	archive, digest, err := ReadArchive(raw)
+	if err != nil {
+		return nil, err
+	}
+	_, creates, err := s.archiveData(ctx, archive)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.archiveRepo.CountRestore(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to count account data", err)
+	}
+	return &ArchiveRestorePlan{
+		Action:            ArchiveActionRestore,
+		Version:           archive.Version,
+		ExportedAt:        archive.ExportedAt,
+		Deletes:           counts,
+		Creates:           creates,
+		ConfirmationToken: restoreToken(digest, counts),
+	}, nil
+}
+
+// RestoreArchive replaces all words, groups, their goals and study history with
+// the contents of an archive and applies its preferences. confirm must be the
+// token of a current PlanArchiveRestore of the same archive. Study activities,
+// certificates and the audit log are kept; every session's activity must exist.
+func (s *ArchiveService) RestoreArchive(ctx context.Context, raw []byte, confirm, actor string) (*ArchiveRestorePlan, error) {
+	ctx, span := tracer.Start(ctx, "ArchiveService.RestoreArchive")
+	defer span.End()
+
+	archive, digest, err := ReadArchive(raw)
+	if err != nil {
+		return nil, err
+	}
+	data, creates, err := s.archiveData(ctx, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted map[string]int64
+	err = s.archiveRepo.Restore(ctx, data, func(counts map[string]int64) error {
+		deleted = counts
+		if confirm == "" {
+			return NewServiceError(ErrCodeInvalidInput, "Confirmation token required; request a dry run first", nil)
+		}
+		if confirm != restoreToken(digest, counts) {
+			return NewServiceError(ErrCodeInvalidInput, "Confirmation token does not match this archive and the current data; request a new dry run", nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, resetError("Failed to restore archive", err)
+	}
+	s.invalidateDashboard()
+	s.publish(events.TypeDataRestored, nil)
+
+	plan := &ArchiveRestorePlan{
+		Action:     ArchiveActionRestore,
+		Version:    archive.Version,
+		ExportedAt: archive.ExportedAt,
+		Deletes:    deleted,
+		Creates:    creates,
+	}
+	if err := s.recordAudit(ctx, actor, models.AuditActionRestore, models.AuditEntityAll, nil, deleted, creates); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ReadArchive decodes an archive sent as JSON or as a zip file holding
+// ArchiveFileName, and returns it with a digest of its contents
+func ReadArchive(raw []byte) (*Archive, string, error) {
+	if bytes.HasPrefix(raw, []byte("PK\x03\x04")) {
+		var err error
+		if raw, err = unzipArchive(raw); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var archive Archive
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&archive); err != nil {
+		return nil, "", NewServiceError(ErrCodeInvalidInput, "Archive is not valid JSON", err).WithDetails(ErrorReason{Reason: err.Error()})
+	}
+	if archive.Version != ArchiveVersion {
+		return nil, "", NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("Unsupported archive version %d", archive.Version), nil)
+	}
+	sum := sha256.Sum256(raw)
+	return &archive, hex.EncodeToString(sum[:]), nil
+}
+
+// WriteArchiveZip writes archive to w as a zip file holding ArchiveFileName
+func WriteArchiveZip(w io.Writer, archive *Archive) error {
+	zw := zip.NewWriter(w)
+	f, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     ArchiveFileName,
+		Method:   zip.Deflate,
+		Modified: archive.ExportedAt,
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(archive); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func unzipArchive(raw []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Archive is not a valid zip file", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != ArchiveFileName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Archive is not a valid zip file", err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(io.LimitReader(rc, maxArchiveEntrySize+1))
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Archive is not a valid zip file", err)
+		}
+		if len(data) > maxArchiveEntrySize {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Archive is too large", nil)
+		}
+		return data, nil
+	}
+	return nil, NewServiceError(ErrCodeInvalidInput, "Zip file does not contain "+ArchiveFileName, nil)
+}
+
+// archiveData checks the references within an archive, resolves session
+// activities by name and converts it into rows, counting them per table
+func (s *ArchiveService) archiveData(ctx context.Context, archive *Archive) (*repository.ArchiveData, map[string]int, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf(format, args...), nil)
+	}
+
+	activities, err := s.studyRepo.ListAllStudyActivities(ctx)
+	if err != nil {
+		return nil, nil, NewServiceError(ErrCodeInternal, "Failed to list study activities", err)
+	}
+	activityIDs := make(map[string]uint, len(activities))
+	for _, a := range activities {
+		activityIDs[a.Name] = a.ID
+	}
+
+	data := &repository.ArchiveData{
+		Words:     make([]models.Word, len(archive.Words)),
+		Groups:    make([]models.Group, len(archive.Groups)),
+		Sessions:  make([]models.StudySession, len(archive.Sessions)),
+		Reviews:   make([]models.WordReview, len(archive.Reviews)),
+		Sentences: make([]models.ExampleSentence, len(archive.Sentences)),
+	}
+
+	words := make(map[uint]bool, len(archive.Words))
+	japanese := make(map[string]bool, len(archive.Words))
+	for i, w := range archive.Words {
+		if w.ID == 0 || words[w.ID] {
+			return nil, nil, invalid("Word %d: missing or duplicate id", i+1)
+		}
+		if w.Japanese == "" || w.Romaji == "" || w.English == "" {
+			return nil, nil, invalid("Word %d: japanese, romaji and english are required", w.ID)
+		}
+		if japanese[w.Japanese] {
+			return nil, nil, invalid("Word %d: duplicate japanese %q", w.ID, w.Japanese)
+		}
+		language, err := targetLanguage(w.Language)
+		if err != nil {
+			return nil, nil, invalid("Word %d: unsupported language %q", w.ID, w.Language)
+		}
+		words[w.ID] = true
+		japanese[w.Japanese] = true
+		data.Words[i] = models.Word{
+			ID:             w.ID,
+			Language:       language,
+			Term:           w.Japanese,
+			Reading:        w.Romaji,
+			Script:         w.Script,
+			ReadingScript:  w.ReadingScript,
+			English:        w.English,
+			German:         w.German,
+			Parts:          models.StringSlice(w.Parts),
+			CreatedAt:      w.CreatedAt,
+			UpdatedAt:      w.UpdatedAt,
+			LastReviewedAt: w.LastReviewedAt,
+			NextDueAt:      w.NextDueAt,
+			AccuracyEWMA:   w.AccuracyEWMA,
+		}
+		data.Words[i].DefaultScripts()
+	}
+
+	groups := make(map[uint]bool, len(archive.Groups))
+	names := make(map[string]bool, len(archive.Groups))
+	for i, g := range archive.Groups {
+		if g.ID == 0 || groups[g.ID] {
+			return nil, nil, invalid("Group %d: missing or duplicate id", i+1)
+		}
+		language, err := targetLanguage(g.Language)
+		if err != nil {
+			return nil, nil, invalid("Group %d: unsupported language %q", g.ID, g.Language)
+		}
+		// Group names are unique per language
+		name := strings.TrimSpace(g.Name)
+		if name == "" || names[language+"\x00"+name] {
+			return nil, nil, invalid("Group %d: missing or duplicate name", g.ID)
+		}
+		groups[g.ID] = true
+		names[language+"\x00"+name] = true
+		data.Groups[i] = models.Group{ID: g.ID, Name: name, Language: language, CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt}
+
+		inGroup := make(map[uint]bool, len(g.WordIDs))
+		for _, wordID := range g.WordIDs {
+			if !words[wordID] {
+				return nil, nil, invalid("Group %d: unknown word %d", g.ID, wordID)
+			}
+			if !inGroup[wordID] {
+				inGroup[wordID] = true
+				data.WordGroups = append(data.WordGroups, repository.WordGroup{GroupID: g.ID, WordID: wordID})
+			}
+		}
+	}
+
+	sessions := make(map[uint]bool, len(archive.Sessions))
+	for i, session := range archive.Sessions {
+		if session.ID == 0 || sessions[session.ID] {
+			return nil, nil, invalid("Study session %d: missing or duplicate id", i+1)
+		}
+		if !groups[session.GroupID] {
+			return nil, nil, invalid("Study session %d: unknown group %d", session.ID, session.GroupID)
+		}
+		activityID, ok := activityIDs[session.Activity]
+		if !ok {
+			return nil, nil, invalid("Study session %d: unknown study activity %q; import the activity catalog first", session.ID, session.Activity)
+		}
+		if session.ReviewOrder != "" && !ValidReviewOrder(session.ReviewOrder) {
+			return nil, nil, invalid("Study session %d: unknown review order %q", session.ID, session.ReviewOrder)
+		}
+		if session.WordLimit < 0 || session.WordLimit > MaxBundleWords {
+			return nil, nil, invalid("Study session %d: word limit must be between 0 and %d", session.ID, MaxBundleWords)
+		}
+		sessions[session.ID] = true
+		data.Sessions[i] = models.StudySession{
+			ID:              session.ID,
+			GroupID:         session.GroupID,
+			StudyActivityID: activityID,
+			ReviewOrder:     session.ReviewOrder,
+			WordLimit:       session.WordLimit,
+			CreatedAt:       session.CreatedAt,
+		}
+	}
+
+	reviews := make(map[uint]bool, len(archive.Reviews))
+	for i, r := range archive.Reviews {
+		if r.ID == 0 || reviews[r.ID] {
+			return nil, nil, invalid("Word review %d: missing or duplicate id", i+1)
+		}
+		if !words[r.WordID] {
+			return nil, nil, invalid("Word review %d: unknown word %d", r.ID, r.WordID)
+		}
+		if !sessions[r.StudySessionID] {
+			return nil, nil, invalid("Word review %d: unknown study session %d", r.ID, r.StudySessionID)
+		}
+		if !r.Grade.Valid() || (r.Grade != "" && r.Grade.Correct() != r.Correct) {
+			return nil, nil, invalid("Word review %d: invalid grade %q", r.ID, r.Grade)
+		}
+		if !models.ValidAnswerTime(r.AnswerTimeMs) {
+			return nil, nil, invalid("Word review %d: answer_time_ms must be between 0 and %d", r.ID, models.MaxAnswerTimeMs)
+		}
+		reviews[r.ID] = true
+		data.Reviews[i] = models.WordReview{
+			ID:             r.ID,
+			WordID:         r.WordID,
+			StudySessionID: r.StudySessionID,
+			Correct:        r.Correct,
+			Grade:          r.Grade,
+			AnswerTimeMs:   r.AnswerTimeMs,
+			CreatedAt:      r.CreatedAt,
+		}
+	}
+
+	sentences := make(map[uint]bool, len(archive.Sentences))
+	for i, sentence := range archive.Sentences {
+		if sentence.ID == 0 || sentences[sentence.ID] {
+			return nil, nil, invalid("Example sentence %d: missing or duplicate id", i+1)
+		}
+		if !words[sentence.WordID] {
+			return nil, nil, invalid("Example sentence %d: unknown word %d", sentence.ID, sentence.WordID)
+		}
+		sentences[sentence.ID] = true
+		data.Sentences[i] = models.ExampleSentence{
+			ID:        sentence.ID,
+			WordID:    sentence.WordID,
+			Japanese:  sentence.Japanese,
+			English:   sentence.English,
+			CreatedAt: sentence.CreatedAt,
+		}
+		if err := data.Sentences[i].Validate(); err != nil {
+			return nil, nil, invalid("Example sentence %d: japanese is required and sentences are limited to 200 characters", sentence.ID)
+		}
+	}
+
+	if archive.Preferences != nil {
+		if err := archive.Preferences.validate(); err != nil {
+			return nil, nil, err
+		}
+		for key, value := range archive.Preferences.settings() {
+			data.Settings = append(data.Settings, models.Setting{Key: key, Value: value})
+		}
+	}
+
+	creates := map[string]int{
+		"words":             len(data.Words),
+		"groups":            len(data.Groups),
+		"word_groups":       len(data.WordGroups),
+		"study_sessions":    len(data.Sessions),
+		"word_review_items": len(data.Reviews),
+		"example_sentences": len(data.Sentences),
+		"settings":          len(data.Settings),
+	}
+	return data, creates, nil
+}
+
+// restoreToken hashes the archive digest with the rows a restore would delete
+func restoreToken(digest string, counts map[string]int64) string {
+	return resetToken(ArchiveActionRestore+"\n"+digest, counts)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadArchive(t *testing.T) {
+	archive := &Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Words:      []ArchiveWord{{ID: 1, Japanese: "猫", Romaji: "neko", English: "cat", Parts: []string{"noun"}}},
+		Groups:     []ArchiveGroup{{ID: 2, Name: "Animals", WordIDs: []uint{1}}},
+	}
+
+	var zipped bytes.Buffer
+	require.NoError(t, WriteArchiveZip(&zipped, archive))
+	read, digest, err := ReadArchive(zipped.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, archive.Words, read.Words)
+	assert.Equal(t, archive.Groups, read.Groups)
+	assert.Len(t, digest, 64)
+
+	_, plainDigest, err := ReadArchive([]byte(`{"version":1,"words":[]}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, plainDigest)
+
+	for name, raw := range map[string]string{
+		"unsupported version": `{"version":2}`,
+		"unknown field":       `{"version":1,"wordz":[]}`,
+		"not json":            `version: 1`,
+		"broken zip":          "PK\x03\x04broken",
+	} {
+		_, _, err := ReadArchive([]byte(raw))
+		require.Error(t, err, name)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, name)
+	}
+}
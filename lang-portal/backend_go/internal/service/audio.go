@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/embeddings"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// ListeningActivityName is the study activity listening quizzes are recorded
+// under. It is created the first time a quiz is generated.
+const ListeningActivityName = "Listening Quiz"
+
+// listeningActivity is the study activity listening answers are recorded under
+var listeningActivity = models.StudyActivity{
+	Name:         ListeningActivityName,
+	Description:  "Hear a word and pick its meaning",
+	ThumbnailURL: "/thumbnails/listening-quiz.png",
+	Modes:        models.StringSlice{"listening", "multiple_choice"},
+	Capabilities: models.StringSlice{"audio"},
+}
+
+// Listening quiz sizes
+const (
+	DefaultListeningQuestions = 10
+	MaxListeningQuestions     = 50
+	// listeningOptions is the number of meanings offered per question, when the
+	// group has enough words
+	listeningOptions = 4
+)
+
+// AudioContentType is the content type of word audio
+const AudioContentType = "audio/mpeg"
+
+// ErrAudioNotFound is returned by an AudioSource without audio for a word
+var ErrAudioNotFound = errors.New("word audio not found")
+
+// AudioSource provides recordings of words, as MP3
+type AudioSource interface {
+	// Audio returns the recording of word, or ErrAudioNotFound
+	Audio(ctx context.Context, word *models.Word) ([]byte, error)
+	// Has reports whether there is a recording of word, without loading it
+	Has(ctx context.Context, word *models.Word) (bool, error)
+}
+
+// AudioDir reads word audio from a directory holding one MP3 per word, named
+// after the word's Japanese text, such as 食べる.mp3
+type AudioDir string
+
+// Audio implements AudioSource
+func (d AudioDir) Audio(_ context.Context, word *models.Word) ([]byte, error) {
+	path, ok := d.path(word)
+	if !ok {
+		return nil, ErrAudioNotFound
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrAudioNotFound
+	}
+	return data, err
+}
+
+// Has implements AudioSource
+func (d AudioDir) Has(_ context.Context, word *models.Word) (bool, error) {
+	path, ok := d.path(word)
+	if !ok {
+		return false, nil
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return info.Mode().IsRegular(), nil
+}
+
+// path returns the file of word's recording. The text names a file in the
+// directory, never a path.
+func (d AudioDir) path(word *models.Word) (string, bool) {
+	name := word.Term + ".mp3"
+	if strings.ContainsAny(word.Term, `/\`) || filepath.Base(name) != name {
+		return "", false
+	}
+	return filepath.Join(string(d), name), true
+}
+
+// AudioService serves word audio and the listening quizzes built on it
+type AudioService struct {
+	*BaseService
+	audio AudioSource
+}
+
+// NewAudioService creates a new audio service
+func NewAudioService(base *BaseService) *AudioService {
+	return &AudioService{BaseService: base}
+}
+
+// WithAudioSource serves word audio from src. Without one, words have no audio
+// and listening quizzes are empty.
+func (s *AudioService) WithAudioSource(src AudioSource) *AudioService {
+	s.audio = src
+	return s
+}
+
+// ListeningQuestion asks for the meaning of a word played from AudioURL.
+// Options are English meanings; AnswerIndex is the position of the word's own.
+type ListeningQuestion struct {
+	WordID      uint     `json:"word_id"`
+	AudioURL    string   `json:"audio_url"`
+	Options     []string `json:"options"`
+	AnswerIndex int      `json:"answer_index"`
+}
+
+// ListeningQuiz is a list of listening questions for a group. Reviews of the
+// answers are recorded in sessions of the listening study activity.
+type ListeningQuiz struct {
+	StudyActivityID uint                `json:"study_activity_id"`
+	GroupID         uint                `json:"group_id"`
+	Questions       []ListeningQuestion `json:"questions"`
+}
+
+// GetWordAudio returns the recording of a word
+func (s *AudioService) GetWordAudio(ctx context.Context, wordID uint) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "AudioService.GetWordAudio")
+	defer span.End()
+
+	word, err := s.wordRepo.GetByID(ctx, wordID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Word not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
+	}
+
+	data, err := s.loadAudio(ctx, word)
+	if err != nil {
+		if errors.Is(err, ErrAudioNotFound) {
+			return nil, NewServiceError(ErrCodeNotFound, "No audio for this word", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to load word audio", err)
+	}
+	return data, nil
+}
+
+// GenerateListeningQuiz builds up to limit listening questions, at most
+// MaxListeningQuestions, for the words of a group that have audio. Words are
+// taken in the preferred review order. Each question offers the word's meaning
+// and up to three different meanings of other words in the group, shuffled; a
+// word is skipped when no other word has a different meaning.
+func (s *AudioService) GenerateListeningQuiz(ctx context.Context, groupID uint, limit int) (*ListeningQuiz, error) {
+	ctx, span := tracer.Start(ctx, "AudioService.GenerateListeningQuiz")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = DefaultListeningQuestions
+	}
+	if limit > MaxListeningQuestions {
+		limit = MaxListeningQuestions
+	}
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+
+	activity, err := s.builtinActivity(ctx, listeningActivity)
+	if err != nil {
+		return nil, err
+	}
+	words, err := s.wordRepo.GetWordsByGroupRaw(ctx, groupID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group words", err)
+	}
+	order, err := s.defaultReviewOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// With embeddings, distractors are the meanings closest to the word's; a
+	// provider failure falls back to random distractors
+	var vectors map[uint][]float32
+	if s.embeddings != nil {
+		vectors, _ = s.wordVectors(ctx, words)
+	}
+
+	quiz := &ListeningQuiz{StudyActivityID: activity.ID, GroupID: groupID, Questions: []ListeningQuestion{}}
+	seed := time.Now().UnixNano()
+	orderWords(words, order, seed)
+	rnd := rand.New(rand.NewSource(seed))
+	for i := range words {
+		if len(quiz.Questions) == limit {
+			break
+		}
+		has, err := s.hasAudio(ctx, &words[i])
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to look up word audio", err)
+		}
+		if !has {
+			continue
+		}
+		if question, ok := listeningQuestion(words, i, rnd, vectors); ok {
+			quiz.Questions = append(quiz.Questions, question)
+		}
+	}
+	return quiz, nil
+}
+
+// listeningQuestion makes the question for words[i], with distinct meanings of
+// the other words as wrong options. Given the words' embeddings, the meanings of
+// the most similar words are preferred.
+func listeningQuestion(words []models.Word, i int, rnd *rand.Rand, vectors map[uint][]float32) (ListeningQuestion, bool) {
+	word := words[i]
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(word.English)): true}
+	candidates := rnd.Perm(len(words))
+	if target, ok := vectors[word.ID]; ok {
+		similarity := make(map[int]float64, len(words))
+		for _, j := range candidates {
+			similarity[j] = -2
+			if vector, ok := vectors[words[j].ID]; ok {
+				similarity[j] = embeddings.Cosine(target, vector)
+			}
+		}
+		sort.SliceStable(candidates, func(a, b int) bool { return similarity[candidates[a]] > similarity[candidates[b]] })
+	}
+	var others []string
+	for _, j := range candidates {
+		meaning := strings.TrimSpace(words[j].English)
+		key := strings.ToLower(meaning)
+		if meaning == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		others = append(others, meaning)
+		if len(others) == listeningOptions-1 {
+			break
+		}
+	}
+	if len(others) == 0 {
+		return ListeningQuestion{}, false
+	}
+
+	options := append(others, strings.TrimSpace(word.English))
+	rnd.Shuffle(len(options), func(a, b int) { options[a], options[b] = options[b], options[a] })
+	question := ListeningQuestion{
+		WordID:   word.ID,
+		AudioURL: wordAudioURL(word.ID),
+		Options:  options,
+	}
+	for k, option := range options {
+		if option == strings.TrimSpace(word.English) {
+			question.AnswerIndex = k
+		}
+	}
+	return question, true
+}
+
+// wordAudioURL is the path serving the recording of a word
+func wordAudioURL(id uint) string {
+	return "/api/v1/words/" + strconv.FormatUint(uint64(id), 10) + "/audio"
+}
+
+func (s *AudioService) hasAudio(ctx context.Context, word *models.Word) (bool, error) {
+	if s.audio == nil {
+		return false, nil
+	}
+	return s.audio.Has(ctx, word)
+}
+
+func (s *AudioService) loadAudio(ctx context.Context, word *models.Word) ([]byte, error) {
+	if s.audio == nil {
+		return nil, ErrAudioNotFound
+	}
+	return s.audio.Audio(ctx, word)
+}
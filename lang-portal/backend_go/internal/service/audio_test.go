@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "食べる.mp3"), []byte("ID3"), 0o644))
+
+	audio, err := AudioDir(dir).Audio(context.Background(), &models.Word{Term: "食べる"})
+	require.NoError(t, err)
+	assert.Equal(t, "ID3", string(audio))
+
+	has, err := AudioDir(dir).Has(context.Background(), &models.Word{Term: "食べる"})
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	for _, japanese := range []string{"飲む", "../食べる", "a/b", ""} {
+		_, err := AudioDir(dir).Audio(context.Background(), &models.Word{Term: japanese})
+		assert.Equal(t, ErrAudioNotFound, err, japanese)
+		has, err := AudioDir(dir).Has(context.Background(), &models.Word{Term: japanese})
+		require.NoError(t, err, japanese)
+		assert.False(t, has, japanese)
+	}
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "見る.mp3"), 0o755))
+	has, err = AudioDir(dir).Has(context.Background(), &models.Word{Term: "見る"})
+	require.NoError(t, err)
+	assert.False(t, has, "only files are recordings")
+}
+
+func TestListeningQuestion(t *testing.T) {
+	words := []models.Word{
+		{ID: 1, English: "to eat"},
+		{ID: 2, English: "to drink"},
+		{ID: 3, English: "To Eat"},
+		{ID: 4, English: "cat"},
+		{ID: 5, English: "dog"},
+		{ID: 6, English: "bird"},
+	}
+	rnd := rand.New(rand.NewSource(1))
+
+	question, ok := listeningQuestion(words, 0, rnd, nil)
+	require.True(t, ok)
+	assert.Equal(t, uint(1), question.WordID)
+	assert.Equal(t, "/api/v1/words/1/audio", question.AudioURL)
+	require.Len(t, question.Options, listeningOptions)
+	assert.Equal(t, "to eat", question.Options[question.AnswerIndex])
+	assert.NotContains(t, question.Options, "To Eat", "meanings are offered once, ignoring case")
+
+	_, ok = listeningQuestion(words[:1], 0, rnd, nil)
+	assert.False(t, ok, "a question needs at least one wrong option")
+}
+
+func TestListeningQuestion_SimilarDistractors(t *testing.T) {
+	words := []models.Word{
+		{ID: 1, English: "cat"},
+		{ID: 2, English: "bread"},
+		{ID: 3, English: "dog"},
+		{ID: 4, English: "rice"},
+		{ID: 5, English: "bird"},
+		{ID: 6, English: "tea"},
+		{ID: 7, English: "fox"},
+	}
+	vectors := map[uint][]float32{
+		1: {1, 0}, 2: {0, 1}, 3: {1, 0.1}, 4: {0, 1}, 5: {1, 0.2}, 6: {0, 1}, 7: {1, 0.3},
+	}
+
+	question, ok := listeningQuestion(words, 0, rand.New(rand.NewSource(1)), vectors)
+	require.True(t, ok)
+	assert.ElementsMatch(t, []string{"cat", "dog", "bird", "fox"}, question.Options)
+}
+
+func TestAudioService_WithoutSource(t *testing.T) {
+	s := NewAudioService(NewBaseService(nil, nil, nil, nil, nil))
+	_, err := s.loadAudio(context.Background(), &models.Word{Term: "食べる"})
+	assert.Equal(t, ErrAudioNotFound, err)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// AuditService handles access to the audit log
+type AuditService struct {
+	*BaseService
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(base *BaseService) *AuditService {
+	return &AuditService{BaseService: base}
+}
+
+// AuditFilter narrows down the audit entries returned by ListAuditEntries
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   *uint
+	Since      time.Time
+	Until      time.Time
+}
+
+// ListAuditEntries retrieves a paginated list of audit entries, newest first
+func (s *AuditService) ListAuditEntries(ctx context.Context, filter AuditFilter, params PaginationParams) (*PaginatedResult[models.AuditEntry], error) {
+	ctx, span := tracer.Start(ctx, "AuditService.ListAuditEntries")
+	defer span.End()
+
+	if s.auditRepo == nil {
+		return nil, NewServiceError(ErrCodeInternal, "Audit log is not configured", nil)
+	}
+
+	result, err := s.auditRepo.List(ctx, repository.AuditFilter{
+		Actor:      filter.Actor,
+		Action:     filter.Action,
+		EntityType: filter.EntityType,
+		EntityID:   filter.EntityID,
+		TimeRange:  repository.TimeRange{Start: filter.Since, End: filter.Until},
+	}, repository.PaginationParams{
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list audit entries", err)
+	}
+
+	return NewPaginatedResult(result.Items, result.TotalItems, params.Page, params.PageSize), nil
+}
+
+// recordAudit writes an audit entry for a completed destructive operation.
+// It is a no-op when no audit repository is configured.
+func (s *BaseService) recordAudit(ctx context.Context, actor, action, entityType string, entityID *uint, before, after interface{}) error {
+	if s.auditRepo == nil {
+		return nil
+	}
+
+	entry, err := models.NewAuditEntry(actor, action, entityType, entityID, before, after)
+	if err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to encode audit entry", err)
+	}
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to record audit entry", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockAuditRepository is a mock implementation of AuditRepositoryInterface
+type mockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *mockAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *mockAuditRepository) List(ctx context.Context, filter repository.AuditFilter, params repository.PaginationParams) (*repository.PaginatedResult[models.AuditEntry], error) {
+	args := m.Called(filter, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.PaginatedResult[models.AuditEntry]), args.Error(1)
+}
+
+func TestWordService_DeleteWord_RecordsAudit(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	mockAudit := new(mockAuditRepository)
+	wordService := NewWordService(NewBaseService(mockRepo, nil, nil, mockAudit, nil))
+
+	testWordID := uint(7)
+	mockRepo.On("GetByID", testWordID).Return(&models.Word{ID: testWordID, Term: "犬", English: "dog"}, nil)
+	mockRepo.On("Delete", testWordID).Return(nil)
+
+	var recorded *models.AuditEntry
+	mockAudit.On("Create", mock.AnythingOfType("*models.AuditEntry")).Run(func(args mock.Arguments) {
+		recorded = args.Get(0).(*models.AuditEntry)
+	}).Return(nil)
+
+	err := wordService.DeleteWord(context.Background(), testWordID, "tester")
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, recorded) {
+		assert.Equal(t, "tester", recorded.Actor)
+		assert.Equal(t, models.AuditActionDelete, recorded.Action)
+		assert.Equal(t, models.AuditEntityWord, recorded.EntityType)
+		assert.Equal(t, testWordID, *recorded.EntityID)
+		assert.Nil(t, recorded.After)
+
+		var before models.Word
+		assert.NoError(t, json.Unmarshal(recorded.Before, &before))
+		assert.Equal(t, "犬", before.Term)
+	}
+	mockRepo.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestAuditService_ListAuditEntries(t *testing.T) {
+	mockAudit := new(mockAuditRepository)
+	auditService := NewAuditService(NewBaseService(nil, nil, nil, mockAudit, nil))
+
+	mockAudit.On("List", repository.AuditFilter{Action: models.AuditActionDelete}, repository.PaginationParams{Page: 1, PageSize: 10}).
+		Return(&repository.PaginatedResult[models.AuditEntry]{
+			Items:      []models.AuditEntry{{ID: 1, Actor: "tester", Action: models.AuditActionDelete}},
+			TotalItems: 1,
+		}, nil)
+
+	result, err := auditService.ListAuditEntries(context.Background(), AuditFilter{Action: models.AuditActionDelete}, PaginationParams{Page: 1, PageSize: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.TotalItems)
+	assert.Len(t, result.Items, 1)
+	mockAudit.AssertExpectations(t)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// certificateLine is a line of text on the certificate page
+type certificateLine struct {
+	font string
+	size int
+	y    int
+	text string
+}
+
+// RenderCertificatePDF renders a certificate as a single landscape A4 page.
+// The page uses the standard PDF fonts, so characters outside ASCII, such as a
+// Japanese group name, are replaced; the JSON record keeps the exact values.
+func RenderCertificatePDF(c *models.GroupCertificate) []byte {
+	lines := []certificateLine{
+		{"F1", 32, 460, "Certificate of Completion"},
+		{"F2", 18, 400, "Group: " + c.GroupName},
+		{"F2", 14, 360, fmt.Sprintf("Goal: %s accuracy over the last %d sessions", formatPercent(c.MinAccuracy), c.Sessions)},
+		{"F2", 14, 335, fmt.Sprintf("Achieved: %s accuracy on %s", formatPercent(c.Accuracy), c.AchievedAt.UTC().Format("2006-01-02"))},
+		{"F2", 10, 150, "Certificate ID: " + c.Token},
+		{"F2", 10, 135, "Signature: " + c.Signature},
+		{"F2", 10, 120, "Verify at /api/public/certificates/" + c.Token},
+	}
+
+	var content bytes.Buffer
+	for _, line := range lines {
+		fmt.Fprintf(&content, "BT /%s %d Tf 72 %d Td (%s) Tj ET\n", line.font, line.size, line.y, pdfString(line.text))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 842 595] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+// pdfString escapes text for a PDF literal string, replacing what the standard
+// fonts cannot show
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func formatPercent(ratio float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", ratio*100), "0"), ".") + "%"
+}
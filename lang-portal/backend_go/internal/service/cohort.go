@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// maxLearnerLength matches the longest actor study sessions are recorded under
+const maxLearnerLength = 100
+
+// CohortService manages the cohorts teachers run and reports their students'
+// study. A teacher is the actor that created a cohort; apart from its members,
+// who see its leaderboard, other actors are not told it exists.
+type CohortService struct {
+	*BaseService
+	cohortRepo repository.CohortRepositoryInterface
+}
+
+// NewCohortService creates a new cohort service
+func NewCohortService(base *BaseService, cohortRepo repository.CohortRepositoryInterface) *CohortService {
+	return &CohortService{BaseService: base, cohortRepo: cohortRepo}
+}
+
+// StudentStats is the study of one member of a cohort in the cohort's groups.
+// LastStudiedAt is nil for members who have not studied them.
+type StudentStats struct {
+	Learner        string     `json:"learner"`
+	Sessions       int64      `json:"sessions"`
+	Reviews        int64      `json:"reviews"`
+	CorrectReviews int64      `json:"correct_reviews"`
+	SuccessRate    int        `json:"success_rate"`
+	LastStudiedAt  *time.Time `json:"last_studied_at"`
+}
+
+// CohortTotals sums the study of a cohort's members. ActiveStudents counts the
+// members with at least one session.
+type CohortTotals struct {
+	Students       int   `json:"students"`
+	ActiveStudents int   `json:"active_students"`
+	Sessions       int64 `json:"sessions"`
+	Reviews        int64 `json:"reviews"`
+	CorrectReviews int64 `json:"correct_reviews"`
+	SuccessRate    int   `json:"success_rate"`
+}
+
+// CohortStats reports the study of a cohort's members in its groups, in total
+// and per student
+type CohortStats struct {
+	CohortID uint           `json:"cohort_id"`
+	Name     string         `json:"name"`
+	GroupIDs []uint         `json:"group_ids"`
+	Totals   CohortTotals   `json:"totals"`
+	Students []StudentStats `json:"students"`
+}
+
+// CreateCohort creates a cohort run by owner
+func (s *CohortService) CreateCohort(ctx context.Context, owner, name string) (*models.Cohort, error) {
+	ctx, span := tracer.Start(ctx, "CohortService.CreateCohort")
+	defer span.End()
+
+	cohort := &models.Cohort{Name: strings.TrimSpace(name), Owner: owner}
+	if err := s.cohortRepo.Create(ctx, cohort); err != nil {
+		if err == repository.ErrInvalidInput {
+			return nil, NewServiceError(ErrCodeInvalidInput, "name must be 1 to 100 characters", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create cohort", err)
+	}
+	return cohort, nil
+}
+
+// ListCohorts returns the cohorts owner runs, newest first
+func (s *CohortService) ListCohorts(ctx context.Context, owner string) ([]models.Cohort, error) {
+	ctx, span := tracer.Start(ctx, "CohortService.ListCohorts")
+	defer span.End()
+
+	cohorts, err := s.cohortRepo.ListByOwner(ctx, owner)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list cohorts", err)
+	}
+	return cohorts, nil
+}
+
+// GetCohort returns one of owner's cohorts with its members and groups
+func (s *CohortService) GetCohort(ctx context.Context, owner string, id uint) (*models.Cohort, error) {
+	ctx, span := tracer.Start(ctx, "CohortService.GetCohort")
+	defer span.End()
+
+	return s.getCohort(ctx, owner, id)
+}
+
+// DeleteCohort deletes one of owner's cohorts and records it in the audit log.
+// The groups and the study of its members are kept.
+func (s *CohortService) DeleteCohort(ctx context.Context, owner string, id uint) error {
+	ctx, span := tracer.Start(ctx, "CohortService.DeleteCohort")
+	defer span.End()
+
+	cohort, err := s.getCohort(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+	if err := s.cohortRepo.Delete(ctx, id); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Cohort not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to delete cohort", err)
+	}
+	s.invalidateLeaderboard(id)
+	return s.recordAudit(ctx, owner, models.AuditActionDelete, models.AuditEntityCohort, &id, cohort, nil)
+}
+
+// AddMember adds a learner, the actor their sessions are recorded under, to one
+// of owner's cohorts
+func (s *CohortService) AddMember(ctx context.Context, owner string, id uint, learner string) (*models.CohortMembership, error) {
+	ctx, span := tracer.Start(ctx, "CohortService.AddMember")
+	defer span.End()
+
+	learner = strings.TrimSpace(learner)
+	if learner == "" || len(learner) > maxLearnerLength {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("learner must be 1 to %d characters", maxLearnerLength), nil)
+	}
+	if _, err := s.getCohort(ctx, owner, id); err != nil {
+		return nil, err
+	}
+
+	membership := &models.CohortMembership{CohortID: id, Learner: learner}
+	if err := s.cohortRepo.AddMember(ctx, membership); err != nil {
+		if err == repository.ErrAlreadyExists {
+			return nil, NewServiceError(ErrCodeConflict, "Learner is already in the cohort", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to add cohort member", err)
+	}
+	s.invalidateLeaderboard(id)
+	return membership, nil
+}
+
+// RemoveMember removes a learner from one of owner's cohorts
+func (s *CohortService) RemoveMember(ctx context.Context, owner string, id uint, learner string) error {
+	ctx, span := tracer.Start(ctx, "CohortService.RemoveMember")
+	defer span.End()
+
+	if _, err := s.getCohort(ctx, owner, id); err != nil {
+		return err
+	}
+	if err := s.cohortRepo.RemoveMember(ctx, id, learner); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Learner is not in the cohort", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to remove cohort member", err)
+	}
+	s.invalidateLeaderboard(id)
+	return nil
+}
+
+// AssignGroup assigns a group to one of owner's cohorts
+func (s *CohortService) AssignGroup(ctx context.Context, owner string, id, groupID uint) error {
+	ctx, span := tracer.Start(ctx, "CohortService.AssignGroup")
+	defer span.End()
+
+	if _, err := s.getCohort(ctx, owner, id); err != nil {
+		return err
+	}
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+	if err := s.cohortRepo.AddGroup(ctx, id, groupID); err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to assign group", err)
+	}
+	s.invalidateLeaderboard(id)
+	return nil
+}
+
+// UnassignGroup removes a group from one of owner's cohorts
+func (s *CohortService) UnassignGroup(ctx context.Context, owner string, id, groupID uint) error {
+	ctx, span := tracer.Start(ctx, "CohortService.UnassignGroup")
+	defer span.End()
+
+	if _, err := s.getCohort(ctx, owner, id); err != nil {
+		return err
+	}
+	if err := s.cohortRepo.RemoveGroup(ctx, id, groupID); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Group is not assigned to the cohort", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to unassign group", err)
+	}
+	s.invalidateLeaderboard(id)
+	return nil
+}
+
+// GetCohortStats reports the study of the members of one of owner's cohorts in
+// its groups. Every member is listed, in name order, including those who have
+// not studied yet.
+func (s *CohortService) GetCohortStats(ctx context.Context, owner string, id uint) (*CohortStats, error) {
+	ctx, span := tracer.Start(ctx, "CohortService.GetCohortStats")
+	defer span.End()
+
+	cohort, err := s.getCohort(ctx, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.cohortRepo.GetLearnerStats(ctx, id, time.Time{})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get cohort statistics", err)
+	}
+	byLearner := make(map[string]repository.LearnerStudyStats, len(rows))
+	for _, row := range rows {
+		byLearner[row.Learner] = row
+	}
+
+	stats := &CohortStats{
+		CohortID: cohort.ID,
+		Name:     cohort.Name,
+		GroupIDs: make([]uint, len(cohort.Groups)),
+		Students: make([]StudentStats, len(cohort.Members)),
+	}
+	for i, group := range cohort.Groups {
+		stats.GroupIDs[i] = group.GroupID
+	}
+	for i, member := range cohort.Members {
+		student := StudentStats{Learner: member.Learner}
+		if row, ok := byLearner[member.Learner]; ok {
+			last := row.LastStudiedAt
+			student = StudentStats{
+				Learner:        member.Learner,
+				Sessions:       row.Sessions,
+				Reviews:        row.Reviews,
+				CorrectReviews: row.CorrectReviews,
+				SuccessRate:    successRate(row.CorrectReviews, row.Reviews),
+				LastStudiedAt:  &last,
+			}
+			stats.Totals.ActiveStudents++
+		}
+		stats.Students[i] = student
+		stats.Totals.Sessions += student.Sessions
+		stats.Totals.Reviews += student.Reviews
+		stats.Totals.CorrectReviews += student.CorrectReviews
+	}
+	stats.Totals.Students = len(cohort.Members)
+	stats.Totals.SuccessRate = successRate(stats.Totals.CorrectReviews, stats.Totals.Reviews)
+	return stats, nil
+}
+
+// getCohort fetches one of owner's cohorts. Other owners' cohorts are not
+// acknowledged to exist.
+func (s *CohortService) getCohort(ctx context.Context, owner string, id uint) (*models.Cohort, error) {
+	cohort, err := s.cohortRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Cohort not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch cohort", err)
+	}
+	if cohort.Owner != owner {
+		return nil, NewServiceError(ErrCodeNotFound, "Cohort not found", nil)
+	}
+	return cohort, nil
+}
+
+// successRate is the percentage of correct reviews, 0 without reviews
+func successRate(correct, total int64) int {
+	if total == 0 {
+		return 0
+	}
+	return int(float64(correct) / float64(total) * 100)
+}
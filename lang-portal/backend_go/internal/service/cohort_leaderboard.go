@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// LeaderboardCacheTTL bounds how long a cohort's leaderboard is served from the
+// cache. Membership changes invalidate it immediately; new study shows up once
+// it expires.
+const LeaderboardCacheTTL = 5 * time.Minute
+
+// Leaderboard metrics
+const (
+	LeaderboardMetricReviews  = "reviews"
+	LeaderboardMetricAccuracy = "accuracy"
+	LeaderboardMetricStreak   = "streak"
+)
+
+// leaderboardWeek is the window weekly reviews and accuracy are counted over
+const leaderboardWeek = 7 * 24 * time.Hour
+
+// maxLeaderboardStreakDays bounds how far back study days are read for streaks
+const maxLeaderboardStreakDays = 365
+
+// LeaderboardEntry is a member's place on a cohort leaderboard. Members are
+// shown under their display name only; You marks the caller.
+type LeaderboardEntry struct {
+	Rank          int    `json:"rank"`
+	DisplayName   string `json:"display_name"`
+	WeeklyReviews int64  `json:"weekly_reviews"`
+	Accuracy      int    `json:"accuracy"`
+	Streak        int    `json:"streak"`
+	You           bool   `json:"you"`
+}
+
+// Leaderboard ranks the members of a cohort who have not opted out by Metric.
+// Weekly reviews and accuracy count the sessions started since Since.
+type Leaderboard struct {
+	CohortID uint               `json:"cohort_id"`
+	Name     string             `json:"name"`
+	Metric   string             `json:"metric"`
+	Since    time.Time          `json:"since"`
+	Entries  []LeaderboardEntry `json:"entries"`
+}
+
+// leaderboardStanding is the cached study of one member, before ranking
+type leaderboardStanding struct {
+	Learner       string
+	DisplayName   string
+	WeeklyReviews int64
+	WeeklyCorrect int64
+	Streak        int
+}
+
+// leaderboardStandings is what is cached per cohort
+type leaderboardStandings struct {
+	Name      string
+	Since     time.Time
+	Standings []leaderboardStanding
+}
+
+// GetLeaderboard ranks the members of a cohort by metric: weekly reviews, the
+// default, weekly accuracy or study streak. Only the cohort's teacher and
+// members see it. Members who opted out are left off, and ties share a rank.
+func (s *CohortService) GetLeaderboard(ctx context.Context, caller string, id uint, metric string) (*Leaderboard, error) {
+	ctx, span := tracer.Start(ctx, "CohortService.GetLeaderboard")
+	defer span.End()
+
+	if metric == "" {
+		metric = LeaderboardMetricReviews
+	}
+	if metric != LeaderboardMetricReviews && metric != LeaderboardMetricAccuracy && metric != LeaderboardMetricStreak {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("metric must be %s, %s or %s", LeaderboardMetricReviews, LeaderboardMetricAccuracy, LeaderboardMetricStreak), nil)
+	}
+	cohort, err := s.cohortRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Cohort not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch cohort", err)
+	}
+	if cohort.Owner != caller && findMember(cohort, caller) == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Cohort not found", nil)
+	}
+
+	cached, err := cache.GetOrLoad(s.cache, leaderboardCacheKey(id), LeaderboardCacheTTL, func() (*leaderboardStandings, error) {
+		return s.computeStandings(ctx, cohort)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	standings := cached.Standings
+	sort.SliceStable(standings, func(i, j int) bool {
+		if c := compareStandings(metric, standings[i], standings[j]); c != 0 {
+			return c > 0
+		}
+		return standings[i].DisplayName < standings[j].DisplayName
+	})
+	board := &Leaderboard{
+		CohortID: cohort.ID,
+		Name:     cached.Name,
+		Metric:   metric,
+		Since:    cached.Since,
+		Entries:  make([]LeaderboardEntry, len(standings)),
+	}
+	for i, standing := range standings {
+		rank := i + 1
+		if i > 0 && compareStandings(metric, standing, standings[i-1]) == 0 {
+			rank = board.Entries[i-1].Rank
+		}
+		board.Entries[i] = LeaderboardEntry{
+			Rank:          rank,
+			DisplayName:   standing.DisplayName,
+			WeeklyReviews: standing.WeeklyReviews,
+			Accuracy:      successRate(standing.WeeklyCorrect, standing.WeeklyReviews),
+			Streak:        standing.Streak,
+			You:           standing.Learner == caller,
+		}
+	}
+	return board, nil
+}
+
+// UpdateMembership sets the name learner is shown under on a cohort's
+// leaderboard, a numbered placeholder when empty, and whether they are left off
+// it. Learners can only change their own membership.
+func (s *CohortService) UpdateMembership(ctx context.Context, learner string, id uint, displayName string, optOut bool) (*models.CohortMembership, error) {
+	ctx, span := tracer.Start(ctx, "CohortService.UpdateMembership")
+	defer span.End()
+
+	displayName = strings.TrimSpace(displayName)
+	if utf8.RuneCountInString(displayName) > models.MaxDisplayNameLength {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("display_name must be at most %d characters", models.MaxDisplayNameLength), nil)
+	}
+	cohort, err := s.cohortRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Cohort not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch cohort", err)
+	}
+	membership := findMember(cohort, learner)
+	if membership == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Cohort not found", nil)
+	}
+
+	membership.DisplayName = displayName
+	membership.LeaderboardOptOut = optOut
+	if err := s.cohortRepo.UpdateMember(ctx, membership); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Cohort not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to update cohort membership", err)
+	}
+	s.invalidateLeaderboard(id)
+	return membership, nil
+}
+
+// computeStandings aggregates the weekly study and streaks of the members of a
+// cohort who have not opted out of its leaderboard
+func (s *CohortService) computeStandings(ctx context.Context, cohort *models.Cohort) (*leaderboardStandings, error) {
+	now := time.Now().UTC()
+	since := now.Add(-leaderboardWeek)
+	weekly, err := s.cohortRepo.GetLearnerStats(ctx, cohort.ID, since)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get cohort statistics", err)
+	}
+	days, err := s.cohortRepo.GetLearnerStudyDays(ctx, cohort.ID, now.AddDate(0, 0, -maxLeaderboardStreakDays))
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get cohort study days", err)
+	}
+	byLearner := make(map[string]repository.LearnerStudyStats, len(weekly))
+	for _, row := range weekly {
+		byLearner[row.Learner] = row
+	}
+	daysByLearner := make(map[string][]string)
+	for _, day := range days {
+		daysByLearner[day.Learner] = append(daysByLearner[day.Learner], day.Day)
+	}
+
+	standings := &leaderboardStandings{Name: cohort.Name, Since: since, Standings: []leaderboardStanding{}}
+	for _, member := range cohort.Members {
+		if member.LeaderboardOptOut {
+			continue
+		}
+		name := member.DisplayName
+		if name == "" {
+			name = fmt.Sprintf("Learner %d", member.ID)
+		}
+		row := byLearner[member.Learner]
+		standings.Standings = append(standings.Standings, leaderboardStanding{
+			Learner:       member.Learner,
+			DisplayName:   name,
+			WeeklyReviews: row.Reviews,
+			WeeklyCorrect: row.CorrectReviews,
+			Streak:        studyStreak(daysByLearner[member.Learner], now),
+		})
+	}
+	return standings, nil
+}
+
+// compareStandings orders two members by metric, positive when a ranks above
+// b. Members without reviews this week rank below everyone on accuracy.
+func compareStandings(metric string, a, b leaderboardStanding) int {
+	switch metric {
+	case LeaderboardMetricAccuracy:
+		if (a.WeeklyReviews > 0) != (b.WeeklyReviews > 0) {
+			if a.WeeklyReviews > 0 {
+				return 1
+			}
+			return -1
+		}
+		return successRate(a.WeeklyCorrect, a.WeeklyReviews) - successRate(b.WeeklyCorrect, b.WeeklyReviews)
+	case LeaderboardMetricStreak:
+		return a.Streak - b.Streak
+	}
+	switch {
+	case a.WeeklyReviews > b.WeeklyReviews:
+		return 1
+	case a.WeeklyReviews < b.WeeklyReviews:
+		return -1
+	}
+	return 0
+}
+
+// studyStreak counts the consecutive UTC days, newest first as YYYY-MM-DD, that
+// end today or yesterday
+func studyStreak(days []string, now time.Time) int {
+	expected := now.Format("2006-01-02")
+	if len(days) > 0 && days[0] != expected {
+		expected = now.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	streak := 0
+	for _, day := range days {
+		if day != expected {
+			break
+		}
+		streak++
+		next, _ := time.Parse("2006-01-02", day)
+		expected = next.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	return streak
+}
+
+// findMember returns learner's membership of a cohort, or nil
+func findMember(cohort *models.Cohort, learner string) *models.CohortMembership {
+	for i := range cohort.Members {
+		if cohort.Members[i].Learner == learner {
+			return &cohort.Members[i]
+		}
+	}
+	return nil
+}
+
+// leaderboardCacheKey is the cache key of a cohort's leaderboard standings
+func leaderboardCacheKey(id uint) string {
+	return fmt.Sprintf("cohort:leaderboard:%d", id)
+}
+
+// invalidateLeaderboard drops a cohort's cached leaderboard after its members,
+// their privacy choices or its groups change
+func (s *CohortService) invalidateLeaderboard(id uint) {
+	if s.cache != nil {
+		s.cache.Delete(leaderboardCacheKey(id))
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func leaderboardCohort() *models.Cohort {
+	return &models.Cohort{
+		ID: 1, Name: "Class 1A", Owner: "teacher",
+		Members: []models.CohortMembership{
+			{ID: 10, Learner: "aiko", DisplayName: "Aiko"},
+			{ID: 11, Learner: "ken"},
+			{ID: 12, Learner: "mei", DisplayName: "Mei", LeaderboardOptOut: true},
+			{ID: 13, Learner: "yuki", DisplayName: "Yuki"},
+		},
+	}
+}
+
+func TestCohortService_GetLeaderboard(t *testing.T) {
+	today := time.Now().UTC()
+	day := func(offset int) string { return today.AddDate(0, 0, offset).Format("2006-01-02") }
+	cohortRepo := new(mockCohortRepository)
+	cohortRepo.On("GetByID", uint(1)).Return(leaderboardCohort(), nil)
+	cohortRepo.On("GetLearnerStats", uint(1), mock.Anything).Return([]repository.LearnerStudyStats{
+		{Learner: "aiko", Reviews: 20, CorrectReviews: 10},
+		{Learner: "mei", Reviews: 50, CorrectReviews: 50},
+		{Learner: "yuki", Reviews: 20, CorrectReviews: 18},
+	}, nil)
+	cohortRepo.On("GetLearnerStudyDays", uint(1), mock.Anything).Return([]repository.LearnerStudyDay{
+		{Learner: "aiko", Day: day(0)},
+		{Learner: "mei", Day: day(0)},
+		{Learner: "yuki", Day: day(-1)},
+		{Learner: "yuki", Day: day(-2)},
+	}, nil)
+	s := NewCohortService(NewBaseService(nil, nil, nil, nil, nil), cohortRepo)
+	ctx := context.Background()
+
+	board, err := s.GetLeaderboard(ctx, "yuki", 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, LeaderboardMetricReviews, board.Metric)
+	assert.Equal(t, []LeaderboardEntry{
+		{Rank: 1, DisplayName: "Aiko", WeeklyReviews: 20, Accuracy: 50, Streak: 1},
+		{Rank: 1, DisplayName: "Yuki", WeeklyReviews: 20, Accuracy: 90, Streak: 2, You: true},
+		{Rank: 3, DisplayName: "Learner 11"},
+	}, board.Entries, "mei opted out and ken is shown under a placeholder")
+
+	board, err = s.GetLeaderboard(ctx, "teacher", 1, LeaderboardMetricAccuracy)
+	require.NoError(t, err)
+	names := []string{}
+	for _, entry := range board.Entries {
+		names = append(names, entry.DisplayName)
+	}
+	assert.Equal(t, []string{"Yuki", "Aiko", "Learner 11"}, names, "members without reviews rank last")
+
+	board, err = s.GetLeaderboard(ctx, "ken", 1, LeaderboardMetricStreak)
+	require.NoError(t, err)
+	assert.Equal(t, "Yuki", board.Entries[0].DisplayName)
+	assert.True(t, board.Entries[2].You)
+}
+
+func TestCohortService_GetLeaderboard_Access(t *testing.T) {
+	cohortRepo := new(mockCohortRepository)
+	cohortRepo.On("GetByID", uint(1)).Return(leaderboardCohort(), nil)
+	s := NewCohortService(NewBaseService(nil, nil, nil, nil, nil), cohortRepo)
+	ctx := context.Background()
+
+	_, err := s.GetLeaderboard(ctx, "stranger", 1, "")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+
+	_, err = s.GetLeaderboard(ctx, "yuki", 1, "speed")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+	cohortRepo.AssertNotCalled(t, "GetLearnerStats", mock.Anything, mock.Anything)
+}
+
+func TestCohortService_GetLeaderboard_Cached(t *testing.T) {
+	cohortRepo := new(mockCohortRepository)
+	cohortRepo.On("GetByID", uint(1)).Return(leaderboardCohort(), nil)
+	cohortRepo.On("GetLearnerStats", uint(1), mock.Anything).Return([]repository.LearnerStudyStats{}, nil)
+	cohortRepo.On("GetLearnerStudyDays", uint(1), mock.Anything).Return([]repository.LearnerStudyDay{}, nil)
+	cohortRepo.On("UpdateMember", mock.Anything).Return(nil)
+	s := NewCohortService(NewBaseService(nil, nil, nil, nil, nil).WithCache(cache.NewMemory()), cohortRepo)
+	ctx := context.Background()
+
+	_, err := s.GetLeaderboard(ctx, "yuki", 1, LeaderboardMetricReviews)
+	require.NoError(t, err)
+	_, err = s.GetLeaderboard(ctx, "ken", 1, LeaderboardMetricStreak)
+	require.NoError(t, err)
+	cohortRepo.AssertNumberOfCalls(t, "GetLearnerStats", 1)
+
+	_, err = s.UpdateMembership(ctx, "yuki", 1, "Yuki", true)
+	require.NoError(t, err)
+	_, err = s.GetLeaderboard(ctx, "yuki", 1, LeaderboardMetricReviews)
+	require.NoError(t, err)
+	cohortRepo.AssertNumberOfCalls(t, "GetLearnerStats", 2)
+}
+
+func TestCohortService_UpdateMembership(t *testing.T) {
+	cohortRepo := new(mockCohortRepository)
+	cohortRepo.On("GetByID", uint(1)).Return(leaderboardCohort(), nil)
+	cohortRepo.On("UpdateMember", &models.CohortMembership{ID: 11, Learner: "ken", DisplayName: "Ken", LeaderboardOptOut: true}).Return(nil)
+	s := NewCohortService(NewBaseService(nil, nil, nil, nil, nil), cohortRepo)
+	ctx := context.Background()
+
+	membership, err := s.UpdateMembership(ctx, "ken", 1, " Ken ", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Ken", membership.DisplayName)
+
+	_, err = s.UpdateMembership(ctx, "teacher", 1, "Sensei", false)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code, "the teacher is not a member")
+
+	_, err = s.UpdateMembership(ctx, "ken", 1, strings.Repeat("あ", models.MaxDisplayNameLength+1), false)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
+
+func TestStudyStreak(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 0, studyStreak(nil, now))
+	assert.Equal(t, 3, studyStreak([]string{"2025-03-10", "2025-03-09", "2025-03-08", "2025-03-06"}, now))
+	assert.Equal(t, 2, studyStreak([]string{"2025-03-09", "2025-03-08"}, now), "a streak ending yesterday still counts")
+	assert.Equal(t, 0, studyStreak([]string{"2025-03-08", "2025-03-07"}, now))
+	assert.Equal(t, 2, studyStreak([]string{"2025-03-01", "2025-02-28"}, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
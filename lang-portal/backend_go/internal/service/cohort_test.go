@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCohortRepository is a mock implementation of CohortRepositoryInterface
+type mockCohortRepository struct {
+	mock.Mock
+}
+
+func (m *mockCohortRepository) Create(ctx context.Context, cohort *models.Cohort) error {
+	return m.Called(cohort).Error(0)
+}
+
+func (m *mockCohortRepository) GetByID(ctx context.Context, id uint) (*models.Cohort, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Cohort), args.Error(1)
+}
+
+func (m *mockCohortRepository) ListByOwner(ctx context.Context, owner string) ([]models.Cohort, error) {
+	args := m.Called(owner)
+	return args.Get(0).([]models.Cohort), args.Error(1)
+}
+
+func (m *mockCohortRepository) Delete(ctx context.Context, id uint) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockCohortRepository) AddMember(ctx context.Context, membership *models.CohortMembership) error {
+	return m.Called(membership).Error(0)
+}
+
+func (m *mockCohortRepository) RemoveMember(ctx context.Context, cohortID uint, learner string) error {
+	return m.Called(cohortID, learner).Error(0)
+}
+
+func (m *mockCohortRepository) AddGroup(ctx context.Context, cohortID, groupID uint) error {
+	return m.Called(cohortID, groupID).Error(0)
+}
+
+func (m *mockCohortRepository) RemoveGroup(ctx context.Context, cohortID, groupID uint) error {
+	return m.Called(cohortID, groupID).Error(0)
+}
+
+func (m *mockCohortRepository) UpdateMember(ctx context.Context, membership *models.CohortMembership) error {
+	return m.Called(membership).Error(0)
+}
+
+func (m *mockCohortRepository) GetLearnerStats(ctx context.Context, cohortID uint, since time.Time) ([]repository.LearnerStudyStats, error) {
+	args := m.Called(cohortID, since)
+	return args.Get(0).([]repository.LearnerStudyStats), args.Error(1)
+}
+
+func (m *mockCohortRepository) GetLearnerStudyDays(ctx context.Context, cohortID uint, since time.Time) ([]repository.LearnerStudyDay, error) {
+	args := m.Called(cohortID, since)
+	return args.Get(0).([]repository.LearnerStudyDay), args.Error(1)
+}
+
+func TestCohortService_GetCohortStats(t *testing.T) {
+	last := time.Date(2025, 3, 4, 9, 30, 0, 0, time.UTC)
+	cohortRepo := new(mockCohortRepository)
+	cohortRepo.On("GetByID", uint(1)).Return(&models.Cohort{
+		ID: 1, Name: "Class 1A", Owner: "teacher",
+		Members: []models.CohortMembership{{Learner: "ken"}, {Learner: "yuki"}},
+		Groups:  []models.CohortGroup{{CohortID: 1, GroupID: 4}},
+	}, nil)
+	cohortRepo.On("GetLearnerStats", uint(1), time.Time{}).Return([]repository.LearnerStudyStats{
+		{Learner: "yuki", Sessions: 3, Reviews: 20, CorrectReviews: 15, LastStudiedAt: last},
+	}, nil)
+	s := NewCohortService(NewBaseService(nil, nil, nil, nil, nil), cohortRepo)
+
+	stats, err := s.GetCohortStats(context.Background(), "teacher", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{4}, stats.GroupIDs)
+	assert.Equal(t, CohortTotals{Students: 2, ActiveStudents: 1, Sessions: 3, Reviews: 20, CorrectReviews: 15, SuccessRate: 75}, stats.Totals)
+	assert.Equal(t, []StudentStats{
+		{Learner: "ken"},
+		{Learner: "yuki", Sessions: 3, Reviews: 20, CorrectReviews: 15, SuccessRate: 75, LastStudiedAt: &last},
+	}, stats.Students)
+}
+
+func TestCohortService_OtherOwner(t *testing.T) {
+	cohortRepo := new(mockCohortRepository)
+	cohortRepo.On("GetByID", uint(1)).Return(&models.Cohort{ID: 1, Name: "Class 1A", Owner: "teacher"}, nil)
+	s := NewCohortService(NewBaseService(nil, nil, nil, nil, nil), cohortRepo)
+	ctx := context.Background()
+
+	for name, call := range map[string]func() error{
+		"get":    func() error { _, err := s.GetCohort(ctx, "yuki", 1); return err },
+		"stats":  func() error { _, err := s.GetCohortStats(ctx, "yuki", 1); return err },
+		"member": func() error { _, err := s.AddMember(ctx, "yuki", 1, "yuki"); return err },
+		"delete": func() error { return s.DeleteCohort(ctx, "yuki", 1) },
+	} {
+		err := call()
+		require.Error(t, err, name)
+		assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code, name)
+	}
+	cohortRepo.AssertNotCalled(t, "AddMember", mock.Anything)
+	cohortRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestCohortService_AddMember(t *testing.T) {
+	cohortRepo := new(mockCohortRepository)
+	cohortRepo.On("GetByID", uint(1)).Return(&models.Cohort{ID: 1, Name: "Class 1A", Owner: "teacher"}, nil)
+	cohortRepo.On("AddMember", &models.CohortMembership{CohortID: 1, Learner: "yuki"}).Return(nil).Once()
+	cohortRepo.On("AddMember", mock.Anything).Return(repository.ErrAlreadyExists)
+	s := NewCohortService(NewBaseService(nil, nil, nil, nil, nil), cohortRepo)
+	ctx := context.Background()
+
+	membership, err := s.AddMember(ctx, "teacher", 1, " yuki ")
+	require.NoError(t, err)
+	assert.Equal(t, "yuki", membership.Learner)
+
+	_, err = s.AddMember(ctx, "teacher", 1, "yuki")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeConflict, err.(*ServiceError).Code)
+
+	_, err = s.AddMember(ctx, "teacher", 1, "  ")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
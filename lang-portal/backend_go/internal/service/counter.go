@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// CounterActivityName is the study activity counter drills are recorded under.
+// It is created the first time a drill is generated.
+const CounterActivityName = "Counter Drill"
+
+// counterActivity is the study activity counter drills are recorded under
+var counterActivity = models.StudyActivity{
+	Name:         CounterActivityName,
+	Description:  "Read numbers with counter words, such as 三本 (さんぼん)",
+	ThumbnailURL: "/thumbnails/counter-drill.png",
+	Modes:        models.StringSlice{"typing"},
+}
+
+// Counter drill sizes
+const (
+	DefaultCounterQuestions = 10
+	MaxCounterQuestions     = 50
+	// DefaultCounterMaxNumber is the largest number drilled unless asked for
+	// larger ones
+	DefaultCounterMaxNumber = 10
+)
+
+// CounterService generates counter drills and records the answers to them.
+// Readings come from the rules in package jpn, not from the word list.
+type CounterService struct {
+	*BaseService
+	counterRepo repository.CounterRepositoryInterface
+}
+
+// NewCounterService creates a new counter drill service
+func NewCounterService(base *BaseService, counterRepo repository.CounterRepositoryInterface) *CounterService {
+	return &CounterService{BaseService: base, counterRepo: counterRepo}
+}
+
+// CounterSummary is a counter word with the answers given to its drills
+type CounterSummary struct {
+	jpn.Counter
+	Reviews int64 `json:"reviews"`
+	Correct int64 `json:"correct"`
+}
+
+// CounterQuestion asks for the reading of Text, a number with a counter in
+// kanji numerals. Numeral writes it in Arabic numerals; Answers are the
+// accepted readings in hiragana, the most common first.
+type CounterQuestion struct {
+	Counter string   `json:"counter"`
+	Number  int      `json:"number"`
+	Text    string   `json:"text"`
+	Numeral string   `json:"numeral"`
+	Usage   string   `json:"usage"`
+	Answers []string `json:"answers"`
+}
+
+// CounterDrill is a list of counter questions. Answers are recorded as counter
+// reviews under the counter drill study activity.
+type CounterDrill struct {
+	StudyActivityID uint              `json:"study_activity_id"`
+	Questions       []CounterQuestion `json:"questions"`
+}
+
+// CounterReviewResult is a recorded answer to a counter question, with the
+// readings it was checked against
+type CounterReviewResult struct {
+	Correct bool                  `json:"correct"`
+	Answers []string              `json:"answers"`
+	Review  *models.CounterReview `json:"review"`
+}
+
+// ListCounters returns the counters that can be drilled, in the order they are
+// usually learned, with the answers given to each
+func (s *CounterService) ListCounters(ctx context.Context) ([]CounterSummary, error) {
+	ctx, span := tracer.Start(ctx, "CounterService.ListCounters")
+	defer span.End()
+
+	stats, err := s.counterRepo.GetStats(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch counter statistics", err)
+	}
+	bySymbol := make(map[string]repository.CounterStats, len(stats))
+	for _, stat := range stats {
+		bySymbol[stat.Counter] = stat
+	}
+
+	counters := jpn.Counters()
+	summaries := make([]CounterSummary, len(counters))
+	for i, counter := range counters {
+		stat := bySymbol[counter.Symbol]
+		summaries[i] = CounterSummary{Counter: counter, Reviews: stat.Reviews, Correct: stat.Correct}
+	}
+	return summaries, nil
+}
+
+// GenerateCounterDrill builds up to limit questions, at most
+// MaxCounterQuestions, pairing the given counters, or all of them, with random
+// numbers from 1 to maxNumber. Numbers are capped at what each counter is used
+// with, so つ is never drilled past 10. No pair is asked twice.
+func (s *CounterService) GenerateCounterDrill(ctx context.Context, symbols []string, limit, maxNumber int) (*CounterDrill, error) {
+	ctx, span := tracer.Start(ctx, "CounterService.GenerateCounterDrill")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = DefaultCounterQuestions
+	}
+	if limit > MaxCounterQuestions {
+		limit = MaxCounterQuestions
+	}
+	if maxNumber <= 0 {
+		maxNumber = DefaultCounterMaxNumber
+	}
+	if maxNumber > jpn.MaxCounterNumber {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("max_number must be at most %d", jpn.MaxCounterNumber), nil)
+	}
+
+	counters := jpn.Counters()
+	if len(symbols) > 0 {
+		counters = counters[:0:0]
+		for _, symbol := range symbols {
+			counter, ok := jpn.FindCounter(symbol)
+			if !ok {
+				return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("Unknown counter %q", symbol), nil)
+			}
+			counters = append(counters, counter)
+		}
+	}
+
+	activity, err := s.builtinActivity(ctx, counterActivity)
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct {
+		counter jpn.Counter
+		number  int
+	}
+	var pairs []pair
+	seen := make(map[string]bool)
+	for _, counter := range counters {
+		if seen[counter.Symbol] {
+			continue
+		}
+		seen[counter.Symbol] = true
+		for number := 1; number <= maxNumber && number <= counter.Max; number++ {
+			pairs = append(pairs, pair{counter: counter, number: number})
+		}
+	}
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rnd.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	drill := &CounterDrill{StudyActivityID: activity.ID, Questions: make([]CounterQuestion, len(pairs))}
+	for i, p := range pairs {
+		drill.Questions[i] = CounterQuestion{
+			Counter: p.counter.Symbol,
+			Number:  p.number,
+			Text:    p.counter.Text(p.number),
+			Numeral: strconv.Itoa(p.number) + p.counter.Symbol,
+			Usage:   p.counter.Usage,
+			Answers: p.counter.Readings(p.number),
+		}
+	}
+	return drill, nil
+}
+
+// ReviewCounter checks an answer, in kana or romaji, to the reading of number
+// with a counter and records it
+func (s *CounterService) ReviewCounter(ctx context.Context, symbol string, number int, answer string, answerTimeMs *int) (*CounterReviewResult, error) {
+	ctx, span := tracer.Start(ctx, "CounterService.ReviewCounter")
+	defer span.End()
+
+	counter, ok := jpn.FindCounter(symbol)
+	if !ok {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("Unknown counter %q", symbol), nil)
+	}
+	readings := counter.Readings(number)
+	if readings == nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("number must be between 1 and %d for %s", counter.Max, counter.Symbol), nil)
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "answer is required", nil)
+	}
+	if !models.ValidAnswerTime(answerTimeMs) {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("answer_time_ms must be between 0 and %d", models.MaxAnswerTimeMs), nil)
+	}
+
+	review := &models.CounterReview{
+		Counter:      counter.Symbol,
+		Number:       number,
+		Answer:       answer,
+		Correct:      jpn.CheckCounterAnswer(answer, readings),
+		AnswerTimeMs: answerTimeMs,
+	}
+	if err := s.counterRepo.CreateReview(ctx, review); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to record counter review", err)
+	}
+	return &CounterReviewResult{Correct: review.Correct, Answers: readings, Review: review}, nil
+}
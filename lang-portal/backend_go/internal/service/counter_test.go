@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCounterRepository is a mock implementation of CounterRepositoryInterface
+type mockCounterRepository struct {
+	mock.Mock
+}
+
+func (m *mockCounterRepository) CreateReview(ctx context.Context, review *models.CounterReview) error {
+	return m.Called(review).Error(0)
+}
+
+func (m *mockCounterRepository) GetStats(ctx context.Context) ([]repository.CounterStats, error) {
+	args := m.Called()
+	return args.Get(0).([]repository.CounterStats), args.Error(1)
+}
+
+func TestCounterService_ListCounters(t *testing.T) {
+	counterRepo := new(mockCounterRepository)
+	counterRepo.On("GetStats").Return([]repository.CounterStats{{Counter: "本", Reviews: 4, Correct: 3}}, nil)
+	s := NewCounterService(NewBaseService(nil, nil, nil, nil, nil), counterRepo)
+
+	counters, err := s.ListCounters(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, counters)
+	assert.Equal(t, "つ", counters[0].Symbol)
+	for _, counter := range counters {
+		if counter.Symbol == "本" {
+			assert.Equal(t, int64(4), counter.Reviews)
+			assert.Equal(t, int64(3), counter.Correct)
+		} else {
+			assert.Zero(t, counter.Reviews)
+		}
+	}
+}
+
+func TestCounterService_ReviewCounter(t *testing.T) {
+	counterRepo := new(mockCounterRepository)
+	counterRepo.On("CreateReview", mock.Anything).Return(nil)
+	s := NewCounterService(NewBaseService(nil, nil, nil, nil, nil), counterRepo)
+	ctx := context.Background()
+
+	result, err := s.ReviewCounter(ctx, "本", 3, "sanbon", nil)
+	require.NoError(t, err)
+	assert.True(t, result.Correct, "romaji answers are accepted")
+	assert.Equal(t, []string{"さんぼん"}, result.Answers)
+	assert.Equal(t, &models.CounterReview{Counter: "本", Number: 3, Answer: "sanbon", Correct: true}, result.Review)
+
+	result, err = s.ReviewCounter(ctx, "本", 1, "いちほん", nil)
+	require.NoError(t, err)
+	assert.False(t, result.Correct)
+	assert.Equal(t, []string{"いっぽん"}, result.Answers)
+	counterRepo.AssertNumberOfCalls(t, "CreateReview", 2)
+
+	tooLong := models.MaxAnswerTimeMs + 1
+	for name, call := range map[string]func() error{
+		"unknown counter": func() error { _, err := s.ReviewCounter(ctx, "羽", 1, "いちわ", nil); return err },
+		"out of range":    func() error { _, err := s.ReviewCounter(ctx, "つ", 11, "じゅういち", nil); return err },
+		"empty answer":    func() error { _, err := s.ReviewCounter(ctx, "本", 2, " ", nil); return err },
+		"answer time":     func() error { _, err := s.ReviewCounter(ctx, "本", 2, "にほん", &tooLong); return err },
+	} {
+		err := call()
+		require.Error(t, err, name)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, name)
+	}
+	counterRepo.AssertNumberOfCalls(t, "CreateReview", 2)
+}
+
+func TestCounterService_GenerateCounterDrill_Invalid(t *testing.T) {
+	s := NewCounterService(NewBaseService(nil, nil, nil, nil, nil), new(mockCounterRepository))
+
+	_, err := s.GenerateCounterDrill(context.Background(), []string{"本", "羽"}, 10, 10)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+
+	_, err = s.GenerateCounterDrill(context.Background(), nil, 10, 100)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"lang-portal/backend_go/internal/repository"
+)
+
+// summaryInterval is how often the summary job checks for a day to summarize
+const summaryInterval = time.Hour
+
+// MaxSummaryDays caps the days one request for daily summaries covers
+const MaxSummaryDays = 366
+
+// defaultSummaryDays is how many days are returned when no range is given
+const defaultSummaryDays = 30
+
+// DailySummaryService serves the learners' precomputed daily summaries
+type DailySummaryService struct {
+	*BaseService
+	summaryRepo repository.DailySummaryRepositoryInterface
+}
+
+// NewDailySummaryService creates a daily summary service reading from summaryRepo
+func NewDailySummaryService(base *BaseService, summaryRepo repository.DailySummaryRepositoryInterface) *DailySummaryService {
+	return &DailySummaryService{BaseService: base, summaryRepo: summaryRepo}
+}
+
+// DailySummaryDay is a learner's study on one day
+type DailySummaryDay struct {
+	// Day is the UTC date as YYYY-MM-DD
+	Day            string `json:"day"`
+	Sessions       int64  `json:"sessions"`
+	Reviews        int64  `json:"reviews"`
+	CorrectReviews int64  `json:"correct_reviews"`
+	// Accuracy is the percentage of correct reviews, 0 without reviews
+	Accuracy float64 `json:"accuracy"`
+	NewWords int64   `json:"new_words"`
+	// ActiveMinutes counts the minutes with at least one review
+	ActiveMinutes int64 `json:"active_minutes"`
+}
+
+// DailySummaries is a learner's study on consecutive days, oldest first
+type DailySummaries struct {
+	From string            `json:"from"`
+	To   string            `json:"to"`
+	Days []DailySummaryDay `json:"days"`
+}
+
+// GetDailySummaries returns learner's study on each day from from to to, given
+// as YYYY-MM-DD and both included. Only completed days are summarized, so the
+// range ends yesterday at the latest. Without from and to it covers the last 30
+// completed days. Days without study are included with zero values.
+func (s *DailySummaryService) GetDailySummaries(ctx context.Context, learner, from, to string) (*DailySummaries, error) {
+	ctx, span := tracer.Start(ctx, "DailySummaryService.GetDailySummaries")
+	defer span.End()
+
+	now := time.Now().UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	end := yesterday
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInvalidInput, "to must be a date as YYYY-MM-DD", err)
+		}
+		end = parsed
+	}
+	start := end.AddDate(0, 0, 1-defaultSummaryDays)
+	if from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInvalidInput, "from must be a date as YYYY-MM-DD", err)
+		}
+		start = parsed
+	}
+	if start.After(end) {
+		return nil, NewServiceError(ErrCodeInvalidInput, "from must not be after to", nil)
+	}
+	if end.Sub(start) >= MaxSummaryDays*24*time.Hour {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("a range covers at most %d days", MaxSummaryDays), nil)
+	}
+	if end.After(yesterday) {
+		end = yesterday
+	}
+
+	result := &DailySummaries{From: start.Format("2006-01-02"), To: end.Format("2006-01-02"), Days: []DailySummaryDay{}}
+	if start.After(end) {
+		return result, nil
+	}
+	summaries, err := s.summaryRepo.List(ctx, learner, result.From, result.To)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get daily summaries", err)
+	}
+	next := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		point := DailySummaryDay{Day: day.Format("2006-01-02")}
+		if next < len(summaries) && summaries[next].Day == point.Day {
+			summary := summaries[next]
+			next++
+			point.Sessions, point.Reviews, point.CorrectReviews = summary.Sessions, summary.Reviews, summary.CorrectReviews
+			point.NewWords, point.ActiveMinutes = summary.NewWords, summary.ActiveMinutes
+			if summary.Reviews > 0 {
+				point.Accuracy = float64(summary.CorrectReviews) / float64(summary.Reviews) * 100
+			}
+		}
+		result.Days = append(result.Days, point)
+	}
+	return result, nil
+}
+
+// DailySummarizer is the nightly job precomputing the daily summaries. Each day
+// is summarized once it has ended.
+type DailySummarizer struct {
+	*BaseService
+	summaryRepo repository.DailySummaryRepositoryInterface
+	logger      *log.Logger
+	// summarizedDay is the day of the last successful run, as YYYY-MM-DD
+	summarizedDay string
+}
+
+// NewDailySummarizer creates a summary job writing to summaryRepo
+func NewDailySummarizer(base *BaseService, summaryRepo repository.DailySummaryRepositoryInterface, logger *log.Logger) *DailySummarizer {
+	return &DailySummarizer{BaseService: base, summaryRepo: summaryRepo, logger: logger}
+}
+
+// Run summarizes the days that have ended at start and then checks every hour
+// until ctx is done, so each day is summarized within an hour after midnight UTC
+func (d *DailySummarizer) Run(ctx context.Context) {
+	ticker := time.NewTicker(summaryInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Summarize(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			d.logger.Printf("Daily summaries: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Summarize computes the summaries of the days ended at now that were not
+// summarized yet and returns how many were stored. The last day already
+// summarized is computed again to pick up reviews recorded after it was. The
+// first run summarizes the whole review history.
+func (d *DailySummarizer) Summarize(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "DailySummarizer.Summarize")
+	defer span.End()
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if d.summarizedDay == today.Format("2006-01-02") {
+		return 0, nil
+	}
+
+	from, err := d.summaryRepo.LastDay(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the last summarized day: %w", err)
+	}
+	if from == "" {
+		if from, err = d.summaryRepo.FirstReviewDay(ctx); err != nil {
+			return 0, fmt.Errorf("failed to read the first review day: %w", err)
+		}
+	}
+	stored := 0
+	if from != "" {
+		start, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return 0, fmt.Errorf("invalid summary day %q: %w", from, err)
+		}
+		if stored, err = d.summaryRepo.Compute(ctx, start, today); err != nil {
+			return 0, fmt.Errorf("failed to compute daily summaries: %w", err)
+		}
+	}
+	d.summarizedDay = today.Format("2006-01-02")
+	return stored, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDailySummaryRepository records the ranges computed and lists the stored
+// summaries
+type fakeDailySummaryRepository struct {
+	summaries   []models.DailySummary
+	firstReview string
+	computed    [][2]time.Time
+}
+
+func (f *fakeDailySummaryRepository) Compute(ctx context.Context, from, to time.Time) (int, error) {
+	f.computed = append(f.computed, [2]time.Time{from, to})
+	day := to.AddDate(0, 0, -1).Format("2006-01-02")
+	f.summaries = append(f.summaries, models.DailySummary{Learner: "yuki", Day: day})
+	return 1, nil
+}
+
+func (f *fakeDailySummaryRepository) List(ctx context.Context, learner, from, to string) ([]models.DailySummary, error) {
+	var summaries []models.DailySummary
+	for _, summary := range f.summaries {
+		if summary.Learner == learner && summary.Day >= from && summary.Day <= to {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
+
+func (f *fakeDailySummaryRepository) LastDay(ctx context.Context) (string, error) {
+	last := ""
+	for _, summary := range f.summaries {
+		if summary.Day > last {
+			last = summary.Day
+		}
+	}
+	return last, nil
+}
+
+func (f *fakeDailySummaryRepository) FirstReviewDay(ctx context.Context) (string, error) {
+	return f.firstReview, nil
+}
+
+func TestDailySummarizer_Summarize(t *testing.T) {
+	repo := &fakeDailySummaryRepository{}
+	d := NewDailySummarizer(NewBaseService(nil, nil, nil, nil, nil), repo, nil)
+	ctx := context.Background()
+	now := time.Date(2025, 3, 12, 0, 30, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	stored, err := d.Summarize(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, stored, "nothing to summarize without reviews")
+
+	repo.firstReview = "2025-03-01"
+	stored, err = d.Summarize(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, stored, "each day is summarized once")
+
+	stored, err = d.Summarize(ctx, now.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+	require.Len(t, repo.computed, 1)
+	assert.Equal(t, [2]time.Time{time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), today.AddDate(0, 0, 1)}, repo.computed[0],
+		"the first run covers the whole history")
+
+	_, err = d.Summarize(ctx, now.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, repo.computed, 2)
+	assert.Equal(t, [2]time.Time{today, today.AddDate(0, 0, 2)}, repo.computed[1],
+		"later runs start with the last summarized day")
+}
+
+func TestDailySummaryService_GetDailySummaries(t *testing.T) {
+	now := time.Now().UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	day := func(offset int) string { return yesterday.AddDate(0, 0, offset).Format("2006-01-02") }
+	repo := &fakeDailySummaryRepository{summaries: []models.DailySummary{
+		{Learner: "yuki", Day: day(-2), Sessions: 1, Reviews: 4, CorrectReviews: 3, NewWords: 2, ActiveMinutes: 5},
+		{Learner: "ken", Day: day(-1), Sessions: 1, Reviews: 1},
+		{Learner: "yuki", Day: day(0), Sessions: 2, Reviews: 10, CorrectReviews: 10, ActiveMinutes: 8},
+	}}
+	s := NewDailySummaryService(NewBaseService(nil, nil, nil, nil, nil), repo)
+	ctx := context.Background()
+
+	summaries, err := s.GetDailySummaries(ctx, "yuki", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, day(-29), summaries.From)
+	assert.Equal(t, day(0), summaries.To)
+	require.Len(t, summaries.Days, 30)
+	assert.Equal(t, DailySummaryDay{Day: day(-2), Sessions: 1, Reviews: 4, CorrectReviews: 3, Accuracy: 75, NewWords: 2, ActiveMinutes: 5}, summaries.Days[27])
+	assert.Equal(t, DailySummaryDay{Day: day(-1)}, summaries.Days[28], "other learners' study is left out")
+	assert.Equal(t, float64(100), summaries.Days[29].Accuracy)
+
+	summaries, err = s.GetDailySummaries(ctx, "yuki", day(-1), day(5))
+	require.NoError(t, err)
+	assert.Equal(t, day(0), summaries.To, "today has not been summarized")
+	assert.Len(t, summaries.Days, 2)
+
+	summaries, err = s.GetDailySummaries(ctx, "yuki", day(2), day(5))
+	require.NoError(t, err)
+	assert.Empty(t, summaries.Days)
+
+	for _, input := range [][2]string{{"yesterday", ""}, {"", "2025-13-01"}, {day(0), day(-1)}, {day(-MaxSummaryDays), day(0)}} {
+		_, err := s.GetDailySummaries(ctx, "yuki", input[0], input[1])
+		require.Error(t, err, input)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, input)
+	}
+}
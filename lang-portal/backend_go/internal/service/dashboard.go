@@ -1,13 +1,30 @@
 package service
 
 import (
+	"context"
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/models"
 	"lang-portal/backend_go/internal/repository"
 	"time"
 )
 
-// DashboardService handles dashboard-related business logic
+// DashboardCacheTTL bounds how long cached dashboard aggregates are served. Writes
+// through the services invalidate them immediately; the TTL covers changes made
+// outside this process and the study streak rolling over at midnight.
+const DashboardCacheTTL = time.Minute
+
+// Cache keys for dashboard aggregates
+const (
+	cacheKeyStudyProgress = "dashboard:study_progress"
+	cacheKeyQuickStats    = "dashboard:quick_stats"
+)
+
+// DashboardService handles dashboard-related business logic. The aggregate
+// queries behind progress and quick stats are cached and shared between
+// concurrent callers.
 type DashboardService struct {
 	*BaseService
+	flights computeGroup
 }
 
 // NewDashboardService creates a new dashboard service
@@ -26,8 +43,11 @@ type LastStudySession struct {
 }
 
 // GetLastStudySession returns information about the most recent study session
-func (s *DashboardService) GetLastStudySession() (*LastStudySession, error) {
-	session, err := s.studyRepo.GetLastStudySession()
+func (s *DashboardService) GetLastStudySession(ctx context.Context) (*LastStudySession, error) {
+	ctx, span := tracer.Start(ctx, "DashboardService.GetLastStudySession")
+	defer span.End()
+
+	session, err := s.studyRepo.GetLastStudySession(ctx)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return &LastStudySession{}, nil
@@ -49,25 +69,47 @@ func (s *DashboardService) GetLastStudySession() (*LastStudySession, error) {
 type StudyProgress struct {
 	TotalWordsStudied   int64 `json:"total_words_studied"`
 	TotalAvailableWords int64 `json:"total_available_words"`
+	// WordStates counts the words in each progress state
+	WordStates map[string]int64 `json:"word_states"`
 }
 
 // GetStudyProgress returns study progress statistics
-func (s *DashboardService) GetStudyProgress() (*StudyProgress, error) {
+func (s *DashboardService) GetStudyProgress(ctx context.Context) (*StudyProgress, error) {
+	ctx, span := tracer.Start(ctx, "DashboardService.GetStudyProgress")
+	defer span.End()
+
+	// Concurrent callers share one computation, so it must not be cancelled
+	// when the caller that started it goes away
+	shared := context.WithoutCancel(ctx)
+	return computeOnce(&s.flights, "study_progress", func() (*StudyProgress, error) {
+		return cache.GetOrLoad(s.cache, cacheKeyStudyProgress, DashboardCacheTTL, func() (*StudyProgress, error) {
+			return s.computeStudyProgress(shared)
+		})
+	})
+}
+
+func (s *DashboardService) computeStudyProgress(ctx context.Context) (*StudyProgress, error) {
 	// Get total available words
-	totalWords, err := s.wordRepo.GetTotalWordCount()
+	totalWords, err := s.wordRepo.GetTotalWordCount(ctx)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to get total word count", err)
 	}
 
 	// Get total studied words
-	studiedWords, err := s.wordRepo.GetStudiedWordCount()
+	studiedWords, err := s.wordRepo.GetStudiedWordCount(ctx)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to get studied word count", err)
 	}
 
+	states, err := s.wordRepo.CountWordsByProgressState(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to count words by progress state", err)
+	}
+
 	return &StudyProgress{
 		TotalWordsStudied:   studiedWords,
 		TotalAvailableWords: totalWords,
+		WordStates:          states,
 	}, nil
 }
 
@@ -80,15 +122,27 @@ type QuickStats struct {
 }
 
 // GetQuickStats returns quick overview statistics
-func (s *DashboardService) GetQuickStats() (*QuickStats, error) {
+func (s *DashboardService) GetQuickStats(ctx context.Context) (*QuickStats, error) {
+	ctx, span := tracer.Start(ctx, "DashboardService.GetQuickStats")
+	defer span.End()
+
+	shared := context.WithoutCancel(ctx)
+	return computeOnce(&s.flights, "quick_stats", func() (*QuickStats, error) {
+		return cache.GetOrLoad(s.cache, cacheKeyQuickStats, DashboardCacheTTL, func() (*QuickStats, error) {
+			return s.computeQuickStats(shared)
+		})
+	})
+}
+
+func (s *DashboardService) computeQuickStats(ctx context.Context) (*QuickStats, error) {
 	// Get total study sessions
-	totalSessions, totalReviews, correctReviews, err := s.studyRepo.GetStudyStats()
+	totalSessions, totalReviews, correctReviews, err := s.studyRepo.GetStudyStats(ctx)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to get study statistics", err)
 	}
 
 	// Get total active groups
-	activeGroups, err := s.studyRepo.GetActiveGroups()
+	activeGroups, err := s.studyRepo.GetActiveGroups(ctx)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to get active groups count", err)
 	}
@@ -100,7 +154,7 @@ func (s *DashboardService) GetQuickStats() (*QuickStats, error) {
 	}
 
 	// Get study streak
-	streak, err := s.studyRepo.GetStudyStreak()
+	streak, err := s.studyRepo.GetStudyStreak(ctx)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to calculate study streak", err)
 	}
@@ -112,3 +166,166 @@ func (s *DashboardService) GetQuickStats() (*QuickStats, error) {
 		StudyStreakDays:    streak,
 	}, nil
 }
+
+// DeviceStats represents the study activity on one device
+type DeviceStats struct {
+	// DeviceLabel is empty for sessions started without a label
+	DeviceLabel    string    `json:"device_label"`
+	Sessions       int64     `json:"sessions"`
+	Reviews        int64     `json:"reviews"`
+	CorrectReviews int64     `json:"correct_reviews"`
+	SuccessRate    int       `json:"success_rate"`
+	LastStudiedAt  time.Time `json:"last_studied_at"`
+}
+
+// DeviceBreakdown represents study activity split by device
+type DeviceBreakdown struct {
+	Devices []DeviceStats `json:"devices"`
+	// MultiDevice reports whether sessions were started on more than one
+	// labelled device
+	MultiDevice bool `json:"multi_device"`
+}
+
+// GetDeviceStats returns study statistics per device label, most used first
+func (s *DashboardService) GetDeviceStats(ctx context.Context) (*DeviceBreakdown, error) {
+	ctx, span := tracer.Start(ctx, "DashboardService.GetDeviceStats")
+	defer span.End()
+
+	rows, err := s.studyRepo.GetDeviceStudyStats(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get device statistics", err)
+	}
+
+	breakdown := &DeviceBreakdown{Devices: make([]DeviceStats, 0, len(rows))}
+	labelled := 0
+	for _, row := range rows {
+		successRate := 0
+		if row.Reviews > 0 {
+			successRate = int((float64(row.CorrectReviews) / float64(row.Reviews)) * 100)
+		}
+		if row.DeviceLabel != "" {
+			labelled++
+		}
+		breakdown.Devices = append(breakdown.Devices, DeviceStats{
+			DeviceLabel:    row.DeviceLabel,
+			Sessions:       row.Sessions,
+			Reviews:        row.Reviews,
+			CorrectReviews: row.CorrectReviews,
+			SuccessRate:    successRate,
+			LastStudiedAt:  row.LastStudiedAt,
+		})
+	}
+	breakdown.MultiDevice = labelled > 1
+	return breakdown, nil
+}
+
+// PartStats represents the accuracy of the reviews of words tagged with one
+// part of speech
+type PartStats struct {
+	Part           string `json:"part"`
+	Words          int64  `json:"words"`
+	Reviews        int64  `json:"reviews"`
+	CorrectReviews int64  `json:"correct_reviews"`
+	// Accuracy is the percentage of correct reviews
+	Accuracy float64 `json:"accuracy"`
+}
+
+// PartBreakdown represents review accuracy split by part of speech
+type PartBreakdown struct {
+	Parts []PartStats `json:"parts"`
+}
+
+// GetPartStats returns review accuracy per part of speech, most reviewed first
+func (s *DashboardService) GetPartStats(ctx context.Context) (*PartBreakdown, error) {
+	ctx, span := tracer.Start(ctx, "DashboardService.GetPartStats")
+	defer span.End()
+
+	rows, err := s.studyRepo.GetPartStudyStats(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get part of speech statistics", err)
+	}
+
+	breakdown := &PartBreakdown{Parts: make([]PartStats, 0, len(rows))}
+	for _, row := range rows {
+		stats := PartStats{
+			Part:           row.Part,
+			Words:          row.Words,
+			Reviews:        row.Reviews,
+			CorrectReviews: row.CorrectReviews,
+		}
+		if row.Reviews > 0 {
+			stats.Accuracy = float64(row.CorrectReviews) / float64(row.Reviews) * 100
+		}
+		breakdown.Parts = append(breakdown.Parts, stats)
+	}
+	return breakdown, nil
+}
+
+// LanguageStats represents the vocabulary and study of one target language
+type LanguageStats struct {
+	Language       string `json:"language"`
+	Name           string `json:"name"`
+	Words          int64  `json:"words"`
+	StudiedWords   int64  `json:"studied_words"`
+	Groups         int64  `json:"groups"`
+	Sessions       int64  `json:"sessions"`
+	Reviews        int64  `json:"reviews"`
+	CorrectReviews int64  `json:"correct_reviews"`
+	SuccessRate    int    `json:"success_rate"`
+}
+
+// LanguageBreakdown represents vocabulary and study split by target language
+type LanguageBreakdown struct {
+	Languages []LanguageStats `json:"languages"`
+}
+
+// GetLanguageStats returns the vocabulary and study of every target language,
+// in the order of models.TargetLanguages; languages without words are listed
+// with zeros
+func (s *DashboardService) GetLanguageStats(ctx context.Context) (*LanguageBreakdown, error) {
+	ctx, span := tracer.Start(ctx, "DashboardService.GetLanguageStats")
+	defer span.End()
+
+	rows, err := s.studyRepo.GetLanguageStudyStats(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get language statistics", err)
+	}
+	byLanguage := make(map[string]repository.LanguageStudyStats, len(rows))
+	for _, row := range rows {
+		byLanguage[row.Language] = row
+	}
+
+	breakdown := &LanguageBreakdown{Languages: make([]LanguageStats, 0, len(models.TargetLanguages))}
+	for _, language := range models.TargetLanguages {
+		row := byLanguage[language.Code]
+		stats := LanguageStats{
+			Language:       language.Code,
+			Name:           language.Name,
+			Words:          row.Words,
+			StudiedWords:   row.StudiedWords,
+			Groups:         row.Groups,
+			Sessions:       row.Sessions,
+			Reviews:        row.Reviews,
+			CorrectReviews: row.CorrectReviews,
+		}
+		if row.Reviews > 0 {
+			stats.SuccessRate = int((float64(row.CorrectReviews) / float64(row.Reviews)) * 100)
+		}
+		breakdown.Languages = append(breakdown.Languages, stats)
+	}
+	return breakdown, nil
+}
+
+// ComputeStats reports how many dashboard computations ran and how many
+// callers shared a concurrent result
+func (s *DashboardService) ComputeStats() ComputeStats {
+	return s.flights.stats()
+}
+
+// invalidateDashboard drops cached dashboard aggregates after words, sessions or
+// reviews change
+func (s *BaseService) invalidateDashboard() {
+	if s.cache != nil {
+		s.cache.Delete(cacheKeyStudyProgress, cacheKeyQuickStats)
+	}
+}
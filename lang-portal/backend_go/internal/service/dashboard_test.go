@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardService_GetStudyProgress_Cached(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	base := NewBaseService(mockRepo, nil, nil, nil, nil).WithCache(cache.NewMemory())
+	dashboardService := NewDashboardService(base)
+	wordService := NewWordService(base)
+
+	mockRepo.On("GetTotalWordCount").Return(int64(10), nil).Once()
+	mockRepo.On("GetStudiedWordCount").Return(int64(4), nil).Once()
+	mockRepo.On("CountWordsByProgressState").Return(map[string]int64{models.ProgressNew: 6, models.ProgressLearning: 4}, nil).Once()
+
+	first, err := dashboardService.GetStudyProgress(context.Background())
+	require.NoError(t, err)
+	second, err := dashboardService.GetStudyProgress(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	mockRepo.AssertExpectations(t)
+
+	// Creating a word invalidates the cached progress
+	mockRepo.On("Create", mock.AnythingOfType("*models.Word")).Return(nil)
+	require.NoError(t, wordService.CreateWord(context.Background(), &models.Word{Term: "犬", Reading: "inu", English: "dog"}))
+
+	mockRepo.On("GetTotalWordCount").Return(int64(11), nil).Once()
+	mockRepo.On("GetStudiedWordCount").Return(int64(4), nil).Once()
+	mockRepo.On("CountWordsByProgressState").Return(map[string]int64{models.ProgressNew: 7, models.ProgressLearning: 4}, nil).Once()
+
+	third, err := dashboardService.GetStudyProgress(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), third.TotalAvailableWords)
+	assert.Equal(t, int64(7), third.WordStates[models.ProgressNew])
+	mockRepo.AssertExpectations(t)
+}
+
+// mockPartStatsRepository implements the part of speech statistics of StudyRepositoryInterface
+type mockPartStatsRepository struct {
+	repository.StudyRepositoryInterface
+	mock.Mock
+}
+
+func (m *mockPartStatsRepository) GetPartStudyStats(ctx context.Context) ([]repository.PartStudyStats, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.PartStudyStats), args.Error(1)
+}
+
+func TestDashboardService_GetPartStats(t *testing.T) {
+	studyRepo := new(mockPartStatsRepository)
+	studyRepo.On("GetPartStudyStats").Return([]repository.PartStudyStats{
+		{Part: "verb", Words: 2, Reviews: 4, CorrectReviews: 3},
+		{Part: "noun", Words: 1, Reviews: 2, CorrectReviews: 2},
+	}, nil)
+	dashboardService := NewDashboardService(NewBaseService(nil, nil, studyRepo, nil, nil))
+
+	breakdown, err := dashboardService.GetPartStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []PartStats{
+		{Part: "verb", Words: 2, Reviews: 4, CorrectReviews: 3, Accuracy: 75},
+		{Part: "noun", Words: 1, Reviews: 2, CorrectReviews: 2, Accuracy: 100},
+	}, breakdown.Parts)
+}
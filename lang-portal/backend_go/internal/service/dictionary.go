@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/dictionary"
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/ocr"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// dictionaryCacheTTL is how long dictionary lookups are cached
+const dictionaryCacheTTL = time.Hour
+
+// DictionaryService looks words up in a dictionary and adds entries to the
+// word list
+type DictionaryService struct {
+	*BaseService
+	provider dictionary.Provider
+	reader   ocr.Reader
+}
+
+// NewDictionaryService creates a new dictionary service
+func NewDictionaryService(base *BaseService, provider dictionary.Provider) *DictionaryService {
+	return &DictionaryService{BaseService: base, provider: provider}
+}
+
+// Lookup returns up to limit dictionary entries matching query, best match first
+func (s *DictionaryService) Lookup(ctx context.Context, query string, limit int) ([]dictionary.Entry, error) {
+	ctx, span := tracer.Start(ctx, "DictionaryService.Lookup")
+	defer span.End()
+
+	entries, err := s.lookup(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// AddWord creates a word from the dictionary entry entryID found by query,
+// using the entry's first form and the given sense
+func (s *DictionaryService) AddWord(ctx context.Context, query, entryID string, sense int) (*models.Word, error) {
+	ctx, span := tracer.Start(ctx, "DictionaryService.AddWord")
+	defer span.End()
+
+	entry, err := s.findEntry(ctx, query, entryID)
+	if err != nil {
+		return nil, err
+	}
+	word, err := entry.Word(sense)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Invalid dictionary sense", err).WithDetails(ErrorReason{Reason: err.Error()})
+	}
+	if _, err := s.wordRepo.GetByTerm(ctx, word.Term); err == nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Word already exists: "+word.Term, nil)
+	} else if err != repository.ErrNotFound {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to check existing words", err)
+	}
+
+	if err := s.wordRepo.Create(ctx, word); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create word", err)
+	}
+	s.invalidateDashboard()
+	s.publish(events.TypeWordCreated, map[string]uint{"id": word.ID})
+	return word, nil
+}
+
+// findEntry returns the dictionary entry entryID among those found by query
+func (s *DictionaryService) findEntry(ctx context.Context, query, entryID string) (*dictionary.Entry, error) {
+	entries, err := s.lookup(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].ID == entryID {
+			return &entries[i], nil
+		}
+	}
+	return nil, NewServiceError(ErrCodeNotFound, "Dictionary entry not found", nil)
+}
+
+func (s *DictionaryService) lookup(ctx context.Context, query string) ([]dictionary.Entry, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Query is required", nil)
+	}
+	if s.provider == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Dictionary lookup is not available", nil)
+	}
+
+	entries, err := cache.GetOrLoad(s.cache, "dictionary:lookup:"+query, dictionaryCacheTTL, func() ([]dictionary.Entry, error) {
+		return s.provider.Lookup(ctx, query)
+	})
+	if err != nil {
+		if errors.Is(err, dictionary.ErrUnavailable) {
+			return nil, NewServiceError(ErrCodeInternal, "Dictionary is unavailable", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to look up word", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/dictionary"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// staticDictionary answers every lookup with the same entries
+type staticDictionary []dictionary.Entry
+
+func (d staticDictionary) Lookup(ctx context.Context, query string) ([]dictionary.Entry, error) {
+	return d, nil
+}
+
+var testEntries = staticDictionary{
+	{
+		ID:     "1358280",
+		Forms:  []dictionary.Form{{Written: "食べる", Reading: "たべる"}},
+		Senses: []dictionary.Sense{{Glosses: []string{"to eat"}, PartsOfSpeech: []string{"Ichidan verb"}}},
+	},
+	{
+		ID:     "1358300",
+		Forms:  []dictionary.Form{{Written: "食べ物", Reading: "たべもの"}},
+		Senses: []dictionary.Sense{{Glosses: []string{"food"}, PartsOfSpeech: []string{"Noun"}}},
+	},
+}
+
+func TestDictionaryService_Lookup(t *testing.T) {
+	s := NewDictionaryService(NewBaseService(nil, nil, nil, nil, nil), testEntries)
+
+	entries, err := s.Lookup(context.Background(), "taberu", 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "1358280", entries[0].ID)
+
+	_, err = s.Lookup(context.Background(), "  ", 10)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
+
+func TestDictionaryService_AddWord(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	s := NewDictionaryService(NewBaseService(mockRepo, nil, nil, nil, nil), testEntries)
+
+	mockRepo.On("GetByTerm", "食べ物").Return(nil, repository.ErrNotFound)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Word")).Return(nil)
+
+	word, err := s.AddWord(context.Background(), "tabe", "1358300", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "食べ物", word.Term)
+	assert.Equal(t, "tabemono", word.Reading)
+	assert.Equal(t, "food", word.English)
+	assert.Equal(t, models.StringSlice{"noun"}, word.Parts)
+	mockRepo.AssertExpectations(t)
+
+	mockRepo.On("GetByTerm", "食べる").Return(&models.Word{ID: 1, Term: "食べる"}, nil)
+	_, err = s.AddWord(context.Background(), "tabe", "1358280", 0)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+
+	_, err = s.AddWord(context.Background(), "tabe", "missing", 0)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+
+	_, err = s.AddWord(context.Background(), "tabe", "1358280", 3)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
+)
+
+// MaxPollWait bounds how long a long-poll request waits for new events. It stays
+// below the server's write timeout so that an idle poll still gets its response.
+const MaxPollWait = 10 * time.Second
+
+// pollBatchSize caps the events returned by a single poll
+const pollBatchSize = 100
+
+// EventService serves buffered change events to clients that cannot hold a
+// realtime connection open
+type EventService struct {
+	*BaseService
+}
+
+// NewEventService creates a new event service
+func NewEventService(base *BaseService) *EventService {
+	return &EventService{BaseService: base}
+}
+
+// PollEvents returns the events published after cursor, waiting up to wait for
+// one to arrive. A zero or excessive wait uses MaxPollWait. An empty cursor
+// returns the current cursor straight away so new clients start from now.
+func (s *EventService) PollEvents(ctx context.Context, cursor string, wait time.Duration) (*events.Batch, error) {
+	ctx, span := tracer.Start(ctx, "EventService.PollEvents")
+	defer span.End()
+
+	if s.events == nil {
+		return nil, NewServiceError(ErrCodeInternal, "Events are not enabled", nil)
+	}
+	if wait <= 0 || wait > MaxPollWait {
+		wait = MaxPollWait
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	batch, err := s.events.Wait(ctx, cursor, pollBatchSize)
+	if errors.Is(err, events.ErrInvalidCursor) {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Invalid cursor", err)
+	}
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to read events", err)
+	}
+	return batch, nil
+}
+
+// publish notifies event clients of a change; it is a no-op without a hub
+func (s *BaseService) publish(eventType string, data interface{}) {
+	if s.events != nil {
+		s.events.Publish(eventType, data)
+	}
+}
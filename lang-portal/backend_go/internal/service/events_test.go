@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventService_PollEvents_ReturnsPublishedChanges(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	hub := events.NewHub(10)
+	base := NewBaseService(mockRepo, nil, nil, nil, nil).WithEvents(hub)
+	wordService := NewWordService(base)
+	eventService := NewEventService(base)
+
+	start, err := eventService.PollEvents(context.Background(), "", time.Second)
+	require.NoError(t, err)
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Word")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Word).ID = 7
+	}).Return(nil)
+	require.NoError(t, wordService.CreateWord(context.Background(), &models.Word{Term: "犬"}))
+
+	batch, err := eventService.PollEvents(context.Background(), start.Cursor, time.Second)
+	require.NoError(t, err)
+	require.Len(t, batch.Events, 1)
+	assert.Equal(t, events.TypeWordCreated, batch.Events[0].Type)
+	assert.Equal(t, map[string]uint{"id": 7}, batch.Events[0].Data)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEventService_PollEvents_InvalidCursor(t *testing.T) {
+	eventService := NewEventService(NewBaseService(nil, nil, nil, nil, nil).WithEvents(events.NewHub(10)))
+
+	_, err := eventService.PollEvents(context.Background(), "not-a-cursor", time.Second)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeInvalidInput, serviceErr.Code)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"sync/atomic"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ComputeStats reports how often expensive computations actually ran versus
+// how often callers received a result computed for a concurrent caller
+type ComputeStats struct {
+	Computed int64 `json:"computed"`
+	Shared   int64 `json:"shared"`
+}
+
+// computeGroup deduplicates concurrent computations of the same key, so a burst
+// of identical requests runs the underlying queries once
+type computeGroup struct {
+	group    singleflight.Group
+	computed atomic.Int64
+	shared   atomic.Int64
+}
+
+// stats returns a snapshot of the group's counters
+func (g *computeGroup) stats() ComputeStats {
+	return ComputeStats{
+		Computed: g.computed.Load(),
+		Shared:   g.shared.Load(),
+	}
+}
+
+// computeOnce runs fn for key unless a computation for key is already in flight,
+// in which case it waits for and returns that computation's result. Results may
+// be handed to several callers and must not be mutated.
+func computeOnce[T any](g *computeGroup, key string, fn func() (T, error)) (T, error) {
+	v, err, shared := g.group.Do(key, func() (interface{}, error) {
+		g.computed.Add(1)
+		return fn()
+	})
+	if shared {
+		g.shared.Add(1)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
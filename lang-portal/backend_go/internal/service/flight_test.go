@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeOnce_SharesConcurrentCalls(t *testing.T) {
+	var g computeGroup
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]int, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := computeOnce(&g, "key", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			assert.NoError(t, err)
+			results[i] = v
+		}(i)
+	}
+
+	// Give every caller time to join the in-flight computation
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, v := range results {
+		assert.Equal(t, 42, v)
+	}
+	stats := g.stats()
+	assert.Equal(t, int64(1), stats.Computed)
+	assert.Equal(t, int64(callers), stats.Shared)
+
+	// Later calls compute again
+	v, err := computeOnce(&g, "key", func() (int, error) { return 7, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 7, v)
+	assert.Equal(t, int64(2), g.stats().Computed)
+}
+
+func TestComputeOnce_ReturnsError(t *testing.T) {
+	var g computeGroup
+	expected := errors.New("query failed")
+
+	v, err := computeOnce(&g, "key", func() (*QuickStats, error) { return nil, expected })
+
+	assert.Nil(t, v)
+	assert.Equal(t, expected, err)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Review forecast lengths, in days
+const (
+	DefaultForecastDays = 30
+	MaxForecastDays     = 365
+)
+
+// ForecastDay is the number of reviews coming due on one day
+type ForecastDay struct {
+	// Date is the UTC date, as YYYY-MM-DD
+	Date string `json:"date"`
+	Due  int64  `json:"due"`
+}
+
+// ReviewForecast is the review load of the coming days, starting today
+type ReviewForecast struct {
+	Days int `json:"days"`
+	// Overdue counts the words that became due before today; they are included
+	// in today's count
+	Overdue int64         `json:"overdue"`
+	Total   int64         `json:"total"`
+	Points  []ForecastDay `json:"points"`
+}
+
+// GetReviewForecast returns how many word reviews come due on each of the next
+// days days, starting today in UTC. Days without reviews are included with zero.
+func (s *StudyService) GetReviewForecast(ctx context.Context, days int) (*ReviewForecast, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetReviewForecast")
+	defer span.End()
+
+	if days < 1 || days > MaxForecastDays {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("days must be between 1 and %d", MaxForecastDays), nil)
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due, err := s.wordRepo.CountDueByDay(ctx, today.AddDate(0, 0, days))
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get review forecast", err)
+	}
+
+	forecast := &ReviewForecast{Days: days, Points: make([]ForecastDay, days)}
+	index := make(map[string]int, days)
+	for i := range forecast.Points {
+		date := today.AddDate(0, 0, i).Format("2006-01-02")
+		forecast.Points[i].Date = date
+		index[date] = i
+	}
+	for _, day := range due {
+		i, ok := index[day.Day]
+		if !ok {
+			// Nothing due after the range is read, so other days are past
+			forecast.Overdue += day.Words
+			i = 0
+		}
+		forecast.Points[i].Due += day.Words
+		forecast.Total += day.Words
+	}
+	return forecast, nil
+}
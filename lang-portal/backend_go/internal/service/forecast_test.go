@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudyService_GetReviewForecast(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	studyService := NewStudyService(NewBaseService(mockRepo, nil, nil, nil, nil))
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := func(n int) string { return today.AddDate(0, 0, n).Format("2006-01-02") }
+	mockRepo.On("CountDueByDay", mock.MatchedBy(func(until time.Time) bool {
+		return until.Equal(today.AddDate(0, 0, 3))
+	})).Return([]repository.DueDay{
+		{Day: day(-4), Words: 2},
+		{Day: day(-1), Words: 1},
+		{Day: day(0), Words: 4},
+		{Day: day(2), Words: 5},
+	}, nil)
+
+	forecast, err := studyService.GetReviewForecast(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, &ReviewForecast{
+		Days:    3,
+		Overdue: 3,
+		Total:   12,
+		Points: []ForecastDay{
+			{Date: day(0), Due: 7},
+			{Date: day(1), Due: 0},
+			{Date: day(2), Due: 5},
+		},
+	}, forecast)
+	mockRepo.AssertExpectations(t)
+
+	for _, days := range []int{0, MaxForecastDays + 1} {
+		_, err := studyService.GetReviewForecast(context.Background(), days)
+		require.Error(t, err)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/moderation"
+)
+
+// Learner levels, which the AI features pitch their language at
+const (
+	LevelBeginner     = "beginner"
+	LevelIntermediate = "intermediate"
+	LevelAdvanced     = "advanced"
+)
+
+// Words in review or mastered a learner needs to reach each level
+const (
+	intermediateWords = 100
+	advancedWords     = 500
+)
+
+// Reply length caps of the generated content, in tokens
+const (
+	sentenceReplyTokens    = 200
+	explanationReplyTokens = 400
+)
+
+// maxExplanationLength caps an explanation that passes moderation, in characters
+const maxExplanationLength = 2000
+
+// sentenceFormat tells the model how to lay out a generated sentence, whatever
+// the sentence generation prompt says
+const sentenceFormat = "Reply with exactly two lines and nothing else: the Japanese sentence, then its English translation."
+
+// GeneratedSentence is an example sentence written by the language model. It
+// is not stored; it can be added to the word as an example sentence.
+type GeneratedSentence struct {
+	WordID   uint   `json:"word_id"`
+	Level    string `json:"level"`
+	Japanese string `json:"japanese"`
+	English  string `json:"english"`
+}
+
+// WordExplanation is an explanation of a word written by the language model
+type WordExplanation struct {
+	WordID      uint   `json:"word_id"`
+	Level       string `json:"level"`
+	Explanation string `json:"explanation"`
+}
+
+// WithLLM has client write the AI features' content: tutor replies, example
+// sentences and word explanations. Without one, those features are not
+// available.
+func (s *BaseService) WithLLM(client llm.Client) *BaseService {
+	s.llm = client
+	return s
+}
+
+// ValidLevel reports whether level is a learner level
+func ValidLevel(level string) bool {
+	switch level {
+	case LevelBeginner, LevelIntermediate, LevelAdvanced:
+		return true
+	}
+	return false
+}
+
+// GenerateExampleSentence asks the language model for a new example sentence
+// for a word, pitched at level or, when level is empty, at the learner's level.
+// The sentence must contain the word and pass moderation, or another is asked
+// for. It counts towards client's AI quota.
+func (s *SentenceService) GenerateExampleSentence(ctx context.Context, client string, wordID uint, level string) (*GeneratedSentence, error) {
+	ctx, span := tracer.Start(ctx, "SentenceService.GenerateExampleSentence")
+	defer span.End()
+
+	if s.llm == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Sentence generation is not available", nil)
+	}
+	word, err := s.getWord(ctx, wordID)
+	if err != nil {
+		return nil, err
+	}
+	if level, err = s.resolveLevel(ctx, level); err != nil {
+		return nil, err
+	}
+
+	prompt, err := s.renderPrompt(ctx, PromptSentenceGeneration, wordPromptValues(word, level))
+	if err != nil {
+		return nil, err
+	}
+	var japanese, english string
+	_, err = s.complete(ctx, client, PromptSentenceGeneration, llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: sentenceFormat},
+			{Role: llm.RoleUser, Content: prompt},
+		},
+		MaxTokens: sentenceReplyTokens,
+	}, func(reply string) error {
+		japanese, english = splitSentenceReply(reply)
+		return checkSentence(word, japanese, english)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GeneratedSentence{WordID: word.ID, Level: level, Japanese: japanese, English: english}, nil
+}
+
+// ExplainWord asks the language model to explain how a word is used, pitched
+// at level or, when level is empty, at the learner's level. It counts towards
+// client's AI quota.
+func (s *WordService) ExplainWord(ctx context.Context, client string, id uint, level string) (*WordExplanation, error) {
+	ctx, span := tracer.Start(ctx, "WordService.ExplainWord")
+	defer span.End()
+
+	if s.llm == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Word explanations are not available", nil)
+	}
+	word, err := s.getWord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if level, err = s.resolveLevel(ctx, level); err != nil {
+		return nil, err
+	}
+
+	prompt, err := s.renderPrompt(ctx, PromptWordExplanation, wordPromptValues(word, level))
+	if err != nil {
+		return nil, err
+	}
+	reply, err := s.complete(ctx, client, PromptWordExplanation, llm.Request{
+		Messages:  []llm.Message{{Role: llm.RoleUser, Content: prompt}},
+		MaxTokens: explanationReplyTokens,
+	}, func(reply string) error {
+		return moderation.Check(reply, moderation.Policy{Language: moderation.Mixed, MaxLength: maxExplanationLength})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &WordExplanation{WordID: word.ID, Level: level, Explanation: strings.TrimSpace(reply)}, nil
+}
+
+// complete sends a request of feature to the language model for client and
+// returns the first reply check accepts
+func (s *BaseService) complete(ctx context.Context, client, feature string, req llm.Request, check func(reply string) error) (string, error) {
+	response, err := s.generate(ctx, client, feature, req, nil, check)
+	if err != nil {
+		return "", err
+	}
+	return response.Content, nil
+}
+
+// resolveLevel checks a requested level, defaulting to the learner's level
+func (s *BaseService) resolveLevel(ctx context.Context, level string) (string, error) {
+	if level == "" {
+		return s.learnerLevel(ctx)
+	}
+	if !ValidLevel(level) {
+		return "", NewServiceError(ErrCodeInvalidInput, "level must be beginner, intermediate or advanced", nil)
+	}
+	return level, nil
+}
+
+// learnerLevel estimates the learner's level from the number of words they
+// have learned, those in review or mastered
+func (s *BaseService) learnerLevel(ctx context.Context) (string, error) {
+	states, err := s.wordRepo.CountWordsByProgressState(ctx)
+	if err != nil {
+		return "", NewServiceError(ErrCodeInternal, "Failed to count learned words", err)
+	}
+	switch learned := states[models.ProgressReview] + states[models.ProgressMastered]; {
+	case learned >= advancedWords:
+		return LevelAdvanced, nil
+	case learned >= intermediateWords:
+		return LevelIntermediate, nil
+	}
+	return LevelBeginner, nil
+}
+
+// wordPromptValues fills in the variables of the prompts about a word
+func wordPromptValues(word *models.Word, level string) map[string]string {
+	return map[string]string{
+		"word":    word.Term,
+		"reading": word.Reading,
+		"meaning": word.English,
+		"level":   level,
+	}
+}
+
+// checkSentence checks a generated sentence can be used as an example of word:
+// that it contains the word and both it and its translation pass moderation
+func checkSentence(word *models.Word, japanese, english string) error {
+	sentence := &models.ExampleSentence{WordID: word.ID, Japanese: japanese, English: english}
+	if err := sentence.Validate(); err != nil {
+		return err
+	}
+	if _, _, ok := jpn.BlankWord(japanese, jpn.WordForms(word.Term), jpn.ClozeBlank); !ok {
+		return errors.New("the sentence does not contain the word")
+	}
+	if err := moderation.Check(japanese, moderation.Policy{Language: moderation.Japanese}); err != nil {
+		return err
+	}
+	return moderation.Check(english, moderation.Policy{Language: moderation.English})
+}
+
+// splitSentenceReply takes the sentence and its translation from the first two
+// non-empty lines of a reply
+func splitSentenceReply(reply string) (japanese, english string) {
+	var lines []string
+	for _, line := range strings.Split(reply, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > 0 {
+		japanese = lines[0]
+	}
+	if len(lines) > 1 {
+		english = lines[1]
+	}
+	return japanese, english
+}
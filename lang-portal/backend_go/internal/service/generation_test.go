@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseService_LearnerLevel(t *testing.T) {
+	for learned, level := range map[int64]string{0: LevelBeginner, 99: LevelBeginner, 100: LevelIntermediate, 500: LevelAdvanced} {
+		mockRepo := new(mockWordRepository)
+		mockRepo.On("CountWordsByProgressState").Return(map[string]int64{
+			models.ProgressReview:   learned / 2,
+			models.ProgressMastered: learned - learned/2,
+			models.ProgressLearning: 1000,
+		}, nil)
+		got, err := NewBaseService(mockRepo, nil, nil, nil, nil).learnerLevel(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, level, got, learned)
+	}
+}
+
+func TestSentenceService_GenerateExampleSentence(t *testing.T) {
+	word := &models.Word{ID: 1, Term: "食べる", Reading: "taberu", English: "to eat"}
+	mockRepo := new(mockWordRepository)
+	mockRepo.On("GetByID", uint(1)).Return(word, nil)
+	base := NewBaseService(mockRepo, nil, nil, nil, nil)
+	s := NewSentenceService(base, nil)
+	ctx := context.Background()
+
+	_, err := s.GenerateExampleSentence(ctx, "alice", 1, "")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code, "generation needs a language model")
+
+	client := &scriptedLLM{pieces: []string{"毎朝パンを食べます。\n\n", "I eat bread every morning.\n"}}
+	base.WithLLM(client)
+	sentence, err := s.GenerateExampleSentence(ctx, "alice", 1, LevelAdvanced)
+	require.NoError(t, err)
+	assert.Equal(t, &GeneratedSentence{WordID: 1, Level: LevelAdvanced, Japanese: "毎朝パンを食べます。", English: "I eat bread every morning."}, sentence)
+	require.Len(t, client.request.Messages, 2)
+	assert.Equal(t, llm.RoleSystem, client.request.Messages[0].Role)
+	assert.Contains(t, client.request.Messages[1].Content, `食べる (taberu, "to eat") that advanced learners`)
+
+	client.pieces = []string{"毎朝パンを買います。\nI buy bread every morning."}
+	_, err = s.GenerateExampleSentence(ctx, "alice", 1, LevelAdvanced)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInternal, err.(*ServiceError).Code, "sentences must use the word")
+
+	_, err = s.GenerateExampleSentence(ctx, "alice", 1, "expert")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
+
+func TestWordService_ExplainWord(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	mockRepo.On("GetByID", uint(1)).Return(&models.Word{ID: 1, Term: "食べる", Reading: "taberu", English: "to eat"}, nil)
+	mockRepo.On("CountWordsByProgressState").Return(map[string]int64{}, nil)
+	client := &scriptedLLM{pieces: []string{"  食べる (taberu) is the everyday verb for eating.  "}}
+	s := NewWordService(NewBaseService(mockRepo, nil, nil, nil, nil).WithLLM(client))
+
+	explanation, err := s.ExplainWord(context.Background(), "alice", 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, &WordExplanation{WordID: 1, Level: LevelBeginner, Explanation: "食べる (taberu) is the everyday verb for eating."}, explanation)
+	assert.Contains(t, client.request.Messages[0].Content, "to beginner learners")
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// certificateTokenBytes is the amount of randomness in a certificate ID
+const certificateTokenBytes = 16
+
+// GoalService manages group goals and the certificates issued when a group's
+// goal is attained
+type GoalService struct {
+	*BaseService
+	goalRepo repository.GoalRepositoryInterface
+
+	// mu serializes goal evaluation so a certificate is issued only once
+	mu  sync.Mutex
+	key storedKey
+	now func() time.Time
+}
+
+// NewGoalService creates a new goal service
+func NewGoalService(base *BaseService, goalRepo repository.GoalRepositoryInterface) *GoalService {
+	return &GoalService{
+		BaseService: base,
+		goalRepo:    goalRepo,
+		key:         storedKey{setting: models.SettingCertificateSigningKey},
+		now:         time.Now,
+	}
+}
+
+// WithSigningKey signs certificates with key. Without one, a key is generated
+// on first use and kept in the settings table, or in memory when there is no
+// settings repository, in which case certificates stop verifying after a restart.
+func (s *GoalService) WithSigningKey(key []byte) *GoalService {
+	s.key.set(key)
+	return s
+}
+
+// GroupGoalStatus reports progress towards a group's goal
+type GroupGoalStatus struct {
+	Goal *models.GroupGoal `json:"goal"`
+	// SessionsCounted is the number of recent sessions with reviews, at most Goal.Sessions
+	SessionsCounted int `json:"sessions_counted"`
+	// Accuracy is the share of correct reviews over the counted sessions, between 0 and 1
+	Accuracy float64 `json:"accuracy"`
+	Attained bool    `json:"attained"`
+	// Certificate is the one issued since the goal was last changed, if attained
+	Certificate *models.GroupCertificate `json:"certificate,omitempty"`
+}
+
+// SetGroupGoal creates or replaces the goal of a group
+func (s *GoalService) SetGroupGoal(ctx context.Context, groupID uint, minAccuracy float64, sessions int) (*models.GroupGoal, error) {
+	ctx, span := tracer.Start(ctx, "GoalService.SetGroupGoal")
+	defer span.End()
+
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	goal := &models.GroupGoal{GroupID: groupID, MinAccuracy: minAccuracy, Sessions: sessions}
+	if err := goal.Validate(); err != nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "min_accuracy must be in (0, 1] and sessions between 1 and 100", err)
+	}
+	if err := s.goalRepo.SetGoal(ctx, goal); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to save goal", err)
+	}
+
+	saved, err := s.goalRepo.GetGoal(ctx, groupID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch goal", err)
+	}
+	return saved, nil
+}
+
+// DeleteGroupGoal removes the goal of a group. Certificates already issued
+// remain valid.
+func (s *GoalService) DeleteGroupGoal(ctx context.Context, groupID uint, actor string) error {
+	ctx, span := tracer.Start(ctx, "GoalService.DeleteGroupGoal")
+	defer span.End()
+
+	goal, err := s.goalRepo.GetGoal(ctx, groupID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Goal not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to fetch goal", err)
+	}
+	if err := s.goalRepo.DeleteGoal(ctx, groupID); err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to delete goal", err)
+	}
+	return s.recordAudit(ctx, actor, models.AuditActionDelete, models.AuditEntityGroupGoal, &groupID, goal, nil)
+}
+
+// GetGroupGoalStatus evaluates a group's goal against its most recent sessions.
+// The first time the goal is found attained, a certificate is issued.
+func (s *GoalService) GetGroupGoalStatus(ctx context.Context, groupID uint) (*GroupGoalStatus, error) {
+	ctx, span := tracer.Start(ctx, "GoalService.GetGroupGoalStatus")
+	defer span.End()
+
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	goal, err := s.goalRepo.GetGoal(ctx, groupID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Goal not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch goal", err)
+	}
+
+	sessions, err := s.goalRepo.GetRecentSessionAccuracy(ctx, groupID, goal.Sessions)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get session accuracy", err)
+	}
+	status := &GroupGoalStatus{Goal: goal, SessionsCounted: len(sessions)}
+	status.Accuracy, status.Attained = evaluateGoal(goal, sessions)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest, err := s.goalRepo.GetLatestCertificate(ctx, groupID)
+	if err != nil && err != repository.ErrNotFound {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch certificate", err)
+	}
+	if latest != nil && !latest.AchievedAt.Before(goal.UpdatedAt.Truncate(time.Second)) {
+		status.Certificate = latest
+	}
+	if status.Attained && status.Certificate == nil {
+		certificate, err := s.issueCertificate(ctx, group, goal, status.Accuracy)
+		if err != nil {
+			return nil, err
+		}
+		status.Certificate = certificate
+	}
+	return status, nil
+}
+
+// evaluateGoal measures the accuracy over a group's most recent sessions with
+// reviews, newest first, and whether it attains the group's goal
+func evaluateGoal(goal *models.GroupGoal, sessions []repository.SessionAccuracy) (accuracy float64, attained bool) {
+	var total, correct int64
+	for _, session := range sessions {
+		total += session.TotalReviews
+		correct += session.CorrectReviews
+	}
+	if total > 0 {
+		accuracy = math.Round(float64(correct)/float64(total)*10000) / 10000
+	}
+	return accuracy, len(sessions) == goal.Sessions && accuracy >= goal.MinAccuracy
+}
+
+// GetGroupCertificate returns the most recent certificate of a group, issuing
+// one first if the current goal has just been attained
+func (s *GoalService) GetGroupCertificate(ctx context.Context, groupID uint) (*models.GroupCertificate, error) {
+	ctx, span := tracer.Start(ctx, "GoalService.GetGroupCertificate")
+	defer span.End()
+
+	if _, err := s.GetGroupGoalStatus(ctx, groupID); err != nil {
+		if serviceErr, ok := err.(*ServiceError); !ok || serviceErr.Code != ErrCodeNotFound {
+			return nil, err
+		}
+	}
+
+	certificate, err := s.goalRepo.GetLatestCertificate(ctx, groupID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "No certificate has been issued for this group", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch certificate", err)
+	}
+	return certificate, nil
+}
+
+// VerifyCertificate looks up a certificate by its ID and checks its signature
+func (s *GoalService) VerifyCertificate(ctx context.Context, token string) (*models.GroupCertificate, bool, error) {
+	ctx, span := tracer.Start(ctx, "GoalService.VerifyCertificate")
+	defer span.End()
+
+	certificate, err := s.goalRepo.GetCertificateByToken(ctx, token)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, false, NewServiceError(ErrCodeNotFound, "Certificate not found", err)
+		}
+		return nil, false, NewServiceError(ErrCodeInternal, "Failed to fetch certificate", err)
+	}
+
+	key, err := s.signingKey(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	expected := signCertificate(key, certificate)
+	return certificate, hmac.Equal([]byte(expected), []byte(certificate.Signature)), nil
+}
+
+func (s *GoalService) getGroup(ctx context.Context, groupID uint) (*models.Group, error) {
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+	return group, nil
+}
+
+func (s *GoalService) issueCertificate(ctx context.Context, group *models.Group, goal *models.GroupGoal, accuracy float64) (*models.GroupCertificate, error) {
+	buf := make([]byte, certificateTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to generate certificate ID", err)
+	}
+	key, err := s.signingKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate := &models.GroupCertificate{
+		Token:       hex.EncodeToString(buf),
+		GroupID:     group.ID,
+		GroupName:   group.Name,
+		MinAccuracy: goal.MinAccuracy,
+		Sessions:    goal.Sessions,
+		Accuracy:    accuracy,
+		// Whole seconds, so the signed timestamp survives a round trip through the database
+		AchievedAt: s.now().UTC().Truncate(time.Second),
+	}
+	certificate.Signature = signCertificate(key, certificate)
+	if err := s.goalRepo.CreateCertificate(ctx, certificate); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to store certificate", err)
+	}
+	return certificate, nil
+}
+
+// signingKey returns the key signing certificates
+func (s *GoalService) signingKey(ctx context.Context) ([]byte, error) {
+	return s.key.get(ctx, s.settingRepo)
+}
+
+// signCertificate computes the HMAC-SHA256 of the certificate's fields
+func signCertificate(key []byte, c *models.GroupCertificate) string {
+	payload := strings.Join([]string{
+		c.Token,
+		strconv.FormatUint(uint64(c.GroupID), 10),
+		c.GroupName,
+		strconv.FormatFloat(c.MinAccuracy, 'f', -1, 64),
+		strconv.Itoa(c.Sessions),
+		strconv.FormatFloat(c.Accuracy, 'f', -1, 64),
+		c.AchievedAt.UTC().Format(time.RFC3339),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCertificate() *models.GroupCertificate {
+	return &models.GroupCertificate{
+		Token:       "0123456789abcdef",
+		GroupID:     3,
+		GroupName:   "Core Verbs (基本)",
+		MinAccuracy: 0.9,
+		Sessions:    3,
+		Accuracy:    0.9412,
+		AchievedAt:  time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestSignCertificate(t *testing.T) {
+	key := []byte("secret")
+	certificate := testCertificate()
+	signature := signCertificate(key, certificate)
+
+	assert.Equal(t, signature, signCertificate(key, testCertificate()))
+	assert.NotEqual(t, signature, signCertificate([]byte("other"), certificate))
+
+	// Any change to a signed field invalidates the signature
+	tampered := testCertificate()
+	tampered.Accuracy = 0.99
+	assert.NotEqual(t, signature, signCertificate(key, tampered))
+
+	// The signature does not depend on the time zone the timestamp was read in
+	local := testCertificate()
+	local.AchievedAt = local.AchievedAt.In(time.FixedZone("JST", 9*3600))
+	assert.Equal(t, signature, signCertificate(key, local))
+}
+
+func TestRenderCertificatePDF(t *testing.T) {
+	certificate := testCertificate()
+	certificate.Signature = signCertificate([]byte("secret"), certificate)
+
+	pdf := RenderCertificatePDF(certificate)
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), `(Group: Core Verbs \(??\)) Tj`)
+	assert.Contains(t, string(pdf), "(Goal: 90% accuracy over the last 3 sessions) Tj")
+	assert.Contains(t, string(pdf), "(Achieved: 94.12% accuracy on 2025-03-01) Tj")
+	assert.Contains(t, string(pdf), certificate.Signature)
+}
@@ -1,6 +1,10 @@
 package service
 
 import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
 	"lang-portal/backend_go/internal/models"
 	"lang-portal/backend_go/internal/repository"
 )
@@ -19,14 +23,25 @@ func NewGroupService(base *BaseService) *GroupService {
 type Group struct {
 	ID        uint   `json:"id"`
 	Name      string `json:"name"`
+	Language  string `json:"language"`
 	WordCount int    `json:"word_count"`
+	// Mastery is the percentage of the group's words that are mastered, as
+	// defined by the mastery preferences; only set in group lists
+	Mastery   float64   `json:"mastery"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // GroupDetail represents detailed group information
 type GroupDetail struct {
-	ID        uint   `json:"id"`
-	Name      string `json:"name"`
-	WordCount int    `json:"word_count"`
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Language  string    `json:"language"`
+	WordCount int       `json:"word_count"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Version is sent back with updates to detect concurrent changes
+	Version uint `json:"version"`
+	// ShareSlug is set while the group is published as a shared deck
+	ShareSlug *string `json:"share_slug,omitempty"`
 }
 
 // GroupWordRaw represents a simplified word in a group (for raw endpoint)
@@ -37,10 +52,41 @@ type GroupWordRaw struct {
 	English  string `json:"english"`
 }
 
-// CreateGroup creates a new group
-func (s *GroupService) CreateGroup(group *models.Group) error {
+// GroupWordStats is the review record of one word in a group
+type GroupWordStats struct {
+	WordID       uint       `json:"word_id"`
+	Japanese     string     `json:"japanese"`
+	Romaji       string     `json:"romaji"`
+	English      string     `json:"english"`
+	Correct      int64      `json:"correct"`
+	Wrong        int64      `json:"wrong"`
+	LastReviewed *time.Time `json:"last_reviewed"`
+	// SuccessRate is the percentage of correct reviews, 0 for unreviewed words
+	SuccessRate float64 `json:"success_rate"`
+}
+
+// GroupListOptions selects the order and the groups of a group list
+type GroupListOptions struct {
+	// Sort is by name, created_at, word_count or mastered_count; the default
+	// order is by ID
+	Sort SortParams
+	// Language keeps only the groups of this target language when not empty
+	Language string
+}
+
+// CreateGroup creates a new group, in the default language unless it names one
+func (s *GroupService) CreateGroup(ctx context.Context, group *models.Group) error {
+	ctx, span := tracer.Start(ctx, "GroupService.CreateGroup")
+	defer span.End()
+
+	language, err := targetLanguage(group.Language)
+	if err != nil {
+		return err
+	}
+	group.Language = language
+
 	// Check if group with same name exists
-	existing, err := s.groupRepo.GetByName(group.Name)
+	existing, err := s.groupRepo.GetByName(ctx, group.Name, group.Language)
 	if err != nil && err != repository.ErrNotFound {
 		return NewServiceError(ErrCodeInternal, "Failed to check for existing group", err)
 	}
@@ -48,15 +94,18 @@ func (s *GroupService) CreateGroup(group *models.Group) error {
 		return NewServiceError(ErrCodeInvalidInput, "A group with this name already exists", nil)
 	}
 
-	if err := s.groupRepo.Create(group); err != nil {
+	if err := s.groupRepo.Create(ctx, group); err != nil {
 		return NewServiceError(ErrCodeInternal, "Failed to create group", err)
 	}
 	return nil
 }
 
 // GetGroup retrieves a group by ID
-func (s *GroupService) GetGroup(id uint) (*GroupDetail, error) {
-	group, err := s.groupRepo.GetByID(id)
+func (s *GroupService) GetGroup(ctx context.Context, id uint) (*GroupDetail, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.GetGroup")
+	defer span.End()
+
+	group, err := s.groupRepo.GetSummary(ctx, id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
@@ -67,15 +116,42 @@ func (s *GroupService) GetGroup(id uint) (*GroupDetail, error) {
 	return &GroupDetail{
 		ID:        group.ID,
 		Name:      group.Name,
-		WordCount: len(group.Words),
+		Language:  group.Language,
+		WordCount: int(group.WordCount),
+		UpdatedAt: group.UpdatedAt,
+		Version:   group.Version,
+		ShareSlug: group.ShareSlug,
 	}, nil
 }
 
-// ListGroups retrieves a paginated list of groups
-func (s *GroupService) ListGroups(params PaginationParams) (*PaginatedResult[Group], error) {
-	result, err := s.groupRepo.List(repository.PaginationParams{
+// ListGroups retrieves a paginated list of groups, filtered and ordered by opts
+func (s *GroupService) ListGroups(ctx context.Context, params PaginationParams, opts GroupListOptions) (*PaginatedResult[Group], error) {
+	ctx, span := tracer.Start(ctx, "GroupService.ListGroups")
+	defer span.End()
+
+	repoSort, err := opts.Sort.toRepository(repository.ValidGroupSortField)
+	if err != nil {
+		return nil, err
+	}
+	var language string
+	if opts.Language != "" {
+		if language, err = targetLanguage(opts.Language); err != nil {
+			return nil, err
+		}
+	}
+	minReviews, minRate, err := s.masteryThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.groupRepo.List(ctx, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
+	}, repository.GroupListOptions{
+		Sort:                   repoSort,
+		Language:               language,
+		MasteredMinReviews:     minReviews,
+		MasteredMinSuccessRate: minRate,
 	})
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to list groups", err)
@@ -87,27 +163,43 @@ func (s *GroupService) ListGroups(params PaginationParams) (*PaginatedResult[Gro
 		groups[i] = Group{
 			ID:        g.ID,
 			Name:      g.Name,
-			WordCount: len(g.Words),
+			Language:  g.Language,
+			WordCount: int(g.WordCount),
+			UpdatedAt: g.UpdatedAt,
+		}
+		if g.WordCount > 0 {
+			groups[i].Mastery = float64(g.MasteredCount) / float64(g.WordCount) * 100
 		}
 	}
 
 	return NewPaginatedResult(groups, result.TotalItems, params.Page, params.PageSize), nil
 }
 
-// UpdateGroup updates an existing group
-func (s *GroupService) UpdateGroup(id uint, group *models.Group) error {
+// UpdateGroup updates an existing group. If group.Version is set, the update is
+// rejected with a conflict when the group has been updated since that version.
+// The language of a group cannot be changed; group.Language may be left empty.
+func (s *GroupService) UpdateGroup(ctx context.Context, id uint, group *models.Group) error {
+	ctx, span := tracer.Start(ctx, "GroupService.UpdateGroup")
+	defer span.End()
+
 	// Verify group exists
-	existing, err := s.groupRepo.GetByID(id)
+	existing, err := s.groupRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Group not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
 	}
+	if err := staleVersion("group", group.Version, existing.Version); err != nil {
+		return err
+	}
+	if group.Language != "" && group.Language != existing.Language {
+		return NewServiceError(ErrCodeInvalidInput, "The language of a group cannot be changed", nil)
+	}
 
 	// Check if new name conflicts with existing group
 	if existing.Name != group.Name {
-		conflicting, err := s.groupRepo.GetByName(group.Name)
+		conflicting, err := s.groupRepo.GetByName(ctx, group.Name, existing.Language)
 		if err != nil && err != repository.ErrNotFound {
 			return NewServiceError(ErrCodeInternal, "Failed to check for existing group", err)
 		}
@@ -119,27 +211,93 @@ func (s *GroupService) UpdateGroup(id uint, group *models.Group) error {
 	// Update fields
 	existing.Name = group.Name
 
-	if err := s.groupRepo.Update(existing); err != nil {
+	if err := s.groupRepo.Update(ctx, existing); err != nil {
+		if err == repository.ErrConflict {
+			return conflictingUpdate("group", err)
+		}
 		return NewServiceError(ErrCodeInternal, "Failed to update group", err)
 	}
 	return nil
 }
 
-// DeleteGroup deletes a group
-func (s *GroupService) DeleteGroup(id uint) error {
-	if err := s.groupRepo.Delete(id); err != nil {
+// GroupPatch is a partial update of a group. Nil fields are left unchanged.
+// Version, if set, is the version of the group the patch was made against.
+type GroupPatch struct {
+	Name    *string `json:"name"`
+	Version uint    `json:"version"`
+}
+
+// PatchGroup updates the fields set in patch and returns the updated group
+func (s *GroupService) PatchGroup(ctx context.Context, id uint, patch *GroupPatch) (*GroupDetail, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.PatchGroup")
+	defer span.End()
+
+	existing, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+	if err := staleVersion("group", patch.Version, existing.Version); err != nil {
+		return nil, err
+	}
+
+	if patch.Name != nil && *patch.Name != existing.Name {
+		conflicting, err := s.groupRepo.GetByName(ctx, *patch.Name, existing.Language)
+		if err != nil && err != repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to check for existing group", err)
+		}
+		if conflicting != nil {
+			return nil, NewServiceError(ErrCodeInvalidInput, "A group with this name already exists", nil)
+		}
+		existing.Name = *patch.Name
+	}
+
+	if err := s.groupRepo.Update(ctx, existing); err != nil {
+		if err == repository.ErrInvalidInput {
+			return nil, NewServiceError(ErrCodeInvalidInput, "name must not be empty", err)
+		}
+		if err == repository.ErrConflict {
+			return nil, conflictingUpdate("group", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to update group", err)
+	}
+	return s.GetGroup(ctx, id)
+}
+
+// DeleteGroup deletes a group and records the deleted group in the audit log
+func (s *GroupService) DeleteGroup(ctx context.Context, id uint, actor string) error {
+	ctx, span := tracer.Start(ctx, "GroupService.DeleteGroup")
+	defer span.End()
+
+	existing, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+
+	if err := s.groupRepo.Delete(ctx, id); err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Group not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to delete group", err)
 	}
-	return nil
+	s.invalidateDashboard()
+	s.publish(events.TypeGroupDeleted, map[string]uint{"id": id})
+	return s.recordAudit(ctx, actor, models.AuditActionDelete, models.AuditEntityGroup, &id, existing, nil)
 }
 
-// AddWordToGroup adds a word to a group
-func (s *GroupService) AddWordToGroup(groupID, wordID uint) error {
+// AddWordToGroup adds a word to a group of the word's language
+func (s *GroupService) AddWordToGroup(ctx context.Context, groupID, wordID uint) error {
+	ctx, span := tracer.Start(ctx, "GroupService.AddWordToGroup")
+	defer span.End()
+
 	// Verify group exists
-	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Group not found", err)
 		}
@@ -147,23 +305,30 @@ func (s *GroupService) AddWordToGroup(groupID, wordID uint) error {
 	}
 
 	// Verify word exists
-	if _, err := s.wordRepo.GetByID(wordID); err != nil {
+	word, err := s.wordRepo.GetByID(ctx, wordID)
+	if err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Word not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
 	}
+	if word.Language != group.Language {
+		return NewServiceError(ErrCodeInvalidInput, "The word is not in the language of the group", nil)
+	}
 
-	if err := s.groupRepo.AddWord(groupID, wordID); err != nil {
+	if err := s.groupRepo.AddWord(ctx, groupID, wordID); err != nil {
 		return NewServiceError(ErrCodeInternal, "Failed to add word to group", err)
 	}
 	return nil
 }
 
-// RemoveWordFromGroup removes a word from a group
-func (s *GroupService) RemoveWordFromGroup(groupID, wordID uint) error {
+// RemoveWordFromGroup removes a word from a group and records the removal in the audit log
+func (s *GroupService) RemoveWordFromGroup(ctx context.Context, groupID, wordID uint, actor string) error {
+	ctx, span := tracer.Start(ctx, "GroupService.RemoveWordFromGroup")
+	defer span.End()
+
 	// Verify group exists
-	if _, err := s.groupRepo.GetByID(groupID); err != nil {
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Group not found", err)
 		}
@@ -171,22 +336,26 @@ func (s *GroupService) RemoveWordFromGroup(groupID, wordID uint) error {
 	}
 
 	// Verify word exists
-	if _, err := s.wordRepo.GetByID(wordID); err != nil {
+	if _, err := s.wordRepo.GetByID(ctx, wordID); err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Word not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
 	}
 
-	if err := s.groupRepo.RemoveWord(groupID, wordID); err != nil {
+	if err := s.groupRepo.RemoveWord(ctx, groupID, wordID); err != nil {
 		return NewServiceError(ErrCodeInternal, "Failed to remove word from group", err)
 	}
-	return nil
+	return s.recordAudit(ctx, actor, models.AuditActionRemove, models.AuditEntityWordGroup, &groupID,
+		map[string]uint{"group_id": groupID, "word_id": wordID}, nil)
 }
 
 // GetGroupStudyStats retrieves study statistics for a group
-func (s *GroupService) GetGroupStudyStats(id uint) (totalSessions, totalReviews, correctReviews int, err error) {
-	totalSessions, totalReviews, correctReviews, err = s.groupRepo.GetStudyStats(id)
+func (s *GroupService) GetGroupStudyStats(ctx context.Context, id uint) (totalSessions, totalReviews, correctReviews int, err error) {
+	ctx, span := tracer.Start(ctx, "GroupService.GetGroupStudyStats")
+	defer span.End()
+
+	totalSessions, totalReviews, correctReviews, err = s.groupRepo.GetStudyStats(ctx, id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return 0, 0, 0, NewServiceError(ErrCodeNotFound, "Group not found", err)
@@ -196,9 +365,48 @@ func (s *GroupService) GetGroupStudyStats(id uint) (totalSessions, totalReviews,
 	return
 }
 
+// GetGroupWordStats retrieves the review record of every word in a group, for
+// showing per-word progress without fetching each word
+func (s *GroupService) GetGroupWordStats(ctx context.Context, id uint) ([]GroupWordStats, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.GetGroupWordStats")
+	defer span.End()
+
+	if _, err := s.groupRepo.GetByID(ctx, id); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+
+	rows, err := s.groupRepo.GetWordStats(ctx, id)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get word statistics", err)
+	}
+
+	stats := make([]GroupWordStats, len(rows))
+	for i, row := range rows {
+		stats[i] = GroupWordStats{
+			WordID:       row.WordID,
+			Japanese:     row.Term,
+			Romaji:       row.Reading,
+			English:      row.English,
+			Correct:      row.CorrectCount,
+			Wrong:        row.WrongCount,
+			LastReviewed: row.LastReviewedAt,
+		}
+		if total := row.CorrectCount + row.WrongCount; total > 0 {
+			stats[i].SuccessRate = float64(row.CorrectCount) / float64(total) * 100
+		}
+	}
+	return stats, nil
+}
+
 // GetGroupsByWord retrieves groups containing a specific word
-func (s *GroupService) GetGroupsByWord(wordID uint, params PaginationParams) (*PaginatedResult[Group], error) {
-	result, err := s.groupRepo.GetGroupsByWord(wordID, repository.PaginationParams{
+func (s *GroupService) GetGroupsByWord(ctx context.Context, wordID uint, params PaginationParams) (*PaginatedResult[Group], error) {
+	ctx, span := tracer.Start(ctx, "GroupService.GetGroupsByWord")
+	defer span.End()
+
+	result, err := s.groupRepo.GetGroupsByWord(ctx, wordID, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
 	})
@@ -209,11 +417,12 @@ func (s *GroupService) GetGroupsByWord(wordID uint, params PaginationParams) (*P
 	// Transform groups
 	groups := make([]Group, len(result.Items))
 	for i, g := range result.Items {
-		wordCount := int64(len(g.Words)) // Calculate word count from the Words relationship
 		groups[i] = Group{
 			ID:        g.ID,
 			Name:      g.Name,
-			WordCount: int(wordCount),
+			Language:  g.Language,
+			WordCount: int(g.WordCount),
+			UpdatedAt: g.UpdatedAt,
 		}
 	}
 
@@ -221,8 +430,11 @@ func (s *GroupService) GetGroupsByWord(wordID uint, params PaginationParams) (*P
 }
 
 // GetWordsRaw retrieves a simplified list of words in a group (id, japanese, romaji, english only)
-func (s *GroupService) GetWordsRaw(groupID uint) ([]GroupWordRaw, error) {
-	words, err := s.wordRepo.GetWordsByGroupRaw(groupID)
+func (s *GroupService) GetWordsRaw(ctx context.Context, groupID uint) ([]GroupWordRaw, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.GetWordsRaw")
+	defer span.End()
+
+	words, err := s.wordRepo.GetWordsByGroupRaw(ctx, groupID)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to get group words", err)
 	}
@@ -232,8 +444,8 @@ func (s *GroupService) GetWordsRaw(groupID uint) ([]GroupWordRaw, error) {
 	for i, w := range words {
 		rawWords[i] = GroupWordRaw{
 			ID:       w.ID,
-			Japanese: w.Japanese,
-			Romaji:   w.Romaji,
+			Japanese: w.Term,
+			Romaji:   w.Reading,
 			English:  w.English,
 		}
 	}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// shareSlugBytes is the amount of randomness in a generated share slug
+const shareSlugBytes = 6
+
+// maxCopySuffix bounds the numbers tried when the name of a shared group is
+// taken: Animals (2) up to Animals (99)
+const maxCopySuffix = 99
+
+// shareSlugPattern is the form of share slugs chosen by the publisher
+var shareSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Length bounds of share slugs chosen by the publisher
+const (
+	minShareSlugLength = 3
+	maxShareSlugLength = 64
+)
+
+// SharedWord is a word of a shared group
+type SharedWord struct {
+	Japanese string `json:"japanese"`
+	Romaji   string `json:"romaji"`
+	English  string `json:"english"`
+}
+
+// SharedGroup is the read-only view of a published group
+type SharedGroup struct {
+	Slug      string       `json:"slug"`
+	Name      string       `json:"name"`
+	Language  string       `json:"language"`
+	WordCount int          `json:"word_count"`
+	Words     []SharedWord `json:"words"`
+}
+
+// PublishGroup publishes a group as a shared deck under slug, or under a
+// random slug when slug is empty. A published group keeps its slug unless a
+// new one is given.
+func (s *GroupService) PublishGroup(ctx context.Context, id uint, slug, actor string) (*GroupDetail, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.PublishGroup")
+	defer span.End()
+
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug != "" && (len(slug) < minShareSlugLength || len(slug) > maxShareSlugLength || !shareSlugPattern.MatchString(slug)) {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("slug must be %d to %d lowercase letters, digits and single hyphens", minShareSlugLength, maxShareSlugLength), nil)
+	}
+	existing, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+	if slug == "" {
+		if existing.ShareSlug != nil {
+			return s.GetGroup(ctx, id)
+		}
+		buf := make([]byte, shareSlugBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to generate share slug", err)
+		}
+		slug = hex.EncodeToString(buf)
+	}
+
+	if err := s.groupRepo.SetShareSlug(ctx, id, &slug); err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return nil, NewServiceError(ErrCodeConflict, "Another group is published under this slug", err)
+		case repository.ErrNotFound:
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to publish group", err)
+	}
+	if err := s.recordAudit(ctx, actor, models.AuditActionPublish, models.AuditEntityGroup, &id, existing.ShareSlug, slug); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id)
+}
+
+// UnpublishGroup makes a published group private again. Its slug stops
+// working; copies already made are kept.
+func (s *GroupService) UnpublishGroup(ctx context.Context, id uint, actor string) error {
+	ctx, span := tracer.Start(ctx, "GroupService.UnpublishGroup")
+	defer span.End()
+
+	existing, err := s.groupRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+	if existing.ShareSlug == nil {
+		return nil
+	}
+	if err := s.groupRepo.SetShareSlug(ctx, id, nil); err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to unpublish group", err)
+	}
+	return s.recordAudit(ctx, actor, models.AuditActionUnpublish, models.AuditEntityGroup, &id, *existing.ShareSlug, nil)
+}
+
+// GetSharedGroup returns the group published under slug with its words
+func (s *GroupService) GetSharedGroup(ctx context.Context, slug string) (*SharedGroup, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.GetSharedGroup")
+	defer span.End()
+
+	group, err := s.sharedGroup(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	shared := &SharedGroup{
+		Slug:      slug,
+		Name:      group.Name,
+		Language:  group.Language,
+		WordCount: len(group.Words),
+		Words:     make([]SharedWord, len(group.Words)),
+	}
+	for i, word := range group.Words {
+		shared.Words[i] = SharedWord{Japanese: word.Term, Romaji: word.Reading, English: word.English}
+	}
+	return shared, nil
+}
+
+// CopySharedGroup creates a group holding the words of the group published
+// under slug, in its language. An empty name uses the shared group's name, numbered when a group
+// already has it; a name that is given must be free.
+func (s *GroupService) CopySharedGroup(ctx context.Context, slug, name string) (*models.Group, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.CopySharedGroup")
+	defer span.End()
+
+	shared, err := s.sharedGroup(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name, err = s.freeGroupName(ctx, shared.Name, shared.Language)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		existing, err := s.groupRepo.GetByName(ctx, name, shared.Language)
+		if err != nil && err != repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to check for existing group", err)
+		}
+		if existing != nil {
+			return nil, NewServiceError(ErrCodeInvalidInput, "A group with this name already exists", nil)
+		}
+	}
+
+	wordIDs := make([]uint, len(shared.Words))
+	for i, word := range shared.Words {
+		wordIDs[i] = word.ID
+	}
+	group := &models.Group{Name: name, Language: shared.Language}
+	if err := s.groupRepo.CreateWithWords(ctx, group, wordIDs); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create group", err)
+	}
+	return group, nil
+}
+
+// sharedGroup fetches the group published under slug
+func (s *GroupService) sharedGroup(ctx context.Context, slug string) (*models.Group, error) {
+	group, err := s.groupRepo.GetByShareSlug(ctx, slug)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Shared group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch shared group", err)
+	}
+	return group, nil
+}
+
+// freeGroupName returns name, or name with the lowest number from 2 that no
+// group of language has
+func (s *GroupService) freeGroupName(ctx context.Context, name, language string) (string, error) {
+	candidate := name
+	for n := 2; n <= maxCopySuffix+1; n++ {
+		_, err := s.groupRepo.GetByName(ctx, candidate, language)
+		if err == repository.ErrNotFound {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", NewServiceError(ErrCodeInternal, "Failed to check for existing group", err)
+		}
+		candidate = fmt.Sprintf("%s (%d)", name, n)
+	}
+	return "", NewServiceError(ErrCodeConflict, "Too many groups are named "+name+"; choose a name for the copy", nil)
+}
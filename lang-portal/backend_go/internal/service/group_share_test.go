@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupService_PublishGroup(t *testing.T) {
+	slug := "jlpt-n5"
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetByID", uint(3)).Return(&models.Group{ID: 3, Name: "JLPT N5"}, nil)
+	groupRepo.On("SetShareSlug", uint(3), &slug).Return(nil).Once()
+	groupRepo.On("GetSummary", uint(3)).Return(&repository.GroupSummary{Group: models.Group{ID: 3, Name: "JLPT N5", ShareSlug: &slug}}, nil)
+	audit := new(mockAuditRepository)
+	audit.On("Create", mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.Action == models.AuditActionPublish && entry.Actor == "teacher"
+	})).Return(nil).Once()
+	s := NewGroupService(NewBaseService(nil, groupRepo, nil, audit, nil))
+
+	group, err := s.PublishGroup(context.Background(), 3, " JLPT-N5 ", "teacher")
+	require.NoError(t, err)
+	assert.Equal(t, &slug, group.ShareSlug)
+	groupRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+
+	for _, invalid := range []string{"n5", "jlpt n5", "jlpt--n5", "-n5-"} {
+		_, err := s.PublishGroup(context.Background(), 3, invalid, "teacher")
+		require.Error(t, err, invalid)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, invalid)
+	}
+
+	groupRepo.On("SetShareSlug", uint(3), mock.Anything).Return(repository.ErrAlreadyExists)
+	_, err = s.PublishGroup(context.Background(), 3, "taken", "teacher")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeConflict, err.(*ServiceError).Code)
+}
+
+func TestGroupService_PublishGroup_KeepsSlug(t *testing.T) {
+	slug := "3f9a0c12de45"
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetByID", uint(3)).Return(&models.Group{ID: 3, Name: "JLPT N5", ShareSlug: &slug}, nil)
+	groupRepo.On("GetSummary", uint(3)).Return(&repository.GroupSummary{Group: models.Group{ID: 3, Name: "JLPT N5", ShareSlug: &slug}}, nil)
+	s := NewGroupService(NewBaseService(nil, groupRepo, nil, nil, nil))
+
+	group, err := s.PublishGroup(context.Background(), 3, "", "teacher")
+	require.NoError(t, err)
+	assert.Equal(t, &slug, group.ShareSlug)
+	groupRepo.AssertNotCalled(t, "SetShareSlug", mock.Anything, mock.Anything)
+}
+
+func TestGroupService_GetSharedGroup(t *testing.T) {
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetByShareSlug", "jlpt-n5").Return(&models.Group{ID: 3, Name: "JLPT N5", Words: []models.Word{
+		{ID: 1, Term: "犬", Reading: "inu", English: "dog"},
+	}}, nil)
+	groupRepo.On("GetByShareSlug", "gone").Return(nil, repository.ErrNotFound)
+	s := NewGroupService(NewBaseService(nil, groupRepo, nil, nil, nil))
+
+	shared, err := s.GetSharedGroup(context.Background(), "jlpt-n5")
+	require.NoError(t, err)
+	assert.Equal(t, &SharedGroup{Slug: "jlpt-n5", Name: "JLPT N5", WordCount: 1, Words: []SharedWord{
+		{Japanese: "犬", Romaji: "inu", English: "dog"},
+	}}, shared)
+
+	_, err = s.GetSharedGroup(context.Background(), "gone")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
+
+func TestGroupService_CopySharedGroup(t *testing.T) {
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetByShareSlug", "jlpt-n5").Return(&models.Group{ID: 3, Name: "JLPT N5", Language: models.LanguageJapanese, Words: []models.Word{{ID: 1}, {ID: 2}}}, nil)
+	groupRepo.On("GetByName", "JLPT N5", models.LanguageJapanese).Return(&models.Group{ID: 3, Name: "JLPT N5"}, nil)
+	groupRepo.On("GetByName", "JLPT N5 (2)", models.LanguageJapanese).Return(nil, repository.ErrNotFound)
+	groupRepo.On("CreateWithWords", &models.Group{Name: "JLPT N5 (2)", Language: models.LanguageJapanese}, []uint{1, 2}).Return(nil).Once()
+	s := NewGroupService(NewBaseService(nil, groupRepo, nil, nil, nil))
+
+	group, err := s.CopySharedGroup(context.Background(), "jlpt-n5", "")
+	require.NoError(t, err)
+	assert.Equal(t, "JLPT N5 (2)", group.Name)
+	groupRepo.AssertExpectations(t)
+
+	// A name that is given must be free
+	_, err = s.CopySharedGroup(context.Background(), "jlpt-n5", "JLPT N5")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGroupRepository is a partial mock of GroupRepositoryInterface
+type mockGroupRepository struct {
+	repository.GroupRepositoryInterface
+	mock.Mock
+}
+
+func (m *mockGroupRepository) GetByID(ctx context.Context, id uint) (*models.Group, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *mockGroupRepository) GetSummary(ctx context.Context, id uint) (*repository.GroupSummary, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.GroupSummary), args.Error(1)
+}
+
+func (m *mockGroupRepository) GetByName(ctx context.Context, name, language string) (*models.Group, error) {
+	args := m.Called(name, language)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *mockGroupRepository) GetByShareSlug(ctx context.Context, slug string) (*models.Group, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Group), args.Error(1)
+}
+
+func (m *mockGroupRepository) SetShareSlug(ctx context.Context, id uint, slug *string) error {
+	return m.Called(id, slug).Error(0)
+}
+
+func (m *mockGroupRepository) CreateWithWords(ctx context.Context, group *models.Group, wordIDs []uint) error {
+	return m.Called(group, wordIDs).Error(0)
+}
+
+func (m *mockGroupRepository) Create(ctx context.Context, group *models.Group) error {
+	return m.Called(group).Error(0)
+}
+
+func (m *mockGroupRepository) AddWord(ctx context.Context, groupID, wordID uint) error {
+	return m.Called(groupID, wordID).Error(0)
+}
+
+func TestGroupService_CreateGroup(t *testing.T) {
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetByName", "Verbs", models.LanguageJapanese).Return(nil, repository.ErrNotFound).Once()
+	groupRepo.On("Create", mock.AnythingOfType("*models.Group")).Return(nil).Once()
+	s := NewGroupService(NewBaseService(nil, groupRepo, nil, nil, nil))
+
+	require.NoError(t, s.CreateGroup(context.Background(), &models.Group{Name: "Verbs"}))
+
+	groupRepo.On("GetByName", "Verbs", models.LanguageJapanese).Return(&models.Group{ID: 1, Name: "Verbs"}, nil)
+	err := s.CreateGroup(context.Background(), &models.Group{Name: "Verbs"})
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestGroupService_GetGroup(t *testing.T) {
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetSummary", uint(1)).Return(&repository.GroupSummary{Group: models.Group{ID: 1, Name: "Verbs", Version: 2}, WordCount: 5}, nil)
+	groupRepo.On("GetSummary", uint(2)).Return(nil, repository.ErrNotFound)
+	s := NewGroupService(NewBaseService(nil, groupRepo, nil, nil, nil))
+
+	group, err := s.GetGroup(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Verbs", group.Name)
+	assert.Equal(t, 5, group.WordCount)
+	assert.Equal(t, uint(2), group.Version)
+
+	_, err = s.GetGroup(context.Background(), 2)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
+
+func TestGroupService_AddWordToGroup(t *testing.T) {
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetByID", uint(1)).Return(&models.Group{ID: 1}, nil)
+	groupRepo.On("GetByID", uint(2)).Return(nil, repository.ErrNotFound)
+	groupRepo.On("AddWord", uint(1), uint(7)).Return(nil).Once()
+	groupRepo.On("AddWord", uint(1), uint(8)).Return(errors.New("disk full")).Once()
+	wordRepo := new(mockWordRepository)
+	wordRepo.On("GetByID", uint(7)).Return(&models.Word{ID: 7}, nil)
+	wordRepo.On("GetByID", uint(8)).Return(&models.Word{ID: 8}, nil)
+	wordRepo.On("GetByID", uint(9)).Return(nil, repository.ErrNotFound)
+	s := NewGroupService(NewBaseService(wordRepo, groupRepo, nil, nil, nil))
+
+	require.NoError(t, s.AddWordToGroup(context.Background(), 1, 7))
+
+	for _, tc := range []struct {
+		groupID, wordID uint
+		code            string
+	}{
+		{2, 7, ErrCodeNotFound},
+		{1, 9, ErrCodeNotFound},
+		{1, 8, ErrCodeInternal},
+	} {
+		err := s.AddWordToGroup(context.Background(), tc.groupID, tc.wordID)
+		require.Error(t, err)
+		assert.Equal(t, tc.code, err.(*ServiceError).Code)
+	}
+	groupRepo.AssertExpectations(t)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Health statuses
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+)
+
+// HealthCheckTimeout bounds how long a single readiness check may take
+const HealthCheckTimeout = 2 * time.Second
+
+// HealthCheck is a named dependency check. A failing critical check makes the
+// service not ready; a failing non-critical one only degrades it.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// ComponentHealth is the outcome of a single health check
+type ComponentHealth struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// HealthReport is the overall readiness of the service
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+	CheckedAt  time.Time                  `json:"checked_at"`
+}
+
+// Ready reports whether every critical component is healthy
+func (r *HealthReport) Ready() bool {
+	return r.Status != HealthStatusDown
+}
+
+// HealthService runs dependency checks for the readiness probe
+type HealthService struct {
+	checks []HealthCheck
+}
+
+// NewHealthService creates a health service running the given checks
+func NewHealthService(checks ...HealthCheck) *HealthService {
+	return &HealthService{checks: checks}
+}
+
+// CheckReadiness runs all checks concurrently, each bounded by HealthCheckTimeout
+func (s *HealthService) CheckReadiness(ctx context.Context) *HealthReport {
+	report := &HealthReport{
+		Status:     HealthStatusOK,
+		Components: make(map[string]ComponentHealth, len(s.checks)),
+		CheckedAt:  time.Now().UTC(),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+			component := runHealthCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[check.Name] = component
+			if component.Status == HealthStatusOK {
+				return
+			}
+			if check.Critical {
+				report.Status = HealthStatusDown
+			} else if report.Status == HealthStatusOK {
+				report.Status = HealthStatusDegraded
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func runHealthCheck(ctx context.Context, check HealthCheck) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	component := ComponentHealth{
+		Status:    HealthStatusOK,
+		Critical:  check.Critical,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		component.Status = HealthStatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
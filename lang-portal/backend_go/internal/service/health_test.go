@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthService_CheckReadiness(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("unreachable") }
+
+	tests := []struct {
+		name   string
+		checks []HealthCheck
+		status string
+		ready  bool
+	}{
+		{"all healthy", []HealthCheck{{Name: "database", Critical: true, Check: ok}, {Name: "cache", Check: ok}}, HealthStatusOK, true},
+		{"non-critical failure", []HealthCheck{{Name: "database", Critical: true, Check: ok}, {Name: "cache", Check: failing}}, HealthStatusDegraded, true},
+		{"critical failure", []HealthCheck{{Name: "database", Critical: true, Check: failing}, {Name: "cache", Check: failing}}, HealthStatusDown, false},
+		{"no checks", nil, HealthStatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewHealthService(tt.checks...).CheckReadiness(context.Background())
+			assert.Equal(t, tt.status, report.Status)
+			assert.Equal(t, tt.ready, report.Ready())
+			assert.Len(t, report.Components, len(tt.checks))
+		})
+	}
+}
+
+func TestHealthService_CheckReadiness_ReportsErrors(t *testing.T) {
+	report := NewHealthService(HealthCheck{
+		Name:     "migrations",
+		Critical: true,
+		Check:    func(ctx context.Context) error { return errors.New("2 migrations pending") },
+	}).CheckReadiness(context.Background())
+
+	component := report.Components["migrations"]
+	assert.Equal(t, HealthStatusDown, component.Status)
+	assert.True(t, component.Critical)
+	assert.Equal(t, "2 migrations pending", component.Error)
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"lang-portal/backend_go/internal/dictionary"
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/ocr"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// MaxCaptureImageSize is the default cap on the size of an image sent for
+// vocabulary capture
+const MaxCaptureImageSize = 10 << 20
+
+// Vocabulary capture limits
+const (
+	// maxCapturedWords caps the distinct words looked up per image, and the
+	// words confirmed at once
+	maxCapturedWords = 50
+	// capturedEntries is the number of dictionary entries offered per word
+	capturedEntries = 3
+)
+
+// CapturedWord is a word read from an image with the dictionary entries it may
+// be. Query is what the entries were found by: the word as written or, when
+// nothing matched, its kanji alone. ExistingWordID is set when the best entry,
+// or the word itself, is already in the word list.
+type CapturedWord struct {
+	Text           string             `json:"text"`
+	Query          string             `json:"query"`
+	Occurrences    int                `json:"occurrences"`
+	ExistingWordID *uint              `json:"existing_word_id,omitempty"`
+	Entries        []dictionary.Entry `json:"entries"`
+}
+
+// ImageCapture is the text read from an image and the words found in it, in
+// the order they first appear
+type ImageCapture struct {
+	Text  string         `json:"text"`
+	Words []CapturedWord `json:"words"`
+}
+
+// CapturedWordPick confirms a dictionary entry and sense for a captured word
+type CapturedWordPick struct {
+	Query   string `json:"query" binding:"required"`
+	EntryID string `json:"entry_id" binding:"required"`
+	Sense   int    `json:"sense"`
+}
+
+// CaptureResult reports the confirmed words: those created in the word list
+// and the IDs of those added to the group, which excludes words already in it
+type CaptureResult struct {
+	GroupID uint          `json:"group_id"`
+	Created []models.Word `json:"created"`
+	Added   []uint        `json:"added"`
+}
+
+// WithTextReader reads images for vocabulary capture with r. Without one,
+// vocabulary capture is not available.
+func (s *DictionaryService) WithTextReader(r ocr.Reader) *DictionaryService {
+	s.reader = r
+	return s
+}
+
+// CaptureFromImage reads the Japanese text in an image, splits it into likely
+// words and looks each distinct word up in the dictionary. Words without any
+// dictionary entry are left out. Nothing is stored.
+func (s *DictionaryService) CaptureFromImage(ctx context.Context, image []byte, contentType string) (*ImageCapture, error) {
+	ctx, span := tracer.Start(ctx, "DictionaryService.CaptureFromImage")
+	defer span.End()
+
+	if s.reader == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Vocabulary capture is not available", nil)
+	}
+	if len(image) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Image is empty", nil)
+	}
+	if !ocr.Supported(contentType) {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Unsupported image type: "+contentType, nil)
+	}
+
+	text, err := s.reader.Read(ctx, image, contentType)
+	if err != nil {
+		if errors.Is(err, ocr.ErrUnavailable) {
+			return nil, NewServiceError(ErrCodeInternal, "Text recognition is unavailable", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to read image", err)
+	}
+
+	capture := &ImageCapture{Text: text, Words: []CapturedWord{}}
+	index := make(map[string]int)
+	looked := 0
+	for _, token := range jpn.Tokens(text) {
+		if i, ok := index[token.Surface]; ok {
+			if i >= 0 {
+				capture.Words[i].Occurrences++
+			}
+			continue
+		}
+		if looked == maxCapturedWords {
+			break
+		}
+		looked++
+
+		word, err := s.captureWord(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if word == nil {
+			index[token.Surface] = -1
+			continue
+		}
+		index[token.Surface] = len(capture.Words)
+		capture.Words = append(capture.Words, *word)
+	}
+	return capture, nil
+}
+
+// captureWord looks a token up by its surface and then by its stem, and returns
+// nil when neither has dictionary entries
+func (s *DictionaryService) captureWord(ctx context.Context, token jpn.Token) (*CapturedWord, error) {
+	for _, query := range []string{token.Surface, token.Stem} {
+		if query == "" {
+			continue
+		}
+		entries, err := s.lookup(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		if len(entries) > capturedEntries {
+			entries = entries[:capturedEntries]
+		}
+
+		word := &CapturedWord{Text: token.Surface, Query: query, Occurrences: 1, Entries: entries}
+		japanese := token.Surface
+		if best, err := entries[0].Word(0); err == nil {
+			japanese = best.Term
+		}
+		existing, err := s.wordRepo.GetByTerm(ctx, japanese)
+		switch {
+		case err == nil:
+			word.ExistingWordID = &existing.ID
+		case err != repository.ErrNotFound:
+			return nil, NewServiceError(ErrCodeInternal, "Failed to check existing words", err)
+		}
+		return word, nil
+	}
+	return nil, nil
+}
+
+// AddCapturedWords adds the picked dictionary entries to a group, creating the
+// words that are not in the word list yet. Words already in the group are
+// left as they are.
+func (s *DictionaryService) AddCapturedWords(ctx context.Context, groupID uint, picks []CapturedWordPick) (*CaptureResult, error) {
+	ctx, span := tracer.Start(ctx, "DictionaryService.AddCapturedWords")
+	defer span.End()
+
+	if len(picks) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "No words to add", nil)
+	}
+	if len(picks) > maxCapturedWords {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Too many words to add at once", nil)
+	}
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+	members, err := s.wordRepo.GetWordsByGroupRaw(ctx, groupID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group words", err)
+	}
+	inGroup := make(map[uint]bool, len(members))
+	for _, member := range members {
+		inGroup[member.ID] = true
+	}
+
+	// Resolve every pick before changing anything
+	words := make([]*models.Word, len(picks))
+	for i, pick := range picks {
+		entry, err := s.findEntry(ctx, pick.Query, pick.EntryID)
+		if err != nil {
+			return nil, err
+		}
+		if words[i], err = entry.Word(pick.Sense); err != nil {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Invalid dictionary sense", err).WithDetails(ErrorReason{Reason: err.Error()})
+		}
+	}
+
+	result := &CaptureResult{GroupID: groupID, Created: []models.Word{}, Added: []uint{}}
+	for _, word := range words {
+		existing, err := s.wordRepo.GetByTerm(ctx, word.Term)
+		switch {
+		case err == nil:
+			word = existing
+		case err != repository.ErrNotFound:
+			return nil, NewServiceError(ErrCodeInternal, "Failed to check existing words", err)
+		default:
+			if err := s.wordRepo.Create(ctx, word); err != nil {
+				return nil, NewServiceError(ErrCodeInternal, "Failed to create word", err)
+			}
+			result.Created = append(result.Created, *word)
+			s.publish(events.TypeWordCreated, map[string]uint{"id": word.ID})
+		}
+
+		if inGroup[word.ID] {
+			continue
+		}
+		if err := s.groupRepo.AddWord(ctx, groupID, word.ID); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to add word to group", err)
+		}
+		inGroup[word.ID] = true
+		result.Added = append(result.Added, word.ID)
+	}
+	s.invalidateDashboard()
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticReader reads the same text from every image
+type staticReader string
+
+func (r staticReader) Read(ctx context.Context, image []byte, contentType string) (string, error) {
+	return string(r), nil
+}
+
+func TestDictionaryService_CaptureFromImage(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	s := NewDictionaryService(NewBaseService(mockRepo, nil, nil, nil, nil), testEntries).
+		WithTextReader(staticReader("毎朝パンを食べます。パン！"))
+
+	mockRepo.On("GetByTerm", "食べる").Return(&models.Word{ID: 7, Term: "食べる"}, nil)
+
+	capture, err := s.CaptureFromImage(context.Background(), []byte("PNG"), "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, "毎朝パンを食べます。パン！", capture.Text)
+	require.Len(t, capture.Words, 3)
+	assert.Equal(t, "毎朝", capture.Words[0].Text)
+	assert.Equal(t, "パン", capture.Words[1].Text)
+	assert.Equal(t, 2, capture.Words[1].Occurrences)
+	assert.Equal(t, "食べます", capture.Words[2].Query)
+	require.NotNil(t, capture.Words[2].ExistingWordID)
+	assert.Equal(t, uint(7), *capture.Words[2].ExistingWordID)
+	assert.Len(t, capture.Words[2].Entries, len(testEntries))
+}
+
+func TestDictionaryService_CaptureFromImage_Invalid(t *testing.T) {
+	base := NewBaseService(nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	_, err := NewDictionaryService(base, testEntries).CaptureFromImage(ctx, []byte("PNG"), "image/png")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code, "capture needs a text reader")
+
+	s := NewDictionaryService(base, testEntries).WithTextReader(staticReader("パン"))
+	_, err = s.CaptureFromImage(ctx, nil, "image/png")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+	_, err = s.CaptureFromImage(ctx, []byte("PDF"), "application/pdf")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+
+	_, err = s.AddCapturedWords(ctx, 1, nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
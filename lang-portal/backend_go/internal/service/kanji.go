@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// KanjiService handles kanji browsing and per-kanji progress
+type KanjiService struct {
+	*BaseService
+	kanjiRepo repository.KanjiRepositoryInterface
+	strokes   StrokeSource
+}
+
+// NewKanjiService creates a new kanji service
+func NewKanjiService(base *BaseService, kanjiRepo repository.KanjiRepositoryInterface) *KanjiService {
+	return &KanjiService{BaseService: base, kanjiRepo: kanjiRepo}
+}
+
+// Kanji represents a kanji with the study progress of the words containing it
+type Kanji struct {
+	Character   string   `json:"character"`
+	Meanings    []string `json:"meanings"`
+	Onyomi      []string `json:"onyomi"`
+	Kunyomi     []string `json:"kunyomi"`
+	StrokeCount int      `json:"stroke_count"`
+	JLPTLevel   int      `json:"jlpt_level"`
+	WordCount   int64    `json:"word_count"`
+	// StudiedWords is the number of words containing the kanji reviewed at least once
+	StudiedWords  int64 `json:"studied_words"`
+	MasteredWords int64 `json:"mastered_words"`
+	CorrectCount  int64 `json:"correct_count"`
+	WrongCount    int64 `json:"wrong_count"`
+	// Mastery is the percentage of words containing the kanji that are mastered
+	Mastery float64 `json:"mastery"`
+}
+
+// KanjiUpdate is the dictionary data of a kanji
+type KanjiUpdate struct {
+	Meanings    []string `json:"meanings"`
+	Onyomi      []string `json:"onyomi"`
+	Kunyomi     []string `json:"kunyomi"`
+	StrokeCount int      `json:"stroke_count"`
+	JLPTLevel   int      `json:"jlpt_level"`
+}
+
+// KanjiListOptions filters and orders kanji lists
+type KanjiListOptions struct {
+	// JLPTLevel keeps only kanji of that level when non-zero
+	JLPTLevel int
+	// Sort is by character, stroke_count, jlpt_level, word_count or mastered_count
+	Sort SortParams
+}
+
+// ListKanji retrieves a paginated list of kanji, by default the kanji found in
+// the most words first
+func (s *KanjiService) ListKanji(ctx context.Context, params PaginationParams, listOpts KanjiListOptions) (*PaginatedResult[Kanji], error) {
+	ctx, span := tracer.Start(ctx, "KanjiService.ListKanji")
+	defer span.End()
+
+	if listOpts.JLPTLevel < 0 || listOpts.JLPTLevel > 5 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "jlpt must be between 1 and 5", nil)
+	}
+	sort, err := listOpts.Sort.toRepository(repository.ValidKanjiSortField)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := s.listOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts.JLPTLevel = listOpts.JLPTLevel
+	opts.Sort = sort
+
+	result, err := s.kanjiRepo.List(ctx, repository.PaginationParams{
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}, opts)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list kanji", err)
+	}
+
+	kanji := make([]Kanji, len(result.Items))
+	for i := range result.Items {
+		kanji[i] = toKanji(&result.Items[i])
+	}
+	return NewPaginatedResult(kanji, result.TotalItems, params.Page, params.PageSize), nil
+}
+
+// GetKanji retrieves a kanji and its progress
+func (s *KanjiService) GetKanji(ctx context.Context, character string) (*Kanji, error) {
+	ctx, span := tracer.Start(ctx, "KanjiService.GetKanji")
+	defer span.End()
+
+	summary, err := s.getSummary(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+	kanji := toKanji(summary)
+	return &kanji, nil
+}
+
+// UpdateKanji replaces the dictionary data of a kanji
+func (s *KanjiService) UpdateKanji(ctx context.Context, character string, update *KanjiUpdate) (*Kanji, error) {
+	ctx, span := tracer.Start(ctx, "KanjiService.UpdateKanji")
+	defer span.End()
+
+	summary, err := s.getSummary(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+
+	kanji := summary.Kanji
+	kanji.Meanings = update.Meanings
+	kanji.Onyomi = update.Onyomi
+	kanji.Kunyomi = update.Kunyomi
+	kanji.StrokeCount = update.StrokeCount
+	kanji.JLPTLevel = update.JLPTLevel
+	if err := s.kanjiRepo.Update(ctx, &kanji); err != nil {
+		if err == repository.ErrInvalidInput {
+			return nil, NewServiceError(ErrCodeInvalidInput, "stroke_count must be between 0 and 84 and jlpt_level between 0 and 5", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to update kanji", err)
+	}
+
+	summary.Kanji = kanji
+	updated := toKanji(summary)
+	return &updated, nil
+}
+
+// GetKanjiWords retrieves the words containing a kanji
+func (s *KanjiService) GetKanjiWords(ctx context.Context, character string, params PaginationParams) (*PaginatedResult[Word], error) {
+	ctx, span := tracer.Start(ctx, "KanjiService.GetKanjiWords")
+	defer span.End()
+
+	summary, err := s.getSummary(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.kanjiRepo.GetWords(ctx, summary.ID, repository.PaginationParams{
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get kanji words", err)
+	}
+
+	words := make([]Word, len(result.Items))
+	for i, w := range result.Items {
+		correctCount, wrongCount, err := s.wordRepo.GetStudyStats(ctx, w.ID)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to get word statistics", err)
+		}
+
+		words[i] = Word{
+			ID:            w.ID,
+			Language:      w.Language,
+			Japanese:      w.Term,
+			Romaji:        w.Reading,
+			English:       w.English,
+			CorrectCount:  correctCount,
+			WrongCount:    wrongCount,
+			State:         w.ProgressState(),
+			FrequencyRank: w.FrequencyRank,
+			UpdatedAt:     w.UpdatedAt,
+		}
+	}
+	return NewPaginatedResult(words, result.TotalItems, params.Page, params.PageSize), nil
+}
+
+func (s *KanjiService) getSummary(ctx context.Context, character string) (*repository.KanjiSummary, error) {
+	opts, err := s.listOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.kanjiRepo.GetByCharacter(ctx, character, opts)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Kanji not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch kanji", err)
+	}
+	return summary, nil
+}
+
+func (s *KanjiService) listOptions(ctx context.Context) (repository.KanjiListOptions, error) {
+	minReviews, minRate, err := s.masteryThresholds(ctx)
+	if err != nil {
+		return repository.KanjiListOptions{}, err
+	}
+	return repository.KanjiListOptions{MasteredMinReviews: minReviews, MasteredMinSuccessRate: minRate}, nil
+}
+
+func toKanji(k *repository.KanjiSummary) Kanji {
+	kanji := Kanji{
+		Character:     k.Character,
+		Meanings:      nonNil(k.Meanings),
+		Onyomi:        nonNil(k.Onyomi),
+		Kunyomi:       nonNil(k.Kunyomi),
+		StrokeCount:   k.StrokeCount,
+		JLPTLevel:     k.JLPTLevel,
+		WordCount:     k.WordCount,
+		StudiedWords:  k.StudiedCount,
+		MasteredWords: k.MasteredCount,
+		CorrectCount:  k.CorrectCount,
+		WrongCount:    k.WrongCount,
+	}
+	if k.WordCount > 0 {
+		kanji.Mastery = float64(k.MasteredCount) / float64(k.WordCount) * 100
+	}
+	return kanji
+}
+
+func nonNil(values models.StringSlice) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"regexp"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// targetLanguage returns the target language code, the default language when
+// code is empty. Languages vocabulary cannot be kept in are rejected.
+func targetLanguage(code string) (string, error) {
+	if code == "" {
+		return models.DefaultLanguage, nil
+	}
+	if _, ok := models.FindTargetLanguage(code); !ok {
+		return "", NewServiceError(ErrCodeInvalidInput, "Unsupported language: "+code, nil)
+	}
+	return code, nil
+}
+
+// scriptCode matches ISO 15924 script codes such as Latn
+var scriptCode = regexp.MustCompile(`^[A-Z][a-z]{3}$`)
+
+// checkScripts rejects scripts of a word that are not ISO 15924 codes. Empty
+// scripts are those of the word's language.
+func checkScripts(word *models.Word) error {
+	for _, script := range []string{word.Script, word.ReadingScript} {
+		if script != "" && !scriptCode.MatchString(script) {
+			return NewServiceError(ErrCodeInvalidInput, "Scripts must be ISO 15924 codes such as Latn: "+script, nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Recent mistakes window, in days
+const (
+	DefaultMistakesDays = 7
+	MaxMistakesDays     = 90
+)
+
+// MistakesGroupName names the group that mistakes sessions are created on. Its
+// words are replaced with the current queue every time such a session starts.
+const MistakesGroupName = "Recent mistakes"
+
+// MistakeWord is a word answered incorrectly recently and not yet answered
+// correctly since
+type MistakeWord struct {
+	ID       uint   `json:"id"`
+	Japanese string `json:"japanese"`
+	Romaji   string `json:"romaji"`
+	English  string `json:"english"`
+	// Mistakes is the number of incorrect answers since the last correct one
+	Mistakes      int       `json:"mistakes"`
+	LastMistakeAt time.Time `json:"last_mistake_at"`
+}
+
+// MistakesSession is a study session on the mistakes queue
+type MistakesSession struct {
+	Session *models.StudySession `json:"session"`
+	Words   []MistakeWord        `json:"words"`
+}
+
+// GetMistakes returns the words answered incorrectly in the last days days and
+// not re-reviewed correctly since, most recent mistake first
+func (s *StudyService) GetMistakes(ctx context.Context, days int) ([]MistakeWord, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetMistakes")
+	defer span.End()
+
+	if days < 1 || days > MaxMistakesDays {
+		return nil, NewServiceError(ErrCodeInvalidInput, "days must be between 1 and 90", nil)
+	}
+
+	reviews, err := s.studyRepo.GetOpenMistakes(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get recent mistakes", err)
+	}
+
+	// Reviews come newest first, so the first one seen per word is its last mistake
+	words := []MistakeWord{}
+	index := make(map[uint]int)
+	for _, review := range reviews {
+		if i, ok := index[review.WordID]; ok {
+			words[i].Mistakes++
+			continue
+		}
+		index[review.WordID] = len(words)
+		words = append(words, MistakeWord{
+			ID:            review.Word.ID,
+			Japanese:      review.Word.Term,
+			Romaji:        review.Word.Reading,
+			English:       review.Word.English,
+			Mistakes:      1,
+			LastMistakeAt: review.CreatedAt,
+		})
+	}
+	return words, nil
+}
+
+// CreateMistakesSession starts a study session on the current mistakes queue.
+// The session belongs to the MistakesGroupName group, which is created on first
+// use and whose words are replaced with the queue. The session is learner's.
+func (s *StudyService) CreateMistakesSession(ctx context.Context, learner string, activityID uint, days int, reviewOrder string, client models.ClientInfo) (*MistakesSession, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.CreateMistakesSession")
+	defer span.End()
+
+	words, err := s.GetMistakes(ctx, days)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "There are no recent mistakes to review", nil)
+	}
+	wordIDs := make([]uint, len(words))
+	for i, word := range words {
+		wordIDs[i] = word.ID
+	}
+
+	group, err := s.groupRepo.GetByName(ctx, MistakesGroupName, models.DefaultLanguage)
+	switch {
+	case err == repository.ErrNotFound:
+		group = &models.Group{Name: MistakesGroupName}
+		if err := s.groupRepo.CreateWithWords(ctx, group, wordIDs); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to create mistakes group", err)
+		}
+	case err != nil:
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch mistakes group", err)
+	default:
+		if err := s.groupRepo.ReplaceWords(ctx, group.ID, wordIDs); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to update mistakes group", err)
+		}
+	}
+
+	session := &models.StudySession{GroupID: group.ID, StudyActivityID: activityID, ReviewOrder: reviewOrder, Learner: learner, Client: client}
+	if err := s.CreateStudySession(ctx, session); err != nil {
+		return nil, err
+	}
+	return &MistakesSession{Session: session, Words: words}, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"lang-portal/backend_go/internal/llm"
+)
+
+// maxGenerationAttempts caps the requests made for a reply that passes its
+// checks
+const maxGenerationAttempts = 3
+
+// WithLogger logs the replies of the language model that fail their checks to
+// logger. Without one they are dropped silently.
+func (s *BaseService) WithLogger(logger *log.Logger) *BaseService {
+	s.logger = logger
+	return s
+}
+
+// generate asks the language model for a reply of feature for client until check
+// accepts it, up to maxGenerationAttempts times, logging the replies it
+// rejects. A nil check accepts any reply. A streamed reply cannot be taken back,
+// so once part of one has been streamed its rejection is final.
+func (s *BaseService) generate(ctx context.Context, client, feature string, req llm.Request, onDelta func(string) error, check func(reply string) error) (*llm.Response, error) {
+	var rejection error
+	for attempt := 1; attempt <= maxGenerationAttempts; attempt++ {
+		started := false
+		var deliver func(string) error
+		if onDelta != nil {
+			deliver = func(text string) error {
+				started = true
+				return onDelta(text)
+			}
+		}
+
+		response, err := s.chat(ctx, client, feature, req, deliver)
+		if err != nil {
+			return nil, err
+		}
+		if check == nil {
+			return response, nil
+		}
+		if rejection = check(response.Content); rejection == nil {
+			return response, nil
+		}
+		if s.logger != nil {
+			s.logger.Printf("Rejected %s reply from %q for %s (attempt %d of %d): %v", feature, response.Provider, client, attempt, maxGenerationAttempts, rejection)
+		}
+		if started {
+			break
+		}
+	}
+	return nil, NewServiceError(ErrCodeInternal, "The generated content was rejected", rejection)
+}
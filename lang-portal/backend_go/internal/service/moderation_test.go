@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/moderation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replyQueue is a language model giving a reply from replies per request,
+// repeating the last one
+type replyQueue struct {
+	replies []string
+	calls   int
+}
+
+func (q *replyQueue) Chat(ctx context.Context, req llm.Request, onDelta func(string) error) (*llm.Response, error) {
+	reply := q.replies[min(q.calls, len(q.replies)-1)]
+	q.calls++
+	if onDelta != nil {
+		if err := onDelta(reply); err != nil {
+			return nil, err
+		}
+	}
+	return &llm.Response{Content: reply, Provider: "openai"}, nil
+}
+
+func TestBaseService_Generate_RetriesRejectedReplies(t *testing.T) {
+	var logged bytes.Buffer
+	client := &replyQueue{replies: []string{"Email sensei@example.com to practise.", "食べる (taberu) means to eat."}}
+	base := NewBaseService(nil, nil, nil, nil, nil).WithLLM(client).WithLogger(log.New(&logged, "", 0))
+	check := func(reply string) error {
+		return moderation.Check(reply, moderation.Policy{Language: moderation.Mixed})
+	}
+
+	response, err := base.generate(context.Background(), "alice", PromptWordExplanation, llm.Request{}, nil, check)
+	require.NoError(t, err)
+	assert.Equal(t, "食べる (taberu) means to eat.", response.Content)
+	assert.Equal(t, 2, client.calls)
+	assert.Contains(t, logged.String(), `Rejected word_explanation reply from "openai" for alice (attempt 1 of 3): pii`)
+
+	client = &replyQueue{replies: []string{"Это не японский."}}
+	base.WithLLM(client)
+	_, err = base.generate(context.Background(), "alice", PromptWordExplanation, llm.Request{}, nil, check)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInternal, err.(*ServiceError).Code)
+	assert.Equal(t, maxGenerationAttempts, client.calls)
+
+	client = &replyQueue{replies: []string{"Это не японский.", "はい"}}
+	base.WithLLM(client)
+	_, err = base.generate(context.Background(), "alice", PromptTutor, llm.Request{}, func(string) error { return nil }, check)
+	require.Error(t, err)
+	assert.Equal(t, 1, client.calls, "a streamed reply is not asked for again")
+}
+
+func TestSentenceService_GenerateExampleSentence_RetriesUnusableSentences(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	mockRepo.On("GetByID", uint(1)).Return(&models.Word{ID: 1, Term: "食べる", Reading: "taberu", English: "to eat"}, nil)
+	client := &replyQueue{replies: []string{
+		"毎朝パンを買います。\nI buy bread every morning.",
+		"毎朝パンを食べます。\nI eat fucking bread every morning.",
+		"毎朝パンを食べます。\nI eat bread every morning.",
+	}}
+	s := NewSentenceService(NewBaseService(mockRepo, nil, nil, nil, nil).WithLLM(client), nil)
+
+	sentence, err := s.GenerateExampleSentence(context.Background(), "alice", 1, LevelBeginner)
+	require.NoError(t, err)
+	assert.Equal(t, "I eat bread every morning.", sentence.English)
+	assert.Equal(t, 3, client.calls)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// NotificationService serves the in-app inbox of each learner, the actor their
+// study sessions are recorded under. The Notifier fills it.
+type NotificationService struct {
+	*BaseService
+	notificationRepo repository.NotificationRepositoryInterface
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(base *BaseService, notificationRepo repository.NotificationRepositoryInterface) *NotificationService {
+	return &NotificationService{BaseService: base, notificationRepo: notificationRepo}
+}
+
+// NotificationPage is a page of a learner's inbox with the number of their
+// notifications still unread
+type NotificationPage struct {
+	*PaginatedResult[models.Notification]
+	UnreadCount int64
+}
+
+// ListNotifications returns a page of recipient's notifications, newest first,
+// optionally only the unread ones
+func (s *NotificationService) ListNotifications(ctx context.Context, recipient string, unreadOnly bool, params PaginationParams) (*NotificationPage, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.ListNotifications")
+	defer span.End()
+
+	result, err := s.notificationRepo.List(ctx, recipient, unreadOnly, repository.PaginationParams{
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list notifications", err)
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, recipient)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to count unread notifications", err)
+	}
+	return &NotificationPage{
+		PaginatedResult: NewPaginatedResult(result.Items, result.TotalItems, params.Page, params.PageSize),
+		UnreadCount:     unread,
+	}, nil
+}
+
+// MarkRead marks one of recipient's notifications read
+func (s *NotificationService) MarkRead(ctx context.Context, recipient string, id uint) (*models.Notification, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.MarkRead")
+	defer span.End()
+
+	notification, err := s.notificationRepo.MarkRead(ctx, recipient, id, time.Now().UTC())
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Notification not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to mark notification read", err)
+	}
+	return notification, nil
+}
+
+// MarkAllRead marks all of recipient's notifications read and returns how many
+// were unread
+func (s *NotificationService) MarkAllRead(ctx context.Context, recipient string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.MarkAllRead")
+	defer span.End()
+
+	marked, err := s.notificationRepo.MarkAllRead(ctx, recipient, time.Now().UTC())
+	if err != nil {
+		return 0, NewServiceError(ErrCodeInternal, "Failed to mark notifications read", err)
+	}
+	return marked, nil
+}
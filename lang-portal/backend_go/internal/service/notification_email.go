@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/mail"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// ReminderEmailHour is the UTC hour from which the day's reminder emails are
+// sent. Weekly reminders go out once per week starting Monday.
+const ReminderEmailHour = 8
+
+// unsubscribeTokenBytes is the length of unsubscribe tokens before hex encoding
+const unsubscribeTokenBytes = 16
+
+// maxEmailLength is the longest address RFC 5321 allows
+const maxEmailLength = 254
+
+// GetPreferences returns how learner is reminded by email, with reminders off
+// when they have not chosen
+func (s *NotificationService) GetPreferences(ctx context.Context, learner string) (*models.NotificationPreference, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.GetPreferences")
+	defer span.End()
+
+	preference, err := s.notificationRepo.GetPreference(ctx, learner)
+	if err == repository.ErrNotFound {
+		return &models.NotificationPreference{Learner: learner, EmailFrequency: models.EmailFrequencyOff}, nil
+	}
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch notification preferences", err)
+	}
+	return preference, nil
+}
+
+// UpdatePreferences sets the address learner's reminder emails go to and how
+// often they are sent. Daily and weekly reminders need an address.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, learner, email, frequency string) (*models.NotificationPreference, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.UpdatePreferences")
+	defer span.End()
+
+	email = strings.TrimSpace(email)
+	if frequency == "" {
+		frequency = models.EmailFrequencyOff
+	}
+	if frequency != models.EmailFrequencyOff && frequency != models.EmailFrequencyDaily && frequency != models.EmailFrequencyWeekly {
+		return nil, NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("email_frequency must be %s, %s or %s", models.EmailFrequencyOff, models.EmailFrequencyDaily, models.EmailFrequencyWeekly), nil)
+	}
+	if email != "" {
+		address, err := netmail.ParseAddress(email)
+		if err != nil || address.Address != email || len(email) > maxEmailLength {
+			return nil, NewServiceError(ErrCodeInvalidInput, "email must be a valid address", err)
+		}
+	} else if frequency != models.EmailFrequencyOff {
+		return nil, NewServiceError(ErrCodeInvalidInput, "email is required for reminder emails", nil)
+	}
+
+	preference, err := s.GetPreferences(ctx, learner)
+	if err != nil {
+		return nil, err
+	}
+	if preference.UnsubscribeToken == "" {
+		buf := make([]byte, unsubscribeTokenBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to generate unsubscribe token", err)
+		}
+		preference.UnsubscribeToken = hex.EncodeToString(buf)
+	}
+	preference.Email = email
+	preference.EmailFrequency = frequency
+	if err := s.notificationRepo.SavePreference(ctx, preference); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to save notification preferences", err)
+	}
+	return preference, nil
+}
+
+// Unsubscribe turns off the reminder emails of the learner an unsubscribe
+// token was sent to
+func (s *NotificationService) Unsubscribe(ctx context.Context, token string) error {
+	ctx, span := tracer.Start(ctx, "NotificationService.Unsubscribe")
+	defer span.End()
+
+	if err := s.notificationRepo.Unsubscribe(ctx, token); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Unsubscribe link not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to unsubscribe", err)
+	}
+	return nil
+}
+
+// WithMail has the notifier send reminder emails from the address from with
+// sender. Unsubscribe links point at publicURL, the address the API is reached
+// at. Without it no emails are sent.
+func (n *Notifier) WithMail(sender mail.Sender, from, publicURL string) *Notifier {
+	n.mail = sender
+	n.mailFrom = from
+	n.publicURL = strings.TrimRight(publicURL, "/")
+	return n
+}
+
+// SendReminders emails the learners whose daily or weekly reminder is due at
+// now and returns how many were sent. A failed email is logged and retried on
+// the next check.
+func (n *Notifier) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "Notifier.SendReminders")
+	defer span.End()
+
+	now = now.UTC()
+	if n.mail == nil || now.Hour() < ReminderEmailHour {
+		return 0, nil
+	}
+	subscribers, err := n.notificationRepo.ListEmailSubscribers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read email subscribers: %w", err)
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+
+	var due []models.NotificationPreference
+	for _, subscriber := range subscribers {
+		since := today
+		if subscriber.EmailFrequency == models.EmailFrequencyWeekly {
+			since = monday
+		}
+		if subscriber.LastEmailedAt == nil || subscriber.LastEmailedAt.Before(since) {
+			due = append(due, subscriber)
+		}
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	dueReviews, err := n.notificationRepo.CountDueWords(ctx, today.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count due reviews: %w", err)
+	}
+	days, err := n.notificationRepo.GetStudyDays(ctx, today.AddDate(0, 0, -maxLeaderboardStreakDays))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read study days: %w", err)
+	}
+	levels, err := n.notificationRepo.ListLevels(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read levels: %w", err)
+	}
+	daysByLearner := make(map[string][]string)
+	for _, day := range days {
+		daysByLearner[day.Learner] = append(daysByLearner[day.Learner], day.Day)
+	}
+	levelByLearner := make(map[string]models.LearnerXP, len(levels))
+	for _, level := range levels {
+		levelByLearner[level.Learner] = level
+	}
+
+	sent := 0
+	for _, subscriber := range due {
+		level, ok := levelByLearner[subscriber.Learner]
+		if !ok {
+			level.Level = 1
+		}
+		reminder := mail.Reminder{
+			DueReviews:     dueReviews,
+			Streak:         studyStreak(daysByLearner[subscriber.Learner], now),
+			Level:          level.Level,
+			XP:             level.XP,
+			UnsubscribeURL: n.publicURL + "/api/v1/public/unsubscribe/" + subscriber.UnsubscribeToken,
+		}
+		if reminder.UnreadNotifications, err = n.notificationRepo.CountUnread(ctx, subscriber.Learner); err != nil {
+			return sent, fmt.Errorf("failed to count unread notifications: %w", err)
+		}
+		kind := mail.ReminderDaily
+		if subscriber.EmailFrequency == models.EmailFrequencyWeekly {
+			kind = mail.ReminderWeekly
+			week, err := n.notificationRepo.GetStudySince(ctx, subscriber.Learner, now.Add(-leaderboardWeek))
+			if err != nil {
+				return sent, fmt.Errorf("failed to read the week's study: %w", err)
+			}
+			reminder.WeekSessions, reminder.WeekReviews = week.Sessions, week.Reviews
+			reminder.WeekAccuracy = successRate(week.CorrectReviews, week.Reviews)
+		}
+
+		msg, err := mail.RenderReminder(kind, reminder)
+		if err != nil {
+			return sent, fmt.Errorf("failed to render the %s reminder: %w", kind, err)
+		}
+		msg.From, msg.To = n.mailFrom, subscriber.Email
+		if err := n.mail.Send(ctx, msg); err != nil {
+			if n.logger != nil {
+				n.logger.Printf("Failed to email the %s reminder to %q: %v", kind, subscriber.Learner, err)
+			}
+			continue
+		}
+		if err := n.notificationRepo.MarkEmailed(ctx, subscriber.Learner, now); err != nil {
+			return sent, fmt.Errorf("failed to record the reminder to %q: %w", subscriber.Learner, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/mail"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePreferenceRepository adds reminder settings to fakeNotificationRepository
+type fakePreferenceRepository struct {
+	fakeNotificationRepository
+	preferences map[string]*models.NotificationPreference
+	weeks       map[string]repository.LearnerStudyStats
+}
+
+func newFakePreferenceRepository() *fakePreferenceRepository {
+	return &fakePreferenceRepository{preferences: make(map[string]*models.NotificationPreference)}
+}
+
+func (f *fakePreferenceRepository) GetPreference(ctx context.Context, learner string) (*models.NotificationPreference, error) {
+	preference, ok := f.preferences[learner]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *preference
+	return &copied, nil
+}
+
+func (f *fakePreferenceRepository) SavePreference(ctx context.Context, preference *models.NotificationPreference) error {
+	saved := *preference
+	if existing, ok := f.preferences[preference.Learner]; ok {
+		saved.LastEmailedAt = existing.LastEmailedAt
+	}
+	f.preferences[preference.Learner] = &saved
+	return nil
+}
+
+func (f *fakePreferenceRepository) Unsubscribe(ctx context.Context, token string) error {
+	for _, preference := range f.preferences {
+		if preference.UnsubscribeToken == token {
+			preference.EmailFrequency = models.EmailFrequencyOff
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakePreferenceRepository) ListEmailSubscribers(ctx context.Context) ([]models.NotificationPreference, error) {
+	var subscribers []models.NotificationPreference
+	for _, learner := range []string{"aiko", "ken", "yuki"} {
+		if preference, ok := f.preferences[learner]; ok && preference.EmailFrequency != models.EmailFrequencyOff {
+			subscribers = append(subscribers, *preference)
+		}
+	}
+	return subscribers, nil
+}
+
+func (f *fakePreferenceRepository) MarkEmailed(ctx context.Context, learner string, at time.Time) error {
+	f.preferences[learner].LastEmailedAt = &at
+	return nil
+}
+
+func (f *fakePreferenceRepository) GetStudySince(ctx context.Context, learner string, since time.Time) (*repository.LearnerStudyStats, error) {
+	stats := f.weeks[learner]
+	return &stats, nil
+}
+
+// fakeSender records the messages it sends and fails for one address
+type fakeSender struct {
+	sent   []mail.Message
+	failTo string
+}
+
+func (f *fakeSender) Send(ctx context.Context, msg mail.Message) error {
+	if msg.To == f.failTo {
+		return mail.ErrUnavailable
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestNotificationService_Preferences(t *testing.T) {
+	repo := newFakePreferenceRepository()
+	s := NewNotificationService(NewBaseService(nil, nil, nil, nil, nil), repo)
+	ctx := context.Background()
+
+	preference, err := s.GetPreferences(ctx, "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, models.EmailFrequencyOff, preference.EmailFrequency)
+
+	for _, input := range []struct{ email, frequency string }{
+		{"", models.EmailFrequencyDaily},
+		{"not an address", models.EmailFrequencyDaily},
+		{"Yuki <yuki@example.com>", models.EmailFrequencyDaily},
+		{"yuki@example.com", "hourly"},
+	} {
+		_, err := s.UpdatePreferences(ctx, "yuki", input.email, input.frequency)
+		require.Error(t, err, input)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, input)
+	}
+
+	preference, err = s.UpdatePreferences(ctx, "yuki", " yuki@example.com ", models.EmailFrequencyWeekly)
+	require.NoError(t, err)
+	assert.Equal(t, "yuki@example.com", preference.Email)
+	assert.Len(t, preference.UnsubscribeToken, 2*unsubscribeTokenBytes)
+	token := preference.UnsubscribeToken
+
+	preference, err = s.UpdatePreferences(ctx, "yuki", "yuki@example.org", models.EmailFrequencyDaily)
+	require.NoError(t, err)
+	assert.Equal(t, token, preference.UnsubscribeToken, "the token stays valid for emails already sent")
+
+	require.NoError(t, s.Unsubscribe(ctx, token))
+	preference, err = s.GetPreferences(ctx, "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, models.EmailFrequencyOff, preference.EmailFrequency)
+	assert.Equal(t, "yuki@example.org", preference.Email)
+
+	err = s.Unsubscribe(ctx, "unknown")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
+
+func TestNotifier_SendReminders(t *testing.T) {
+	// A Wednesday morning
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)
+	monday := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	repo := newFakePreferenceRepository()
+	repo.due = 12
+	repo.days = []repository.LearnerStudyDay{{Learner: "yuki", Day: "2025-03-11"}, {Learner: "yuki", Day: "2025-03-10"}}
+	repo.levels = []models.LearnerXP{{Learner: "yuki", XP: 320, Level: 3}}
+	repo.weeks = map[string]repository.LearnerStudyStats{"ken": {Sessions: 2, Reviews: 10, CorrectReviews: 8}}
+	repo.preferences["aiko"] = &models.NotificationPreference{Learner: "aiko", Email: "aiko@example.com", EmailFrequency: models.EmailFrequencyDaily, UnsubscribeToken: "a"}
+	repo.preferences["ken"] = &models.NotificationPreference{Learner: "ken", Email: "ken@example.com", EmailFrequency: models.EmailFrequencyWeekly, UnsubscribeToken: "k"}
+	repo.preferences["yuki"] = &models.NotificationPreference{Learner: "yuki", Email: "yuki@example.com", EmailFrequency: models.EmailFrequencyDaily, UnsubscribeToken: "y"}
+	sender := &fakeSender{failTo: "aiko@example.com"}
+	n := NewNotifier(NewBaseService(nil, nil, nil, nil, nil), repo, nil).
+		WithMail(sender, "Lang Portal <noreply@example.com>", "https://portal.example.com/")
+	ctx := context.Background()
+
+	sent, err := n.SendReminders(ctx, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, sent, "reminders wait for the morning")
+
+	sent, err = n.SendReminders(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent, "aiko's email failed and is retried on the next check")
+	require.Len(t, sender.sent, 2)
+	assert.Equal(t, "ken@example.com", sender.sent[0].To)
+	assert.Equal(t, "Your week of Japanese study", sender.sent[0].Subject)
+	assert.Contains(t, sender.sent[0].Text, "Reviews: 10 (80% correct)")
+	yuki := sender.sent[1]
+	assert.Equal(t, "Lang Portal <noreply@example.com>", yuki.From)
+	assert.Equal(t, "12 reviews due today", yuki.Subject)
+	assert.Contains(t, yuki.Text, "Your study streak is 2 days")
+	assert.Contains(t, yuki.Text, "Level 3, 320 XP")
+	assert.Equal(t, "<https://portal.example.com/api/v1/public/unsubscribe/y>", yuki.Headers["List-Unsubscribe"])
+
+	sent, err = n.SendReminders(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, sent, "each reminder goes out once per day or week")
+
+	sent, err = n.SendReminders(ctx, now.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent, "yuki's daily reminder the next day; ken's weekly one waits for Monday")
+	sent, err = n.SendReminders(ctx, monday.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+}
+
+func TestNotifier_SendReminders_WithoutMail(t *testing.T) {
+	repo := newFakePreferenceRepository()
+	repo.preferences["yuki"] = &models.NotificationPreference{Learner: "yuki", Email: "yuki@example.com", EmailFrequency: models.EmailFrequencyDaily}
+	n := NewNotifier(NewBaseService(nil, nil, nil, nil, nil), repo, nil)
+
+	sent, err := n.SendReminders(context.Background(), time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+}
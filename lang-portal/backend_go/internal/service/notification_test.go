@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotificationRepository keeps notifications in memory and serves fixed study
+type fakeNotificationRepository struct {
+	repository.NotificationRepositoryInterface
+	notifications []models.Notification
+	days          []repository.LearnerStudyDay
+	due           int64
+	levels        []models.LearnerXP
+}
+
+func (f *fakeNotificationRepository) Create(ctx context.Context, notification *models.Notification) (bool, error) {
+	for _, existing := range f.notifications {
+		if existing.Recipient == notification.Recipient && existing.Key == notification.Key {
+			return false, nil
+		}
+	}
+	notification.ID = uint(len(f.notifications) + 1)
+	f.notifications = append(f.notifications, *notification)
+	return true, nil
+}
+
+func (f *fakeNotificationRepository) List(ctx context.Context, recipient string, unreadOnly bool, params repository.PaginationParams) (*repository.PaginatedResult[models.Notification], error) {
+	var items []models.Notification
+	for _, notification := range f.notifications {
+		if notification.Recipient == recipient && (!unreadOnly || notification.ReadAt == nil) {
+			items = append(items, notification)
+		}
+	}
+	return &repository.PaginatedResult[models.Notification]{Items: items, TotalItems: int64(len(items))}, nil
+}
+
+func (f *fakeNotificationRepository) CountUnread(ctx context.Context, recipient string) (int64, error) {
+	var count int64
+	for _, notification := range f.notifications {
+		if notification.Recipient == recipient && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeNotificationRepository) MarkRead(ctx context.Context, recipient string, id uint, at time.Time) (*models.Notification, error) {
+	for i := range f.notifications {
+		if f.notifications[i].ID == id && f.notifications[i].Recipient == recipient {
+			f.notifications[i].ReadAt = &at
+			return &f.notifications[i], nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeNotificationRepository) GetStudyDays(ctx context.Context, since time.Time) ([]repository.LearnerStudyDay, error) {
+	return f.days, nil
+}
+
+func (f *fakeNotificationRepository) CountDueWords(ctx context.Context, before time.Time) (int64, error) {
+	return f.due, nil
+}
+
+func (f *fakeNotificationRepository) ListLevels(ctx context.Context) ([]models.LearnerXP, error) {
+	return f.levels, nil
+}
+
+func (f *fakeNotificationRepository) titles(recipient string) []string {
+	var titles []string
+	for _, notification := range f.notifications {
+		if notification.Recipient == recipient {
+			titles = append(titles, notification.Title)
+		}
+	}
+	return titles
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	evening := time.Date(2025, 3, 10, 19, 0, 0, 0, time.UTC)
+	repo := &fakeNotificationRepository{
+		days: []repository.LearnerStudyDay{
+			{Learner: "aiko", Day: "2025-03-10"},
+			{Learner: "ken", Day: "2025-01-02"},
+			{Learner: "yuki", Day: "2025-03-09"},
+			{Learner: "yuki", Day: "2025-03-08"},
+		},
+		due:    50,
+		levels: []models.LearnerXP{{Learner: "yuki", XP: 320, Level: 3}},
+	}
+	n := NewNotifier(NewBaseService(nil, nil, nil, nil, nil), repo, nil)
+	ctx := context.Background()
+
+	created, err := n.Notify(ctx, evening)
+	require.NoError(t, err)
+	assert.Equal(t, 4, created)
+	assert.Equal(t, []string{"50 reviews due today"}, repo.titles("aiko"), "aiko studied today")
+	assert.Empty(t, repo.titles("ken"), "ken has not studied for a month")
+	assert.Equal(t, []string{
+		"50 reviews due today",
+		"Your 2-day streak is about to break",
+		"Level 3 reached",
+	}, repo.titles("yuki"))
+
+	created, err = n.Notify(ctx, evening.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, created, "nothing is notified twice")
+}
+
+func TestNotifier_Notify_BelowThresholds(t *testing.T) {
+	morning := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	repo := &fakeNotificationRepository{
+		days: []repository.LearnerStudyDay{{Learner: "yuki", Day: "2025-03-09"}},
+		due:  DueReviewsNotifyThreshold - 1,
+	}
+	n := NewNotifier(NewBaseService(nil, nil, nil, nil, nil), repo, nil)
+
+	created, err := n.Notify(context.Background(), morning)
+	require.NoError(t, err)
+	assert.Zero(t, created, "too few reviews are due and the streak is only at risk in the evening")
+}
+
+func TestNotificationService_Inbox(t *testing.T) {
+	repo := &fakeNotificationRepository{}
+	for i, recipient := range []string{"yuki", "yuki", "ken"} {
+		_, err := repo.Create(context.Background(), &models.Notification{Recipient: recipient, Key: fmt.Sprintf("level:%d", i+2), Title: "Hello"})
+		require.NoError(t, err)
+	}
+	s := NewNotificationService(NewBaseService(nil, nil, nil, nil, nil), repo)
+	ctx := context.Background()
+
+	page, err := s.ListNotifications(ctx, "yuki", false, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, int64(2), page.UnreadCount)
+
+	read, err := s.MarkRead(ctx, "yuki", page.Items[0].ID)
+	require.NoError(t, err)
+	assert.NotNil(t, read.ReadAt)
+	page, err = s.ListNotifications(ctx, "yuki", true, PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, int64(1), page.UnreadCount)
+
+	_, err = s.MarkRead(ctx, "yuki", 3)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code, "ken's notification is not yuki's")
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"lang-portal/backend_go/internal/mail"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Notifier settings. Learners who have not studied for a month are not
+// reminded of due reviews.
+const (
+	notifyInterval        = 15 * time.Minute
+	notificationRetention = 30 * 24 * time.Hour
+	activeLearnerDays     = 30
+	// DueReviewsNotifyThreshold is the fewest reviews due today that learners
+	// are reminded of
+	DueReviewsNotifyThreshold = 20
+	// StreakReminderHour is the UTC hour from which learners who have not
+	// studied today are warned that their streak is about to break
+	StreakReminderHour = 18
+)
+
+// Notifier fills the learners' inboxes from their study: reviews due today,
+// streaks about to break and levels reached. Each notification has a key, such
+// as the day of a reminder, so checking again never repeats one. With mail
+// configured it also sends the daily and weekly reminder emails learners asked
+// for.
+type Notifier struct {
+	*BaseService
+	notificationRepo repository.NotificationRepositoryInterface
+	logger           *log.Logger
+	mail             mail.Sender
+	mailFrom         string
+	publicURL        string
+}
+
+// NewNotifier creates a notifier writing to notificationRepo
+func NewNotifier(base *BaseService, notificationRepo repository.NotificationRepositoryInterface, logger *log.Logger) *Notifier {
+	return &Notifier{BaseService: base, notificationRepo: notificationRepo, logger: logger}
+}
+
+// Run checks for notifications and reminder emails at start and every 15
+// minutes until ctx is done. Notifications read over a month ago are pruned.
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(notifyInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().UTC()
+		if _, err := n.Notify(ctx, now); err != nil && ctx.Err() == nil {
+			n.logger.Printf("Notifier: %v", err)
+		}
+		if _, err := n.SendReminders(ctx, now); err != nil && ctx.Err() == nil {
+			n.logger.Printf("Reminder emails: %v", err)
+		}
+		if _, err := n.notificationRepo.PruneRead(ctx, now.Add(-notificationRetention)); err != nil && ctx.Err() == nil {
+			n.logger.Printf("Failed to prune notifications: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Notify creates the notifications due at now and returns how many are new
+func (n *Notifier) Notify(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "Notifier.Notify")
+	defer span.End()
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days, err := n.notificationRepo.GetStudyDays(ctx, today.AddDate(0, 0, -maxLeaderboardStreakDays))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read study days: %w", err)
+	}
+	due, err := n.notificationRepo.CountDueWords(ctx, today.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count due reviews: %w", err)
+	}
+	levels, err := n.notificationRepo.ListLevels(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read levels: %w", err)
+	}
+
+	var learners []string
+	daysByLearner := make(map[string][]string)
+	for _, day := range days {
+		if _, ok := daysByLearner[day.Learner]; !ok {
+			learners = append(learners, day.Learner)
+		}
+		daysByLearner[day.Learner] = append(daysByLearner[day.Learner], day.Day)
+	}
+
+	var notifications []models.Notification
+	date := today.Format("2006-01-02")
+	activeSince := today.AddDate(0, 0, -activeLearnerDays).Format("2006-01-02")
+	yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+	for _, learner := range learners {
+		studied := daysByLearner[learner]
+		if due >= DueReviewsNotifyThreshold && studied[0] >= activeSince {
+			notifications = append(notifications, models.Notification{
+				Recipient: learner,
+				Kind:      models.NotificationKindReviewsDue,
+				Key:       "reviews_due:" + date,
+				Title:     fmt.Sprintf("%d reviews due today", due),
+				Body:      "Review them today to keep your words fresh.",
+			})
+		}
+		if studied[0] == yesterday && now.Hour() >= StreakReminderHour {
+			notifications = append(notifications, models.Notification{
+				Recipient: learner,
+				Kind:      models.NotificationKindStreakAtRisk,
+				Key:       "streak_at_risk:" + date,
+				Title:     fmt.Sprintf("Your %d-day streak is about to break", studyStreak(studied, now)),
+				Body:      "Study before midnight UTC to keep it going.",
+			})
+		}
+	}
+	for _, level := range levels {
+		notifications = append(notifications, models.Notification{
+			Recipient: level.Learner,
+			Kind:      models.NotificationKindAchievement,
+			Key:       fmt.Sprintf("level:%d", level.Level),
+			Title:     fmt.Sprintf("Level %d reached", level.Level),
+			Body:      fmt.Sprintf("You have earned %d XP.", level.XP),
+		})
+	}
+
+	created := 0
+	for i := range notifications {
+		ok, err := n.notificationRepo.Create(ctx, &notifications[i])
+		if err != nil {
+			return created, fmt.Errorf("failed to notify %q: %w", notifications[i].Recipient, err)
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Review ordering strategies for session word lists
+const (
+	ReviewOrderHardestFirst = "hardest_first"
+	ReviewOrderOldestFirst  = "oldest_first"
+	ReviewOrderDueFirst     = "due_first"
+	ReviewOrderFrequency    = "frequency"
+	ReviewOrderRandom       = "random"
+)
+
+// DefaultReviewOrder is used when neither the session nor the preferences choose one
+const DefaultReviewOrder = ReviewOrderHardestFirst
+
+// unseenAccuracy ranks words without reviews between known-hard and known-easy words
+const unseenAccuracy = 0.5
+
+// ValidReviewOrder reports whether order names a known ordering strategy
+func ValidReviewOrder(order string) bool {
+	switch order {
+	case ReviewOrderHardestFirst, ReviewOrderOldestFirst, ReviewOrderDueFirst, ReviewOrderFrequency, ReviewOrderRandom:
+		return true
+	}
+	return false
+}
+
+// defaultReviewOrder returns the preferred review order, falling back to
+// DefaultReviewOrder when no preference is stored
+func (s *BaseService) defaultReviewOrder(ctx context.Context) (string, error) {
+	if s.settingRepo == nil {
+		return DefaultReviewOrder, nil
+	}
+	order, err := s.settingRepo.Get(ctx, models.SettingReviewOrder)
+	if err == repository.ErrNotFound || (err == nil && !ValidReviewOrder(order)) {
+		return DefaultReviewOrder, nil
+	}
+	if err != nil {
+		return "", NewServiceError(ErrCodeInternal, "Failed to get review order preference", err)
+	}
+	return order, nil
+}
+
+// orderWords sorts words in place according to the strategy.
+//
+//   - hardest_first: lowest accuracy average first, unseen words in the middle;
+//     ties go to the word reviewed longest ago
+//   - oldest_first: never-reviewed words first, then by last review time
+//   - due_first: earliest next due time first, never-scheduled words last in
+//     oldest_first order
+//   - frequency: most frequent first by frequency rank, words missing from the
+//     frequency list last in oldest_first order
+//   - random: shuffled with seed, so the same session always gets the same order
+func orderWords(words []models.Word, strategy string, seed int64) {
+	switch strategy {
+	case ReviewOrderRandom:
+		rnd := rand.New(rand.NewSource(seed))
+		rnd.Shuffle(len(words), func(i, j int) { words[i], words[j] = words[j], words[i] })
+	case ReviewOrderOldestFirst:
+		sort.SliceStable(words, func(i, j int) bool {
+			return reviewedBefore(words[i], words[j])
+		})
+	case ReviewOrderDueFirst:
+		sort.SliceStable(words, func(i, j int) bool {
+			return dueBefore(words[i], words[j])
+		})
+	case ReviewOrderFrequency:
+		sort.SliceStable(words, func(i, j int) bool {
+			return moreFrequent(words[i], words[j])
+		})
+	default:
+		sort.SliceStable(words, func(i, j int) bool {
+			ai, aj := accuracyOf(words[i]), accuracyOf(words[j])
+			if ai != aj {
+				return ai < aj
+			}
+			return reviewedBefore(words[i], words[j])
+		})
+	}
+}
+
+func accuracyOf(w models.Word) float64 {
+	if w.AccuracyEWMA == nil {
+		return unseenAccuracy
+	}
+	return *w.AccuracyEWMA
+}
+
+// reviewedBefore orders never-reviewed words first, then by last review time
+func reviewedBefore(a, b models.Word) bool {
+	switch {
+	case a.LastReviewedAt == nil:
+		return b.LastReviewedAt != nil
+	case b.LastReviewedAt == nil:
+		return false
+	default:
+		return a.LastReviewedAt.Before(*b.LastReviewedAt)
+	}
+}
+
+// dueBefore orders words by next due time, never-scheduled words last
+func dueBefore(a, b models.Word) bool {
+	switch {
+	case a.NextDueAt == nil && b.NextDueAt == nil:
+		return reviewedBefore(a, b)
+	case a.NextDueAt == nil:
+		return false
+	case b.NextDueAt == nil:
+		return true
+	default:
+		return a.NextDueAt.Before(*b.NextDueAt)
+	}
+}
+
+// moreFrequent orders words by frequency rank, words without a rank last
+func moreFrequent(a, b models.Word) bool {
+	switch {
+	case a.FrequencyRank == nil && b.FrequencyRank == nil:
+		return reviewedBefore(a, b)
+	case a.FrequencyRank == nil:
+		return false
+	case b.FrequencyRank == nil:
+		return true
+	default:
+		return *a.FrequencyRank < *b.FrequencyRank
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func orderingFixture() []models.Word {
+	hard, easy := 0.2, 0.9
+	old := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := old.Add(72 * time.Hour)
+	return []models.Word{
+		{ID: 1, AccuracyEWMA: &easy, LastReviewedAt: &old},
+		{ID: 2},
+		{ID: 3, AccuracyEWMA: &hard, LastReviewedAt: &recent},
+		{ID: 4, AccuracyEWMA: &hard, LastReviewedAt: &old},
+	}
+}
+
+func wordIDs(words []models.Word) []uint {
+	ids := make([]uint, len(words))
+	for i, w := range words {
+		ids[i] = w.ID
+	}
+	return ids
+}
+
+func TestOrderWords(t *testing.T) {
+	words := orderingFixture()
+	orderWords(words, ReviewOrderHardestFirst, 1)
+	assert.Equal(t, []uint{4, 3, 2, 1}, wordIDs(words))
+
+	words = orderingFixture()
+	orderWords(words, ReviewOrderOldestFirst, 1)
+	assert.Equal(t, []uint{2, 1, 4, 3}, wordIDs(words))
+
+	words = orderingFixture()
+	soon, later := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	words[0].NextDueAt, words[3].NextDueAt = &later, &soon
+	orderWords(words, ReviewOrderDueFirst, 1)
+	assert.Equal(t, []uint{4, 1, 2, 3}, wordIDs(words))
+
+	words = orderingFixture()
+	common, rare := 12, 3400
+	words[2].FrequencyRank, words[1].FrequencyRank = &rare, &common
+	orderWords(words, ReviewOrderFrequency, 1)
+	assert.Equal(t, []uint{2, 3, 1, 4}, wordIDs(words), "unranked words go last in oldest_first order")
+
+	first, second := orderingFixture(), orderingFixture()
+	orderWords(first, ReviewOrderRandom, 42)
+	orderWords(second, ReviewOrderRandom, 42)
+	assert.Equal(t, wordIDs(first), wordIDs(second), "random order must be stable per seed")
+	assert.ElementsMatch(t, []uint{1, 2, 3, 4}, wordIDs(first))
+}
+
+func TestPreferencesService_DefaultsWithoutStorage(t *testing.T) {
+	prefsService := NewPreferencesService(NewBaseService(nil, nil, nil, nil, nil))
+
+	prefs, err := prefsService.GetPreferences(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultReviewOrder, prefs.ReviewOrder)
+	assert.Equal(t, DefaultMasteredMinReviews, prefs.MasteredMinReviews)
+	assert.Equal(t, DefaultMasteredMinSuccessRate, prefs.MasteredMinSuccessRate)
+
+	err = prefsService.UpdatePreferences(context.Background(), &Preferences{ReviewOrder: "alphabetical"})
+	assert.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+
+	err = prefsService.UpdatePreferences(context.Background(), &Preferences{ReviewOrder: DefaultReviewOrder, MasteredMinSuccessRate: 1.5})
+	assert.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
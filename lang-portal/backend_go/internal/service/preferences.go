@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// PreferencesService handles persisted user preferences
+type PreferencesService struct {
+	*BaseService
+}
+
+// NewPreferencesService creates a new preferences service
+func NewPreferencesService(base *BaseService) *PreferencesService {
+	return &PreferencesService{BaseService: base}
+}
+
+// Preferences holds the persisted user preferences. Zero mastery thresholds
+// and a zero session minimum restore the defaults.
+type Preferences struct {
+	ReviewOrder            string  `json:"review_order"`
+	MasteredMinReviews     int     `json:"mastered_min_reviews"`
+	MasteredMinSuccessRate float64 `json:"mastered_min_success_rate"`
+	// MinSessionWords is how many words a group needs to start a study session
+	MinSessionWords int `json:"min_session_words"`
+}
+
+// GetPreferences returns the current preferences, with defaults for unset values
+func (s *PreferencesService) GetPreferences(ctx context.Context) (*Preferences, error) {
+	ctx, span := tracer.Start(ctx, "PreferencesService.GetPreferences")
+	defer span.End()
+
+	return s.preferences(ctx)
+}
+
+// preferences reads the preferences, with defaults for unset values
+func (s *BaseService) preferences(ctx context.Context) (*Preferences, error) {
+	order, err := s.defaultReviewOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	minReviews, minRate, err := s.masteryThresholds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	minWords, err := s.minSessionWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Preferences{
+		ReviewOrder:            order,
+		MasteredMinReviews:     minReviews,
+		MasteredMinSuccessRate: minRate,
+		MinSessionWords:        minWords,
+	}, nil
+}
+
+// UpdatePreferences validates and stores the given preferences
+func (s *PreferencesService) UpdatePreferences(ctx context.Context, prefs *Preferences) error {
+	ctx, span := tracer.Start(ctx, "PreferencesService.UpdatePreferences")
+	defer span.End()
+
+	if err := prefs.validate(); err != nil {
+		return err
+	}
+	if s.settingRepo == nil {
+		return NewServiceError(ErrCodeInternal, "Preferences storage is not configured", nil)
+	}
+
+	for key, value := range prefs.settings() {
+		if err := s.settingRepo.Set(ctx, key, value); err != nil {
+			return NewServiceError(ErrCodeInternal, "Failed to update preferences", err)
+		}
+	}
+	return nil
+}
+
+// validate checks the preferences are in range
+func (p *Preferences) validate() error {
+	if !ValidReviewOrder(p.ReviewOrder) {
+		return NewServiceError(ErrCodeInvalidInput, "Unknown review order: "+p.ReviewOrder, nil)
+	}
+	if p.MasteredMinReviews != 0 && !validMasteredMinReviews(p.MasteredMinReviews) {
+		return NewServiceError(ErrCodeInvalidInput, "mastered_min_reviews must be at least 1", nil)
+	}
+	if p.MasteredMinSuccessRate != 0 && !validMasteredMinSuccessRate(p.MasteredMinSuccessRate) {
+		return NewServiceError(ErrCodeInvalidInput, "mastered_min_success_rate must be between 0 and 1", nil)
+	}
+	if p.MinSessionWords != 0 && !validMinSessionWords(p.MinSessionWords) {
+		return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("min_session_words must be between 1 and %d", MaxBundleWords), nil)
+	}
+	return nil
+}
+
+// settings returns the preferences as stored. Stored zeros are out of range and
+// read back as the defaults.
+func (p *Preferences) settings() map[string]string {
+	return map[string]string{
+		models.SettingReviewOrder:            p.ReviewOrder,
+		models.SettingMasteredMinReviews:     strconv.Itoa(p.MasteredMinReviews),
+		models.SettingMasteredMinSuccessRate: strconv.FormatFloat(p.MasteredMinSuccessRate, 'f', -1, 64),
+		models.SettingMinSessionWords:        strconv.Itoa(p.MinSessionWords),
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Progress history intervals
+const (
+	ProgressIntervalDay   = repository.ProgressIntervalDay
+	ProgressIntervalWeek  = repository.ProgressIntervalWeek
+	ProgressIntervalMonth = repository.ProgressIntervalMonth
+)
+
+// MaxProgressPeriods caps the length of a progress history series
+const MaxProgressPeriods = 366
+
+// defaultProgressPeriods is the series length per interval when none is given
+var defaultProgressPeriods = map[string]int{
+	ProgressIntervalDay:   30,
+	ProgressIntervalWeek:  12,
+	ProgressIntervalMonth: 12,
+}
+
+// ProgressPoint is the study activity of one period
+type ProgressPoint struct {
+	// Start is the UTC date the period starts on, as YYYY-MM-DD
+	Start          string `json:"start"`
+	Reviews        int64  `json:"reviews"`
+	CorrectReviews int64  `json:"correct_reviews"`
+	// Accuracy is the percentage of correct reviews, 0 without reviews
+	Accuracy     float64 `json:"accuracy"`
+	WordsLearned int64   `json:"words_learned"`
+	// ActiveMinutes counts the minutes with at least one review
+	ActiveMinutes int64 `json:"active_minutes"`
+}
+
+// ProgressHistory is a series of consecutive periods, oldest first, ending with
+// the current one
+type ProgressHistory struct {
+	Interval string          `json:"interval"`
+	Points   []ProgressPoint `json:"points"`
+}
+
+// GetProgressHistory returns the study activity of the last periods periods of
+// interval. Periods without activity are included with zero values. A
+// non-positive periods uses the interval's default.
+func (s *DashboardService) GetProgressHistory(ctx context.Context, interval string, periods int) (*ProgressHistory, error) {
+	ctx, span := tracer.Start(ctx, "DashboardService.GetProgressHistory")
+	defer span.End()
+
+	if interval == "" {
+		interval = ProgressIntervalDay
+	}
+	defaultPeriods, ok := defaultProgressPeriods[interval]
+	if !ok {
+		return nil, NewServiceError(ErrCodeInvalidInput, "interval must be day, week or month", nil)
+	}
+	if periods <= 0 {
+		periods = defaultPeriods
+	}
+	if periods > MaxProgressPeriods {
+		return nil, NewServiceError(ErrCodeInvalidInput, "periods must be at most 366", nil)
+	}
+
+	starts := progressPeriodStarts(interval, periods, time.Now().UTC())
+	buckets, err := s.studyRepo.GetProgressHistory(ctx, interval, starts[0])
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get progress history", err)
+	}
+	byPeriod := make(map[string]repository.ProgressBucket, len(buckets))
+	for _, bucket := range buckets {
+		byPeriod[bucket.Period] = bucket
+	}
+
+	points := make([]ProgressPoint, len(starts))
+	for i, start := range starts {
+		bucket := byPeriod[start.Format("2006-01-02")]
+		points[i] = ProgressPoint{
+			Start:          start.Format("2006-01-02"),
+			Reviews:        bucket.Reviews,
+			CorrectReviews: bucket.CorrectReviews,
+			WordsLearned:   bucket.WordsLearned,
+			ActiveMinutes:  bucket.ActiveMinutes,
+		}
+		if bucket.Reviews > 0 {
+			points[i].Accuracy = float64(bucket.CorrectReviews) / float64(bucket.Reviews) * 100
+		}
+	}
+	return &ProgressHistory{Interval: interval, Points: points}, nil
+}
+
+// progressPeriodStarts returns the start dates of the periods consecutive
+// periods of interval ending with the one containing now, oldest first
+func progressPeriodStarts(interval string, periods int, now time.Time) []time.Time {
+	current := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	step := func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) }
+	switch interval {
+	case ProgressIntervalWeek:
+		// Weeks start on Monday
+		current = current.AddDate(0, 0, -(int(current.Weekday())+6)%7)
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) }
+	case ProgressIntervalMonth:
+		current = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, n, 0) }
+	}
+
+	starts := make([]time.Time, periods)
+	for i := range starts {
+		starts[i] = step(current, i-periods+1)
+	}
+	return starts
+}
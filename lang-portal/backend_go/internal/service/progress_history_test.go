@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressPeriodStarts(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2025, 3, 5, 18, 30, 0, 0, time.UTC)
+	format := func(starts []time.Time) []string {
+		dates := make([]string, len(starts))
+		for i, start := range starts {
+			dates[i] = start.Format("2006-01-02")
+		}
+		return dates
+	}
+
+	assert.Equal(t, []string{"2025-03-03", "2025-03-04", "2025-03-05"}, format(progressPeriodStarts(ProgressIntervalDay, 3, now)))
+	assert.Equal(t, []string{"2025-02-24", "2025-03-03"}, format(progressPeriodStarts(ProgressIntervalWeek, 2, now)))
+	assert.Equal(t, []string{"2024-12-01", "2025-01-01", "2025-02-01", "2025-03-01"}, format(progressPeriodStarts(ProgressIntervalMonth, 4, now)))
+
+	// A Sunday belongs to the week starting the Monday before
+	sunday := time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"2025-03-03"}, format(progressPeriodStarts(ProgressIntervalWeek, 1, sunday)))
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Names of the prompts of the AI features
+const (
+	PromptTutor              = "tutor"
+	PromptSentenceGeneration = "sentence_generation"
+	PromptWordExplanation    = "word_explanation"
+)
+
+// maxPromptLength caps a prompt template, in characters
+const maxPromptLength = 10000
+
+// PromptVariable is a {{variable}} a prompt may use. Required variables must
+// appear in every version of the prompt.
+type PromptVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// promptDefinition describes a prompt of a feature: the variables the feature
+// fills in and the prompt used until an admin edits it
+type promptDefinition struct {
+	description string
+	variables   []PromptVariable
+	body        string
+}
+
+// levelVariable is the learner's level, filled in for every prompt
+var levelVariable = PromptVariable{Name: "level", Description: "The learner's level: beginner, intermediate or advanced"}
+
+// wordVariables describe the word a prompt is about
+var wordVariables = []PromptVariable{
+	{Name: "word", Description: "The word in Japanese", Required: true},
+	{Name: "reading", Description: "The word's reading in romaji"},
+	{Name: "meaning", Description: "The word's English meaning"},
+	levelVariable,
+}
+
+// promptDefinitions are the prompts of the AI features by name
+var promptDefinitions = map[string]promptDefinition{
+	PromptTutor: {
+		description: "System prompt of tutor conversations",
+		variables: []PromptVariable{
+			levelVariable,
+			{Name: "weak_words", Description: "The learner's recent mistakes and weakest words when the conversation started, one per line"},
+		},
+		body: `You are a friendly Japanese tutor for an English-speaking learner at {{level}} level.
+Answer in English, writing Japanese with its romaji reading the first time it appears.
+Keep replies short and practical, and end with a small exercise when it fits.
+When the learner writes Japanese, point out mistakes gently and give the corrected sentence.
+
+The learner has been struggling with these words; use them in examples where natural:
+{{weak_words}}`,
+	},
+	PromptSentenceGeneration: {
+		description: "Request for a new example sentence for a word",
+		variables:   wordVariables,
+		body: `Write one short, natural Japanese example sentence using {{word}} ({{reading}}, "{{meaning}}") that {{level}} learners can understand.
+Use the word as written, in its dictionary form or a common conjugation.`,
+	},
+	PromptWordExplanation: {
+		description: "Request for an explanation of how a word is used",
+		variables:   wordVariables,
+		body: `Explain the Japanese word {{word}} ({{reading}}, "{{meaning}}") to {{level}} learners: how it is used, common phrases it appears in, and words it is easily confused with.
+Write Japanese with its romaji reading and keep the explanation under 150 words.`,
+	},
+}
+
+// Prompt is the version of a prompt in use. Version 0 is the built-in prompt,
+// used until the prompt is edited.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Variables   []PromptVariable `json:"variables"`
+	Body        string           `json:"body"`
+	Version     int              `json:"version"`
+	Author      string           `json:"author,omitempty"`
+	UpdatedAt   *time.Time       `json:"updated_at,omitempty"`
+}
+
+// PromptHistory is a prompt with its built-in body and every edited version,
+// newest first
+type PromptHistory struct {
+	Prompt
+	Default  string                  `json:"default"`
+	Versions []models.PromptTemplate `json:"versions"`
+}
+
+// PromptService manages the prompts of the AI features, so they can be tuned
+// without a release
+type PromptService struct {
+	*BaseService
+}
+
+// NewPromptService creates a new prompt service
+func NewPromptService(base *BaseService) *PromptService {
+	return &PromptService{BaseService: base}
+}
+
+// WithPrompts reads the prompts of the AI features from repo, where admins
+// edit them. Without it the built-in prompts are used.
+func (s *BaseService) WithPrompts(repo repository.PromptRepositoryInterface) *BaseService {
+	s.prompts = repo
+	return s
+}
+
+// ListPrompts returns the prompts in use, by name
+func (s *PromptService) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	ctx, span := tracer.Start(ctx, "PromptService.ListPrompts")
+	defer span.End()
+
+	names := make([]string, 0, len(promptDefinitions))
+	for name := range promptDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prompts := make([]Prompt, len(names))
+	for i, name := range names {
+		prompt, err := s.currentPrompt(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		prompts[i] = *prompt
+	}
+	return prompts, nil
+}
+
+// GetPrompt returns a prompt with its versions
+func (s *PromptService) GetPrompt(ctx context.Context, name string) (*PromptHistory, error) {
+	ctx, span := tracer.Start(ctx, "PromptService.GetPrompt")
+	defer span.End()
+
+	prompt, err := s.currentPrompt(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	history := &PromptHistory{Prompt: *prompt, Default: promptDefinitions[name].body, Versions: []models.PromptTemplate{}}
+	if s.prompts != nil {
+		versions, err := s.prompts.ListVersions(ctx, name)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to list prompt versions", err)
+		}
+		history.Versions = append(history.Versions, versions...)
+	}
+	return history, nil
+}
+
+// UpdatePrompt stores body as the next version of a prompt. The body may only
+// use the prompt's variables and must use its required ones.
+func (s *PromptService) UpdatePrompt(ctx context.Context, name, body, author string) (*Prompt, error) {
+	ctx, span := tracer.Start(ctx, "PromptService.UpdatePrompt")
+	defer span.End()
+
+	definition, ok := promptDefinitions[name]
+	if !ok {
+		return nil, NewServiceError(ErrCodeNotFound, "Prompt not found", nil)
+	}
+	if err := definition.validate(body); err != nil {
+		return nil, err
+	}
+	return s.addVersion(ctx, name, body, author)
+}
+
+// RestorePromptVersion makes an earlier version of a prompt current again by
+// storing it as the next version. Version 0 restores the built-in prompt.
+func (s *PromptService) RestorePromptVersion(ctx context.Context, name string, version int, author string) (*Prompt, error) {
+	ctx, span := tracer.Start(ctx, "PromptService.RestorePromptVersion")
+	defer span.End()
+
+	definition, ok := promptDefinitions[name]
+	if !ok {
+		return nil, NewServiceError(ErrCodeNotFound, "Prompt not found", nil)
+	}
+	body := definition.body
+	if version != 0 {
+		if s.prompts == nil {
+			return nil, NewServiceError(ErrCodeNotFound, "Prompt version not found", nil)
+		}
+		template, err := s.prompts.GetVersion(ctx, name, version)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				return nil, NewServiceError(ErrCodeNotFound, "Prompt version not found", err)
+			}
+			return nil, NewServiceError(ErrCodeInternal, "Failed to fetch prompt version", err)
+		}
+		body = template.Body
+	}
+	return s.addVersion(ctx, name, body, author)
+}
+
+// addVersion stores the next version of a prompt
+func (s *PromptService) addVersion(ctx context.Context, name, body, author string) (*Prompt, error) {
+	if s.prompts == nil {
+		return nil, NewServiceError(ErrCodeInternal, "Prompt storage is not configured", nil)
+	}
+	template := &models.PromptTemplate{Name: name, Body: body, Author: author}
+	if err := s.prompts.AddVersion(ctx, template); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to save prompt", err)
+	}
+	return promptDefinitions[name].prompt(name, template), nil
+}
+
+// currentPrompt returns the version of a prompt in use
+func (s *BaseService) currentPrompt(ctx context.Context, name string) (*Prompt, error) {
+	definition, ok := promptDefinitions[name]
+	if !ok {
+		return nil, NewServiceError(ErrCodeNotFound, "Prompt not found", nil)
+	}
+	if s.prompts == nil {
+		return definition.prompt(name, nil), nil
+	}
+	template, err := s.prompts.GetLatest(ctx, name)
+	switch {
+	case err == repository.ErrNotFound:
+		return definition.prompt(name, nil), nil
+	case err != nil:
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch prompt", err)
+	}
+	return definition.prompt(name, template), nil
+}
+
+// renderPrompt fills in the current version of a prompt with values
+func (s *BaseService) renderPrompt(ctx context.Context, name string, values map[string]string) (string, error) {
+	prompt, err := s.currentPrompt(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := llm.Render(prompt.Body, values)
+	if err != nil {
+		return "", NewServiceError(ErrCodeInternal, "Failed to render prompt "+name, err)
+	}
+	return rendered, nil
+}
+
+// validate checks a new body of the prompt uses only its variables, and all
+// of its required ones
+func (d promptDefinition) validate(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return NewServiceError(ErrCodeInvalidInput, "Prompt is empty", nil)
+	}
+	if utf8.RuneCountInString(body) > maxPromptLength {
+		return NewServiceError(ErrCodeInvalidInput, "Prompt is too long", nil)
+	}
+
+	known := make(map[string]bool, len(d.variables))
+	for _, variable := range d.variables {
+		known[variable.Name] = true
+	}
+	used := make(map[string]bool)
+	var unknown []string
+	for _, name := range llm.Variables(body) {
+		used[name] = true
+		if !known[name] {
+			unknown = append(unknown, "{{"+name+"}}")
+		}
+	}
+	if len(unknown) > 0 {
+		return NewServiceError(ErrCodeInvalidInput, "Unknown variables: "+strings.Join(unknown, ", "), nil)
+	}
+	var missing []string
+	for _, variable := range d.variables {
+		if variable.Required && !used[variable.Name] {
+			missing = append(missing, "{{"+variable.Name+"}}")
+		}
+	}
+	if len(missing) > 0 {
+		return NewServiceError(ErrCodeInvalidInput, "Missing required variables: "+strings.Join(missing, ", "), nil)
+	}
+	return nil
+}
+
+// prompt describes the prompt with template as its current version, or the
+// built-in body when template is nil
+func (d promptDefinition) prompt(name string, template *models.PromptTemplate) *Prompt {
+	prompt := &Prompt{Name: name, Description: d.description, Variables: d.variables, Body: d.body}
+	if template != nil {
+		prompt.Body = template.Body
+		prompt.Version = template.Version
+		prompt.Author = template.Author
+		prompt.UpdatedAt = &template.CreatedAt
+	}
+	return prompt
+}
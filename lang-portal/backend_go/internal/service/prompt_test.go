@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPromptRepository is a mock implementation of PromptRepositoryInterface
+type mockPromptRepository struct {
+	mock.Mock
+}
+
+func (m *mockPromptRepository) GetLatest(ctx context.Context, name string) (*models.PromptTemplate, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PromptTemplate), args.Error(1)
+}
+
+func (m *mockPromptRepository) GetVersion(ctx context.Context, name string, version int) (*models.PromptTemplate, error) {
+	args := m.Called(name, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PromptTemplate), args.Error(1)
+}
+
+func (m *mockPromptRepository) ListVersions(ctx context.Context, name string) ([]models.PromptTemplate, error) {
+	args := m.Called(name)
+	return args.Get(0).([]models.PromptTemplate), args.Error(1)
+}
+
+func (m *mockPromptRepository) AddVersion(ctx context.Context, template *models.PromptTemplate) error {
+	template.Version = 3
+	return m.Called(template).Error(0)
+}
+
+func TestPromptDefinitions_ValidDefaults(t *testing.T) {
+	for name, definition := range promptDefinitions {
+		assert.NoError(t, definition.validate(definition.body), name)
+	}
+}
+
+func TestPromptService_UpdatePrompt_Validates(t *testing.T) {
+	s := NewPromptService(NewBaseService(nil, nil, nil, nil, nil).WithPrompts(new(mockPromptRepository)))
+	ctx := context.Background()
+
+	for body, message := range map[string]string{
+		"   ":                               "Prompt is empty",
+		"Use {{word}} at {{grade}}":         "Unknown variables: {{grade}}",
+		"Explain {{meaning}} for {{level}}": "Missing required variables: {{word}}",
+	} {
+		_, err := s.UpdatePrompt(ctx, PromptWordExplanation, body, "admin")
+		require.Error(t, err, body)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, body)
+		assert.Equal(t, message, err.(*ServiceError).Message, body)
+	}
+
+	_, err := s.UpdatePrompt(ctx, "unknown", "{{word}}", "admin")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
+
+func TestPromptService_Versions(t *testing.T) {
+	prompts := new(mockPromptRepository)
+	base := NewBaseService(nil, nil, nil, nil, nil).WithPrompts(prompts)
+	s := NewPromptService(base)
+	ctx := context.Background()
+
+	// Until it is edited, a prompt is the built-in one
+	prompts.On("GetLatest", PromptWordExplanation).Return(nil, repository.ErrNotFound).Once()
+	rendered, err := base.renderPrompt(ctx, PromptWordExplanation, map[string]string{"word": "食べる", "reading": "taberu", "meaning": "to eat", "level": "beginner"})
+	require.NoError(t, err)
+	assert.Contains(t, rendered, `食べる (taberu, "to eat") to beginner learners`)
+
+	prompts.On("AddVersion", mock.MatchedBy(func(t *models.PromptTemplate) bool {
+		return t.Name == PromptWordExplanation && t.Body == "Explain {{ word }}." && t.Author == "admin"
+	})).Return(nil).Once()
+	prompt, err := s.UpdatePrompt(ctx, PromptWordExplanation, "Explain {{ word }}.", "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 3, prompt.Version)
+	assert.Equal(t, "admin", prompt.Author)
+
+	prompts.On("GetLatest", PromptWordExplanation).Return(&models.PromptTemplate{Name: PromptWordExplanation, Version: 3, Body: "Explain {{ word }}."}, nil).Once()
+	rendered, err = base.renderPrompt(ctx, PromptWordExplanation, map[string]string{"word": "食べる"})
+	require.NoError(t, err)
+	assert.Equal(t, "Explain 食べる.", rendered)
+
+	// Restoring version 0 brings back the built-in prompt as a new version
+	prompts.On("AddVersion", mock.MatchedBy(func(t *models.PromptTemplate) bool {
+		return t.Body == promptDefinitions[PromptWordExplanation].body
+	})).Return(nil).Once()
+	_, err = s.RestorePromptVersion(ctx, PromptWordExplanation, 0, "admin")
+	require.NoError(t, err)
+
+	prompts.On("GetVersion", PromptWordExplanation, 9).Return(nil, repository.ErrNotFound).Once()
+	_, err = s.RestorePromptVersion(ctx, PromptWordExplanation, 9, "admin")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+	prompts.AssertExpectations(t)
+}
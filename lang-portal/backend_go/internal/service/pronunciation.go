@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+	"lang-portal/backend_go/internal/speech"
+)
+
+// SpeakingActivityName is the study activity pronunciation checks are recorded
+// under when no session is given. It is created on the first check.
+const SpeakingActivityName = "Speaking Practice"
+
+// speakingActivity is the study activity pronunciation checks are recorded under
+var speakingActivity = models.StudyActivity{
+	Name:         SpeakingActivityName,
+	Description:  "Say a word aloud and have its pronunciation checked",
+	ThumbnailURL: "/thumbnails/speaking-practice.png",
+	Modes:        models.StringSlice{"speaking"},
+	Capabilities: models.StringSlice{"microphone"},
+}
+
+// Pronunciation scores at or above which a spoken word is graded
+const (
+	// pronunciationClear is graded good; anything less that passes is hard
+	pronunciationClear = 1.0
+	// pronunciationPass is the lowest score counted as correct
+	pronunciationPass = 0.75
+)
+
+// MaxRecordingSize is the default cap on the size of a recording sent for a
+// pronunciation check
+const MaxRecordingSize = 10 << 20
+
+// PronunciationCheck is a recording of a word spoken aloud. The review is
+// recorded in SessionID, or in a new session of the speaking activity for
+// GroupID when no session is given.
+type PronunciationCheck struct {
+	WordID    uint
+	SessionID uint
+	GroupID   uint
+	// ContentType is the format of the recording, such as audio/webm
+	ContentType string
+	Client      models.ClientInfo
+	// Learner starts the speaking session when none is given
+	Learner string
+}
+
+// PronunciationResult reports how closely the transcript of a recording
+// matches the word. Score runs from 0 (nothing alike) to 1 (identical);
+// Expected is the written form or reading the transcript came closest to.
+type PronunciationResult struct {
+	WordID     uint               `json:"word_id"`
+	SessionID  uint               `json:"session_id"`
+	ReviewID   uint               `json:"review_id"`
+	Transcript string             `json:"transcript"`
+	Expected   string             `json:"expected"`
+	Score      float64            `json:"score"`
+	Correct    bool               `json:"correct"`
+	Grade      models.ReviewGrade `json:"grade"`
+}
+
+// WithSpeechRecognizer transcribes pronunciation checks with r. Without one,
+// pronunciation checks are not available.
+func (s *StudyService) WithSpeechRecognizer(r speech.Recognizer) *StudyService {
+	s.recognizer = r
+	return s
+}
+
+// CheckPronunciation transcribes a recording of a word, scores the transcript
+// against the word's written form and reading and records the result as a
+// review: a perfect match is graded good, a score of at least 0.75 hard and
+// anything else again.
+func (s *StudyService) CheckPronunciation(ctx context.Context, check PronunciationCheck, audio []byte) (*PronunciationResult, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.CheckPronunciation")
+	defer span.End()
+
+	if s.recognizer == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Pronunciation checks are not available", nil)
+	}
+	if len(audio) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Recording is empty", nil)
+	}
+	if _, ok := speech.Extension(check.ContentType); !ok {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Unsupported recording type: "+check.ContentType, nil)
+	}
+	if check.SessionID == 0 && check.GroupID == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "session_id or group_id is required", nil)
+	}
+
+	word, err := s.wordRepo.GetByID(ctx, check.WordID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Word not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
+	}
+	if check.SessionID != 0 {
+		if _, err := s.studyRepo.GetStudySessionByID(ctx, check.SessionID); err != nil {
+			if err == repository.ErrNotFound {
+				return nil, NewServiceError(ErrCodeNotFound, "Study session not found", err)
+			}
+			return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
+		}
+	} else if _, err := s.groupRepo.GetByID(ctx, check.GroupID); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+
+	transcript, err := s.recognizer.Transcribe(ctx, audio, check.ContentType)
+	if err != nil {
+		if errors.Is(err, speech.ErrUnavailable) {
+			return nil, NewServiceError(ErrCodeInternal, "Speech recognition is unavailable", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to transcribe recording", err)
+	}
+
+	// Start the session only once there is a review to record in it
+	sessionID := check.SessionID
+	if sessionID == 0 {
+		activity, err := s.builtinActivity(ctx, speakingActivity)
+		if err != nil {
+			return nil, err
+		}
+		session := &models.StudySession{GroupID: check.GroupID, StudyActivityID: activity.ID, Learner: check.Learner, Client: check.Client}
+		if err := s.CreateStudySession(ctx, session); err != nil {
+			return nil, err
+		}
+		sessionID = session.ID
+	}
+
+	result := scorePronunciation(transcript, word)
+	result.WordID, result.SessionID = word.ID, sessionID
+	review := &models.WordReview{WordID: word.ID, Grade: result.Grade}
+	if err := s.AddWordReview(ctx, sessionID, review); err != nil {
+		return nil, err
+	}
+	result.ReviewID = review.ID
+	return result, nil
+}
+
+// scorePronunciation compares a transcript with the written form of a word and
+// with its reading, in any romanization or kana, and grades the best match
+func scorePronunciation(transcript string, word *models.Word) *PronunciationResult {
+	result := &PronunciationResult{Transcript: transcript, Expected: word.Term, Grade: models.GradeAgain}
+	spoken := spokenText(transcript)
+	if spoken == "" {
+		return result
+	}
+
+	candidates := []struct{ text, key, spoken string }{
+		{word.Term, spokenText(word.Term), spoken},
+		{word.Reading, jpn.NormalizeRomaji(spokenText(word.Reading)), jpn.NormalizeRomaji(spoken)},
+		{word.Term, jpn.NormalizeRomaji(spokenText(word.Term)), jpn.NormalizeRomaji(spoken)},
+	}
+	for _, c := range candidates {
+		if c.key == "" {
+			continue
+		}
+		if score := similarity(c.spoken, c.key); score > result.Score {
+			result.Score, result.Expected = score, c.text
+		}
+	}
+
+	switch {
+	case result.Score >= pronunciationClear:
+		result.Correct, result.Grade = true, models.GradeGood
+	case result.Score >= pronunciationPass:
+		result.Correct, result.Grade = true, models.GradeHard
+	}
+	return result
+}
+
+// spokenText lower-cases a transcript and drops the punctuation and spacing
+// speech recognizers add
+func spokenText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, text)
+}
+
+// similarity is one minus the edit distance of a and b relative to the longer
+// of the two, rounded to two decimals
+func similarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 0
+	}
+	score := 1 - float64(editDistance(a, b))/float64(longest)
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticRecognizer hears the same transcript in every recording
+type staticRecognizer string
+
+func (r staticRecognizer) Transcribe(ctx context.Context, audio []byte, contentType string) (string, error) {
+	return string(r), nil
+}
+
+func TestScorePronunciation(t *testing.T) {
+	word := &models.Word{Term: "食べる", Reading: "taberu"}
+
+	tests := []struct {
+		transcript string
+		expected   string
+		score      float64
+		grade      models.ReviewGrade
+	}{
+		{"食べる。", "食べる", 1, models.GradeGood},
+		{"たべる", "taberu", 1, models.GradeGood},
+		{"Taberu", "taberu", 1, models.GradeGood},
+		{"たべろ", "taberu", 0.83, models.GradeHard},
+		{"のむ", "食べる", 0.2, models.GradeAgain},
+		{"。", "食べる", 0, models.GradeAgain},
+	}
+	for _, tt := range tests {
+		result := scorePronunciation(tt.transcript, word)
+		assert.Equal(t, tt.transcript, result.Transcript)
+		assert.Equal(t, tt.expected, result.Expected, tt.transcript)
+		assert.Equal(t, tt.score, result.Score, tt.transcript)
+		assert.Equal(t, tt.grade, result.Grade, tt.transcript)
+		assert.Equal(t, tt.grade.Correct(), result.Correct, tt.transcript)
+	}
+}
+
+func TestStudyService_CheckPronunciation_Invalid(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	base := NewBaseService(mockRepo, nil, nil, nil, nil)
+	ctx := context.Background()
+	check := PronunciationCheck{WordID: 1, SessionID: 2, ContentType: "audio/webm"}
+
+	_, err := NewStudyService(base).CheckPronunciation(ctx, check, []byte("RIFF"))
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code, "checks need a recognizer")
+
+	s := NewStudyService(base).WithSpeechRecognizer(staticRecognizer("たべる"))
+	for name, tc := range map[string]struct {
+		check PronunciationCheck
+		audio []byte
+	}{
+		"empty recording":  {check, nil},
+		"unsupported type": {PronunciationCheck{WordID: 1, SessionID: 2, ContentType: "text/plain"}, []byte("RIFF")},
+		"no session/group": {PronunciationCheck{WordID: 1, ContentType: "audio/webm"}, []byte("RIFF")},
+	} {
+		_, err := s.CheckPronunciation(ctx, tc.check, tc.audio)
+		require.Error(t, err, name)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, name)
+	}
+
+	mockRepo.On("GetByID", uint(1)).Return(nil, repository.ErrNotFound)
+	_, err = s.CheckPronunciation(ctx, check, []byte("RIFF"))
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+	mockRepo.AssertExpectations(t)
+}
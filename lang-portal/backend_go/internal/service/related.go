@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"lang-portal/backend_go/internal/embeddings"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Related word list sizes
+const (
+	DefaultRelatedWords = 10
+	MaxRelatedWords     = 50
+)
+
+// embeddingBatchSize caps the words embedded per provider request
+const embeddingBatchSize = 100
+
+// RelatedWord is a word similar in meaning to another, such as a word easily
+// confused with it. Similarity is the cosine similarity of their embeddings.
+type RelatedWord struct {
+	ID         uint    `json:"id"`
+	Japanese   string  `json:"japanese"`
+	Romaji     string  `json:"romaji"`
+	English    string  `json:"english"`
+	Similarity float64 `json:"similarity"`
+}
+
+// WithEmbeddings embeds words with provider to find related words. Without a
+// provider there are no related words and quizzes pick distractors at random.
+func (s *BaseService) WithEmbeddings(provider embeddings.Provider) *BaseService {
+	s.embeddings = provider
+	return s
+}
+
+// GetRelatedWords returns up to limit words of the word list closest in meaning
+// to a word, most similar first. Words are embedded on first use and again
+// after they are edited.
+func (s *WordService) GetRelatedWords(ctx context.Context, id uint, limit int) ([]RelatedWord, error) {
+	ctx, span := tracer.Start(ctx, "WordService.GetRelatedWords")
+	defer span.End()
+
+	if s.embeddings == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Related words are not available", nil)
+	}
+	if limit <= 0 {
+		limit = DefaultRelatedWords
+	}
+	if limit > MaxRelatedWords {
+		limit = MaxRelatedWords
+	}
+
+	word, err := s.wordRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Word not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
+	}
+	words, err := s.wordRepo.FindWords(ctx, repository.WordFilter{})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch words", err)
+	}
+	vectors, err := s.wordVectors(ctx, words)
+	if err != nil {
+		return nil, err
+	}
+
+	target, ok := vectors[word.ID]
+	if !ok {
+		// Created after the words were listed
+		return []RelatedWord{}, nil
+	}
+	related := make([]RelatedWord, 0, len(words))
+	for _, w := range words {
+		vector, ok := vectors[w.ID]
+		if w.ID == word.ID || !ok {
+			continue
+		}
+		related = append(related, RelatedWord{
+			ID:         w.ID,
+			Japanese:   w.Term,
+			Romaji:     w.Reading,
+			English:    w.English,
+			Similarity: embeddings.Cosine(target, vector),
+		})
+	}
+	sort.SliceStable(related, func(i, j int) bool { return related[i].Similarity > related[j].Similarity })
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+// wordVectors returns the embeddings of words by word ID, embedding the words
+// without a current vector and storing their vectors
+func (s *BaseService) wordVectors(ctx context.Context, words []models.Word) (map[uint][]float32, error) {
+	model := s.embeddings.Model()
+	stored, err := s.wordRepo.GetWordVectors(ctx, model)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch word embeddings", err)
+	}
+	byWord := make(map[uint]models.WordVector, len(stored))
+	for _, vector := range stored {
+		byWord[vector.WordID] = vector
+	}
+
+	vectors := make(map[uint][]float32, len(words))
+	var missing []models.WordVector
+	for _, word := range words {
+		source := embeddingSource(&word)
+		if stored, ok := byWord[word.ID]; ok && stored.Source == source {
+			if vector, err := embeddings.Decode(stored.Vector); err == nil {
+				vectors[word.ID] = vector
+				continue
+			}
+		}
+		missing = append(missing, models.WordVector{WordID: word.ID, Model: model, Source: source})
+	}
+
+	for start := 0; start < len(missing); start += embeddingBatchSize {
+		batch := missing[start:min(start+embeddingBatchSize, len(missing))]
+		texts := make([]string, len(batch))
+		for i, vector := range batch {
+			texts[i] = vector.Source
+		}
+		embedded, err := s.embeddings.Embed(ctx, texts)
+		if err != nil {
+			if errors.Is(err, embeddings.ErrUnavailable) {
+				return nil, NewServiceError(ErrCodeInternal, "Embedding provider is unavailable", err)
+			}
+			return nil, NewServiceError(ErrCodeInternal, "Failed to embed words", err)
+		}
+		for i := range batch {
+			batch[i].Dimensions = len(embedded[i])
+			batch[i].Vector = embeddings.Encode(embedded[i])
+			vectors[batch[i].WordID] = embedded[i]
+		}
+		if err := s.wordRepo.SaveWordVectors(ctx, batch); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to store word embeddings", err)
+		}
+	}
+	return vectors, nil
+}
+
+// embeddingSource is the text a word is embedded as: its Japanese, reading
+// and meaning, so that words close in either sound or meaning are related
+func embeddingSource(word *models.Word) string {
+	return word.Term + " (" + word.Reading + "): " + word.English
+}
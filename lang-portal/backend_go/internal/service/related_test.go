@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"lang-portal/backend_go/internal/embeddings"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// keywordEmbeddings embeds texts by the keywords they contain, one dimension
+// per keyword, and records what it embedded
+type keywordEmbeddings struct {
+	keywords []string
+	embedded []string
+}
+
+func (e *keywordEmbeddings) Model() string { return "keywords" }
+
+func (e *keywordEmbeddings) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(e.keywords))
+		for k, keyword := range e.keywords {
+			if strings.Contains(text, keyword) {
+				vectors[i][k] = 1
+			}
+		}
+	}
+	e.embedded = append(e.embedded, texts...)
+	return vectors, nil
+}
+
+func TestWordService_GetRelatedWords(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	provider := &keywordEmbeddings{keywords: []string{"animal", "pet", "food"}}
+	s := NewWordService(NewBaseService(mockRepo, nil, nil, nil, nil).WithEmbeddings(provider))
+
+	words := []models.Word{
+		{ID: 1, Term: "猫", Reading: "neko", English: "cat (animal, pet)"},
+		{ID: 2, Term: "犬", Reading: "inu", English: "dog (animal, pet)"},
+		{ID: 3, Term: "鳥", Reading: "tori", English: "bird (animal)"},
+		{ID: 4, Term: "パン", Reading: "pan", English: "bread (food)"},
+	}
+	mockRepo.On("GetByID", uint(1)).Return(&words[0], nil)
+	mockRepo.On("FindWords", repository.WordFilter{}).Return(words, nil)
+	// The cat's vector is current; the dog's was made for an older meaning
+	mockRepo.On("GetWordVectors", "keywords").Return([]models.WordVector{
+		{WordID: 1, Model: "keywords", Source: embeddingSource(&words[0]), Vector: embeddings.Encode([]float32{1, 1, 0})},
+		{WordID: 2, Model: "keywords", Source: "犬 (inu): dog", Vector: embeddings.Encode([]float32{0, 0, 1})},
+	}, nil)
+	mockRepo.On("SaveWordVectors", mock.MatchedBy(func(vectors []models.WordVector) bool {
+		return len(vectors) == 3 && vectors[0].WordID == 2 && vectors[0].Dimensions == 3
+	})).Return(nil)
+
+	related, err := s.GetRelatedWords(context.Background(), 1, 2)
+	require.NoError(t, err)
+	require.Len(t, related, 2)
+	assert.Equal(t, uint(2), related[0].ID)
+	assert.InDelta(t, 1, related[0].Similarity, 1e-9)
+	assert.Equal(t, uint(3), related[1].ID)
+	assert.Len(t, provider.embedded, 3, "only stale and missing words are embedded")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWordService_GetRelatedWords_Unavailable(t *testing.T) {
+	s := NewWordService(NewBaseService(nil, nil, nil, nil, nil))
+	_, err := s.GetRelatedWords(context.Background(), 1, 10)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
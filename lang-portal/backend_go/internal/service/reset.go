@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/models"
+)
+
+// Reset actions reported in a ResetPlan
+const (
+	ResetActionStudyHistory = "reset_history"
+	ResetActionFullReset    = "full_reset"
+)
+
+// ResetPlan reports what a reset would delete. The real run must pass back
+// ConfirmationToken, which is derived from the counts: it stops matching as
+// soon as the data changes, so a stale dry run cannot confirm a larger reset.
+type ResetPlan struct {
+	Action string `json:"action"`
+	// Deletes is the number of rows per table that would be deleted
+	Deletes           map[string]int64 `json:"deletes"`
+	ConfirmationToken string           `json:"confirmation_token"`
+}
+
+// PlanStudyHistoryReset reports what ResetStudyHistory would delete, without
+// changing anything
+func (s *StudyService) PlanStudyHistoryReset(ctx context.Context) (*ResetPlan, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.PlanStudyHistoryReset")
+	defer span.End()
+
+	counts, err := s.studyRepo.CountStudyHistory(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to count study history", err)
+	}
+	return newResetPlan(ResetActionStudyHistory, counts), nil
+}
+
+// ResetStudyHistory resets all study-related data. confirm must be the token of
+// a current PlanStudyHistoryReset. The audit entry records how many rows were
+// wiped.
+func (s *StudyService) ResetStudyHistory(ctx context.Context, actor, confirm string) error {
+	ctx, span := tracer.Start(ctx, "StudyService.ResetStudyHistory")
+	defer span.End()
+
+	var deleted map[string]int64
+	err := s.studyRepo.ResetStudyHistory(ctx, func(counts map[string]int64) error {
+		deleted = counts
+		return confirmReset(ResetActionStudyHistory, counts, confirm)
+	})
+	if err != nil {
+		return resetError("Failed to reset study history", err)
+	}
+	s.invalidateDashboard()
+	s.publish(events.TypeStudyHistoryReset, nil)
+	return s.recordAudit(ctx, actor, models.AuditActionResetHistory, models.AuditEntityStudy, nil, deleted, nil)
+}
+
+// PlanFullReset reports what FullReset would delete, without changing anything
+func (s *StudyService) PlanFullReset(ctx context.Context) (*ResetPlan, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.PlanFullReset")
+	defer span.End()
+
+	counts, err := s.studyRepo.CountAllData(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to count application data", err)
+	}
+	return newResetPlan(ResetActionFullReset, counts), nil
+}
+
+// FullReset deletes all words, groups and study history. confirm must be the
+// token of a current PlanFullReset. Study activities, settings and the audit
+// log are kept.
+func (s *StudyService) FullReset(ctx context.Context, actor, confirm string) error {
+	ctx, span := tracer.Start(ctx, "StudyService.FullReset")
+	defer span.End()
+
+	var deleted map[string]int64
+	err := s.studyRepo.ResetAllData(ctx, func(counts map[string]int64) error {
+		deleted = counts
+		return confirmReset(ResetActionFullReset, counts, confirm)
+	})
+	if err != nil {
+		return resetError("Failed to reset application data", err)
+	}
+	s.invalidateDashboard()
+	s.publish(events.TypeDataReset, nil)
+	return s.recordAudit(ctx, actor, models.AuditActionFullReset, models.AuditEntityAll, nil, deleted, nil)
+}
+
+func newResetPlan(action string, counts map[string]int64) *ResetPlan {
+	return &ResetPlan{
+		Action:            action,
+		Deletes:           counts,
+		ConfirmationToken: resetToken(action, counts),
+	}
+}
+
+// resetToken hashes the action and its row counts in a stable order
+func resetToken(action string, counts map[string]int64) string {
+	tables := make([]string, 0, len(counts))
+	for table := range counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	h := sha256.New()
+	h.Write([]byte(action))
+	for _, table := range tables {
+		fmt.Fprintf(h, "\n%s=%d", table, counts[table])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// confirmReset checks confirm against the counts a reset is about to delete
+func confirmReset(action string, counts map[string]int64, confirm string) error {
+	if confirm == "" {
+		return NewServiceError(ErrCodeInvalidInput, "Confirmation token required; request a dry run first", nil)
+	}
+	if confirm != resetToken(action, counts) {
+		return NewServiceError(ErrCodeInvalidInput, "Confirmation token does not match the current data; request a new dry run", nil)
+	}
+	return nil
+}
+
+// resetError passes through confirmation errors and wraps everything else
+func resetError(message string, err error) error {
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr
+	}
+	return NewServiceError(ErrCodeInternal, message, err)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmReset(t *testing.T) {
+	counts := map[string]int64{"study_sessions": 2, "word_review_items": 10}
+	plan := newResetPlan(ResetActionStudyHistory, counts)
+
+	assert.NoError(t, confirmReset(ResetActionStudyHistory, counts, plan.ConfirmationToken))
+
+	// The token is tied to the action and to the exact counts
+	assert.Error(t, confirmReset(ResetActionFullReset, counts, plan.ConfirmationToken))
+	assert.Error(t, confirmReset(ResetActionStudyHistory, map[string]int64{"study_sessions": 3, "word_review_items": 10}, plan.ConfirmationToken))
+
+	err := confirmReset(ResetActionStudyHistory, counts, "")
+	if assert.Error(t, err) {
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"lang-portal/backend_go/internal/repository"
+)
+
+// reviewArchiveInterval is how often the archiver looks for reviews past the
+// retention period
+const reviewArchiveInterval = 24 * time.Hour
+
+// ReviewArchiver keeps word_review_items from growing without bound by moving
+// the reviews of months past the retention period to the review archive. The
+// statistics read by the repositories merge the archive, so learners see the
+// same totals before and after.
+type ReviewArchiver struct {
+	*BaseService
+	archiveRepo     repository.ReviewArchiveRepositoryInterface
+	retentionMonths int
+	logger          *log.Logger
+}
+
+// NewReviewArchiver creates an archiver keeping the reviews of the last
+// retentionMonths months, the current one included, live
+func NewReviewArchiver(base *BaseService, archiveRepo repository.ReviewArchiveRepositoryInterface, retentionMonths int, logger *log.Logger) *ReviewArchiver {
+	return &ReviewArchiver{BaseService: base, archiveRepo: archiveRepo, retentionMonths: retentionMonths, logger: logger}
+}
+
+// Run archives at start and then once a day until ctx is done
+func (a *ReviewArchiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(reviewArchiveInterval)
+	defer ticker.Stop()
+
+	for {
+		archived, err := a.Archive(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			a.logger.Printf("Review archive: %v", err)
+		}
+		if archived > 0 {
+			a.logger.Printf("Archived %d reviews", archived)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Archive moves the reviews recorded before the retention period at now to the
+// archive and returns how many were moved. Whole UTC months are archived, so the
+// monthly totals of a word are final once written.
+func (a *ReviewArchiver) Archive(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := tracer.Start(ctx, "ReviewArchiver.Archive")
+	defer span.End()
+
+	if a.retentionMonths < 1 {
+		return 0, fmt.Errorf("retention must be at least one month, got %d", a.retentionMonths)
+	}
+	now = now.UTC()
+	before := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-a.retentionMonths, 0)
+	archived, err := a.archiveRepo.Archive(ctx, before, now)
+	if err != nil {
+		return archived, fmt.Errorf("failed to archive reviews before %s: %w", before.Format("2006-01"), err)
+	}
+	return archived, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReviewArchiveRepository records the cutoffs it archives before
+type fakeReviewArchiveRepository struct {
+	before []time.Time
+}
+
+func (f *fakeReviewArchiveRepository) Archive(ctx context.Context, before, at time.Time) (int64, error) {
+	f.before = append(f.before, before)
+	return 3, nil
+}
+
+func TestReviewArchiver_Archive(t *testing.T) {
+	repo := &fakeReviewArchiveRepository{}
+	now := time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)
+
+	archived, err := NewReviewArchiver(NewBaseService(nil, nil, nil, nil, nil), repo, 12, nil).Archive(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), archived)
+	_, err = NewReviewArchiver(NewBaseService(nil, nil, nil, nil, nil), repo, 1, nil).Archive(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}, repo.before, "whole months are archived, keeping the current one live")
+
+	_, err = NewReviewArchiver(NewBaseService(nil, nil, nil, nil, nil), repo, 0, nil).Archive(context.Background(), now)
+	assert.Error(t, err)
+}
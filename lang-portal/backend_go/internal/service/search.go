@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// SearchService handles cross-resource search
+type SearchService struct {
+	*BaseService
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(base *BaseService) *SearchService {
+	return &SearchService{BaseService: base}
+}
+
+// Search result types
+const (
+	SearchTypeWord     = "word"
+	SearchTypeGroup    = "group"
+	SearchTypeActivity = "activity"
+)
+
+// SearchTypes lists every searchable result type
+var SearchTypes = []string{SearchTypeWord, SearchTypeGroup, SearchTypeActivity}
+
+// SearchResult represents a single search hit of any type
+type SearchResult struct {
+	Type     string  `json:"type"`
+	ID       uint    `json:"id"`
+	Title    string  `json:"title"`
+	Subtitle string  `json:"subtitle,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// Search returns words, groups and activities matching the query, ordered by
+// relevance. types restricts the result types; an empty slice searches all of them.
+// limit applies to each type before merging, and again to the merged results.
+func (s *SearchService) Search(ctx context.Context, query string, types []string, limit int) ([]SearchResult, error) {
+	ctx, span := tracer.Start(ctx, "SearchService.Search")
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Search query must not be empty", nil)
+	}
+
+	wanted := make(map[string]bool, len(SearchTypes))
+	if len(types) == 0 {
+		types = SearchTypes
+	}
+	for _, t := range types {
+		switch t {
+		case SearchTypeWord, SearchTypeGroup, SearchTypeActivity:
+			wanted[t] = true
+		default:
+			return nil, NewServiceError(ErrCodeInvalidInput, "Unknown search type: "+t, nil)
+		}
+	}
+
+	var results []SearchResult
+
+	if wanted[SearchTypeWord] {
+		words, err := s.wordRepo.Search(ctx, query, limit)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to search words", err)
+		}
+		for _, w := range words {
+			results = append(results, SearchResult{
+				Type:     SearchTypeWord,
+				ID:       w.ID,
+				Title:    w.Term,
+				Subtitle: w.English,
+				Score:    relevance(query, w.Term, w.Reading, w.English),
+			})
+		}
+	}
+
+	if wanted[SearchTypeGroup] {
+		groups, err := s.groupRepo.Search(ctx, query, limit)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to search groups", err)
+		}
+		for _, g := range groups {
+			results = append(results, SearchResult{
+				Type:  SearchTypeGroup,
+				ID:    g.ID,
+				Title: g.Name,
+				Score: relevance(query, g.Name),
+			})
+		}
+	}
+
+	if wanted[SearchTypeActivity] {
+		activities, err := s.studyRepo.SearchStudyActivities(ctx, query, limit)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to search study activities", err)
+		}
+		for _, a := range activities {
+			results = append(results, SearchResult{
+				Type:     SearchTypeActivity,
+				ID:       a.ID,
+				Title:    a.Name,
+				Subtitle: a.Description,
+				Score:    relevance(query, a.Name, a.Description),
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// relevance scores how well query matches the given fields, from 0 to 1.
+// An exact match scores highest, then a prefix match, then a substring match.
+// The first field is the primary one; matches on later fields are discounted.
+func relevance(query string, fields ...string) float64 {
+	q := strings.ToLower(query)
+	best := 0.0
+	for i, field := range fields {
+		f := strings.ToLower(field)
+		var score float64
+		switch {
+		case f == q:
+			score = 1.0
+		case strings.HasPrefix(f, q):
+			score = 0.75
+		case strings.Contains(f, q):
+			score = 0.5
+		}
+		if i > 0 {
+			score *= 0.9
+		}
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
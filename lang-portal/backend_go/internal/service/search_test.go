@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchService_Search_Words(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil) // Only words are searched
+	searchService := NewSearchService(baseService)
+
+	mockRepo.On("Search", "inu", 10).Return([]models.Word{
+		{ID: 1, Term: "子犬", Reading: "koinu", English: "puppy"},
+		{ID: 2, Term: "犬", Reading: "inu", English: "dog"},
+	}, nil)
+
+	results, err := searchService.Search(context.Background(), " inu ", []string{SearchTypeWord}, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, uint(2), results[0].ID, "exact match should rank first")
+	assert.Equal(t, SearchTypeWord, results[0].Type)
+	assert.Greater(t, results[0].Score, results[1].Score)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchService_Search_InvalidInput(t *testing.T) {
+	searchService := NewSearchService(NewBaseService(nil, nil, nil, nil, nil))
+
+	_, err := searchService.Search(context.Background(), "  ", nil, 10)
+	assert.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+
+	_, err = searchService.Search(context.Background(), "inu", []string{"sentence"}, 10)
+	assert.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
+
+func TestRelevance(t *testing.T) {
+	assert.Equal(t, 1.0, relevance("Dog", "dog"))
+	assert.Equal(t, 0.75, relevance("do", "dog"))
+	assert.Equal(t, 0.5, relevance("og", "dog"))
+	assert.Equal(t, 0.9, relevance("dog", "犬", "dog"))
+	assert.Equal(t, 0.0, relevance("cat", "dog"))
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/jpn"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// ClozeActivityName is the study activity cloze sets are recorded under. It is
+// created the first time a cloze set is generated.
+const ClozeActivityName = "Sentence Cloze"
+
+// clozeActivity is the study activity cloze answers are recorded under
+var clozeActivity = models.StudyActivity{
+	Name:         ClozeActivityName,
+	Description:  "Fill in the word missing from an example sentence",
+	ThumbnailURL: "/thumbnails/sentence-cloze.png",
+	Modes:        models.StringSlice{"cloze", "typing"},
+	Capabilities: models.StringSlice{},
+}
+
+// Cloze set sizes
+const (
+	DefaultClozeQuestions = 10
+	MaxClozeQuestions     = 50
+)
+
+// SentenceService manages example sentences and the cloze questions generated
+// from them
+type SentenceService struct {
+	*BaseService
+	sentenceRepo repository.SentenceRepositoryInterface
+}
+
+// NewSentenceService creates a new example sentence service
+func NewSentenceService(base *BaseService, sentenceRepo repository.SentenceRepositoryInterface) *SentenceService {
+	return &SentenceService{BaseService: base, sentenceRepo: sentenceRepo}
+}
+
+// ClozeQuestion is a sentence with a word blanked out
+type ClozeQuestion struct {
+	SentenceID uint   `json:"sentence_id"`
+	WordID     uint   `json:"word_id"`
+	Text       string `json:"text"`
+	// Translation is the English sentence, if one was given
+	Translation string `json:"translation,omitempty"`
+	// Hint is the English meaning of the blanked word
+	Hint string `json:"hint"`
+	// Answers are the accepted fillings of the blank: the text that was removed
+	// and, when it is the whole word, the word's romaji
+	Answers []string `json:"answers"`
+}
+
+// ClozeSet is a list of cloze questions for a group. Reviews of the answers
+// are recorded in sessions of the cloze study activity.
+type ClozeSet struct {
+	StudyActivityID uint            `json:"study_activity_id"`
+	GroupID         uint            `json:"group_id"`
+	Questions       []ClozeQuestion `json:"questions"`
+}
+
+// AddExampleSentence adds an example sentence to a word. The sentence must
+// contain the word, or the stem of a conjugated form of it.
+func (s *SentenceService) AddExampleSentence(ctx context.Context, wordID uint, japanese, english string) (*models.ExampleSentence, error) {
+	ctx, span := tracer.Start(ctx, "SentenceService.AddExampleSentence")
+	defer span.End()
+
+	word, err := s.getWord(ctx, wordID)
+	if err != nil {
+		return nil, err
+	}
+
+	sentence := &models.ExampleSentence{
+		WordID:   word.ID,
+		Japanese: strings.TrimSpace(japanese),
+		English:  strings.TrimSpace(english),
+	}
+	if err := sentence.Validate(); err != nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "japanese is required; sentences are limited to 200 characters and translations to 400", err)
+	}
+	if _, _, ok := jpn.BlankWord(sentence.Japanese, jpn.WordForms(word.Term), jpn.ClozeBlank); !ok {
+		return nil, NewServiceError(ErrCodeInvalidInput, "The sentence does not contain the word "+word.Term, nil)
+	}
+	if err := s.sentenceRepo.Create(ctx, sentence); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create example sentence", err)
+	}
+	return sentence, nil
+}
+
+// ListExampleSentences returns the example sentences of a word
+func (s *SentenceService) ListExampleSentences(ctx context.Context, wordID uint) ([]models.ExampleSentence, error) {
+	ctx, span := tracer.Start(ctx, "SentenceService.ListExampleSentences")
+	defer span.End()
+
+	if _, err := s.getWord(ctx, wordID); err != nil {
+		return nil, err
+	}
+	sentences, err := s.sentenceRepo.ListByWord(ctx, wordID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list example sentences", err)
+	}
+	return sentences, nil
+}
+
+// DeleteExampleSentence deletes an example sentence of a word
+func (s *SentenceService) DeleteExampleSentence(ctx context.Context, wordID, id uint, actor string) error {
+	ctx, span := tracer.Start(ctx, "SentenceService.DeleteExampleSentence")
+	defer span.End()
+
+	sentences, err := s.sentenceRepo.ListByWord(ctx, wordID)
+	if err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to list example sentences", err)
+	}
+	var existing *models.ExampleSentence
+	for i := range sentences {
+		if sentences[i].ID == id {
+			existing = &sentences[i]
+		}
+	}
+	if existing == nil {
+		return NewServiceError(ErrCodeNotFound, "Example sentence not found", repository.ErrNotFound)
+	}
+
+	if err := s.sentenceRepo.Delete(ctx, wordID, id); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Example sentence not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to delete example sentence", err)
+	}
+	return s.recordAudit(ctx, actor, models.AuditActionDelete, models.AuditEntitySentence, &id, existing, nil)
+}
+
+// GenerateCloze builds up to limit cloze questions, at most MaxClozeQuestions,
+// from the example sentences of a group's words, one per word. Words are taken
+// in the preferred review order, and each gets one of its sentences at random.
+// Sentences that no longer contain their word, after the word was edited, are
+// skipped.
+func (s *SentenceService) GenerateCloze(ctx context.Context, groupID uint, limit int) (*ClozeSet, error) {
+	ctx, span := tracer.Start(ctx, "SentenceService.GenerateCloze")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = DefaultClozeQuestions
+	}
+	if limit > MaxClozeQuestions {
+		limit = MaxClozeQuestions
+	}
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Group not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
+	}
+
+	activity, err := s.builtinActivity(ctx, clozeActivity)
+	if err != nil {
+		return nil, err
+	}
+	sentences, err := s.sentenceRepo.ListByGroup(ctx, groupID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list example sentences", err)
+	}
+	order, err := s.defaultReviewOrder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := time.Now().UnixNano()
+	set := &ClozeSet{StudyActivityID: activity.ID, GroupID: groupID, Questions: clozeQuestions(sentences, order, seed)}
+	if len(set.Questions) > limit {
+		set.Questions = set.Questions[:limit]
+	}
+	return set, nil
+}
+
+// clozeQuestions makes one question per word from sentences, the words ordered
+// by strategy and each word's sentence picked with seed
+func clozeQuestions(sentences []models.ExampleSentence, strategy string, seed int64) []ClozeQuestion {
+	byWord := make(map[uint][]ClozeQuestion)
+	var words []models.Word
+	for _, sentence := range sentences {
+		word := sentence.Word
+		text, removed, ok := jpn.BlankWord(sentence.Japanese, jpn.WordForms(word.Term), jpn.ClozeBlank)
+		if !ok {
+			continue
+		}
+		answers := []string{removed}
+		if removed == word.Term && word.Reading != "" {
+			answers = append(answers, word.Reading)
+		}
+		if _, seen := byWord[word.ID]; !seen {
+			words = append(words, word)
+		}
+		byWord[word.ID] = append(byWord[word.ID], ClozeQuestion{
+			SentenceID:  sentence.ID,
+			WordID:      word.ID,
+			Text:        text,
+			Translation: sentence.English,
+			Hint:        word.English,
+			Answers:     answers,
+		})
+	}
+
+	orderWords(words, strategy, seed)
+	rnd := rand.New(rand.NewSource(seed))
+	questions := make([]ClozeQuestion, 0, len(words))
+	for _, word := range words {
+		candidates := byWord[word.ID]
+		questions = append(questions, candidates[rnd.Intn(len(candidates))])
+	}
+	return questions
+}
+
+// getWord fetches a word, as a service error when it fails
+func (s *BaseService) getWord(ctx context.Context, wordID uint) (*models.Word, error) {
+	word, err := s.wordRepo.GetByID(ctx, wordID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Word not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
+	}
+	return word, nil
+}
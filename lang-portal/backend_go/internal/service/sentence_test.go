@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClozeQuestions(t *testing.T) {
+	hard := 0.2
+	eat := models.Word{ID: 1, Term: "食べる", Reading: "taberu", English: "to eat"}
+	cat := models.Word{ID: 2, Term: "猫", Reading: "neko", English: "cat", AccuracyEWMA: &hard}
+	dog := models.Word{ID: 3, Term: "犬", Reading: "inu", English: "dog"}
+	sentences := []models.ExampleSentence{
+		{ID: 10, WordID: eat.ID, Word: eat, Japanese: "毎朝パンを食べます。", English: "I eat bread every morning."},
+		{ID: 11, WordID: cat.ID, Word: cat, Japanese: "猫が好きです。"},
+		// The word was edited after the sentence was added
+		{ID: 12, WordID: dog.ID, Word: dog, Japanese: "鳥が飛ぶ。"},
+	}
+
+	questions := clozeQuestions(sentences, ReviewOrderHardestFirst, 1)
+	require.Len(t, questions, 2)
+
+	assert.Equal(t, ClozeQuestion{
+		SentenceID: 11,
+		WordID:     cat.ID,
+		Text:       "＿＿が好きです。",
+		Hint:       "cat",
+		Answers:    []string{"猫", "neko"},
+	}, questions[0], "hardest words come first")
+	assert.Equal(t, ClozeQuestion{
+		SentenceID:  10,
+		WordID:      eat.ID,
+		Text:        "毎朝パンを＿＿ます。",
+		Translation: "I eat bread every morning.",
+		Hint:        "to eat",
+		Answers:     []string{"食べ"},
+	}, questions[1], "a blanked stem is not answered by the romaji of the whole word")
+
+	assert.Empty(t, clozeQuestions(nil, ReviewOrderRandom, 1))
+}
@@ -1,30 +1,68 @@
 package service
 
 import (
+	"log"
+
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/embeddings"
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/llm"
 	"lang-portal/backend_go/internal/repository"
 )
 
 // BaseService provides common service functionality
 type BaseService struct {
-	wordRepo  repository.WordRepositoryInterface
-	groupRepo repository.GroupRepositoryInterface
-	studyRepo repository.StudyRepositoryInterface
+	wordRepo    repository.WordRepositoryInterface
+	groupRepo   repository.GroupRepositoryInterface
+	studyRepo   repository.StudyRepositoryInterface
+	auditRepo   repository.AuditRepositoryInterface
+	settingRepo repository.SettingRepositoryInterface
+	cache       cache.Cache
+	events      events.Broker
+	embeddings  embeddings.Provider
+	llm         llm.Client
+	prompts     repository.PromptRepositoryInterface
+	aiUsage     repository.AIUsageRepositoryInterface
+	aiQuota     AIQuota
+	xp          repository.XPRepositoryInterface
+	xpRules     XPRules
+	logger      *log.Logger
 }
 
-// NewBaseService creates a new base service
-func NewBaseService(wordRepo repository.WordRepositoryInterface, groupRepo repository.GroupRepositoryInterface, studyRepo repository.StudyRepositoryInterface) *BaseService {
+// NewBaseService creates a new base service.
+// auditRepo may be nil, in which case destructive operations are not audited;
+// settingRepo may be nil, in which case preferences fall back to their defaults.
+func NewBaseService(wordRepo repository.WordRepositoryInterface, groupRepo repository.GroupRepositoryInterface, studyRepo repository.StudyRepositoryInterface, auditRepo repository.AuditRepositoryInterface, settingRepo repository.SettingRepositoryInterface) *BaseService {
 	return &BaseService{
-		wordRepo:  wordRepo,
-		groupRepo: groupRepo,
-		studyRepo: studyRepo,
+		wordRepo:    wordRepo,
+		groupRepo:   groupRepo,
+		studyRepo:   studyRepo,
+		auditRepo:   auditRepo,
+		settingRepo: settingRepo,
 	}
 }
 
+// WithCache enables read-through caching of expensive aggregates such as the
+// dashboard statistics. Without a cache they are computed on every request.
+func (s *BaseService) WithCache(c cache.Cache) *BaseService {
+	s.cache = c
+	return s
+}
+
+// WithEvents publishes changes made through the services to broker, for
+// clients keeping live views up to date
+func (s *BaseService) WithEvents(broker events.Broker) *BaseService {
+	s.events = broker
+	return s
+}
+
 // ServiceError represents a service-level error
 type ServiceError struct {
 	Code    string
 	Message string
 	Err     error
+	// Details are sent to the client with Message, unlike Err
+	Details interface{}
 }
 
 func (e *ServiceError) Error() string {
@@ -39,6 +77,9 @@ const (
 	ErrCodeNotFound     = "NOT_FOUND"
 	ErrCodeInvalidInput = "INVALID_INPUT"
 	ErrCodeInternal     = "INTERNAL_ERROR"
+	ErrCodeUnauthorized = "UNAUTHORIZED"
+	ErrCodeConflict     = "CONFLICT"
+	ErrCodeRateLimited  = "RATE_LIMITED"
 )
 
 // NewServiceError creates a new service error
@@ -50,6 +91,34 @@ func NewServiceError(code, message string, err error) *ServiceError {
 	}
 }
 
+// WithDetails sets the details sent to the client and returns e
+func (e *ServiceError) WithDetails(details interface{}) *ServiceError {
+	e.Details = details
+	return e
+}
+
+// ErrorReason is the detail of an error whose cause the client can act on,
+// such as where a document failed to parse
+type ErrorReason struct {
+	Reason string `json:"reason"`
+}
+
+// staleVersion returns the conflict error for an update of entity based on a
+// version other than the current one. Zero versions come from clients that do
+// not track versions and are never stale.
+func staleVersion(entity string, version, current uint) error {
+	if version == 0 || version == current {
+		return nil
+	}
+	return conflictingUpdate(entity, repository.ErrConflict)
+}
+
+// conflictingUpdate is the error for an update of entity that lost a race with
+// another update
+func conflictingUpdate(entity string, err error) *ServiceError {
+	return NewServiceError(ErrCodeConflict, "The "+entity+" was changed by another request; reload it and try again", err)
+}
+
 // PaginationParams represents common pagination parameters
 type PaginationParams struct {
 	Page     int
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// SessionQueueWord is a word of a study session's queue. Reviewed is set once
+// the word has a review in the session.
+type SessionQueueWord struct {
+	Position int    `json:"position"`
+	WordID   uint   `json:"word_id"`
+	Japanese string `json:"japanese"`
+	Romaji   string `json:"romaji"`
+	English  string `json:"english"`
+	Reviewed bool   `json:"reviewed"`
+}
+
+// SessionQueue is the ordered word queue of a study session. Next is the
+// position of the first word not reviewed yet, -1 once all of them are.
+type SessionQueue struct {
+	StudySessionID uint               `json:"study_session_id"`
+	ReviewOrder    string             `json:"review_order,omitempty"`
+	Words          []SessionQueueWord `json:"words"`
+	Remaining      int                `json:"remaining"`
+	Next           int                `json:"next"`
+}
+
+// GetSessionQueue returns the word queue of a study session. Sessions started
+// before queues were stored get theirs generated on first request.
+func (s *StudyService) GetSessionQueue(ctx context.Context, id uint) (*SessionQueue, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetSessionQueue")
+	defer span.End()
+
+	session, err := s.studyRepo.GetStudySessionByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Study session not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
+	}
+	items, err := s.sessionQueue(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return newSessionQueue(session, items), nil
+}
+
+func newSessionQueue(session *models.StudySession, items []repository.SessionQueueItem) *SessionQueue {
+	queue := &SessionQueue{StudySessionID: session.ID, ReviewOrder: session.ReviewOrder, Words: make([]SessionQueueWord, len(items)), Next: -1}
+	for i, item := range items {
+		queue.Words[i] = SessionQueueWord{
+			Position: item.Position,
+			WordID:   item.WordID,
+			Japanese: item.Term,
+			Romaji:   item.Reading,
+			English:  item.English,
+			Reviewed: item.Reviewed,
+		}
+		if !item.Reviewed {
+			queue.Remaining++
+			if queue.Next < 0 {
+				queue.Next = item.Position
+			}
+		}
+	}
+	return queue
+}
+
+// sessionQueue returns the stored queue of session, generating it first if the
+// session has none
+func (s *StudyService) sessionQueue(ctx context.Context, session *models.StudySession) ([]repository.SessionQueueItem, error) {
+	items, err := s.studyRepo.GetSessionQueue(ctx, session.ID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get session word queue", err)
+	}
+	if len(items) > 0 {
+		return items, nil
+	}
+	if err := s.generateSessionQueue(ctx, session); err != nil {
+		return nil, err
+	}
+	if items, err = s.studyRepo.GetSessionQueue(ctx, session.ID); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get session word queue", err)
+	}
+	return items, nil
+}
+
+// generateSessionQueue orders the words of the session's group by its review
+// order, or the preference if it has none, and stores the first words up to
+// the session's word limit as its queue
+func (s *StudyService) generateSessionQueue(ctx context.Context, session *models.StudySession) error {
+	order := session.ReviewOrder
+	if order == "" {
+		var err error
+		if order, err = s.defaultReviewOrder(ctx); err != nil {
+			return err
+		}
+	}
+	words, err := s.wordRepo.GetWordsByGroupRaw(ctx, session.GroupID)
+	if err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to get group words", err)
+	}
+	orderWords(words, order, int64(session.ID))
+	if limit := sessionWordLimit(session); len(words) > limit {
+		words = words[:limit]
+	}
+
+	ids := make([]uint, len(words))
+	for i, w := range words {
+		ids[i] = w.ID
+	}
+	if err := s.studyRepo.CreateSessionWords(ctx, session.ID, ids); err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to store session word queue", err)
+	}
+	return nil
+}
+
+// sessionWordLimit returns how many of its group's words a session covers
+func sessionWordLimit(session *models.StudySession) int {
+	if session.WordLimit > 0 && session.WordLimit < MaxBundleWords {
+		return session.WordLimit
+	}
+	return MaxBundleWords
+}
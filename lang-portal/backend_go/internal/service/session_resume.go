@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"lang-portal/backend_go/internal/repository"
+)
+
+// sessionExpiryInterval is how often the expiry job looks for stale sessions
+const sessionExpiryInterval = 15 * time.Minute
+
+// SessionProgress is how far a study session got through its queue
+type SessionProgress struct {
+	QueueWords     int `json:"queue_words"`
+	ReviewedWords  int `json:"reviewed_words"`
+	Reviews        int `json:"reviews"`
+	CorrectReviews int `json:"correct_reviews"`
+}
+
+// ActiveStudySession is a learner's open study session with the words of its
+// queue not reviewed yet, in queue order. Next is the position to resume at.
+type ActiveStudySession struct {
+	Session         StudySessionInfo   `json:"session"`
+	GroupID         uint               `json:"group_id"`
+	StudyActivityID uint               `json:"study_activity_id"`
+	ReviewOrder     string             `json:"review_order,omitempty"`
+	Remaining       []SessionQueueWord `json:"remaining"`
+	Next            int                `json:"next"`
+	Progress        SessionProgress    `json:"progress"`
+}
+
+// GetActiveSession returns the most recent study session of learner that was
+// neither completed nor expired, so that it can be resumed
+func (s *StudyService) GetActiveSession(ctx context.Context, learner string) (*ActiveStudySession, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetActiveSession")
+	defer span.End()
+
+	session, err := s.studyRepo.GetActiveStudySession(ctx, learner)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "No active study session", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch active study session", err)
+	}
+	items, err := s.sessionQueue(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	queue := newSessionQueue(session, items)
+
+	active := &ActiveStudySession{
+		Session:         newStudySessionInfo(*session),
+		GroupID:         session.GroupID,
+		StudyActivityID: session.StudyActivityID,
+		ReviewOrder:     session.ReviewOrder,
+		Remaining:       []SessionQueueWord{},
+		Next:            queue.Next,
+		Progress: SessionProgress{
+			QueueWords:    len(queue.Words),
+			ReviewedWords: len(queue.Words) - queue.Remaining,
+			Reviews:       len(session.Reviews),
+		},
+	}
+	for _, word := range queue.Words {
+		if !word.Reviewed {
+			active.Remaining = append(active.Remaining, word)
+		}
+	}
+	for _, review := range session.Reviews {
+		if review.Correct {
+			active.Progress.CorrectReviews++
+		}
+	}
+	return active, nil
+}
+
+// SessionExpirer closes study sessions left open, such as when the browser was
+// closed mid-session, once they had no reviews for the idle period. Expired
+// sessions are no longer offered for resuming and do not count as completed.
+type SessionExpirer struct {
+	*BaseService
+	idle   time.Duration
+	logger *log.Logger
+}
+
+// NewSessionExpirer creates an expiry job for sessions idle longer than idle
+func NewSessionExpirer(base *BaseService, idle time.Duration, logger *log.Logger) *SessionExpirer {
+	return &SessionExpirer{BaseService: base, idle: idle, logger: logger}
+}
+
+// Run expires stale sessions at start and then every 15 minutes until ctx is done
+func (e *SessionExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(sessionExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := e.Expire(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			e.logger.Printf("Session expiry: %v", err)
+		}
+		if expired > 0 {
+			e.logger.Printf("Expired %d idle study sessions", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Expire closes the open sessions without a review, or a start, within the
+// idle period before now and returns how many were closed
+func (e *SessionExpirer) Expire(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := tracer.Start(ctx, "SessionExpirer.Expire")
+	defer span.End()
+
+	return e.studyRepo.ExpireStaleSessions(ctx, now.Add(-e.idle), now)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResumeRepository adds the open session lookup and expiry to a queue
+type fakeResumeRepository struct {
+	*fakeQueueRepository
+	learner   string
+	idleSince time.Time
+	expiredAt time.Time
+}
+
+func (f *fakeResumeRepository) GetActiveStudySession(ctx context.Context, learner string) (*models.StudySession, error) {
+	if learner != f.learner {
+		return nil, repository.ErrNotFound
+	}
+	return f.GetStudySessionByID(ctx, f.session.ID)
+}
+
+func (f *fakeResumeRepository) ExpireStaleSessions(ctx context.Context, idleSince, at time.Time) (int64, error) {
+	f.idleSince, f.expiredAt = idleSince, at
+	return 2, nil
+}
+
+func TestStudyService_GetActiveSession(t *testing.T) {
+	queue, wordRepo := queueFixture(0)
+	queue.session.Learner = "yuki"
+	queue.session.Reviews = []models.WordReview{{WordID: 3, Correct: true}, {WordID: 3, Correct: false}}
+	queue.reviewed[3] = true
+	repo := &fakeResumeRepository{fakeQueueRepository: queue, learner: "yuki"}
+	s := NewStudyService(NewBaseService(wordRepo, nil, repo, nil, nil))
+
+	active, err := s.GetActiveSession(context.Background(), "yuki")
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), active.Session.ID)
+	assert.Equal(t, uint(4), active.GroupID)
+	assert.Equal(t, 1, active.Next)
+	require.Len(t, active.Remaining, 2)
+	assert.Equal(t, []uint{1, 2}, []uint{active.Remaining[0].WordID, active.Remaining[1].WordID})
+	assert.Equal(t, SessionProgress{QueueWords: 3, ReviewedWords: 1, Reviews: 2, CorrectReviews: 1}, active.Progress)
+
+	_, err = s.GetActiveSession(context.Background(), "ken")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
+
+func TestSessionExpirer_Expire(t *testing.T) {
+	queue, _ := queueFixture(0)
+	repo := &fakeResumeRepository{fakeQueueRepository: queue}
+	expirer := NewSessionExpirer(NewBaseService(nil, nil, repo, nil, nil), 12*time.Hour, log.New(io.Discard, "", 0))
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	expired, err := expirer.Expire(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), expired)
+	assert.Equal(t, now.Add(-12*time.Hour), repo.idleSince)
+	assert.Equal(t, now, repo.expiredAt)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// PublicStatsCacheTTL is how long public stats are served from memory before
+// being recomputed
+const PublicStatsCacheTTL = 5 * time.Minute
+
+// shareTokenBytes is the amount of randomness in a share token
+const shareTokenBytes = 16
+
+// ShareService manages share tokens and the public stats they expose
+type ShareService struct {
+	*BaseService
+	shareRepo repository.ShareRepositoryInterface
+
+	mu      sync.Mutex
+	cache   map[string]cachedPublicStats
+	now     func() time.Time
+	flights computeGroup
+}
+
+type cachedPublicStats struct {
+	stats     *PublicStats
+	expiresAt time.Time
+}
+
+// NewShareService creates a new share service
+func NewShareService(base *BaseService, shareRepo repository.ShareRepositoryInterface) *ShareService {
+	return &ShareService{
+		BaseService: base,
+		shareRepo:   shareRepo,
+		cache:       make(map[string]cachedPublicStats),
+		now:         time.Now,
+	}
+}
+
+// PublicStats holds the stats exposed by a share token. Stats that were not
+// chosen when the token was created are omitted.
+type PublicStats struct {
+	Streak        *int      `json:"streak,omitempty"`
+	WordsLearned  *int64    `json:"words_learned,omitempty"`
+	TotalSessions *int64    `json:"total_sessions,omitempty"`
+	SuccessRate   *float64  `json:"success_rate,omitempty"`
+	GeneratedAt   time.Time `json:"generated_at"`
+}
+
+// CreateShare creates a share token exposing the given stats
+func (s *ShareService) CreateShare(ctx context.Context, stats []string) (*models.StatsShare, error) {
+	ctx, span := tracer.Start(ctx, "ShareService.CreateShare")
+	defer span.End()
+
+	if len(stats) == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "At least one stat must be shared", nil)
+	}
+	seen := make(map[string]bool, len(stats))
+	var unique models.StringSlice
+	for _, stat := range stats {
+		switch stat {
+		case models.ShareStatStreak, models.ShareStatWordsLearned, models.ShareStatTotalSessions, models.ShareStatSuccessRate:
+		default:
+			return nil, NewServiceError(ErrCodeInvalidInput, "Unknown stat: "+stat, nil)
+		}
+		if !seen[stat] {
+			seen[stat] = true
+			unique = append(unique, stat)
+		}
+	}
+
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to generate share token", err)
+	}
+
+	share := &models.StatsShare{
+		Token: hex.EncodeToString(buf),
+		Stats: unique,
+	}
+	if err := s.shareRepo.Create(ctx, share); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create share", err)
+	}
+	return share, nil
+}
+
+// RevokeShare deletes a share token; cached stats for it are dropped immediately
+func (s *ShareService) RevokeShare(ctx context.Context, token string) error {
+	ctx, span := tracer.Start(ctx, "ShareService.RevokeShare")
+	defer span.End()
+
+	if err := s.shareRepo.DeleteByToken(ctx, token); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Share not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to revoke share", err)
+	}
+
+	s.mu.Lock()
+	delete(s.cache, token)
+	s.mu.Unlock()
+	return nil
+}
+
+// GetPublicStats returns the stats exposed by a share token, served from an
+// in-memory cache for PublicStatsCacheTTL. Concurrent cache misses for the same
+// token share a single computation.
+func (s *ShareService) GetPublicStats(ctx context.Context, token string) (*PublicStats, error) {
+	ctx, span := tracer.Start(ctx, "ShareService.GetPublicStats")
+	defer span.End()
+
+	now := s.now()
+
+	s.mu.Lock()
+	if cached, ok := s.cache[token]; ok && now.Before(cached.expiresAt) {
+		s.mu.Unlock()
+		return cached.stats, nil
+	}
+	s.mu.Unlock()
+
+	shared := context.WithoutCancel(ctx)
+	return computeOnce(&s.flights, token, func() (*PublicStats, error) {
+		return s.computePublicStats(shared, token, now)
+	})
+}
+
+func (s *ShareService) computePublicStats(ctx context.Context, token string, now time.Time) (*PublicStats, error) {
+	share, err := s.shareRepo.GetByToken(ctx, token)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Share not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch share", err)
+	}
+
+	stats := &PublicStats{GeneratedAt: now}
+
+	if share.Shares(models.ShareStatStreak) {
+		streak, err := s.studyRepo.GetStudyStreak(ctx)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to get study streak", err)
+		}
+		stats.Streak = &streak
+	}
+
+	if share.Shares(models.ShareStatWordsLearned) {
+		learned, err := s.wordRepo.GetStudiedWordCount(ctx)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to get studied word count", err)
+		}
+		stats.WordsLearned = &learned
+	}
+
+	if share.Shares(models.ShareStatTotalSessions) || share.Shares(models.ShareStatSuccessRate) {
+		totalSessions, totalReviews, correctReviews, err := s.studyRepo.GetStudyStats(ctx)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to get study statistics", err)
+		}
+		if share.Shares(models.ShareStatTotalSessions) {
+			stats.TotalSessions = &totalSessions
+		}
+		if share.Shares(models.ShareStatSuccessRate) {
+			var rate float64
+			if totalReviews > 0 {
+				rate = float64(correctReviews) / float64(totalReviews) * 100
+			}
+			stats.SuccessRate = &rate
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[token] = cachedPublicStats{stats: stats, expiresAt: now.Add(PublicStatsCacheTTL)}
+	s.mu.Unlock()
+
+	return stats, nil
+}
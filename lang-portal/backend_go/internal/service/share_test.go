@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockShareRepository is a mock implementation of ShareRepositoryInterface
+type mockShareRepository struct {
+	mock.Mock
+}
+
+func (m *mockShareRepository) Create(ctx context.Context, share *models.StatsShare) error {
+	args := m.Called(share)
+	return args.Error(0)
+}
+
+func (m *mockShareRepository) GetByToken(ctx context.Context, token string) (*models.StatsShare, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StatsShare), args.Error(1)
+}
+
+func (m *mockShareRepository) DeleteByToken(ctx context.Context, token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func TestShareService_CreateShare(t *testing.T) {
+	mockShares := new(mockShareRepository)
+	shareService := NewShareService(NewBaseService(nil, nil, nil, nil, nil), mockShares)
+
+	mockShares.On("Create", mock.AnythingOfType("*models.StatsShare")).Return(nil)
+
+	share, err := shareService.CreateShare(context.Background(), []string{models.ShareStatStreak, models.ShareStatStreak, models.ShareStatWordsLearned})
+
+	assert.NoError(t, err)
+	assert.Len(t, share.Token, shareTokenBytes*2)
+	assert.Equal(t, models.StringSlice{models.ShareStatStreak, models.ShareStatWordsLearned}, share.Stats)
+	mockShares.AssertExpectations(t)
+
+	_, err = shareService.CreateShare(context.Background(), []string{"email"})
+	assert.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
+
+func TestShareService_GetPublicStats_CachesSelectedStats(t *testing.T) {
+	mockWords := new(mockWordRepository)
+	mockShares := new(mockShareRepository)
+	shareService := NewShareService(NewBaseService(mockWords, nil, nil, nil, nil), mockShares)
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	shareService.now = func() time.Time { return now }
+
+	mockShares.On("GetByToken", "abc").Return(&models.StatsShare{Token: "abc", Stats: models.StringSlice{models.ShareStatWordsLearned}}, nil).Twice()
+	mockWords.On("GetStudiedWordCount").Return(int64(42), nil).Twice()
+
+	stats, err := shareService.GetPublicStats(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), *stats.WordsLearned)
+	assert.Nil(t, stats.Streak, "unshared stats must be omitted")
+	assert.Nil(t, stats.SuccessRate)
+
+	// Served from cache within the TTL
+	now = now.Add(PublicStatsCacheTTL - time.Second)
+	_, err = shareService.GetPublicStats(context.Background(), "abc")
+	assert.NoError(t, err)
+	mockShares.AssertNumberOfCalls(t, "GetByToken", 1)
+
+	// Recomputed once the TTL has passed
+	now = now.Add(2 * time.Second)
+	_, err = shareService.GetPublicStats(context.Background(), "abc")
+	assert.NoError(t, err)
+	mockShares.AssertNumberOfCalls(t, "GetByToken", 2)
+	mockWords.AssertExpectations(t)
+}
+
+func TestShareService_GetPublicStats_UnknownToken(t *testing.T) {
+	mockShares := new(mockShareRepository)
+	shareService := NewShareService(NewBaseService(nil, nil, nil, nil, nil), mockShares)
+
+	mockShares.On("GetByToken", "missing").Return(nil, repository.ErrNotFound)
+
+	_, err := shareService.GetPublicStats(context.Background(), "missing")
+	assert.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"lang-portal/backend_go/internal/repository"
+)
+
+// signingKeyBytes is the size of generated signing keys
+const signingKeyBytes = 32
+
+// storedKey is an HMAC key that is either configured or generated on first use
+// and kept in a setting, so that signatures stay valid across restarts. Without
+// a settings repository a generated key lives in memory only.
+type storedKey struct {
+	setting string
+
+	mu  sync.Mutex
+	key []byte
+}
+
+// set configures the key; an empty key is ignored
+func (k *storedKey) set(key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(key) > 0 {
+		k.key = key
+	}
+}
+
+// get returns the configured key, or the one kept in the settings table,
+// generating it on first use
+func (k *storedKey) get(ctx context.Context, settings repository.SettingRepositoryInterface) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.key != nil {
+		return k.key, nil
+	}
+
+	if settings != nil {
+		stored, err := settings.Get(ctx, k.setting)
+		if err == nil {
+			key, err := hex.DecodeString(stored)
+			if err == nil && len(key) > 0 {
+				k.key = key
+				return key, nil
+			}
+		} else if err != repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to load signing key", err)
+		}
+	}
+
+	key := make([]byte, signingKeyBytes)
+	if _, err := rand.Read(key); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to generate signing key", err)
+	}
+	if settings != nil {
+		if err := settings.Set(ctx, k.setting, hex.EncodeToString(key)); err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to store signing key", err)
+		}
+	}
+	k.key = key
+	return key, nil
+}
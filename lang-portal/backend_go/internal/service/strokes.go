@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/backend_go/internal/cache"
+	"lang-portal/backend_go/internal/models"
+)
+
+// DefaultKanjiVGURL serves the files of the KanjiVG dataset, one SVG per kanji
+const DefaultKanjiVGURL = "https://raw.githubusercontent.com/KanjiVG/kanjivg/master/kanji"
+
+// maxStrokesSize caps the size of a stroke order file; KanjiVG files are a few KB
+const maxStrokesSize = 1 << 20
+
+// strokesCacheTTL is how long fetched stroke order data is cached. The dataset
+// rarely changes, so this mostly bounds the memory held by the cache.
+const strokesCacheTTL = 24 * time.Hour
+
+// ErrStrokesNotFound is returned by a StrokeSource without data for a character
+var ErrStrokesNotFound = errors.New("stroke order data not found")
+
+// StrokeSource provides KanjiVG stroke order SVGs
+type StrokeSource interface {
+	// Strokes returns the SVG for character, or ErrStrokesNotFound
+	Strokes(ctx context.Context, character string) ([]byte, error)
+}
+
+// kanjiVGFile names the KanjiVG file of a character: its code point as five
+// lowercase hex digits
+func kanjiVGFile(character string) string {
+	r, _ := utf8.DecodeRuneInString(character)
+	return fmt.Sprintf("%05x.svg", r)
+}
+
+// KanjiVGDir reads stroke order data from the kanji directory of a KanjiVG release
+type KanjiVGDir string
+
+// Strokes implements StrokeSource
+func (d KanjiVGDir) Strokes(_ context.Context, character string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(string(d), kanjiVGFile(character)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrStrokesNotFound
+	}
+	return data, err
+}
+
+// KanjiVGHTTP fetches stroke order data from a server laid out like the KanjiVG
+// kanji directory, such as DefaultKanjiVGURL
+type KanjiVGHTTP struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// Strokes implements StrokeSource
+func (h *KanjiVGHTTP) Strokes(ctx context.Context, character string) ([]byte, error) {
+	url := strings.TrimSuffix(h.BaseURL, "/") + "/" + kanjiVGFile(character)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrStrokesNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxStrokesSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxStrokesSize {
+		return nil, fmt.Errorf("fetching %s: file exceeds %d bytes", url, maxStrokesSize)
+	}
+	return data, nil
+}
+
+// WithStrokeSource serves stroke order data from src. Without one, stroke
+// order requests are answered as not found.
+func (s *KanjiService) WithStrokeSource(src StrokeSource) *KanjiService {
+	s.strokes = src
+	return s
+}
+
+// GetKanjiStrokes returns the KanjiVG stroke order SVG of a single kanji. The
+// kanji does not need to appear in any word.
+func (s *KanjiService) GetKanjiStrokes(ctx context.Context, character string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "KanjiService.GetKanjiStrokes")
+	defer span.End()
+
+	r, size := utf8.DecodeRuneInString(character)
+	if size == 0 || size != len(character) || !models.IsKanji(r) {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Stroke order is available for a single kanji only", nil)
+	}
+	if s.strokes == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Stroke order data is not available", nil)
+	}
+
+	data, err := cache.GetOrLoad(s.cache, "kanji:strokes:"+character, strokesCacheTTL, func() ([]byte, error) {
+		return s.strokes.Strokes(ctx, character)
+	})
+	if err != nil {
+		if errors.Is(err, ErrStrokesNotFound) {
+			return nil, NewServiceError(ErrCodeNotFound, "No stroke order data for this kanji", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to load stroke order data", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"lang-portal/backend_go/internal/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKanjiVGFile(t *testing.T) {
+	assert.Equal(t, "098df.svg", kanjiVGFile("食"))
+	assert.Equal(t, "04e00.svg", kanjiVGFile("一"))
+}
+
+func TestGetKanjiStrokes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "098df.svg"), []byte("<svg/>"), 0o644))
+
+	s := NewKanjiService(NewBaseService(nil, nil, nil, nil, nil), nil).WithStrokeSource(KanjiVGDir(dir))
+	svg, err := s.GetKanjiStrokes(context.Background(), "食")
+	require.NoError(t, err)
+	assert.Equal(t, "<svg/>", string(svg))
+
+	_, err = s.GetKanjiStrokes(context.Background(), "飲")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+
+	for _, invalid := range []string{"", "食べ", "た", "a", "々"} {
+		_, err = s.GetKanjiStrokes(context.Background(), invalid)
+		require.Error(t, err)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, invalid)
+	}
+}
+
+func TestKanjiVGHTTP_Cached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/kanji/098df.svg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<svg/>"))
+	}))
+	defer server.Close()
+
+	base := NewBaseService(nil, nil, nil, nil, nil).WithCache(cache.NewMemory())
+	s := NewKanjiService(base, nil).WithStrokeSource(&KanjiVGHTTP{BaseURL: server.URL + "/kanji/"})
+
+	for i := 0; i < 2; i++ {
+		svg, err := s.GetKanjiStrokes(context.Background(), "食")
+		require.NoError(t, err)
+		assert.Equal(t, "<svg/>", string(svg))
+	}
+	assert.Equal(t, 1, requests)
+
+	_, err := s.GetKanjiStrokes(context.Background(), "飲")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
@@ -1,20 +1,40 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"time"
 
+	"lang-portal/backend_go/internal/events"
 	"lang-portal/backend_go/internal/models"
 	"lang-portal/backend_go/internal/repository"
+	"lang-portal/backend_go/internal/speech"
 )
 
 // StudyService handles study-related business logic
 type StudyService struct {
 	*BaseService
+	launchKey  storedKey
+	recognizer speech.Recognizer
+	audio      AudioSource
+	goalRepo   repository.GoalRepositoryInterface
 }
 
 // NewStudyService creates a new study service
 func NewStudyService(base *BaseService) *StudyService {
-	return &StudyService{BaseService: base}
+	return &StudyService{
+		BaseService: base,
+		launchKey:   storedKey{setting: models.SettingLaunchSigningKey},
+	}
+}
+
+// WithAudioSource links the words of offline bundles to their recordings in
+// src. Without one, bundled words have no audio.
+func (s *StudyService) WithAudioSource(src AudioSource) *StudyService {
+	s.audio = src
+	return s
 }
 
 // StudyActivity represents a study activity
@@ -30,8 +50,72 @@ type StudySessionInfo struct {
 	ActivityName     string    `json:"activity_name"`
 	GroupName        string    `json:"group_name"`
 	StartTime        time.Time `json:"start_time"`
-	EndTime          time.Time `json:"end_time"` // Placeholder: using CreatedAt from model
+	EndTime          time.Time `json:"end_time"` // The completion time, or CreatedAt while the session is open
 	ReviewItemsCount int       `json:"review_items_count"`
+	SuccessRate      float64   `json:"success_rate"`
+	// AvgAnswerTimeMs is null when none of the session's reviews was timed
+	AvgAnswerTimeMs *float64 `json:"avg_answer_time_ms"`
+	// LaunchURL opens the session in its activity's app, when one is registered
+	LaunchURL string `json:"launch_url,omitempty"`
+}
+
+// newStudySessionInfo converts a session model (with Activity, Group and Reviews preloaded) to its DTO
+func newStudySessionInfo(session models.StudySession) StudySessionInfo {
+	end := session.CreatedAt
+	if session.CompletedAt != nil {
+		end = *session.CompletedAt
+	}
+	return StudySessionInfo{
+		ID:               session.ID,
+		ActivityName:     session.Activity.Name,
+		GroupName:        session.Group.Name,
+		StartTime:        session.CreatedAt,
+		EndTime:          end,
+		ReviewItemsCount: len(session.Reviews),
+		SuccessRate:      session.GetSuccessRate(),
+		AvgAnswerTimeMs:  session.AverageAnswerTimeMs(),
+		LaunchURL:        session.Activity.SessionLaunchURL(session.ID, session.GroupID),
+	}
+}
+
+// StudyActivityInfo represents a study activity with its display details and,
+// for registered apps, how to launch them
+type StudyActivityInfo struct {
+	ID           uint            `json:"id"`
+	Name         string          `json:"name"`
+	ThumbnailURL string          `json:"thumbnail_url"`
+	Description  string          `json:"description"`
+	LaunchURL    string          `json:"launch_url,omitempty"`
+	Modes        []string        `json:"modes"`
+	Capabilities []string        `json:"capabilities"`
+	Config       json.RawMessage `json:"config,omitempty"`
+	ReviewDedup  string          `json:"review_dedup,omitempty"`
+}
+
+func newStudyActivityInfo(a models.StudyActivity) StudyActivityInfo {
+	return StudyActivityInfo{
+		ID:           a.ID,
+		Name:         a.Name,
+		ThumbnailURL: a.ThumbnailURL,
+		Description:  a.Description,
+		LaunchURL:    a.LaunchURL,
+		Modes:        nonNil(a.Modes),
+		Capabilities: nonNil(a.Capabilities),
+		Config:       json.RawMessage(a.Config),
+		ReviewDedup:  a.ReviewDedup,
+	}
+}
+
+// StudyActivityDetail represents a study activity together with the groups it can be launched for
+type StudyActivityDetail struct {
+	StudyActivityInfo
+	AvailableGroups []GroupInfo `json:"available_groups"`
+}
+
+// GroupStudySession represents a study session with the success rate for a single group's words
+type GroupStudySession struct {
+	StudySessionInfo
+	GroupSuccessRate float64 `json:"group_success_rate"`
 }
 
 // StudySession represents a study session
@@ -47,26 +131,118 @@ type StudySession struct {
 
 // WordReview represents a word review
 type WordReview struct {
-	ID        uint      `json:"id"`
-	WordID    uint      `json:"word_id"`
-	Japanese  string    `json:"japanese"`
-	Romaji    string    `json:"romaji"`
-	English   string    `json:"english"`
-	Correct   bool      `json:"correct"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           uint               `json:"id"`
+	WordID       uint               `json:"word_id"`
+	Japanese     string             `json:"japanese"`
+	Romaji       string             `json:"romaji"`
+	English      string             `json:"english"`
+	Correct      bool               `json:"correct"`
+	Grade        models.ReviewGrade `json:"grade,omitempty"`
+	AnswerTimeMs *int               `json:"answer_time_ms,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// MaxBundleWords caps the number of words included in an offline session bundle.
+// Audio is linked rather than embedded, so the cap also bounds the size of a
+// bundle to a few hundred kilobytes.
+const MaxBundleWords = 500
+
+// GradingRules describes how answers should be graded when a session is run offline
+type GradingRules struct {
+	CaseSensitive bool `json:"case_sensitive"`
+	AcceptRomaji  bool `json:"accept_romaji"`
+	AcceptKana    bool `json:"accept_kana"`
+	TrimSpaces    bool `json:"trim_spaces"`
+}
+
+// BundleWord is a word of an offline bundle. AudioURL links its recording, for
+// the client to fetch while still online; it is empty for words without one.
+type BundleWord struct {
+	GroupWordRaw
+	AudioURL string `json:"audio_url,omitempty"`
+}
+
+// SessionBundle contains everything a client needs to run a study session offline
+// and sync its reviews back later
+type SessionBundle struct {
+	Session      StudySessionInfo `json:"session"`
+	Words        []BundleWord     `json:"words"`
+	TotalWords   int              `json:"total_words"`
+	Truncated    bool             `json:"truncated"`
+	ReviewOrder  string           `json:"review_order"`
+	WordLimit    int              `json:"word_limit,omitempty"`
+	GradingRules GradingRules     `json:"grading_rules"`
+	Settings     Preferences      `json:"settings"`
+	SyncURL      string           `json:"sync_url"`
+	GeneratedAt  time.Time        `json:"generated_at"`
 }
 
 // CreateStudyActivity creates a new study activity
-func (s *StudyService) CreateStudyActivity(activity *models.StudyActivity) error {
-	if err := s.studyRepo.CreateStudyActivity(activity); err != nil {
+func (s *StudyService) CreateStudyActivity(ctx context.Context, activity *models.StudyActivity) error {
+	ctx, span := tracer.Start(ctx, "StudyService.CreateStudyActivity")
+	defer span.End()
+
+	if err := s.studyRepo.CreateStudyActivity(ctx, activity); err != nil {
 		return NewServiceError(ErrCodeInternal, "Failed to create study activity", err)
 	}
 	return nil
 }
 
+// ListAllStudyActivities retrieves all study activities with their display details
+func (s *StudyService) ListAllStudyActivities(ctx context.Context) ([]StudyActivityInfo, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.ListAllStudyActivities")
+	defer span.End()
+
+	activities, err := s.studyRepo.ListAllStudyActivities(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list study activities", err)
+	}
+
+	infos := make([]StudyActivityInfo, len(activities))
+	for i, a := range activities {
+		infos[i] = newStudyActivityInfo(a)
+	}
+	return infos, nil
+}
+
+// GetStudyActivityDetail retrieves a study activity by ID along with the groups available to study with it
+func (s *StudyService) GetStudyActivityDetail(ctx context.Context, id uint) (*StudyActivityDetail, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetStudyActivityDetail")
+	defer span.End()
+
+	activity, err := s.studyRepo.GetStudyActivityByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Study activity not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study activity", err)
+	}
+
+	groups, err := s.groupRepo.ListAll(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch available groups", err)
+	}
+
+	availableGroups := make([]GroupInfo, len(groups))
+	for i, g := range groups {
+		availableGroups[i] = GroupInfo{
+			ID:   g.ID,
+			Name: g.Name,
+		}
+	}
+
+	return &StudyActivityDetail{
+		StudyActivityInfo: newStudyActivityInfo(*activity),
+		AvailableGroups:   availableGroups,
+	}, nil
+}
+
 // GetStudyActivity retrieves a study activity by ID
-func (s *StudyService) GetStudyActivity(id uint) (*StudyActivity, error) {
-	activity, err := s.studyRepo.GetStudyActivityByID(id)
+func (s *StudyService) GetStudyActivity(ctx context.Context, id uint) (*StudyActivity, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetStudyActivity")
+	defer span.End()
+
+	activity, err := s.studyRepo.GetStudyActivityByID(ctx, id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return nil, NewServiceError(ErrCodeNotFound, "Study activity not found", err)
@@ -81,8 +257,11 @@ func (s *StudyService) GetStudyActivity(id uint) (*StudyActivity, error) {
 }
 
 // ListStudyActivities retrieves a paginated list of study activities
-func (s *StudyService) ListStudyActivities(params PaginationParams) (*PaginatedResult[StudyActivity], error) {
-	result, err := s.studyRepo.ListStudyActivities(repository.PaginationParams{
+func (s *StudyService) ListStudyActivities(ctx context.Context, params PaginationParams) (*PaginatedResult[StudyActivity], error) {
+	ctx, span := tracer.Start(ctx, "StudyService.ListStudyActivities")
+	defer span.End()
+
+	result, err := s.studyRepo.ListStudyActivities(ctx, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
 	})
@@ -102,30 +281,77 @@ func (s *StudyService) ListStudyActivities(params PaginationParams) (*PaginatedR
 	return NewPaginatedResult(activities, result.TotalItems, params.Page, params.PageSize), nil
 }
 
-// CreateStudySession creates a new study session
-func (s *StudyService) CreateStudySession(session *models.StudySession) error {
-	// Verify group exists
-	if _, err := s.groupRepo.GetByID(session.GroupID); err != nil {
+// DefaultMinSessionWords is how many words a group needs to start a study
+// session when no preference is stored
+const DefaultMinSessionWords = 1
+
+// CreateStudySession creates a new study session and its word queue. The group
+// must have at least the preferred minimum of words, and the session's word
+// limit may not exceed MaxBundleWords. Without a review order the session gets
+// the preferred one.
+func (s *StudyService) CreateStudySession(ctx context.Context, session *models.StudySession) error {
+	ctx, span := tracer.Start(ctx, "StudyService.CreateStudySession")
+	defer span.End()
+
+	if session.ReviewOrder != "" && !ValidReviewOrder(session.ReviewOrder) {
+		return NewServiceError(ErrCodeInvalidInput, "Unknown review order: "+session.ReviewOrder, nil)
+	}
+	session.Client = models.NewClientInfo(session.Client.DeviceLabel, session.Client.UserAgent)
+	if len(session.Client.DeviceLabel) > models.MaxDeviceLabelLength {
+		return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("Device label must be at most %d characters", models.MaxDeviceLabelLength), nil)
+	}
+
+	if session.WordLimit < 0 || session.WordLimit > MaxBundleWords {
+		return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("Word limit must be between 0 and %d", MaxBundleWords), nil)
+	}
+
+	// Verify group exists and has enough words to study
+	group, err := s.groupRepo.GetSummary(ctx, session.GroupID)
+	if err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Group not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to fetch group", err)
 	}
+	minWords, err := s.minSessionWords(ctx)
+	if err != nil {
+		return err
+	}
+	if group.WordCount < int64(minWords) {
+		return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("Group has %d words; a study session needs at least %d", group.WordCount, minWords), nil)
+	}
 
 	// Verify activity exists
-	if _, err := s.studyRepo.GetStudyActivityByID(session.StudyActivityID); err != nil {
+	if _, err := s.studyRepo.GetStudyActivityByID(ctx, session.StudyActivityID); err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Study activity not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to fetch study activity", err)
 	}
 
-	if err := s.studyRepo.CreateStudySession(session); err != nil {
+	// The queue keeps the order the session starts with, so store it
+	if session.ReviewOrder == "" {
+		if session.ReviewOrder, err = s.defaultReviewOrder(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := s.studyRepo.CreateStudySession(ctx, session); err != nil {
 		return NewServiceError(ErrCodeInternal, "Failed to create study session", err)
 	}
+	// Without a stored queue one is generated when it is first requested
+	if err := s.generateSessionQueue(ctx, session); err != nil && s.logger != nil {
+		s.logger.Printf("Failed to generate the word queue of study session %d: %v", session.ID, err)
+	}
+	s.invalidateDashboard()
+	s.publish(events.TypeStudySessionCreated, map[string]uint{
+		"id":                session.ID,
+		"group_id":          session.GroupID,
+		"study_activity_id": session.StudyActivityID,
+	})
 
 	// Reload the session to populate associations for the response for POST
-	loadedSession, err := s.studyRepo.GetStudySessionByID(session.ID)
+	loadedSession, err := s.studyRepo.GetStudySessionByID(ctx, session.ID)
 	if err != nil {
 		// Log this error or handle as per requirements.
 		// For POST, we need the full model, so if this fails, it's an issue.
@@ -141,8 +367,11 @@ func (s *StudyService) CreateStudySession(session *models.StudySession) error {
 }
 
 // GetStudySession retrieves a study session by ID and transforms it to StudySessionInfo DTO.
-func (s *StudyService) GetStudySession(id uint) (*StudySessionInfo, error) {
-	modelSession, err := s.studyRepo.GetStudySessionByID(id) // This fetches models.StudySession
+func (s *StudyService) GetStudySession(ctx context.Context, id uint) (*StudySessionInfo, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetStudySession")
+	defer span.End()
+
+	modelSession, err := s.studyRepo.GetStudySessionByID(ctx, id) // This fetches models.StudySession
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return nil, NewServiceError(ErrCodeNotFound, "Study session not found", err)
@@ -150,19 +379,144 @@ func (s *StudyService) GetStudySession(id uint) (*StudySessionInfo, error) {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
 	}
 
-	return &StudySessionInfo{
-		ID:               modelSession.ID,
-		ActivityName:     modelSession.Activity.Name,
-		GroupName:        modelSession.Group.Name,
-		StartTime:        modelSession.CreatedAt,    // Map CreatedAt to StartTime
-		EndTime:          modelSession.CreatedAt,    // Placeholder: Map CreatedAt to EndTime
-		ReviewItemsCount: len(modelSession.Reviews), // Map Reviews length to ReviewItemsCount
+	info := newStudySessionInfo(*modelSession)
+	return &info, nil
+}
+
+// GetSessionBundle builds an offline bundle for a study session containing the
+// session's group words (capped at the session's word limit and MaxBundleWords)
+// with links to their audio, the grading rules and the preferences.
+// Without order the words are those of the session's queue; order reorders the
+// group words instead.
+func (s *StudyService) GetSessionBundle(ctx context.Context, id uint, order string) (*SessionBundle, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetSessionBundle")
+	defer span.End()
+
+	modelSession, err := s.studyRepo.GetStudySessionByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Study session not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
+	}
+
+	queued := order == ""
+	if order == "" {
+		order = modelSession.ReviewOrder
+	}
+	if order == "" {
+		if order, err = s.defaultReviewOrder(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if !ValidReviewOrder(order) {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Unknown review order: "+order, nil)
+	}
+
+	words, err := s.wordRepo.GetWordsByGroupRaw(ctx, modelSession.GroupID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get group words", err)
+	}
+	totalWords := len(words)
+
+	var bundled []models.Word
+	if queued {
+		items, err := s.sessionQueue(ctx, modelSession)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]models.Word, len(words))
+		for _, w := range words {
+			byID[w.ID] = w
+		}
+		bundled = make([]models.Word, len(items))
+		for i, item := range items {
+			w, ok := byID[item.WordID]
+			if !ok {
+				w = models.Word{ID: item.WordID, Term: item.Term, Reading: item.Reading, English: item.English}
+			}
+			bundled[i] = w
+		}
+	} else {
+		orderWords(words, order, int64(modelSession.ID))
+		if limit := sessionWordLimit(modelSession); totalWords > limit {
+			words = words[:limit]
+		}
+		bundled = words
+	}
+
+	bundleWords := make([]BundleWord, len(bundled))
+	for i := range bundled {
+		w := &bundled[i]
+		bundleWords[i] = BundleWord{GroupWordRaw: GroupWordRaw{
+			ID:       w.ID,
+			Japanese: w.Term,
+			Romaji:   w.Reading,
+			English:  w.English,
+		}}
+		if s.audio == nil {
+			continue
+		}
+		has, err := s.audio.Has(ctx, w)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to look up word audio", err)
+		}
+		if has {
+			bundleWords[i].AudioURL = wordAudioURL(w.ID)
+		}
+	}
+
+	settings, err := s.preferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionBundle{
+		Session:     newStudySessionInfo(*modelSession),
+		Words:       bundleWords,
+		TotalWords:  totalWords,
+		Truncated:   len(bundleWords) < totalWords,
+		ReviewOrder: order,
+		WordLimit:   modelSession.WordLimit,
+		GradingRules: GradingRules{
+			CaseSensitive: false,
+			AcceptRomaji:  true,
+			AcceptKana:    true,
+			TrimSpaces:    true,
+		},
+		Settings:    *settings,
+		SyncURL:     fmt.Sprintf("/api/v1/study/sessions/%d/reviews", id),
+		GeneratedAt: time.Now(),
 	}, nil
 }
 
+// minSessionWords returns the preferred minimum of words a group needs to
+// start a study session, falling back to DefaultMinSessionWords when it is
+// unset or out of range
+func (s *BaseService) minSessionWords(ctx context.Context) (int, error) {
+	if s.settingRepo == nil {
+		return DefaultMinSessionWords, nil
+	}
+	raw, err := s.settingRepo.Get(ctx, models.SettingMinSessionWords)
+	if err != nil && err != repository.ErrNotFound {
+		return 0, NewServiceError(ErrCodeInternal, "Failed to get session preference", err)
+	}
+	if n, convErr := strconv.Atoi(raw); err == nil && convErr == nil && validMinSessionWords(n) {
+		return n, nil
+	}
+	return DefaultMinSessionWords, nil
+}
+
+func validMinSessionWords(n int) bool {
+	return n >= 1 && n <= MaxBundleWords
+}
+
 // ListStudySessions retrieves a paginated list of study sessions
-func (s *StudyService) ListStudySessions(params PaginationParams) (*PaginatedResult[StudySessionInfo], error) {
-	result, err := s.studyRepo.ListStudySessions(repository.PaginationParams{
+func (s *StudyService) ListStudySessions(ctx context.Context, params PaginationParams) (*PaginatedResult[StudySessionInfo], error) {
+	ctx, span := tracer.Start(ctx, "StudyService.ListStudySessions")
+	defer span.End()
+
+	result, err := s.studyRepo.ListStudySessions(ctx, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
 	})
@@ -173,22 +527,18 @@ func (s *StudyService) ListStudySessions(params PaginationParams) (*PaginatedRes
 	// Transform sessions
 	infos := make([]StudySessionInfo, len(result.Items))
 	for i, modelSession := range result.Items {
-		infos[i] = StudySessionInfo{
-			ID:               modelSession.ID,
-			ActivityName:     modelSession.Activity.Name,
-			GroupName:        modelSession.Group.Name,
-			StartTime:        modelSession.CreatedAt,
-			EndTime:          modelSession.CreatedAt, // Placeholder
-			ReviewItemsCount: len(modelSession.Reviews),
-		}
+		infos[i] = newStudySessionInfo(modelSession)
 	}
 
 	return NewPaginatedResult(infos, result.TotalItems, params.Page, params.PageSize), nil
 }
 
 // GetStudySessionsByGroup retrieves study sessions for a specific group
-func (s *StudyService) GetStudySessionsByGroup(groupID uint, params PaginationParams) (*PaginatedResult[StudySessionInfo], error) {
-	result, err := s.studyRepo.GetStudySessionsByGroup(groupID, repository.PaginationParams{
+func (s *StudyService) GetStudySessionsByGroup(ctx context.Context, groupID uint, params PaginationParams) (*PaginatedResult[StudySessionInfo], error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetStudySessionsByGroup")
+	defer span.End()
+
+	result, err := s.studyRepo.GetStudySessionsByGroup(ctx, groupID, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
 	})
@@ -199,22 +549,18 @@ func (s *StudyService) GetStudySessionsByGroup(groupID uint, params PaginationPa
 	// Transform sessions
 	infos := make([]StudySessionInfo, len(result.Items))
 	for i, modelSession := range result.Items {
-		infos[i] = StudySessionInfo{
-			ID:               modelSession.ID,
-			ActivityName:     modelSession.Activity.Name,
-			GroupName:        modelSession.Group.Name,
-			StartTime:        modelSession.CreatedAt,
-			EndTime:          modelSession.CreatedAt, // Placeholder
-			ReviewItemsCount: len(modelSession.Reviews),
-		}
+		infos[i] = newStudySessionInfo(modelSession)
 	}
 
 	return NewPaginatedResult(infos, result.TotalItems, params.Page, params.PageSize), nil
 }
 
 // GetStudySessionsByActivity retrieves study sessions for a specific activity
-func (s *StudyService) GetStudySessionsByActivity(activityID uint, params PaginationParams) (*PaginatedResult[StudySessionInfo], error) {
-	result, err := s.studyRepo.GetStudySessionsByActivity(activityID, repository.PaginationParams{
+func (s *StudyService) GetStudySessionsByActivity(ctx context.Context, activityID uint, params PaginationParams) (*PaginatedResult[StudySessionInfo], error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetStudySessionsByActivity")
+	defer span.End()
+
+	result, err := s.studyRepo.GetStudySessionsByActivity(ctx, activityID, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
 	})
@@ -225,31 +571,71 @@ func (s *StudyService) GetStudySessionsByActivity(activityID uint, params Pagina
 	// Transform sessions
 	infos := make([]StudySessionInfo, len(result.Items))
 	for i, modelSession := range result.Items {
-		infos[i] = StudySessionInfo{
-			ID:               modelSession.ID,
-			ActivityName:     modelSession.Activity.Name,
-			GroupName:        modelSession.Group.Name,
-			StartTime:        modelSession.CreatedAt,
-			EndTime:          modelSession.CreatedAt, // Placeholder
-			ReviewItemsCount: len(modelSession.Reviews),
-		}
+		infos[i] = newStudySessionInfo(modelSession)
 	}
 
 	return NewPaginatedResult(infos, result.TotalItems, params.Page, params.PageSize), nil
 }
 
-// AddWordReview adds a word review to a study session
-func (s *StudyService) AddWordReview(sessionID uint, review *models.WordReview) error {
+// GetGroupStudySessions retrieves study sessions that reviewed words from a group, along with
+// the success rate restricted to that group's words
+func (s *StudyService) GetGroupStudySessions(ctx context.Context, groupID uint, params PaginationParams) (*PaginatedResult[GroupStudySession], error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetGroupStudySessions")
+	defer span.End()
+
+	result, err := s.studyRepo.GetGroupStudySessions(ctx, groupID, repository.PaginationParams{
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get group study sessions", err)
+	}
+
+	sessions := make([]GroupStudySession, len(result.Items))
+	for i, modelSession := range result.Items {
+		totalReviews, correctReviews, err := s.studyRepo.GetGroupSessionReviewStats(ctx, modelSession.ID, groupID)
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to get group session statistics", err)
+		}
+
+		groupSuccessRate := 0.0
+		if totalReviews > 0 {
+			groupSuccessRate = float64(correctReviews) / float64(totalReviews) * 100
+		}
+
+		sessions[i] = GroupStudySession{
+			StudySessionInfo: newStudySessionInfo(modelSession),
+			GroupSuccessRate: groupSuccessRate,
+		}
+	}
+
+	return NewPaginatedResult(sessions, result.TotalItems, params.Page, params.PageSize), nil
+}
+
+// AddWordReview adds a word review to a study session. Expired sessions take
+// no more reviews.
+func (s *StudyService) AddWordReview(ctx context.Context, sessionID uint, review *models.WordReview) error {
+	ctx, span := tracer.Start(ctx, "StudyService.AddWordReview")
+	defer span.End()
+
+	if err := checkReview(review); err != nil {
+		return err
+	}
+
 	// Verify session exists
-	if _, err := s.studyRepo.GetStudySessionByID(sessionID); err != nil {
+	session, err := s.studyRepo.GetStudySessionByID(ctx, sessionID)
+	if err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Study session not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
 	}
+	if session.ExpiredAt != nil {
+		return sessionExpired()
+	}
 
 	// Verify word exists
-	if _, err := s.wordRepo.GetByID(review.WordID); err != nil {
+	if _, err := s.wordRepo.GetByID(ctx, review.WordID); err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Word not found", err)
 		}
@@ -259,23 +645,57 @@ func (s *StudyService) AddWordReview(sessionID uint, review *models.WordReview)
 	// Set the session ID
 	review.StudySessionID = sessionID
 
-	if err := s.studyRepo.AddWordReview(review); err != nil {
+	if err := s.studyRepo.AddWordReview(ctx, review); err != nil {
+		if err == repository.ErrDuplicateReview {
+			return NewServiceError(ErrCodeConflict, "Word has already been reviewed in this session", err)
+		}
 		return NewServiceError(ErrCodeInternal, "Failed to add word review", err)
 	}
+	s.awardReviewXP(ctx, session, *review)
+	s.invalidateDashboard()
+	s.publish(events.TypeWordReviewCreated, map[string]interface{}{
+		"study_session_id": sessionID,
+		"word_id":          review.WordID,
+		"correct":          review.Correct,
+		"grade":            review.EffectiveGrade(),
+	})
+	return nil
+}
+
+// sessionExpired is the error for changes to a session the expiry job closed
+func sessionExpired() error {
+	return NewServiceError(ErrCodeConflict, "Study session has expired; start a new one", nil)
+}
+
+// checkReview validates the optional grade and answer time of a submitted
+// review. A graded review is correct unless graded again.
+func checkReview(review *models.WordReview) error {
+	if !review.Grade.Valid() {
+		return NewServiceError(ErrCodeInvalidInput, "grade must be again, hard, good or easy", nil)
+	}
+	if !models.ValidAnswerTime(review.AnswerTimeMs) {
+		return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("answer_time_ms must be between 0 and %d", models.MaxAnswerTimeMs), nil)
+	}
+	if review.Grade != "" {
+		review.Correct = review.Grade.Correct()
+	}
 	return nil
 }
 
 // GetWordReviewsBySession retrieves word reviews for a specific study session
-func (s *StudyService) GetWordReviewsBySession(sessionID uint, params PaginationParams) (*PaginatedResult[WordReview], error) {
+func (s *StudyService) GetWordReviewsBySession(ctx context.Context, sessionID uint, params PaginationParams) (*PaginatedResult[WordReview], error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetWordReviewsBySession")
+	defer span.End()
+
 	// Verify session exists
-	if _, err := s.studyRepo.GetStudySessionByID(sessionID); err != nil {
+	if _, err := s.studyRepo.GetStudySessionByID(ctx, sessionID); err != nil {
 		if err == repository.ErrNotFound {
 			return nil, NewServiceError(ErrCodeNotFound, "Study session not found", err)
 		}
 		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
 	}
 
-	result, err := s.studyRepo.GetWordReviewsBySession(sessionID, repository.PaginationParams{
+	result, err := s.studyRepo.GetWordReviewsBySession(ctx, sessionID, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
 	})
@@ -287,13 +707,15 @@ func (s *StudyService) GetWordReviewsBySession(sessionID uint, params Pagination
 	reviews := make([]WordReview, len(result.Items))
 	for i, r := range result.Items {
 		reviews[i] = WordReview{
-			ID:        r.ID,
-			WordID:    r.WordID,
-			Japanese:  r.Word.Japanese,
-			Romaji:    r.Word.Romaji,
-			English:   r.Word.English,
-			Correct:   r.Correct,
-			CreatedAt: r.CreatedAt,
+			ID:           r.ID,
+			WordID:       r.WordID,
+			Japanese:     r.Word.Term,
+			Romaji:       r.Word.Reading,
+			English:      r.Word.English,
+			Correct:      r.Correct,
+			Grade:        r.Grade,
+			AnswerTimeMs: r.AnswerTimeMs,
+			CreatedAt:    r.CreatedAt,
 		}
 	}
 
@@ -301,8 +723,11 @@ func (s *StudyService) GetWordReviewsBySession(sessionID uint, params Pagination
 }
 
 // GetLastStudySession retrieves the most recent study session
-func (s *StudyService) GetLastStudySession() (*StudySessionInfo, error) {
-	modelSession, err := s.studyRepo.GetLastStudySession()
+func (s *StudyService) GetLastStudySession(ctx context.Context) (*StudySessionInfo, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetLastStudySession")
+	defer span.End()
+
+	modelSession, err := s.studyRepo.GetLastStudySession(ctx)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			// For GetLastStudySession, returning nil (which becomes null in JSON) is fine if no session exists.
@@ -312,19 +737,16 @@ func (s *StudyService) GetLastStudySession() (*StudySessionInfo, error) {
 	}
 
 	// If a session is found, transform it to StudySessionInfo
-	return &StudySessionInfo{
-		ID:               modelSession.ID,
-		ActivityName:     modelSession.Activity.Name,
-		GroupName:        modelSession.Group.Name,
-		StartTime:        modelSession.CreatedAt,
-		EndTime:          modelSession.CreatedAt, // Placeholder
-		ReviewItemsCount: len(modelSession.Reviews),
-	}, nil
+	info := newStudySessionInfo(*modelSession)
+	return &info, nil
 }
 
 // GetStudyStats retrieves overall study statistics
-func (s *StudyService) GetStudyStats() (totalSessions, totalReviews, correctReviews int64, err error) {
-	totalSessions, totalReviews, correctReviews, err = s.studyRepo.GetStudyStats()
+func (s *StudyService) GetStudyStats(ctx context.Context) (totalSessions, totalReviews, correctReviews int64, err error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetStudyStats")
+	defer span.End()
+
+	totalSessions, totalReviews, correctReviews, err = s.studyRepo.GetStudyStats(ctx)
 	if err != nil {
 		return 0, 0, 0, NewServiceError(ErrCodeInternal, "Failed to get study statistics", err)
 	}
@@ -332,8 +754,11 @@ func (s *StudyService) GetStudyStats() (totalSessions, totalReviews, correctRevi
 }
 
 // GetStudyStreak retrieves the current study streak in days
-func (s *StudyService) GetStudyStreak() (int, error) {
-	streak, err := s.studyRepo.GetStudyStreak()
+func (s *StudyService) GetStudyStreak(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetStudyStreak")
+	defer span.End()
+
+	streak, err := s.studyRepo.GetStudyStreak(ctx)
 	if err != nil {
 		return 0, NewServiceError(ErrCodeInternal, "Failed to get study streak", err)
 	}
@@ -341,18 +766,13 @@ func (s *StudyService) GetStudyStreak() (int, error) {
 }
 
 // GetActiveGroups retrieves the number of groups that have been studied
-func (s *StudyService) GetActiveGroups() (int64, error) {
-	count, err := s.studyRepo.GetActiveGroups()
+func (s *StudyService) GetActiveGroups(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetActiveGroups")
+	defer span.End()
+
+	count, err := s.studyRepo.GetActiveGroups(ctx)
 	if err != nil {
 		return 0, NewServiceError(ErrCodeInternal, "Failed to get active groups", err)
 	}
 	return count, nil
 }
-
-// ResetStudyHistory resets all study-related data
-func (s *StudyService) ResetStudyHistory() error {
-	if err := s.studyRepo.ResetStudyHistory(); err != nil {
-		return NewServiceError(ErrCodeInternal, "Failed to reset study history", err)
-	}
-	return nil
-}
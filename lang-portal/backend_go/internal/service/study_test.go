@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStudyService_CreateStudySession_RequiresGroupWords(t *testing.T) {
+	groupRepo := new(mockGroupRepository)
+	groupRepo.On("GetSummary", uint(1)).Return(&repository.GroupSummary{Group: models.Group{ID: 1}}, nil)
+	groupRepo.On("GetSummary", uint(2)).Return(nil, repository.ErrNotFound)
+	s := NewStudyService(NewBaseService(nil, groupRepo, nil, nil, nil))
+	ctx := context.Background()
+
+	err := s.CreateStudySession(ctx, &models.StudySession{GroupID: 1, StudyActivityID: 1})
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+	assert.Contains(t, err.Error(), "at least 1")
+
+	err = s.CreateStudySession(ctx, &models.StudySession{GroupID: 2, StudyActivityID: 1})
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+
+	for _, limit := range []int{-1, MaxBundleWords + 1} {
+		err = s.CreateStudySession(ctx, &models.StudySession{GroupID: 1, StudyActivityID: 1, WordLimit: limit})
+		require.Error(t, err)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, "limit %d", limit)
+	}
+}
+
+// fakeQueueRepository keeps the word queue of a single study session
+type fakeQueueRepository struct {
+	repository.StudyRepositoryInterface
+	session  models.StudySession
+	words    map[uint]models.Word
+	queue    []uint
+	reviewed map[uint]bool
+}
+
+func (f *fakeQueueRepository) GetStudySessionByID(ctx context.Context, id uint) (*models.StudySession, error) {
+	if id != f.session.ID {
+		return nil, repository.ErrNotFound
+	}
+	session := f.session
+	return &session, nil
+}
+
+func (f *fakeQueueRepository) CreateSessionWords(ctx context.Context, sessionID uint, wordIDs []uint) error {
+	if len(f.queue) == 0 {
+		f.queue = wordIDs
+	}
+	return nil
+}
+
+func (f *fakeQueueRepository) GetSessionQueue(ctx context.Context, sessionID uint) ([]repository.SessionQueueItem, error) {
+	var items []repository.SessionQueueItem
+	for i, id := range f.queue {
+		w := f.words[id]
+		items = append(items, repository.SessionQueueItem{Position: i, WordID: id, Term: w.Term, Reading: w.Reading, English: w.English, Reviewed: f.reviewed[id]})
+	}
+	return items, nil
+}
+
+func queueFixture(limit int) (*fakeQueueRepository, *mockWordRepository) {
+	due := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	words := []models.Word{{ID: 1, Term: "一"}, {ID: 2, Term: "二"}, {ID: 3, Term: "三", NextDueAt: &due, LastReviewedAt: &due}}
+	repo := &fakeQueueRepository{
+		session:  models.StudySession{ID: 9, GroupID: 4, ReviewOrder: ReviewOrderDueFirst, WordLimit: limit},
+		words:    map[uint]models.Word{},
+		reviewed: map[uint]bool{},
+	}
+	for _, w := range words {
+		repo.words[w.ID] = w
+	}
+	wordRepo := new(mockWordRepository)
+	wordRepo.On("GetWordsByGroupRaw", uint(4)).Return(words, nil)
+	return repo, wordRepo
+}
+
+func TestStudyService_GetSessionBundle_WordLimit(t *testing.T) {
+	repo, wordRepo := queueFixture(2)
+	s := NewStudyService(NewBaseService(wordRepo, nil, repo, nil, nil))
+
+	bundle, err := s.GetSessionBundle(context.Background(), 9, "")
+	require.NoError(t, err)
+	assert.Equal(t, ReviewOrderDueFirst, bundle.ReviewOrder)
+	assert.Equal(t, 2, bundle.WordLimit)
+	assert.Equal(t, 3, bundle.TotalWords)
+	assert.True(t, bundle.Truncated)
+	require.Len(t, bundle.Words, 2)
+	assert.Equal(t, uint(3), bundle.Words[0].ID, "the scheduled word comes first")
+	assert.Equal(t, []uint{3, 1}, repo.queue, "the bundle follows the stored queue")
+
+	bundle, err = s.GetSessionBundle(context.Background(), 9, ReviewOrderOldestFirst)
+	require.NoError(t, err)
+	require.Len(t, bundle.Words, 2)
+	assert.Equal(t, uint(1), bundle.Words[0].ID, "an explicit order reorders the group words")
+}
+
+func TestStudyService_GetSessionBundle_AudioAndSettings(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "一.mp3"), []byte("ID3"), 0o644))
+	repo, wordRepo := queueFixture(0)
+	s := NewStudyService(NewBaseService(wordRepo, nil, repo, nil, nil)).WithAudioSource(AudioDir(dir))
+
+	bundle, err := s.GetSessionBundle(context.Background(), 9, ReviewOrderOldestFirst)
+	require.NoError(t, err)
+	require.Len(t, bundle.Words, 3)
+	audio := map[uint]string{}
+	for _, w := range bundle.Words {
+		audio[w.ID] = w.AudioURL
+	}
+	assert.Equal(t, map[uint]string{1: "/api/v1/words/1/audio", 2: "", 3: ""}, audio)
+	assert.Equal(t, Preferences{
+		ReviewOrder:            DefaultReviewOrder,
+		MasteredMinReviews:     DefaultMasteredMinReviews,
+		MasteredMinSuccessRate: DefaultMasteredMinSuccessRate,
+		MinSessionWords:        DefaultMinSessionWords,
+	}, bundle.Settings, "unset preferences are bundled with their defaults")
+
+	// Without an audio source no word links audio
+	bundle, err = NewStudyService(NewBaseService(wordRepo, nil, repo, nil, nil)).GetSessionBundle(context.Background(), 9, "")
+	require.NoError(t, err)
+	for _, w := range bundle.Words {
+		assert.Empty(t, w.AudioURL)
+	}
+}
+
+func TestStudyService_GetSessionQueue(t *testing.T) {
+	repo, wordRepo := queueFixture(0)
+	s := NewStudyService(NewBaseService(wordRepo, nil, repo, nil, nil))
+	ctx := context.Background()
+
+	queue, err := s.GetSessionQueue(ctx, 9)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 1, 2}, repo.queue, "a missing queue is generated on request")
+	assert.Equal(t, 0, queue.Next)
+	assert.Equal(t, 3, queue.Remaining)
+	assert.Equal(t, "三", queue.Words[0].Japanese)
+
+	repo.reviewed[3], repo.reviewed[2] = true, true
+	queue, err = s.GetSessionQueue(ctx, 9)
+	require.NoError(t, err)
+	assert.Equal(t, 1, queue.Next)
+	assert.Equal(t, 1, queue.Remaining)
+	assert.True(t, queue.Words[0].Reviewed)
+
+	repo.reviewed[1] = true
+	queue, err = s.GetSessionQueue(ctx, 9)
+	require.NoError(t, err)
+	assert.Equal(t, -1, queue.Next)
+	assert.Zero(t, queue.Remaining)
+
+	_, err = s.GetSessionQueue(ctx, 10)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+	wordRepo.AssertNumberOfCalls(t, "GetWordsByGroupRaw", 1)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Group suggestion keys. Part suggestions are keyed "weakest_part:<part>".
+const (
+	SuggestionWeakest        = "weakest"
+	SuggestionWeakestPart    = "weakest_part"
+	SuggestionAddedThisMonth = "added_this_month"
+)
+
+const (
+	// suggestionSize caps the words in a weakest-words suggestion
+	suggestionSize = 20
+	// maxSuggestionWords caps the words in any suggestion
+	maxSuggestionWords = 100
+	// minSuggestionWords is the smallest suggestion worth proposing
+	minSuggestionWords = 3
+	// maxPartSuggestions caps the number of per-part suggestions
+	maxPartSuggestions = 3
+)
+
+// GroupSuggestion is a proposed group derived from the word list and review history
+type GroupSuggestion struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	WordCount   int    `json:"word_count"`
+	WordIDs     []uint `json:"word_ids"`
+}
+
+// SuggestGroups proposes groups from review behavior: the weakest words overall,
+// the weakest words of the most reviewed parts of speech, and the words added
+// this month. Suggestions with fewer than minSuggestionWords words are left out.
+func (s *GroupService) SuggestGroups(ctx context.Context) ([]GroupSuggestion, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.SuggestGroups")
+	defer span.End()
+
+	now := time.Now()
+
+	parts, err := s.wordRepo.GetReviewedParts(ctx, minSuggestionWords, maxPartSuggestions)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to analyze reviewed words", err)
+	}
+
+	keys := []string{SuggestionWeakest}
+	for _, part := range parts {
+		keys = append(keys, SuggestionWeakestPart+":"+part)
+	}
+	keys = append(keys, SuggestionAddedThisMonth)
+
+	suggestions := make([]GroupSuggestion, 0, len(keys))
+	for _, key := range keys {
+		suggestion, err := s.buildSuggestion(ctx, key, now)
+		if err != nil {
+			return nil, err
+		}
+		if suggestion.WordCount >= minSuggestionWords {
+			suggestions = append(suggestions, *suggestion)
+		}
+	}
+	return suggestions, nil
+}
+
+// CreateGroupFromSuggestion recomputes the suggestion with the given key and creates
+// a group holding its words. An empty name uses the suggested name.
+func (s *GroupService) CreateGroupFromSuggestion(ctx context.Context, key, name string) (*models.Group, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.CreateGroupFromSuggestion")
+	defer span.End()
+
+	suggestion, err := s.buildSuggestion(ctx, key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.WordCount == 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Suggestion has no words", nil)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = suggestion.Name
+	}
+	existing, err := s.groupRepo.GetByName(ctx, name, models.DefaultLanguage)
+	if err != nil && err != repository.ErrNotFound {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to check for existing group", err)
+	}
+	if existing != nil {
+		return nil, NewServiceError(ErrCodeInvalidInput, "A group with this name already exists", nil)
+	}
+
+	group := &models.Group{Name: name}
+	if err := s.groupRepo.CreateWithWords(ctx, group, suggestion.WordIDs); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create group", err)
+	}
+	return group, nil
+}
+
+// buildSuggestion evaluates the suggestion identified by key as of now
+func (s *GroupService) buildSuggestion(ctx context.Context, key string, now time.Time) (*GroupSuggestion, error) {
+	var filter repository.WordFilter
+	var describe func(count int) (name, description string)
+
+	switch {
+	case key == SuggestionWeakest:
+		filter = repository.WordFilter{ReviewedOnly: true, WeakestFirst: true, Limit: suggestionSize}
+		describe = func(count int) (string, string) {
+			return fmt.Sprintf("Your %d weakest words", count),
+				"Reviewed words with the lowest recent accuracy"
+		}
+	case strings.HasPrefix(key, SuggestionWeakestPart+":"):
+		part := strings.TrimPrefix(key, SuggestionWeakestPart+":")
+		if part == "" {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Suggestion is missing a part of speech", nil)
+		}
+		filter = repository.WordFilter{Part: part, ReviewedOnly: true, WeakestFirst: true, Limit: suggestionSize}
+		describe = func(count int) (string, string) {
+			return fmt.Sprintf("Your %d weakest words: %s", count, part),
+				fmt.Sprintf("Reviewed words tagged %q with the lowest recent accuracy", part)
+		}
+	case key == SuggestionAddedThisMonth:
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		filter = repository.WordFilter{CreatedSince: monthStart, Limit: maxSuggestionWords}
+		describe = func(count int) (string, string) {
+			return "Words added in " + now.Format("January 2006"),
+				"Words added since the start of the month, newest first"
+		}
+	default:
+		return nil, NewServiceError(ErrCodeInvalidInput, "Unknown suggestion: "+key, nil)
+	}
+
+	words, err := s.wordRepo.FindWords(ctx, filter)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to find words for suggestion", err)
+	}
+
+	ids := make([]uint, len(words))
+	for i, w := range words {
+		ids[i] = w.ID
+	}
+	name, description := describe(len(ids))
+	return &GroupSuggestion{
+		Key:         key,
+		Name:        name,
+		Description: description,
+		WordCount:   len(ids),
+		WordIDs:     ids,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupService_SuggestGroups(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	groupService := NewGroupService(NewBaseService(mockRepo, nil, nil, nil, nil))
+
+	threeWords := []models.Word{{ID: 1}, {ID: 2}, {ID: 3}}
+	mockRepo.On("GetReviewedParts", minSuggestionWords, maxPartSuggestions).Return([]string{"verb"}, nil)
+	mockRepo.On("FindWords", repository.WordFilter{ReviewedOnly: true, WeakestFirst: true, Limit: suggestionSize}).Return(threeWords, nil)
+	mockRepo.On("FindWords", repository.WordFilter{Part: "verb", ReviewedOnly: true, WeakestFirst: true, Limit: suggestionSize}).Return(threeWords, nil)
+	// Too few words added this month to be worth a group
+	mockRepo.On("FindWords", mock.MatchedBy(func(f repository.WordFilter) bool { return !f.CreatedSince.IsZero() })).Return([]models.Word{{ID: 4}}, nil)
+
+	suggestions, err := groupService.SuggestGroups(context.Background())
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, SuggestionWeakest, suggestions[0].Key)
+	assert.Equal(t, "Your 3 weakest words", suggestions[0].Name)
+	assert.Equal(t, []uint{1, 2, 3}, suggestions[0].WordIDs)
+	assert.Equal(t, "weakest_part:verb", suggestions[1].Key)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGroupService_CreateGroupFromSuggestion_InvalidInput(t *testing.T) {
+	groupService := NewGroupService(NewBaseService(nil, nil, nil, nil, nil))
+
+	for _, key := range []string{"", "hardest", "weakest_part:"} {
+		_, err := groupService.CreateGroupFromSuggestion(context.Background(), key, "")
+		assert.Error(t, err)
+		assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code, key)
+	}
+}
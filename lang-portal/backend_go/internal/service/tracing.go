@@ -0,0 +1,8 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer records a span for each exported service method. Spans nest under the
+// request span started by the HTTP middleware and parent the query spans
+// recorded by the database plugin.
+var tracer = otel.Tracer("lang-portal/backend_go/internal/service")
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/moderation"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Tutor limits
+const (
+	// MaxTutorMessageLength caps a learner's message, in characters
+	MaxTutorMessageLength = 2000
+	// maxTutorTitleLength caps a conversation title, in characters
+	maxTutorTitleLength = 200
+	// tutorHistory is the number of earlier messages sent with each turn
+	tutorHistory = 20
+	// tutorWeakWords caps the weak words a conversation is about
+	tutorWeakWords = 15
+	// tutorReplyTokens caps the length of a reply
+	tutorReplyTokens = 800
+	// maxTutorReplyLength caps a reply that passes moderation, in characters
+	maxTutorReplyLength = 4000
+)
+
+// noWeakWords stands in for the weak words of a learner without any
+const noWeakWords = "(none yet)"
+
+// TutorService manages chats with the AI tutor
+type TutorService struct {
+	*BaseService
+	tutorRepo repository.TutorRepositoryInterface
+}
+
+// NewTutorService creates a new tutor service
+func NewTutorService(base *BaseService, tutorRepo repository.TutorRepositoryInterface) *TutorService {
+	return &TutorService{BaseService: base, tutorRepo: tutorRepo}
+}
+
+// CreateConversation starts a conversation for user. The learner's recent
+// mistakes and weakest words at this point become the conversation's context,
+// so the tutor can bring them up.
+func (s *TutorService) CreateConversation(ctx context.Context, user, title string) (*models.Conversation, error) {
+	ctx, span := tracer.Start(ctx, "TutorService.CreateConversation")
+	defer span.End()
+
+	if s.llm == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Tutor is not available", nil)
+	}
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) > maxTutorTitleLength {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Title is too long", nil)
+	}
+
+	weak, err := s.weakWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conversation := &models.Conversation{Title: title, User: user, Context: tutorContext(weak)}
+	if err := s.tutorRepo.CreateConversation(ctx, conversation); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to create conversation", err)
+	}
+	conversation.Messages = []models.Message{}
+	return conversation, nil
+}
+
+// GetConversation retrieves one of user's conversations with its messages
+func (s *TutorService) GetConversation(ctx context.Context, user string, id uint) (*models.Conversation, error) {
+	ctx, span := tracer.Start(ctx, "TutorService.GetConversation")
+	defer span.End()
+
+	return s.getConversation(ctx, user, id)
+}
+
+// ListConversations retrieves a paginated list of user's conversations, most
+// recently active first
+func (s *TutorService) ListConversations(ctx context.Context, user string, params PaginationParams) (*PaginatedResult[models.Conversation], error) {
+	ctx, span := tracer.Start(ctx, "TutorService.ListConversations")
+	defer span.End()
+
+	result, err := s.tutorRepo.ListConversations(ctx, user, repository.PaginationParams{
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list conversations", err)
+	}
+	return NewPaginatedResult(result.Items, result.TotalItems, params.Page, params.PageSize), nil
+}
+
+// DeleteConversation deletes one of user's conversations
+func (s *TutorService) DeleteConversation(ctx context.Context, user string, id uint) error {
+	ctx, span := tracer.Start(ctx, "TutorService.DeleteConversation")
+	defer span.End()
+
+	if _, err := s.getConversation(ctx, user, id); err != nil {
+		return err
+	}
+	if err := s.tutorRepo.DeleteConversation(ctx, id); err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Conversation not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to delete conversation", err)
+	}
+	return nil
+}
+
+// SendMessage sends a learner's message in one of user's conversations and
+// returns the tutor's reply. If onDelta is not nil the reply is streamed to it
+// as it is written. The message and reply are stored together once the reply
+// is complete and has passed moderation, so a failed turn leaves the
+// conversation as it was. A rejected reply is asked for again unless part of it
+// has been streamed. The reply counts towards client's AI quota.
+func (s *TutorService) SendMessage(ctx context.Context, user, client string, conversationID uint, content string, onDelta func(string) error) (*models.Message, error) {
+	ctx, span := tracer.Start(ctx, "TutorService.SendMessage")
+	defer span.End()
+
+	if s.llm == nil {
+		return nil, NewServiceError(ErrCodeNotFound, "Tutor is not available", nil)
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Message is empty", nil)
+	}
+	if utf8.RuneCountInString(content) > MaxTutorMessageLength {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Message is too long", nil)
+	}
+
+	conversation, err := s.getConversation(ctx, user, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	level, err := s.learnerLevel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	weak := conversation.Context
+	if weak == "" {
+		weak = noWeakWords
+	}
+	system, err := s.renderPrompt(ctx, PromptTutor, map[string]string{"level": level, "weak_words": weak})
+	if err != nil {
+		return nil, err
+	}
+
+	message := &models.Message{Role: models.MessageRoleUser, Content: content}
+	response, err := s.generate(ctx, client, PromptTutor, tutorRequest(system, conversation, content), onDelta, func(reply string) error {
+		return moderation.Check(reply, moderation.Policy{Language: moderation.Mixed, MaxLength: maxTutorReplyLength})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &models.Message{
+		Role:             models.MessageRoleAssistant,
+		Content:          response.Content,
+		PromptTokens:     response.Usage.PromptTokens,
+		CompletionTokens: response.Usage.CompletionTokens,
+	}
+	if err := s.tutorRepo.AddExchange(ctx, conversation.ID, message, reply); err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Conversation not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to save messages", err)
+	}
+	return reply, nil
+}
+
+// getConversation fetches a conversation, which must belong to user
+func (s *TutorService) getConversation(ctx context.Context, user string, id uint) (*models.Conversation, error) {
+	conversation, err := s.tutorRepo.GetConversation(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Conversation not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch conversation", err)
+	}
+	// Other users' conversations are not acknowledged to exist
+	if conversation.User != user {
+		return nil, NewServiceError(ErrCodeNotFound, "Conversation not found", nil)
+	}
+	return conversation, nil
+}
+
+// weakWords returns the words the learner struggles with: recent mistakes not
+// yet corrected, then the reviewed words with the lowest accuracy
+func (s *TutorService) weakWords(ctx context.Context) ([]models.Word, error) {
+	reviews, err := s.studyRepo.GetOpenMistakes(ctx, time.Now().AddDate(0, 0, -DefaultMistakesDays))
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get recent mistakes", err)
+	}
+	words := []models.Word{}
+	seen := make(map[uint]bool)
+	for _, review := range reviews {
+		if len(words) == tutorWeakWords {
+			return words, nil
+		}
+		if seen[review.WordID] || review.Word.ID == 0 {
+			continue
+		}
+		seen[review.WordID] = true
+		words = append(words, review.Word)
+	}
+
+	weakest, err := s.wordRepo.FindWords(ctx, repository.WordFilter{ReviewedOnly: true, WeakestFirst: true, Limit: tutorWeakWords})
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch weak words", err)
+	}
+	for _, word := range weakest {
+		if len(words) == tutorWeakWords {
+			break
+		}
+		if !seen[word.ID] {
+			seen[word.ID] = true
+			words = append(words, word)
+		}
+	}
+	return words, nil
+}
+
+// tutorContext lists words for the system prompt, one per line
+func tutorContext(words []models.Word) string {
+	lines := make([]string, len(words))
+	for i, word := range words {
+		lines[i] = "- " + word.Term + " (" + word.Reading + "): " + word.English
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tutorRequest builds the chat for the next turn of a conversation: the system
+// prompt, the latest messages so far and the new message
+func tutorRequest(system string, conversation *models.Conversation, content string) llm.Request {
+	messages := []llm.Message{{Role: llm.RoleSystem, Content: system}}
+
+	history := conversation.Messages
+	if len(history) > tutorHistory {
+		history = history[len(history)-tutorHistory:]
+	}
+	for _, m := range history {
+		role := llm.RoleUser
+		if m.Role == models.MessageRoleAssistant {
+			role = llm.RoleAssistant
+		}
+		messages = append(messages, llm.Message{Role: role, Content: m.Content})
+	}
+	messages = append(messages, llm.Message{Role: llm.RoleUser, Content: content})
+	return llm.Request{Messages: messages, MaxTokens: tutorReplyTokens}
+}
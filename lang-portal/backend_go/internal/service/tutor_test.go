@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/llm"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTutorRepository implements the conversation operations of
+// TutorRepositoryInterface
+type mockTutorRepository struct {
+	repository.TutorRepositoryInterface
+	mock.Mock
+}
+
+func (m *mockTutorRepository) CreateConversation(ctx context.Context, conversation *models.Conversation) error {
+	return m.Called(conversation).Error(0)
+}
+
+func (m *mockTutorRepository) GetConversation(ctx context.Context, id uint) (*models.Conversation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Conversation), args.Error(1)
+}
+
+func (m *mockTutorRepository) AddExchange(ctx context.Context, conversationID uint, message, reply *models.Message) error {
+	return m.Called(conversationID, message, reply).Error(0)
+}
+
+// mockMistakesRepository implements the mistakes query of StudyRepositoryInterface
+type mockMistakesRepository struct {
+	repository.StudyRepositoryInterface
+	reviews []models.WordReview
+}
+
+func (m *mockMistakesRepository) GetOpenMistakes(ctx context.Context, since time.Time) ([]models.WordReview, error) {
+	return m.reviews, nil
+}
+
+// scriptedLLM replies with its pieces in order, recording the request
+type scriptedLLM struct {
+	pieces  []string
+	err     error
+	request llm.Request
+}
+
+func (l *scriptedLLM) Chat(ctx context.Context, req llm.Request, onDelta func(string) error) (*llm.Response, error) {
+	l.request = req
+	if l.err != nil {
+		return nil, l.err
+	}
+	for _, piece := range l.pieces {
+		if onDelta != nil {
+			if err := onDelta(piece); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &llm.Response{Content: strings.Join(l.pieces, ""), Usage: llm.Usage{PromptTokens: 120, CompletionTokens: 9}}, nil
+}
+
+func TestTutorService_CreateConversation_IncludesWeakWords(t *testing.T) {
+	eat := models.Word{ID: 1, Term: "食べる", Reading: "taberu", English: "to eat"}
+	drink := models.Word{ID: 2, Term: "飲む", Reading: "nomu", English: "to drink"}
+	mockWords := new(mockWordRepository)
+	mockWords.On("FindWords", repository.WordFilter{ReviewedOnly: true, WeakestFirst: true, Limit: tutorWeakWords}).
+		Return([]models.Word{eat, drink}, nil)
+	mistakes := &mockMistakesRepository{reviews: []models.WordReview{{WordID: 2, Word: drink}, {WordID: 2, Word: drink}}}
+	mockTutor := new(mockTutorRepository)
+	mockTutor.On("CreateConversation", mock.AnythingOfType("*models.Conversation")).Return(nil)
+
+	base := NewBaseService(mockWords, nil, mistakes, nil, nil)
+	s := NewTutorService(base, mockTutor)
+	_, err := s.CreateConversation(context.Background(), "alice", "")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code, "the tutor needs a language model")
+
+	base.WithLLM(&scriptedLLM{})
+	conversation, err := s.CreateConversation(context.Background(), "alice", " Verbs ")
+	require.NoError(t, err)
+	assert.Equal(t, "Verbs", conversation.Title)
+	assert.Equal(t, "alice", conversation.User)
+	assert.Equal(t, "- 飲む (nomu): to drink\n- 食べる (taberu): to eat", conversation.Context, "mistakes first, without repeats")
+	mockWords.AssertExpectations(t)
+	mockTutor.AssertExpectations(t)
+}
+
+func TestTutorService_SendMessage(t *testing.T) {
+	conversation := &models.Conversation{ID: 4, User: "alice", Context: "- 飲む (nomu): to drink"}
+	for i := 0; i < tutorHistory+2; i++ {
+		conversation.Messages = append(conversation.Messages, models.Message{Role: models.MessageRoleUser, Content: fmt.Sprint(i)})
+	}
+	mockTutor := new(mockTutorRepository)
+	mockTutor.On("GetConversation", uint(4)).Return(conversation, nil)
+	mockTutor.On("AddExchange", uint(4), mock.Anything, mock.Anything).Return(nil)
+	mockWords := new(mockWordRepository)
+	mockWords.On("CountWordsByProgressState").Return(map[string]int64{models.ProgressMastered: 120}, nil)
+	client := &scriptedLLM{pieces: []string{"飲む ", "(nomu) means to drink."}}
+	s := NewTutorService(NewBaseService(mockWords, nil, nil, nil, nil).WithLLM(client), mockTutor)
+
+	var streamed []string
+	reply, err := s.SendMessage(context.Background(), "alice", "ip:192.0.2.1", 4, " What does 飲む mean? ", func(text string) error {
+		streamed = append(streamed, text)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, client.pieces, streamed)
+	assert.Equal(t, models.MessageRoleAssistant, reply.Role)
+	assert.Equal(t, "飲む (nomu) means to drink.", reply.Content)
+	assert.Equal(t, 120, reply.PromptTokens)
+
+	// The system prompt carries the learner's level and weak words, followed
+	// by the latest history and the new message
+	messages := client.request.Messages
+	require.Len(t, messages, tutorHistory+2)
+	assert.Equal(t, llm.RoleSystem, messages[0].Role)
+	assert.Contains(t, messages[0].Content, "learner at intermediate level")
+	assert.Contains(t, messages[0].Content, "\n- 飲む (nomu): to drink")
+	assert.Equal(t, "2", messages[1].Content)
+	assert.Equal(t, llm.Message{Role: llm.RoleUser, Content: "What does 飲む mean?"}, messages[len(messages)-1])
+
+	stored := mockTutor.Calls[1].Arguments
+	assert.Equal(t, "What does 飲む mean?", stored.Get(1).(*models.Message).Content)
+	assert.Same(t, reply, stored.Get(2).(*models.Message))
+	mockTutor.AssertExpectations(t)
+}
+
+func TestTutorService_SendMessage_Errors(t *testing.T) {
+	conversation := &models.Conversation{ID: 4, User: "alice"}
+	mockTutor := new(mockTutorRepository)
+	mockTutor.On("GetConversation", uint(4)).Return(conversation, nil)
+	mockTutor.On("GetConversation", uint(5)).Return(nil, repository.ErrNotFound)
+	mockWords := new(mockWordRepository)
+	mockWords.On("CountWordsByProgressState").Return(map[string]int64{}, nil)
+	client := &scriptedLLM{err: fmt.Errorf("%w: status 503", llm.ErrUnavailable)}
+	s := NewTutorService(NewBaseService(mockWords, nil, nil, nil, nil).WithLLM(client), mockTutor)
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		user    string
+		id      uint
+		content string
+		code    string
+	}{
+		"empty message":        {"alice", 4, "  ", ErrCodeInvalidInput},
+		"message too long":     {"alice", 4, strings.Repeat("あ", MaxTutorMessageLength+1), ErrCodeInvalidInput},
+		"unknown conversation": {"alice", 5, "hi", ErrCodeNotFound},
+		"someone else's":       {"bob", 4, "hi", ErrCodeNotFound},
+		"model unavailable":    {"alice", 4, "hi", ErrCodeInternal},
+	} {
+		_, err := s.SendMessage(ctx, tc.user, "ip:192.0.2.1", tc.id, tc.content, nil)
+		require.Error(t, err, name)
+		assert.Equal(t, tc.code, err.(*ServiceError).Code, name)
+	}
+	mockTutor.AssertNotCalled(t, "AddExchange", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,6 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/events"
 	"lang-portal/backend_go/internal/models"
 	"lang-portal/backend_go/internal/repository"
 )
@@ -17,25 +23,89 @@ func NewWordService(base *BaseService) *WordService {
 
 // Word represents a word with its study statistics
 type Word struct {
-	ID           uint   `json:"id"`
-	Japanese     string `json:"japanese"`
-	Romaji       string `json:"romaji"`
-	English      string `json:"english"`
-	CorrectCount int64  `json:"correct_count"`
-	WrongCount   int64  `json:"wrong_count"`
+	ID       uint   `json:"id"`
+	Language string `json:"language"`
+	Japanese string `json:"japanese"`
+	Romaji   string `json:"romaji"`
+	// Script and ReadingScript are the ISO 15924 codes of the scripts of the
+	// term and reading
+	Script        string `json:"script"`
+	ReadingScript string `json:"reading_script"`
+	English       string `json:"english"`
+	German        string `json:"german,omitempty"`
+	// Translation is the meaning in the language asked for with Translate,
+	// in TranslationLanguage
+	Translation         string `json:"translation,omitempty"`
+	TranslationLanguage string `json:"translation_language,omitempty"`
+	CorrectCount        int64  `json:"correct_count"`
+	WrongCount          int64  `json:"wrong_count"`
+	// State is the word's progress state, such as learning or mastered
+	State string `json:"state"`
+	// FrequencyRank is nil for words missing from the frequency list
+	FrequencyRank *int      `json:"frequency_rank"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // WordDetail represents detailed word information
 type WordDetail struct {
-	ID         uint   `json:"id"`
-	Japanese   string `json:"japanese"`
-	Romaji     string `json:"romaji"`
-	English    string `json:"english"`
-	StudyStats struct {
+	ID       uint   `json:"id"`
+	Language string `json:"language"`
+	Japanese string `json:"japanese"`
+	Romaji   string `json:"romaji"`
+	// Script and ReadingScript are the ISO 15924 codes of the scripts of the
+	// term and reading
+	Script        string `json:"script"`
+	ReadingScript string `json:"reading_script"`
+	English       string `json:"english"`
+	German        string `json:"german,omitempty"`
+	// Translation is the meaning in the language asked for with Translate,
+	// in TranslationLanguage
+	Translation         string `json:"translation,omitempty"`
+	TranslationLanguage string `json:"translation_language,omitempty"`
+	StudyStats          struct {
 		CorrectCount int64 `json:"correct_count"`
 		WrongCount   int64 `json:"wrong_count"`
+		// AvgAnswerTimeMs is null until a review of the word is timed
+		AvgAnswerTimeMs *float64 `json:"avg_answer_time_ms"`
 	} `json:"study_stats"`
-	Groups []GroupInfo `json:"groups"`
+	// Progress is the word's progress state with its streak and lapses
+	Progress models.WordProgress `json:"progress"`
+	// FrequencyRank is nil for words missing from the frequency list
+	FrequencyRank *int        `json:"frequency_rank"`
+	Groups        []GroupInfo `json:"groups"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	// Version is sent back with updates to detect concurrent changes
+	Version uint `json:"version"`
+}
+
+// Translate sets the translation of the word to its meaning in lang, see
+// models.Meaning
+func (w *Word) Translate(lang string) {
+	w.Translation, w.TranslationLanguage = models.Meaning(w.English, w.German, lang)
+}
+
+// Translate sets the translation of the word to its meaning in lang, see
+// models.Meaning
+func (w *WordDetail) Translate(lang string) {
+	w.Translation, w.TranslationLanguage = models.Meaning(w.English, w.German, lang)
+}
+
+// wordProgress returns the progress of a word, new when it has none
+func wordProgress(word *models.Word) models.WordProgress {
+	if word.Progress == nil {
+		return models.WordProgress{WordID: word.ID, State: models.ProgressNew}
+	}
+	return *word.Progress
+}
+
+// DueWord represents a word whose next review is due
+type DueWord struct {
+	ID             uint       `json:"id"`
+	Japanese       string     `json:"japanese"`
+	Romaji         string     `json:"romaji"`
+	English        string     `json:"english"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at"`
+	NextDueAt      *time.Time `json:"next_due_at"`
 }
 
 // GroupInfo represents basic group information
@@ -44,17 +114,40 @@ type GroupInfo struct {
 	Name string `json:"name"`
 }
 
-// CreateWord creates a new word
-func (s *WordService) CreateWord(word *models.Word) error {
-	if err := s.wordRepo.Create(word); err != nil {
+// CreateWord creates a new word, in the default language unless it names one
+func (s *WordService) CreateWord(ctx context.Context, word *models.Word) error {
+	ctx, span := tracer.Start(ctx, "WordService.CreateWord")
+	defer span.End()
+
+	language, err := targetLanguage(word.Language)
+	if err != nil {
+		return err
+	}
+	word.Language = language
+	if err := checkScripts(word); err != nil {
+		return err
+	}
+
+	if err := s.wordRepo.Create(ctx, word); err != nil {
+		if err == repository.ErrInvalidInput {
+			return NewServiceError(ErrCodeInvalidInput, "Invalid word", err)
+		}
+		if unknown := unknownPartsError(err); unknown != nil {
+			return unknown
+		}
 		return NewServiceError(ErrCodeInternal, "Failed to create word", err)
 	}
+	s.invalidateDashboard()
+	s.publish(events.TypeWordCreated, map[string]uint{"id": word.ID})
 	return nil
 }
 
 // GetWord retrieves a word by ID
-func (s *WordService) GetWord(id uint) (*WordDetail, error) {
-	word, err := s.wordRepo.GetByID(id)
+func (s *WordService) GetWord(ctx context.Context, id uint) (*WordDetail, error) {
+	ctx, span := tracer.Start(ctx, "WordService.GetWord")
+	defer span.End()
+
+	word, err := s.wordRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return nil, NewServiceError(ErrCodeNotFound, "Word not found", err)
@@ -63,7 +156,11 @@ func (s *WordService) GetWord(id uint) (*WordDetail, error) {
 	}
 
 	// Calculate study statistics
-	correctCount, wrongCount, err := s.wordRepo.GetStudyStats(id)
+	correctCount, wrongCount, err := s.wordRepo.GetStudyStats(ctx, id)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get word statistics", err)
+	}
+	avgAnswerTime, err := s.wordRepo.GetAverageAnswerTime(ctx, id)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to get word statistics", err)
 	}
@@ -78,27 +175,45 @@ func (s *WordService) GetWord(id uint) (*WordDetail, error) {
 	}
 
 	return &WordDetail{
-		ID:       word.ID,
-		Japanese: word.Japanese,
-		Romaji:   word.Romaji,
-		English:  word.English,
+		ID:            word.ID,
+		Language:      word.Language,
+		Japanese:      word.Term,
+		Romaji:        word.Reading,
+		Script:        word.Script,
+		ReadingScript: word.ReadingScript,
+		English:       word.English,
+		German:        word.German,
 		StudyStats: struct {
-			CorrectCount int64 `json:"correct_count"`
-			WrongCount   int64 `json:"wrong_count"`
+			CorrectCount    int64    `json:"correct_count"`
+			WrongCount      int64    `json:"wrong_count"`
+			AvgAnswerTimeMs *float64 `json:"avg_answer_time_ms"`
 		}{
-			CorrectCount: correctCount,
-			WrongCount:   wrongCount,
+			CorrectCount:    correctCount,
+			WrongCount:      wrongCount,
+			AvgAnswerTimeMs: avgAnswerTime,
 		},
-		Groups: groups,
+		Progress:      wordProgress(word),
+		FrequencyRank: word.FrequencyRank,
+		Groups:        groups,
+		UpdatedAt:     word.UpdatedAt,
+		Version:       word.Version,
 	}, nil
 }
 
-// ListWords retrieves a paginated list of words
-func (s *WordService) ListWords(params PaginationParams) (*PaginatedResult[Word], error) {
-	result, err := s.wordRepo.List(repository.PaginationParams{
+// ListWords retrieves a paginated list of words, filtered and ordered by opts
+func (s *WordService) ListWords(ctx context.Context, params PaginationParams, opts WordListOptions) (*PaginatedResult[Word], error) {
+	ctx, span := tracer.Start(ctx, "WordService.ListWords")
+	defer span.End()
+
+	repoOpts, err := s.wordListOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.wordRepo.List(ctx, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
-	})
+	}, repoOpts)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to list words", err)
 	}
@@ -106,28 +221,36 @@ func (s *WordService) ListWords(params PaginationParams) (*PaginatedResult[Word]
 	// Transform words
 	words := make([]Word, len(result.Items))
 	for i, w := range result.Items {
-		correctCount, wrongCount, err := s.wordRepo.GetStudyStats(w.ID)
-		if err != nil {
-			return nil, NewServiceError(ErrCodeInternal, "Failed to get word statistics", err)
-		}
-
+		correctCount, wrongCount := w.ReviewCounts()
 		words[i] = Word{
-			ID:           w.ID,
-			Japanese:     w.Japanese,
-			Romaji:       w.Romaji,
-			English:      w.English,
-			CorrectCount: correctCount,
-			WrongCount:   wrongCount,
+			ID:            w.ID,
+			Language:      w.Language,
+			Japanese:      w.Term,
+			Romaji:        w.Reading,
+			Script:        w.Script,
+			ReadingScript: w.ReadingScript,
+			English:       w.English,
+			German:        w.German,
+			CorrectCount:  correctCount,
+			WrongCount:    wrongCount,
+			State:         w.ProgressState(),
+			FrequencyRank: w.FrequencyRank,
+			UpdatedAt:     w.UpdatedAt,
 		}
 	}
 
 	return NewPaginatedResult(words, result.TotalItems, params.Page, params.PageSize), nil
 }
 
-// UpdateWord updates an existing word
-func (s *WordService) UpdateWord(id uint, word *models.Word) error {
+// UpdateWord updates an existing word. If word.Version is set, the update is
+// rejected with a conflict when the word has been updated since that version.
+// The language of a word cannot be changed; word.Language may be left empty.
+func (s *WordService) UpdateWord(ctx context.Context, id uint, word *models.Word) error {
+	ctx, span := tracer.Start(ctx, "WordService.UpdateWord")
+	defer span.End()
+
 	// Verify word exists
-	existing, err := s.wordRepo.GetByID(id)
+	existing, err := s.wordRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Word not found", err)
@@ -135,34 +258,188 @@ func (s *WordService) UpdateWord(id uint, word *models.Word) error {
 		return NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
 	}
 
+	if err := staleVersion("word", word.Version, existing.Version); err != nil {
+		return err
+	}
+	if word.Language != "" && word.Language != existing.Language {
+		return NewServiceError(ErrCodeInvalidInput, "The language of a word cannot be changed", nil)
+	}
+	if err := checkScripts(word); err != nil {
+		return err
+	}
+
 	// Update fields
-	existing.Japanese = word.Japanese
-	existing.Romaji = word.Romaji
+	existing.Term = word.Term
+	existing.Reading = word.Reading
+	if word.Script != "" {
+		existing.Script = word.Script
+	}
+	if word.ReadingScript != "" {
+		existing.ReadingScript = word.ReadingScript
+	}
 	existing.English = word.English
+	existing.German = word.German
 
-	if err := s.wordRepo.Update(existing); err != nil {
+	if err := s.wordRepo.Update(ctx, existing); err != nil {
+		if err == repository.ErrConflict {
+			return conflictingUpdate("word", err)
+		}
 		return NewServiceError(ErrCodeInternal, "Failed to update word", err)
 	}
 	return nil
 }
 
-// DeleteWord deletes a word
-func (s *WordService) DeleteWord(id uint) error {
-	if err := s.wordRepo.Delete(id); err != nil {
+// WordPatch is a partial update of a word. Nil fields are left unchanged.
+// Version, if set, is the version of the word the patch was made against.
+type WordPatch struct {
+	Japanese *string   `json:"japanese"`
+	Romaji   *string   `json:"romaji"`
+	English  *string   `json:"english"`
+	German   *string   `json:"german"`
+	Parts    *[]string `json:"parts"`
+	Version  uint      `json:"version"`
+}
+
+// PatchWord updates the fields set in patch and returns the updated word.
+// Fields that are set must not be empty.
+func (s *WordService) PatchWord(ctx context.Context, id uint, patch *WordPatch) (*WordDetail, error) {
+	ctx, span := tracer.Start(ctx, "WordService.PatchWord")
+	defer span.End()
+
+	existing, err := s.wordRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Word not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
+	}
+	if err := staleVersion("word", patch.Version, existing.Version); err != nil {
+		return nil, err
+	}
+
+	if patch.Japanese != nil && *patch.Japanese != existing.Term {
+		conflicting, err := s.wordRepo.GetByTerm(ctx, *patch.Japanese)
+		if err != nil && err != repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to check for existing word", err)
+		}
+		if conflicting != nil {
+			return nil, NewServiceError(ErrCodeConflict, "A word with this Japanese text already exists", nil)
+		}
+		existing.Term = *patch.Japanese
+	}
+	if patch.Romaji != nil {
+		existing.Reading = *patch.Romaji
+	}
+	if patch.English != nil {
+		existing.English = *patch.English
+	}
+	if patch.German != nil {
+		existing.German = *patch.German
+	}
+	if patch.Parts != nil {
+		existing.Parts = models.StringSlice(*patch.Parts)
+	}
+	// Name the empty fields: they may be ones the patch left out, such as the
+	// parts of seeded words
+	if empty := emptyWordFields(existing); len(empty) > 0 {
+		return nil, NewServiceError(ErrCodeInvalidInput, strings.Join(empty, ", ")+" must not be empty", nil)
+	}
+
+	if err := s.wordRepo.Update(ctx, existing); err != nil {
+		if err == repository.ErrInvalidInput {
+			return nil, NewServiceError(ErrCodeInvalidInput, "Invalid word", err)
+		}
+		if unknown := unknownPartsError(err); unknown != nil {
+			return nil, unknown
+		}
+		if err == repository.ErrConflict {
+			return nil, conflictingUpdate("word", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to update word", err)
+	}
+	return s.GetWord(ctx, id)
+}
+
+// emptyWordFields returns the names of the required fields of word that are empty
+func emptyWordFields(word *models.Word) []string {
+	var empty []string
+	for _, field := range []struct {
+		name  string
+		empty bool
+	}{
+		{"japanese", word.Term == ""},
+		{"romaji", word.Reading == ""},
+		{"english", word.English == ""},
+		{"parts", len(word.Parts) == 0},
+	} {
+		if field.empty {
+			empty = append(empty, field.name)
+		}
+	}
+	return empty
+}
+
+// unknownPartsError returns an invalid input error naming the unknown parts of
+// speech if err reports any, and nil otherwise
+func unknownPartsError(err error) error {
+	var unknown *repository.UnknownPartsError
+	if !errors.As(err, &unknown) {
+		return nil
+	}
+	return NewServiceError(ErrCodeInvalidInput, "Unknown parts of speech: "+strings.Join(unknown.Parts, ", "), nil)
+}
+
+// ListPartsOfSpeech returns the part-of-speech vocabulary words can be tagged
+// with, with the number of words tagged with each part
+func (s *WordService) ListPartsOfSpeech(ctx context.Context) ([]repository.PartOfSpeechSummary, error) {
+	ctx, span := tracer.Start(ctx, "WordService.ListPartsOfSpeech")
+	defer span.End()
+
+	parts, err := s.wordRepo.ListPartsOfSpeech(ctx)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list parts of speech", err)
+	}
+	return parts, nil
+}
+
+// DeleteWord deletes a word and records the deleted word in the audit log
+func (s *WordService) DeleteWord(ctx context.Context, id uint, actor string) error {
+	ctx, span := tracer.Start(ctx, "WordService.DeleteWord")
+	defer span.End()
+
+	existing, err := s.wordRepo.GetByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return NewServiceError(ErrCodeNotFound, "Word not found", err)
+		}
+		return NewServiceError(ErrCodeInternal, "Failed to fetch word", err)
+	}
+
+	if err := s.wordRepo.Delete(ctx, id); err != nil {
 		if err == repository.ErrNotFound {
 			return NewServiceError(ErrCodeNotFound, "Word not found", err)
 		}
 		return NewServiceError(ErrCodeInternal, "Failed to delete word", err)
 	}
-	return nil
+	s.invalidateDashboard()
+	s.publish(events.TypeWordDeleted, map[string]uint{"id": id})
+	return s.recordAudit(ctx, actor, models.AuditActionDelete, models.AuditEntityWord, &id, existing, nil)
 }
 
-// GetWordsByGroup retrieves words belonging to a group
-func (s *WordService) GetWordsByGroup(groupID uint, params PaginationParams) (*PaginatedResult[Word], error) {
-	result, err := s.wordRepo.GetWordsByGroup(groupID, repository.PaginationParams{
+// GetWordsByGroup retrieves words belonging to a group, filtered and ordered by opts
+func (s *WordService) GetWordsByGroup(ctx context.Context, groupID uint, params PaginationParams, opts WordListOptions) (*PaginatedResult[Word], error) {
+	ctx, span := tracer.Start(ctx, "WordService.GetWordsByGroup")
+	defer span.End()
+
+	repoOpts, err := s.wordListOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.wordRepo.GetWordsByGroup(ctx, groupID, repository.PaginationParams{
 		Page:     params.Page,
 		PageSize: params.PageSize,
-	})
+	}, repoOpts)
 	if err != nil {
 		return nil, NewServiceError(ErrCodeInternal, "Failed to get group words", err)
 	}
@@ -170,20 +447,46 @@ func (s *WordService) GetWordsByGroup(groupID uint, params PaginationParams) (*P
 	// Transform words
 	words := make([]Word, len(result.Items))
 	for i, w := range result.Items {
-		correctCount, wrongCount, err := s.wordRepo.GetStudyStats(w.ID)
-		if err != nil {
-			return nil, NewServiceError(ErrCodeInternal, "Failed to get word statistics", err)
-		}
-
+		correctCount, wrongCount := w.ReviewCounts()
 		words[i] = Word{
-			ID:           w.ID,
-			Japanese:     w.Japanese,
-			Romaji:       w.Romaji,
-			English:      w.English,
-			CorrectCount: correctCount,
-			WrongCount:   wrongCount,
+			ID:            w.ID,
+			Language:      w.Language,
+			Japanese:      w.Term,
+			Romaji:        w.Reading,
+			Script:        w.Script,
+			ReadingScript: w.ReadingScript,
+			English:       w.English,
+			CorrectCount:  correctCount,
+			WrongCount:    wrongCount,
+			State:         w.ProgressState(),
+			FrequencyRank: w.FrequencyRank,
+			UpdatedAt:     w.UpdatedAt,
 		}
 	}
 
 	return NewPaginatedResult(words, result.TotalItems, params.Page, params.PageSize), nil
 }
+
+// GetDueWords retrieves up to limit words that are due for review, most overdue first
+func (s *WordService) GetDueWords(ctx context.Context, limit int) ([]DueWord, error) {
+	ctx, span := tracer.Start(ctx, "WordService.GetDueWords")
+	defer span.End()
+
+	words, err := s.wordRepo.GetDueWords(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get due words", err)
+	}
+
+	dueWords := make([]DueWord, len(words))
+	for i, w := range words {
+		dueWords[i] = DueWord{
+			ID:             w.ID,
+			Japanese:       w.Term,
+			Romaji:         w.Reading,
+			English:        w.English,
+			LastReviewedAt: w.LastReviewedAt,
+			NextDueAt:      w.NextDueAt,
+		}
+	}
+	return dueWords, nil
+}
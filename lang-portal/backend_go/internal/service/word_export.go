@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+)
+
+// Word export formats
+const (
+	WordExportCSV    = "csv"
+	WordExportNDJSON = "ndjson"
+)
+
+// wordExportBatchSize is the number of words read per query during an export
+const wordExportBatchSize = 500
+
+// wordExportColumns are the columns of a CSV export. The word list importer
+// recognizes the japanese, romaji, english and parts columns, so exports can be
+// imported again.
+var wordExportColumns = []string{"id", "japanese", "romaji", "english", "parts", "created_at", "updated_at"}
+
+// ValidWordExportFormat reports whether format is a supported export format
+func ValidWordExportFormat(format string) bool {
+	return format == WordExportCSV || format == WordExportNDJSON
+}
+
+// ExportWords writes every word to w in format, in ID order: a CSV file with a
+// header row, parts separated by semicolons, or one JSON word per line. Words
+// are read in batches, so the vocabulary is never held in memory at once. An
+// error after the first word was written leaves the export truncated.
+func (s *WordService) ExportWords(ctx context.Context, format string, w io.Writer) error {
+	ctx, span := tracer.Start(ctx, "WordService.ExportWords")
+	defer span.End()
+
+	var write func(word *models.Word) error
+	var flush func() error
+	switch format {
+	case WordExportCSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(wordExportColumns); err != nil {
+			return NewServiceError(ErrCodeInternal, "Failed to write export", err)
+		}
+		write = func(word *models.Word) error {
+			return out.Write([]string{
+				strconv.FormatUint(uint64(word.ID), 10),
+				word.Term,
+				word.Reading,
+				word.English,
+				strings.Join(word.Parts, ";"),
+				word.CreatedAt.UTC().Format(time.RFC3339),
+				word.UpdatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		flush = func() error {
+			out.Flush()
+			return out.Error()
+		}
+	case WordExportNDJSON:
+		enc := json.NewEncoder(w)
+		write = func(word *models.Word) error { return enc.Encode(word) }
+		flush = func() error { return nil }
+	default:
+		return NewServiceError(ErrCodeInvalidInput, fmt.Sprintf("format must be %s or %s", WordExportCSV, WordExportNDJSON), nil)
+	}
+
+	var afterID uint
+	for {
+		words, err := s.wordRepo.ListAfter(ctx, afterID, wordExportBatchSize)
+		if err != nil {
+			return NewServiceError(ErrCodeInternal, "Failed to read words", err)
+		}
+		for i := range words {
+			if err := write(&words[i]); err != nil {
+				return NewServiceError(ErrCodeInternal, "Failed to write export", err)
+			}
+		}
+		if err := flush(); err != nil {
+			return NewServiceError(ErrCodeInternal, "Failed to write export", err)
+		}
+		if len(words) < wordExportBatchSize {
+			return nil
+		}
+		afterID = words[len(words)-1].ID
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestWords(from, n int) []models.Word {
+	created := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	words := make([]models.Word, n)
+	for i := range words {
+		id := uint(from + i)
+		words[i] = models.Word{ID: id, Term: fmt.Sprintf("語%d", id), Reading: "go", English: "word, term", Parts: models.StringSlice{"noun", "suffix"}, CreatedAt: created, UpdatedAt: created}
+	}
+	return words
+}
+
+func TestWordService_ExportWords_CSV(t *testing.T) {
+	repo := new(mockWordRepository)
+	repo.On("ListAfter", uint(0), wordExportBatchSize).Return(exportTestWords(1, wordExportBatchSize), nil)
+	repo.On("ListAfter", uint(wordExportBatchSize), wordExportBatchSize).Return(exportTestWords(wordExportBatchSize+1, 1), nil)
+	s := NewWordService(NewBaseService(repo, nil, nil, nil, nil))
+
+	var out strings.Builder
+	require.NoError(t, s.ExportWords(context.Background(), WordExportCSV, &out))
+	repo.AssertExpectations(t)
+
+	rows, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, wordExportBatchSize+2, "a header and every word")
+	assert.Equal(t, wordExportColumns, rows[0])
+	assert.Equal(t, []string{"1", "語1", "go", "word, term", "noun;suffix", "2025-03-10T09:00:00Z", "2025-03-10T09:00:00Z"}, rows[1])
+	assert.Equal(t, "501", rows[len(rows)-1][0])
+}
+
+func TestWordService_ExportWords_NDJSON(t *testing.T) {
+	repo := new(mockWordRepository)
+	repo.On("ListAfter", uint(0), wordExportBatchSize).Return(exportTestWords(1, 2), nil)
+	s := NewWordService(NewBaseService(repo, nil, nil, nil, nil))
+
+	var out strings.Builder
+	require.NoError(t, s.ExportWords(context.Background(), WordExportNDJSON, &out))
+
+	var words []models.Word
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var word models.Word
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &word))
+		words = append(words, word)
+	}
+	require.Len(t, words, 2)
+	assert.Equal(t, "語2", words[1].Term)
+	assert.Equal(t, models.StringSlice{"noun", "suffix"}, words[1].Parts)
+
+	err := s.ExportWords(context.Background(), "xml", &out)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
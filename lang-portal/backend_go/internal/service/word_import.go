@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"lang-portal/backend_go/internal/events"
+	"lang-portal/backend_go/internal/importer"
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// WordImportRow is an imported row and whether its word already exists
+type WordImportRow struct {
+	importer.Row
+	// Exists is set for valid rows whose japanese is already a word; they are
+	// skipped, but still added to the import's group
+	Exists bool `json:"exists,omitempty"`
+}
+
+// WordImportPreview describes what an import would do
+type WordImportPreview struct {
+	Format    string          `json:"format"`
+	Delimiter string          `json:"delimiter,omitempty"`
+	Header    bool            `json:"header"`
+	Columns   []string        `json:"columns,omitempty"`
+	Group     string          `json:"group,omitempty"`
+	Rows      []WordImportRow `json:"rows"`
+	New       int             `json:"new"`
+	Existing  int             `json:"existing"`
+	Invalid   int             `json:"invalid"`
+	// ConfirmationToken must be passed back to run the import
+	ConfirmationToken string `json:"confirmation_token"`
+}
+
+// WordImportResult reports what an import did
+type WordImportResult struct {
+	Created int    `json:"created"`
+	Skipped int    `json:"skipped"`
+	Invalid int    `json:"invalid"`
+	Group   string `json:"group,omitempty"`
+}
+
+// PreviewWordImport parses a word list and reports what importing it would do,
+// without changing anything. group, if not empty, names the group the words
+// would be added to.
+func (s *WordService) PreviewWordImport(ctx context.Context, data []byte, opts importer.Options, group string) (*WordImportPreview, error) {
+	ctx, span := tracer.Start(ctx, "WordService.PreviewWordImport")
+	defer span.End()
+
+	preview, _, err := s.previewWordImport(ctx, data, opts, group)
+	return preview, err
+}
+
+// ImportWords creates the new words of a word list and adds the new and
+// existing ones to group, if given. confirm must be the confirmation token of
+// a preview of the same list, so that what was reviewed is what gets imported.
+// Invalid rows are skipped.
+func (s *WordService) ImportWords(ctx context.Context, data []byte, opts importer.Options, group, confirm, actor string) (*WordImportResult, error) {
+	ctx, span := tracer.Start(ctx, "WordService.ImportWords")
+	defer span.End()
+
+	preview, existing, err := s.previewWordImport(ctx, data, opts, group)
+	if err != nil {
+		return nil, err
+	}
+	if confirm == "" {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Confirmation token required; request a preview first", nil)
+	}
+	if confirm != preview.ConfirmationToken {
+		return nil, NewServiceError(ErrCodeInvalidInput, "Confirmation token does not match this import; request a new preview", nil)
+	}
+
+	var target *models.Group
+	if preview.Group != "" {
+		target, err = s.groupRepo.GetByName(ctx, preview.Group, models.DefaultLanguage)
+		if err == repository.ErrNotFound {
+			target = &models.Group{Name: preview.Group}
+			err = s.groupRepo.Create(ctx, target)
+		}
+		if err != nil {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to prepare group", err)
+		}
+	}
+
+	result := &WordImportResult{Skipped: preview.Existing, Invalid: preview.Invalid, Group: preview.Group}
+	for _, row := range preview.Rows {
+		if row.Error != "" {
+			continue
+		}
+		word := existing[row.Japanese]
+		if word == nil {
+			word = row.Word()
+			if err := s.wordRepo.Create(ctx, word); err != nil {
+				return nil, NewServiceError(ErrCodeInternal, fmt.Sprintf("Failed to import line %d", row.Line), err)
+			}
+			result.Created++
+			s.publish(events.TypeWordCreated, map[string]uint{"id": word.ID})
+		}
+		if target != nil {
+			if err := s.groupRepo.AddWord(ctx, target.ID, word.ID); err != nil {
+				return nil, NewServiceError(ErrCodeInternal, fmt.Sprintf("Failed to add line %d to the group", row.Line), err)
+			}
+		}
+	}
+
+	s.invalidateDashboard()
+	if err := s.recordAudit(ctx, actor, models.AuditActionImport, models.AuditEntityWord, nil, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// previewWordImport parses and checks a word list, returning the preview and
+// the existing words by japanese
+func (s *WordService) previewWordImport(ctx context.Context, data []byte, opts importer.Options, group string) (*WordImportPreview, map[string]*models.Word, error) {
+	parsed, err := importer.Parse(data, opts)
+	if err != nil {
+		return nil, nil, NewServiceError(ErrCodeInvalidInput, err.Error(), nil)
+	}
+
+	preview := &WordImportPreview{
+		Format:    parsed.Format,
+		Delimiter: parsed.Delimiter,
+		Header:    parsed.Header,
+		Columns:   parsed.Columns,
+		Group:     strings.TrimSpace(group),
+		Rows:      make([]WordImportRow, len(parsed.Rows)),
+	}
+	vocabulary, err := s.wordRepo.ListPartsOfSpeech(ctx)
+	if err != nil {
+		return nil, nil, NewServiceError(ErrCodeInternal, "Failed to list parts of speech", err)
+	}
+	known := make(map[string]bool, len(vocabulary))
+	for _, part := range vocabulary {
+		known[part.Name] = true
+	}
+
+	existing := make(map[string]*models.Word)
+	for i, row := range parsed.Rows {
+		preview.Rows[i] = WordImportRow{Row: row}
+		if row.Error != "" {
+			preview.Invalid++
+			continue
+		}
+		if err := row.Word().Validate(); err != nil {
+			preview.Rows[i].Error = "invalid word: " + err.Error()
+			preview.Invalid++
+			continue
+		}
+		if unknown := unknownParts(row.Parts, known); len(unknown) > 0 {
+			preview.Rows[i].Error = "unknown parts of speech: " + strings.Join(unknown, ", ")
+			preview.Invalid++
+			continue
+		}
+
+		word, err := s.wordRepo.GetByTerm(ctx, row.Japanese)
+		switch {
+		case err == nil:
+			existing[row.Japanese] = word
+			preview.Rows[i].Exists = true
+			preview.Existing++
+		case err == repository.ErrNotFound:
+			preview.New++
+		default:
+			return nil, nil, NewServiceError(ErrCodeInternal, "Failed to check existing words", err)
+		}
+	}
+	preview.ConfirmationToken = importToken(preview)
+	return preview, existing, nil
+}
+
+// unknownParts returns the parts that are not known, in order
+func unknownParts(parts []string, known map[string]bool) []string {
+	var unknown []string
+	for _, part := range parts {
+		if !known[part] {
+			unknown = append(unknown, part)
+		}
+	}
+	return unknown
+}
+
+// importToken fingerprints what an import would change: the group and the
+// valid rows with whether each already exists
+func importToken(preview *WordImportPreview) string {
+	h := sha256.New()
+	h.Write([]byte(preview.Group))
+	for _, row := range preview.Rows {
+		if row.Error != "" {
+			continue
+		}
+		fmt.Fprintf(h, "\n%s\x00%s\x00%s\x00%s\x00%t", row.Japanese, row.Romaji, row.English, strings.Join(row.Parts, ","), row.Exists)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Sort orders for lists
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Default mastery thresholds, used when no preference is stored. A word is
+// mastered once it has at least this many reviews at this success rate.
+const (
+	DefaultMasteredMinReviews     = 5
+	DefaultMasteredMinSuccessRate = 0.8
+)
+
+// WordListOptions orders and filters word lists
+type WordListOptions struct {
+	Sort WordSort
+	// Status is one of unstudied, learning or mastered; empty matches every word
+	Status string
+	// Part keeps only the words tagged with this part of speech when not empty
+	Part string
+	// Language keeps only the words of this target language when not empty
+	Language string
+}
+
+// SortParams selects the order of a list: By is one of the list's sort fields
+// and Order is asc or desc. An empty By keeps the list's default order.
+type SortParams struct {
+	By    string
+	Order string
+}
+
+// WordSort selects the order of a word list; By is one of japanese, romaji,
+// english, created_at, correct_count, success_rate or frequency_rank. An empty
+// By keeps the default order by ID.
+type WordSort = SortParams
+
+// toRepository validates the sort against the fields valid accepts and converts
+// it for the repository
+func (sp SortParams) toRepository(valid func(string) bool) (repository.SortParams, error) {
+	if sp.By == "" {
+		return repository.SortParams{}, nil
+	}
+	if !valid(sp.By) {
+		return repository.SortParams{}, NewServiceError(ErrCodeInvalidInput, "Unknown sort field: "+sp.By, nil)
+	}
+	switch sp.Order {
+	case "", SortOrderAsc:
+		return repository.SortParams{Field: sp.By}, nil
+	case SortOrderDesc:
+		return repository.SortParams{Field: sp.By, Desc: true}, nil
+	default:
+		return repository.SortParams{}, NewServiceError(ErrCodeInvalidInput, "Sort order must be asc or desc", nil)
+	}
+}
+
+// wordListOptions validates opts and resolves the mastery thresholds for the
+// status filter from the preferences
+func (s *BaseService) wordListOptions(ctx context.Context, opts WordListOptions) (repository.WordListOptions, error) {
+	sort, err := opts.Sort.toRepository(repository.ValidWordSortField)
+	if err != nil {
+		return repository.WordListOptions{}, err
+	}
+	repoOpts := repository.WordListOptions{Sort: sort, Part: opts.Part}
+	if opts.Language != "" {
+		if repoOpts.Language, err = targetLanguage(opts.Language); err != nil {
+			return repository.WordListOptions{}, err
+		}
+	}
+	if opts.Status == "" {
+		return repoOpts, nil
+	}
+	if !repository.ValidWordStatus(opts.Status) {
+		return repository.WordListOptions{}, NewServiceError(ErrCodeInvalidInput, "Unknown word status: "+opts.Status, nil)
+	}
+
+	minReviews, minRate, err := s.masteryThresholds(ctx)
+	if err != nil {
+		return repository.WordListOptions{}, err
+	}
+	repoOpts.Status = repository.WordStatusFilter{
+		Status:                 opts.Status,
+		MasteredMinReviews:     minReviews,
+		MasteredMinSuccessRate: minRate,
+	}
+	return repoOpts, nil
+}
+
+// masteryThresholds returns the preferred mastery thresholds, falling back to
+// the defaults for values that are unset or out of range
+func (s *BaseService) masteryThresholds(ctx context.Context) (minReviews int, minRate float64, err error) {
+	minReviews, minRate = DefaultMasteredMinReviews, DefaultMasteredMinSuccessRate
+	if s.settingRepo == nil {
+		return minReviews, minRate, nil
+	}
+
+	raw, err := s.settingRepo.Get(ctx, models.SettingMasteredMinReviews)
+	if err != nil && err != repository.ErrNotFound {
+		return 0, 0, NewServiceError(ErrCodeInternal, "Failed to get mastery preference", err)
+	}
+	if n, convErr := strconv.Atoi(raw); err == nil && convErr == nil && validMasteredMinReviews(n) {
+		minReviews = n
+	}
+
+	raw, err = s.settingRepo.Get(ctx, models.SettingMasteredMinSuccessRate)
+	if err != nil && err != repository.ErrNotFound {
+		return 0, 0, NewServiceError(ErrCodeInternal, "Failed to get mastery preference", err)
+	}
+	if rate, convErr := strconv.ParseFloat(raw, 64); err == nil && convErr == nil && validMasteredMinSuccessRate(rate) {
+		minRate = rate
+	}
+	return minReviews, minRate, nil
+}
+
+func validMasteredMinReviews(n int) bool {
+	return n >= 1
+}
+
+func validMasteredMinSuccessRate(rate float64) bool {
+	return rate > 0 && rate <= 1
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"lang-portal/backend_go/internal/jpn"
+)
+
+// Formats words can be rendered in
+const (
+	// RenderRuby is HTML with the readings of kanji in <ruby> annotations
+	RenderRuby = "ruby"
+)
+
+// RenderedSentence is an example sentence of a rendered word. English is
+// plain text.
+type RenderedSentence struct {
+	ID       uint   `json:"id"`
+	Japanese string `json:"japanese"`
+	English  string `json:"english"`
+}
+
+// RenderedWord is a word and its example sentences rendered in a format, with
+// the furigana the rendering is built from. Reading is the word in hiragana,
+// empty when its romaji could not be read as kana; its kanji are then left
+// without readings.
+type RenderedWord struct {
+	WordID    uint               `json:"word_id"`
+	Format    string             `json:"format"`
+	Reading   string             `json:"reading"`
+	Furigana  []jpn.Segment      `json:"furigana"`
+	Japanese  string             `json:"japanese"`
+	English   string             `json:"english"`
+	Sentences []RenderedSentence `json:"sentences"`
+}
+
+// RenderWord renders a word and its example sentences in format, so that every
+// frontend shows readings the same way. The word's reading is its romaji in
+// hiragana, lined up with its kanji; in sentences the kanji of the word are
+// annotated wherever it occurs, including conjugated forms.
+func (s *SentenceService) RenderWord(ctx context.Context, wordID uint, format string) (*RenderedWord, error) {
+	ctx, span := tracer.Start(ctx, "SentenceService.RenderWord")
+	defer span.End()
+
+	if format == "" {
+		format = RenderRuby
+	}
+	if format != RenderRuby {
+		return nil, NewServiceError(ErrCodeInvalidInput, "format must be ruby", nil)
+	}
+	word, err := s.getWord(ctx, wordID)
+	if err != nil {
+		return nil, err
+	}
+	sentences, err := s.sentenceRepo.ListByWord(ctx, wordID)
+	if err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to list example sentences", err)
+	}
+
+	// A reading that is not romaji is empty, leaving the kanji without readings
+	reading, _ := jpn.RomajiToHiragana(word.Reading)
+	furigana := jpn.Furigana(word.Term, reading)
+	rendered := &RenderedWord{
+		WordID:    word.ID,
+		Format:    format,
+		Reading:   jpn.Reading(furigana),
+		Furigana:  furigana,
+		Japanese:  jpn.Ruby(furigana),
+		English:   word.English,
+		Sentences: make([]RenderedSentence, len(sentences)),
+	}
+	for i, sentence := range sentences {
+		rendered.Sentences[i] = RenderedSentence{
+			ID:       sentence.ID,
+			Japanese: jpn.RubySentence(sentence.Japanese, furigana),
+			English:  sentence.English,
+		}
+	}
+	return rendered, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSentenceRepository is a partial mock of SentenceRepositoryInterface
+type mockSentenceRepository struct {
+	repository.SentenceRepositoryInterface
+	mock.Mock
+}
+
+func (m *mockSentenceRepository) ListByWord(ctx context.Context, wordID uint) ([]models.ExampleSentence, error) {
+	args := m.Called(wordID)
+	return args.Get(0).([]models.ExampleSentence), args.Error(1)
+}
+
+func TestSentenceService_RenderWord(t *testing.T) {
+	wordRepo := new(mockWordRepository)
+	wordRepo.On("GetByID", uint(1)).Return(&models.Word{ID: 1, Term: "食べる", Reading: "taberu", English: "to eat"}, nil)
+	sentenceRepo := new(mockSentenceRepository)
+	sentenceRepo.On("ListByWord", uint(1)).Return([]models.ExampleSentence{
+		{ID: 7, WordID: 1, Japanese: "パンを食べました。", English: "I ate bread."},
+	}, nil)
+	s := NewSentenceService(NewBaseService(wordRepo, nil, nil, nil, nil), sentenceRepo)
+
+	rendered, err := s.RenderWord(context.Background(), 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, RenderRuby, rendered.Format)
+	assert.Equal(t, "たべる", rendered.Reading)
+	assert.Equal(t, "<ruby>食<rp>(</rp><rt>た</rt><rp>)</rp></ruby>べる", rendered.Japanese)
+	assert.Equal(t, []RenderedSentence{
+		{ID: 7, Japanese: "パンを<ruby>食<rp>(</rp><rt>た</rt><rp>)</rp></ruby>べました。", English: "I ate bread."},
+	}, rendered.Sentences)
+
+	_, err = s.RenderWord(context.Background(), 1, "markdown")
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeInvalidInput, err.(*ServiceError).Code)
+}
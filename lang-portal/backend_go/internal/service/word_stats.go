@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// wordStatsInterval is how often the recompute job checks the word stats
+const wordStatsInterval = 24 * time.Hour
+
+// WordStatsRecomputer keeps the review totals in word_stats consistent with the
+// reviews. The totals are updated with every review, so the job only repairs
+// drift, such as from reviews written directly to the database.
+type WordStatsRecomputer struct {
+	*BaseService
+	logger *log.Logger
+}
+
+// NewWordStatsRecomputer creates a recompute job for the word stats
+func NewWordStatsRecomputer(base *BaseService, logger *log.Logger) *WordStatsRecomputer {
+	return &WordStatsRecomputer{BaseService: base, logger: logger}
+}
+
+// Run recomputes at start and then once a day until ctx is done
+func (r *WordStatsRecomputer) Run(ctx context.Context) {
+	ticker := time.NewTicker(wordStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		drifted, err := r.Recompute(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Printf("Word stats recompute: %v", err)
+		}
+		if drifted > 0 {
+			r.logger.Printf("Corrected the review totals of %d words", drifted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Recompute rebuilds the word stats when they differ from the reviews and
+// returns the number of words whose totals were wrong or missing
+func (r *WordStatsRecomputer) Recompute(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "WordStatsRecomputer.Recompute")
+	defer span.End()
+
+	drifted, err := r.wordRepo.RecomputeWordStats(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute word stats: %w", err)
+	}
+	return drifted, nil
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordStatsRecomputer_Recompute(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	recomputer := NewWordStatsRecomputer(NewBaseService(mockRepo, nil, nil, nil, nil), nil)
+
+	mockRepo.On("RecomputeWordStats").Return(int64(2), nil).Once()
+	drifted, err := recomputer.Recompute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), drifted)
+
+	mockRepo.On("RecomputeWordStats").Return(int64(0), errors.New("database is locked")).Once()
+	_, err = recomputer.Recompute(context.Background())
+	assert.ErrorContains(t, err, "database is locked")
+	mockRepo.AssertExpectations(t)
+}
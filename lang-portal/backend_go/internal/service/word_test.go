@@ -1,14 +1,17 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"lang-portal/backend_go/internal/models"
 	"lang-portal/backend_go/internal/repository"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // mockWordRepository is a mock implementation of WordRepositoryInterface
@@ -16,12 +19,12 @@ type mockWordRepository struct {
 	mock.Mock
 }
 
-func (m *mockWordRepository) Create(word *models.Word) error {
+func (m *mockWordRepository) Create(ctx context.Context, word *models.Word) error {
 	args := m.Called(word)
 	return args.Error(0)
 }
 
-func (m *mockWordRepository) GetByID(id uint) (*models.Word, error) {
+func (m *mockWordRepository) GetByID(ctx context.Context, id uint) (*models.Word, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -29,56 +32,69 @@ func (m *mockWordRepository) GetByID(id uint) (*models.Word, error) {
 	return args.Get(0).(*models.Word), args.Error(1)
 }
 
-func (m *mockWordRepository) List(params repository.PaginationParams) (*repository.PaginatedResult[models.Word], error) {
-	args := m.Called(params)
+func (m *mockWordRepository) List(ctx context.Context, params repository.PaginationParams, opts repository.WordListOptions) (*repository.PaginatedResult[models.Word], error) {
+	args := m.Called(params, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*repository.PaginatedResult[models.Word]), args.Error(1)
 }
 
-func (m *mockWordRepository) Update(word *models.Word) error {
+func (m *mockWordRepository) Update(ctx context.Context, word *models.Word) error {
 	args := m.Called(word)
 	return args.Error(0)
 }
 
-func (m *mockWordRepository) Delete(id uint) error {
+func (m *mockWordRepository) Delete(ctx context.Context, id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *mockWordRepository) GetStudyStats(wordID uint) (correctCount int64, wrongCount int64, err error) {
+func (m *mockWordRepository) GetStudyStats(ctx context.Context, wordID uint) (correctCount int64, wrongCount int64, err error) {
 	args := m.Called(wordID)
 	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *mockWordRepository) GetWordsByGroup(groupID uint, params repository.PaginationParams) (*repository.PaginatedResult[models.Word], error) {
-	args := m.Called(groupID, params)
+func (m *mockWordRepository) GetAverageAnswerTime(ctx context.Context, wordID uint) (*float64, error) {
+	args := m.Called(wordID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*float64), args.Error(1)
+}
+
+func (m *mockWordRepository) GetWordsByGroup(ctx context.Context, groupID uint, params repository.PaginationParams, opts repository.WordListOptions) (*repository.PaginatedResult[models.Word], error) {
+	args := m.Called(groupID, params, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*repository.PaginatedResult[models.Word]), args.Error(1)
 }
 
-func (m *mockWordRepository) GetTotalWordCount() (int64, error) {
+func (m *mockWordRepository) GetTotalWordCount(ctx context.Context) (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *mockWordRepository) GetStudiedWordCount() (int64, error) {
+func (m *mockWordRepository) RecomputeWordStats(ctx context.Context) (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *mockWordRepository) GetByJapanese(japanese string) (*models.Word, error) {
-	args := m.Called(japanese)
+func (m *mockWordRepository) GetStudiedWordCount(ctx context.Context) (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockWordRepository) GetByTerm(ctx context.Context, term string) (*models.Word, error) {
+	args := m.Called(term)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Word), args.Error(1)
 }
 
-func (m *mockWordRepository) GetWordsByGroupRaw(groupID uint) ([]models.Word, error) {
+func (m *mockWordRepository) GetWordsByGroupRaw(ctx context.Context, groupID uint) ([]models.Word, error) {
 	args := m.Called(groupID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -86,34 +102,114 @@ func (m *mockWordRepository) GetWordsByGroupRaw(groupID uint) ([]models.Word, er
 	return args.Get(0).([]models.Word), args.Error(1)
 }
 
+func (m *mockWordRepository) GetDueWords(ctx context.Context, asOf time.Time, limit int) ([]models.Word, error) {
+	args := m.Called(asOf, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Word), args.Error(1)
+}
+
+func (m *mockWordRepository) Search(ctx context.Context, query string, limit int) ([]models.Word, error) {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Word), args.Error(1)
+}
+
+func (m *mockWordRepository) FindWords(ctx context.Context, filter repository.WordFilter) ([]models.Word, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Word), args.Error(1)
+}
+
+func (m *mockWordRepository) ListPartsOfSpeech(ctx context.Context) ([]repository.PartOfSpeechSummary, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.PartOfSpeechSummary), args.Error(1)
+}
+
+func (m *mockWordRepository) GetReviewedParts(ctx context.Context, minWords, limit int) ([]string, error) {
+	args := m.Called(minWords, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockWordRepository) CountWordsByProgressState(ctx context.Context) (map[string]int64, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *mockWordRepository) CountDueByDay(ctx context.Context, until time.Time) ([]repository.DueDay, error) {
+	args := m.Called(until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.DueDay), args.Error(1)
+}
+
+func (m *mockWordRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]models.Word, error) {
+	args := m.Called(afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Word), args.Error(1)
+}
+
+func (m *mockWordRepository) GetWordVectors(ctx context.Context, model string) ([]models.WordVector, error) {
+	args := m.Called(model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WordVector), args.Error(1)
+}
+
+func (m *mockWordRepository) SaveWordVectors(ctx context.Context, vectors []models.WordVector) error {
+	args := m.Called(vectors)
+	return args.Error(0)
+}
+
 func TestWordService_GetWord(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil) // Other repos are nil as they are not used by WordService's GetWord
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil) // Other repos are nil as they are not used by WordService's GetWord
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(1)
 	expectedWord := &models.Word{
-		ID:       testWordID,
-		Japanese: "こんにちは",
-		Romaji:   "Konnichiwa",
-		English:  "Hello",
-		Groups:   []models.Group{{ID: 1, Name: "Greetings"}},
+		ID:      testWordID,
+		Term:    "こんにちは",
+		Reading: "Konnichiwa",
+		English: "Hello",
+		Groups:  []models.Group{{ID: 1, Name: "Greetings"}},
 	}
 
 	// Setup mock expectations
 	mockRepo.On("GetByID", testWordID).Return(expectedWord, nil)
 	mockRepo.On("GetStudyStats", testWordID).Return(int64(10), int64(2), nil)
+	avgAnswerTime := 1250.0
+	mockRepo.On("GetAverageAnswerTime", testWordID).Return(&avgAnswerTime, nil)
 
 	// Call the service method
-	wordDetail, err := wordService.GetWord(testWordID)
+	wordDetail, err := wordService.GetWord(context.Background(), testWordID)
 
 	// Assertions
 	assert.NoError(t, err)
 	assert.NotNil(t, wordDetail)
 	assert.Equal(t, expectedWord.ID, wordDetail.ID)
-	assert.Equal(t, expectedWord.Japanese, wordDetail.Japanese)
+	assert.Equal(t, expectedWord.Term, wordDetail.Japanese)
 	assert.Equal(t, int64(10), wordDetail.StudyStats.CorrectCount)
 	assert.Equal(t, int64(2), wordDetail.StudyStats.WrongCount)
+	assert.Equal(t, &avgAnswerTime, wordDetail.StudyStats.AvgAnswerTimeMs)
 	assert.Len(t, wordDetail.Groups, 1)
 	assert.Equal(t, expectedWord.Groups[0].Name, wordDetail.Groups[0].Name)
 
@@ -123,7 +219,7 @@ func TestWordService_GetWord(t *testing.T) {
 
 func TestWordService_GetWord_NotFound(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(2)
@@ -132,7 +228,7 @@ func TestWordService_GetWord_NotFound(t *testing.T) {
 	mockRepo.On("GetByID", testWordID).Return(nil, repository.ErrNotFound)
 
 	// Call the service method
-	wordDetail, err := wordService.GetWord(testWordID)
+	wordDetail, err := wordService.GetWord(context.Background(), testWordID)
 
 	// Assertions
 	assert.Error(t, err)
@@ -147,21 +243,21 @@ func TestWordService_GetWord_NotFound(t *testing.T) {
 
 func TestWordService_CreateWord(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	newWord := &models.Word{
-		Japanese: "新しい単語",
-		Romaji:   "Atarashii tango",
-		English:  "New word",
-		Parts:    []string{"noun"},
+		Term:    "新しい単語",
+		Reading: "Atarashii tango",
+		English: "New word",
+		Parts:   []string{"noun"},
 	}
 
 	// Setup mock expectation
 	mockRepo.On("Create", newWord).Return(nil)
 
 	// Call the service method
-	err := wordService.CreateWord(newWord)
+	err := wordService.CreateWord(context.Background(), newWord)
 
 	// Assertions
 	assert.NoError(t, err)
@@ -170,11 +266,11 @@ func TestWordService_CreateWord(t *testing.T) {
 
 func TestWordService_CreateWord_Error(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	newWord := &models.Word{
-		Japanese: "テスト",
+		Term: "テスト",
 	}
 	expectedError := errors.New("create failed")
 
@@ -182,7 +278,7 @@ func TestWordService_CreateWord_Error(t *testing.T) {
 	mockRepo.On("Create", newWord).Return(expectedError)
 
 	// Call the service method
-	err := wordService.CreateWord(newWord)
+	err := wordService.CreateWord(context.Background(), newWord)
 
 	// Assertions
 	assert.Error(t, err)
@@ -193,9 +289,25 @@ func TestWordService_CreateWord_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestWordService_CreateWord_UnknownParts(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	wordService := NewWordService(NewBaseService(mockRepo, nil, nil, nil, nil))
+
+	newWord := &models.Word{Term: "犬", Reading: "inu", English: "dog", Parts: []string{"noun", "animal"}}
+	mockRepo.On("Create", newWord).Return(&repository.UnknownPartsError{Parts: []string{"animal"}})
+
+	err := wordService.CreateWord(context.Background(), newWord)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeInvalidInput, serviceErr.Code)
+	assert.Contains(t, serviceErr.Message, "animal")
+	mockRepo.AssertExpectations(t)
+}
+
 func TestWordService_ListWords(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	params := PaginationParams{Page: 1, PageSize: 10}
@@ -203,8 +315,8 @@ func TestWordService_ListWords(t *testing.T) {
 
 	expectedRepoResult := &repository.PaginatedResult[models.Word]{
 		Items: []models.Word{
-			{ID: 1, Japanese: "こんにちは", Romaji: "Konnichiwa", English: "Hello"},
-			{ID: 2, Japanese: "ありがとう", Romaji: "Arigato", English: "Thank you"},
+			{ID: 1, Term: "こんにちは", Reading: "Konnichiwa", English: "Hello", Stats: &models.WordStats{WordID: 1, CorrectCount: 5, WrongCount: 1}},
+			{ID: 2, Term: "ありがとう", Reading: "Arigato", English: "Thank you", Stats: &models.WordStats{WordID: 2, CorrectCount: 10}},
 		},
 		TotalItems: 2,
 		Page:       1,
@@ -212,37 +324,35 @@ func TestWordService_ListWords(t *testing.T) {
 		TotalPages: 1,
 	}
 
-	// Mock expectations for List
-	mockRepo.On("List", repoParams).Return(expectedRepoResult, nil)
-	// Mock expectations for GetStudyStats for each word
-	mockRepo.On("GetStudyStats", uint(1)).Return(int64(5), int64(1), nil)
-	mockRepo.On("GetStudyStats", uint(2)).Return(int64(10), int64(0), nil)
+	// The review counts come with the words, without a query per word
+	mockRepo.On("List", repoParams, repository.WordListOptions{}).Return(expectedRepoResult, nil)
 
-	result, err := wordService.ListWords(params)
+	result, err := wordService.ListWords(context.Background(), params, WordListOptions{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Len(t, result.Items, 2)
 	assert.Equal(t, int64(2), result.TotalItems)
-	assert.Equal(t, expectedRepoResult.Items[0].Japanese, result.Items[0].Japanese)
+	assert.Equal(t, expectedRepoResult.Items[0].Term, result.Items[0].Japanese)
 	assert.Equal(t, int64(5), result.Items[0].CorrectCount)
-	assert.Equal(t, expectedRepoResult.Items[1].Romaji, result.Items[1].Romaji)
+	assert.Equal(t, int64(1), result.Items[0].WrongCount)
+	assert.Equal(t, expectedRepoResult.Items[1].Reading, result.Items[1].Romaji)
 	assert.Equal(t, int64(10), result.Items[1].CorrectCount)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestWordService_ListWords_RepoError(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	params := PaginationParams{Page: 1, PageSize: 10}
 	repoParams := repository.PaginationParams{Page: 1, PageSize: 10}
 	expectedError := errors.New("list failed")
 
-	mockRepo.On("List", repoParams).Return(nil, expectedError)
+	mockRepo.On("List", repoParams, repository.WordListOptions{}).Return(nil, expectedError)
 
-	result, err := wordService.ListWords(params)
+	result, err := wordService.ListWords(context.Background(), params, WordListOptions{})
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -252,59 +362,99 @@ func TestWordService_ListWords_RepoError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestWordService_ListWords_GetStudyStatsError(t *testing.T) {
+func TestWordService_ListWords_Unreviewed(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	params := PaginationParams{Page: 1, PageSize: 10}
 	repoParams := repository.PaginationParams{Page: 1, PageSize: 10}
 
 	expectedRepoResult := &repository.PaginatedResult[models.Word]{
-		Items: []models.Word{
-			{ID: 1, Japanese: "こんにちは"},
-			{ID: 2, Japanese: "ありがとう"},
-		},
-		TotalItems: 2,
+		Items:      []models.Word{{ID: 1, Term: "こんにちは"}},
+		TotalItems: 1,
 	}
+	mockRepo.On("List", repoParams, repository.WordListOptions{}).Return(expectedRepoResult, nil)
 
-	statsError := errors.New("failed to get stats")
+	result, err := wordService.ListWords(context.Background(), params, WordListOptions{})
 
-	mockRepo.On("List", repoParams).Return(expectedRepoResult, nil)
-	mockRepo.On("GetStudyStats", uint(1)).Return(int64(5), int64(1), nil)        // First word stats succeed
-	mockRepo.On("GetStudyStats", uint(2)).Return(int64(0), int64(0), statsError) // Second word stats fail
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 1)
+	assert.Zero(t, result.Items[0].CorrectCount)
+	assert.Zero(t, result.Items[0].WrongCount)
+	mockRepo.AssertNotCalled(t, "GetStudyStats", uint(1))
+	mockRepo.AssertExpectations(t)
+}
 
-	result, err := wordService.ListWords(params)
+func TestWordService_ListWords_Sorted(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	wordService := NewWordService(NewBaseService(mockRepo, nil, nil, nil, nil))
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	serviceErr, ok := err.(*ServiceError)
-	assert.True(t, ok)
-	assert.Equal(t, ErrCodeInternal, serviceErr.Code)
-	assert.Contains(t, serviceErr.Error(), statsError.Error())
+	params := PaginationParams{Page: 1, PageSize: 10}
+	repoParams := repository.PaginationParams{Page: 1, PageSize: 10}
+	mockRepo.On("List", repoParams, repository.WordListOptions{Sort: repository.WordSort{Field: repository.WordSortSuccessRate, Desc: true}}).
+		Return(&repository.PaginatedResult[models.Word]{Page: 1, PageSize: 10}, nil)
+
+	_, err := wordService.ListWords(context.Background(), params, WordListOptions{Sort: WordSort{By: "success_rate", Order: SortOrderDesc}})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWordService_ListWords_ByStatusUsesDefaultThresholds(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	wordService := NewWordService(NewBaseService(mockRepo, nil, nil, nil, nil))
+
+	repoParams := repository.PaginationParams{Page: 1, PageSize: 10}
+	mockRepo.On("List", repoParams, repository.WordListOptions{Status: repository.WordStatusFilter{
+		Status:                 repository.WordStatusMastered,
+		MasteredMinReviews:     DefaultMasteredMinReviews,
+		MasteredMinSuccessRate: DefaultMasteredMinSuccessRate,
+	}}).Return(&repository.PaginatedResult[models.Word]{Page: 1, PageSize: 10}, nil)
+
+	_, err := wordService.ListWords(context.Background(), PaginationParams{Page: 1, PageSize: 10}, WordListOptions{Status: "mastered"})
+	require.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestWordService_ListWords_InvalidOptions(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	wordService := NewWordService(NewBaseService(mockRepo, nil, nil, nil, nil))
+	params := PaginationParams{Page: 1, PageSize: 10}
+
+	for _, opts := range []WordListOptions{
+		{Sort: WordSort{By: "id; DROP TABLE words"}},
+		{Sort: WordSort{By: "japanese", Order: "sideways"}},
+		{Status: "forgotten"},
+	} {
+		_, err := wordService.ListWords(context.Background(), params, opts)
+		require.Error(t, err)
+		serviceErr, ok := err.(*ServiceError)
+		require.True(t, ok)
+		assert.Equal(t, ErrCodeInvalidInput, serviceErr.Code)
+	}
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
 func TestWordService_UpdateWord(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(1)
 	updateData := &models.Word{
-		Japanese: "じゃあね",
-		Romaji:   "Jaane",
-		English:  "Bye",
+		Term:    "じゃあね",
+		Reading: "Jaane",
+		English: "Bye",
 	}
 
-	existingWord := &models.Word{ID: testWordID, Japanese: "こんにちは", Romaji: "Konnichiwa", English: "Hello"}
+	existingWord := &models.Word{ID: testWordID, Term: "こんにちは", Reading: "Konnichiwa", English: "Hello"}
 
 	mockRepo.On("GetByID", testWordID).Return(existingWord, nil)
 	mockRepo.On("Update", mock.MatchedBy(func(w *models.Word) bool {
-		return w.ID == testWordID && w.Japanese == updateData.Japanese // Check a few fields
+		return w.ID == testWordID && w.Term == updateData.Term // Check a few fields
 	})).Return(nil)
 
-	err := wordService.UpdateWord(testWordID, updateData)
+	err := wordService.UpdateWord(context.Background(), testWordID, updateData)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -312,25 +462,25 @@ func TestWordService_UpdateWord(t *testing.T) {
 
 func TestWordService_UpdateWord_RepoUpdateError(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(1)
 	updateData := &models.Word{
-		Japanese: "じゃあね",
-		Romaji:   "Jaane",
-		English:  "Bye",
+		Term:    "じゃあね",
+		Reading: "Jaane",
+		English: "Bye",
 	}
 
-	existingWord := &models.Word{ID: testWordID, Japanese: "こんにちは", Romaji: "Konnichiwa", English: "Hello"}
+	existingWord := &models.Word{ID: testWordID, Term: "こんにちは", Reading: "Konnichiwa", English: "Hello"}
 	updateError := errors.New("failed to update in repo")
 
 	mockRepo.On("GetByID", testWordID).Return(existingWord, nil)
 	mockRepo.On("Update", mock.MatchedBy(func(w *models.Word) bool {
-		return w.ID == testWordID && w.Japanese == updateData.Japanese
+		return w.ID == testWordID && w.Term == updateData.Term
 	})).Return(updateError)
 
-	err := wordService.UpdateWord(testWordID, updateData)
+	err := wordService.UpdateWord(context.Background(), testWordID, updateData)
 
 	assert.Error(t, err)
 	serviceErr, ok := err.(*ServiceError)
@@ -342,15 +492,15 @@ func TestWordService_UpdateWord_RepoUpdateError(t *testing.T) {
 
 func TestWordService_UpdateWord_NotFound(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(99)
-	updateData := &models.Word{Japanese: "Test"}
+	updateData := &models.Word{Term: "Test"}
 
 	mockRepo.On("GetByID", testWordID).Return(nil, repository.ErrNotFound)
 
-	err := wordService.UpdateWord(testWordID, updateData)
+	err := wordService.UpdateWord(context.Background(), testWordID, updateData)
 
 	assert.Error(t, err)
 	serviceErr, ok := err.(*ServiceError)
@@ -359,16 +509,77 @@ func TestWordService_UpdateWord_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestWordService_PatchWord(t *testing.T) {
+	mockRepo := new(mockWordRepository)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
+	wordService := NewWordService(baseService)
+
+	testWordID := uint(1)
+	existingWord := &models.Word{ID: testWordID, Term: "こんにちは", Reading: "Konnichiwa", English: "Hello", Parts: models.StringSlice{"interjection"}}
+	english := "Good afternoon"
+
+	mockRepo.On("GetByID", testWordID).Return(existingWord, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(w *models.Word) bool {
+		// Fields absent from the patch keep their values
+		return w.English == english && w.Term == "こんにちは" && w.Reading == "Konnichiwa" && len(w.Parts) == 1
+	})).Return(nil)
+	mockRepo.On("GetStudyStats", testWordID).Return(int64(0), int64(0), nil)
+	mockRepo.On("GetAverageAnswerTime", testWordID).Return(nil, nil)
+
+	word, err := wordService.PatchWord(context.Background(), testWordID, &WordPatch{English: &english})
+
+	require.NoError(t, err)
+	assert.Equal(t, english, word.English)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetByTerm", mock.Anything)
+}
+
+func TestWordService_PatchWord_Rejects(t *testing.T) {
+	testWordID := uint(1)
+	taken, empty, hi := "さようなら", "", "Hi"
+
+	tests := []struct {
+		name  string
+		patch WordPatch
+		setup func(*mockWordRepository)
+		code  string
+	}{
+		{"duplicate japanese", WordPatch{Japanese: &taken}, func(m *mockWordRepository) {
+			m.On("GetByTerm", taken).Return(&models.Word{ID: 2, Term: taken}, nil)
+		}, ErrCodeConflict},
+		{"empty english", WordPatch{English: &empty}, func(m *mockWordRepository) {}, ErrCodeInvalidInput},
+		{"stale version", WordPatch{English: &hi, Version: 1}, func(m *mockWordRepository) {}, ErrCodeConflict},
+		{"concurrent update", WordPatch{English: &hi, Version: 2}, func(m *mockWordRepository) {
+			m.On("Update", mock.Anything).Return(repository.ErrConflict)
+		}, ErrCodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mockWordRepository)
+			wordService := NewWordService(NewBaseService(mockRepo, nil, nil, nil, nil))
+			mockRepo.On("GetByID", testWordID).Return(&models.Word{ID: testWordID, Term: "こんにちは", Reading: "Konnichiwa", English: "Hello", Parts: models.StringSlice{"greeting"}, Version: 2}, nil)
+			tt.setup(mockRepo)
+
+			_, err := wordService.PatchWord(context.Background(), testWordID, &tt.patch)
+			require.Error(t, err)
+			assert.Equal(t, tt.code, err.(*ServiceError).Code)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestWordService_DeleteWord(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(1)
 
+	mockRepo.On("GetByID", testWordID).Return(&models.Word{ID: testWordID}, nil)
 	mockRepo.On("Delete", testWordID).Return(nil)
 
-	err := wordService.DeleteWord(testWordID)
+	err := wordService.DeleteWord(context.Background(), testWordID, "tester")
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -376,15 +587,16 @@ func TestWordService_DeleteWord(t *testing.T) {
 
 func TestWordService_DeleteWord_Error(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(1)
 	expectedError := errors.New("delete failed")
 
+	mockRepo.On("GetByID", testWordID).Return(&models.Word{ID: testWordID}, nil)
 	mockRepo.On("Delete", testWordID).Return(expectedError)
 
-	err := wordService.DeleteWord(testWordID)
+	err := wordService.DeleteWord(context.Background(), testWordID, "tester")
 
 	assert.Error(t, err)
 	serviceErr, ok := err.(*ServiceError)
@@ -395,13 +607,13 @@ func TestWordService_DeleteWord_Error(t *testing.T) {
 
 func TestWordService_DeleteWord_NotFound(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testWordID := uint(99)
-	mockRepo.On("Delete", testWordID).Return(repository.ErrNotFound)
+	mockRepo.On("GetByID", testWordID).Return(nil, repository.ErrNotFound)
 
-	err := wordService.DeleteWord(testWordID)
+	err := wordService.DeleteWord(context.Background(), testWordID, "tester")
 
 	assert.Error(t, err)
 	serviceErr, ok := err.(*ServiceError)
@@ -412,7 +624,7 @@ func TestWordService_DeleteWord_NotFound(t *testing.T) {
 
 func TestWordService_GetWordsByGroup(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
 	testGroupID := uint(1)
@@ -421,15 +633,14 @@ func TestWordService_GetWordsByGroup(t *testing.T) {
 
 	expectedRepoResult := &repository.PaginatedResult[models.Word]{
 		Items: []models.Word{
-			{ID: 1, Japanese: "犬", Romaji: "Inu", English: "Dog"},
+			{ID: 1, Term: "犬", Reading: "Inu", English: "Dog", Stats: &models.WordStats{WordID: 1, CorrectCount: 3}},
 		},
 		TotalItems: 1,
 	}
 
-	mockRepo.On("GetWordsByGroup", testGroupID, repoParams).Return(expectedRepoResult, nil)
-	mockRepo.On("GetStudyStats", uint(1)).Return(int64(3), int64(0), nil)
+	mockRepo.On("GetWordsByGroup", testGroupID, repoParams, repository.WordListOptions{}).Return(expectedRepoResult, nil)
 
-	result, err := wordService.GetWordsByGroup(testGroupID, params)
+	result, err := wordService.GetWordsByGroup(context.Background(), testGroupID, params, WordListOptions{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -439,35 +650,21 @@ func TestWordService_GetWordsByGroup(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestWordService_GetWordsByGroup_GetStudyStatsError(t *testing.T) {
+func TestWordService_GetDueWords(t *testing.T) {
 	mockRepo := new(mockWordRepository)
-	baseService := NewBaseService(mockRepo, nil, nil)
+	baseService := NewBaseService(mockRepo, nil, nil, nil, nil)
 	wordService := NewWordService(baseService)
 
-	testGroupID := uint(1)
-	params := PaginationParams{Page: 1, PageSize: 5}
-	repoParams := repository.PaginationParams{Page: params.Page, PageSize: params.PageSize}
+	due := time.Now().Add(-time.Hour)
+	mockRepo.On("GetDueWords", mock.AnythingOfType("time.Time"), 20).Return([]models.Word{
+		{ID: 1, Term: "犬", Reading: "Inu", English: "Dog", NextDueAt: &due},
+	}, nil)
 
-	expectedRepoResult := &repository.PaginatedResult[models.Word]{
-		Items: []models.Word{
-			{ID: 1, Japanese: "犬"},
-			{ID: 2, Japanese: "猫"},
-		},
-		TotalItems: 2,
-	}
-	statsError := errors.New("failed to get stats for group word")
+	result, err := wordService.GetDueWords(context.Background(), 20)
 
-	mockRepo.On("GetWordsByGroup", testGroupID, repoParams).Return(expectedRepoResult, nil)
-	mockRepo.On("GetStudyStats", uint(1)).Return(int64(3), int64(0), nil)        // First word stats succeed
-	mockRepo.On("GetStudyStats", uint(2)).Return(int64(0), int64(0), statsError) // Second word stats fail
-
-	result, err := wordService.GetWordsByGroup(testGroupID, params)
-
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	serviceErr, ok := err.(*ServiceError)
-	assert.True(t, ok)
-	assert.Equal(t, ErrCodeInternal, serviceErr.Code)
-	assert.Contains(t, serviceErr.Error(), statsError.Error())
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "犬", result[0].Japanese)
+	assert.Equal(t, &due, result[0].NextDueAt)
 	mockRepo.AssertExpectations(t)
 }
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+)
+
+// Default experience awards
+const (
+	DefaultXPPerReview  = 10
+	DefaultXPPerSession = 50
+	DefaultXPPerGoal    = 200
+	// defaultMaxLevel is the highest level of the default curve
+	defaultMaxLevel = 50
+)
+
+// XPRules sets the experience awarded for study and the levels it reaches.
+// Levels holds the total experience needed for level 2, 3 and so on, in
+// increasing order; learners start at level 1.
+type XPRules struct {
+	// Review is awarded for each correct review
+	Review int64
+	// Session is awarded for completing a session
+	Session int64
+	// Goal is awarded for completing a session that meets its group's goal,
+	// once per group
+	Goal   int64
+	Levels []int64
+}
+
+// DefaultXPRules returns the default awards with a curve where each level
+// takes 100 more experience than the one before: 100 for level 2, 300 for
+// level 3, 600 for level 4
+func DefaultXPRules() XPRules {
+	rules := XPRules{Review: DefaultXPPerReview, Session: DefaultXPPerSession, Goal: DefaultXPPerGoal}
+	for level := int64(2); level <= defaultMaxLevel; level++ {
+		rules.Levels = append(rules.Levels, 50*level*(level-1))
+	}
+	return rules
+}
+
+// Validate checks that the awards are not negative and the level thresholds
+// are positive and increasing
+func (r XPRules) Validate() error {
+	if r.Review < 0 || r.Session < 0 || r.Goal < 0 {
+		return fmt.Errorf("XP awards must not be negative")
+	}
+	for i, threshold := range r.Levels {
+		if threshold <= 0 || (i > 0 && threshold <= r.Levels[i-1]) {
+			return fmt.Errorf("XP level thresholds must be positive and increasing")
+		}
+	}
+	return nil
+}
+
+// Level returns the level xp reaches
+func (r XPRules) Level(xp int64) int {
+	level := 1
+	for _, threshold := range r.Levels {
+		if xp < threshold {
+			break
+		}
+		level++
+	}
+	return level
+}
+
+// nextLevelXP returns the total experience needed for the level after level,
+// or nil at the highest level
+func (r XPRules) nextLevelXP(level int) *int64 {
+	if level < 1 || level > len(r.Levels) {
+		return nil
+	}
+	next := r.Levels[level-1]
+	return &next
+}
+
+// WithXP awards experience for study in repo under rules. Without it no
+// experience is awarded.
+func (s *BaseService) WithXP(repo repository.XPRepositoryInterface, rules XPRules) *BaseService {
+	s.xp = repo
+	s.xpRules = rules
+	return s
+}
+
+// WithGoals lets completed sessions earn the experience for meeting their
+// group's goal
+func (s *StudyService) WithGoals(goalRepo repository.GoalRepositoryInterface) *StudyService {
+	s.goalRepo = goalRepo
+	return s
+}
+
+// LearnerLevel is the experience of a learner and their level. NextLevelXP is
+// the total experience the next level needs, null at the highest level.
+type LearnerLevel struct {
+	Learner     string `json:"learner"`
+	XP          int64  `json:"xp"`
+	Level       int    `json:"level"`
+	NextLevelXP *int64 `json:"next_level_xp"`
+}
+
+// LevelUp is a level reached, with the total experience it needed
+type LevelUp struct {
+	Level int   `json:"level"`
+	XP    int64 `json:"xp"`
+}
+
+// SessionSummary sums up a completed study session: its reviews, the
+// experience the learner earned in it and the levels it took them to. Level is
+// the learner's level now, null for sessions without a learner or when no
+// experience is awarded.
+type SessionSummary struct {
+	StudySessionID uint          `json:"study_session_id"`
+	CompletedAt    *time.Time    `json:"completed_at"`
+	Reviews        int64         `json:"reviews"`
+	CorrectReviews int64         `json:"correct_reviews"`
+	GoalMet        bool          `json:"goal_met"`
+	XPEarned       int64         `json:"xp_earned"`
+	Level          *LearnerLevel `json:"level"`
+	LevelUps       []LevelUp     `json:"level_ups"`
+}
+
+// CompleteSession marks a study session complete, awards its learner the
+// experience for it and for meeting its group's goal, and sums it up.
+// Completing a session again returns the same summary without awarding more;
+// an expired session cannot be completed.
+func (s *StudyService) CompleteSession(ctx context.Context, id uint) (*SessionSummary, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.CompleteSession")
+	defer span.End()
+
+	session, err := s.studyRepo.GetStudySessionByID(ctx, id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeNotFound, "Study session not found", err)
+		}
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
+	}
+	if session.ExpiredAt != nil {
+		return nil, sessionExpired()
+	}
+	if _, err := s.studyRepo.CompleteStudySession(ctx, id); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to complete study session", err)
+	}
+	// Read the session back for its completion time, which an earlier call may have set
+	if session, err = s.studyRepo.GetStudySessionByID(ctx, id); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to fetch study session", err)
+	}
+
+	summary := &SessionSummary{StudySessionID: id, CompletedAt: session.CompletedAt, LevelUps: []LevelUp{}}
+	if summary.Reviews, summary.CorrectReviews, err = s.studyRepo.GetSessionReviewStats(ctx, id); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to get session review statistics", err)
+	}
+	if summary.GoalMet, err = s.sessionMeetsGoal(ctx, session); err != nil {
+		return nil, err
+	}
+	if s.xp == nil || session.Learner == "" {
+		return summary, nil
+	}
+
+	if err := s.awardXP(ctx, session.Learner, models.XPSourceSession, id, id, s.xpRules.Session); err != nil {
+		return nil, err
+	}
+	if summary.GoalMet {
+		if err := s.awardXP(ctx, session.Learner, models.XPSourceGoal, session.GroupID, id, s.xpRules.Goal); err != nil {
+			return nil, err
+		}
+	}
+	if summary.XPEarned, err = s.xp.SessionTotal(ctx, session.Learner, id); err != nil {
+		return nil, NewServiceError(ErrCodeInternal, "Failed to sum session experience", err)
+	}
+	if summary.Level, err = s.learnerLevel(ctx, session.Learner); err != nil {
+		return nil, err
+	}
+	for level := s.xpRules.Level(summary.Level.XP-summary.XPEarned) + 1; level <= s.xpRules.Level(summary.Level.XP); level++ {
+		summary.LevelUps = append(summary.LevelUps, LevelUp{Level: level, XP: s.xpRules.Levels[level-2]})
+	}
+	return summary, nil
+}
+
+// GetLearnerLevel returns the experience and level of a learner, level 1
+// without experience
+func (s *StudyService) GetLearnerLevel(ctx context.Context, learner string) (*LearnerLevel, error) {
+	ctx, span := tracer.Start(ctx, "StudyService.GetLearnerLevel")
+	defer span.End()
+
+	return s.learnerLevel(ctx, learner)
+}
+
+func (s *StudyService) learnerLevel(ctx context.Context, learner string) (*LearnerLevel, error) {
+	level := &LearnerLevel{Learner: learner, Level: 1}
+	if s.xp != nil {
+		xp, err := s.xp.Get(ctx, learner)
+		if err != nil && err != repository.ErrNotFound {
+			return nil, NewServiceError(ErrCodeInternal, "Failed to fetch experience", err)
+		}
+		if xp != nil {
+			level.XP, level.Level = xp.XP, xp.Level
+		}
+	}
+	level.NextLevelXP = s.xpRules.nextLevelXP(level.Level)
+	return level, nil
+}
+
+// sessionMeetsGoal reports whether the goal of a session's group is attained
+// over its most recent sessions, false when it has no goal
+func (s *StudyService) sessionMeetsGoal(ctx context.Context, session *models.StudySession) (bool, error) {
+	if s.goalRepo == nil {
+		return false, nil
+	}
+	goal, err := s.goalRepo.GetGoal(ctx, session.GroupID)
+	if err == repository.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, NewServiceError(ErrCodeInternal, "Failed to fetch goal", err)
+	}
+	sessions, err := s.goalRepo.GetRecentSessionAccuracy(ctx, session.GroupID, goal.Sessions)
+	if err != nil {
+		return false, NewServiceError(ErrCodeInternal, "Failed to get session accuracy", err)
+	}
+	_, attained := evaluateGoal(goal, sessions)
+	return attained, nil
+}
+
+// awardXP gives learner amount experience, earned in a session, for a source
+// they have not been rewarded for yet. Nothing is awarded without an XP
+// repository, a learner or an amount.
+func (s *BaseService) awardXP(ctx context.Context, learner, source string, sourceID, sessionID uint, amount int64) error {
+	if s.xp == nil || learner == "" || amount <= 0 {
+		return nil
+	}
+	award := &models.XPAward{Learner: learner, Source: source, SourceID: sourceID, StudySessionID: &sessionID, Amount: amount}
+	if _, _, err := s.xp.Award(ctx, award, s.xpRules.Level); err != nil {
+		return NewServiceError(ErrCodeInternal, "Failed to award experience", err)
+	}
+	return nil
+}
+
+// awardReviewXP awards the experience for the correct ones among reviews just
+// recorded in session. The reviews are kept when this fails, so the failure is
+// logged rather than returned.
+func (s *BaseService) awardReviewXP(ctx context.Context, session *models.StudySession, reviews ...models.WordReview) {
+	for _, review := range reviews {
+		if !review.Correct {
+			continue
+		}
+		if err := s.awardXP(ctx, session.Learner, models.XPSourceReview, review.ID, session.ID, s.xpRules.Review); err != nil && s.logger != nil {
+			s.logger.Printf("Failed to award experience for review %d to %q: %v", review.ID, session.Learner, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCompletionRepository implements the session completion of StudyRepositoryInterface
+type mockCompletionRepository struct {
+	repository.StudyRepositoryInterface
+	mock.Mock
+	completedAt *time.Time
+}
+
+func (m *mockCompletionRepository) GetStudySessionByID(ctx context.Context, id uint) (*models.StudySession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	session := *args.Get(0).(*models.StudySession)
+	session.CompletedAt = m.completedAt
+	return &session, args.Error(1)
+}
+
+func (m *mockCompletionRepository) CompleteStudySession(ctx context.Context, id uint) (bool, error) {
+	if m.completedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	m.completedAt = &now
+	return true, nil
+}
+
+func (m *mockCompletionRepository) GetSessionReviewStats(ctx context.Context, sessionID uint) (int64, int64, error) {
+	args := m.Called(sessionID)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+// mockGoalLookup implements the goal evaluation of GoalRepositoryInterface
+type mockGoalLookup struct {
+	repository.GoalRepositoryInterface
+	goal     *models.GroupGoal
+	sessions []repository.SessionAccuracy
+}
+
+func (m *mockGoalLookup) GetGoal(ctx context.Context, groupID uint) (*models.GroupGoal, error) {
+	if m.goal == nil {
+		return nil, repository.ErrNotFound
+	}
+	return m.goal, nil
+}
+
+func (m *mockGoalLookup) GetRecentSessionAccuracy(ctx context.Context, groupID uint, limit int) ([]repository.SessionAccuracy, error) {
+	return m.sessions, nil
+}
+
+// fakeXPRepository keeps awards in memory
+type fakeXPRepository struct {
+	awards  []models.XPAward
+	learner map[string]*models.LearnerXP
+}
+
+func newFakeXPRepository() *fakeXPRepository {
+	return &fakeXPRepository{learner: make(map[string]*models.LearnerXP)}
+}
+
+func (f *fakeXPRepository) Award(ctx context.Context, award *models.XPAward, level func(xp int64) int) (*models.LearnerXP, bool, error) {
+	xp, ok := f.learner[award.Learner]
+	if !ok {
+		xp = &models.LearnerXP{Learner: award.Learner, Level: level(0)}
+		f.learner[award.Learner] = xp
+	}
+	for _, existing := range f.awards {
+		if existing.Learner == award.Learner && existing.Source == award.Source && existing.SourceID == award.SourceID {
+			return xp, false, nil
+		}
+	}
+	f.awards = append(f.awards, *award)
+	xp.XP += award.Amount
+	xp.Level = level(xp.XP)
+	return xp, true, nil
+}
+
+func (f *fakeXPRepository) Get(ctx context.Context, learner string) (*models.LearnerXP, error) {
+	xp, ok := f.learner[learner]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return xp, nil
+}
+
+func (f *fakeXPRepository) SessionTotal(ctx context.Context, learner string, sessionID uint) (int64, error) {
+	var total int64
+	for _, award := range f.awards {
+		if award.Learner == learner && award.StudySessionID != nil && *award.StudySessionID == sessionID {
+			total += award.Amount
+		}
+	}
+	return total, nil
+}
+
+func TestXPRules(t *testing.T) {
+	rules := DefaultXPRules()
+	require.NoError(t, rules.Validate())
+	assert.Equal(t, 1, rules.Level(0))
+	assert.Equal(t, 1, rules.Level(99))
+	assert.Equal(t, 2, rules.Level(100))
+	assert.Equal(t, 3, rules.Level(300))
+	assert.Equal(t, int64(600), *rules.nextLevelXP(3))
+	assert.Nil(t, rules.nextLevelXP(defaultMaxLevel))
+
+	assert.Error(t, XPRules{Levels: []int64{100, 100}}.Validate())
+	assert.Error(t, XPRules{Levels: []int64{0}}.Validate())
+	assert.Error(t, XPRules{Review: -1}.Validate())
+}
+
+func TestStudyService_CompleteSession(t *testing.T) {
+	studyRepo := &mockCompletionRepository{}
+	studyRepo.On("GetStudySessionByID", uint(5)).Return(&models.StudySession{ID: 5, GroupID: 2, Learner: "yuki"}, nil)
+	studyRepo.On("GetSessionReviewStats", uint(5)).Return(int64(4), int64(3), nil)
+	goals := &mockGoalLookup{
+		goal:     &models.GroupGoal{GroupID: 2, MinAccuracy: 0.7, Sessions: 1},
+		sessions: []repository.SessionAccuracy{{SessionID: 5, TotalReviews: 4, CorrectReviews: 3}},
+	}
+	xp := newFakeXPRepository()
+	rules := XPRules{Review: 10, Session: 50, Goal: 200, Levels: []int64{100, 300}}
+	base := NewBaseService(nil, nil, studyRepo, nil, nil).WithXP(xp, rules)
+	s := NewStudyService(base).WithGoals(goals)
+	ctx := context.Background()
+
+	// A correct review earned in the session before it is completed
+	s.awardReviewXP(ctx, &models.StudySession{ID: 5, Learner: "yuki"}, models.WordReview{ID: 9, Correct: true}, models.WordReview{ID: 10})
+
+	summary, err := s.CompleteSession(ctx, 5)
+	require.NoError(t, err)
+	assert.NotNil(t, summary.CompletedAt)
+	assert.Equal(t, int64(4), summary.Reviews)
+	assert.Equal(t, int64(3), summary.CorrectReviews)
+	assert.True(t, summary.GoalMet)
+	assert.Equal(t, int64(260), summary.XPEarned)
+	next := int64(300)
+	assert.Equal(t, &LearnerLevel{Learner: "yuki", XP: 260, Level: 2, NextLevelXP: &next}, summary.Level)
+	assert.Equal(t, []LevelUp{{Level: 2, XP: 100}}, summary.LevelUps)
+
+	again, err := s.CompleteSession(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, summary.CompletedAt, again.CompletedAt)
+	assert.Equal(t, int64(260), again.Level.XP, "completing again awards nothing more")
+}
+
+func TestStudyService_CompleteSession_WithoutLearner(t *testing.T) {
+	studyRepo := &mockCompletionRepository{}
+	studyRepo.On("GetStudySessionByID", uint(5)).Return(&models.StudySession{ID: 5, GroupID: 2}, nil)
+	studyRepo.On("GetStudySessionByID", uint(6)).Return(nil, repository.ErrNotFound)
+	studyRepo.On("GetSessionReviewStats", uint(5)).Return(int64(0), int64(0), nil)
+	xp := newFakeXPRepository()
+	s := NewStudyService(NewBaseService(nil, nil, studyRepo, nil, nil).WithXP(xp, DefaultXPRules()))
+
+	summary, err := s.CompleteSession(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Nil(t, summary.Level)
+	assert.Empty(t, summary.LevelUps)
+	assert.Empty(t, xp.awards)
+
+	_, err = s.CompleteSession(context.Background(), 6)
+	require.Error(t, err)
+	assert.Equal(t, ErrCodeNotFound, err.(*ServiceError).Code)
+}
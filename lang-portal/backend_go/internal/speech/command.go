@@ -0,0 +1,48 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command transcribes recordings with a local speech-to-text program, such as
+// the whisper.cpp command line tool. The recording is written to a temporary
+// file whose path is passed as the last argument; the program prints the
+// transcript on standard output.
+type Command struct {
+	Path string
+	Args []string
+}
+
+// Transcribe implements Recognizer
+func (c *Command) Transcribe(ctx context.Context, audio []byte, contentType string) (string, error) {
+	ext, ok := Extension(contentType)
+	if !ok {
+		return "", fmt.Errorf("unsupported recording type %q", contentType)
+	}
+
+	file, err := os.CreateTemp("", "recording-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(audio); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Path, append(append([]string{}, c.Args...), file.Name())...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %v: %s", ErrUnavailable, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
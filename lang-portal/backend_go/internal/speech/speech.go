@@ -0,0 +1,42 @@
+// Package speech transcribes recordings of spoken Japanese, either with a
+// Whisper transcription API or with a local speech-to-text program.
+package speech
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrUnavailable is returned when the recognizer cannot be reached or run
+var ErrUnavailable = errors.New("speech recognition unavailable")
+
+// Recognizer transcribes recordings
+type Recognizer interface {
+	// Transcribe returns the text spoken in audio, a recording in the format
+	// named by contentType such as audio/webm or audio/wav
+	Transcribe(ctx context.Context, audio []byte, contentType string) (string, error)
+}
+
+// extensions maps recording content types to file extensions, which
+// transcription APIs and programs use to detect the format
+var extensions = map[string]string{
+	"audio/mpeg":  ".mp3",
+	"audio/mp3":   ".mp3",
+	"audio/mp4":   ".m4a",
+	"audio/x-m4a": ".m4a",
+	"audio/ogg":   ".ogg",
+	"audio/wav":   ".wav",
+	"audio/x-wav": ".wav",
+	"audio/wave":  ".wav",
+	"audio/webm":  ".webm",
+	"audio/flac":  ".flac",
+}
+
+// Extension returns the file extension of a recording content type, ignoring
+// parameters such as codecs, and whether the type is supported
+func Extension(contentType string) (string, bool) {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	ext, ok := extensions[strings.ToLower(strings.TrimSpace(mediaType))]
+	return ext, ok
+}
@@ -0,0 +1,67 @@
+package speech
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtension(t *testing.T) {
+	ext, ok := Extension("audio/webm;codecs=opus")
+	assert.True(t, ok)
+	assert.Equal(t, ".webm", ext)
+
+	ext, ok = Extension("Audio/WAV")
+	assert.True(t, ok)
+	assert.Equal(t, ".wav", ext)
+
+	_, ok = Extension("video/mp4")
+	assert.False(t, ok)
+}
+
+func TestWhisper_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, DefaultWhisperModel, r.FormValue("model"))
+		assert.Equal(t, "ja", r.FormValue("language"))
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "recording.webm", header.Filename)
+		assert.Equal(t, "RIFF", string(data))
+		w.Write([]byte(`{"text":" 食べる。"}`))
+	}))
+	defer server.Close()
+
+	whisper := &Whisper{URL: server.URL, APIKey: "secret"}
+	text, err := whisper.Transcribe(context.Background(), []byte("RIFF"), "audio/webm")
+	require.NoError(t, err)
+	assert.Equal(t, "食べる。", text)
+
+	_, err = whisper.Transcribe(context.Background(), []byte("RIFF"), "text/plain")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnavailable)
+
+	server.Close()
+	_, err = whisper.Transcribe(context.Background(), []byte("RIFF"), "audio/webm")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestCommand_Transcribe(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "transcribe.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$1\"\ncat \"$2\"\n"), 0o755))
+
+	text, err := (&Command{Path: script, Args: []string{"たべる"}}).Transcribe(context.Background(), []byte(" です"), "audio/wav")
+	require.NoError(t, err)
+	assert.Equal(t, "たべる\n です", text)
+
+	_, err = (&Command{Path: filepath.Join(t.TempDir(), "missing")}).Transcribe(context.Background(), nil, "audio/wav")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
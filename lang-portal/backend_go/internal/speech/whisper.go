@@ -0,0 +1,99 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// Whisper API defaults
+const (
+	// DefaultWhisperURL is the transcription endpoint of the OpenAI API
+	DefaultWhisperURL = "https://api.openai.com/v1/audio/transcriptions"
+	// DefaultWhisperModel is the transcription model used when none is set
+	DefaultWhisperModel = "whisper-1"
+)
+
+// maxWhisperResponse caps the size of a transcription response
+const maxWhisperResponse = 1 << 20
+
+// Whisper transcribes recordings with a Whisper transcription API: the OpenAI
+// API, or a local server exposing the same endpoint such as whisper.cpp's
+type Whisper struct {
+	// URL is the transcription endpoint, DefaultWhisperURL when empty
+	URL string
+	// APIKey is sent as a bearer token when set
+	APIKey string
+	// Model is the transcription model, DefaultWhisperModel when empty
+	Model  string
+	Client *http.Client
+}
+
+// Transcribe implements Recognizer. Recordings are transcribed as Japanese.
+func (w *Whisper) Transcribe(ctx context.Context, audio []byte, contentType string) (string, error) {
+	ext, ok := Extension(contentType)
+	if !ok {
+		return "", fmt.Errorf("unsupported recording type %q", contentType)
+	}
+	model := w.Model
+	if model == "" {
+		model = DefaultWhisperModel
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "recording"+ext)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(audio); err != nil {
+		return "", err
+	}
+	for field, value := range map[string]string{"model": model, "language": "ja", "response_format": "json"} {
+		if err := form.WriteField(field, value); err != nil {
+			return "", err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	endpoint := w.URL
+	if endpoint == "" {
+		endpoint = DefaultWhisperURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if w.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+w.APIKey)
+	}
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: whisper answered %s", ErrUnavailable, resp.Status)
+	}
+
+	var transcription struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxWhisperResponse)).Decode(&transcription); err != nil {
+		return "", fmt.Errorf("%w: decoding whisper response: %v", ErrUnavailable, err)
+	}
+	return strings.TrimSpace(transcription.Text), nil
+}
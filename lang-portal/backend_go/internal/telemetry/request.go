@@ -0,0 +1,27 @@
+package telemetry
+
+import "context"
+
+// Request identifies the HTTP request work is done for, so that logs and
+// metrics recorded below the API layer, such as database queries, can be
+// attributed to it
+type Request struct {
+	// ID is the request ID, sent back in the X-Request-ID header
+	ID string
+	// Route is the route pattern that matched, such as /api/words/:id
+	Route string
+}
+
+type requestKey struct{}
+
+// WithRequest returns a copy of ctx carrying req
+func WithRequest(ctx context.Context, req Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// RequestFrom returns the request ctx carries, if any. Work that is not done
+// for a request, such as migrations and background jobs, has none.
+func RequestFrom(ctx context.Context) (Request, bool) {
+	req, ok := ctx.Value(requestKey{}).(Request)
+	return req, ok
+}
@@ -0,0 +1,102 @@
+// Package telemetry configures OpenTelemetry tracing and metrics. Spans and
+// metrics are exported over OTLP/HTTP when an endpoint is configured through the
+// standard OTEL_* environment variables; otherwise they stay a no-op.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// DefaultServiceName identifies the service when OTEL_SERVICE_NAME is unset
+const DefaultServiceName = "lang-portal"
+
+// Environment variables read by Setup. The exporter also honours the other
+// OTEL_EXPORTER_OTLP_* variables (headers, timeout, insecure).
+const (
+	EnvEndpoint        = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvTracesEndpoint  = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	EnvMetricsEndpoint = "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"
+)
+
+// ShutdownFunc flushes pending spans and metrics and stops the exporters
+type ShutdownFunc func(ctx context.Context) error
+
+// Enabled reports whether an OTLP endpoint for traces is configured
+func Enabled() bool {
+	return os.Getenv(EnvEndpoint) != "" || os.Getenv(EnvTracesEndpoint) != ""
+}
+
+// MetricsEnabled reports whether an OTLP endpoint for metrics is configured
+func MetricsEnabled() bool {
+	return os.Getenv(EnvEndpoint) != "" || os.Getenv(EnvMetricsEndpoint) != ""
+}
+
+// Setup installs the global tracer and meter providers and the W3C trace
+// context propagator. Providers are installed only for the signals with a
+// configured endpoint; without any, Setup returns a no-op shutdown.
+func Setup(ctx context.Context) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !Enabled() && !MetricsEnabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Attributes from OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the default name
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", DefaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build telemetry resource: %w", err)
+	}
+
+	var shutdowns []ShutdownFunc
+	if Enabled() {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		provider := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(res),
+		)
+		otel.SetTracerProvider(provider)
+		shutdowns = append(shutdowns, provider.Shutdown)
+	}
+	if MetricsEnabled() {
+		exporter, err := otlpmetrichttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+		}
+		provider := sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+			sdkmetric.WithResource(res),
+		)
+		otel.SetMeterProvider(provider)
+		shutdowns = append(shutdowns, provider.Shutdown)
+	}
+
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, shutdown := range shutdowns {
+			errs = append(errs, shutdown(ctx))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
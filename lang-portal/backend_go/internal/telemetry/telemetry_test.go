@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_DisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv(EnvEndpoint, "")
+	t.Setenv(EnvTracesEndpoint, "")
+	t.Setenv(EnvMetricsEndpoint, "")
+
+	shutdown, err := Setup(context.Background())
+	require.NoError(t, err)
+	assert.False(t, Enabled())
+	assert.NoError(t, shutdown(context.Background()))
+
+	// The global provider is left as the no-op default
+	_, span := otel.Tracer("test").Start(context.Background(), "noop")
+	defer span.End()
+	assert.False(t, span.SpanContext().IsValid())
+}
+
+func TestSetup_EnabledWithEndpoint(t *testing.T) {
+	t.Setenv(EnvEndpoint, "http://127.0.0.1:4318")
+	t.Setenv("OTEL_SERVICE_NAME", "lang-portal-test")
+
+	previous := otel.GetTracerProvider()
+	previousMeters := otel.GetMeterProvider()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		otel.SetMeterProvider(previousMeters)
+	})
+
+	shutdown, err := Setup(context.Background())
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "recorded")
+	assert.True(t, span.SpanContext().IsValid())
+	span.End()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Nothing is listening; shutdown must still return rather than block
+	_ = shutdown(ctx)
+}
+
+func TestSetup_MetricsOnly(t *testing.T) {
+	t.Setenv(EnvEndpoint, "")
+	t.Setenv(EnvTracesEndpoint, "")
+	t.Setenv(EnvMetricsEndpoint, "http://127.0.0.1:4318/v1/metrics")
+
+	previous := otel.GetMeterProvider()
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	shutdown, err := Setup(context.Background())
+	require.NoError(t, err)
+	assert.False(t, Enabled())
+	assert.True(t, MetricsEnabled())
+	assert.NotEqual(t, previous, otel.GetMeterProvider())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "noop")
+	defer span.End()
+	assert.False(t, span.SpanContext().IsValid(), "traces stay off without a traces endpoint")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = shutdown(ctx)
+}
@@ -0,0 +1,234 @@
+// Package apitest runs the full application on an in-memory database so that
+// integration tests can exercise the HTTP API end to end: routing, binding,
+// middleware, services and the JSON they return.
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"lang-portal/backend_go/internal/api/middleware"
+	"lang-portal/backend_go/internal/app"
+	"lang-portal/backend_go/internal/testutil"
+)
+
+// update rewrites the golden files with the current responses instead of
+// comparing against them
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Placeholders for values that differ between runs, substituted in golden files
+const (
+	timePlaceholder      = "<time>"
+	datePlaceholder      = "<date>"
+	requestIDPlaceholder = "<request_id>"
+)
+
+var (
+	timePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`)
+	datePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// Server is the application served over HTTP for a single test
+type Server struct {
+	// DB is the application's database, for arranging data and checking
+	// what the requests stored
+	DB  *gorm.DB
+	App *app.App
+	URL string
+	// Header is sent with every request, such as Accept-Language
+	Header http.Header
+
+	t      *testing.T
+	client *http.Client
+}
+
+// New builds the application on a fresh in-memory database and serves it
+// until the test ends. opts are applied after the defaults, which discard the
+// logs.
+func New(t *testing.T, opts ...app.Option) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := testutil.SetupTestDB(t)
+	opts = append([]app.Option{app.WithLogger(log.New(io.Discard, "", 0))}, opts...)
+	a, err := app.New(db, opts...)
+	require.NoError(t, err)
+	a.Start()
+
+	srv := httptest.NewServer(a.Router)
+	t.Cleanup(func() {
+		srv.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		a.Shutdown(ctx)
+	})
+
+	return &Server{DB: db, App: a, URL: srv.URL, Header: http.Header{}, t: t, client: srv.Client()}
+}
+
+// Do sends a request to path, with body encoded as JSON unless it is nil, and
+// reads the whole response
+func (s *Server) Do(method, path string, body interface{}) *Response {
+	s.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(s.t, err)
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	require.NoError(s.t, err)
+	for key, values := range s.Header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	require.NoError(s.t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(s.t, err)
+
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: data, t: s.t}
+}
+
+// Get sends a GET request to path
+func (s *Server) Get(path string) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodGet, path, nil)
+}
+
+// Post sends body as JSON to path
+func (s *Server) Post(path string, body interface{}) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodPost, path, body)
+}
+
+// Put sends body as JSON to path
+func (s *Server) Put(path string, body interface{}) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodPut, path, body)
+}
+
+// Patch sends body as JSON to path
+func (s *Server) Patch(path string, body interface{}) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodPatch, path, body)
+}
+
+// Delete sends a DELETE request to path
+func (s *Server) Delete(path string) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodDelete, path, nil)
+}
+
+// Response is a response read in full
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	t *testing.T
+}
+
+// Status fails the test unless the response has the status want, showing the
+// body to explain unexpected errors
+func (r *Response) Status(want int) *Response {
+	r.t.Helper()
+	require.Equal(r.t, want, r.StatusCode, "unexpected status, body: %s", strings.TrimSpace(string(r.Body)))
+	return r
+}
+
+// Error returns the message of an error response
+func (r *Response) Error() string {
+	r.t.Helper()
+	return JSON[middleware.ErrorResponse](r).Error.Message
+}
+
+// ErrorCode returns the code of an error response
+func (r *Response) ErrorCode() string {
+	r.t.Helper()
+	return JSON[middleware.ErrorResponse](r).Error.Code
+}
+
+// JSON decodes the body of r as a T
+func JSON[T any](r *Response) T {
+	r.t.Helper()
+	var v T
+	require.NoError(r.t, json.Unmarshal(r.Body, &v), "body: %s", r.Body)
+	return v
+}
+
+// Golden fails the test unless the body of r matches testdata/name.golden.json.
+// Bodies are compared as indented JSON with sorted keys, and timestamps and
+// dates are replaced by placeholders, so only changes to field names, shapes
+// and values fail. Run the tests with -update to accept the current responses.
+func (r *Response) Golden(name string) *Response {
+	r.t.Helper()
+
+	var body interface{}
+	require.NoError(r.t, json.Unmarshal(r.Body, &body), "body: %s", r.Body)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	require.NoError(r.t, enc.Encode(normalize(body)))
+	got := buf.Bytes()
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		require.NoError(r.t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(r.t, os.WriteFile(path, got, 0o644))
+		return r
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(r.t, err, "missing golden file; run the tests with -update to create it")
+	require.Equal(r.t, string(want), string(got), "response differs from %s; run the tests with -update if the change is intended", path)
+	return r
+}
+
+// normalize replaces the timestamps, dates and request IDs in a decoded JSON
+// value
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if key == "request_id" {
+				v[key] = requestIDPlaceholder
+				continue
+			}
+			v[key] = normalize(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = normalize(value)
+		}
+	case string:
+		if timePattern.MatchString(v) {
+			return timePlaceholder
+		}
+		if datePattern.MatchString(v) {
+			return datePlaceholder
+		}
+	}
+	return v
+}
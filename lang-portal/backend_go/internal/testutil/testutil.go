@@ -9,6 +9,7 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"lang-portal/backend_go/internal/database"
 	"lang-portal/backend_go/internal/models"
 )
 
@@ -17,15 +18,13 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
-	// Run migrations
-	err = db.AutoMigrate(
-		&models.Word{},
-		&models.Group{},
-		&models.StudyActivity{},
-		&models.StudySession{},
-		&models.WordReview{},
-	)
+	// An in-memory database lives only as long as its connection
+	sqlDB, err := db.DB()
 	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	// Run migrations
+	require.NoError(t, database.Migrate(db))
 
 	return db
 }
@@ -33,10 +32,10 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 // CreateTestWord creates a test word with default values
 func CreateTestWord(t *testing.T, db *gorm.DB) *models.Word {
 	word := &models.Word{
-		Japanese: "テスト",
-		Romaji:   "tesuto",
-		English:  "test",
-		Parts:    models.StringSlice{"noun"},
+		Term:    "テスト",
+		Reading: "tesuto",
+		English: "test",
+		Parts:   models.StringSlice{"noun"},
 	}
 	err := db.Create(word).Error
 	require.NoError(t, err)
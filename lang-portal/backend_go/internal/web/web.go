@@ -0,0 +1,60 @@
+// Package web serves the built frontend from the server binary, so that a
+// single binary or container image serves both the app and the API. The
+// frontend is embedded from the static directory, which `mage frontend:build`
+// fills; binaries built without it serve the API only.
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed all:static
+var files embed.FS
+
+// indexFile is the page of the app, served for every client-side route
+const indexFile = "index.html"
+
+// Assets returns the embedded frontend, or nil when the binary was built
+// without it
+func Assets() fs.FS {
+	assets, err := fs.Sub(files, "static")
+	if err != nil {
+		return nil
+	}
+	if _, err := fs.Stat(assets, indexFile); err != nil {
+		return nil
+	}
+	return assets
+}
+
+// Handler serves the files of assets. Any other GET or HEAD request outside
+// the API is answered with the app's page, so that client-side routes load the
+// app; the rest are left unanswered for the router's 404. Hashed build
+// output under /assets is cached for good, the page is always revalidated.
+func Handler(assets fs.FS) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		urlPath := c.Request.URL.Path
+		if (method != http.MethodGet && method != http.MethodHead) || urlPath == "/api" || strings.HasPrefix(urlPath, "/api/") {
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean(urlPath), "/")
+		if info, err := fs.Stat(assets, name); name == "" || err != nil || info.IsDir() {
+			name = indexFile
+		}
+		if strings.HasPrefix(name, "assets/") {
+			c.Header("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			c.Header("Cache-Control", "no-cache")
+		}
+		http.ServeFileFS(c.Writer, c.Request, assets, name)
+		c.Abort()
+	}
+}
@@ -0,0 +1,61 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/words", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"items": []string{}}) })
+	router.NoRoute(Handler(fstest.MapFS{
+		"index.html":          {Data: []byte("<html>app</html>")},
+		"assets/index-ab1.js": {Data: []byte("console.log(1)")},
+		"favicon.svg":         {Data: []byte("<svg/>")},
+	}))
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+		cache  string
+	}{
+		{"app page", http.MethodGet, "/", http.StatusOK, "<html>app</html>", "no-cache"},
+		{"client-side route", http.MethodGet, "/groups/3", http.StatusOK, "<html>app</html>", "no-cache"},
+		{"hashed asset", http.MethodGet, "/assets/index-ab1.js", http.StatusOK, "console.log(1)", "public, max-age=31536000, immutable"},
+		{"static file", http.MethodGet, "/favicon.svg", http.StatusOK, "<svg/>", "no-cache"},
+		{"directory", http.MethodGet, "/assets", http.StatusOK, "<html>app</html>", "no-cache"},
+		{"API route", http.MethodGet, "/api/v1/words", http.StatusOK, `{"items":[]}`, ""},
+		{"unknown API route", http.MethodGet, "/api/v1/nothing", http.StatusNotFound, "404 page not found", ""},
+		{"other method", http.MethodPost, "/groups", http.StatusNotFound, "404 page not found", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.method, tt.path)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+			assert.Equal(t, tt.cache, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestAssets_WithoutBuild(t *testing.T) {
+	if _, err := files.Open("static/index.html"); err == nil {
+		t.Skip("the frontend is built into this binary")
+	}
+	assert.Nil(t, Assets())
+}
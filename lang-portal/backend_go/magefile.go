@@ -3,20 +3,19 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
-	"sort"
-	"strings"
 
 	"github.com/magefile/mage/mg"
+	"github.com/magefile/mage/sh"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
-	"lang-portal/backend_go/internal/models"
+	"lang-portal/backend_go/internal/database"
+	"lang-portal/backend_go/internal/repository"
 )
 
 // Database tasks
@@ -48,15 +47,8 @@ func (DB) Initialize() error {
 		return fmt.Errorf("failed to open database: %v", err)
 	}
 
-	// Auto-migrate the schema
-	err = db.AutoMigrate(
-		&models.Word{},
-		&models.Group{},
-		&models.StudyActivity{},
-		&models.StudySession{},
-		&models.WordReview{},
-	)
-	if err != nil {
+	// Apply versioned migrations
+	if err := database.Migrate(db); err != nil {
 		os.Remove(dbPath) // Clean up the file if migration fails
 		return fmt.Errorf("failed to migrate database: %v", err)
 	}
@@ -65,7 +57,11 @@ func (DB) Initialize() error {
 	return nil
 }
 
-// Migrate runs all database migrations
+// Migrate applies pending versioned migrations.
+//
+// Deprecated: migrations are embedded in the binaries and applied at startup;
+// use `langctl migrate` to run them by hand. This target is kept for existing
+// scripts and delegates to the same runner.
 func (DB) Migrate() error {
 	fmt.Println("Running database migrations...")
 	dbPath := "words.db"
@@ -83,85 +79,23 @@ func (DB) Migrate() error {
 		return fmt.Errorf("failed to open database: %v", err)
 	}
 
-	// Get all migration files
-	migrationsDir := "db/migrations"
-	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
-	if err != nil {
-		return fmt.Errorf("failed to find migration files: %v", err)
-	}
-
-	if len(files) == 0 {
-		return fmt.Errorf("no migration files found in %s", migrationsDir)
-	}
-
-	// Sort files by name (they should be prefixed with numbers)
-	sort.Strings(files)
-
-	// Create migrations table if it doesn't exist
-	if err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS migrations (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			name TEXT NOT NULL UNIQUE,
-			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-		)
-	`).Error; err != nil {
-		return fmt.Errorf("failed to create migrations table: %v", err)
-	}
-
-	// Run each migration in a transaction
-	for _, file := range files {
-		fileName := filepath.Base(file)
-		fmt.Printf("Checking migration: %s\n", fileName)
-
-		// Check if migration has already been applied
-		var count int64
-		if err := db.Model(&struct{}{}).Table("migrations").Where("name = ?", fileName).Count(&count).Error; err != nil {
-			return fmt.Errorf("failed to check migration status: %v", err)
-		}
-		if count > 0 {
-			fmt.Printf("Migration %s already applied, skipping\n", fileName)
-			continue
-		}
-
-		// Read migration file
-		content, err := ioutil.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %v", fileName, err)
-		}
-
-		// Begin transaction
-		tx := db.Begin()
-		if tx.Error != nil {
-			return fmt.Errorf("failed to begin transaction: %v", tx.Error)
-		}
-
-		// Execute migration
-		if err := tx.Exec(string(content)).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to execute migration %s: %v", fileName, err)
-		}
-
-		// Record migration
-		if err := tx.Exec("INSERT INTO migrations (name) VALUES (?)", fileName).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to record migration %s: %v", fileName, err)
-		}
-
-		// Commit transaction
-		if err := tx.Commit().Error; err != nil {
-			return fmt.Errorf("failed to commit migration %s: %v", fileName, err)
-		}
-
-		fmt.Printf("Successfully applied migration: %s\n", fileName)
+	if err := database.Migrate(db); err != nil {
+		return fmt.Errorf("failed to migrate database: %v", err)
 	}
 
 	fmt.Println("All migrations completed successfully")
 	return nil
 }
 
-// Seed imports seed data into the database
+// Seed adds the words of a seed profile to the database, the demo profile
+// unless LANG_PORTAL_SEED_PROFILE names another one. It can be run again to
+// add a profile on top of the words already there.
 func (DB) Seed() error {
-	fmt.Println("Seeding database...")
+	profile := os.Getenv("LANG_PORTAL_SEED_PROFILE")
+	if profile == "" {
+		profile = database.DefaultSeedProfile
+	}
+	fmt.Printf("Seeding database with the %s profile...\n", profile)
 	dbPath := "words.db"
 
 	// Check if database exists
@@ -171,110 +105,71 @@ func (DB) Seed() error {
 
 	// Open database connection
 	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info), // Reduced default GORM logging for seeds
+		Logger: logger.Default.LogMode(logger.Warn),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to open database: %v", err)
 	}
 
-	// Get all seed files (JSON and SQL)
-	seedsDir := "db/seeds"
-	jsonFiles, err := filepath.Glob(filepath.Join(seedsDir, "*.json"))
-	if err != nil {
-		return fmt.Errorf("failed to find JSON seed files: %v", err)
-	}
-	sqlFiles, err := filepath.Glob(filepath.Join(seedsDir, "*.sql"))
+	result, err := database.Seed(db, profile)
 	if err != nil {
-		return fmt.Errorf("failed to find SQL seed files: %v", err)
+		return err
 	}
 
-	files := append(jsonFiles, sqlFiles...)
-	if len(files) == 0 {
-		fmt.Println("No seed files found in", seedsDir) // Changed to info, not error if no seeds
-		return nil
+	// Link the new words like the server does after seeding
+	ctx := context.Background()
+	if _, err := repository.NewKanjiRepository(db).LinkUnlinkedWords(ctx); err != nil {
+		return fmt.Errorf("failed to link words to kanji: %v", err)
+	}
+	words := repository.NewWordRepository(db)
+	if _, err := words.LinkUnlinkedParts(ctx); err != nil {
+		return fmt.Errorf("failed to link words to parts of speech: %v", err)
+	}
+	if err := words.RankUnrankedWords(ctx); err != nil {
+		return fmt.Errorf("failed to rank words by frequency: %v", err)
 	}
 
-	// Sort files by name to ensure consistent order
-	sort.Strings(files)
-
-	// Process each seed file
-	for _, file := range files {
-		fileName := filepath.Base(file)
-		fmt.Printf("Processing seed file: %s\n", fileName)
+	fmt.Printf("Added %d groups, %d words and %d group memberships\n", result.Groups, result.Words, result.Memberships)
+	return nil
+}
 
-		// Read seed file content
-		content, err := ioutil.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to read seed file %s: %v", fileName, err)
-		}
+// Frontend tasks
+type Frontend mg.Namespace
 
-		// Begin transaction for this file
-		tx := db.Begin()
-		if tx.Error != nil {
-			return fmt.Errorf("failed to begin transaction for %s: %v", fileName, tx.Error)
-		}
+// frontendDir is the React app, next to the backend
+const frontendDir = "../frontend_react"
 
-		if strings.HasSuffix(fileName, ".json") {
-			groupName := strings.TrimSuffix(fileName, ".json")
-			// Parse JSON data
-			var words []struct {
-				Japanese string   `json:"japanese"`
-				Romaji   string   `json:"romaji"`
-				English  string   `json:"english"`
-				Parts    []string `json:"parts"`
-			}
-			if err := json.Unmarshal(content, &words); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to parse seed file %s: %v", fileName, err)
-			}
+// embedDir receives the built frontend for the server binary to embed
+const embedDir = "internal/web/static"
 
-			// Create or get group
-			group := models.Group{Name: groupName}
-			if err := tx.FirstOrCreate(&group, models.Group{Name: groupName}).Error; err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to create/get group %s: %v", groupName, err)
-			}
-
-			// Create words and associate with group
-			for _, wordData := range words {
-				word := models.Word{
-					Japanese: wordData.Japanese,
-					Romaji:   wordData.Romaji,
-					English:  wordData.English,
-					Parts:    wordData.Parts,
-				}
-
-				// Create or get word
-				if err := tx.FirstOrCreate(&word, models.Word{Japanese: wordData.Japanese}).Error; err != nil {
-					tx.Rollback()
-					return fmt.Errorf("failed to create/get word %s: %v", wordData.Japanese, err)
-				}
+// Build builds the React app and copies it into the server, which serves it
+// under / when started with -frontend. Build the server binary afterwards.
+func (Frontend) Build() error {
+	fmt.Println("Building the frontend...")
+	if err := sh.RunV("npm", "--prefix", frontendDir, "ci"); err != nil {
+		return fmt.Errorf("failed to install the frontend dependencies: %v", err)
+	}
+	if err := sh.RunV("npm", "--prefix", frontendDir, "run", "build"); err != nil {
+		return fmt.Errorf("failed to build the frontend: %v", err)
+	}
 
-				// Associate word with group
-				if err := tx.Model(&group).Association("Words").Append(&word); err != nil {
-					tx.Rollback()
-					return fmt.Errorf("failed to associate word %s with group %s: %v", wordData.Japanese, groupName, err)
-				}
-			}
-			fmt.Printf("Successfully seeded group '%s' from %s\n", groupName, fileName)
-		} else if strings.HasSuffix(fileName, ".sql") {
-			// Execute SQL file content
-			if err := tx.Exec(string(content)).Error; err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to execute SQL seed file %s: %v", fileName, err)
+	// Replace the previous build, keeping the placeholder that lets the
+	// server compile without one
+	previous, err := os.ReadDir(embedDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range previous {
+		if entry.Name() != ".gitkeep" {
+			if err := os.RemoveAll(filepath.Join(embedDir, entry.Name())); err != nil {
+				return err
 			}
-			fmt.Printf("Successfully executed SQL seed file: %s\n", fileName)
-		} else {
-			tx.Rollback() // Should not happen with current glob, but good practice
-			return fmt.Errorf("unsupported seed file type: %s", fileName)
-		}
-
-		// Commit transaction for this file
-		if err := tx.Commit().Error; err != nil {
-			return fmt.Errorf("failed to commit seed data for %s: %v", fileName, err)
 		}
 	}
+	if err := os.CopyFS(embedDir, os.DirFS(filepath.Join(frontendDir, "dist"))); err != nil {
+		return fmt.Errorf("failed to copy the frontend build: %v", err)
+	}
 
-	fmt.Println("All seed data processed successfully")
+	fmt.Println("Frontend copied to", embedDir)
 	return nil
 }
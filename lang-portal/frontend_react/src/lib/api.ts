@@ -1,5 +1,18 @@
 const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8081';
 
+// ApiError is a failed request; code is the error code of the response, see
+// "Error Responses" in the backend specs
+export class ApiError extends Error {
+  constructor(
+    public status: number,
+    public code: string | undefined,
+    message: string,
+  ) {
+    super(message);
+    this.name = 'ApiError';
+  }
+}
+
 async function fetchApi<T>(endpoint: string, options?: RequestInit): Promise<T> {
   const url = `${API_BASE_URL}${endpoint}`;
   const response = await fetch(url, {
@@ -11,8 +24,8 @@ async function fetchApi<T>(endpoint: string, options?: RequestInit): Promise<T>
   });
 
   if (!response.ok) {
-    const error = await response.json().catch(() => ({}));
-    throw new Error(error.message || 'Something went wrong');
+    const body = await response.json().catch(() => ({}));
+    throw new ApiError(response.status, body.error?.code, body.error?.message || 'Something went wrong');
   }
 
   return response.json();